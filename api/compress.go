@@ -0,0 +1,256 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// DefaultCompressionMinLength is the size of the response body below which responses are sent uncompressed.
+	DefaultCompressionMinLength = 1024
+
+	encodingGzip = "gzip"
+	encodingZstd = "zstd"
+)
+
+var (
+	gzipEncoders = sync.Pool{
+		New: func() any { return gzip.NewWriter(io.Discard) },
+	}
+	zstdEncoders = sync.Pool{
+		New: func() any {
+			enc, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+			return enc
+		},
+	}
+)
+
+// Compression returns the middleware compressing REST API responses with gzip or zstd, as negotiated via the `Accept-Encoding`
+// request header. Compressed responses have no known length, so they use chunked transfer encoding, and large result maps are
+// streamed to the client as they are compressed. Responses shorter than `minLength` bytes are sent as they are.
+func Compression(minLength int) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+
+			res := ctx.Response()
+			res.Header().Add(echo.HeaderVary, echo.HeaderAcceptEncoding)
+
+			encoding := negotiateEncoding(ctx.Request().Header.Get(echo.HeaderAcceptEncoding))
+			if encoding == "" || ctx.Request().Method == http.MethodHead {
+				return next(ctx)
+			}
+
+			writer := &compressWriter{
+				ResponseWriter: res.Writer,
+				encoding:       encoding,
+				minLength:      minLength,
+			}
+			res.Writer = writer
+			defer func() {
+				err := writer.Close()
+				if err != nil {
+					ctx.Logger().Errorf("could not finish compressed response: %s", err)
+				}
+				res.Writer = writer.ResponseWriter
+			}()
+
+			return next(ctx)
+		}
+	}
+}
+
+// negotiateEncoding returns the preferred supported encoding from the `Accept-Encoding` header value.
+// If the client accepts both zstd and gzip equally, zstd is used. Empty string means the response should not be compressed.
+func negotiateEncoding(header string) string {
+
+	var (
+		chosen  string
+		quality float64
+	)
+	for _, part := range strings.Split(header, ",") {
+
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		if name == "*" {
+			name = encodingGzip
+		}
+
+		if name != encodingGzip && name != encodingZstd || q <= 0 {
+			continue
+		}
+
+		if q > quality || q == quality && name == encodingZstd {
+			chosen, quality = name, q
+		}
+	}
+
+	return chosen
+}
+
+// compressWriter buffers the response until it reaches the minimum length, after which the response is compressed as it is written.
+type compressWriter struct {
+	http.ResponseWriter
+
+	encoding  string
+	minLength int
+
+	status  int
+	buf     bytes.Buffer
+	encoder io.WriteCloser
+	// passthrough is set for responses that are not compressed, once their headers are written.
+	passthrough bool
+}
+
+func (w *compressWriter) WriteHeader(status int) {
+
+	// Responses without a body, or already encoded by the handler, are sent as they are.
+	if status == http.StatusNoContent || status == http.StatusNotModified || w.Header().Get(echo.HeaderContentEncoding) != "" {
+		w.passthrough = true
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+
+	w.status = status
+}
+
+func (w *compressWriter) Write(p []byte) (int, error) {
+
+	if w.passthrough {
+		return w.ResponseWriter.Write(p)
+	}
+
+	if w.encoder != nil {
+		return w.encoder.Write(p)
+	}
+
+	w.buf.Write(p)
+	if w.buf.Len() < w.minLength {
+		return len(p), nil
+	}
+
+	err := w.startCompression()
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// startCompression writes the response headers and the buffered part of the response to the encoder.
+func (w *compressWriter) startCompression() error {
+
+	header := w.Header()
+	header.Set(echo.HeaderContentEncoding, w.encoding)
+	header.Del(echo.HeaderContentLength)
+
+	w.ResponseWriter.WriteHeader(w.statusCode())
+
+	switch w.encoding {
+	case encodingZstd:
+		enc := zstdEncoders.Get().(*zstd.Encoder)
+		enc.Reset(w.ResponseWriter)
+		w.encoder = enc
+
+	default:
+		enc := gzipEncoders.Get().(*gzip.Writer)
+		enc.Reset(w.ResponseWriter)
+		w.encoder = enc
+	}
+
+	_, err := w.encoder.Write(w.buf.Bytes())
+	w.buf.Reset()
+
+	return err
+}
+
+// Flush sends the data written so far to the client, compressing it if the response is not sent as it is.
+func (w *compressWriter) Flush() {
+
+	if !w.passthrough && w.encoder == nil {
+		err := w.startCompression()
+		if err != nil {
+			return
+		}
+	}
+
+	type flusher interface {
+		Flush() error
+	}
+	if enc, ok := w.encoder.(flusher); ok {
+		_ = enc.Flush()
+	}
+
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Close finishes the response. Responses shorter than the minimum length are written uncompressed.
+func (w *compressWriter) Close() error {
+
+	if w.passthrough {
+		return nil
+	}
+
+	if w.encoder == nil {
+		if w.status == 0 && w.buf.Len() == 0 {
+			return nil
+		}
+
+		w.passthrough = true
+		w.ResponseWriter.WriteHeader(w.statusCode())
+		_, err := w.ResponseWriter.Write(w.buf.Bytes())
+		return err
+	}
+
+	err := w.encoder.Close()
+
+	switch enc := w.encoder.(type) {
+	case *zstd.Encoder:
+		zstdEncoders.Put(enc)
+	case *gzip.Writer:
+		gzipEncoders.Put(enc)
+	}
+	w.encoder = nil
+	w.passthrough = true
+
+	return err
+}
+
+func (w *compressWriter) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	return hijacker.Hijack()
+}
+
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
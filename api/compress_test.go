@@ -0,0 +1,92 @@
+package api_test
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/api"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestAPI_Compression(t *testing.T) {
+
+	// Result map large enough to be compressed.
+	results := make(execute.ResultMap)
+	for _, id := range mocks.GenericPeerIDs {
+		result := mocks.GenericExecutionResult
+		result.Result.Stdout = strings.Repeat("output", 100)
+		results[id] = execute.NodeResult{Result: result}
+	}
+
+	serve := func(t *testing.T, acceptEncoding string, payload any) *httptest.ResponseRecorder {
+		t.Helper()
+
+		server := echo.New()
+		server.GET("/", func(ctx echo.Context) error {
+			return ctx.JSON(http.StatusOK, payload)
+		}, api.Compression(api.DefaultCompressionMinLength))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderAcceptEncoding, acceptEncoding)
+
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, echo.HeaderAcceptEncoding, rec.Header().Get(echo.HeaderVary))
+
+		return rec
+	}
+
+	decode := func(t *testing.T, r io.Reader) execute.ResultMap {
+		t.Helper()
+
+		var res execute.ResultMap
+		err := json.NewDecoder(r).Decode(&res)
+		require.NoError(t, err)
+
+		return res
+	}
+
+	t.Run("gzip", func(t *testing.T) {
+		rec := serve(t, "gzip", results)
+		require.Equal(t, "gzip", rec.Header().Get(echo.HeaderContentEncoding))
+
+		reader, err := gzip.NewReader(rec.Body)
+		require.NoError(t, err)
+		require.Equal(t, results, decode(t, reader))
+	})
+	t.Run("zstd is preferred", func(t *testing.T) {
+		rec := serve(t, "gzip, deflate, zstd", results)
+		require.Equal(t, "zstd", rec.Header().Get(echo.HeaderContentEncoding))
+
+		reader, err := zstd.NewReader(rec.Body)
+		require.NoError(t, err)
+		defer reader.Close()
+		require.Equal(t, results, decode(t, reader))
+	})
+	t.Run("client preference is respected", func(t *testing.T) {
+		rec := serve(t, "zstd;q=0.5, gzip;q=0.8", results)
+		require.Equal(t, "gzip", rec.Header().Get(echo.HeaderContentEncoding))
+	})
+	t.Run("unsupported encoding", func(t *testing.T) {
+		rec := serve(t, "br, gzip;q=0", results)
+		require.Empty(t, rec.Header().Get(echo.HeaderContentEncoding))
+		require.Equal(t, results, decode(t, rec.Body))
+	})
+	t.Run("small responses are not compressed", func(t *testing.T) {
+		rec := serve(t, "gzip", map[string]string{"status": "ok"})
+		require.Empty(t, rec.Header().Get(echo.HeaderContentEncoding))
+		require.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+	})
+}
@@ -6,12 +6,22 @@ import (
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/blocklessnetwork/b7s/b7serrors"
 	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
 	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/request"
 	"github.com/blocklessnetwork/b7s/node/aggregate"
 )
 
+// Response headers identifying the execution, so clients can correlate it with function logs and b7s traces.
+const (
+	RequestIDHeader = "X-B7S-Request-ID"
+	TraceIDHeader   = "X-B7S-Trace-ID"
+)
+
 // ExecuteFunction implements the REST API endpoint for function execution.
 func (a *API) ExecuteFunction(ctx echo.Context) error {
 
@@ -34,8 +44,38 @@ func (a *API) ExecuteFunction(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
 	}
 
+	if req.Callback != "" {
+
+		if !req.Async {
+			return echo.NewHTTPError(http.StatusBadRequest, errors.New("callback is only supported for asynchronous executions"))
+		}
+
+		err = request.ValidCallback(req.Callback)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid callback: %w", err))
+		}
+	}
+
+	if req.Async {
+
+		if len(req.Subgroups) > 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, errors.New("asynchronous executions cannot be dispatched to multiple subgroups"))
+		}
+
+		return a.executeAsync(ctx, exr, req.Topic, req.Callback)
+	}
+
+	if len(req.Subgroups) > 0 {
+
+		if req.Topic != "" {
+			return echo.NewHTTPError(http.StatusBadRequest, errors.New("topic and subgroups cannot be used together"))
+		}
+
+		return a.executeInSubgroups(ctx, exr, req.Subgroups)
+	}
+
 	// Get the execution result.
-	code, id, results, cluster, err := a.Node.ExecuteFunction(ctx.Request().Context(), exr, req.Topic)
+	code, id, results, cluster, timing, err := a.Node.ExecuteFunction(ctx.Request().Context(), exr, req.Topic)
 	if err != nil {
 		a.Log.Warn().Str("function", req.FunctionId).Err(err).Msg("node failed to execute function")
 	}
@@ -46,13 +86,101 @@ func (a *API) ExecuteFunction(ctx echo.Context) error {
 		RequestId: id,
 		Results:   aggregate.Aggregate(results),
 		Cluster:   cluster,
+		Timing:    timing,
 	}
 
 	// Communicate the reason for failure in these cases.
-	if errors.Is(err, blockless.ErrRollCallTimeout) || errors.Is(err, blockless.ErrExecutionNotEnoughNodes) {
+	if b7serrors.IsPublic(err) {
 		res.Message = err.Error()
 	}
 
+	// Results of sampled executions are also reported for each stratum.
+	for _, stratum := range execute.GroupByStratum(results, cluster.Strata) {
+		res.Strata = append(res.Strata, StratumExecutionResponse{
+			Stratum: stratum.Stratum,
+			Peers:   blockless.PeerIDsToStr(stratum.Peers),
+			Results: aggregate.Aggregate(stratum.Results),
+		})
+	}
+
+	if id != "" {
+		ctx.Response().Header().Set(RequestIDHeader, id)
+	}
+	if sc := trace.SpanContextFromContext(ctx.Request().Context()); sc.HasTraceID() {
+		ctx.Response().Header().Set(TraceIDHeader, sc.TraceID().String())
+	}
+
 	// Send the response.
 	return ctx.JSON(http.StatusOK, res)
 }
+
+// executeAsync starts the execution and acknowledges it right away. Once the execution is done, results are posted to the callback URL,
+// if set, and can be retrieved using the request ID.
+func (a *API) executeAsync(ctx echo.Context, req execute.Request, topic string, callback string) error {
+
+	code, id, err := a.Node.ExecuteFunctionAsync(ctx.Request().Context(), req, topic, callback)
+	if err != nil {
+		a.Log.Warn().Str("function", req.FunctionID).Err(err).Msg("node failed to start asynchronous execution")
+	}
+
+	res := ExecutionResponse{
+		Code:      string(code),
+		RequestId: id,
+	}
+
+	// Communicate the reason for failure in these cases.
+	if b7serrors.IsPublic(err) {
+		res.Message = err.Error()
+	}
+
+	status := http.StatusOK
+	if code == codes.Accepted {
+		status = http.StatusAccepted
+	}
+
+	if id != "" {
+		ctx.Response().Header().Set(RequestIDHeader, id)
+	}
+
+	return ctx.JSON(status, res)
+}
+
+// executeInSubgroups dispatches the execution to multiple subgroups and returns the results of each subgroup separately.
+func (a *API) executeInSubgroups(ctx echo.Context, req execute.Request, targets []execute.SubgroupTarget) error {
+
+	code, results, err := a.Node.ExecuteFunctionInSubgroups(ctx.Request().Context(), req, targets)
+	if code == codes.Invalid {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+	}
+	if err != nil {
+		a.Log.Warn().Str("function", req.FunctionID).Err(err).Msg("node failed to execute function in subgroups")
+	}
+
+	res := ExecutionResponse{
+		Code:      string(code),
+		Subgroups: make([]SubgroupExecutionResponse, 0, len(results)),
+	}
+
+	// Communicate the reason for failure in these cases.
+	if b7serrors.IsPublic(err) {
+		res.Message = err.Error()
+	}
+
+	for _, result := range results {
+		res.Subgroups = append(res.Subgroups, SubgroupExecutionResponse{
+			Topic:     result.Topic,
+			Code:      string(result.Code),
+			RequestId: result.RequestID,
+			Message:   result.Message,
+			Results:   aggregate.Aggregate(result.Results),
+			Cluster:   result.Cluster,
+			Timing:    result.Timing,
+		})
+	}
+
+	if sc := trace.SpanContextFromContext(ctx.Request().Context()); sc.HasTraceID() {
+		ctx.Response().Header().Set(TraceIDHeader, sc.TraceID().String())
+	}
+
+	return ctx.JSON(http.StatusOK, res)
+}
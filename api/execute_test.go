@@ -28,10 +28,14 @@ func TestAPI_Execute(t *testing.T) {
 	peerIDs := []peer.ID{
 		mocks.GenericPeerID,
 	}
+	timing := execute.Timing{
+		RollCall:     100,
+		ResultGather: 200,
+	}
 	expectedCode := codes.OK
 
 	node := mocks.BaselineNode(t)
-	node.ExecuteFunctionFunc = func(context.Context, execute.Request, string) (codes.Code, string, execute.ResultMap, execute.Cluster, error) {
+	node.ExecuteFunctionFunc = func(context.Context, execute.Request, string) (codes.Code, string, execute.ResultMap, execute.Cluster, execute.Timing, error) {
 
 		res := execute.ResultMap{
 			mocks.GenericPeerID: execute.NodeResult{Result: executionResult},
@@ -41,7 +45,7 @@ func TestAPI_Execute(t *testing.T) {
 			Peers: peerIDs,
 		}
 
-		return expectedCode, mocks.GenericUUID.String(), res, cluster, nil
+		return expectedCode, mocks.GenericUUID.String(), res, cluster, timing, nil
 	}
 
 	srv := api.New(mocks.NoopLogger, node)
@@ -70,6 +74,10 @@ func TestAPI_Execute(t *testing.T) {
 	require.Equal(t, peerIDs, res.Results[0].Peers)
 
 	require.Equal(t, mocks.GenericUUID.String(), res.RequestId)
+	require.Equal(t, timing, res.Timing)
+
+	// Request ID is reported in the response headers too.
+	require.Equal(t, mocks.GenericUUID.String(), rec.Header().Get(api.RequestIDHeader))
 }
 
 func TestAPI_Execute_HandlesErrors(t *testing.T) {
@@ -85,13 +93,13 @@ func TestAPI_Execute_HandlesErrors(t *testing.T) {
 	expectedCode := codes.Error
 
 	node := mocks.BaselineNode(t)
-	node.ExecuteFunctionFunc = func(context.Context, execute.Request, string) (codes.Code, string, execute.ResultMap, execute.Cluster, error) {
+	node.ExecuteFunctionFunc = func(context.Context, execute.Request, string) (codes.Code, string, execute.ResultMap, execute.Cluster, execute.Timing, error) {
 
 		res := execute.ResultMap{
 			mocks.GenericPeerID: execute.NodeResult{Result: executionResult},
 		}
 
-		return expectedCode, "", res, execute.Cluster{}, mocks.GenericError
+		return expectedCode, "", res, execute.Cluster{}, execute.Timing{}, mocks.GenericError
 	}
 
 	srv := api.New(mocks.NoopLogger, node)
@@ -189,3 +197,171 @@ func TestAPI_Execute_HandlesMalformedRequests(t *testing.T) {
 		})
 	}
 }
+
+func TestAPI_ExecuteInSubgroups(t *testing.T) {
+
+	targets := []execute.SubgroupTarget{
+		{Topic: "eu", NodeCount: 3},
+		{Topic: "us", NodeCount: 1},
+	}
+
+	req := api.ExecutionRequest{
+		FunctionId: mocks.GenericExecutionRequest.FunctionID,
+		Method:     mocks.GenericExecutionRequest.Method,
+		Subgroups:  targets,
+	}
+
+	t.Run("nominal case", func(t *testing.T) {
+
+		node := mocks.BaselineNode(t)
+		node.ExecuteInSubgroupsFunc = func(_ context.Context, _ execute.Request, received []execute.SubgroupTarget) (codes.Code, []execute.SubgroupResult, error) {
+			require.Equal(t, targets, received)
+
+			results := []execute.SubgroupResult{
+				{
+					Topic:     "eu",
+					Code:      codes.OK,
+					RequestID: mocks.GenericUUID.String(),
+					Results:   mocks.GenericExecutionResultMap,
+				},
+				{
+					Topic:   "us",
+					Code:    codes.Timeout,
+					Message: "roll call timed out",
+				},
+			}
+
+			return codes.PartialContent, results, nil
+		}
+
+		srv := api.New(mocks.NoopLogger, node)
+
+		rec, ctx, err := setupRecorder(executeEndpoint, req)
+		require.NoError(t, err)
+
+		err = srv.ExecuteFunction(ctx)
+		require.NoError(t, err)
+
+		var res api.ExecutionResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+
+		require.Equal(t, http.StatusOK, rec.Result().StatusCode)
+		require.Equal(t, codes.PartialContent.String(), res.Code)
+		require.Empty(t, res.Results)
+
+		require.Len(t, res.Subgroups, 2)
+
+		eu := res.Subgroups[0]
+		require.Equal(t, "eu", eu.Topic)
+		require.Equal(t, codes.OK.String(), eu.Code)
+		require.Equal(t, mocks.GenericUUID.String(), eu.RequestId)
+		require.Len(t, eu.Results, 1)
+
+		us := res.Subgroups[1]
+		require.Equal(t, "us", us.Topic)
+		require.Equal(t, codes.Timeout.String(), us.Code)
+		require.Equal(t, "roll call timed out", us.Message)
+		require.Empty(t, us.Results)
+	})
+	t.Run("topic and subgroups are exclusive", func(t *testing.T) {
+
+		req := req
+		req.Topic = "eu"
+
+		srv := api.New(mocks.NoopLogger, mocks.BaselineNode(t))
+
+		_, ctx, err := setupRecorder(executeEndpoint, req)
+		require.NoError(t, err)
+
+		err = srv.ExecuteFunction(ctx)
+		require.Error(t, err)
+
+		echoErr, ok := err.(*echo.HTTPError)
+		require.True(t, ok)
+		require.Equal(t, http.StatusBadRequest, echoErr.Code)
+	})
+	t.Run("invalid subgroups", func(t *testing.T) {
+
+		node := mocks.BaselineNode(t)
+		node.ExecuteInSubgroupsFunc = func(context.Context, execute.Request, []execute.SubgroupTarget) (codes.Code, []execute.SubgroupResult, error) {
+			return codes.Invalid, nil, mocks.GenericError
+		}
+
+		srv := api.New(mocks.NoopLogger, node)
+
+		_, ctx, err := setupRecorder(executeEndpoint, req)
+		require.NoError(t, err)
+
+		err = srv.ExecuteFunction(ctx)
+		require.Error(t, err)
+
+		echoErr, ok := err.(*echo.HTTPError)
+		require.True(t, ok)
+		require.Equal(t, http.StatusBadRequest, echoErr.Code)
+	})
+}
+
+func TestAPI_ExecuteAsync(t *testing.T) {
+
+	const (
+		callback = "https://example.com/results"
+	)
+
+	req := api.ExecutionRequest{
+		FunctionId: mocks.GenericExecutionRequest.FunctionID,
+		Method:     mocks.GenericExecutionRequest.Method,
+		Async:      true,
+		Callback:   callback,
+	}
+
+	t.Run("nominal case", func(t *testing.T) {
+
+		node := mocks.BaselineNode(t)
+		node.ExecuteFunctionAsyncFunc = func(_ context.Context, _ execute.Request, _ string, received string) (codes.Code, string, error) {
+			require.Equal(t, callback, received)
+			return codes.Accepted, mocks.GenericUUID.String(), nil
+		}
+
+		srv := api.New(mocks.NoopLogger, node)
+
+		rec, ctx, err := setupRecorder(executeEndpoint, req)
+		require.NoError(t, err)
+
+		err = srv.ExecuteFunction(ctx)
+		require.NoError(t, err)
+
+		var res api.ExecutionResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+
+		require.Equal(t, http.StatusAccepted, rec.Result().StatusCode)
+		require.Equal(t, codes.Accepted.String(), res.Code)
+		require.Equal(t, mocks.GenericUUID.String(), res.RequestId)
+		require.Equal(t, mocks.GenericUUID.String(), rec.Header().Get(api.RequestIDHeader))
+	})
+	t.Run("invalid requests", func(t *testing.T) {
+
+		noAsync := req
+		noAsync.Async = false
+
+		badCallback := req
+		badCallback.Callback = "ftp://example.com/results"
+
+		withSubgroups := req
+		withSubgroups.Subgroups = []execute.SubgroupTarget{{Topic: "eu"}}
+
+		for _, req := range []api.ExecutionRequest{noAsync, badCallback, withSubgroups} {
+
+			srv := api.New(mocks.NoopLogger, mocks.BaselineNode(t))
+
+			_, ctx, err := setupRecorder(executeEndpoint, req)
+			require.NoError(t, err)
+
+			err = srv.ExecuteFunction(ctx)
+			require.Error(t, err)
+
+			echoErr, ok := err.(*echo.HTTPError)
+			require.True(t, ok)
+			require.Equal(t, http.StatusBadRequest, echoErr.Code)
+		}
+	})
+}
@@ -17,14 +17,26 @@ type AggregatedResults = aggregate.Results
 // AttributeAttestors Require specific attestors as vouchers
 type AttributeAttestors = execute.AttributeAttestors
 
+// ExecutionArtifact Output file written by the function to its artifacts directory, and uploaded to blob storage
+type ExecutionArtifact = execute.Artifact
+
 // ExecutionConfig Configuration options for the Execution Request
 type ExecutionConfig = execute.Config
 
+// ExecutionInput Remote resource, such as a dataset, placed in the function filesystem before the execution
+type ExecutionInput = execute.Input
+
 // ExecutionParameter defines model for ExecutionParameter.
 type ExecutionParameter = execute.Parameter
 
 // ExecutionRequest defines model for ExecutionRequest.
 type ExecutionRequest struct {
+	// Async Acknowledge the request right away, and deliver the results once the execution is done. Cannot be used together with subgroups
+	Async bool `json:"async,omitempty"`
+
+	// Callback HTTP URL the results of an asynchronous execution are posted to. The URL must resolve to a public address and redirects are not followed
+	Callback string `json:"callback,omitempty"`
+
 	// Config Configuration options for the Execution Request
 	Config ExecutionConfig `json:"config,omitempty"`
 
@@ -37,6 +49,9 @@ type ExecutionRequest struct {
 	// Parameters CLI arguments for the Blockless Function
 	Parameters []ExecutionParameter `json:"parameters,omitempty"`
 
+	// Subgroups Dispatch the execution to multiple subgroups at the same time, gathering results for each subgroup separately. Cannot be used together with topic
+	Subgroups []SubgroupTarget `json:"subgroups,omitempty"`
+
 	// Topic In the scenario where workers form subgroups, you can target a specific subgroup by specifying its identifier
 	Topic string `json:"topic,omitempty"`
 }
@@ -57,11 +72,26 @@ type ExecutionResponse struct {
 
 	// Results List of unique results of the Execution Request
 	Results AggregatedResults `json:"results,omitempty"`
+
+	// Strata Results of the execution in each stratum, if the execution was done on a stratified sample of workers
+	Strata []StratumExecutionResponse `json:"strata,omitempty"`
+
+	// Subgroups Results of the execution in each subgroup, if the execution was dispatched to multiple subgroups
+	Subgroups []SubgroupExecutionResponse `json:"subgroups,omitempty"`
+
+	// Timing Time spent in each phase of the execution, in milliseconds
+	Timing ExecutionTiming `json:"timing,omitempty"`
 }
 
 // ExecutionResult Actual outputs of the execution, like Standard Output, Standard Error, Exit Code etc..
 type ExecutionResult = execute.RuntimeOutput
 
+// ExecutionSampling Execute the request on a stratified sample of workers, grouped by the value of one of their attributes
+type ExecutionSampling = execute.Sampling
+
+// ExecutionTiming Time spent in each phase of the execution, in milliseconds
+type ExecutionTiming = execute.Timing
+
 // FunctionInstallRequest defines model for FunctionInstallRequest.
 type FunctionInstallRequest struct {
 	// Cid CID of the function
@@ -86,6 +116,9 @@ type FunctionResultRequest struct {
 // FunctionResultResponse defines model for FunctionResultResponse.
 type FunctionResultResponse = ExecutionResponse
 
+// GPURequirements GPUs the Node should have. Executions requiring GPUs are given access to the GPU devices of the Node
+type GPURequirements = execute.GPURequirements
+
 // HealthStatus Node status
 type HealthStatus struct {
 	Code string `json:"code,omitempty"`
@@ -106,6 +139,45 @@ type ResultAggregation = execute.ResultAggregation
 // RuntimeConfig Configuration options for the Blockless Runtime
 type RuntimeConfig = execute.BLSRuntimeConfig
 
+// StratumExecutionResponse Results of the workers sampled from a single stratum
+type StratumExecutionResponse struct {
+	// Peers LibP2P IDs of the Nodes sampled from the stratum
+	Peers []string `json:"peers,omitempty"`
+
+	// Results List of unique results of the Execution Request
+	Results AggregatedResults `json:"results,omitempty"`
+
+	// Stratum Value of the sampling attribute shared by the Nodes in the stratum
+	Stratum string `json:"stratum,omitempty"`
+}
+
+// SubgroupExecutionResponse Outcome of the execution in a single subgroup
+type SubgroupExecutionResponse struct {
+	// Cluster Information about the cluster of nodes that executed this request
+	Cluster NodeCluster `json:"cluster,omitempty"`
+
+	// Code Status of the execution in the subgroup
+	Code string `json:"code,omitempty"`
+
+	// Message If the execution in the subgroup failed, this message might have more info about the error
+	Message string `json:"message,omitempty"`
+
+	// RequestId ID of the Execution Request in the subgroup
+	RequestId string `json:"request_id,omitempty"`
+
+	// Results List of unique results of the Execution Request
+	Results AggregatedResults `json:"results,omitempty"`
+
+	// Timing Time spent in each phase of the execution, in milliseconds
+	Timing ExecutionTiming `json:"timing,omitempty"`
+
+	// Topic Identifier of the subgroup
+	Topic string `json:"topic,omitempty"`
+}
+
+// SubgroupTarget Subgroup the execution is dispatched to
+type SubgroupTarget = execute.SubgroupTarget
+
 // ExecuteFunctionJSONRequestBody defines body for ExecuteFunction for application/json ContentType.
 type ExecuteFunctionJSONRequestBody = ExecutionRequest
 
@@ -8,7 +8,9 @@ import (
 )
 
 type Node interface {
-	ExecuteFunction(ctx context.Context, req execute.Request, subgroup string) (code codes.Code, requestID string, results execute.ResultMap, peers execute.Cluster, err error)
+	ExecuteFunction(ctx context.Context, req execute.Request, subgroup string) (code codes.Code, requestID string, results execute.ResultMap, peers execute.Cluster, timing execute.Timing, err error)
+	ExecuteFunctionAsync(ctx context.Context, req execute.Request, subgroup string, callback string) (code codes.Code, requestID string, err error)
+	ExecuteFunctionInSubgroups(ctx context.Context, req execute.Request, targets []execute.SubgroupTarget) (code codes.Code, results []execute.SubgroupResult, err error)
 	ExecutionResult(id string) (execute.ResultMap, bool)
 	PublishFunctionInstall(ctx context.Context, uri string, cid string, subgroup string) error
 }
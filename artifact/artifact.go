@@ -0,0 +1,20 @@
+// Package artifact provides blob storage backends for output files produced by functions. Workers upload the files
+// and return their locations in the execution result, so large outputs do not have to travel in p2p messages.
+package artifact
+
+import (
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// How long can a single upload take.
+const defaultTimeout = 5 * time.Minute
+
+func newHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout:   defaultTimeout,
+		Transport: otelhttp.NewTransport(http.DefaultTransport),
+	}
+}
@@ -0,0 +1,102 @@
+package artifact
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+// IPFS uploads artifacts to an IPFS node, using its HTTP RPC API.
+type IPFS struct {
+	api     string
+	gateway string
+	http    *http.Client
+}
+
+// NewIPFS creates a new IPFS artifact store. The API address is the address of the IPFS node RPC API (e.g. `http://127.0.0.1:5001`).
+// If the gateway address is set, artifacts get a gateway URL along with their CID.
+func NewIPFS(api string, gateway string) (*IPFS, error) {
+
+	if api == "" {
+		return nil, errors.New("IPFS API address is required")
+	}
+
+	s := IPFS{
+		api:     strings.TrimSuffix(api, "/"),
+		gateway: strings.TrimSuffix(gateway, "/"),
+		http:    newHTTPClient(),
+	}
+
+	return &s, nil
+}
+
+// Upload adds the file to the IPFS node and pins it.
+func (s *IPFS) Upload(ctx context.Context, key string, data io.Reader, size int64) (execute.Artifact, error) {
+
+	// Stream the file to the IPFS node as it is read.
+	body, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+	go func() {
+		part, err := form.CreateFormFile("file", path.Base(key))
+		if err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+
+		_, err = io.Copy(part, data)
+		if err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+
+		writer.CloseWithError(form.Close())
+	}()
+
+	address := fmt.Sprintf("%s/api/v0/add?cid-version=1&pin=true", s.api)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, address, body)
+	if err != nil {
+		body.Close()
+		return execute.Artifact{}, fmt.Errorf("could not create request: %w", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	res, err := s.http.Do(req)
+	if err != nil {
+		return execute.Artifact{}, fmt.Errorf("could not upload file: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return execute.Artifact{}, fmt.Errorf("unexpected response status: %s", res.Status)
+	}
+
+	var added struct {
+		Hash string `json:"Hash"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&added)
+	if err != nil {
+		return execute.Artifact{}, fmt.Errorf("could not decode response: %w", err)
+	}
+
+	if added.Hash == "" {
+		return execute.Artifact{}, errors.New("IPFS node returned no CID")
+	}
+
+	artifact := execute.Artifact{
+		Size: size,
+		CID:  added.Hash,
+	}
+	if s.gateway != "" {
+		artifact.URL = fmt.Sprintf("%s/ipfs/%s", s.gateway, added.Hash)
+	}
+
+	return artifact, nil
+}
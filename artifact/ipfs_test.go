@@ -0,0 +1,83 @@
+package artifact_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/artifact"
+)
+
+func TestIPFS_Upload(t *testing.T) {
+
+	const (
+		key     = "dummy-request-id/report.csv"
+		payload = "a,b,c"
+		cid     = "bafkreigh2akiscaildcqabsyg3dfr6chu3fgpregiymsck7e7aqa4s52zy"
+		gateway = "https://ipfs.example.com"
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/api/v0/add", req.URL.Path)
+		require.Equal(t, "true", req.URL.Query().Get("pin"))
+
+		file, header, err := req.FormFile("file")
+		require.NoError(t, err)
+		defer file.Close()
+
+		require.Equal(t, "report.csv", header.Filename)
+
+		data, err := io.ReadAll(file)
+		require.NoError(t, err)
+		require.Equal(t, payload, string(data))
+
+		w.Write([]byte(`{"Name":"report.csv","Hash":"` + cid + `","Size":"5"}`))
+	}))
+	defer srv.Close()
+
+	t.Run("nominal case", func(t *testing.T) {
+		store, err := artifact.NewIPFS(srv.URL, gateway+"/")
+		require.NoError(t, err)
+
+		res, err := store.Upload(context.Background(), key, strings.NewReader(payload), int64(len(payload)))
+		require.NoError(t, err)
+
+		require.Equal(t, cid, res.CID)
+		require.Equal(t, gateway+"/ipfs/"+cid, res.URL)
+		require.Equal(t, int64(len(payload)), res.Size)
+	})
+	t.Run("no gateway", func(t *testing.T) {
+		store, err := artifact.NewIPFS(srv.URL, "")
+		require.NoError(t, err)
+
+		res, err := store.Upload(context.Background(), key, strings.NewReader(payload), int64(len(payload)))
+		require.NoError(t, err)
+
+		require.Equal(t, cid, res.CID)
+		require.Empty(t, res.URL)
+	})
+	t.Run("missing API address", func(t *testing.T) {
+		_, err := artifact.NewIPFS("", gateway)
+		require.Error(t, err)
+	})
+}
+
+func TestIPFS_UploadHandlesErrors(t *testing.T) {
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	store, err := artifact.NewIPFS(srv.URL, "")
+	require.NoError(t, err)
+
+	_, err = store.Upload(context.Background(), "dummy-request-id/report.csv", strings.NewReader("a,b,c"), 5)
+	require.Error(t, err)
+}
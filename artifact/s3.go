@@ -0,0 +1,198 @@
+package artifact
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+const (
+	s3Algorithm      = "AWS4-HMAC-SHA256"
+	s3Service        = "s3"
+	s3UnsignedSHA256 = "UNSIGNED-PAYLOAD"
+	s3DateFormat     = "20060102"
+	s3TimeFormat     = "20060102T150405Z"
+)
+
+// S3Config describes the S3-compatible bucket artifacts are uploaded to.
+type S3Config struct {
+	Endpoint  string // Address of the S3-compatible service, e.g. `https://s3.us-east-1.amazonaws.com`.
+	Region    string // Region of the bucket.
+	Bucket    string // Name of the bucket.
+	AccessKey string // Access key ID.
+	SecretKey string // Secret access key.
+	Prefix    string // Prefix for the object keys, e.g. when the bucket is shared with other data.
+	PublicURL string // Base URL of the published bucket contents. If unset, the object URL is used.
+}
+
+// Valid checks if the configuration is correct.
+func (c S3Config) Valid() error {
+
+	if c.Endpoint == "" {
+		return errors.New("endpoint is required")
+	}
+
+	_, err := url.Parse(c.Endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid endpoint: %w", err)
+	}
+
+	if c.Region == "" {
+		return errors.New("region is required")
+	}
+
+	if c.Bucket == "" {
+		return errors.New("bucket is required")
+	}
+
+	if c.AccessKey == "" || c.SecretKey == "" {
+		return errors.New("credentials are required")
+	}
+
+	return nil
+}
+
+// S3 uploads artifacts to an S3-compatible bucket, using path-style addressing.
+type S3 struct {
+	cfg  S3Config
+	http *http.Client
+	now  func() time.Time
+}
+
+// NewS3 creates a new S3 artifact store.
+func NewS3(cfg S3Config) (*S3, error) {
+
+	err := cfg.Valid()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	cfg.Endpoint = strings.TrimSuffix(cfg.Endpoint, "/")
+	cfg.PublicURL = strings.TrimSuffix(cfg.PublicURL, "/")
+
+	s := S3{
+		cfg:  cfg,
+		http: newHTTPClient(),
+		now:  time.Now,
+	}
+
+	return &s, nil
+}
+
+// Upload puts the file in the bucket, under the given key.
+func (s *S3) Upload(ctx context.Context, key string, data io.Reader, size int64) (execute.Artifact, error) {
+
+	if s.cfg.Prefix != "" {
+		key = strings.TrimSuffix(s.cfg.Prefix, "/") + "/" + key
+	}
+
+	objectPath := "/" + s.cfg.Bucket + "/" + key
+	address, err := url.Parse(s.cfg.Endpoint + uriEncode(objectPath))
+	if err != nil {
+		return execute.Artifact{}, fmt.Errorf("could not create object URL: %w", err)
+	}
+
+	// S3 does not accept chunked uploads, so the request must have the length set - even for empty files.
+	if size == 0 {
+		data = http.NoBody
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, address.String(), data)
+	if err != nil {
+		return execute.Artifact{}, fmt.Errorf("could not create request: %w", err)
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	s.sign(req, s.now().UTC())
+
+	res, err := s.http.Do(req)
+	if err != nil {
+		return execute.Artifact{}, fmt.Errorf("could not upload file: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return execute.Artifact{}, fmt.Errorf("unexpected response status: %s", res.Status)
+	}
+
+	artifact := execute.Artifact{
+		Size: size,
+		URL:  address.String(),
+	}
+	if s.cfg.PublicURL != "" {
+		artifact.URL = s.cfg.PublicURL + uriEncode("/"+key)
+	}
+
+	return artifact, nil
+}
+
+// sign adds the AWS Signature Version 4 authorization header to the request. The payload is not signed, so files can be
+// streamed without reading them twice.
+func (s *S3) sign(req *http.Request, now time.Time) {
+
+	date := now.Format(s3DateFormat)
+	timestamp := now.Format(s3TimeFormat)
+
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedSHA256)
+	req.Header.Set("X-Amz-Date", timestamp)
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := fmt.Sprintf("host:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n", req.URL.Host, s3UnsignedSHA256, timestamp)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		s3UnsignedSHA256,
+	}, "\n")
+
+	scope := strings.Join([]string{date, s.cfg.Region, s3Service, "aws4_request"}, "/")
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{s3Algorithm, timestamp, scope, hex.EncodeToString(hash[:])}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), date)
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, s3Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.cfg.AccessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// uriEncode encodes the object path as required by the AWS signature - all characters except the unreserved ones and
+// the path separator are percent-encoded.
+func uriEncode(path string) string {
+
+	var b strings.Builder
+	for _, c := range []byte(path) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,99 @@
+package artifact_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/artifact"
+)
+
+func TestS3_Upload(t *testing.T) {
+
+	const (
+		key     = "dummy-request-id/daily report.csv"
+		payload = "a,b,c"
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/bucket/worker/dummy-request-id/daily%20report.csv", req.URL.EscapedPath())
+		require.Equal(t, int64(len(payload)), req.ContentLength)
+		require.Equal(t, "UNSIGNED-PAYLOAD", req.Header.Get("X-Amz-Content-Sha256"))
+		require.NotEmpty(t, req.Header.Get("X-Amz-Date"))
+
+		auth := req.Header.Get("Authorization")
+		require.True(t, strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=access-key/"))
+		require.Contains(t, auth, "/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=")
+
+		data, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.Equal(t, payload, string(data))
+	}))
+	defer srv.Close()
+
+	cfg := artifact.S3Config{
+		Endpoint:  srv.URL,
+		Region:    "us-east-1",
+		Bucket:    "bucket",
+		AccessKey: "access-key",
+		SecretKey: "secret-key",
+		Prefix:    "worker",
+	}
+
+	t.Run("nominal case", func(t *testing.T) {
+		store, err := artifact.NewS3(cfg)
+		require.NoError(t, err)
+
+		res, err := store.Upload(context.Background(), key, strings.NewReader(payload), int64(len(payload)))
+		require.NoError(t, err)
+
+		require.Equal(t, srv.URL+"/bucket/worker/dummy-request-id/daily%20report.csv", res.URL)
+		require.Equal(t, int64(len(payload)), res.Size)
+		require.Empty(t, res.CID)
+	})
+	t.Run("public URL", func(t *testing.T) {
+		cfg := cfg
+		cfg.PublicURL = "https://artifacts.example.com/"
+
+		store, err := artifact.NewS3(cfg)
+		require.NoError(t, err)
+
+		res, err := store.Upload(context.Background(), key, strings.NewReader(payload), int64(len(payload)))
+		require.NoError(t, err)
+
+		require.Equal(t, "https://artifacts.example.com/worker/dummy-request-id/daily%20report.csv", res.URL)
+	})
+	t.Run("invalid configuration", func(t *testing.T) {
+		cfg := cfg
+		cfg.SecretKey = ""
+
+		_, err := artifact.NewS3(cfg)
+		require.Error(t, err)
+	})
+}
+
+func TestS3_UploadHandlesErrors(t *testing.T) {
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	store, err := artifact.NewS3(artifact.S3Config{
+		Endpoint:  srv.URL,
+		Region:    "us-east-1",
+		Bucket:    "bucket",
+		AccessKey: "access-key",
+		SecretKey: "secret-key",
+	})
+	require.NoError(t, err)
+
+	_, err = store.Upload(context.Background(), "dummy-request-id/report.csv", strings.NewReader("a,b,c"), 5)
+	require.Error(t, err)
+}
@@ -0,0 +1,51 @@
+package b7serrors
+
+import (
+	"fmt"
+)
+
+// Category groups errors by how the caller is expected to handle them.
+type Category uint
+
+const (
+	CategoryNone Category = iota
+	CategoryInvalid
+	CategoryNotFound
+	CategoryNotPermitted
+	CategoryTimeout
+	CategoryRateLimited
+	CategoryQuotaExceeded
+	CategoryUnavailable
+	CategoryNotSupported
+	CategoryInternal
+	CategoryTooManyInFlight
+)
+
+func (c Category) String() string {
+	switch c {
+	case CategoryNone:
+		return "none"
+	case CategoryInvalid:
+		return "invalid"
+	case CategoryNotFound:
+		return "not-found"
+	case CategoryNotPermitted:
+		return "not-permitted"
+	case CategoryTimeout:
+		return "timeout"
+	case CategoryRateLimited:
+		return "rate-limited"
+	case CategoryQuotaExceeded:
+		return "quota-exceeded"
+	case CategoryTooManyInFlight:
+		return "too-many-in-flight"
+	case CategoryUnavailable:
+		return "unavailable"
+	case CategoryNotSupported:
+		return "not-supported"
+	case CategoryInternal:
+		return "internal"
+	default:
+		return fmt.Sprintf("unknown: %d", c)
+	}
+}
@@ -0,0 +1,130 @@
+// Package b7serrors defines the errors b7s nodes return, grouped into categories, so embedders can handle failures
+// uniformly. Errors can be mapped to Blockless response codes, HTTP statuses and gRPC codes.
+package b7serrors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Sentinel errors.
+var (
+	ErrNotFound                = New(CategoryNotFound, "not found")
+	ErrRollCallTimeout         = New(CategoryTimeout, "roll call timed out - not enough nodes responded")
+	ErrExecutionNotEnoughNodes = New(CategoryUnavailable, "not enough execution results received")
+	ErrQuotaExceeded           = New(CategoryQuotaExceeded, "client quota exceeded")
+	ErrRateLimited             = New(CategoryRateLimited, "execution request rate limit exceeded")
+	ErrTooManyInFlight         = New(CategoryTooManyInFlight, "too many executions in flight")
+)
+
+// publicErrors are errors whose message is communicated back to the client as the reason for a failed request.
+var publicErrors = []error{
+	ErrRollCallTimeout,
+	ErrExecutionNotEnoughNodes,
+	ErrQuotaExceeded,
+	ErrRateLimited,
+	ErrTooManyInFlight,
+}
+
+// Error is an error with a category.
+type Error struct {
+	category Category
+	msg      string
+}
+
+// New creates a new error with the given category.
+func New(category Category, msg string) *Error {
+	return &Error{
+		category: category,
+		msg:      msg,
+	}
+}
+
+func (e *Error) Error() string {
+	return e.msg
+}
+
+func (e *Error) Category() Category {
+	return e.category
+}
+
+// categorized is an error annotated with a category.
+type categorized struct {
+	err      error
+	category Category
+}
+
+func (e *categorized) Error() string {
+	return e.err.Error()
+}
+
+func (e *categorized) Unwrap() error {
+	return e.err
+}
+
+func (e *categorized) Category() Category {
+	return e.category
+}
+
+// WithCategory sets the category of an arbitrary error. The original error can still be matched using `errors.Is` and `errors.As`.
+func WithCategory(err error, category Category) error {
+	if err == nil {
+		return nil
+	}
+
+	return &categorized{
+		err:      err,
+		category: category,
+	}
+}
+
+// Wrap adds context to the error message, keeping the error matchable using `errors.Is` and `errors.As`.
+func Wrap(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("%s: %w", fmt.Sprintf(format, args...), err)
+}
+
+// CategoryOf returns the category of the first categorized error in the chain.
+// Errors without a category are in the `CategoryInternal` category.
+func CategoryOf(err error) Category {
+
+	if err == nil {
+		return CategoryNone
+	}
+
+	var cerr interface{ Category() Category }
+	if errors.As(err, &cerr) {
+		return cerr.Category()
+	}
+
+	return CategoryInternal
+}
+
+// IsPublic returns true if the error message should be communicated to the client as the reason for the failure.
+func IsPublic(err error) bool {
+
+	for _, perr := range publicErrors {
+		if errors.Is(err, perr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParsePublic returns the public error whose message the given error message ends with, as reported by another node.
+// Unknown messages yield an internal error.
+func ParsePublic(msg string) error {
+
+	for _, perr := range publicErrors {
+		if strings.HasSuffix(msg, perr.Error()) {
+			return perr
+		}
+	}
+
+	return errors.New(msg)
+}
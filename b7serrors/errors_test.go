@@ -0,0 +1,86 @@
+package b7serrors_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	grpccodes "google.golang.org/grpc/codes"
+
+	"github.com/blocklessnetwork/b7s/b7serrors"
+	"github.com/blocklessnetwork/b7s/models/codes"
+)
+
+func TestCategoryOf(t *testing.T) {
+
+	t.Run("sentinel errors", func(t *testing.T) {
+		require.Equal(t, b7serrors.CategoryTimeout, b7serrors.CategoryOf(b7serrors.ErrRollCallTimeout))
+		require.Equal(t, b7serrors.CategoryQuotaExceeded, b7serrors.CategoryOf(b7serrors.ErrQuotaExceeded))
+	})
+	t.Run("wrapped errors keep their category", func(t *testing.T) {
+		err := fmt.Errorf("could not roll call peers: %w", b7serrors.ErrRollCallTimeout)
+		require.Equal(t, b7serrors.CategoryTimeout, b7serrors.CategoryOf(err))
+
+		err = b7serrors.Wrap(b7serrors.ErrNotFound, "function not installed (function: %s)", "abc")
+		require.ErrorIs(t, err, b7serrors.ErrNotFound)
+		require.Equal(t, "function not installed (function: abc): not found", err.Error())
+		require.Equal(t, b7serrors.CategoryNotFound, b7serrors.CategoryOf(err))
+	})
+	t.Run("categorized errors", func(t *testing.T) {
+		orig := errors.New("bad input")
+		err := b7serrors.WithCategory(orig, b7serrors.CategoryInvalid)
+
+		require.ErrorIs(t, err, orig)
+		require.Equal(t, orig.Error(), err.Error())
+		require.Equal(t, b7serrors.CategoryInvalid, b7serrors.CategoryOf(err))
+	})
+	t.Run("uncategorized errors are internal", func(t *testing.T) {
+		require.Equal(t, b7serrors.CategoryInternal, b7serrors.CategoryOf(errors.New("failure")))
+	})
+	t.Run("nil errors", func(t *testing.T) {
+		require.Equal(t, b7serrors.CategoryNone, b7serrors.CategoryOf(nil))
+		require.NoError(t, b7serrors.Wrap(nil, "context"))
+		require.NoError(t, b7serrors.WithCategory(nil, b7serrors.CategoryInvalid))
+	})
+}
+
+func TestMapping(t *testing.T) {
+
+	tests := []struct {
+		err    error
+		code   codes.Code
+		status int
+		grpc   grpccodes.Code
+	}{
+		{nil, codes.OK, http.StatusOK, grpccodes.OK},
+		{b7serrors.ErrNotFound, codes.NotFound, http.StatusNotFound, grpccodes.NotFound},
+		{b7serrors.ErrRollCallTimeout, codes.Timeout, http.StatusRequestTimeout, grpccodes.DeadlineExceeded},
+		{b7serrors.ErrExecutionNotEnoughNodes, codes.NotAvailable, http.StatusServiceUnavailable, grpccodes.Unavailable},
+		{b7serrors.ErrQuotaExceeded, codes.QuotaExceeded, http.StatusTooManyRequests, grpccodes.ResourceExhausted},
+		{b7serrors.ErrRateLimited, codes.TooManyRequests, http.StatusTooManyRequests, grpccodes.ResourceExhausted},
+		{b7serrors.ErrTooManyInFlight, codes.TooManyInFlight, http.StatusTooManyRequests, grpccodes.ResourceExhausted},
+		{errors.New("failure"), codes.Error, http.StatusInternalServerError, grpccodes.Internal},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.code, b7serrors.Code(test.err))
+		require.Equal(t, test.status, b7serrors.HTTPStatus(test.err))
+		require.Equal(t, test.grpc, b7serrors.GRPCCode(test.err))
+	}
+}
+
+func TestIsPublic(t *testing.T) {
+	require.True(t, b7serrors.IsPublic(fmt.Errorf("execution failed: %w", b7serrors.ErrExecutionNotEnoughNodes)))
+	require.True(t, b7serrors.IsPublic(b7serrors.ErrRateLimited))
+	require.False(t, b7serrors.IsPublic(b7serrors.ErrNotFound))
+	require.False(t, b7serrors.IsPublic(errors.New("failure")))
+	require.False(t, b7serrors.IsPublic(nil))
+}
+
+func TestParsePublic(t *testing.T) {
+	require.ErrorIs(t, b7serrors.ParsePublic("could not roll call peers: "+b7serrors.ErrRollCallTimeout.Error()), b7serrors.ErrRollCallTimeout)
+	require.ErrorIs(t, b7serrors.ParsePublic(b7serrors.ErrQuotaExceeded.Error()), b7serrors.ErrQuotaExceeded)
+	require.False(t, b7serrors.IsPublic(b7serrors.ParsePublic("failure")))
+}
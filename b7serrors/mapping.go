@@ -0,0 +1,41 @@
+package b7serrors
+
+import (
+	grpccodes "google.golang.org/grpc/codes"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+)
+
+var responseCodes = map[Category]codes.Code{
+	CategoryNone:            codes.OK,
+	CategoryInvalid:         codes.Invalid,
+	CategoryNotFound:        codes.NotFound,
+	CategoryNotPermitted:    codes.NotPermitted,
+	CategoryTimeout:         codes.Timeout,
+	CategoryRateLimited:     codes.TooManyRequests,
+	CategoryQuotaExceeded:   codes.QuotaExceeded,
+	CategoryTooManyInFlight: codes.TooManyInFlight,
+	CategoryUnavailable:     codes.NotAvailable,
+	CategoryNotSupported:    codes.NotSupported,
+	CategoryInternal:        codes.Error,
+}
+
+// Code returns the Blockless response code for the error.
+func Code(err error) codes.Code {
+	code, ok := responseCodes[CategoryOf(err)]
+	if !ok {
+		return codes.Error
+	}
+
+	return code
+}
+
+// HTTPStatus returns the HTTP status code for the error.
+func HTTPStatus(err error) int {
+	return Code(err).HTTPStatus()
+}
+
+// GRPCCode returns the gRPC status code for the error.
+func GRPCCode(err error) grpccodes.Code {
+	return Code(err).GRPCCode()
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/libp2p/go-libp2p/core/crypto"
 	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/keystore"
 )
 
 // LoadOrCreateKeys loads existing keys or creates new ones if not present
@@ -83,3 +85,18 @@ func LoadOrCreateKeys(privKeyFile string, outputDir string) (crypto.PrivKey, cry
 
 	return priv, pub, nil
 }
+
+// WriteEncryptedKey writes the private key encrypted with the passphrase, to be used with the encrypted file keystore.
+func WriteEncryptedKey(priv crypto.PrivKey, passphrase string, outputDir string) {
+
+	payload, err := keystore.Encrypt(priv, passphrase)
+	if err != nil {
+		log.Fatalf("Could not encrypt private key: %s", err)
+	}
+
+	encryptedKeyFile := filepath.Join(outputDir, encryptedKeyName)
+	err = os.WriteFile(encryptedKeyFile, payload, privKeyPermissions)
+	if err != nil {
+		log.Fatalf("Could not write encrypted private key to file: %s", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/pflag"
 )
@@ -14,19 +15,24 @@ const (
 	pubKeyTxtName      = "pubkey.txt"
 	identityName       = "identity"
 	peerIDFileName     = "peerid.txt"
+	encryptedKeyName   = "priv.enc"
 	privKeyPermissions = 0600
 	pubKeyPermissions  = 0644
 )
 
 func main() {
 	var (
-		flagOutputDir string
-		flagString    string
-		flagFile      string
-		flagPublicKey string
-		flagMessage   string
-		flagSignature string
-		flagPeerID    string
+		flagOutputDir  string
+		flagString     string
+		flagFile       string
+		flagPublicKey  string
+		flagMessage    string
+		flagSignature  string
+		flagPeerID     string
+		flagSubgroup   string
+		flagMember     string
+		flagValidity   time.Duration
+		flagPassphrase string
 	)
 
 	pflag.StringVar(&flagPeerID, "peerid", "", "PeerID for verification")
@@ -36,6 +42,10 @@ func main() {
 	pflag.StringVar(&flagPublicKey, "pubkey", "", "Base64 encoded public key for verification")
 	pflag.StringVar(&flagMessage, "message", "", "The original message to verify")
 	pflag.StringVar(&flagSignature, "signature", "", "Base64 encoded signature to verify")
+	pflag.StringVar(&flagSubgroup, "subgroup", "", "restricted subgroup to issue a membership credential for")
+	pflag.StringVar(&flagMember, "member", "", "PeerID of the worker admitted to the subgroup")
+	pflag.DurationVar(&flagValidity, "validity", 0, "how long the membership credential is valid for (0 for no expiry)")
+	pflag.StringVar(&flagPassphrase, "passphrase", "", "passphrase to write the private key encrypted with, for the encrypted file keystore")
 
 	pflag.Parse()
 
@@ -52,6 +62,10 @@ func main() {
 		log.Fatalf("Error loading or creating keys: %s", err)
 	}
 
+	if flagPassphrase != "" {
+		WriteEncryptedKey(priv, flagPassphrase, flagOutputDir)
+	}
+
 	if flagString != "" || flagFile != "" {
 		HandleSignAndVerify(priv, pub, flagString, flagFile, flagOutputDir)
 	}
@@ -63,4 +77,8 @@ func main() {
 	if flagPeerID != "" && flagMessage != "" && flagSignature != "" {
 		VerifyGivenSignatureWithPeerID(flagPeerID, flagMessage, flagSignature)
 	}
+
+	if flagSubgroup != "" && flagMember != "" {
+		IssueMembershipCredential(priv, flagSubgroup, flagMember, flagValidity, flagOutputDir)
+	}
 }
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+)
+
+const membershipCredentialName = "membership.json"
+
+// IssueMembershipCredential creates a credential admitting the member to the subgroup, signed with the key of the subgroup owner.
+func IssueMembershipCredential(priv crypto.PrivKey, subgroup string, member string, validity time.Duration, outputDir string) {
+
+	id, err := peer.Decode(member)
+	if err != nil {
+		log.Fatalf("Could not decode member peer ID: %s", err)
+	}
+
+	credential := blockless.MembershipCredential{
+		Subgroup: subgroup,
+		Member:   id,
+	}
+	if validity > 0 {
+		credential.Expires = time.Now().Add(validity).UTC()
+	}
+
+	err = credential.Sign(priv)
+	if err != nil {
+		log.Fatalf("Could not sign membership credential: %s", err)
+	}
+
+	payload, err := json.MarshalIndent(credential, "", "  ")
+	if err != nil {
+		log.Fatalf("Could not encode membership credential: %s", err)
+	}
+
+	credentialFile := filepath.Join(outputDir, membershipCredentialName)
+	err = os.WriteFile(credentialFile, payload, pubKeyPermissions)
+	if err != nil {
+		log.Fatalf("Could not write membership credential to file: %s", err)
+	}
+
+	fmt.Printf("Membership credential for %s in subgroup %s written to %s\n", member, subgroup, credentialFile)
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/rs/zerolog"
+	"github.com/spf13/pflag"
+
+	"github.com/blocklessnetwork/b7s/fstore"
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/node"
+	"github.com/blocklessnetwork/b7s/node/head/simulation"
+	"github.com/blocklessnetwork/b7s/store"
+	"github.com/blocklessnetwork/b7s/store/codec"
+)
+
+const (
+	success = 0
+	failure = 1
+
+	address = "127.0.0.1"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+func run() int {
+
+	var (
+		flagWorkers         uint
+		flagRequests        uint
+		flagConcurrency     uint
+		flagNodeCount       int
+		flagFunctionID      string
+		flagRollCallTimeout time.Duration
+		flagSampling        uint
+		flagSamplingFactor  float64
+		flagStartupDelay    time.Duration
+		flagLogLevel        string
+		flagJSON            bool
+		flagSeed            int64
+
+		profile simulation.Profile
+	)
+
+	pflag.UintVar(&flagWorkers, "workers", 10, "number of synthetic workers")
+	pflag.UintVar(&flagRequests, "requests", 100, "number of execution requests")
+	pflag.UintVar(&flagConcurrency, "concurrency", 10, "number of execution requests running at the same time")
+	pflag.IntVar(&flagNodeCount, "node-count", 1, "number of workers that should execute each request")
+	pflag.StringVar(&flagFunctionID, "function-id", "simulated-function", "function ID used for execution requests")
+	pflag.DurationVar(&flagRollCallTimeout, "roll-call-timeout", node.DefaultRollCallTimeout, "how long the head node waits for roll call responses")
+	pflag.UintVar(&flagSampling, "roll-call-sampling-threshold", 0, "number of peers on a topic above which roll calls are sampled (0 disables sampling)")
+	pflag.Float64Var(&flagSamplingFactor, "roll-call-sampling-factor", node.DefaultRollCallSamplingFactor, "how many times more workers than needed are asked to answer a sampled roll call")
+	pflag.DurationVar(&flagStartupDelay, "startup-delay", 2*time.Second, "how long to wait for workers to subscribe to topics before starting")
+	pflag.StringVar(&flagLogLevel, "log-level", "error", "log level for the head node and the workers")
+	pflag.BoolVar(&flagJSON, "json", false, "print the report as JSON")
+	pflag.Int64Var(&flagSeed, "seed", 0, "seed for random choices of the head node and the workers, for reproducible runs (0 picks a random seed)")
+
+	pflag.DurationVar(&profile.RollCallLatency.Min, "roll-call-latency-min", 0, "minimum time it takes a worker to report for a roll call")
+	pflag.DurationVar(&profile.RollCallLatency.Max, "roll-call-latency-max", 50*time.Millisecond, "maximum time it takes a worker to report for a roll call")
+	pflag.DurationVar(&profile.ExecutionLatency.Min, "execution-latency-min", 10*time.Millisecond, "minimum time it takes a worker to execute a request")
+	pflag.DurationVar(&profile.ExecutionLatency.Max, "execution-latency-max", 100*time.Millisecond, "maximum time it takes a worker to execute a request")
+	pflag.Float64Var(&profile.DeclineRate, "decline-rate", 0, "share of roll calls workers do not report for")
+	pflag.Float64Var(&profile.FailureRate, "failure-rate", 0, "share of executions that fail")
+
+	pflag.Parse()
+
+	level, err := zerolog.ParseLevel(flagLogLevel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not parse log level: %s\n", err)
+		return failure
+	}
+
+	log := zerolog.New(os.Stderr).With().Timestamp().Logger().Level(level)
+
+	// Always use a seed, so the run can be reproduced.
+	if flagSeed == 0 {
+		flagSeed = time.Now().UnixNano()
+	}
+	profile.Seed = flagSeed
+	fmt.Fprintf(os.Stderr, "random seed: %v\n", flagSeed)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	// Create the head node, with an in-memory database.
+	db, err := pebble.Open("", &pebble.Options{FS: vfs.NewMem()})
+	if err != nil {
+		log.Error().Err(err).Msg("could not open database")
+		return failure
+	}
+	defer db.Close()
+
+	workdir, err := os.MkdirTemp("", "b7s-loadtest-*")
+	if err != nil {
+		log.Error().Err(err).Msg("could not create workspace")
+		return failure
+	}
+	defer os.RemoveAll(workdir)
+
+	headHost, err := host.New(log, address, 0)
+	if err != nil {
+		log.Error().Err(err).Msg("could not create head node host")
+		return failure
+	}
+	defer headHost.Close()
+
+	var (
+		nodeStore = store.New(db, codec.NewJSONCodec())
+		fstore    = fstore.New(log, nodeStore, workdir)
+	)
+
+	head, err := node.New(log, headHost, nodeStore, fstore,
+		node.WithRole(blockless.HeadNode),
+		node.WithRollCallTimeout(flagRollCallTimeout),
+		node.WithRollCallSampling(flagSampling, flagSamplingFactor),
+		node.WithRandomSeed(flagSeed),
+	)
+	if err != nil {
+		log.Error().Err(err).Msg("could not create head node")
+		return failure
+	}
+
+	// Create synthetic workers and connect them to the head node.
+	fleet, err := simulation.NewFleet(log, flagWorkers, address, profile, []string{node.DefaultTopic})
+	if err != nil {
+		log.Error().Err(err).Msg("could not create synthetic workers")
+		return failure
+	}
+	defer fleet.Close()
+
+	err = fleet.Connect(ctx, peer.AddrInfo{ID: headHost.ID(), Addrs: headHost.Addrs()})
+	if err != nil {
+		log.Error().Err(err).Msg("could not connect synthetic workers to head node")
+		return failure
+	}
+
+	go func() {
+		err := head.Run(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("head node failed")
+			cancel()
+		}
+	}()
+
+	go func() {
+		err := fleet.Run(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("synthetic workers failed")
+			cancel()
+		}
+	}()
+
+	// Give pubsub time to disseminate topic subscriptions.
+	select {
+	case <-time.After(flagStartupDelay):
+	case <-ctx.Done():
+		return failure
+	}
+
+	bench := simulation.Benchmark{
+		Request: execute.Request{
+			FunctionID: flagFunctionID,
+			Method:     "simulated.wasm",
+			Config: execute.Config{
+				NodeCount: flagNodeCount,
+			},
+		},
+		Requests:    flagRequests,
+		Concurrency: flagConcurrency,
+	}
+
+	report := simulation.Run(ctx, head, bench)
+
+	if flagJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+		if err != nil {
+			log.Error().Err(err).Msg("could not encode report")
+			return failure
+		}
+
+		return success
+	}
+
+	printReport(os.Stdout, report)
+
+	return success
+}
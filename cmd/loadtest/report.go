@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/blocklessnetwork/b7s/node/head/simulation"
+)
+
+func printReport(out io.Writer, report simulation.Report) {
+
+	fmt.Fprintf(out, "requests:   %v\n", report.Requests)
+	fmt.Fprintf(out, "errors:     %v\n", report.Errors)
+	fmt.Fprintf(out, "duration:   %v\n", report.Duration.Round(time.Millisecond))
+	fmt.Fprintf(out, "throughput: %.2f requests/s\n", report.Throughput)
+
+	fmt.Fprintf(out, "codes:\n")
+	for _, code := range slices.Sorted(maps.Keys(report.Codes)) {
+		fmt.Fprintf(out, "  %v: %v\n", code, report.Codes[code])
+	}
+
+	fmt.Fprintln(out)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "phase\tmean\tp50\tp95\tp99\tmax")
+
+	phases := []struct {
+		name      string
+		durations simulation.Durations
+	}{
+		{name: "total", durations: report.Total},
+		{name: "roll call", durations: report.RollCall},
+		{name: "result gather", durations: report.ResultGather},
+	}
+
+	for _, phase := range phases {
+		d := phase.durations
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\n",
+			phase.name,
+			d.Mean.Round(time.Millisecond),
+			d.P50.Round(time.Millisecond),
+			d.P95.Round(time.Millisecond),
+			d.P99.Round(time.Millisecond),
+			d.Max.Round(time.Millisecond),
+		)
+	}
+
+	w.Flush()
+}
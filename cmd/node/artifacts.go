@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/blocklessnetwork/b7s/artifact"
+	"github.com/blocklessnetwork/b7s/config"
+	"github.com/blocklessnetwork/b7s/executor"
+)
+
+// Supported blob storage for execution artifacts.
+const (
+	artifactStoreS3   = "s3"
+	artifactStoreIPFS = "ipfs"
+)
+
+// createArtifactStore creates the blob storage workers upload output files of executions to.
+func createArtifactStore(cfg config.ArtifactStorage) (executor.ArtifactStore, error) {
+
+	switch cfg.Store {
+	case artifactStoreS3:
+		return artifact.NewS3(artifact.S3Config{
+			Endpoint:  cfg.S3.Endpoint,
+			Region:    cfg.S3.Region,
+			Bucket:    cfg.S3.Bucket,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+			PublicURL: cfg.S3.PublicURL,
+		})
+
+	case artifactStoreIPFS:
+		return artifact.NewIPFS(cfg.IPFS.API, cfg.IPFS.Gateway)
+
+	default:
+		return nil, fmt.Errorf("unsupported artifact store: %s", cfg.Store)
+	}
+}
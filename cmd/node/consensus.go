@@ -0,0 +1,17 @@
+//go:build !noconsensus
+
+package main
+
+import (
+	"slices"
+
+	"github.com/blocklessnetwork/b7s/consensus/hotstuff"
+	"github.com/blocklessnetwork/b7s/consensus/pbft"
+	"github.com/blocklessnetwork/b7s/consensus/raft"
+)
+
+var consensusSummaries = slices.Concat(
+	hotstuff.Summaries,
+	pbft.Summaries,
+	raft.Summaries,
+)
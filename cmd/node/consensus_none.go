@@ -0,0 +1,10 @@
+//go:build noconsensus
+
+package main
+
+import (
+	mp "github.com/armon/go-metrics/prometheus"
+)
+
+// Node built without consensus algorithms has no consensus metrics.
+var consensusSummaries []mp.SummaryDefinition
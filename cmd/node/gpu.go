@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/blocklessnetwork/b7s/config"
+	"github.com/blocklessnetwork/b7s/executor/gpu"
+)
+
+// detectGPUs returns the GPUs the worker should use - the detected devices, limited to the configured ones if any.
+func detectGPUs(ctx context.Context, cfg config.GPU) ([]gpu.Device, error) {
+
+	detected, err := gpu.Detect(ctx, cfg.CLI)
+	if err != nil {
+		return nil, fmt.Errorf("could not detect GPUs: %w", err)
+	}
+
+	if len(cfg.Devices) == 0 {
+		return detected, nil
+	}
+
+	devices := make([]gpu.Device, 0, len(cfg.Devices))
+	for _, index := range cfg.Devices {
+
+		n := slices.IndexFunc(detected, func(device gpu.Device) bool {
+			return device.Index == index
+		})
+		if n < 0 {
+			return nil, fmt.Errorf("configured GPU device not found (index: %s)", index)
+		}
+
+		devices = append(devices, detected[n])
+	}
+
+	return devices, nil
+}
@@ -2,15 +2,18 @@ package main
 
 import (
 	"fmt"
+	"slices"
 
+	"github.com/libp2p/go-libp2p/core/crypto"
 	"github.com/rs/zerolog"
 
 	"github.com/blocklessnetwork/b7s/config"
 	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/host/gater"
 	"github.com/blocklessnetwork/b7s/models/blockless"
 )
 
-func createHost(log zerolog.Logger, cfg config.Config, role blockless.NodeRole, dialbackPeers ...blockless.Peer) (*host.Host, error) {
+func createHost(log zerolog.Logger, cfg config.Config, role blockless.NodeRole, identity crypto.PrivKey, dialbackPeers ...blockless.Peer) (*host.Host, error) {
 
 	// Get the list of boot nodes addresses.
 	bootNodes, err := getBootNodeAddresses(cfg.BootNodes)
@@ -26,13 +29,38 @@ func createHost(log zerolog.Logger, cfg config.Config, role blockless.NodeRole,
 		host.WithDialBackWebsocketPort(cfg.Connectivity.WebsocketDialbackPort),
 		host.WithWebsocket(cfg.Connectivity.Websocket),
 		host.WithWebsocketPort(cfg.Connectivity.WebsocketPort),
+		host.WithTCP(!cfg.Connectivity.DisableTCP),
+		host.WithQUIC(cfg.Connectivity.QUIC),
+		host.WithQUICPort(cfg.Connectivity.QUICPort),
+		host.WithWebTransport(cfg.Connectivity.Webtransport),
+		host.WithWebTransportPort(cfg.Connectivity.WebtransportPort),
+		host.WithListenAddresses(cfg.Connectivity.ListenAddresses),
+		host.WithAnnounceAddresses(cfg.Connectivity.AnnounceAddresses),
 		host.WithDialBackPeers(dialbackPeers),
 		host.WithMustReachBootNodes(cfg.Connectivity.MustReachBootNodes),
 		host.WithDisabledResourceLimits(cfg.Connectivity.DisableConnectionLimits),
 		host.WithEnableP2PRelay(role == blockless.HeadNode),
 		host.WithConnectionLimit(cfg.Connectivity.ConnectionCount),
+		host.WithMaxMessageSize(cfg.Connectivity.MaxMessageSize),
+		host.WithGossipMeshSize(cfg.Connectivity.GossipMeshSize),
 	}
 
+	if identity != nil {
+		opts = append(opts, host.WithIdentity(identity))
+	}
+
+	gater, err := createConnectionGater(log, cfg.Connectivity.Gater)
+	if err != nil {
+		return nil, fmt.Errorf("could not create connection gater: %w", err)
+	}
+
+	if gater != nil {
+		opts = append(opts, host.WithConnectionGater(gater))
+	}
+
+	// Zero disables keepalive pings.
+	opts = append(opts, host.WithKeepaliveInterval(cfg.Connectivity.KeepaliveInterval))
+
 	// Create libp2p host.
 	host, err := host.New(log, cfg.Connectivity.Address, cfg.Connectivity.Port, opts...)
 	if err != nil {
@@ -41,3 +69,55 @@ func createHost(log zerolog.Logger, cfg config.Config, role blockless.NodeRole,
 
 	return host, nil
 }
+
+// createDataHost creates a libp2p host used for bulk data transfers. It shares the identity of the main host.
+func createDataHost(log zerolog.Logger, cfg config.Config, main *host.Host) (*host.Host, error) {
+
+	address := cfg.Connectivity.DataAddress
+	if address == "" {
+		address = cfg.Connectivity.Address
+	}
+
+	opts := []func(*host.Config){
+		host.WithIdentity(main.PrivateKey()),
+		host.WithConnectionGater(main.ConnectionGater()),
+		host.WithDisabledResourceLimits(cfg.Connectivity.DisableConnectionLimits),
+	}
+
+	host, err := host.New(log, address, cfg.Connectivity.DataPort, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("could not create data host: %w", err)
+	}
+
+	return host, nil
+}
+
+// createConnectionGater creates the connection gater for the configured policy. If there are no rules, the gater is nil.
+func createConnectionGater(log zerolog.Logger, cfg config.ConnectionGater) (*gater.Gater, error) {
+
+	rules := slices.Concat(cfg.AllowedNetworks, cfg.DeniedNetworks, cfg.AllowedASNs, cfg.DeniedASNs, cfg.AllowedCountries, cfg.DeniedCountries)
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	opts := []gater.Option{
+		gater.WithAllowedNetworks(cfg.AllowedNetworks),
+		gater.WithDeniedNetworks(cfg.DeniedNetworks),
+		gater.WithAllowedASNs(cfg.AllowedASNs),
+		gater.WithDeniedASNs(cfg.DeniedASNs),
+		gater.WithAllowedCountries(cfg.AllowedCountries),
+		gater.WithDeniedCountries(cfg.DeniedCountries),
+		gater.WithRelayedConnections(cfg.AllowRelayed),
+	}
+
+	if cfg.Database != "" {
+		db, err := gater.LoadDatabase(cfg.Database)
+		if err != nil {
+			return nil, fmt.Errorf("could not load IP to ASN database: %w", err)
+		}
+
+		opts = append(opts, gater.WithDatabase(db))
+	}
+
+	return gater.New(log, opts...)
+}
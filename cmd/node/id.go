@@ -1,39 +1,62 @@
 package main
 
 import (
+	"errors"
 	"fmt"
-	"os"
 
 	"github.com/libp2p/go-libp2p/core/crypto"
-	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/config"
+	"github.com/blocklessnetwork/b7s/keystore"
 )
 
-func peerIDFromKey(keyPath string) (string, error) {
+// loadIdentity returns the private key of the node from the configured keystore.
+// If no keystore or private key is configured, the key is nil and the node uses a random identity.
+func loadIdentity(cfg config.Config) (crypto.PrivKey, error) {
 
-	key, err := readPrivateKey(keyPath)
+	store, err := createKeystore(cfg.Connectivity)
 	if err != nil {
-		return "", fmt.Errorf("could not read key file: %w", err)
+		return nil, fmt.Errorf("could not create keystore: %w", err)
+	}
+
+	if store == nil {
+		return nil, nil
 	}
 
-	id, err := peer.IDFromPrivateKey(key)
+	key, err := store.PrivateKey()
 	if err != nil {
-		return "", fmt.Errorf("could not determine identity: %w", err)
+		return nil, fmt.Errorf("could not load private key: %w", err)
 	}
 
-	return id.String(), nil
+	return key, nil
 }
 
-func readPrivateKey(filepath string) (crypto.PrivKey, error) {
+func createKeystore(cfg config.Connectivity) (keystore.Keystore, error) {
 
-	payload, err := os.ReadFile(filepath)
-	if err != nil {
-		return nil, fmt.Errorf("could not read file: %w", err)
-	}
+	switch cfg.Keystore.Type {
 
-	key, err := crypto.UnmarshalPrivateKey(payload)
-	if err != nil {
-		return nil, fmt.Errorf("could not unmarshal private key: %w", err)
-	}
+	case "", keystore.TypeFile:
+		if cfg.PrivateKey == "" {
+			return nil, nil
+		}
 
-	return key, nil
+		return keystore.NewFile(cfg.PrivateKey), nil
+
+	case keystore.TypeEncryptedFile:
+		if cfg.PrivateKey == "" {
+			return nil, errors.New("private key path is required for the encrypted file keystore")
+		}
+
+		return keystore.NewEncryptedFile(cfg.PrivateKey, cfg.Keystore.Passphrase)
+
+	case keystore.TypeKeychain:
+		return keystore.NewKeychain(cfg.Keystore.Keychain.Service, cfg.Keystore.Keychain.Account)
+
+	case keystore.TypePKCS11:
+		hsm := cfg.Keystore.PKCS11
+		return keystore.NewPKCS11(hsm.Tool, hsm.Module, hsm.KeyID, hsm.PIN)
+
+	default:
+		return nil, fmt.Errorf("unsupported keystore type: %s", cfg.Keystore.Type)
+	}
 }
@@ -9,21 +9,39 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
 	"github.com/labstack/echo-contrib/echoprometheus"
 	"github.com/labstack/echo/v4"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/rs/zerolog"
+	"github.com/spf13/afero"
 	"github.com/ziflex/lecho/v3"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
 
 	"github.com/blocklessnetwork/b7s/api"
 	"github.com/blocklessnetwork/b7s/config"
 	"github.com/blocklessnetwork/b7s/executor"
+	"github.com/blocklessnetwork/b7s/executor/container"
+	"github.com/blocklessnetwork/b7s/executor/gpu"
 	"github.com/blocklessnetwork/b7s/executor/limits"
+	"github.com/blocklessnetwork/b7s/executor/synthetic"
 	"github.com/blocklessnetwork/b7s/fstore"
 	"github.com/blocklessnetwork/b7s/models/blockless"
 	"github.com/blocklessnetwork/b7s/node"
+	"github.com/blocklessnetwork/b7s/node/aggregate"
+	"github.com/blocklessnetwork/b7s/node/head/anomaly"
+	"github.com/blocklessnetwork/b7s/node/head/archive"
+	"github.com/blocklessnetwork/b7s/node/head/backup"
+	"github.com/blocklessnetwork/b7s/node/head/deferred"
+	"github.com/blocklessnetwork/b7s/node/head/journal"
+	"github.com/blocklessnetwork/b7s/node/head/quota"
+	"github.com/blocklessnetwork/b7s/node/head/schedule"
+	"github.com/blocklessnetwork/b7s/node/migration"
 	"github.com/blocklessnetwork/b7s/store"
 	"github.com/blocklessnetwork/b7s/store/codec"
 	"github.com/blocklessnetwork/b7s/store/traceable"
@@ -32,6 +50,11 @@ import (
 
 const (
 	defaultLogLevel = zerolog.DebugLevel
+
+	defaultModuleCacheDirName = "modules"
+	defaultInputCacheDirName  = "inputs"
+
+	storeMetricsInterval = 30 * time.Second // How often do we report the state of the database.
 )
 
 var (
@@ -75,6 +98,14 @@ func run() int {
 		serverAddress = cmp.Or(cfg.Head.RestAPI, cfg.Telemetry.Metrics.PrometheusAddress)
 	)
 
+	if cfg.Profile != "" {
+		log.Info().Str("profile", cfg.Profile).Msg("using configuration profile")
+	}
+
+	if cfg.Profile == config.ProfileConstrained && nodeRole == blockless.HeadNode {
+		log.Warn().Msg("constrained profile is meant for worker nodes")
+	}
+
 	// Create the main context.
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -89,13 +120,45 @@ func run() int {
 		server = createEchoServer(log)
 	}
 
+	err = checkMigration(*cfg)
+	if err != nil {
+		log.Error().Err(err).Msg("node state migration not supported")
+		return failure
+	}
+
+	// Load the state exported by the worker on different hardware. Imported identity decides the node directory used.
+	var imported *migration.State
+	if cfg.Worker.ImportState != "" {
+
+		state, err := migration.Read(afero.NewOsFs(), cfg.Worker.ImportState)
+		if err != nil {
+			log.Error().Err(err).Str("path", cfg.Worker.ImportState).Msg("could not read node state")
+			return failure
+		}
+
+		err = importIdentity(cfg, state)
+		if err != nil {
+			log.Error().Err(err).Msg("could not import node identity")
+			return failure
+		}
+
+		imported = &state
+	}
+
+	identity, err := loadIdentity(*cfg)
+	if err != nil {
+		log.Error().Err(err).Str("keystore", cfg.Connectivity.Keystore.Type).Str("key", cfg.Connectivity.PrivateKey).Msg("could not load node identity")
+		return failure
+	}
+
 	// TODO: Change how node starts up with regards to key/no-key.
-	if cfg.Connectivity.PrivateKey != "" {
-		nodeID, err = peerIDFromKey(cfg.Connectivity.PrivateKey)
+	if identity != nil {
+		id, err := peer.IDFromPrivateKey(identity)
 		if err != nil {
-			log.Error().Err(err).Str("key", cfg.Connectivity.PrivateKey).Msg("could not read private key")
+			log.Error().Err(err).Msg("could not determine node identity")
 			return failure
 		}
+		nodeID = id.String()
 	}
 
 	if cfg.Telemetry.Tracing.Enable {
@@ -173,16 +236,68 @@ func run() int {
 	}
 	cfg.Workspace = workspace
 
+	// Recreate the head node database from a backup, if requested.
+	if nodeRole == blockless.HeadNode && cfg.Head.RestoreSnapshot != "" {
+
+		if cfg.Head.BackupDir == "" {
+			log.Error().Msg("backup directory is required to restore a snapshot")
+			return failure
+		}
+
+		objects := archive.NewDirStore(afero.NewOsFs(), cfg.Head.BackupDir)
+		snapshot, err := backup.Restore(objects, cfg.Head.RestoreSnapshot, vfs.Default, cfg.DB)
+		switch {
+		// Existing database is never overwritten, so the snapshot can be left in the config after the restore.
+		case errors.Is(err, backup.ErrDatabaseExists):
+			log.Warn().Str("snapshot", cfg.Head.RestoreSnapshot).Str("db", cfg.DB).Msg("database already exists, skipping restore")
+
+		case err != nil:
+			log.Error().Err(err).Str("snapshot", cfg.Head.RestoreSnapshot).Msg("could not restore database from snapshot")
+			return failure
+
+		default:
+			log.Info().Str("snapshot", snapshot.Name).Time("created", snapshot.Created).Str("db", cfg.DB).Msg("restored database from snapshot")
+		}
+	}
+
 	// Open the pebble peer database.
-	db, err := pebble.Open(cfg.DB, &pebble.Options{Logger: &pebbleNoopLogger{}})
+	dbOptions := pebbleOptions(cfg.Profile)
+	if dbOptions.Cache != nil {
+		defer dbOptions.Cache.Unref()
+	}
+
+	db, err := pebble.Open(cfg.DB, dbOptions)
 	if err != nil {
 		log.Error().Err(err).Str("db", cfg.DB).Msg("could not open pebble database")
 		return failure
 	}
 	defer db.Close()
 
+	if cfg.Telemetry.Metrics.Enable {
+		go store.MonitorMetrics(ctx, db, storeMetricsInterval)
+	}
+
 	// Create a new store.
-	store := traceable.New(store.New(db, codec.NewJSONCodec()))
+	var storeOpts []store.Option
+	if cfg.DBTimeout > 0 {
+		storeOpts = append(storeOpts, store.WithTimeout(cfg.DBTimeout))
+	}
+	store := traceable.New(store.New(db, codec.NewJSONCodec(), storeOpts...))
+
+	// Restore peers and functions from the imported state before the host dials back known peers.
+	if imported != nil {
+		err = migration.Restore(ctx, store, *imported)
+		if err != nil {
+			log.Error().Err(err).Msg("could not restore node state")
+			return failure
+		}
+
+		log.Info().
+			Time("created", imported.Created).
+			Int("peers", len(imported.Peers)).
+			Int("functions", len(imported.Functions)).
+			Msg("imported node state")
+	}
 
 	// Create host.
 	var dialbackPeers []blockless.Peer
@@ -194,7 +309,7 @@ func run() int {
 		}
 	}
 
-	host, err := createHost(log.With().Str("component", "host").Logger(), *cfg, nodeRole, dialbackPeers...)
+	host, err := createHost(log.With().Str("component", "host").Logger(), *cfg, nodeRole, identity, dialbackPeers...)
 	if err != nil {
 		log.Error().Err(err).Msg("could not create host")
 		return failure
@@ -214,52 +329,484 @@ func run() int {
 		node.WithAttributeLoading(cfg.LoadAttributes),
 	}
 
+	if cfg.QueueSize > 0 {
+		opts = append(opts, node.WithQueueSize(cfg.QueueSize))
+	}
+
+	if cfg.Telemetry.Metrics.SlowHandlerThreshold > 0 {
+		opts = append(opts, node.WithSlowHandlerThreshold(cfg.Telemetry.Metrics.SlowHandlerThreshold))
+	}
+
+	// Create a separate host for execution results, if configured.
+	if cfg.Connectivity.DataPort > 0 {
+
+		dataHost, err := createDataHost(log.With().Str("component", "data-host").Logger(), *cfg, host)
+		if err != nil {
+			log.Error().Err(err).Msg("could not create data host")
+			return failure
+		}
+		defer dataHost.Close()
+
+		log.Info().
+			Strs("addresses", dataHost.Addresses()).
+			Msg("created data host")
+
+		opts = append(opts, node.WithDataHost(dataHost))
+	}
+
+	// Detect GPUs the worker makes available to executions requiring them.
+	var gpus []gpu.Device
+	if nodeRole == blockless.WorkerNode && cfg.Worker.GPU.Enable {
+		gpus, err = detectGPUs(ctx, cfg.Worker.GPU)
+		if err != nil {
+			log.Error().Err(err).Msg("could not detect GPUs")
+			return failure
+		}
+
+		log.Info().Interface("gpus", gpus).Msg("using GPUs")
+
+		opts = append(opts, node.WithGPUs(gpus))
+	}
+
 	// If this is a worker node, initialize an executor.
 	if nodeRole == blockless.WorkerNode {
 
-		// Executor options.
-		execOptions := []executor.Option{
-			executor.WithWorkDir(cfg.Workspace),
-			executor.WithRuntimeDir(cfg.Worker.RuntimePath),
-			executor.WithExecutableName(cfg.Worker.RuntimeCLI),
-		}
+		// Use a synthetic executor instead of the Blockless Runtime, if configured.
+		if cfg.Worker.Synthetic.Enable {
 
-		if needLimiter(cfg) {
-			limiter, err := limits.New(limits.WithCPUPercentage(cfg.Worker.CPUPercentageLimit), limits.WithMemoryKB(cfg.Worker.MemoryLimitKB))
+			executor, err := synthetic.New(log.With().Str("component", "executor").Logger(),
+				synthetic.WithLatency(time.Duration(cfg.Worker.Synthetic.LatencyMS)*time.Millisecond),
+				synthetic.WithOutputSize(cfg.Worker.Synthetic.OutputSize),
+				synthetic.WithFailureRate(cfg.Worker.Synthetic.FailureRate),
+				synthetic.WithSeed(cfg.Worker.Synthetic.Seed),
+			)
 			if err != nil {
-				log.Error().Err(err).Msg("could not create resource limiter")
+				log.Error().Err(err).Msg("could not create synthetic executor")
 				return failure
 			}
 
+			log.Warn().Msg("using synthetic executor, functions will not be executed")
+
+			opts = append(opts, node.WithExecutor(executor))
+		} else {
+			// Executor options.
+			execOptions := []executor.Option{
+				executor.WithWorkDir(cfg.Workspace),
+				executor.WithRuntimeDir(cfg.Worker.RuntimePath),
+				executor.WithExecutableName(cfg.Worker.RuntimeCLI),
+				executor.WithGPUDevices(gpu.Indexes(gpus)),
+			}
+
+			if needLimiter(cfg) {
+				limiter, err := limits.New(limits.WithCPUPercentage(cfg.Worker.CPUPercentageLimit), limits.WithMemoryKB(cfg.Worker.MemoryLimitKB))
+				if err != nil {
+					log.Error().Err(err).Msg("could not create resource limiter")
+					return failure
+				}
+
+				defer func() {
+					err = limiter.Shutdown()
+					if err != nil {
+						log.Error().Err(err).Msg("could not shutdown resource limiter")
+					}
+				}()
+
+				execOptions = append(execOptions, executor.WithLimiter(limiter))
+			}
+
+			if cfg.Worker.ModuleCache {
+				dir := filepath.Join(cfg.Workspace, defaultModuleCacheDirName)
+				execOptions = append(execOptions, executor.WithModuleCache(dir, cfg.Worker.ModuleCacheSizeMB*1024*1024))
+
+				// Compiled modules are not part of the exported state - report the ones not copied over with the module cache.
+				if imported != nil {
+					missing, err := migration.MissingModules(afero.NewOsFs(), dir, imported.Modules)
+					if err != nil {
+						log.Error().Err(err).Msg("could not check module cache")
+						return failure
+					}
+
+					if len(missing) > 0 {
+						log.Warn().Int("missing", len(missing)).Int("modules", len(imported.Modules)).Msg("module cache entries missing, modules will be compiled again")
+					}
+				}
+			}
+
+			if cfg.Worker.Inputs.Cache {
+				dir := filepath.Join(cfg.Workspace, defaultInputCacheDirName)
+				ttl := cmp.Or(cfg.Worker.Inputs.CacheTTL, executor.DefaultInputCacheTTL)
+				execOptions = append(execOptions, executor.WithInputCache(dir, cfg.Worker.Inputs.CacheSizeMB*1024*1024, ttl))
+			}
+
+			if cfg.Worker.Inputs.MaxSizeMB > 0 {
+				execOptions = append(execOptions, executor.WithInputMaxSize(cfg.Worker.Inputs.MaxSizeMB*1024*1024))
+			}
+
+			if cfg.Worker.Inputs.IPFSGateway != "" {
+				execOptions = append(execOptions, executor.WithIPFSGateway(cfg.Worker.Inputs.IPFSGateway))
+			}
+
+			if cfg.Worker.ProcessReuse.MaxInvocations > 1 {
+				policy := executor.ReusePolicy{
+					MaxInvocations:  cfg.Worker.ProcessReuse.MaxInvocations,
+					MaxLifetime:     cfg.Worker.ProcessReuse.MaxLifetime,
+					MemoryCeilingKB: cfg.Worker.ProcessReuse.MemoryCeilingKB,
+				}
+				execOptions = append(execOptions, executor.WithProcessReuse(policy))
+			}
+
+			if cfg.Worker.WarmProcesses.Size > 0 {
+				policy := executor.WarmPolicy{
+					Size:        cfg.Worker.WarmProcesses.Size,
+					Modules:     cfg.Worker.WarmProcesses.Modules,
+					IdleTimeout: cfg.Worker.WarmProcesses.IdleTimeout,
+				}
+				execOptions = append(execOptions, executor.WithWarmPool(policy))
+			}
+
+			if cfg.Worker.Artifacts.Store != "" {
+				store, err := createArtifactStore(cfg.Worker.Artifacts)
+				if err != nil {
+					log.Error().Err(err).Str("store", cfg.Worker.Artifacts.Store).Msg("could not create artifact store")
+					return failure
+				}
+
+				// All workers executing a request upload their files under the same request ID, so keep them apart by the node ID.
+				execOptions = append(execOptions, executor.WithArtifactStore(store), executor.WithNodeID(host.ID().String()))
+
+				if cfg.Worker.Artifacts.MaxSizeMB > 0 {
+					execOptions = append(execOptions, executor.WithArtifactMaxSize(cfg.Worker.Artifacts.MaxSizeMB*1024*1024))
+				}
+			}
+
+			// Executors the worker switches to at runtime use the same options, apart from the runtime.
+			var (
+				swappedLock sync.Mutex
+				swapped     []*executor.Executor
+			)
+			defer func() {
+				swappedLock.Lock()
+				defer swappedLock.Unlock()
+
+				for _, e := range swapped {
+					err := e.Shutdown()
+					if err != nil {
+						log.Error().Err(err).Msg("could not shutdown executor")
+					}
+				}
+			}()
+
+			opts = append(opts, node.WithExecutorFactory(func(runtimePath string, runtimeCLI string) (blockless.Executor, error) {
+
+				swapOptions := append(slices.Clone(execOptions), executor.WithRuntimeDir(runtimePath))
+				if runtimeCLI != "" {
+					swapOptions = append(swapOptions, executor.WithExecutableName(runtimeCLI))
+				}
+
+				e, err := executor.New(log.With().Str("component", "executor").Logger(), swapOptions...)
+				if err != nil {
+					return nil, err
+				}
+
+				swappedLock.Lock()
+				defer swappedLock.Unlock()
+				swapped = append(swapped, e)
+
+				return e, nil
+			}))
+
+			// Create an executor.
+			executor, err := executor.New(log.With().Str("component", "executor").Logger(), execOptions...)
+			if err != nil {
+				log.Error().
+					Err(err).
+					Str("workspace", cfg.Workspace).
+					Str("runtime_path", cfg.Worker.RuntimePath).
+					Str("runtime_cli", cfg.Worker.RuntimeCLI).
+					Msg("could not create an executor")
+				return failure
+			}
 			defer func() {
-				err = limiter.Shutdown()
+				err := executor.Shutdown()
 				if err != nil {
-					log.Error().Err(err).Msg("could not shutdown resource limiter")
+					log.Error().Err(err).Msg("could not shutdown executor")
 				}
 			}()
 
-			execOptions = append(execOptions, executor.WithLimiter(limiter))
+			opts = append(opts, node.WithExecutor(executor))
 		}
+		opts = append(opts, node.WithWorkspace(cfg.Workspace))
 
-		// Create an executor.
-		executor, err := executor.New(log.With().Str("component", "executor").Logger(), execOptions...)
-		if err != nil {
-			log.Error().
-				Err(err).
-				Str("workspace", cfg.Workspace).
-				Str("runtime_path", cfg.Worker.RuntimePath).
-				Str("runtime_cli", cfg.Worker.RuntimeCLI).
-				Msg("could not create an executor")
-			return failure
+		if len(cfg.Worker.MaintenanceWindows) > 0 {
+			windows := make([]node.MaintenanceWindow, 0, len(cfg.Worker.MaintenanceWindows))
+			for _, window := range cfg.Worker.MaintenanceWindows {
+				start, err := schedule.Parse(window.Start)
+				if err != nil {
+					log.Error().Err(err).Str("start", window.Start).Msg("invalid maintenance window")
+					return failure
+				}
+
+				windows = append(windows, node.MaintenanceWindow{
+					Start:    start,
+					Duration: window.Duration,
+				})
+			}
+
+			opts = append(opts, node.WithMaintenanceWindows(windows))
 		}
 
-		opts = append(opts, node.WithExecutor(executor))
-		opts = append(opts, node.WithWorkspace(cfg.Workspace))
+		if len(cfg.Worker.AdminPeers) > 0 {
+			admins, err := parseAdminPeers(cfg.Worker.AdminPeers)
+			if err != nil {
+				log.Error().Err(err).Msg("could not parse admin peers")
+				return failure
+			}
+
+			opts = append(opts, node.WithAdminPeers(admins))
+		}
+
+		if len(cfg.Worker.MembershipCredentials) > 0 {
+			credentials, err := loadMembershipCredentials(cfg.Worker.MembershipCredentials)
+			if err != nil {
+				log.Error().Err(err).Msg("could not load membership credentials")
+				return failure
+			}
+
+			opts = append(opts, node.WithMembershipCredentials(credentials))
+		}
+
+		if cfg.Worker.TenantConcurrency > 0 {
+			opts = append(opts, node.WithTenantConcurrency(cfg.Worker.TenantConcurrency, cfg.Worker.TenantQueueSize))
+		}
+
+		if len(cfg.Worker.AllowedFunctions) > 0 {
+			opts = append(opts, node.WithAllowedFunctions(cfg.Worker.AllowedFunctions))
+		}
+
+		if len(cfg.Worker.DeniedFunctions) > 0 {
+			opts = append(opts, node.WithDeniedFunctions(cfg.Worker.DeniedFunctions))
+		}
+
+		// Zero means the default interval is used, negative values disable fleet reports.
+		if cfg.Worker.FleetReportInterval != 0 {
+			opts = append(opts, node.WithFleetReportInterval(max(cfg.Worker.FleetReportInterval, 0)))
+		}
+
+		// Zero means the default deadline is used, negative values disable the cluster watchdog.
+		if cfg.Worker.ClusterDeadline != 0 {
+			opts = append(opts, node.WithClusterDeadline(max(cfg.Worker.ClusterDeadline, 0)))
+		}
+
+		if cfg.Worker.ResultCacheTTL > 0 {
+			opts = append(opts, node.WithResultCacheTTL(cfg.Worker.ResultCacheTTL))
+		}
+
+		if cfg.Worker.SelfTest.Enable {
+			opts = append(opts, node.WithSelfTest(node.SelfTest{
+				FunctionID:  cfg.Worker.SelfTest.FunctionID,
+				Method:      cfg.Worker.SelfTest.Method,
+				ManifestURL: cfg.Worker.SelfTest.ManifestURL,
+			}))
+		}
 	}
 
 	// Create function store.
 	fstore := fstore.New(log.With().Str("component", "fstore").Logger(), store, cfg.Workspace)
 
+	if nodeRole == blockless.WorkerNode && cfg.Worker.Container.Enable {
+
+		containerOpts := []container.Option{
+			container.WithMemoryLimit(cfg.Worker.Container.MemoryLimitKB),
+			container.WithCPULimit(cfg.Worker.Container.CPULimit),
+			container.WithGPUDevices(gpu.Indexes(gpus)),
+		}
+		if cfg.Worker.Container.CLI != "" {
+			containerOpts = append(containerOpts, container.WithCLI(cfg.Worker.Container.CLI))
+		}
+		if cfg.Worker.Container.Network != "" {
+			containerOpts = append(containerOpts, container.WithNetwork(cfg.Worker.Container.Network))
+		}
+		if cfg.Worker.Container.PidsLimit > 0 {
+			containerOpts = append(containerOpts, container.WithPidsLimit(cfg.Worker.Container.PidsLimit))
+		}
+
+		containerExecutor, err := container.New(log.With().Str("component", "container_executor").Logger(), fstore, containerOpts...)
+		if err != nil {
+			log.Error().Err(err).Msg("could not create container executor")
+			return failure
+		}
+
+		opts = append(opts, node.WithContainerExecutor(containerExecutor))
+	}
+
+	if cfg.Head.ClientConcurrency > 0 {
+		opts = append(opts, node.WithClientConcurrency(cfg.Head.ClientConcurrency, cfg.Head.ClientQueueSize))
+	}
+
+	if cfg.Head.DelegationThreshold > 0 || cfg.Head.DelegationMaxDepth > 0 {
+		depth := cfg.Head.DelegationMaxDepth
+		if depth == 0 {
+			depth = node.DefaultDelegationMaxDepth
+		}
+
+		opts = append(opts, node.WithDelegation(cfg.Head.DelegationThreshold, depth))
+	}
+
+	if cfg.Head.WorkerDispatchLimit > 0 {
+		opts = append(opts, node.WithWorkerDispatchLimit(cfg.Head.WorkerDispatchLimit))
+	}
+
+	if cfg.Head.Aggregation != "" {
+		aggregator, err := aggregate.Parse(cfg.Head.Aggregation)
+		if err != nil {
+			log.Error().Err(err).Str("aggregation", cfg.Head.Aggregation).Msg("invalid aggregation strategy")
+			return failure
+		}
+
+		opts = append(opts, node.WithAggregator(aggregator))
+	}
+
+	if cfg.Head.RollCallQueueSize > 0 {
+		opts = append(opts, node.WithRollCallQueueSize(cfg.Head.RollCallQueueSize))
+	}
+
+	if cfg.Head.RollCallOverflow != "" {
+		overflow, err := node.ParseRollCallOverflow(cfg.Head.RollCallOverflow)
+		if err != nil {
+			log.Error().Err(err).Str("overflow", cfg.Head.RollCallOverflow).Msg("invalid roll call overflow policy")
+			return failure
+		}
+
+		opts = append(opts, node.WithRollCallOverflow(overflow))
+	}
+
+	if cfg.Head.RollCallBlockTimeout > 0 {
+		opts = append(opts, node.WithRollCallBlockTimeout(cfg.Head.RollCallBlockTimeout))
+	}
+
+	if cfg.Head.RollCallSamplingThreshold > 0 {
+		factor := cfg.Head.RollCallSamplingFactor
+		if factor == 0 {
+			factor = node.DefaultRollCallSamplingFactor
+		}
+
+		opts = append(opts, node.WithRollCallSampling(cfg.Head.RollCallSamplingThreshold, factor))
+	}
+
+	if cfg.Head.RollCallFallback {
+		opts = append(opts, node.WithRollCallFallback(true))
+	}
+
+	if len(cfg.Head.RateLimits) > 0 {
+		limits := make([]node.RateLimit, 0, len(cfg.Head.RateLimits))
+		for _, limit := range cfg.Head.RateLimits {
+			limits = append(limits, node.RateLimit{
+				FunctionID: limit.Function,
+				Rate:       limit.Rate,
+				Burst:      limit.Burst,
+				PerPeer:    limit.PerPeer,
+			})
+		}
+		opts = append(opts, node.WithRateLimits(limits))
+	}
+
+	if len(cfg.Head.Canaries) > 0 {
+		canaries := make([]node.Canary, 0, len(cfg.Head.Canaries))
+		for _, canary := range cfg.Head.Canaries {
+			canaries = append(canaries, node.Canary{
+				FunctionID: canary.Function,
+				Method:     canary.Method,
+				Interval:   canary.Interval,
+				Sample:     canary.Sample,
+			})
+		}
+		opts = append(opts, node.WithCanaries(canaries))
+	}
+
+	if len(cfg.Head.WarmPools) > 0 {
+		pools := make([]node.WarmPool, 0, len(cfg.Head.WarmPools))
+		for _, pool := range cfg.Head.WarmPools {
+			pools = append(pools, node.WarmPool{
+				FunctionID: pool.Function,
+				Topic:      pool.Topic,
+				Size:       pool.Size,
+				Refresh:    pool.Refresh,
+			})
+		}
+		opts = append(opts, node.WithWarmPools(pools))
+	}
+
+	if len(cfg.Head.Shadows) > 0 {
+		shadows := make([]node.Shadow, 0, len(cfg.Head.Shadows))
+		for _, shadow := range cfg.Head.Shadows {
+			shadows = append(shadows, node.Shadow{
+				FunctionID:       shadow.Function,
+				Rate:             shadow.Rate,
+				Subgroup:         shadow.Subgroup,
+				TargetFunctionID: shadow.TargetFunction,
+			})
+		}
+		opts = append(opts, node.WithShadows(shadows))
+	}
+
+	if len(cfg.Head.RestrictedSubgroups) > 0 {
+		subgroups, err := parseRestrictedSubgroups(cfg.Head.RestrictedSubgroups)
+		if err != nil {
+			log.Error().Err(err).Msg("could not parse restricted subgroups")
+			return failure
+		}
+
+		opts = append(opts, node.WithRestrictedSubgroups(subgroups))
+	}
+
+	if cfg.Head.AnomalyThreshold > 0 {
+		opts = append(opts, node.WithAnomalyDetector(anomaly.NewDetector(cfg.Head.AnomalyThreshold)))
+	}
+
+	if cfg.Head.ScheduleResultTopic != "" {
+		opts = append(opts, node.WithScheduleResultTopic(cfg.Head.ScheduleResultTopic))
+	}
+
+	// Head node keeps track of pending execution requests and recurring executions, so they are not lost on restart.
+	if nodeRole == blockless.HeadNode {
+		opts = append(opts, node.WithRequestJournal(journal.New(db)))
+		opts = append(opts, node.WithScheduleStore(schedule.NewStore(db)))
+		opts = append(opts, node.WithDeferredStore(deferred.NewStore(db)))
+
+		if cfg.Head.ClientExecutionsPerHour > 0 || cfg.Head.ClientCPUSecondsPerDay > 0 {
+			limits := quota.Limits{
+				ExecutionsPerHour: cfg.Head.ClientExecutionsPerHour,
+				CPUTimePerDay:     time.Duration(cfg.Head.ClientCPUSecondsPerDay) * time.Second,
+			}
+			opts = append(opts, node.WithQuotaTracker(quota.NewTracker(db, limits)))
+		}
+
+		if cfg.Head.ResultArchive != "" {
+			retention := cmp.Or(cfg.Head.ResultRetention, node.DefaultResultRetention)
+			objects := archive.NewDirStore(afero.NewOsFs(), cfg.Head.ResultArchive)
+			opts = append(opts, node.WithResultArchive(archive.New(db, objects, retention)))
+		}
+
+		if cfg.Head.BackupDir != "" {
+			objects := archive.NewDirStore(afero.NewOsFs(), cfg.Head.BackupDir)
+			opts = append(opts, node.WithBackups(backup.NewManager(db, vfs.Default, cfg.DB, objects)))
+		}
+
+		if len(cfg.Head.AdminPeers) > 0 {
+			admins, err := parseAdminPeers(cfg.Head.AdminPeers)
+			if err != nil {
+				log.Error().Err(err).Msg("could not parse admin peers")
+				return failure
+			}
+
+			opts = append(opts, node.WithAdminPeers(admins))
+		}
+	}
+
+	if imported != nil {
+		opts = append(opts, node.WithImportedState(*imported))
+	}
+
 	// If we have topics specified, use those.
 	if len(cfg.Topics) > 0 {
 		opts = append(opts, node.WithTopics(cfg.Topics))
@@ -300,7 +847,12 @@ func run() int {
 		if nodeRole == blockless.HeadNode {
 
 			apiHandler := api.New(log.With().Str("component", "api").Logger(), node)
-			api.RegisterHandlers(server, apiHandler)
+
+			var router api.EchoRouter = server
+			if cfg.Head.ResponseCompression {
+				router = server.Group("", api.Compression(api.DefaultCompressionMinLength))
+			}
+			api.RegisterHandlers(router, apiHandler)
 		}
 
 		// Start server in a separate goroutine.
@@ -334,6 +886,16 @@ func run() int {
 		return failure
 	}
 
+	if cfg.Worker.ExportState != "" {
+		err = exportState(ctx, node, cfg)
+		if err != nil {
+			log.Error().Err(err).Str("path", cfg.Worker.ExportState).Msg("could not export node state")
+			return failure
+		}
+
+		log.Info().Str("path", cfg.Worker.ExportState).Msg("exported node state")
+	}
+
 	// If we receive a second interrupt signal, exit immediately.
 	go func() {
 		<-sig
@@ -358,7 +920,7 @@ func createEchoServer(log zerolog.Logger) *echo.Echo {
 }
 
 func needLimiter(cfg *config.Config) bool {
-	return (cfg.Worker.CPUPercentageLimit > 0 && cfg.Worker.CPUPercentageLimit < 1.0) || cfg.Worker.MemoryLimitKB > 0
+	return (cfg.Worker.CPUPercentageLimit > 0 && cfg.Worker.CPUPercentageLimit < 1.0) || cfg.Worker.MemoryLimitKB > 0 || cfg.Worker.ExecutionLimits
 }
 
 func updateDirPaths(root string, cfg *config.Config) {
@@ -5,12 +5,12 @@ import (
 
 	mp "github.com/armon/go-metrics/prometheus"
 
-	"github.com/blocklessnetwork/b7s/consensus/pbft"
-	"github.com/blocklessnetwork/b7s/consensus/raft"
 	"github.com/blocklessnetwork/b7s/executor"
 	"github.com/blocklessnetwork/b7s/fstore"
 	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/host/gater"
 	"github.com/blocklessnetwork/b7s/node"
+	"github.com/blocklessnetwork/b7s/store"
 )
 
 func metricCounters() []mp.CounterDefinition {
@@ -18,6 +18,7 @@ func metricCounters() []mp.CounterDefinition {
 	counters := slices.Concat(
 		node.Counters,
 		host.Counters,
+		gater.Counters,
 		fstore.Counters,
 		executor.Counters,
 	)
@@ -30,8 +31,9 @@ func metricSummaries() []mp.SummaryDefinition {
 	summaries := slices.Concat(
 		executor.Summaries,
 		fstore.Summaries,
-		pbft.Summaries,
-		raft.Summaries,
+		node.Summaries,
+		store.Summaries,
+		consensusSummaries,
 	)
 
 	return summaries
@@ -39,7 +41,10 @@ func metricSummaries() []mp.SummaryDefinition {
 
 func metricGauges() []mp.GaugeDefinition {
 
-	// Right now we have a single gauge - node info.
-	gauges := node.Gauges
+	gauges := slices.Concat(
+		node.Gauges,
+		store.Gauges,
+	)
+
 	return gauges
 }
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/spf13/afero"
+
+	"github.com/blocklessnetwork/b7s/config"
+	"github.com/blocklessnetwork/b7s/keystore"
+	"github.com/blocklessnetwork/b7s/node"
+	"github.com/blocklessnetwork/b7s/node/migration"
+)
+
+const (
+	importedKeyName = "priv.bin"
+
+	importedKeyPermissions = 0o600
+	nodeDirPermissions     = 0o750
+)
+
+// checkMigration verifies that the configured keystore supports the requested state export, before the node starts.
+func checkMigration(cfg config.Config) error {
+
+	// Keys kept in an HSM never leave the device, so the identity cannot be part of the exported state.
+	if cfg.Worker.ExportState != "" && cfg.Connectivity.Keystore.Type == keystore.TypePKCS11 {
+		return errors.New("node identity is kept in an HSM and cannot be exported")
+	}
+
+	return nil
+}
+
+// importIdentity makes the node use the identity from the imported state. If the node has no private key configured,
+// the key is written to the node directory and used from there, so the node keeps the identity on later runs too.
+// Only the plain file keystore can take over the imported key - other keystores must already hold the imported identity.
+func importIdentity(cfg *config.Config, state migration.State) error {
+
+	key, err := state.PrivateKey()
+	if err != nil {
+		return fmt.Errorf("could not read imported identity: %w", err)
+	}
+
+	id, err := peer.IDFromPrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("could not determine imported identity: %w", err)
+	}
+
+	fileKeystore := cfg.Connectivity.Keystore.Type == "" || cfg.Connectivity.Keystore.Type == keystore.TypeFile
+
+	configured, err := loadIdentity(*cfg)
+	if err != nil && !fileKeystore {
+		return fmt.Errorf("%s keystore must hold the imported identity (identity: %s): %w", cfg.Connectivity.Keystore.Type, id.String(), err)
+	}
+	if err != nil {
+		return fmt.Errorf("could not load configured identity: %w", err)
+	}
+
+	if configured != nil {
+		configuredID, err := peer.IDFromPrivateKey(configured)
+		if err != nil {
+			return fmt.Errorf("could not determine configured identity: %w", err)
+		}
+
+		if configuredID != id {
+			return fmt.Errorf("configured private key does not match the imported identity (configured: %s, imported: %s)", configuredID.String(), id.String())
+		}
+
+		return nil
+	}
+
+	if !fileKeystore {
+		return fmt.Errorf("%s keystore must hold the imported identity (identity: %s)", cfg.Connectivity.Keystore.Type, id.String())
+	}
+
+	dir := generateNodeDirName(id.String())
+	err = os.MkdirAll(dir, nodeDirPermissions)
+	if err != nil {
+		return fmt.Errorf("could not create node directory: %w", err)
+	}
+
+	path := filepath.Join(dir, importedKeyName)
+	err = os.WriteFile(path, state.Identity, importedKeyPermissions)
+	if err != nil {
+		return fmt.Errorf("could not write private key: %w", err)
+	}
+
+	cfg.Connectivity.PrivateKey = path
+
+	return nil
+}
+
+// exportState writes the state of the node to the file configured for export.
+func exportState(ctx context.Context, node *node.Node, cfg *config.Config) error {
+
+	state, err := node.ExportState(ctx)
+	if err != nil {
+		return fmt.Errorf("could not export node state: %w", err)
+	}
+
+	fs := afero.NewOsFs()
+
+	if cfg.Worker.ModuleCache {
+		state.Modules, err = migration.ModuleManifest(fs, filepath.Join(cfg.Workspace, defaultModuleCacheDirName))
+		if err != nil {
+			return fmt.Errorf("could not create module cache manifest: %w", err)
+		}
+	}
+
+	err = migration.Write(fs, cfg.Worker.ExportState, state)
+	if err != nil {
+		return fmt.Errorf("could not write node state: %w", err)
+	}
+
+	return nil
+}
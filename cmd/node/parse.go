@@ -1,8 +1,14 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
 	"strings"
 
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/config"
 	"github.com/blocklessnetwork/b7s/models/blockless"
 )
 
@@ -20,3 +26,53 @@ func parseNodeRole(role string) blockless.NodeRole {
 		panic("invalid node role specified")
 	}
 }
+
+func parseAdminPeers(ids []string) ([]peer.ID, error) {
+
+	admins := make([]peer.ID, 0, len(ids))
+	for _, id := range ids {
+		admin, err := peer.Decode(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid admin peer ID (peer: %s): %w", id, err)
+		}
+		admins = append(admins, admin)
+	}
+
+	return admins, nil
+}
+
+func loadMembershipCredentials(paths []string) ([]blockless.MembershipCredential, error) {
+
+	credentials := make([]blockless.MembershipCredential, 0, len(paths))
+	for _, path := range paths {
+
+		payload, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read membership credential (path: %s): %w", path, err)
+		}
+
+		var credential blockless.MembershipCredential
+		err = json.Unmarshal(payload, &credential)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode membership credential (path: %s): %w", path, err)
+		}
+
+		credentials = append(credentials, credential)
+	}
+
+	return credentials, nil
+}
+
+func parseRestrictedSubgroups(subgroups []config.RestrictedSubgroup) (map[string]peer.ID, error) {
+
+	restricted := make(map[string]peer.ID, len(subgroups))
+	for _, subgroup := range subgroups {
+		owner, err := peer.Decode(subgroup.Owner)
+		if err != nil {
+			return nil, fmt.Errorf("invalid subgroup owner (subgroup: %s, owner: %s): %w", subgroup.Subgroup, subgroup.Owner, err)
+		}
+		restricted[subgroup.Subgroup] = owner
+	}
+
+	return restricted, nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"github.com/cockroachdb/pebble"
+
+	"github.com/blocklessnetwork/b7s/config"
+)
+
+// Database tuning for constrained devices, typically running off SD cards or other flash storage.
+const (
+	constrainedDBCacheSize           = 4 << 20 // Smaller block cache.
+	constrainedDBMemTableSize        = 8 << 20 // Larger memtable means fewer, larger flushes.
+	constrainedDBBytesPerSync        = 1 << 20 // Sync less often.
+	constrainedDBL0CompactionTrigger = 8       // Tolerate more L0 files before compacting them.
+	constrainedDBL0StopWrites        = 24      // Must stay above the compaction threshold.
+)
+
+// pebbleOptions returns the database options for the given profile.
+// The caller should release the cache, if set, once the database is closed.
+func pebbleOptions(profile string) *pebble.Options {
+
+	opts := &pebble.Options{
+		Logger: &pebbleNoopLogger{},
+	}
+
+	if profile != config.ProfileConstrained {
+		return opts
+	}
+
+	// Less compaction work and fewer writes mean less wear of flash storage.
+	opts.Cache = pebble.NewCache(constrainedDBCacheSize)
+	opts.MemTableSize = constrainedDBMemTableSize
+	opts.BytesPerSync = constrainedDBBytesPerSync
+	opts.L0CompactionThreshold = constrainedDBL0CompactionTrigger
+	opts.L0StopWritesThreshold = constrainedDBL0StopWrites
+	opts.MaxConcurrentCompactions = func() int { return 1 }
+
+	return opts
+}
@@ -12,6 +12,8 @@ const (
 	DefaultConcurrency  = 10
 	DefaultUseWebsocket = false
 	DefaultLogLevel     = "info"
+
+	DefaultKeepaliveInterval = 15 * time.Second
 )
 
 // Default names for storage directories.
@@ -30,6 +32,8 @@ var DefaultConfig = Config{
 		Address:   DefaultAddress,
 		Port:      DefaultPort,
 		Websocket: DefaultUseWebsocket,
+
+		KeepaliveInterval: DefaultKeepaliveInterval,
 	},
 }
 
@@ -38,13 +42,16 @@ var DefaultConfig = Config{
 // NOTE: When adding CLI flags (using the `flag` struct tag) - add the description for (for the flag long version, not the shorthand) it in getFlagDescription() below.
 type Config struct {
 	Role           string   `koanf:"role"            flag:"role,r"`
+	Profile        string   `koanf:"profile"         flag:"profile"`
 	Concurrency    uint     `koanf:"concurrency"     flag:"concurrency,c"`
+	QueueSize      uint     `koanf:"queue-size"      flag:"queue-size"`
 	BootNodes      []string `koanf:"boot-nodes"      flag:"boot-nodes"`
 	Workspace      string   `koanf:"workspace"       flag:"workspace"`       // TODO: Check - does a head node ever use a workspace?
 	LoadAttributes bool     `koanf:"load-attributes" flag:"load-attributes"` // TODO: Head node probably doesn't need attributes..?
 	Topics         []string `koanf:"topics"          flag:"topics"`
 
-	DB string `koanf:"db" flag:"db"`
+	DB        string        `koanf:"db" flag:"db"`
+	DBTimeout time.Duration `koanf:"db-timeout"` // How long can a single database operation take.
 
 	Log          Log          `koanf:"log"`
 	Connectivity Connectivity `koanf:"connectivity"`
@@ -60,29 +67,277 @@ type Log struct {
 
 // Connectivity describes the libp2p host that the node will use.
 type Connectivity struct {
-	Address                 string `koanf:"address"                   flag:"address,a"`
-	Port                    uint   `koanf:"port"                      flag:"port,p"`
-	PrivateKey              string `koanf:"private-key"               flag:"private-key"`
-	DialbackAddress         string `koanf:"dialback-address"          flag:"dialback-address"`
-	DialbackPort            uint   `koanf:"dialback-port"             flag:"dialback-port"`
-	Websocket               bool   `koanf:"websocket"                 flag:"websocket,w"`
-	WebsocketPort           uint   `koanf:"websocket-port"            flag:"websocket-port"`
-	WebsocketDialbackPort   uint   `koanf:"websocket-dialback-port"   flag:"websocket-dialback-port"`
-	NoDialbackPeers         bool   `koanf:"no-dialback-peers"         flag:"no-dialback-peers"`
-	MustReachBootNodes      bool   `koanf:"must-reach-boot-nodes"     flag:"must-reach-boot-nodes"`
-	DisableConnectionLimits bool   `koanf:"disable-connection-limits" flag:"disable-connection-limits"`
-	ConnectionCount         uint   `koanf:"connection-count"          flag:"connection-count"`
+	Address                 string   `koanf:"address"                   flag:"address,a"`
+	Port                    uint     `koanf:"port"                      flag:"port,p"`
+	PrivateKey              string   `koanf:"private-key"               flag:"private-key"`
+	DialbackAddress         string   `koanf:"dialback-address"          flag:"dialback-address"`
+	DialbackPort            uint     `koanf:"dialback-port"             flag:"dialback-port"`
+	Websocket               bool     `koanf:"websocket"                 flag:"websocket,w"`
+	WebsocketPort           uint     `koanf:"websocket-port"            flag:"websocket-port"`
+	WebsocketDialbackPort   uint     `koanf:"websocket-dialback-port"   flag:"websocket-dialback-port"`
+	NoDialbackPeers         bool     `koanf:"no-dialback-peers"         flag:"no-dialback-peers"`
+	MustReachBootNodes      bool     `koanf:"must-reach-boot-nodes"     flag:"must-reach-boot-nodes"`
+	DisableConnectionLimits bool     `koanf:"disable-connection-limits" flag:"disable-connection-limits"`
+	ConnectionCount         uint     `koanf:"connection-count"          flag:"connection-count"`
+	DataAddress             string   `koanf:"data-address"              flag:"data-address"`
+	DataPort                uint     `koanf:"data-port"                 flag:"data-port"`
+	ListenAddresses         []string `koanf:"listen-addresses"          flag:"listen-addresses"`
+	AnnounceAddresses       []string `koanf:"announce-addresses"        flag:"announce-addresses"`
+	DisableTCP              bool     `koanf:"disable-tcp"               flag:"disable-tcp"`
+	QUIC                    bool     `koanf:"quic"                      flag:"quic"`
+	QUICPort                uint     `koanf:"quic-port"                 flag:"quic-port"`
+	Webtransport            bool     `koanf:"webtransport"              flag:"webtransport"`
+	WebtransportPort        uint     `koanf:"webtransport-port"         flag:"webtransport-port"`
+	MaxMessageSize          uint     `koanf:"max-message-size"          flag:"max-message-size"`
+	GossipMeshSize          uint     `koanf:"gossip-mesh-size"          flag:"gossip-mesh-size"`
+
+	KeepaliveInterval time.Duration `koanf:"keepalive-interval" flag:"keepalive-interval"` // Zero disables keepalive pings.
+
+	Keystore Keystore        `koanf:"keystore"`
+	Gater    ConnectionGater `koanf:"connection-gater"`
+}
+
+// ConnectionGater describes the policy for inbound and outbound connections, based on the IP address of the peer.
+type ConnectionGater struct {
+	AllowedNetworks  []string `koanf:"allowed-networks"  flag:"gater-allowed-networks"`
+	DeniedNetworks   []string `koanf:"denied-networks"   flag:"gater-denied-networks"`
+	AllowedASNs      []string `koanf:"allowed-asns"      flag:"gater-allowed-asns"`
+	DeniedASNs       []string `koanf:"denied-asns"       flag:"gater-denied-asns"`
+	AllowedCountries []string `koanf:"allowed-countries" flag:"gater-allowed-countries"`
+	DeniedCountries  []string `koanf:"denied-countries"  flag:"gater-denied-countries"`
+	Database         string   `koanf:"database"          flag:"gater-database"` // IP to ASN database (https://iptoasn.com), required for ASN and country rules.
+	AllowRelayed     bool     `koanf:"allow-relayed"     flag:"gater-allow-relayed"`
+}
+
+// Keystore describes where the private key of the node is kept.
+type Keystore struct {
+	Type       string         `koanf:"type"       flag:"keystore"` // One of `file`, `encrypted-file`, `keychain` or `pkcs11`. Empty means `file`.
+	Passphrase string         `koanf:"passphrase"`                 // Passphrase for the `encrypted-file` keystore. Best set via the environment.
+	Keychain   KeychainConfig `koanf:"keychain"`
+	PKCS11     PKCS11Config   `koanf:"pkcs11"`
+}
+
+// KeychainConfig identifies the OS keychain secret holding the private key.
+type KeychainConfig struct {
+	Service string `koanf:"service"`
+	Account string `koanf:"account"`
+}
+
+// PKCS11Config describes the HSM holding the private key.
+type PKCS11Config struct {
+	Tool   string `koanf:"tool"`
+	Module string `koanf:"module"`
+	KeyID  string `koanf:"key-id"`
+	PIN    string `koanf:"pin"`
 }
 
 type Head struct {
-	RestAPI string `koanf:"rest-api" flag:"rest-api"`
+	RestAPI             string `koanf:"rest-api"              flag:"rest-api"`
+	ResponseCompression bool   `koanf:"response-compression"  flag:"response-compression"`
+	WorkerDispatchLimit uint   `koanf:"worker-dispatch-limit" flag:"worker-dispatch-limit"`
+	Aggregation         string `koanf:"aggregation"           flag:"aggregation"`
+	ScheduleResultTopic string `koanf:"schedule-result-topic" flag:"schedule-result-topic"`
+
+	ClientConcurrency uint `koanf:"client-concurrency" flag:"client-concurrency"`
+	ClientQueueSize   uint `koanf:"client-queue-size"  flag:"client-queue-size"`
+
+	DelegationThreshold uint `koanf:"delegation-threshold" flag:"delegation-threshold"`
+	DelegationMaxDepth  uint `koanf:"delegation-max-depth" flag:"delegation-max-depth"`
+
+	ClientExecutionsPerHour uint `koanf:"client-executions-per-hour" flag:"client-executions-per-hour"`
+	ClientCPUSecondsPerDay  uint `koanf:"client-cpu-seconds-per-day" flag:"client-cpu-seconds-per-day"`
+
+	ResultArchive   string        `koanf:"result-archive" flag:"result-archive"`
+	ResultRetention time.Duration `koanf:"result-retention"`
+
+	BackupDir       string   `koanf:"backup-dir"       flag:"backup-dir"`
+	RestoreSnapshot string   `koanf:"restore-snapshot" flag:"restore-snapshot"`
+	AdminPeers      []string `koanf:"admin-peers"`
+
+	AnomalyThreshold float64 `koanf:"anomaly-threshold" flag:"anomaly-threshold"`
+
+	RollCallQueueSize    uint          `koanf:"roll-call-queue-size" flag:"roll-call-queue-size"`
+	RollCallOverflow     string        `koanf:"roll-call-overflow"   flag:"roll-call-overflow"`
+	RollCallBlockTimeout time.Duration `koanf:"roll-call-block-timeout"`
+	RollCallFallback     bool          `koanf:"roll-call-fallback"   flag:"roll-call-fallback"`
+
+	RollCallSamplingThreshold uint    `koanf:"roll-call-sampling-threshold" flag:"roll-call-sampling-threshold"`
+	RollCallSamplingFactor    float64 `koanf:"roll-call-sampling-factor"    flag:"roll-call-sampling-factor"`
+
+	RateLimits []RateLimit `koanf:"rate-limits"`
+	Canaries   []Canary    `koanf:"canaries"`
+	WarmPools  []WarmPool  `koanf:"warm-pools"`
+	Shadows    []Shadow    `koanf:"shadows"`
+
+	RestrictedSubgroups []RestrictedSubgroup `koanf:"restricted-subgroups"`
+}
+
+// RateLimit describes the rate of execution requests the head node accepts for a function.
+type RateLimit struct {
+	Function string  `koanf:"function"`
+	Rate     float64 `koanf:"rate"`
+	Burst    uint    `koanf:"burst"`
+	PerPeer  bool    `koanf:"per-peer"`
+}
+
+// Canary describes a probe function the head node periodically executes on a sample of workers in each subgroup.
+type Canary struct {
+	Function string        `koanf:"function"`
+	Method   string        `koanf:"method"`
+	Interval time.Duration `koanf:"interval"`
+	Sample   uint          `koanf:"sample"`
+}
+
+// WarmPool describes a pool of available workers the head node keeps for a function.
+type WarmPool struct {
+	Function string        `koanf:"function"`
+	Topic    string        `koanf:"topic"`
+	Size     uint          `koanf:"size"`
+	Refresh  time.Duration `koanf:"refresh"`
+}
+
+// Shadow describes shadow traffic the head node dispatches for a function, alongside client requests.
+type Shadow struct {
+	Function       string  `koanf:"function"`
+	Rate           float64 `koanf:"rate"`
+	Subgroup       string  `koanf:"subgroup"`
+	TargetFunction string  `koanf:"target-function"`
+}
+
+// RestrictedSubgroup describes a subgroup whose workers must present a membership credential issued by the subgroup owner.
+type RestrictedSubgroup struct {
+	Subgroup string `koanf:"subgroup"`
+	Owner    string `koanf:"owner"`
 }
 
 type Worker struct {
-	RuntimePath        string  `koanf:"runtime-path"         flag:"runtime-path"`
-	RuntimeCLI         string  `koanf:"runtime-cli"          flag:"runtime-cli"`
-	CPUPercentageLimit float64 `koanf:"cpu-percentage-limit" flag:"cpu-percentage-limit"`
-	MemoryLimitKB      int64   `koanf:"memory-limit"         flag:"memory-limit"`
+	RuntimePath        string   `koanf:"runtime-path"         flag:"runtime-path"`
+	RuntimeCLI         string   `koanf:"runtime-cli"          flag:"runtime-cli"`
+	CPUPercentageLimit float64  `koanf:"cpu-percentage-limit" flag:"cpu-percentage-limit"`
+	MemoryLimitKB      int64    `koanf:"memory-limit"         flag:"memory-limit"`
+	ExecutionLimits    bool     `koanf:"execution-limits"     flag:"execution-limits"`
+	ModuleCache        bool     `koanf:"module-cache"         flag:"module-cache"`
+	ModuleCacheSizeMB  int64    `koanf:"module-cache-size"    flag:"module-cache-size"`
+	AdminPeers         []string `koanf:"admin-peers"          flag:"admin-peers"`
+
+	MembershipCredentials []string `koanf:"membership-credentials" flag:"membership-credentials"`
+
+	TenantConcurrency uint `koanf:"tenant-concurrency" flag:"tenant-concurrency"`
+	TenantQueueSize   uint `koanf:"tenant-queue-size"  flag:"tenant-queue-size"`
+
+	AllowedFunctions []string `koanf:"allowed-functions" flag:"allowed-functions"`
+	DeniedFunctions  []string `koanf:"denied-functions"  flag:"denied-functions"`
+
+	ExportState string `koanf:"export-state" flag:"export-state"`
+	ImportState string `koanf:"import-state" flag:"import-state"`
+
+	FleetReportInterval time.Duration `koanf:"fleet-report-interval"` // Negative value disables fleet reports.
+	ClusterDeadline     time.Duration `koanf:"cluster-deadline"`      // Negative value disables the cluster watchdog.
+	ResultCacheTTL      time.Duration `koanf:"result-cache-ttl"`      // Zero disables the result cache.
+
+	ProcessReuse  ProcessReuse  `koanf:"process-reuse"`
+	WarmProcesses WarmProcesses `koanf:"warm-processes"`
+
+	Synthetic SyntheticExecution `koanf:"synthetic-execution"`
+
+	SelfTest SelfTest `koanf:"self-test"`
+
+	Container ContainerExecution `koanf:"container-execution"`
+
+	GPU GPU `koanf:"gpu"`
+
+	Inputs Inputs `koanf:"inputs"`
+
+	Artifacts ArtifactStorage `koanf:"artifacts"`
+
+	MaintenanceWindows []MaintenanceWindow `koanf:"maintenance-windows"`
+}
+
+// MaintenanceWindow describes a recurring period during which the worker declines new work.
+type MaintenanceWindow struct {
+	Start    string        `koanf:"start"` // Cron expression for the start of the window.
+	Duration time.Duration `koanf:"duration"`
+}
+
+// ProcessReuse describes when worker can reuse runtime processes for multiple executions.
+type ProcessReuse struct {
+	MaxInvocations  uint          `koanf:"max-invocations"  flag:"process-reuse-max-invocations"`
+	MaxLifetime     time.Duration `koanf:"max-lifetime"`
+	MemoryCeilingKB int64         `koanf:"memory-ceiling"   flag:"process-reuse-memory-ceiling"`
+}
+
+// WarmProcesses describes how many runtime processes the worker starts ahead of executions, for frequently used functions.
+type WarmProcesses struct {
+	Size        uint          `koanf:"size"    flag:"warm-processes-size"`
+	Modules     uint          `koanf:"modules" flag:"warm-processes-modules"`
+	IdleTimeout time.Duration `koanf:"idle-timeout"`
+}
+
+// SyntheticExecution describes the synthetic executor the worker uses instead of the Blockless Runtime, for soak-testing.
+type SyntheticExecution struct {
+	Enable      bool    `koanf:"enable"       flag:"synthetic-execution"`
+	LatencyMS   uint    `koanf:"latency"      flag:"synthetic-execution-latency"`
+	OutputSize  uint    `koanf:"output-size"  flag:"synthetic-execution-output-size"`
+	FailureRate float64 `koanf:"failure-rate" flag:"synthetic-execution-failure-rate"`
+	Seed        int64   `koanf:"seed"`
+}
+
+// SelfTest describes the self-test the worker runs on startup, before it starts serving executions.
+type SelfTest struct {
+	Enable      bool   `koanf:"enable"       flag:"self-test"`
+	FunctionID  string `koanf:"function-id"  flag:"self-test-function"`
+	Method      string `koanf:"method"       flag:"self-test-method"`
+	ManifestURL string `koanf:"manifest-url"`
+}
+
+// ContainerExecution describes the executor running functions packaged as OCI container images.
+type ContainerExecution struct {
+	Enable        bool    `koanf:"enable"          flag:"container-execution"`
+	CLI           string  `koanf:"cli"             flag:"container-cli"`
+	Network       string  `koanf:"network"`
+	MemoryLimitKB int64   `koanf:"memory-limit-kb"`
+	CPULimit      float64 `koanf:"cpu-limit"`
+	PidsLimit     int64   `koanf:"pids-limit"` // Zero means the default limit is used.
+}
+
+// GPU describes the GPUs the worker advertises as attributes and makes available to executions requiring them.
+type GPU struct {
+	Enable  bool     `koanf:"enable"  flag:"gpu"`
+	CLI     string   `koanf:"cli"`
+	Devices []string `koanf:"devices" flag:"gpu-devices"` // Indexes of the devices to use. Empty means all detected devices.
+}
+
+// Inputs describes how the worker fetches and caches remote inputs of executions.
+type Inputs struct {
+	Cache       bool          `koanf:"cache"        flag:"input-cache"`
+	CacheSizeMB int64         `koanf:"cache-size"   flag:"input-cache-size"`
+	CacheTTL    time.Duration `koanf:"cache-ttl"` // Zero means the default TTL is used.
+	MaxSizeMB   int64         `koanf:"max-size"     flag:"input-max-size"`
+	IPFSGateway string        `koanf:"ipfs-gateway" flag:"input-ipfs-gateway"`
+}
+
+// ArtifactStorage describes the blob storage the worker uploads output files of executions to.
+type ArtifactStorage struct {
+	Store     string        `koanf:"store"    flag:"artifact-store"` // Either `s3` or `ipfs`. Empty means output files are not uploaded.
+	MaxSizeMB int64         `koanf:"max-size" flag:"artifact-max-size"`
+	IPFS      IPFSArtifacts `koanf:"ipfs"`
+	S3        S3Artifacts   `koanf:"s3"`
+}
+
+// IPFSArtifacts describes the IPFS node artifacts are uploaded to.
+type IPFSArtifacts struct {
+	API     string `koanf:"api"     flag:"artifact-ipfs-api"`
+	Gateway string `koanf:"gateway"`
+}
+
+// S3Artifacts describes the S3-compatible bucket artifacts are uploaded to.
+type S3Artifacts struct {
+	Endpoint  string `koanf:"endpoint"   flag:"artifact-s3-endpoint"`
+	Region    string `koanf:"region"`
+	Bucket    string `koanf:"bucket"     flag:"artifact-s3-bucket"`
+	AccessKey string `koanf:"access-key"`
+	SecretKey string `koanf:"secret-key"`
+	PublicURL string `koanf:"public-url"`
 }
 
 type Telemetry struct {
@@ -108,6 +363,8 @@ type HTTPTracing struct {
 type Metrics struct {
 	Enable            bool   `koanf:"enable" flag:"enable-metrics"`
 	PrometheusAddress string `koanf:"prometheus-address" flag:"prometheus-address"`
+
+	SlowHandlerThreshold time.Duration `koanf:"slow-handler-threshold"`
 }
 
 // ConfigOptionInfo describes a specific configuration option, it's location in the config file and
@@ -126,8 +383,12 @@ func getFlagDescription(flag string) string {
 	switch flag {
 	case "role":
 		return "role this node will have in the Blockless protocol (head or worker)"
+	case "profile":
+		return "set of defaults for the deployment type (constrained for Raspberry Pi class devices)"
 	case "concurrency":
 		return "maximum number of requests node will process in parallel"
+	case "queue-size":
+		return "maximum number of requests waiting for processing, before new ones are turned down"
 	case "boot-nodes":
 		return "list of addresses that this node will connect to on startup, in multiaddr format"
 	case "workspace":
@@ -146,6 +407,24 @@ func getFlagDescription(flag string) string {
 		return "port that the b7s host will use"
 	case "private-key":
 		return "private key that the b7s host will use"
+	case "keystore":
+		return "keystore holding the private key - file, encrypted-file, keychain or pkcs11"
+	case "gater-allowed-networks":
+		return "CIDR ranges peers are allowed to connect from and to"
+	case "gater-denied-networks":
+		return "CIDR ranges peers are not allowed to connect from and to"
+	case "gater-allowed-asns":
+		return "autonomous systems peers are allowed to connect from and to"
+	case "gater-denied-asns":
+		return "autonomous systems peers are not allowed to connect from and to"
+	case "gater-allowed-countries":
+		return "countries peers are allowed to connect from and to"
+	case "gater-denied-countries":
+		return "countries peers are not allowed to connect from and to"
+	case "gater-database":
+		return "IP to ASN database used for ASN and country rules"
+	case "gater-allow-relayed":
+		return "permit relayed connections through relays permitted by the rules"
 	case "websocket":
 		return "should the node use websocket protocol for communication"
 	case "dialback-address":
@@ -158,8 +437,70 @@ func getFlagDescription(flag string) string {
 		return "external port that the b7s host will advertise for websocket connections"
 	case "connection-count":
 		return "maximum number of connections the b7s host will aim to have"
+	case "listen-addresses":
+		return "additional multiaddresses that the b7s host will listen on"
+	case "announce-addresses":
+		return "multiaddresses that the b7s host will advertise, overriding dialback settings"
+	case "disable-tcp":
+		return "do not listen for TCP connections"
+	case "quic":
+		return "should the node use QUIC protocol for communication"
+	case "quic-port":
+		return "UDP port to use for QUIC connections"
+	case "webtransport":
+		return "should the node use WebTransport protocol for communication"
+	case "webtransport-port":
+		return "UDP port to use for WebTransport connections"
+	case "max-message-size":
+		return "maximum size of messages in bytes, useful when browser-based peers are on the network"
+	case "gossip-mesh-size":
+		return "number of peers the node exchanges full messages with on each topic, 0 being the gossipsub default"
+	case "keepalive-interval":
+		return "how often to ping peers connected using WebSocket or WebTransport, 0 disables keepalive pings"
+	case "data-address":
+		return "address that the b7s data host will use (defaults to the host address)"
+	case "data-port":
+		return "port that the b7s data host will use for execution results, 0 disables the data host"
 	case "rest-api":
 		return "address where the head node REST API will listen on"
+	case "response-compression":
+		return "compress REST API responses with gzip or zstd, if the client accepts it"
+	case "worker-dispatch-limit":
+		return "maximum number of concurrent executions the head node will dispatch to a single worker"
+	case "client-concurrency":
+		return "maximum number of executions a single client can have in flight on the head node (0 means no limit)"
+	case "client-queue-size":
+		return "maximum number of execution requests of a single client waiting for its executions to complete"
+	case "delegation-threshold":
+		return "number of executions in progress above which the head node delegates new requests to peer head nodes (0 disables delegation)"
+	case "delegation-max-depth":
+		return "maximum number of head nodes a request can be delegated through"
+	case "aggregation":
+		return "how the head node collapses results from multiple workers (first-success, majority or all-match)"
+	case "schedule-result-topic":
+		return "topic the head node publishes results of scheduled executions to"
+	case "client-executions-per-hour":
+		return "maximum number of executions per hour for a client (0 means no limit)"
+	case "client-cpu-seconds-per-day":
+		return "maximum CPU time (in seconds) per day for executions of a client (0 means no limit)"
+	case "result-archive":
+		return "directory where the head node archives old execution results"
+	case "backup-dir":
+		return "directory where the head node keeps database backups"
+	case "restore-snapshot":
+		return "name of the backup snapshot the head node database is restored from on startup"
+	case "anomaly-threshold":
+		return "how far (in standard deviations) execution duration or output size can be from the function history before the result is flagged (0 disables detection)"
+	case "roll-call-queue-size":
+		return "maximum number of roll call responses queued per request"
+	case "roll-call-overflow":
+		return "what happens to roll call responses once the queue is full (drop-oldest, reject-new or block)"
+	case "roll-call-fallback":
+		return "retry roll calls on the default topic if not enough workers in the subgroup responded"
+	case "roll-call-sampling-threshold":
+		return "number of peers on a topic above which roll calls are sampled (0 disables sampling)"
+	case "roll-call-sampling-factor":
+		return "how many times more workers than needed are asked to answer a sampled roll call"
 	case "runtime-path":
 		return "Blockless Runtime location (used by the worker node)"
 	case "runtime-cli":
@@ -168,6 +509,76 @@ func getFlagDescription(flag string) string {
 		return "amount of CPU time allowed for Blockless Functions in the 0-1 range, 1 being unlimited"
 	case "memory-limit":
 		return "memory limit (kB) for Blockless Functions"
+	case "execution-limits":
+		return "enforce resource limits requested for individual executions"
+	case "module-cache":
+		return "cache compiled WASM modules between executions"
+	case "module-cache-size":
+		return "maximum size (MB) of the compiled WASM module cache, 0 being unlimited"
+	case "admin-peers":
+		return "list of peers allowed to switch the worker executor at runtime, drain the worker, run its self-test or change its subgroups"
+	case "membership-credentials":
+		return "files with credentials admitting the worker to restricted subgroups"
+	case "tenant-concurrency":
+		return "maximum number of executions a single tenant can have in flight on the worker (0 means no limit)"
+	case "tenant-queue-size":
+		return "maximum number of execution requests of a single tenant waiting for its executions to complete"
+	case "allowed-functions":
+		return "functions the worker executes, all functions that are not denied if not set"
+	case "denied-functions":
+		return "functions the worker never executes"
+	case "export-state":
+		return "file the worker exports its state to on shutdown, for moving it to different hardware"
+	case "import-state":
+		return "file with the state exported by the worker on different hardware, imported on startup"
+	case "process-reuse-max-invocations":
+		return "maximum number of executions a runtime process can handle, values below 2 disable process reuse"
+	case "process-reuse-memory-ceiling":
+		return "memory usage (kB) after which a reused runtime process is recycled, 0 being unlimited"
+	case "warm-processes-size":
+		return "number of runtime processes kept started ahead of executions for each recently used function, 0 disables the warm pool"
+	case "warm-processes-modules":
+		return "maximum number of functions with warm runtime processes, least recently used being evicted first, 0 being unlimited"
+	case "synthetic-execution":
+		return "replace the Blockless Runtime with a synthetic executor, for soak-testing the network"
+	case "synthetic-execution-latency":
+		return "how long (ms) each synthetic execution takes"
+	case "synthetic-execution-output-size":
+		return "size (bytes) of the output of synthetic executions"
+	case "synthetic-execution-failure-rate":
+		return "share of synthetic executions that fail, in the 0-1 range"
+	case "self-test":
+		return "verify the worker can serve executions on startup, and exit if any of the checks fail"
+	case "self-test-function":
+		return "function installed and executed during the startup self-test"
+	case "self-test-method":
+		return "method of the function executed during the startup self-test"
+	case "container-execution":
+		return "run functions packaged as OCI container images"
+	case "container-cli":
+		return "container engine CLI used to run function containers, e.g. docker or podman"
+	case "gpu":
+		return "detect GPUs, advertise them as worker attributes and make them available to executions requiring them"
+	case "gpu-devices":
+		return "indexes of the GPU devices to use, all detected devices if not set"
+	case "input-cache":
+		return "cache remote inputs fetched for executions"
+	case "input-cache-size":
+		return "maximum size (MB) of the execution input cache, 0 being unlimited"
+	case "input-max-size":
+		return "maximum size (MB) of a single remote input of an execution, 0 being the default (1024 MB)"
+	case "input-ipfs-gateway":
+		return "gateway IPFS inputs of executions are fetched through"
+	case "artifact-store":
+		return "blob storage output files of executions are uploaded to - s3 or ipfs"
+	case "artifact-max-size":
+		return "maximum size (MB) of a single output file uploaded as an artifact, 0 being the default (1024 MB)"
+	case "artifact-ipfs-api":
+		return "address of the IPFS node RPC API artifacts are uploaded to"
+	case "artifact-s3-endpoint":
+		return "address of the S3-compatible service artifacts are uploaded to"
+	case "artifact-s3-bucket":
+		return "bucket artifacts are uploaded to"
 	case "no-dialback-peers":
 		return "start without dialing back peers from previous runs"
 	case "must-reach-boot-nodes":
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/knadh/koanf/providers/structs"
 	"github.com/spf13/pflag"
@@ -48,6 +49,9 @@ func addFlag(fs *pflag.FlagSet, fc CLIFlag) error {
 	case int64:
 		fs.Int64P(fc.Flag, fc.Shorthand, def, fc.Description)
 
+	case time.Duration:
+		fs.DurationP(fc.Flag, fc.Shorthand, def, fc.Description)
+
 	case bool:
 		fs.BoolP(fc.Flag, fc.Shorthand, def, fc.Description)
 
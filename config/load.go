@@ -55,6 +55,12 @@ func load(args []string) (*Config, error) {
 		}
 	}
 
+	// Profile defaults are used for values not set in the environment, config file or CLI flags.
+	err = applyProfile(konfig, flags)
+	if err != nil {
+		return nil, fmt.Errorf("could not apply profile: %w", err)
+	}
+
 	// For the sake of usability flags have a flat structure - e.g. port or cpu-percentage-limit.
 	// For use in config files, we prefer a structured layout, e.g. connectivity=>port or worker=>cpu-percentage-limit.
 	// This callback translates the flag names from a flat layout to the structured one, so that koanf knows how to match
@@ -371,3 +371,45 @@ func writeConfigFile(t *testing.T, m map[string]any) string {
 
 	return filepath
 }
+
+func TestConfig_Profile(t *testing.T) {
+
+	t.Run("constrained profile defaults", func(t *testing.T) {
+		cfg, err := load([]string{"--profile", ProfileConstrained})
+		require.NoError(t, err)
+
+		require.Equal(t, ProfileConstrained, cfg.Profile)
+		require.Equal(t, uint(1), cfg.Concurrency)
+		require.Equal(t, uint(4), cfg.QueueSize)
+		require.Equal(t, uint(3), cfg.Connectivity.GossipMeshSize)
+		require.Equal(t, int64(64), cfg.Worker.ModuleCacheSizeMB)
+		require.False(t, cfg.Telemetry.Metrics.Enable)
+	})
+	t.Run("explicit values override profile defaults", func(t *testing.T) {
+
+		filepath := writeConfigFile(t, map[string]any{
+			"profile": ProfileConstrained,
+			"connectivity": map[string]any{
+				"gossip-mesh-size": 4,
+			},
+		})
+
+		cfg, err := load([]string{"--concurrency", "2", "--enable-metrics", "--config", filepath})
+		require.NoError(t, err)
+
+		require.Equal(t, uint(2), cfg.Concurrency)
+		require.Equal(t, uint(4), cfg.Connectivity.GossipMeshSize)
+		require.True(t, cfg.Telemetry.Metrics.Enable)
+		require.Equal(t, int64(64), cfg.Worker.ModuleCacheSizeMB)
+	})
+	t.Run("no profile", func(t *testing.T) {
+		cfg, err := load(nil)
+		require.NoError(t, err)
+		require.Equal(t, uint(DefaultConcurrency), cfg.Concurrency)
+		require.Zero(t, cfg.Connectivity.GossipMeshSize)
+	})
+	t.Run("unknown profile", func(t *testing.T) {
+		_, err := load([]string{"--profile", "unknown"})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/knadh/koanf/v2"
+	"github.com/spf13/pflag"
+)
+
+// Profiles are sets of defaults for a type of deployment, selected with a single option.
+const (
+	// ProfileConstrained is meant for workers on resource-constrained devices, such as Raspberry Pi class boards.
+	ProfileConstrained = "constrained"
+)
+
+// Defaults for workers on constrained devices - single execution at a time, short work queue, smaller caches and gossip mesh and no metrics.
+// The node also tunes its database to write to flash storage less often.
+var constrainedProfile = map[string]any{
+	"concurrency":                   1,
+	"queue-size":                    4,
+	"connectivity.gossip-mesh-size": 3,
+	"worker.module-cache-size":      64,
+	"telemetry.metrics.enable":      false,
+}
+
+// applyProfile sets the profile defaults for values not yet set in the config.
+// Since CLI flags are loaded last, the profile set as a flag takes precedence too.
+func applyProfile(konfig *koanf.Koanf, flags *pflag.FlagSet) error {
+
+	profile := konfig.String("profile")
+	flag := flags.Lookup("profile")
+	if flag != nil && flag.Changed {
+		profile = flag.Value.String()
+	}
+
+	var defaults map[string]any
+	switch profile {
+	case "":
+		return nil
+	case ProfileConstrained:
+		defaults = constrainedProfile
+	default:
+		return fmt.Errorf("unknown profile (%s)", profile)
+	}
+
+	for key, value := range defaults {
+
+		if konfig.Exists(key) {
+			continue
+		}
+
+		err := konfig.Set(key, value)
+		if err != nil {
+			return fmt.Errorf("could not set profile default (key: %s): %w", key, err)
+		}
+	}
+
+	return nil
+}
@@ -1,50 +1,54 @@
 package consensus
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 )
 
+// ErrNotLeader is returned when an operation reserved for the cluster leader is requested from another replica.
+var ErrNotLeader = errors.New("replica is not the cluster leader")
+
 // Type identifies consensus protocols suported by Blockless.
 type Type uint
 
+// Consensus algorithms built into Blockless. Algorithms contributed by other packages should use values outside of this range.
 const (
 	Raft Type = iota + 1
 	PBFT
+	HotStuff
 )
 
 func (t Type) String() string {
-	switch t {
-	case Raft:
-		return "Raft"
-	case PBFT:
-		return "PBFT"
-	default:
+
+	backend, ok := lookup(t)
+	if !ok {
 		return fmt.Sprintf("unknown: %d", t)
 	}
+
+	return backend.name
 }
 
+// Valid returns true if there is a registered implementation of the consensus algorithm.
 func (t Type) Valid() bool {
-	switch t {
-	case Raft, PBFT:
-		return true
-	default:
-		return false
-	}
+	_, ok := lookup(t)
+	return ok
 }
 
+// Parse returns the consensus algorithm registered under the given name. Names are case insensitive.
 func Parse(s string) (Type, error) {
 
 	if s == "" {
 		return 0, nil
 	}
 
-	switch strings.ToLower(s) {
-	case "raft":
-		return Raft, nil
+	registry.RLock()
+	defer registry.RUnlock()
 
-	case "pbft":
-		return PBFT, nil
+	for t, backend := range registry.backends {
+		if strings.EqualFold(backend.name, s) {
+			return t, nil
+		}
 	}
 
 	return 0, fmt.Errorf("unknown consensus value (%s)", s)
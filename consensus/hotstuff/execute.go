@@ -0,0 +1,197 @@
+package hotstuff
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/telemetry/b7ssemconv"
+	"github.com/blocklessnetwork/b7s/telemetry/tracing"
+)
+
+const (
+	spanExecute = "HotStuffExecute"
+)
+
+// Execute fullfils the consensus interface by inserting the request into the pipeline.
+func (r *Replica) Execute(client peer.ID, requestID string, timestamp time.Time, req execute.Request) (codes.Code, execute.Result, error) {
+
+	// Modifying state, so acquire state lock now.
+	r.sl.Lock()
+	defer r.sl.Unlock()
+
+	request := Request{
+		ID:        requestID,
+		Timestamp: timestamp,
+		Origin:    client,
+		Execute:   req,
+	}
+
+	err := r.processRequest(tracing.TraceContext(context.Background(), r.cfg.TraceInfo), request)
+	if err != nil {
+		return codes.Error, execute.Result{}, fmt.Errorf("could not process request: %w", err)
+	}
+
+	// Nothing to return at this point.
+	return codes.NoContent, execute.Result{}, nil
+}
+
+func (r *Replica) processRequest(ctx context.Context, req Request) error {
+
+	pub, err := req.Origin.ExtractPublicKey()
+	if err != nil {
+		return fmt.Errorf("could not extract public key from client: %w", err)
+	}
+
+	err = req.Execute.VerifySignature(pub)
+	if err != nil {
+		return fmt.Errorf("request is not properly signed by the client: %w", err)
+	}
+
+	digest := requestDigest(req)
+
+	log := r.log.With().Str("client", req.Origin.String()).Str("request", req.ID).Str("digest", digest).Logger()
+
+	log.Info().Msg("received a request")
+
+	// Check if we've executed this before. If yes, just return the result.
+	result, ok := r.executions[req.ID]
+	if ok {
+		log.Info().Msg("request already executed, sending result to client")
+
+		err := r.sendResult(ctx, req.Origin, &result)
+		if err != nil {
+			return fmt.Errorf("could not send execution result back to client (request: %s, client: %s): %w", req.ID, req.Origin.String(), err)
+		}
+
+		return nil
+	}
+
+	_, seen := r.requests[digest]
+	if seen {
+		log.Info().Msg("already seen this request, dropping")
+		return nil
+	}
+
+	r.requests[digest] = req
+	r.pending[digest] = req
+
+	// Move on to the next leader if the request is not decided in time.
+	r.startViewTimer(false)
+
+	return r.proposeNext(ctx)
+}
+
+// execute executes the decided request AND sends the result back to origin.
+func (r *Replica) execute(ctx context.Context, view uint, digest string) error {
+
+	request, ok := r.requests[digest]
+	if !ok {
+		return fmt.Errorf("unknown request (digest: %s)", digest)
+	}
+
+	log := r.log.With().Uint("view", view).Str("digest", digest).Str("request", request.ID).Logger()
+
+	// We don't want to execute a job multiple times.
+	_, havePending := r.pending[digest]
+	if !havePending {
+		log.Warn().Msg("no pending request with matching info - likely already executed")
+		return nil
+	}
+
+	delete(r.pending, digest)
+
+	log.Info().Msg("executing request")
+
+	// Execution response is sent in the context of this span, allowing the head node to link to it.
+	ctx, span := r.tracer.Start(ctx, spanExecute, r.executeSpanOpts(request.ID, view)...)
+	defer span.End()
+
+	res, err := r.executor.ExecuteFunction(ctx, request.ID, request.Execute.WithWatermark())
+	if err != nil {
+		log.Error().Err(err).Msg("execution failed")
+	}
+
+	log.Info().Msg("executed request")
+
+	metadata, err := r.cfg.MetadataProvider.Metadata(request.Execute, res.Result)
+	if err != nil {
+		log.Warn().Err(err).Msg("could not get metadata")
+	}
+
+	nres := execute.NodeResult{
+		Result:   res,
+		Metadata: metadata,
+		HotStuff: execute.HotStuffResultInfo{
+			View:             view,
+			RequestTimestamp: request.Timestamp,
+			Replica:          r.id,
+		},
+		Timing:    &execute.Timing{Execution: res.Usage.WallClockTime.Milliseconds()},
+		Watermark: execute.WatermarkTag(request.Execute.Config.Watermark, request.ID),
+	}
+	nres.SetChecksum()
+
+	err = nres.Sign(r.host.PrivateKey())
+	if err != nil {
+		return fmt.Errorf("could not sign execution result: %w", err)
+	}
+
+	msg := response.Execute{
+		BaseMessage: blockless.BaseMessage{TraceInfo: r.cfg.TraceInfo},
+		Code:        res.Code,
+		RequestID:   request.ID,
+		Results:     execute.ResultMap{r.id: nres},
+	}
+
+	// Save this executions in case it's requested again.
+	r.executions[request.ID] = msg
+
+	// Invoke specified post processor functions.
+	for _, proc := range r.cfg.PostProcessors {
+		proc(request.ID, request.Origin, request.Execute, nres)
+	}
+
+	err = r.sendResult(ctx, request.Origin, &msg)
+	if err != nil {
+		return fmt.Errorf("could not send execution response to node (target: %s, request: %s): %w", request.Origin.String(), request.ID, err)
+	}
+
+	r.metrics.MeasureSinceWithLabels(hotstuffExecutionsTimeMetric, request.Timestamp, []metrics.Label{{Name: "function", Value: request.Execute.FunctionID}})
+
+	return nil
+}
+
+// executeSpanOpts returns the options for the span of the request execution. The span is linked to the span
+// that requested cluster formation on the head node, so that the execution can be found from the head node trace.
+func (r *Replica) executeSpanOpts(requestID string, view uint) []trace.SpanStartOption {
+
+	opts := []trace.SpanStartOption{
+		trace.WithAttributes(
+			b7ssemconv.ExecutionRequestID.String(requestID),
+			b7ssemconv.ConsensusReplica.String(r.id.String()),
+			b7ssemconv.ConsensusLeader.String(r.leader(view).String()),
+		),
+	}
+
+	link, ok := tracing.LinkFromTraceInfo(r.cfg.TraceInfo)
+	if ok {
+		opts = append(opts, trace.WithLinks(link))
+	}
+
+	return opts
+}
+
+// requestDigest returns the digest of the request, excluding the trace information which differs between replicas.
+func requestDigest(req Request) string {
+	req.BaseMessage = BaseMessage{}
+	return getDigest(req)
+}
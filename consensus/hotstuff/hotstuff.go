@@ -0,0 +1,244 @@
+package hotstuff
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/rs/zerolog"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/telemetry/tracing"
+)
+
+// Replica is a single HotStuff node. The leader of each view drives the replicas through the prepare, pre-commit,
+// commit and decide phases. Replicas only ever talk to the leader, so each phase takes a linear number of messages,
+// unlike PBFT where every replica broadcasts to every other replica.
+type Replica struct {
+	cfg Config
+
+	// Components.
+	log      zerolog.Logger
+	host     *host.Host
+	executor blockless.Executor
+
+	// Cluster identity.
+	id         peer.ID
+	peers      []peer.ID
+	clusterID  string
+	protocolID protocol.ID
+
+	// Number of byzantine replicas we can tolerate.
+	f uint
+
+	// State lock. This is a global lock for all replica state.
+	sl sync.Mutex
+
+	// Current view.
+	view uint
+
+	// Highest prepare certificate we know of, and the pre-commit certificate we are locked on.
+	highQC   *QuorumCertificate
+	lockedQC *QuorumCertificate
+
+	// Keep track of seen requests, and requests not yet executed, by digest.
+	requests map[string]Request
+	pending  map[string]Request
+
+	// Leader state - digest proposed in each view, collected votes and new view messages.
+	proposals map[uint]string
+	votes     map[voteID]map[peer.ID]Vote
+	newViews  map[uint]map[peer.ID]NewView
+
+	// Leader of the current view may propose once it knows the highest prepare certificate of a quorum of replicas.
+	ready bool
+
+	// Phases we voted in, so we never vote twice.
+	voted map[voteID]struct{}
+
+	// Keep track of past executions. Results are mapped to request IDs, not digests.
+	executions map[string]response.Execute
+
+	// Moves to the next view if no decision is reached in time.
+	viewTimer *time.Timer
+
+	// Telemetry
+	tracer  *tracing.Tracer
+	metrics *metrics.Metrics
+}
+
+// voteID identifies a phase of a view.
+type voteID struct {
+	view  uint
+	phase Phase
+}
+
+// NewReplica creates a new HotStuff replica.
+func NewReplica(log zerolog.Logger, host *host.Host, executor blockless.Executor, peers []peer.ID, clusterID string, options ...Option) (*Replica, error) {
+
+	total := uint(len(peers))
+
+	if total < MinimumReplicaCount {
+		return nil, fmt.Errorf("too small cluster for a valid HotStuff (have: %v, minimum: %v)", total, MinimumReplicaCount)
+	}
+
+	cfg := DefaultConfig
+	for _, option := range options {
+		option(&cfg)
+	}
+
+	replica := Replica{
+		cfg: cfg,
+
+		log:        log.With().Str("component", "hotstuff").Str("cluster", clusterID).Logger(),
+		host:       host,
+		executor:   executor,
+		clusterID:  clusterID,
+		protocolID: protocol.ID(fmt.Sprintf("%s/cluster/%s", Protocol, clusterID)),
+
+		id:    host.ID(),
+		peers: peers,
+		f:     calcByzantineTolerance(total),
+
+		requests:   make(map[string]Request),
+		pending:    make(map[string]Request),
+		proposals:  make(map[uint]string),
+		votes:      make(map[voteID]map[peer.ID]Vote),
+		newViews:   make(map[uint]map[peer.ID]NewView),
+		voted:      make(map[voteID]struct{}),
+		executions: make(map[string]response.Execute),
+
+		// Leader of the first view does not wait for new view messages.
+		ready: true,
+
+		tracer:  tracing.NewTracer(tracerName),
+		metrics: metrics.Default(),
+	}
+
+	replica.log.Info().Strs("replicas", blockless.PeerIDsToStr(peers)).Uint("n", total).Uint("f", replica.f).Msg("created HotStuff replica")
+
+	replica.setMessageHandler()
+
+	return &replica, nil
+}
+
+func (r *Replica) Consensus() consensus.Type {
+	return consensus.HotStuff
+}
+
+func (r *Replica) Shutdown() error {
+	r.host.RemoveStreamHandler(r.protocolID)
+
+	r.sl.Lock()
+	defer r.sl.Unlock()
+
+	r.stopViewTimer()
+	return nil
+}
+
+func (r *Replica) setMessageHandler() {
+
+	// Set the root span for this HotStuff cluster.
+	ctx := tracing.TraceContext(context.Background(), r.cfg.TraceInfo)
+
+	r.host.Host.SetStreamHandler(r.protocolID, func(stream network.Stream) {
+		defer stream.Close()
+
+		from := stream.Conn().RemotePeer()
+
+		// On this protocol we only allow messages from other replicas in the cluster.
+		if !r.isReplica(from) {
+			r.log.Info().Str("peer", from.String()).Msg("received message from a peer not in our cluster, discarding")
+			return
+		}
+
+		buf := bufio.NewReader(stream)
+		msg, err := buf.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			stream.Reset()
+			r.log.Error().Err(err).Msg("error receiving direct message")
+			return
+		}
+
+		r.log.Debug().Str("peer", from.String()).Msg("received message")
+
+		err = r.processMessage(ctx, from, msg)
+		if err != nil {
+			r.log.Error().Err(err).Str("peer", from.String()).Msg("message processing failed")
+		}
+	})
+}
+
+func (r *Replica) processMessage(ctx context.Context, from peer.ID, payload []byte) error {
+
+	msg, err := unpackMessage(payload)
+	if err != nil {
+		return fmt.Errorf("could not unpack message: %w", err)
+	}
+
+	r.sl.Lock()
+	defer r.sl.Unlock()
+
+	switch m := msg.(type) {
+
+	case Request:
+		return r.processRequest(ctx, m)
+
+	case Proposal:
+		return r.processProposal(ctx, from, m)
+
+	case Vote:
+		return r.processVote(ctx, from, m)
+
+	case NewView:
+		return r.processNewView(ctx, from, m)
+	}
+
+	return fmt.Errorf("unexpected message type (from: %s): %T", from, msg)
+}
+
+// leader returns the leader of the given view. Leadership rotates through the replicas.
+func (r *Replica) leader(view uint) peer.ID {
+	return r.peers[view%uint(len(r.peers))]
+}
+
+func (r *Replica) isLeader() bool {
+	return r.leader(r.view) == r.id
+}
+
+func (r *Replica) isReplica(id peer.ID) bool {
+	return slices.Contains(r.peers, id)
+}
+
+// quorum returns the number of votes needed for a quorum certificate - n-f.
+func (r *Replica) quorum() uint {
+	return uint(len(r.peers)) - r.f
+}
+
+// MinClusterResults returns the number of identical results client should expect from the
+// cluster before accepting the result as valid. The number is f+1.
+func MinClusterResults(n uint) uint {
+	return calcByzantineTolerance(n) + 1
+}
+
+// based on the number of replicas, determine how many byzantine replicas we can tolerate.
+func calcByzantineTolerance(n uint) uint {
+
+	if n <= 1 {
+		return 0
+	}
+
+	return (n - 1) / 3
+}
@@ -0,0 +1,393 @@
+package hotstuff
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+const (
+	loopback = "127.0.0.1"
+)
+
+func newDummyReplica(t *testing.T) *Replica {
+	t.Helper()
+
+	var (
+		logger    = mocks.NoopLogger
+		executor  = mocks.BaselineExecutor(t)
+		clusterID = mocks.GenericUUID.String()
+		peers     = mocks.GenericPeerIDs[:4]
+	)
+
+	host, err := host.New(logger, loopback, 0)
+	require.NoError(t, err)
+
+	replica, err := NewReplica(logger, host, executor, peers, clusterID)
+	require.NoError(t, err)
+
+	return replica
+}
+
+func TestReplica_TooSmallCluster(t *testing.T) {
+
+	host, err := host.New(mocks.NoopLogger, loopback, 0)
+	require.NoError(t, err)
+
+	_, err = NewReplica(mocks.NoopLogger, host, mocks.BaselineExecutor(t), mocks.GenericPeerIDs[:3], mocks.GenericUUID.String())
+	require.Error(t, err)
+}
+
+func TestReplica_Leader(t *testing.T) {
+
+	replica := newDummyReplica(t)
+
+	for view := uint(0); view < 8; view++ {
+		require.Equal(t, replica.peers[view%4], replica.leader(view))
+	}
+
+	require.Equal(t, uint(1), replica.f)
+	require.Equal(t, uint(3), replica.quorum())
+	require.Equal(t, uint(2), MinClusterResults(4))
+	require.Equal(t, uint(3), MinClusterResults(7))
+}
+
+func TestSign_Vote(t *testing.T) {
+
+	replica := newDummyReplica(t)
+
+	vote := Vote{
+		View:   3,
+		Phase:  PhaseCommit,
+		Digest: "abcdef123456789",
+	}
+
+	err := replica.sign(&vote)
+	require.NoError(t, err)
+	require.NotEmpty(t, vote.Signature)
+
+	err = verifySignature(&vote, replica.id)
+	require.NoError(t, err)
+
+	vote.Phase = PhaseDecide
+	err = verifySignature(&vote, replica.id)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestReplica_VerifyCertificate(t *testing.T) {
+
+	const (
+		view   = 5
+		phase  = PhasePreCommit
+		digest = "abcdef123456789"
+	)
+
+	// Create replicas with real keys, so they can sign votes.
+	var (
+		hosts    []*host.Host
+		replicas []peer.ID
+	)
+	for i := 0; i < 4; i++ {
+		h, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hosts = append(hosts, h)
+		replicas = append(replicas, h.ID())
+	}
+
+	replica, err := NewReplica(mocks.NoopLogger, hosts[0], mocks.BaselineExecutor(t), replicas, mocks.GenericUUID.String())
+	require.NoError(t, err)
+
+	voteSignature := func(t *testing.T, h *host.Host) VoteSignature {
+		t.Helper()
+
+		vote := Vote{View: view, Phase: phase, Digest: digest}
+		payload := getDigest(vote.signableRecord())
+		sig, err := h.PrivateKey().Sign([]byte(payload))
+		require.NoError(t, err)
+
+		vote.setSignature(hex.EncodeToString(sig))
+		return VoteSignature{Replica: h.ID(), Signature: vote.Signature}
+	}
+
+	t.Run("nominal case", func(t *testing.T) {
+		qc := QuorumCertificate{
+			View:   view,
+			Phase:  phase,
+			Digest: digest,
+			Signatures: []VoteSignature{
+				voteSignature(t, hosts[0]),
+				voteSignature(t, hosts[1]),
+				voteSignature(t, hosts[2]),
+			},
+		}
+
+		err := replica.verifyCertificate(&qc)
+		require.NoError(t, err)
+	})
+	t.Run("not enough votes", func(t *testing.T) {
+		qc := QuorumCertificate{
+			View:   view,
+			Phase:  phase,
+			Digest: digest,
+			Signatures: []VoteSignature{
+				voteSignature(t, hosts[0]),
+				voteSignature(t, hosts[1]),
+			},
+		}
+
+		err := replica.verifyCertificate(&qc)
+		require.ErrorIs(t, err, ErrInvalidCertificate)
+	})
+	t.Run("duplicate votes do not count", func(t *testing.T) {
+		qc := QuorumCertificate{
+			View:   view,
+			Phase:  phase,
+			Digest: digest,
+			Signatures: []VoteSignature{
+				voteSignature(t, hosts[0]),
+				voteSignature(t, hosts[1]),
+				voteSignature(t, hosts[1]),
+			},
+		}
+
+		err := replica.verifyCertificate(&qc)
+		require.ErrorIs(t, err, ErrInvalidCertificate)
+	})
+	t.Run("votes for a different request", func(t *testing.T) {
+		qc := QuorumCertificate{
+			View:   view,
+			Phase:  phase,
+			Digest: "another-digest",
+			Signatures: []VoteSignature{
+				voteSignature(t, hosts[0]),
+				voteSignature(t, hosts[1]),
+				voteSignature(t, hosts[2]),
+			},
+		}
+
+		err := replica.verifyCertificate(&qc)
+		require.ErrorIs(t, err, ErrInvalidCertificate)
+	})
+	t.Run("vote from outside the cluster", func(t *testing.T) {
+		outsider, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		qc := QuorumCertificate{
+			View:   view,
+			Phase:  phase,
+			Digest: digest,
+			Signatures: []VoteSignature{
+				voteSignature(t, hosts[0]),
+				voteSignature(t, hosts[1]),
+				voteSignature(t, outsider),
+			},
+		}
+
+		err = replica.verifyCertificate(&qc)
+		require.ErrorIs(t, err, ErrInvalidCertificate)
+	})
+}
+
+func TestReplica_ProcessProposal(t *testing.T) {
+
+	var (
+		hosts    []*host.Host
+		replicas []peer.ID
+	)
+	for i := 0; i < 4; i++ {
+		h, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hosts = append(hosts, h)
+		replicas = append(replicas, h.ID())
+	}
+
+	replica, err := NewReplica(mocks.NoopLogger, hosts[0], mocks.BaselineExecutor(t), replicas, mocks.GenericUUID.String())
+	require.NoError(t, err)
+
+	// Proposals are signed by the leader of their view.
+	propose := func(t *testing.T, proposal Proposal) (peer.ID, Proposal) {
+		t.Helper()
+
+		leader := replica.leader(proposal.View)
+		for _, h := range hosts {
+			if h.ID() != leader {
+				continue
+			}
+
+			payload := getDigest(proposal.signableRecord())
+			sig, err := h.PrivateKey().Sign([]byte(payload))
+			require.NoError(t, err)
+
+			proposal.setSignature(hex.EncodeToString(sig))
+		}
+
+		return leader, proposal
+	}
+
+	t.Run("invalid proposal does not change the view", func(t *testing.T) {
+		from, proposal := propose(t, Proposal{View: 1, Phase: PhasePrepare, Digest: "abcdef123456789"})
+
+		err := replica.processProposal(context.Background(), from, proposal)
+		require.Error(t, err)
+		require.Zero(t, replica.view)
+	})
+	t.Run("skipping views requires a certificate", func(t *testing.T) {
+		from, proposal := propose(t, Proposal{View: 5, Phase: PhasePrepare, Digest: "abcdef123456789"})
+
+		err := replica.processProposal(context.Background(), from, proposal)
+		require.ErrorIs(t, err, ErrInvalidCertificate)
+		require.Zero(t, replica.view)
+	})
+	t.Run("views too far ahead are rejected", func(t *testing.T) {
+		for _, view := range []uint{maxViewsAhead + 1, math.MaxUint} {
+			from, proposal := propose(t, Proposal{View: view, Phase: PhasePrepare, Digest: "abcdef123456789"})
+
+			err := replica.processProposal(context.Background(), from, proposal)
+			require.ErrorIs(t, err, ErrFutureView)
+			require.Zero(t, replica.view)
+		}
+	})
+}
+
+func TestReplica_Execute(t *testing.T) {
+
+	t.Run("nominal case", func(t *testing.T) {
+		testClusterExecution(t, false)
+	})
+	t.Run("leader offline", func(t *testing.T) {
+		testClusterExecution(t, true)
+	})
+}
+
+// testClusterExecution executes a request on a four replica cluster. If the leader of the first view is offline,
+// the remaining replicas should move on to the next view and execute the request there.
+func testClusterExecution(t *testing.T, leaderOffline bool) {
+	t.Helper()
+
+	const (
+		clusterSize = 4
+		viewTimeout = 500 * time.Millisecond
+		timeout     = 10 * time.Second
+	)
+
+	var (
+		requestID = mocks.GenericUUID.String()
+		timestamp = time.Now().UTC()
+	)
+
+	client, err := host.New(mocks.NoopLogger, loopback, 0)
+	require.NoError(t, err)
+
+	var (
+		hosts []*host.Host
+		peers []peer.ID
+	)
+	for i := 0; i < clusterSize; i++ {
+		h, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hosts = append(hosts, h)
+		peers = append(peers, h.ID())
+	}
+
+	// Let everyone know how to reach everyone else.
+	all := append([]*host.Host{client}, hosts...)
+	for _, h := range all {
+		for _, other := range all {
+			if h.ID() != other.ID() {
+				h.Peerstore().AddAddrs(other.ID(), other.Addrs(), peerstore.PermanentAddrTTL)
+			}
+		}
+	}
+
+	// Leader of the first view is the first replica.
+	running := hosts
+	expectedView := uint(0)
+	if leaderOffline {
+		running = hosts[1:]
+		expectedView = 1
+	}
+
+	var (
+		lock    sync.Mutex
+		results = make(map[peer.ID]response.Execute)
+		done    = make(chan struct{})
+	)
+	client.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+		defer stream.Close()
+
+		payload, err := io.ReadAll(stream)
+		require.NoError(t, err)
+
+		var res response.Execute
+		err = json.Unmarshal(payload, &res)
+		require.NoError(t, err)
+
+		lock.Lock()
+		defer lock.Unlock()
+
+		results[stream.Conn().RemotePeer()] = res
+		if len(results) == len(running) {
+			close(done)
+		}
+	})
+
+	var replicas []*Replica
+	for _, h := range running {
+		replica, err := NewReplica(mocks.NoopLogger, h, mocks.BaselineExecutor(t), peers, requestID, WithViewTimeout(viewTimeout))
+		require.NoError(t, err)
+
+		replicas = append(replicas, replica)
+	}
+
+	req := mocks.GenericExecutionRequest
+	err = req.Sign(client.PrivateKey())
+	require.NoError(t, err)
+
+	for _, replica := range replicas {
+		code, _, err := replica.Execute(client.ID(), requestID, timestamp, req)
+		require.NoError(t, err)
+		require.Equal(t, codes.NoContent, code)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		t.Fatalf("timed out waiting for execution results")
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	for id, res := range results {
+		require.Equal(t, requestID, res.RequestID)
+
+		nres, ok := res.Results[id]
+		require.True(t, ok)
+		require.Equal(t, mocks.GenericExecutionResult.Result, nres.Result.Result)
+		require.Equal(t, timestamp, nres.HotStuff.RequestTimestamp)
+		require.Equal(t, expectedView, nres.HotStuff.View)
+	}
+
+	for _, replica := range replicas {
+		require.NoError(t, replica.Shutdown())
+	}
+}
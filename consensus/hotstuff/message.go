@@ -0,0 +1,218 @@
+package hotstuff
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/telemetry/tracing"
+)
+
+type HotStuffMessage interface {
+	Type() MessageType
+}
+
+type BaseMessage struct {
+	tracing.TraceInfo
+}
+
+func (m *BaseMessage) SaveTraceContext(t tracing.TraceInfo) {
+	m.TraceInfo = t
+}
+
+type MessageType uint
+
+const (
+	MessageRequest MessageType = iota + 1
+	MessageProposal
+	MessageVote
+	MessageNewView
+)
+
+func (m MessageType) String() string {
+	switch m {
+	case MessageRequest:
+		return "MessageRequest"
+	case MessageProposal:
+		return "MessageProposal"
+	case MessageVote:
+		return "MessageVote"
+	case MessageNewView:
+		return "MessageNewView"
+	default:
+		return fmt.Sprintf("unknown: %d", m)
+	}
+}
+
+// Phase is a step in reaching a decision on a request. The leader drives each phase by collecting votes from replicas.
+type Phase uint
+
+const (
+	PhasePrepare Phase = iota + 1
+	PhasePreCommit
+	PhaseCommit
+	PhaseDecide
+)
+
+func (p Phase) String() string {
+	switch p {
+	case PhasePrepare:
+		return "prepare"
+	case PhasePreCommit:
+		return "pre-commit"
+	case PhaseCommit:
+		return "commit"
+	case PhaseDecide:
+		return "decide"
+	default:
+		return fmt.Sprintf("unknown: %d", p)
+	}
+}
+
+// next returns the phase the leader moves to once it has a quorum of votes for this one.
+func (p Phase) next() Phase {
+	return p + 1
+}
+
+type Request struct {
+	BaseMessage
+	ID        string          `json:"id"`
+	Timestamp time.Time       `json:"timestamp"`
+	Origin    peer.ID         `json:"origin"`
+	Execute   execute.Request `json:"execute"`
+}
+
+func (r Request) Type() MessageType {
+	return MessageRequest
+}
+
+// Proposal is broadcast by the leader to start a phase. Prepare proposals carry the request, all later phases carry
+// the quorum certificate for the previous phase.
+type Proposal struct {
+	BaseMessage
+	View    uint               `json:"view"`
+	Phase   Phase              `json:"phase"`
+	Digest  string             `json:"digest"`
+	Request *Request           `json:"request,omitempty"`
+	Justify *QuorumCertificate `json:"justify,omitempty"`
+
+	// Signed digest of the proposal.
+	Signature string `json:"signature,omitempty"`
+}
+
+func (p Proposal) Type() MessageType {
+	return MessageProposal
+}
+
+// Vote is sent by a replica to the leader only, which keeps the message complexity linear in the cluster size.
+type Vote struct {
+	BaseMessage
+	View   uint   `json:"view"`
+	Phase  Phase  `json:"phase"`
+	Digest string `json:"digest"`
+
+	// Signed digest of the vote.
+	Signature string `json:"signature,omitempty"`
+}
+
+func (v Vote) Type() MessageType {
+	return MessageVote
+}
+
+// NewView is sent to the leader of the next view, along with the highest prepare certificate the replica knows of.
+type NewView struct {
+	BaseMessage
+	View    uint               `json:"view"`
+	Justify *QuorumCertificate `json:"justify,omitempty"`
+
+	// Signed digest of the new view message.
+	Signature string `json:"signature,omitempty"`
+}
+
+func (v NewView) Type() MessageType {
+	return MessageNewView
+}
+
+// QuorumCertificate proves that a quorum of replicas voted for the request in a phase of a view.
+type QuorumCertificate struct {
+	View       uint            `json:"view"`
+	Phase      Phase           `json:"phase"`
+	Digest     string          `json:"digest"`
+	Signatures []VoteSignature `json:"signatures"`
+}
+
+// VoteSignature is the signature of a single vote that is part of a quorum certificate.
+type VoteSignature struct {
+	Replica   peer.ID `json:"replica"`
+	Signature string  `json:"signature"`
+}
+
+// messageRecord is used as an interim format to supplement the original type with its type.
+type messageRecord struct {
+	Type MessageType `json:"type"`
+	Data any         `json:"data"`
+}
+
+// messageEnvelope is used as an interim format to extract the original type from the `messageRecord` format.
+type messageEnvelope struct {
+	Type MessageType     `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+func packMessage(msg HotStuffMessage) ([]byte, error) {
+
+	rec := messageRecord{
+		Type: msg.Type(),
+		Data: msg,
+	}
+
+	return json.Marshal(rec)
+}
+
+func unpackMessage(payload []byte) (HotStuffMessage, error) {
+
+	var msg messageEnvelope
+	err := json.Unmarshal(payload, &msg)
+	if err != nil {
+		return nil, fmt.Errorf("could not unpack base message: %w", err)
+	}
+
+	switch msg.Type {
+	case MessageRequest:
+		var request Request
+		err = json.Unmarshal(msg.Data, &request)
+		if err != nil {
+			return nil, fmt.Errorf("could not unpack request: %w", err)
+		}
+		return request, nil
+
+	case MessageProposal:
+		var proposal Proposal
+		err = json.Unmarshal(msg.Data, &proposal)
+		if err != nil {
+			return nil, fmt.Errorf("could not unpack proposal: %w", err)
+		}
+		return proposal, nil
+
+	case MessageVote:
+		var vote Vote
+		err = json.Unmarshal(msg.Data, &vote)
+		if err != nil {
+			return nil, fmt.Errorf("could not unpack vote: %w", err)
+		}
+		return vote, nil
+
+	case MessageNewView:
+		var newView NewView
+		err = json.Unmarshal(msg.Data, &newView)
+		if err != nil {
+			return nil, fmt.Errorf("could not unpack new view message: %w", err)
+		}
+		return newView, nil
+	}
+
+	return nil, fmt.Errorf("unexpected message type (type: %v)", msg.Type)
+}
@@ -0,0 +1,131 @@
+package hotstuff
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/telemetry/tracing"
+)
+
+const (
+	spanMessageSend      = "MessageSend"
+	spanMessageBroadcast = "MessageBroadcast"
+)
+
+// send sends the message to a single replica in the cluster.
+func (r *Replica) send(ctx context.Context, to peer.ID, msg HotStuffMessage) error {
+
+	ctx, span := r.tracer.Start(ctx, msgSendSpanName(msg, spanMessageSend))
+	defer span.End()
+
+	saveTraceContext(ctx, msg)
+
+	payload, err := packMessage(msg)
+	if err != nil {
+		return fmt.Errorf("could not encode record: %w", err)
+	}
+
+	// We don't want to wait indefinitely.
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.NetworkTimeout)
+	defer cancel()
+
+	err = r.host.SendMessageOnProtocol(ctx, to, payload, r.protocolID)
+	if err != nil {
+		return fmt.Errorf("could not send message: %w", err)
+	}
+
+	return nil
+}
+
+// broadcast sends the message to all other replicas in the cluster.
+func (r *Replica) broadcast(ctx context.Context, msg HotStuffMessage) error {
+
+	ctx, span := r.tracer.Start(ctx, msgSendSpanName(msg, spanMessageBroadcast))
+	defer span.End()
+
+	saveTraceContext(ctx, msg)
+
+	payload, err := packMessage(msg)
+	if err != nil {
+		return fmt.Errorf("could not encode record: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.NetworkTimeout)
+	defer cancel()
+
+	var errGroup multierror.Group
+	for _, target := range r.peers {
+
+		// Skip self.
+		if target == r.id {
+			continue
+		}
+
+		errGroup.Go(func() error {
+			err := r.host.SendMessageOnProtocol(ctx, target, payload, r.protocolID)
+			if err != nil {
+				return fmt.Errorf("peer send error (peer: %v): %w", target.String(), err)
+			}
+
+			return nil
+		})
+	}
+
+	sendErr := errGroup.Wait()
+	if sendErr.ErrorOrNil() == nil {
+		return nil
+	}
+
+	errCount := uint(sendErr.Len())
+	if errCount > r.f {
+		r.log.Warn().Uint("f", r.f).Uint("errors", errCount).Msg("broadcast error count higher than HotStuff f value")
+	}
+
+	return fmt.Errorf("could not broadcast message: %w", sendErr)
+}
+
+// sendResult sends the execution result to the client, on the standard protocol.
+func (r *Replica) sendResult(ctx context.Context, to peer.ID, msg *response.Execute) error {
+
+	ctx, span := r.tracer.Start(ctx, fmt.Sprintf("HotStuffMessage %s %s", spanMessageSend, msg.Type()))
+	defer span.End()
+
+	saveTraceContext(ctx, msg)
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("could not encode record: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.NetworkTimeout)
+	defer cancel()
+
+	err = r.host.SendMessageOnProtocol(ctx, to, payload, blockless.ProtocolID)
+	if err != nil {
+		return fmt.Errorf("could not send message: %w", err)
+	}
+
+	return nil
+}
+
+func saveTraceContext(ctx context.Context, msg any) {
+	tmsg, ok := msg.(blockless.TraceableMessage)
+	if !ok {
+		return
+	}
+
+	t := tracing.GetTraceInfo(ctx)
+	if !t.Empty() {
+		tmsg.SaveTraceContext(t)
+	}
+}
+
+func msgSendSpanName(msg HotStuffMessage, action string) string {
+	return fmt.Sprintf("HotStuffMessage %s %s", action, msg.Type().String())
+}
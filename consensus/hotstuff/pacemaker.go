@@ -0,0 +1,198 @@
+package hotstuff
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/telemetry/tracing"
+)
+
+func (r *Replica) startViewTimer(overrideExisting bool) {
+
+	if r.viewTimer != nil && !overrideExisting {
+		r.log.Debug().Msg("view timer running, not overriding")
+		return
+	}
+
+	if r.viewTimer != nil {
+		r.viewTimer.Stop()
+	}
+
+	// Evaluate the view number now. Potentially, we could've already advanced
+	// to the next view before our timer fires.
+	targetView := r.view + 1
+
+	r.viewTimer = time.AfterFunc(r.cfg.ViewTimeout, func() {
+		r.sl.Lock()
+		defer r.sl.Unlock()
+
+		if r.view >= targetView {
+			return
+		}
+
+		r.log.Info().Uint("view", r.view).Msg("view timed out, moving to the next leader")
+
+		r.viewTimer = nil
+
+		ctx := tracing.TraceContext(context.Background(), r.cfg.TraceInfo)
+		err := r.nextView(ctx, targetView)
+		if err != nil {
+			r.log.Error().Err(err).Uint("view", targetView).Msg("could not move to next view")
+		}
+	})
+
+	r.log.Debug().Msg("view timer started")
+}
+
+func (r *Replica) stopViewTimer() {
+
+	if r.viewTimer == nil {
+		return
+	}
+
+	r.viewTimer.Stop()
+	r.viewTimer = nil
+
+	r.log.Debug().Msg("view timer stopped")
+}
+
+// checkFutureView rejects views too far ahead of the current one, and the last view, since there is no view after it.
+func (r *Replica) checkFutureView(view uint) error {
+
+	if view == math.MaxUint || (view > r.view && view-r.view > maxViewsAhead) {
+		return fmt.Errorf("view too far ahead (view: %v, current: %v): %w", view, r.view, ErrFutureView)
+	}
+
+	return nil
+}
+
+// advanceView moves the replica to the given view. Leader of the new view has to collect new view messages before proposing.
+func (r *Replica) advanceView(view uint) {
+
+	r.view = view
+	r.ready = false
+
+	// Votes and new view messages for past views are of no use anymore.
+	for id := range r.votes {
+		if id.view < view {
+			delete(r.votes, id)
+		}
+	}
+	for v := range r.newViews {
+		if v < view {
+			delete(r.newViews, v)
+		}
+	}
+}
+
+// nextView moves the replica to the given view and lets the new leader know about the highest prepare certificate we have.
+func (r *Replica) nextView(ctx context.Context, view uint) error {
+
+	r.advanceView(view)
+
+	// Keep the timer running only if there's work left.
+	if len(r.pending) > 0 {
+		r.startViewTimer(true)
+	} else {
+		r.stopViewTimer()
+	}
+
+	newView := NewView{
+		View:    view,
+		Justify: r.highQC,
+	}
+
+	err := r.sign(&newView)
+	if err != nil {
+		return fmt.Errorf("could not sign new view message: %w", err)
+	}
+
+	leader := r.leader(view)
+
+	r.log.Debug().Uint("view", view).Str("leader", leader.String()).Msg("sending new view message")
+
+	if leader == r.id {
+		return r.processNewView(ctx, r.id, newView)
+	}
+
+	err = r.send(ctx, leader, &newView)
+	if err != nil {
+		return fmt.Errorf("could not send new view message to leader (leader: %s): %w", leader, err)
+	}
+
+	return nil
+}
+
+// processNewView collects new view messages as the leader. Once a quorum of replicas has moved to the view,
+// the leader learns the highest prepare certificate and may start proposing.
+func (r *Replica) processNewView(ctx context.Context, from peer.ID, newView NewView) error {
+
+	if r.leader(newView.View) != r.id {
+		return fmt.Errorf("received new view message for a view we do not lead (view: %v): %w", newView.View, ErrNotLeader)
+	}
+
+	if newView.View < r.view {
+		return fmt.Errorf("new view message for a past view (view: %v, current: %v): %w", newView.View, r.view, ErrStaleView)
+	}
+
+	err := r.checkFutureView(newView.View)
+	if err != nil {
+		return err
+	}
+
+	err = verifySignature(&newView, from)
+	if err != nil {
+		return fmt.Errorf("could not verify new view signature: %w", err)
+	}
+
+	if newView.Justify != nil {
+		if newView.Justify.Phase != PhasePrepare {
+			return fmt.Errorf("%w: new view message must carry a prepare certificate", ErrInvalidCertificate)
+		}
+
+		err = r.verifyCertificate(newView.Justify)
+		if err != nil {
+			return fmt.Errorf("invalid new view certificate: %w", err)
+		}
+	}
+
+	received, ok := r.newViews[newView.View]
+	if !ok {
+		received = make(map[peer.ID]NewView)
+		r.newViews[newView.View] = received
+	}
+
+	_, seen := received[from]
+	if seen {
+		return nil
+	}
+	received[from] = newView
+
+	// Only act once, when we reach the quorum.
+	if uint(len(received)) != r.quorum() {
+		return nil
+	}
+
+	r.log.Info().Uint("view", newView.View).Msg("have quorum of new view messages, taking over as leader")
+
+	if newView.View > r.view {
+		r.advanceView(newView.View)
+		if len(r.pending) > 0 {
+			r.startViewTimer(true)
+		}
+	}
+
+	for _, nv := range received {
+		if nv.Justify != nil {
+			r.updateHighQC(nv.Justify)
+		}
+	}
+
+	r.ready = true
+
+	return r.proposeNext(ctx)
+}
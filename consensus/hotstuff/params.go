@@ -0,0 +1,48 @@
+package hotstuff
+
+import (
+	"errors"
+	"time"
+
+	"github.com/armon/go-metrics/prometheus"
+	"github.com/libp2p/go-libp2p/core/protocol"
+)
+
+const (
+	// Protocol to use for HotStuff related communication.
+	Protocol protocol.ID = "/b7s/consensus/hotstuff/1.0.0"
+
+	// HotStuff offers no resiliency towards Byzantine nodes with less than four nodes.
+	MinimumReplicaCount = 4
+
+	// How long do the send/broadcast operation have until we consider it failed.
+	NetworkTimeout = 5 * time.Second
+
+	// How long does a view last without a decision before replicas move on to the next leader.
+	ViewTimeout = 10 * time.Second
+
+	// How many views ahead of the current one can messages be. Honest replicas only move one view at a time, on decisions and timeouts.
+	maxViewsAhead = 100
+
+	tracerName = "b7s.HotStuffCluster"
+)
+
+var (
+	ErrNotLeader          = errors.New("replica is not the leader of the view")
+	ErrStaleView          = errors.New("message is for a past view")
+	ErrInvalidSignature   = errors.New("invalid signature")
+	ErrInvalidCertificate = errors.New("invalid quorum certificate")
+	ErrUnsafeProposal     = errors.New("proposal conflicts with the locked request")
+	ErrFutureView         = errors.New("message is for a view too far ahead")
+)
+
+var (
+	hotstuffExecutionsTimeMetric = []string{"hotstuff", "execute", "milliseconds"}
+)
+
+var Summaries = []prometheus.SummaryDefinition{
+	{
+		Name: hotstuffExecutionsTimeMetric,
+		Help: "Time needed to reach HotStuff consensus.",
+	},
+}
@@ -0,0 +1,379 @@
+package hotstuff
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// proposeNext starts the prepare phase for a pending request, if we are the leader of the current view and have not proposed yet.
+// A request the highest prepare certificate is for takes precedence, since replicas may be locked on it.
+func (r *Replica) proposeNext(ctx context.Context) error {
+
+	if !r.isLeader() || !r.ready {
+		return nil
+	}
+
+	_, proposed := r.proposals[r.view]
+	if proposed {
+		return nil
+	}
+
+	var (
+		request Request
+		found   bool
+	)
+
+	if r.highQC != nil {
+		request, found = r.pending[r.highQC.Digest]
+	}
+
+	// Otherwise, propose the oldest pending request.
+	if !found {
+		for _, pending := range r.pending {
+			if !found || pending.Timestamp.Before(request.Timestamp) {
+				request = pending
+				found = true
+			}
+		}
+	}
+
+	if !found {
+		return nil
+	}
+
+	digest := requestDigest(request)
+	r.proposals[r.view] = digest
+
+	r.log.Info().Uint("view", r.view).Str("request", request.ID).Str("digest", digest).Msg("we are the leader, proposing request")
+
+	proposal := Proposal{
+		View:    r.view,
+		Phase:   PhasePrepare,
+		Digest:  digest,
+		Request: &request,
+		Justify: r.highQC,
+	}
+
+	return r.propose(ctx, proposal)
+}
+
+// propose signs the proposal, broadcasts it to the replicas and processes it as a replica too.
+func (r *Replica) propose(ctx context.Context, proposal Proposal) error {
+
+	err := r.sign(&proposal)
+	if err != nil {
+		return fmt.Errorf("could not sign proposal: %w", err)
+	}
+
+	err = r.broadcast(ctx, &proposal)
+	if err != nil {
+		// Not fatal - we might still reach a quorum with the replicas we did reach.
+		r.log.Warn().Err(err).Uint("view", proposal.View).Stringer("phase", proposal.Phase).Msg("could not broadcast proposal to all replicas")
+	}
+
+	return r.processProposal(ctx, r.id, proposal)
+}
+
+func (r *Replica) processProposal(ctx context.Context, from peer.ID, proposal Proposal) error {
+
+	log := r.log.With().Uint("view", proposal.View).Stringer("phase", proposal.Phase).Str("digest", proposal.Digest).Logger()
+
+	log.Debug().Msg("received proposal")
+
+	if from != r.leader(proposal.View) {
+		return fmt.Errorf("proposal not sent by the leader of the view (view: %v, leader: %s): %w", proposal.View, r.leader(proposal.View), ErrNotLeader)
+	}
+
+	// A decision stands even if we moved on from its view in the meantime.
+	if proposal.View < r.view && proposal.Phase != PhaseDecide {
+		return fmt.Errorf("proposal for a past view (view: %v, current: %v): %w", proposal.View, r.view, ErrStaleView)
+	}
+
+	err := r.checkFutureView(proposal.View)
+	if err != nil {
+		return err
+	}
+
+	err = verifySignature(&proposal, from)
+	if err != nil {
+		return fmt.Errorf("could not verify proposal signature: %w", err)
+	}
+
+	// Skipping views is only accepted with a certificate showing progress was made without us.
+	if proposal.View > r.view+1 && proposal.Justify == nil {
+		return fmt.Errorf("%w: proposal skipping views must carry a certificate (view: %v, current: %v)", ErrInvalidCertificate, proposal.View, r.view)
+	}
+
+	// Validate the proposal before acting on it, so an invalid proposal cannot move us to another view.
+	switch proposal.Phase {
+	case PhasePrepare:
+		err = r.checkPrepare(proposal)
+		if err != nil {
+			return fmt.Errorf("invalid prepare proposal: %w", err)
+		}
+
+	case PhasePreCommit, PhaseCommit, PhaseDecide:
+		err = r.checkJustify(proposal)
+		if err != nil {
+			return fmt.Errorf("invalid %s proposal: %w", proposal.Phase, err)
+		}
+
+	default:
+		return fmt.Errorf("unexpected proposal phase (phase: %v)", proposal.Phase)
+	}
+
+	// Catch up with the leader, if we fell behind.
+	if proposal.View > r.view {
+		log.Info().Uint("current_view", r.view).Msg("leader is in a later view, moving to it")
+		r.advanceView(proposal.View)
+	}
+
+	switch proposal.Phase {
+
+	case PhasePrepare:
+		digest := proposal.Digest
+		_, known := r.requests[digest]
+		if !known {
+			r.requests[digest] = *proposal.Request
+			r.pending[digest] = *proposal.Request
+		}
+		r.proposals[proposal.View] = digest
+
+		if proposal.Justify != nil {
+			r.updateHighQC(proposal.Justify)
+		}
+
+	case PhasePreCommit:
+		r.updateHighQC(proposal.Justify)
+
+	case PhaseCommit:
+		r.lockedQC = proposal.Justify
+
+	case PhaseDecide:
+		log.Info().Msg("request decided")
+
+		err = r.execute(ctx, proposal.View, proposal.Digest)
+		if err != nil {
+			return fmt.Errorf("could not execute request: %w", err)
+		}
+
+		if proposal.View < r.view {
+			return nil
+		}
+
+		// Hand over to the next leader.
+		return r.nextView(ctx, proposal.View+1)
+	}
+
+	return r.vote(ctx, proposal.View, proposal.Phase, proposal.Digest)
+}
+
+// checkPrepare verifies the request in the prepare proposal, and that voting for it is safe - we either are not locked
+// on a different request, or the leader shows we are locked on a stale one.
+func (r *Replica) checkPrepare(proposal Proposal) error {
+
+	if proposal.Request == nil {
+		return fmt.Errorf("proposal has no request")
+	}
+
+	if requestDigest(*proposal.Request) != proposal.Digest {
+		return fmt.Errorf("request does not match proposal digest")
+	}
+
+	pub, err := proposal.Request.Origin.ExtractPublicKey()
+	if err != nil {
+		return fmt.Errorf("could not extract public key from client: %w", err)
+	}
+
+	err = proposal.Request.Execute.VerifySignature(pub)
+	if err != nil {
+		return fmt.Errorf("request is not properly signed by the client: %w", err)
+	}
+
+	if proposal.Justify != nil {
+		if proposal.Justify.Phase != PhasePrepare {
+			return fmt.Errorf("%w: prepare proposal must be justified by a prepare certificate", ErrInvalidCertificate)
+		}
+
+		err = r.verifyCertificate(proposal.Justify)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !r.safeProposal(proposal) {
+		return ErrUnsafeProposal
+	}
+
+	return nil
+}
+
+// safeProposal implements the HotStuff safety rule.
+func (r *Replica) safeProposal(proposal Proposal) bool {
+
+	if r.lockedQC == nil || r.lockedQC.Digest == proposal.Digest {
+		return true
+	}
+
+	// Request we are locked on has been decided already.
+	if r.decided(r.lockedQC.Digest) {
+		return true
+	}
+
+	return proposal.Justify != nil && proposal.Justify.View > r.lockedQC.View
+}
+
+// checkJustify verifies that the proposal carries a certificate for the previous phase of the same view and request.
+func (r *Replica) checkJustify(proposal Proposal) error {
+
+	qc := proposal.Justify
+	if qc == nil {
+		return fmt.Errorf("%w: missing certificate", ErrInvalidCertificate)
+	}
+
+	if qc.View != proposal.View || qc.Digest != proposal.Digest || qc.Phase.next() != proposal.Phase {
+		return fmt.Errorf("%w: certificate does not match proposal (view: %v, phase: %s)", ErrInvalidCertificate, qc.View, qc.Phase)
+	}
+
+	digest, ok := r.proposals[proposal.View]
+	if !ok || digest != proposal.Digest {
+		return fmt.Errorf("proposal does not match the request prepared in this view")
+	}
+
+	return r.verifyCertificate(qc)
+}
+
+// vote sends our vote for the phase to the leader of the view.
+func (r *Replica) vote(ctx context.Context, view uint, phase Phase, digest string) error {
+
+	id := voteID{view: view, phase: phase}
+	_, voted := r.voted[id]
+	if voted {
+		return nil
+	}
+	r.voted[id] = struct{}{}
+
+	vote := Vote{
+		View:   view,
+		Phase:  phase,
+		Digest: digest,
+	}
+
+	err := r.sign(&vote)
+	if err != nil {
+		return fmt.Errorf("could not sign vote: %w", err)
+	}
+
+	leader := r.leader(view)
+	if leader == r.id {
+		return r.processVote(ctx, r.id, vote)
+	}
+
+	err = r.send(ctx, leader, &vote)
+	if err != nil {
+		return fmt.Errorf("could not send vote to leader (leader: %s): %w", leader, err)
+	}
+
+	return nil
+}
+
+// processVote collects votes as the leader. Once a quorum voted in a phase, the leader starts the next phase.
+func (r *Replica) processVote(ctx context.Context, from peer.ID, vote Vote) error {
+
+	if r.leader(vote.View) != r.id {
+		return fmt.Errorf("received vote for a view we do not lead (view: %v): %w", vote.View, ErrNotLeader)
+	}
+
+	if vote.View != r.view {
+		return fmt.Errorf("vote for a different view (view: %v, current: %v): %w", vote.View, r.view, ErrStaleView)
+	}
+
+	digest, ok := r.proposals[vote.View]
+	if !ok || digest != vote.Digest {
+		return fmt.Errorf("vote for a request we did not propose (digest: %s)", vote.Digest)
+	}
+
+	err := verifySignature(&vote, from)
+	if err != nil {
+		return fmt.Errorf("could not verify vote signature: %w", err)
+	}
+
+	id := voteID{view: vote.View, phase: vote.Phase}
+	votes, ok := r.votes[id]
+	if !ok {
+		votes = make(map[peer.ID]Vote)
+		r.votes[id] = votes
+	}
+
+	_, seen := votes[from]
+	if seen {
+		return nil
+	}
+	votes[from] = vote
+
+	r.log.Debug().Uint("view", vote.View).Stringer("phase", vote.Phase).Int("votes", len(votes)).Uint("quorum", r.quorum()).Msg("accounted vote")
+
+	// Only act once, when we reach the quorum.
+	if uint(len(votes)) != r.quorum() {
+		return nil
+	}
+
+	qc := QuorumCertificate{
+		View:       vote.View,
+		Phase:      vote.Phase,
+		Digest:     vote.Digest,
+		Signatures: make([]VoteSignature, 0, len(votes)),
+	}
+	for replica, v := range votes {
+		qc.Signatures = append(qc.Signatures, VoteSignature{Replica: replica, Signature: v.Signature})
+	}
+	slices.SortFunc(qc.Signatures, func(a, b VoteSignature) int {
+		return compareIDs(a.Replica, b.Replica)
+	})
+
+	r.log.Info().Uint("view", vote.View).Stringer("phase", vote.Phase).Msg("have quorum of votes, starting next phase")
+
+	proposal := Proposal{
+		View:    vote.View,
+		Phase:   vote.Phase.next(),
+		Digest:  vote.Digest,
+		Justify: &qc,
+	}
+
+	return r.propose(ctx, proposal)
+}
+
+// updateHighQC records the prepare certificate, if it is newer than the one we have.
+func (r *Replica) updateHighQC(qc *QuorumCertificate) {
+
+	if qc.Phase != PhasePrepare {
+		return
+	}
+
+	if r.highQC == nil || qc.View > r.highQC.View {
+		r.highQC = qc
+	}
+}
+
+// decided returns true if the request was already decided and executed.
+func (r *Replica) decided(digest string) bool {
+
+	_, known := r.requests[digest]
+	_, pending := r.pending[digest]
+
+	return known && !pending
+}
+
+func compareIDs(a peer.ID, b peer.ID) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
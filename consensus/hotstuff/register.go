@@ -0,0 +1,30 @@
+package hotstuff
+
+import (
+	"github.com/blocklessnetwork/b7s/consensus"
+)
+
+func init() {
+	consensus.Register(consensus.HotStuff, "HotStuff", createReplica, consensus.WithMinimumReplicas(MinimumReplicaCount))
+}
+
+func createReplica(env consensus.Environment, cluster consensus.Cluster) (consensus.Replica, error) {
+
+	opts := []Option{
+		WithTraceInfo(env.TraceInfo),
+	}
+
+	if env.Executed != nil {
+		opts = append(opts, WithPostProcessors(env.Executed))
+	}
+
+	if env.MetadataProvider != nil {
+		opts = append(opts, WithMetadataProvider(env.MetadataProvider))
+	}
+
+	if cluster.RequestTimeout > 0 {
+		opts = append(opts, WithViewTimeout(cluster.RequestTimeout))
+	}
+
+	return NewReplica(env.Log, env.Host, env.Executor, cluster.Peers, cluster.ID, opts...)
+}
@@ -0,0 +1,143 @@
+package hotstuff
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+type signable interface {
+	signableRecord() any
+	setSignature(string)
+	getSignature() string
+}
+
+// Returns the payload that is eligible to be signed. This means basically the Proposal struct, excluding the signature field.
+func (p *Proposal) signableRecord() any {
+	cp := *p
+	cp.setSignature("")
+	cp.BaseMessage = BaseMessage{}
+	return cp
+}
+
+func (p *Proposal) setSignature(signature string) {
+	p.Signature = signature
+}
+
+func (p Proposal) getSignature() string {
+	return p.Signature
+}
+
+// Returns the payload that is eligible to be signed. This means basically the Vote struct, excluding the signature field.
+func (v *Vote) signableRecord() any {
+	cp := *v
+	cp.setSignature("")
+	cp.BaseMessage = BaseMessage{}
+	return cp
+}
+
+func (v *Vote) setSignature(signature string) {
+	v.Signature = signature
+}
+
+func (v Vote) getSignature() string {
+	return v.Signature
+}
+
+// Returns the payload that is eligible to be signed. This means basically the NewView struct, excluding the signature field.
+func (v *NewView) signableRecord() any {
+	cp := *v
+	cp.setSignature("")
+	cp.BaseMessage = BaseMessage{}
+	return cp
+}
+
+func (v *NewView) setSignature(signature string) {
+	v.Signature = signature
+}
+
+func (v NewView) getSignature() string {
+	return v.Signature
+}
+
+func (r *Replica) sign(rec signable) error {
+
+	digest := getDigest(rec.signableRecord())
+	sig, err := r.host.PrivateKey().Sign([]byte(digest))
+	if err != nil {
+		return fmt.Errorf("could not sign message: %w", err)
+	}
+
+	rec.setSignature(hex.EncodeToString(sig))
+
+	return nil
+}
+
+func verifySignature(rec signable, signer peer.ID) error {
+
+	// Get the digest of the message, excluding the signature.
+	digest := getDigest(rec.signableRecord())
+
+	pub, err := signer.ExtractPublicKey()
+	if err != nil {
+		return fmt.Errorf("could not extract public key from peer ID (id: %s): %w", signer.String(), err)
+	}
+
+	sig, err := hex.DecodeString(rec.getSignature())
+	if err != nil {
+		return fmt.Errorf("could not decode signature from hex: %w", err)
+	}
+
+	ok, err := pub.Verify([]byte(digest), sig)
+	if err != nil {
+		return fmt.Errorf("could not verify signature: %w", err)
+	}
+
+	if !ok {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// verifyCertificate checks that the certificate holds valid votes of a quorum of distinct replicas in the cluster.
+func (r *Replica) verifyCertificate(qc *QuorumCertificate) error {
+
+	voters := make(map[peer.ID]struct{}, len(qc.Signatures))
+	for _, sig := range qc.Signatures {
+
+		if !r.isReplica(sig.Replica) {
+			return fmt.Errorf("%w: vote from a peer not in the cluster (peer: %s)", ErrInvalidCertificate, sig.Replica)
+		}
+
+		vote := Vote{
+			View:      qc.View,
+			Phase:     qc.Phase,
+			Digest:    qc.Digest,
+			Signature: sig.Signature,
+		}
+
+		err := verifySignature(&vote, sig.Replica)
+		if err != nil {
+			return fmt.Errorf("%w: invalid vote signature (peer: %s): %w", ErrInvalidCertificate, sig.Replica, err)
+		}
+
+		voters[sig.Replica] = struct{}{}
+	}
+
+	if uint(len(voters)) < r.quorum() {
+		return fmt.Errorf("%w: not enough votes (have: %v, need: %v)", ErrInvalidCertificate, len(voters), r.quorum())
+	}
+
+	return nil
+}
+
+func getDigest(rec any) string {
+	payload, _ := json.Marshal(rec)
+	hash := sha256.Sum256(payload)
+
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,31 @@
+package consensus
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// Kinds of replica misbehavior.
+const (
+	// MisbehaviorConflictingProposal means the replica proposed different requests for the same slot.
+	MisbehaviorConflictingProposal = "conflicting-proposal"
+	// MisbehaviorConflictingVote means the replica voted for different requests for the same slot.
+	MisbehaviorConflictingVote = "conflicting-vote"
+	// MisbehaviorInvalidSignature means the replica sent a message with an invalid signature.
+	MisbehaviorInvalidSignature = "invalid-signature"
+)
+
+// Misbehavior describes a cluster member caught misbehaving, along with the evidence for it.
+type Misbehavior struct {
+	Consensus Type      `json:"consensus"`
+	Cluster   string    `json:"cluster"`
+	Replica   peer.ID   `json:"replica"`
+	Kind      string    `json:"kind"`
+	View      uint      `json:"view"`
+	Sequence  uint      `json:"sequence"`
+	Detected  time.Time `json:"detected"`
+	// Evidence holds the offending messages, as received from the replica.
+	Evidence []json.RawMessage `json:"evidence,omitempty"`
+}
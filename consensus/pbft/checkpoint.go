@@ -0,0 +1,193 @@
+package pbft
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// sendCheckpoint broadcasts the digest of our state after executing the request with the given sequence number.
+func (r *Replica) sendCheckpoint(ctx context.Context, sequence uint) error {
+
+	msg := Checkpoint{
+		SequenceNumber: sequence,
+		Digest:         r.stateDigest,
+	}
+
+	log := r.log.With().Uint("sequence_number", msg.SequenceNumber).Str("digest", msg.Digest).Logger()
+
+	err := r.sign(&msg)
+	if err != nil {
+		return fmt.Errorf("could not sign checkpoint message: %w", err)
+	}
+
+	log.Info().Msg("broadcasting checkpoint message")
+
+	err = r.broadcast(ctx, &msg)
+	if err != nil {
+		return fmt.Errorf("could not broadcast checkpoint message: %w", err)
+	}
+
+	log.Info().Msg("checkpoint message successfully broadcast")
+
+	r.recordCheckpointReceipt(r.id, msg)
+	r.maybeStabilizeCheckpoint(msg.SequenceNumber)
+
+	return nil
+}
+
+func (r *Replica) processCheckpoint(ctx context.Context, replica peer.ID, msg Checkpoint) error {
+
+	log := r.log.With().Str("replica", replica.String()).Uint("sequence_number", msg.SequenceNumber).Str("digest", msg.Digest).Logger()
+
+	log.Info().Msg("received checkpoint message")
+
+	if msg.SequenceNumber <= r.lowWatermark {
+		log.Debug().Uint("low_watermark", r.lowWatermark).Msg("checkpoint older than the last stable checkpoint, dropping")
+		return nil
+	}
+
+	err := r.verifySignature(&msg, replica)
+	if err != nil {
+		return fmt.Errorf("checkpoint message signature not valid: %w", err)
+	}
+
+	r.recordCheckpointReceipt(replica, msg)
+	r.maybeStabilizeCheckpoint(msg.SequenceNumber)
+
+	return nil
+}
+
+func (r *Replica) recordCheckpointReceipt(replica peer.ID, checkpoint Checkpoint) {
+
+	checkpoints, ok := r.checkpoints[checkpoint.SequenceNumber]
+	if !ok {
+		r.checkpoints[checkpoint.SequenceNumber] = newCheckpointReceipts()
+		checkpoints = r.checkpoints[checkpoint.SequenceNumber]
+	}
+
+	checkpoints.Lock()
+	defer checkpoints.Unlock()
+
+	_, exists := checkpoints.m[replica]
+	if exists {
+		r.log.Warn().Uint("sequence", checkpoint.SequenceNumber).Str("digest", checkpoint.Digest).Str("replica", replica.String()).Msg("ignoring duplicate checkpoint message")
+		return
+	}
+
+	checkpoints.m[replica] = checkpoint
+}
+
+// maybeStabilizeCheckpoint checks if the checkpoint with the given sequence number is stable. A checkpoint is stable once we
+// and 2f other replicas report the same state for it. We need our own checkpoint too, since we have no state transfer
+// to catch up on requests we did not execute.
+func (r *Replica) maybeStabilizeCheckpoint(sequence uint) {
+
+	if sequence <= r.lowWatermark {
+		return
+	}
+
+	checkpoints, ok := r.checkpoints[sequence]
+	if !ok {
+		return
+	}
+
+	checkpoints.Lock()
+
+	own, ok := checkpoints.m[r.id]
+	if !ok {
+		checkpoints.Unlock()
+		return
+	}
+
+	var matching uint
+	for _, checkpoint := range checkpoints.m {
+		if checkpoint.Digest == own.Digest {
+			matching++
+		}
+	}
+
+	checkpoints.Unlock()
+
+	if matching < r.commitQuorum() {
+		return
+	}
+
+	r.log.Info().Uint("sequence_number", sequence).Str("digest", own.Digest).Msg("checkpoint is stable")
+
+	r.collectGarbage(sequence)
+}
+
+// collectGarbage advances the low watermark to the stable checkpoint and discards messages it made obsolete.
+// The stable checkpoint itself is kept as proof.
+func (r *Replica) collectGarbage(stable uint) {
+
+	r.lowWatermark = stable
+
+	for id, preprepare := range r.preprepares {
+		if id.sequence > stable {
+			continue
+		}
+
+		delete(r.preprepares, id)
+
+		// Requests that are still pending are needed for view changes.
+		_, pending := r.pending[preprepare.Digest]
+		if !pending {
+			delete(r.requests, preprepare.Digest)
+		}
+	}
+
+	for id := range r.prepares {
+		if id.sequence <= stable {
+			delete(r.prepares, id)
+		}
+	}
+
+	for id := range r.commits {
+		if id.sequence <= stable {
+			delete(r.commits, id)
+		}
+	}
+
+	for sequence := range r.checkpoints {
+		if sequence < stable {
+			delete(r.checkpoints, sequence)
+		}
+	}
+
+	r.log.Debug().Uint("low_watermark", r.lowWatermark).Uint("high_watermark", r.highWatermark()).Msg("discarded messages up to the stable checkpoint")
+}
+
+// highWatermark returns the highest sequence number we accept. The window spans two checkpoint intervals,
+// so replicas can keep going while the next checkpoint becomes stable.
+func (r *Replica) highWatermark() uint {
+	return r.lowWatermark + 2*r.cfg.CheckpointInterval
+}
+
+func (r *Replica) inWatermarks(sequence uint) bool {
+	return sequence > r.lowWatermark && sequence <= r.highWatermark()
+}
+
+// checkWatermarks verifies that the message sequence number is within watermarks. It applies to messages of the normal case operation.
+func (r *Replica) checkWatermarks(msg any) error {
+
+	var sequence uint
+	switch m := msg.(type) {
+	case PrePrepare:
+		sequence = m.SequenceNumber
+	case Prepare:
+		sequence = m.SequenceNumber
+	case Commit:
+		sequence = m.SequenceNumber
+	default:
+		return nil
+	}
+
+	if !r.inWatermarks(sequence) {
+		return fmt.Errorf("%w (sequence: %v, low: %v, high: %v)", ErrOutsideWatermarks, sequence, r.lowWatermark, r.highWatermark())
+	}
+
+	return nil
+}
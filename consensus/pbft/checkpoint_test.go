@@ -0,0 +1,78 @@
+package pbft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestReplica_Checkpoint(t *testing.T) {
+
+	const (
+		interval = 2
+		stable   = 4
+		digest   = "state-digest"
+	)
+
+	replica := newDummyReplica(t)
+	replica.cfg.CheckpointInterval = interval
+
+	// Populate the message log.
+	for sequence := uint(1); sequence <= stable+1; sequence++ {
+
+		request := mocks.GenericUUID.String()
+		requestDigest := getDigest([]any{request, sequence})
+
+		id := getMessageID(0, sequence)
+		replica.preprepares[id] = PrePrepare{SequenceNumber: sequence, Digest: requestDigest}
+		replica.requests[requestDigest] = Request{ID: request}
+		replica.prepares[id] = newPrepareReceipts()
+		replica.commits[id] = newCommitReceipts()
+	}
+
+	checkpoint := Checkpoint{SequenceNumber: stable, Digest: digest}
+
+	// Checkpoint is not stable without our own checkpoint.
+	for _, peer := range mocks.GenericPeerIDs[:3] {
+		replica.recordCheckpointReceipt(peer, checkpoint)
+	}
+	replica.maybeStabilizeCheckpoint(stable)
+	require.Equal(t, uint(0), replica.lowWatermark)
+
+	replica.recordCheckpointReceipt(replica.id, checkpoint)
+	replica.maybeStabilizeCheckpoint(stable)
+	require.Equal(t, uint(stable), replica.lowWatermark)
+
+	// Only messages after the stable checkpoint are kept.
+	require.Len(t, replica.preprepares, 1)
+	require.Len(t, replica.prepares, 1)
+	require.Len(t, replica.commits, 1)
+	require.Len(t, replica.requests, 1)
+	require.Contains(t, replica.preprepares, getMessageID(0, stable+1))
+	require.Contains(t, replica.checkpoints, uint(stable))
+
+	t.Run("watermarks", func(t *testing.T) {
+		require.NoError(t, replica.checkWatermarks(Prepare{SequenceNumber: stable + 1}))
+		require.NoError(t, replica.checkWatermarks(Commit{SequenceNumber: stable + 2*interval}))
+		require.ErrorIs(t, replica.checkWatermarks(PrePrepare{SequenceNumber: stable}), ErrOutsideWatermarks)
+		require.ErrorIs(t, replica.checkWatermarks(Prepare{SequenceNumber: stable + 2*interval + 1}), ErrOutsideWatermarks)
+
+		// Other messages are not subject to watermarks.
+		require.NoError(t, replica.checkWatermarks(ViewChange{}))
+	})
+	t.Run("mismatched checkpoints are not stable", func(t *testing.T) {
+		next := Checkpoint{SequenceNumber: stable + interval, Digest: digest}
+
+		replica.recordCheckpointReceipt(replica.id, next)
+		replica.recordCheckpointReceipt(mocks.GenericPeerIDs[0], next)
+
+		next.Digest = "another-digest"
+		replica.recordCheckpointReceipt(mocks.GenericPeerIDs[1], next)
+		replica.recordCheckpointReceipt(mocks.GenericPeerIDs[2], next)
+
+		replica.maybeStabilizeCheckpoint(next.SequenceNumber)
+		require.Equal(t, uint(stable), replica.lowWatermark)
+	})
+}
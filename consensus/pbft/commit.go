@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/consensus"
 )
 
 func (r *Replica) maybeSendCommit(ctx context.Context, view uint, sequenceNo uint, digest string) error {
@@ -98,6 +100,7 @@ func (r *Replica) processCommit(ctx context.Context, replica peer.ID, commit Com
 
 	err := r.verifySignature(&commit, replica)
 	if err != nil {
+		r.reportMisbehavior(consensus.MisbehaviorInvalidSignature, replica, commit.View, commit.SequenceNumber, commit)
 		return fmt.Errorf("could not validate commit signature: %w", err)
 	}
 
@@ -130,8 +133,12 @@ func (r *Replica) recordCommitReceipt(replica peer.ID, commit Commit) {
 	defer commits.Unlock()
 
 	// Have we already seen this commit?
-	_, exists := commits.m[replica]
+	existing, exists := commits.m[replica]
 	if exists {
+		if existing.Digest != commit.Digest {
+			r.reportMisbehavior(consensus.MisbehaviorConflictingVote, replica, commit.View, commit.SequenceNumber, existing, commit)
+		}
+
 		r.log.Warn().Uint("view", commit.View).Uint("sequence", commit.SequenceNumber).Str("digest", commit.Digest).Msg("ignoring duplicate commit")
 		return
 	}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/libp2p/go-libp2p/core/peer"
 
+	"github.com/blocklessnetwork/b7s/consensus"
 	"github.com/blocklessnetwork/b7s/metadata"
 	"github.com/blocklessnetwork/b7s/models/execute"
 	"github.com/blocklessnetwork/b7s/telemetry/tracing"
@@ -16,18 +17,26 @@ type Option func(*Config)
 // PostProcessFunc is invoked by the replica after execution is done.
 type PostProcessFunc func(requestID string, origin peer.ID, request execute.Request, result execute.NodeResult)
 
+// MisbehaviorFunc is invoked by the replica when it catches another replica misbehaving.
+type MisbehaviorFunc func(misbehavior consensus.Misbehavior)
+
 var DefaultConfig = Config{
-	NetworkTimeout:   NetworkTimeout,
-	RequestTimeout:   RequestTimeout,
-	MetadataProvider: metadata.NewNoopProvider(),
+	NetworkTimeout:     NetworkTimeout,
+	RequestTimeout:     RequestTimeout,
+	MaxRequestTimeout:  MaxRequestTimeout,
+	CheckpointInterval: CheckpointInterval,
+	MetadataProvider:   metadata.NewNoopProvider(),
 }
 
 type Config struct {
-	PostProcessors   []PostProcessFunc // Callback functions to be invoked after execution is done.
-	NetworkTimeout   time.Duration
-	RequestTimeout   time.Duration
-	MetadataProvider metadata.Provider
-	TraceInfo        tracing.TraceInfo
+	PostProcessors     []PostProcessFunc // Callback functions to be invoked after execution is done.
+	MisbehaviorHandler MisbehaviorFunc   // Callback function invoked when a replica is caught misbehaving.
+	NetworkTimeout     time.Duration
+	RequestTimeout     time.Duration // Base inactivity period before we trigger a view change.
+	MaxRequestTimeout  time.Duration // Upper bound for the adapted inactivity period.
+	CheckpointInterval uint          // How many requests are executed between checkpoints.
+	MetadataProvider   metadata.Provider
+	TraceInfo          tracing.TraceInfo
 }
 
 // WithNetworkTimeout sets how much time we allow for message sending.
@@ -44,6 +53,20 @@ func WithRequestTimeout(d time.Duration) Option {
 	}
 }
 
+// WithMaxRequestTimeout sets the upper bound for the inactivity period, after it is adjusted to the observed latency.
+func WithMaxRequestTimeout(d time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.MaxRequestTimeout = d
+	}
+}
+
+// WithCheckpointInterval sets how many requests are executed between checkpoints.
+func WithCheckpointInterval(n uint) Option {
+	return func(cfg *Config) {
+		cfg.CheckpointInterval = n
+	}
+}
+
 // WithPostProcessors sets the callbacks that will be invoked after execution.
 func WithPostProcessors(callbacks ...PostProcessFunc) Option {
 	return func(cfg *Config) {
@@ -53,6 +76,13 @@ func WithPostProcessors(callbacks ...PostProcessFunc) Option {
 	}
 }
 
+// WithMisbehaviorHandler sets the callback invoked when a replica is caught misbehaving.
+func WithMisbehaviorHandler(fn MisbehaviorFunc) Option {
+	return func(cfg *Config) {
+		cfg.MisbehaviorHandler = fn
+	}
+}
+
 // WithMetadataProvider sets the metadata provider for the node.
 func WithMetadataProvider(p metadata.Provider) Option {
 	return func(cfg *Config) {
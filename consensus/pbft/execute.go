@@ -73,11 +73,21 @@ func (r *Replica) execute(ctx context.Context, view uint, sequence uint, digest
 
 	log.Info().Msg("executing request")
 
-	res, err := r.executor.ExecuteFunction(ctx, request.ID, request.Execute)
+	// Execution response is sent in the context of this span, allowing the head node to link to it.
+	ctx, span := r.tracer.Start(ctx, spanExecute, r.executeSpanOpts(request.ID, view, sequence)...)
+	defer span.End()
+
+	res, err := r.executor.ExecuteFunction(ctx, request.ID, request.Execute.WithWatermark())
 	if err != nil {
 		log.Error().Err(err).Msg("execution failed")
 	}
 
+	// Cluster is making progress - learn how long it takes and stop backing off.
+	if r.requestTimer != nil {
+		r.recordRequestLatency(time.Since(r.requestStart))
+	}
+	r.failedViewChanges = 0
+
 	// Stop the timer since we completed an execution.
 	r.stopRequestTimer()
 
@@ -89,6 +99,14 @@ func (r *Replica) execute(ctx context.Context, view uint, sequence uint, digest
 	log.Info().Msg("executed request")
 
 	r.lastExecuted = sequence
+	r.stateDigest = getDigest([]string{r.stateDigest, digest})
+
+	if sequence%r.cfg.CheckpointInterval == 0 {
+		err = r.sendCheckpoint(ctx, sequence)
+		if err != nil {
+			log.Error().Err(err).Msg("could not send checkpoint")
+		}
+	}
 
 	metadata, err := r.cfg.MetadataProvider.Metadata(request.Execute, res.Result)
 	if err != nil {
@@ -103,7 +121,10 @@ func (r *Replica) execute(ctx context.Context, view uint, sequence uint, digest
 			RequestTimestamp: request.Timestamp,
 			Replica:          r.id,
 		},
+		Timing:    &execute.Timing{Execution: res.Usage.WallClockTime.Milliseconds()},
+		Watermark: execute.WatermarkTag(request.Execute.Config.Watermark, request.ID),
 	}
+	nres.SetChecksum()
 
 	err = nres.Sign(r.host.PrivateKey())
 	if err != nil {
@@ -38,6 +38,7 @@ const (
 	MessageCommit
 	MessageViewChange
 	MessageNewView
+	MessageCheckpoint
 )
 
 func (m MessageType) String() string {
@@ -52,6 +53,8 @@ func (m MessageType) String() string {
 		return "MessageViewChange"
 	case MessageNewView:
 		return "MessageNewView"
+	case MessageCheckpoint:
+		return "MessageCheckpoint"
 	default:
 		return fmt.Sprintf("unknown: %d", m)
 	}
@@ -150,3 +153,16 @@ type NewView struct {
 func (v NewView) Type() MessageType {
 	return MessageNewView
 }
+
+type Checkpoint struct {
+	BaseMessage
+	SequenceNumber uint   `json:"sequence_number"`
+	Digest         string `json:"digest"` // Digest of the replica state after executing the request with this sequence number.
+
+	// Signed digest of the checkpoint message.
+	Signature string `json:"signature,omitempty"`
+}
+
+func (c Checkpoint) Type() MessageType {
+	return MessageCheckpoint
+}
@@ -85,3 +85,19 @@ func (v *NewView) setSignature(signature string) {
 func (v NewView) getSignature() string {
 	return v.Signature
 }
+
+// Returns the payload that is eligible to be signed. This means basically the Checkpoint struct, excluding the signature field.
+func (c *Checkpoint) signableRecord() any {
+	cp := *c
+	cp.setSignature("")
+	cp.BaseMessage = BaseMessage{}
+	return cp
+}
+
+func (c *Checkpoint) setSignature(signature string) {
+	c.Signature = signature
+}
+
+func (c Checkpoint) getSignature() string {
+	return c.Signature
+}
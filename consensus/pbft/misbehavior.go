@@ -0,0 +1,168 @@
+package pbft
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+)
+
+// ErrUnverifiableEvidence is returned for misbehavior that cannot be proven to a third party, such as invalid signatures.
+var ErrUnverifiableEvidence = errors.New("misbehavior evidence cannot be verified")
+
+// misbehaviors records the evidence of replicas caught misbehaving.
+type misbehaviors struct {
+	sync.Mutex
+	seen    map[misbehaviorID]struct{}
+	records []consensus.Misbehavior
+}
+
+type misbehaviorID struct {
+	replica  peer.ID
+	kind     string
+	view     uint
+	sequence uint
+}
+
+func newMisbehaviors() *misbehaviors {
+	return &misbehaviors{
+		seen: make(map[misbehaviorID]struct{}),
+	}
+}
+
+// Misbehaviors returns the evidence of cluster members caught misbehaving.
+func (r *Replica) Misbehaviors() []consensus.Misbehavior {
+	r.misbehaviors.Lock()
+	defer r.misbehaviors.Unlock()
+
+	out := make([]consensus.Misbehavior, len(r.misbehaviors.records))
+	copy(out, r.misbehaviors.records)
+
+	return out
+}
+
+// reportMisbehavior records the evidence of a replica misbehaving and passes it on to the misbehavior handler.
+// Each misbehavior is reported once per view and sequence number.
+func (r *Replica) reportMisbehavior(kind string, replica peer.ID, view uint, sequence uint, messages ...any) {
+
+	log := r.log.With().Str("replica", replica.String()).Str("kind", kind).Uint("view", view).Uint("sequence", sequence).Logger()
+
+	id := misbehaviorID{replica: replica, kind: kind, view: view, sequence: sequence}
+
+	r.misbehaviors.Lock()
+	_, seen := r.misbehaviors.seen[id]
+	r.misbehaviors.seen[id] = struct{}{}
+	r.misbehaviors.Unlock()
+
+	if seen {
+		return
+	}
+
+	misbehavior := consensus.Misbehavior{
+		Consensus: consensus.PBFT,
+		Cluster:   r.clusterID,
+		Replica:   replica,
+		Kind:      kind,
+		View:      view,
+		Sequence:  sequence,
+		Detected:  time.Now().UTC(),
+	}
+
+	for _, msg := range messages {
+		payload, err := json.Marshal(msg)
+		if err != nil {
+			log.Warn().Err(err).Msg("could not serialize misbehavior evidence")
+			continue
+		}
+
+		misbehavior.Evidence = append(misbehavior.Evidence, payload)
+	}
+
+	r.misbehaviors.Lock()
+	r.misbehaviors.records = append(r.misbehaviors.records, misbehavior)
+	r.misbehaviors.Unlock()
+
+	log.Warn().Msg("replica caught misbehaving")
+
+	// Handler is invoked while we hold the state lock, so don't block on it.
+	if r.cfg.MisbehaviorHandler != nil {
+		go r.cfg.MisbehaviorHandler(misbehavior)
+	}
+}
+
+// VerifyMisbehavior checks that the evidence proves the replica misbehaved - that it signed conflicting messages for the same view
+// and sequence number. Invalid signatures cannot be attributed to the replica by a third party, and return `ErrUnverifiableEvidence`.
+func VerifyMisbehavior(m consensus.Misbehavior) error {
+
+	switch m.Kind {
+	case consensus.MisbehaviorConflictingProposal, consensus.MisbehaviorConflictingVote:
+	case consensus.MisbehaviorInvalidSignature:
+		return ErrUnverifiableEvidence
+	default:
+		return fmt.Errorf("unknown misbehavior kind (%s)", m.Kind)
+	}
+
+	if len(m.Evidence) != 2 {
+		return fmt.Errorf("conflicting messages expected (have: %d)", len(m.Evidence))
+	}
+
+	digests := make([]string, 0, len(m.Evidence))
+	for _, payload := range m.Evidence {
+
+		msg, err := unpackMessage(payload)
+		if err != nil {
+			return fmt.Errorf("could not unpack evidence: %w", err)
+		}
+
+		var (
+			rec      signable
+			view     uint
+			sequence uint
+			digest   string
+		)
+		switch evidence := msg.(type) {
+		case PrePrepare:
+			if m.Kind != consensus.MisbehaviorConflictingProposal {
+				return fmt.Errorf("unexpected evidence message type: %T", msg)
+			}
+			rec, view, sequence, digest = &evidence, evidence.View, evidence.SequenceNumber, evidence.Digest
+
+		case Prepare:
+			if m.Kind != consensus.MisbehaviorConflictingVote {
+				return fmt.Errorf("unexpected evidence message type: %T", msg)
+			}
+			rec, view, sequence, digest = &evidence, evidence.View, evidence.SequenceNumber, evidence.Digest
+
+		case Commit:
+			if m.Kind != consensus.MisbehaviorConflictingVote {
+				return fmt.Errorf("unexpected evidence message type: %T", msg)
+			}
+			rec, view, sequence, digest = &evidence, evidence.View, evidence.SequenceNumber, evidence.Digest
+
+		default:
+			return fmt.Errorf("unexpected evidence message type: %T", msg)
+		}
+
+		if view != m.View || sequence != m.Sequence {
+			return fmt.Errorf("evidence for a different view or sequence number (view: %v, sequence: %v)", view, sequence)
+		}
+
+		err = verifyMessageSignature(rec, m.Replica)
+		if err != nil {
+			return fmt.Errorf("evidence not signed by the replica: %w", err)
+		}
+
+		digests = append(digests, digest)
+	}
+
+	if digests[0] == digests[1] {
+		return errors.New("evidence messages do not conflict")
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package pbft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestReplica_ReportMisbehavior(t *testing.T) {
+
+	replica := newDummyReplica(t)
+
+	reported := make(chan consensus.Misbehavior, 1)
+	replica.cfg.MisbehaviorHandler = func(m consensus.Misbehavior) {
+		reported <- m
+	}
+
+	var (
+		first  = Prepare{View: 1, SequenceNumber: 2, Digest: "abc"}
+		second = Prepare{View: 1, SequenceNumber: 2, Digest: "def"}
+		faulty = mocks.GenericPeerIDs[1]
+	)
+
+	replica.reportMisbehavior(consensus.MisbehaviorConflictingVote, faulty, 1, 2, first, second)
+	// Same misbehavior is reported once.
+	replica.reportMisbehavior(consensus.MisbehaviorConflictingVote, faulty, 1, 2, first, second)
+
+	m := <-reported
+	require.Equal(t, faulty, m.Replica)
+	require.Equal(t, consensus.PBFT, m.Consensus)
+	require.Equal(t, replica.clusterID, m.Cluster)
+	require.Len(t, m.Evidence, 2)
+
+	require.Len(t, replica.Misbehaviors(), 1)
+}
+
+func TestVerifyMisbehavior(t *testing.T) {
+
+	const (
+		view     = 4
+		sequence = 9
+	)
+
+	replica := newDummyReplica(t)
+
+	signedPrepare := func(digest string) Prepare {
+		prepare := Prepare{View: view, SequenceNumber: sequence, Digest: digest}
+		require.NoError(t, replica.sign(&prepare))
+		return prepare
+	}
+
+	misbehavior := func(kind string, messages ...any) consensus.Misbehavior {
+		reporter := newDummyReplica(t)
+		reporter.reportMisbehavior(kind, replica.host.ID(), view, sequence, messages...)
+		records := reporter.Misbehaviors()
+		require.Len(t, records, 1)
+		return records[0]
+	}
+
+	t.Run("conflicting votes", func(t *testing.T) {
+		m := misbehavior(consensus.MisbehaviorConflictingVote, signedPrepare("abc"), signedPrepare("def"))
+		require.NoError(t, VerifyMisbehavior(m))
+	})
+	t.Run("conflicting proposals", func(t *testing.T) {
+
+		proposal := func(digest string) PrePrepare {
+			preprepare := PrePrepare{
+				View:           view,
+				SequenceNumber: sequence,
+				Digest:         digest,
+				Request: Request{
+					ID:      mocks.GenericUUID.String(),
+					Origin:  mocks.GenericPeerID,
+					Execute: mocks.GenericExecutionRequest,
+				},
+			}
+			require.NoError(t, replica.sign(&preprepare))
+			return preprepare
+		}
+
+		m := misbehavior(consensus.MisbehaviorConflictingProposal, proposal("abc"), proposal("def"))
+		require.NoError(t, VerifyMisbehavior(m))
+	})
+	t.Run("identical votes are not misbehavior", func(t *testing.T) {
+		m := misbehavior(consensus.MisbehaviorConflictingVote, signedPrepare("abc"), signedPrepare("abc"))
+		require.Error(t, VerifyMisbehavior(m))
+	})
+	t.Run("evidence must be signed by the replica", func(t *testing.T) {
+
+		forged := Prepare{View: view, SequenceNumber: sequence, Digest: "def"}
+		require.NoError(t, newDummyReplica(t).sign(&forged))
+
+		m := misbehavior(consensus.MisbehaviorConflictingVote, signedPrepare("abc"), forged)
+		require.Error(t, VerifyMisbehavior(m))
+	})
+	t.Run("invalid signatures are not verifiable", func(t *testing.T) {
+		m := misbehavior(consensus.MisbehaviorInvalidSignature, Prepare{View: view, SequenceNumber: sequence})
+		require.ErrorIs(t, VerifyMisbehavior(m), ErrUnverifiableEvidence)
+	})
+}
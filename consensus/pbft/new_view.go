@@ -119,18 +119,22 @@ func (r *Replica) generatePreprepares(view uint, vcs map[peer.ID]ViewChange) []P
 
 	log := r.log.With().Uint("view", view).Logger()
 
-	// Phase 1. We don't have checkpoints, so our lower sequence number bound is 0.
+	// Phase 1. Our lower sequence number bound is the last stable checkpoint.
 	// Determine the upper higher sequence bound by going through the view change messages
 	// and examining the prepare certificates.
+	min := r.lowWatermark
 	max := getHighestSequenceNumber(vcs)
+	if max < min {
+		max = min
+	}
 
-	log.Info().Uint("max", max).Msg("generating preprepares for new view, determined max sequence number")
+	log.Info().Uint("min", min).Uint("max", max).Msg("generating preprepares for new view, determined sequence number bounds")
 
-	// Phase 2. Go through all sequence numbers from 1 to max. If there is a prepare certificate
+	// Phase 2. Go through all sequence numbers from min+1 to max. If there is a prepare certificate
 	// for a sequence number in the view change messages - create a pre-prepare message for m,v+1,n.
 	// If there are multiple prepare certificates with different view numbers - use the highest view number.
-	preprepares := make([]PrePrepare, 0, max)
-	for sequenceNo := uint(1); sequenceNo <= max; sequenceNo++ {
+	preprepares := make([]PrePrepare, 0, max-min)
+	for sequenceNo := min + 1; sequenceNo <= max; sequenceNo++ {
 
 		log := log.With().Uint("sequence", sequenceNo).Logger()
 
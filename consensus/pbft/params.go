@@ -18,9 +18,21 @@ const (
 	// How long do the send/broadcast operation have until we consider it failed.
 	NetworkTimeout = 5 * time.Second
 
+	// How often (in executed requests) do replicas create a checkpoint, allowing them to discard older messages.
+	CheckpointInterval = 100
+
 	// How long is the inactivity period before we trigger a view change.
 	RequestTimeout = 10 * time.Second
 
+	// Upper bound for the inactivity period, after adapting it to observed latency and failed view changes.
+	MaxRequestTimeout = 2 * time.Minute
+
+	// Inactivity period is at least this many times the smoothed time needed to execute a request.
+	requestTimeoutLatencyFactor = 3
+
+	// Weight of the latest observation when smoothing request latency.
+	requestLatencyWeight = 0.2
+
 	EnvVarByzantine = "B7S_PBFT_BYZANTINE"
 
 	tracerName = "b7s.PBFTCluster"
@@ -33,6 +45,7 @@ var (
 	ErrActiveView            = errors.New("replica is currently in an active view")
 	ErrConflictingPreprepare = errors.New("conflicting pre-prepare")
 	ErrInvalidSignature      = errors.New("invalid signature")
+	ErrOutsideWatermarks     = errors.New("sequence number outside of watermarks")
 )
 
 var (
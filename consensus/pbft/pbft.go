@@ -38,6 +38,11 @@ type Replica struct {
 
 	// Track inactivity period to trigger a view change.
 	requestTimer *time.Timer
+	requestStart time.Time
+
+	// Inactivity period adapts to the smoothed time needed to execute a request, and backs off with consecutive view changes.
+	requestLatency    time.Duration
+	failedViewChanges uint
 
 	// Components.
 	log      zerolog.Logger
@@ -53,6 +58,9 @@ type Replica struct {
 	// TODO (pbft): This is used for testing ATM, remove later.
 	byzantine bool
 
+	// Evidence of cluster members caught misbehaving.
+	misbehaviors *misbehaviors
+
 	// Telemetry
 	tracer  *tracing.Tracer
 	metrics *metrics.Metrics
@@ -72,6 +80,10 @@ func NewReplica(log zerolog.Logger, host *host.Host, executor blockless.Executor
 		option(&cfg)
 	}
 
+	if cfg.CheckpointInterval == 0 {
+		return nil, errors.New("checkpoint interval must be positive")
+	}
+
 	replica := Replica{
 		pbftCore:     newPbftCore(total),
 		replicaState: newState(),
@@ -87,7 +99,8 @@ func NewReplica(log zerolog.Logger, host *host.Host, executor blockless.Executor
 		id:    host.ID(),
 		peers: peers,
 
-		byzantine: isByzantine(),
+		byzantine:    isByzantine(),
+		misbehaviors: newMisbehaviors(),
 
 		tracer:  tracing.NewTracer(tracerName),
 		metrics: metrics.Default(),
@@ -202,6 +215,11 @@ func (r *Replica) processMessage(ctx context.Context, from peer.ID, payload []by
 		return fmt.Errorf("message not allowed (message: %T): %w", msg, err)
 	}
 
+	err = r.checkWatermarks(msg)
+	if err != nil {
+		return fmt.Errorf("message not allowed (message: %T): %w", msg, err)
+	}
+
 	switch m := msg.(type) {
 
 	case Request:
@@ -221,6 +239,9 @@ func (r *Replica) processMessage(ctx context.Context, from peer.ID, payload []by
 
 	case NewView:
 		return r.processNewView(ctx, from, m)
+
+	case Checkpoint:
+		return r.processCheckpoint(ctx, from, m)
 	}
 
 	return fmt.Errorf("unexpected message type (from: %s): %T", from, msg)
@@ -256,10 +277,9 @@ func (r *Replica) isMessageAllowed(msg interface{}) error {
 		}
 	}
 
-	// We are in a view change. Only accept view-change and new-view messages.
-	// PBFT also supports checkpoint messages, but we don't use those.
+	// We are in a view change. Only accept view-change, new-view and checkpoint messages.
 	switch msg.(type) {
-	case ViewChange, NewView:
+	case ViewChange, NewView, Checkpoint:
 		return nil
 	default:
 		return ErrViewChange
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/consensus"
 )
 
 // Send a prepare message. Naturally this is only sent by the non-primary replicas,
@@ -51,8 +53,12 @@ func (r *Replica) recordPrepareReceipt(replica peer.ID, prepare Prepare) {
 	prepares.Lock()
 	defer prepares.Unlock()
 
-	_, exists := prepares.m[replica]
+	existing, exists := prepares.m[replica]
 	if exists {
+		if existing.Digest != prepare.Digest {
+			r.reportMisbehavior(consensus.MisbehaviorConflictingVote, replica, prepare.View, prepare.SequenceNumber, existing, prepare)
+		}
+
 		r.log.Warn().Uint("view", prepare.View).Uint("sequence", prepare.SequenceNumber).Str("digest", prepare.Digest).Str("replica", replica.String()).Msg("ignoring duplicate prepare message")
 		return
 	}
@@ -77,6 +83,7 @@ func (r *Replica) processPrepare(ctx context.Context, replica peer.ID, prepare P
 
 	err := r.verifySignature(&prepare, replica)
 	if err != nil {
+		r.reportMisbehavior(consensus.MisbehaviorInvalidSignature, replica, prepare.View, prepare.SequenceNumber, prepare)
 		return fmt.Errorf("could not verify signature for the prepare message: %w", err)
 	}
 
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/consensus"
 )
 
 func (r *Replica) sendPrePrepare(ctx context.Context, req Request) error {
@@ -14,6 +16,13 @@ func (r *Replica) sendPrePrepare(ctx context.Context, req Request) error {
 		return nil
 	}
 
+	// Sequence numbers up to the stable checkpoint are all taken, even if we were not the primary for them.
+	r.sequence = max(r.sequence, r.lowWatermark)
+
+	if !r.inWatermarks(r.sequence + 1) {
+		return fmt.Errorf("no room for new requests until the next stable checkpoint (sequence: %v, low watermark: %v): %w", r.sequence+1, r.lowWatermark, ErrOutsideWatermarks)
+	}
+
 	r.sequence++
 	sequence := r.sequence
 
@@ -73,6 +82,7 @@ func (r *Replica) processPrePrepare(ctx context.Context, replica peer.ID, msg Pr
 
 	err := r.verifySignature(&msg, r.primaryReplicaID())
 	if err != nil {
+		r.reportMisbehavior(consensus.MisbehaviorInvalidSignature, replica, msg.View, msg.SequenceNumber, msg)
 		return fmt.Errorf("pre-prepare message signature not valid: %w", err)
 	}
 
@@ -81,6 +91,12 @@ func (r *Replica) processPrePrepare(ctx context.Context, replica peer.ID, msg Pr
 	existing, ok := r.preprepares[id]
 	if ok {
 		log.Error().Str("existing_digest", existing.Digest).Msg("pre-prepare message already exists for this view and sequence number, dropping")
+
+		// Primary proposing a different request for the same slot is byzantine.
+		if existing.Digest != msg.Digest {
+			r.reportMisbehavior(consensus.MisbehaviorConflictingProposal, replica, msg.View, msg.SequenceNumber, existing, msg)
+		}
+
 		return ErrConflictingPreprepare
 	}
 
@@ -37,6 +37,21 @@ func newCommitReceipts() *commitReceipts {
 	return &cr
 }
 
+type checkpointReceipts struct {
+	m map[peer.ID]Checkpoint
+	*sync.Mutex
+}
+
+func newCheckpointReceipts() *checkpointReceipts {
+
+	cr := checkpointReceipts{
+		m:     make(map[peer.ID]Checkpoint),
+		Mutex: &sync.Mutex{},
+	}
+
+	return &cr
+}
+
 type viewChangeReceipts struct {
 	m map[peer.ID]ViewChange
 	*sync.Mutex
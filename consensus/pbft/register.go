@@ -0,0 +1,34 @@
+package pbft
+
+import (
+	"github.com/blocklessnetwork/b7s/consensus"
+)
+
+func init() {
+	consensus.Register(consensus.PBFT, "PBFT", createReplica, consensus.WithMinimumReplicas(MinimumReplicaCount))
+}
+
+func createReplica(env consensus.Environment, cluster consensus.Cluster) (consensus.Replica, error) {
+
+	opts := []Option{
+		WithTraceInfo(env.TraceInfo),
+	}
+
+	if env.Executed != nil {
+		opts = append(opts, WithPostProcessors(env.Executed))
+	}
+
+	if env.Misbehaved != nil {
+		opts = append(opts, WithMisbehaviorHandler(env.Misbehaved))
+	}
+
+	if env.MetadataProvider != nil {
+		opts = append(opts, WithMetadataProvider(env.MetadataProvider))
+	}
+
+	if cluster.RequestTimeout > 0 {
+		opts = append(opts, WithRequestTimeout(cluster.RequestTimeout))
+	}
+
+	return NewReplica(env.Log, env.Host, env.Executor, cluster.Peers, cluster.ID, opts...)
+}
@@ -35,8 +35,9 @@ func (r *Request) UnmarshalJSON(data []byte) error {
 	if err != nil {
 		return err
 	}
-	type alias *Request
-	return json.Unmarshal(rec.Data, alias(r))
+	// Unmarshal into a type without the UnmarshalJSON method to avoid recursion.
+	type alias Request
+	return json.Unmarshal(rec.Data, (*alias)(r))
 }
 
 func (p PrePrepare) MarshalJSON() ([]byte, error) {
@@ -54,8 +55,9 @@ func (p *PrePrepare) UnmarshalJSON(data []byte) error {
 	if err != nil {
 		return err
 	}
-	type alias *PrePrepare
-	return json.Unmarshal(rec.Data, alias(p))
+	// Unmarshal into a type without the UnmarshalJSON method to avoid recursion.
+	type alias PrePrepare
+	return json.Unmarshal(rec.Data, (*alias)(p))
 }
 
 func (p Prepare) MarshalJSON() ([]byte, error) {
@@ -73,8 +75,9 @@ func (p *Prepare) UnmarshalJSON(data []byte) error {
 	if err != nil {
 		return err
 	}
-	type alias *Prepare
-	return json.Unmarshal(rec.Data, alias(p))
+	// Unmarshal into a type without the UnmarshalJSON method to avoid recursion.
+	type alias Prepare
+	return json.Unmarshal(rec.Data, (*alias)(p))
 }
 
 func (c Commit) MarshalJSON() ([]byte, error) {
@@ -92,8 +95,9 @@ func (c *Commit) UnmarshalJSON(data []byte) error {
 	if err != nil {
 		return err
 	}
-	type alias *Commit
-	return json.Unmarshal(rec.Data, alias(c))
+	// Unmarshal into a type without the UnmarshalJSON method to avoid recursion.
+	type alias Commit
+	return json.Unmarshal(rec.Data, (*alias)(c))
 }
 
 type prepareInfoEncoded struct {
@@ -165,8 +169,9 @@ func (v *ViewChange) UnmarshalJSON(data []byte) error {
 	if err != nil {
 		return err
 	}
-	type alias *ViewChange
-	return json.Unmarshal(rec.Data, alias(v))
+	// Unmarshal into a type without the UnmarshalJSON method to avoid recursion.
+	type alias ViewChange
+	return json.Unmarshal(rec.Data, (*alias)(v))
 }
 
 type newViewEncode struct {
@@ -233,6 +238,26 @@ func (n *NewView) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+func (c Checkpoint) MarshalJSON() ([]byte, error) {
+	type alias Checkpoint
+	rec := messageRecord{
+		Type: c.Type(),
+		Data: alias(c),
+	}
+	return json.Marshal(rec)
+}
+
+func (c *Checkpoint) UnmarshalJSON(data []byte) error {
+	var rec messageEnvelope
+	err := json.Unmarshal(data, &rec)
+	if err != nil {
+		return err
+	}
+	// Unmarshal into a type without the UnmarshalJSON method to avoid recursion.
+	type alias Checkpoint
+	return json.Unmarshal(rec.Data, (*alias)(c))
+}
+
 func unpackMessage(payload []byte) (PBFTMessage, error) {
 
 	var msg messageEnvelope
@@ -289,6 +314,14 @@ func unpackMessage(payload []byte) (PBFTMessage, error) {
 			return nil, fmt.Errorf("could not unpack new view message: %w", err)
 		}
 		return newView, nil
+
+	case MessageCheckpoint:
+		var checkpoint Checkpoint
+		err = json.Unmarshal(payload, &checkpoint)
+		if err != nil {
+			return nil, fmt.Errorf("could not unpack checkpoint message: %w", err)
+		}
+		return checkpoint, nil
 	}
 
 	return nil, fmt.Errorf("unexpected message type (type: %v)", msg.Type)
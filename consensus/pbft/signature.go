@@ -21,6 +21,10 @@ func (r *Replica) sign(rec signable) error {
 }
 
 func (r *Replica) verifySignature(rec signable, signer peer.ID) error {
+	return verifyMessageSignature(rec, signer)
+}
+
+func verifyMessageSignature(rec signable, signer peer.ID) error {
 
 	// Get the digest of the message, excluding the signature.
 	digest := getDigest(rec.signableRecord())
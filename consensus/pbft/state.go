@@ -18,6 +18,13 @@ type replicaState struct {
 	// Sequence number of last execution.
 	lastExecuted uint
 
+	// Digest of the replica state, updated with each execution.
+	stateDigest string
+
+	// Sequence number of the last stable checkpoint. Messages with sequence numbers at or below it are discarded,
+	// and so are messages too far ahead of it.
+	lowWatermark uint
+
 	// Keep track of seen requests. Map request to the digest.
 	requests map[string]Request
 	// Keep track of requests queued for execution. Could also be tracked via a single map.
@@ -31,6 +38,8 @@ type replicaState struct {
 	commits map[messageID]*commitReceipts
 	// Keep track of view change messages.
 	viewChanges map[uint]*viewChangeReceipts
+	// Keep track of checkpoint messages, mapped to sequence numbers.
+	checkpoints map[uint]*checkpointReceipts
 
 	// Keep track of past executions. Results are mapped to request IDs, not digests.
 	executions map[string]response.Execute
@@ -48,6 +57,7 @@ func newState() replicaState {
 		prepares:    make(map[messageID]*prepareReceipts),
 		commits:     make(map[messageID]*commitReceipts),
 		viewChanges: make(map[uint]*viewChangeReceipts),
+		checkpoints: make(map[uint]*checkpointReceipts),
 		executions:  make(map[string]response.Execute),
 	}
 
@@ -21,7 +21,10 @@ func (r *Replica) startRequestTimer(overrideExisting bool) {
 	// to the next view before our inactivity timer fires.
 	targetView := r.view + 1
 
-	r.requestTimer = time.AfterFunc(r.cfg.RequestTimeout, func() {
+	timeout := r.requestTimeout()
+	r.requestStart = time.Now()
+
+	r.requestTimer = time.AfterFunc(timeout, func() {
 		r.sl.Lock()
 		defer r.sl.Unlock()
 
@@ -31,7 +34,7 @@ func (r *Replica) startRequestTimer(overrideExisting bool) {
 		}
 	})
 
-	r.log.Debug().Msg("view change timer started")
+	r.log.Debug().Dur("timeout", timeout).Msg("view change timer started")
 }
 
 func (r *Replica) stopRequestTimer() {
@@ -48,3 +51,34 @@ func (r *Replica) stopRequestTimer() {
 
 	r.log.Debug().Msg("view change timer stopped")
 }
+
+// requestTimeout returns the inactivity period before we trigger a view change. It is never shorter than the configured
+// request timeout, grows for clusters where executions take longer and doubles with each consecutive view change, so that
+// slow clusters don't keep changing views.
+func (r *Replica) requestTimeout() time.Duration {
+
+	timeout := r.cfg.RequestTimeout
+
+	adaptive := requestTimeoutLatencyFactor * r.requestLatency
+	if adaptive > timeout {
+		timeout = adaptive
+	}
+
+	limit := max(r.cfg.MaxRequestTimeout, r.cfg.RequestTimeout)
+	for i := uint(0); i < r.failedViewChanges && timeout < limit; i++ {
+		timeout *= 2
+	}
+
+	return min(timeout, limit)
+}
+
+// recordRequestLatency updates the smoothed time needed to execute a request.
+func (r *Replica) recordRequestLatency(latency time.Duration) {
+
+	if r.requestLatency == 0 {
+		r.requestLatency = latency
+		return
+	}
+
+	r.requestLatency += time.Duration(requestLatencyWeight * float64(latency-r.requestLatency))
+}
@@ -0,0 +1,39 @@
+package pbft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplica_RequestTimeout(t *testing.T) {
+
+	t.Run("defaults to configured timeout", func(t *testing.T) {
+		replica := newDummyReplica(t)
+		require.Equal(t, RequestTimeout, replica.requestTimeout())
+	})
+	t.Run("adapts to request latency", func(t *testing.T) {
+		replica := newDummyReplica(t)
+
+		replica.recordRequestLatency(time.Second)
+		require.Equal(t, RequestTimeout, replica.requestTimeout())
+
+		replica.requestLatency = 0
+		replica.recordRequestLatency(10 * time.Second)
+		require.Equal(t, 30*time.Second, replica.requestTimeout())
+
+		// Latency is smoothed.
+		replica.recordRequestLatency(20 * time.Second)
+		require.Equal(t, 12*time.Second, replica.requestLatency)
+	})
+	t.Run("backs off on failed view changes", func(t *testing.T) {
+		replica := newDummyReplica(t)
+
+		replica.failedViewChanges = 2
+		require.Equal(t, 4*RequestTimeout, replica.requestTimeout())
+
+		replica.failedViewChanges = 100
+		require.Equal(t, MaxRequestTimeout, replica.requestTimeout())
+	})
+}
@@ -5,7 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/telemetry/b7ssemconv"
 	"github.com/blocklessnetwork/b7s/telemetry/tracing"
 )
 
@@ -13,6 +16,7 @@ const (
 	spanMessageProcess   = "MessageProcess"
 	spanMessageSend      = "MessageSend"
 	spanMessageBroadcast = "MessageBroadcast"
+	spanExecute          = "PBFTExecute"
 )
 
 func saveTraceContext(ctx context.Context, msg any) {
@@ -27,6 +31,28 @@ func saveTraceContext(ctx context.Context, msg any) {
 	}
 }
 
+// executeSpanOpts returns the options for the span of the request execution. The span is linked to the span
+// that requested cluster formation on the head node, so that the execution can be found from the head node trace.
+func (r *Replica) executeSpanOpts(requestID string, view uint, sequence uint) []trace.SpanStartOption {
+
+	opts := []trace.SpanStartOption{
+		trace.WithAttributes(
+			b7ssemconv.ExecutionRequestID.String(requestID),
+			b7ssemconv.ConsensusReplica.String(r.id.String()),
+			b7ssemconv.ConsensusLeader.String(r.primaryReplicaID().String()),
+			b7ssemconv.ConsensusView.Int(int(view)),
+			b7ssemconv.ConsensusSequence.Int(int(sequence)),
+		),
+	}
+
+	link, ok := tracing.LinkFromTraceInfo(r.cfg.TraceInfo)
+	if ok {
+		opts = append(opts, trace.WithLinks(link))
+	}
+
+	return opts
+}
+
 func msgProcessSpanName(t MessageType) string {
 	return fmt.Sprintf("PBFTMessage %s %s", spanMessageProcess, t.String())
 }
@@ -18,6 +18,7 @@ func (r *Replica) startViewChange(view uint) error {
 	r.log.Info().Uint("current_view", r.view).Msg("starting view change")
 
 	r.stopRequestTimer()
+	r.failedViewChanges++
 
 	r.view = view
 	r.activeView = false
@@ -8,6 +8,7 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/blocklessnetwork/b7s/log/hclog"
+	"github.com/blocklessnetwork/b7s/telemetry/tracing"
 )
 
 // Option can be used to set Raft configuration options.
@@ -18,14 +19,23 @@ var DefaultConfig = Config{
 	HeartbeatTimeout: DefaultHeartbeatTimeout,
 	ElectionTimeout:  DefaultElectionTimeout,
 	LeaderLease:      DefaultLeaderLease,
+
+	SnapshotThreshold: DefaultSnapshotThreshold,
+	SnapshotInterval:  DefaultSnapshotInterval,
+	SnapshotRetain:    DefaultSnapshotRetain,
 }
 
 type Config struct {
-	Callbacks []FSMProcessFunc // Callback functions to be invoked by the FSM after execution is done.
+	Callbacks []FSMProcessFunc  // Callback functions to be invoked by the FSM after execution is done.
+	TraceInfo tracing.TraceInfo // Trace information of the cluster formation request, linked from execution spans.
 
 	HeartbeatTimeout time.Duration // How often a consensus cluster leader should ping its followers.
 	ElectionTimeout  time.Duration // How long does a consensus cluster node wait for a leader before it triggers an election.
 	LeaderLease      time.Duration // How long does a leader remain a leader if it cannot contact a quorum of cluster nodes.
+
+	SnapshotThreshold uint64        // How many log entries are applied before the log is compacted into a snapshot.
+	SnapshotInterval  time.Duration // How often do we check if a snapshot should be taken.
+	SnapshotRetain    int           // How many snapshots are kept in the workspace.
 }
 
 // WithHeartbeatTimeout sets the heartbeat timeout for the consensus cluster.
@@ -49,6 +59,27 @@ func WithLeaderLease(d time.Duration) Option {
 	}
 }
 
+// WithSnapshotThreshold sets how many log entries are applied before the log is compacted into a snapshot.
+func WithSnapshotThreshold(n uint64) Option {
+	return func(cfg *Config) {
+		cfg.SnapshotThreshold = n
+	}
+}
+
+// WithSnapshotInterval sets how often the replica checks if a snapshot should be taken.
+func WithSnapshotInterval(d time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.SnapshotInterval = d
+	}
+}
+
+// WithSnapshotRetain sets how many snapshots are kept in the workspace.
+func WithSnapshotRetain(n int) Option {
+	return func(cfg *Config) {
+		cfg.SnapshotRetain = n
+	}
+}
+
 func WithCallbacks(callbacks ...FSMProcessFunc) Option {
 	return func(cfg *Config) {
 		var fns []FSMProcessFunc
@@ -57,6 +88,13 @@ func WithCallbacks(callbacks ...FSMProcessFunc) Option {
 	}
 }
 
+// WithTraceInfo passes along telemetry trace information.
+func WithTraceInfo(t tracing.TraceInfo) Option {
+	return func(cfg *Config) {
+		cfg.TraceInfo = t
+	}
+}
+
 func getRaftConfig(cfg Config, log zerolog.Logger, nodeID string) raft.Config {
 
 	rcfg := raft.DefaultConfig()
@@ -65,6 +103,8 @@ func getRaftConfig(cfg Config, log zerolog.Logger, nodeID string) raft.Config {
 	rcfg.HeartbeatTimeout = cfg.HeartbeatTimeout
 	rcfg.ElectionTimeout = cfg.ElectionTimeout
 	rcfg.LeaderLeaseTimeout = cfg.LeaderLease
+	rcfg.SnapshotThreshold = cfg.SnapshotThreshold
+	rcfg.SnapshotInterval = cfg.SnapshotInterval
 
 	return *rcfg
 }
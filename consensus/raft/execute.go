@@ -24,9 +24,17 @@ func (r *Replica) Execute(from peer.ID, requestID string, timestamp time.Time, r
 
 	r.log.Info().Msg("we are the cluster leader, executing the request")
 
+	// Record ourselves as the leader that appended the entry.
+	_, id := r.LeaderWithID()
+	leader, err := peer.Decode(string(id))
+	if err != nil {
+		r.log.Warn().Err(err).Str("leader", string(id)).Msg("could not decode leader ID")
+	}
+
 	fsmReq := FSMLogEntry{
 		RequestID: requestID,
 		Origin:    from,
+		Leader:    leader,
 		Execute:   req,
 	}
 
@@ -5,38 +5,50 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sync"
 	"time"
 
 	"github.com/armon/go-metrics"
 	"github.com/hashicorp/raft"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/blocklessnetwork/b7s/models/blockless"
 	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/telemetry/b7ssemconv"
+	"github.com/blocklessnetwork/b7s/telemetry/tracing"
 )
 
 type FSMLogEntry struct {
 	RequestID string          `json:"request_id,omitempty"`
 	Origin    peer.ID         `json:"origin,omitempty"`
+	Leader    peer.ID         `json:"leader,omitempty"` // Leader is the replica that appended the entry to the log.
 	Execute   execute.Request `json:"execute,omitempty"`
 }
 
-type FSMProcessFunc func(req FSMLogEntry, res execute.NodeResult)
+// FSMProcessFunc is invoked after the execution. Context carries the span of the execution.
+type FSMProcessFunc func(ctx context.Context, req FSMLogEntry, res execute.NodeResult)
 
 type fsmExecutor struct {
 	log        zerolog.Logger
 	executor   blockless.Executor
 	processors []FSMProcessFunc
+	tracer     *tracing.Tracer
+	traceInfo  tracing.TraceInfo
+
+	// FSM state - results of the applied log entries, mapped to request IDs. This is what a snapshot holds.
+	lock    sync.Mutex
+	results map[string]execute.Result
 }
 
-func newFsmExecutor(log zerolog.Logger, executor blockless.Executor, processors ...FSMProcessFunc) *fsmExecutor {
+func newFsmExecutor(log zerolog.Logger, executor blockless.Executor, traceInfo tracing.TraceInfo, processors ...FSMProcessFunc) *fsmExecutor {
 
 	ps := make([]FSMProcessFunc, 0, len(processors))
 	ps = append(ps, processors...)
 
 	start := time.Now()
-	ps = append(ps, func(req FSMLogEntry, _ execute.NodeResult) {
+	ps = append(ps, func(_ context.Context, req FSMLogEntry, _ execute.NodeResult) {
 		// Global metrics handle.
 		metrics.MeasureSinceWithLabels(raftExecutionTimeMetric, start, []metrics.Label{{Name: "function", Value: req.Execute.FunctionID}})
 	})
@@ -45,12 +57,15 @@ func newFsmExecutor(log zerolog.Logger, executor blockless.Executor, processors
 		log:        log.With().Str("module", "fsm").Logger(),
 		executor:   executor,
 		processors: ps,
+		tracer:     tracing.NewTracer(tracerName),
+		traceInfo:  traceInfo,
+		results:    make(map[string]execute.Result),
 	}
 
 	return &fsm
 }
 
-func (f fsmExecutor) Apply(log *raft.Log) any {
+func (f *fsmExecutor) Apply(log *raft.Log) any {
 
 	f.log.Info().Msg("applying log entry")
 
@@ -63,20 +78,37 @@ func (f fsmExecutor) Apply(log *raft.Log) any {
 		return fmt.Errorf("could not unmarshal request: %w", err)
 	}
 
+	// Log entries applied before a restart are not executed again.
+	f.lock.Lock()
+	applied, ok := f.results[logEntry.RequestID]
+	f.lock.Unlock()
+	if ok {
+		f.log.Info().Str("request", logEntry.RequestID).Msg("log entry already applied, skipping execution")
+		return applied
+	}
+
 	f.log.Info().Str("request", logEntry.RequestID).Str("function", logEntry.Execute.FunctionID).Msg("FSM executing function")
 
-	res, err := f.executor.ExecuteFunction(context.Background(), logEntry.RequestID, logEntry.Execute)
+	ctx, span := f.tracer.Start(context.Background(), spanExecute, f.executeSpanOpts(log, logEntry)...)
+	defer span.End()
+
+	res, err := f.executor.ExecuteFunction(ctx, logEntry.RequestID, logEntry.Execute.WithWatermark())
 	if err != nil {
 		return fmt.Errorf("could not execute function: %w", err)
 	}
 
+	f.lock.Lock()
+	f.results[logEntry.RequestID] = res
+	f.lock.Unlock()
+
 	nres := execute.NodeResult{
-		Result: res,
+		Result:    res,
+		Watermark: execute.WatermarkTag(logEntry.Execute.Config.Watermark, logEntry.RequestID),
 	}
 
 	// Execute processors.
 	for _, proc := range f.processors {
-		proc(logEntry, nres)
+		proc(ctx, logEntry, nres)
 	}
 
 	f.log.Info().Str("request", logEntry.RequestID).Msg("FSM successfully executed function")
@@ -84,12 +116,84 @@ func (f fsmExecutor) Apply(log *raft.Log) any {
 	return res
 }
 
-func (f fsmExecutor) Snapshot() (raft.FSMSnapshot, error) {
+// executeSpanOpts returns the options for the span of the log entry execution. The span is linked to the span
+// that requested cluster formation on the head node, so that the execution can be found from the head node trace.
+func (f *fsmExecutor) executeSpanOpts(log *raft.Log, entry FSMLogEntry) []trace.SpanStartOption {
+
+	opts := []trace.SpanStartOption{
+		trace.WithAttributes(
+			b7ssemconv.ExecutionRequestID.String(entry.RequestID),
+			b7ssemconv.ConsensusLeader.String(entry.Leader.String()),
+			b7ssemconv.ConsensusTerm.Int64(int64(log.Term)),
+			b7ssemconv.ConsensusIndex.Int64(int64(log.Index)),
+		),
+	}
+
+	link, ok := tracing.LinkFromTraceInfo(f.traceInfo)
+	if ok {
+		opts = append(opts, trace.WithLinks(link))
+	}
+
+	return opts
+}
+
+// Snapshot returns a snapshot of the FSM state, allowing raft to compact the log.
+func (f *fsmExecutor) Snapshot() (raft.FSMSnapshot, error) {
+
 	f.log.Info().Msg("received snapshot request")
-	return nil, fmt.Errorf("TBD: not implemented")
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	snapshot := fsmSnapshot{
+		results: make(map[string]execute.Result, len(f.results)),
+	}
+	for id, res := range f.results {
+		snapshot.results[id] = res
+	}
+
+	return &snapshot, nil
 }
 
-func (f fsmExecutor) Restore(snapshot io.ReadCloser) error {
+// Restore replaces the FSM state with the one from the snapshot.
+func (f *fsmExecutor) Restore(snapshot io.ReadCloser) error {
+
 	f.log.Info().Msg("received snapshot restore request")
-	return fmt.Errorf("TBD: not implemented")
+
+	defer snapshot.Close()
+
+	var results map[string]execute.Result
+	err := json.NewDecoder(snapshot).Decode(&results)
+	if err != nil {
+		return fmt.Errorf("could not decode snapshot: %w", err)
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.results = results
+	if f.results == nil {
+		f.results = make(map[string]execute.Result)
+	}
+
+	f.log.Info().Int("results", len(f.results)).Msg("restored FSM state from snapshot")
+
+	return nil
 }
+
+type fsmSnapshot struct {
+	results map[string]execute.Result
+}
+
+func (s *fsmSnapshot) Persist(sink raft.SnapshotSink) error {
+
+	err := json.NewEncoder(sink).Encode(s.results)
+	if err != nil {
+		sink.Cancel()
+		return fmt.Errorf("could not encode snapshot: %w", err)
+	}
+
+	return sink.Close()
+}
+
+func (s *fsmSnapshot) Release() {}
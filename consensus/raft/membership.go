@@ -0,0 +1,91 @@
+package raft
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/raft"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/rs/zerolog"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+)
+
+// ErrNotLeader is returned when a membership change or leadership transfer is requested from a replica that is not the cluster leader.
+var ErrNotLeader = consensus.ErrNotLeader
+
+// Join creates a raft replica that joins an existing cluster. Unlike `New`, it does not bootstrap the cluster
+// nor wait for a leader - the replica stays idle until the cluster leader adds it to the cluster.
+func Join(log zerolog.Logger, host *host.Host, workspace string, requestID string, executor blockless.Executor, options ...Option) (*Replica, error) {
+
+	replica, err := newReplica(log, host, workspace, requestID, executor, []peer.ID{host.ID()}, options...)
+	if err != nil {
+		return nil, fmt.Errorf("could not create raft handler: %w", err)
+	}
+
+	replica.log.Info().Msg("waiting to be added to the cluster by the leader")
+
+	return replica, nil
+}
+
+// UpdateMembership adds and removes peers from the cluster. Only the cluster leader can change the cluster membership.
+// Peers being added should already be running a replica created using `Join`.
+func (r *Replica) UpdateMembership(add []peer.ID, remove []peer.ID) error {
+
+	if !r.isLeader() {
+		return ErrNotLeader
+	}
+
+	for _, id := range add {
+		future := r.AddVoter(raft.ServerID(id.String()), raft.ServerAddress(id), 0, membershipChangeTimeout)
+		err := future.Error()
+		if err != nil {
+			return fmt.Errorf("could not add peer to the cluster (peer: %s): %w", id.String(), err)
+		}
+
+		r.log.Info().Stringer("peer", id).Msg("added peer to the cluster")
+	}
+
+	for _, id := range remove {
+		future := r.RemoveServer(raft.ServerID(id.String()), 0, membershipChangeTimeout)
+		err := future.Error()
+		if err != nil {
+			return fmt.Errorf("could not remove peer from the cluster (peer: %s): %w", id.String(), err)
+		}
+
+		r.log.Info().Stringer("peer", id).Msg("removed peer from the cluster")
+	}
+
+	return nil
+}
+
+// TransferLeadership hands over the cluster leadership to the target peer, or to the most up to date member if no target is given.
+// Only the cluster leader can transfer leadership. Executions already committed to the log are not affected.
+func (r *Replica) TransferLeadership(target peer.ID) error {
+
+	if !r.isLeader() {
+		return ErrNotLeader
+	}
+
+	// We are already the leader.
+	_, leader := r.LeaderWithID()
+	if target != "" && string(leader) == target.String() {
+		return nil
+	}
+
+	future := r.LeadershipTransfer()
+	if target != "" {
+		future = r.LeadershipTransferToServer(raft.ServerID(target.String()), raft.ServerAddress(target))
+	}
+
+	err := future.Error()
+	if err != nil {
+		return fmt.Errorf("could not transfer leadership (target: %s): %w", target.String(), err)
+	}
+
+	_, leader = r.LeaderWithID()
+	r.log.Info().Str("leader", string(leader)).Msg("transferred cluster leadership")
+
+	return nil
+}
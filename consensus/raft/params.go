@@ -17,7 +17,19 @@ const (
 	DefaultElectionTimeout  = 300 * time.Millisecond
 	DefaultLeaderLease      = 200 * time.Millisecond
 
+	DefaultSnapshotThreshold = 1024
+	DefaultSnapshotInterval  = 30 * time.Second
+	DefaultSnapshotRetain    = 2
+
 	consensusTransportTimeout = 1 * time.Minute
+
+	// How long the leader waits for a cluster membership change to be committed.
+	membershipChangeTimeout = 10 * time.Second
+)
+
+const (
+	tracerName  = "b7s.RaftCluster"
+	spanExecute = "RaftExecute"
 )
 
 var (
@@ -17,6 +17,7 @@ import (
 
 	"github.com/blocklessnetwork/b7s/consensus"
 	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/log/hclog"
 	"github.com/blocklessnetwork/b7s/models/blockless"
 )
 
@@ -126,11 +127,15 @@ func newReplica(log zerolog.Logger, host *host.Host, workspace string, requestID
 		return nil, fmt.Errorf("could not create stable store (path: %s): %w", stableDB, err)
 	}
 
-	// Create snapshot store. We never really expect we'll need snapshots
-	// since our clusters are short lived, so this should be fine.
-	snapshot := raft.NewDiscardSnapshotStore()
+	// Create snapshot store. Snapshots compact the log of long-lived clusters, and allow a replica
+	// restarted with the same workspace to restore from the snapshot instead of replaying the full log.
+	// Snapshots are kept in a subdirectory of the consensus work directory.
+	snapshot, err := raft.NewFileSnapshotStoreWithLogger(rootDir, cfg.SnapshotRetain, hclog.New(log).Named("raft-snapshot"))
+	if err != nil {
+		return nil, fmt.Errorf("could not create snapshot store (path: %s): %w", rootDir, err)
+	}
 
-	fsm := newFsmExecutor(log, executor, cfg.Callbacks...)
+	fsm := newFsmExecutor(log, executor, cfg.TraceInfo, cfg.Callbacks...)
 
 	raftCfg := getRaftConfig(cfg, log, host.ID().String())
 
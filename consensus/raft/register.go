@@ -0,0 +1,38 @@
+package raft
+
+import (
+	"context"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+func init() {
+	consensus.Register(consensus.Raft, "Raft", createReplica)
+}
+
+func createReplica(env consensus.Environment, cluster consensus.Cluster) (consensus.Replica, error) {
+	return New(env.Log, env.Host, env.Workspace, cluster.ID, env.Executor, cluster.Peers, EnvironmentOptions(env)...)
+}
+
+// EnvironmentOptions returns the options for replicas running in the given environment.
+// Raft replicas do not send execution results themselves, so results are delivered through the environment.
+func EnvironmentOptions(env consensus.Environment) []Option {
+
+	var callbacks []FSMProcessFunc
+	if env.Executed != nil {
+		callbacks = append(callbacks, func(_ context.Context, req FSMLogEntry, res execute.NodeResult) {
+			env.Executed(req.RequestID, req.Origin, req.Execute, res)
+		})
+	}
+	if env.Deliver != nil {
+		callbacks = append(callbacks, func(ctx context.Context, req FSMLogEntry, res execute.NodeResult) {
+			env.Deliver(ctx, req.RequestID, req.Origin, req.Execute, res)
+		})
+	}
+
+	return []Option{
+		WithCallbacks(callbacks...),
+		WithTraceInfo(env.TraceInfo),
+	}
+}
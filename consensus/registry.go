@@ -0,0 +1,169 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/rs/zerolog"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/metadata"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/telemetry/tracing"
+)
+
+// Replica is a member of a consensus cluster, executing requests once the cluster agrees on them.
+// Execute often does not mean a direct execution but instead just pipelining the request, where execution is done asynchronously.
+type Replica interface {
+	Consensus() Type
+	Execute(from peer.ID, id string, timestamp time.Time, request execute.Request) (codes.Code, execute.Result, error)
+	Shutdown() error
+}
+
+// Environment describes the node a replica runs on.
+type Environment struct {
+	Log              zerolog.Logger
+	Host             *host.Host
+	Executor         blockless.Executor
+	Workspace        string
+	MetadataProvider metadata.Provider
+	TraceInfo        tracing.TraceInfo
+
+	// Executed is invoked after the replica executes a request.
+	Executed func(requestID string, origin peer.ID, request execute.Request, result execute.NodeResult)
+	// Deliver sends the execution result to the origin of the request, for algorithms where replicas do not send results themselves.
+	// Context carries the span of the execution.
+	Deliver func(ctx context.Context, requestID string, origin peer.ID, request execute.Request, result execute.NodeResult)
+	// Misbehaved is invoked when the replica catches another cluster member misbehaving, for algorithms that detect it.
+	Misbehaved func(misbehavior Misbehavior)
+}
+
+// Cluster describes the consensus cluster a replica is a member of.
+type Cluster struct {
+	ID    string    // Cluster ID, same as the ID of the request the cluster is formed for.
+	Peers []peer.ID // Cluster members.
+	// RequestTimeout is the inactivity period after which replicas replace the leader, for algorithms that support it.
+	// Zero means the algorithm default is used.
+	RequestTimeout time.Duration
+}
+
+// Factory creates a replica of the consensus cluster.
+type Factory func(env Environment, cluster Cluster) (Replica, error)
+
+type backend struct {
+	name        string
+	factory     Factory
+	minReplicas int
+}
+
+// BackendOption describes a property of a registered consensus algorithm.
+type BackendOption func(*backend)
+
+// WithMinimumReplicas sets the smallest cluster the algorithm can run on.
+func WithMinimumReplicas(n int) BackendOption {
+	return func(b *backend) {
+		b.minReplicas = n
+	}
+}
+
+var registry = struct {
+	sync.RWMutex
+	backends map[Type]backend
+}{
+	backends: make(map[Type]backend),
+}
+
+// Register makes the consensus algorithm available under the given type and name. It is meant to be called from the init function
+// of the package implementing the algorithm. Register panics if the type or the name is already taken.
+func Register(t Type, name string, factory Factory, opts ...BackendOption) {
+
+	if t == 0 || name == "" {
+		panic("consensus: type and name are required")
+	}
+
+	if factory == nil {
+		panic("consensus: factory is required")
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	for existing, backend := range registry.backends {
+		if existing == t || strings.EqualFold(backend.name, name) {
+			panic(fmt.Sprintf("consensus: algorithm already registered (type: %d, name: %s)", t, name))
+		}
+	}
+
+	b := backend{
+		name:    name,
+		factory: factory,
+	}
+	for _, opt := range opts {
+		opt(&b)
+	}
+
+	registry.backends[t] = b
+}
+
+// Registered returns the consensus algorithms available in this build.
+func Registered() []Type {
+
+	registry.RLock()
+	defer registry.RUnlock()
+
+	types := make([]Type, 0, len(registry.backends))
+	for t := range registry.backends {
+		types = append(types, t)
+	}
+
+	slices.Sort(types)
+
+	return types
+}
+
+// MinimumReplicas returns the smallest cluster the consensus algorithm can run on. Zero means there is no minimum, or the algorithm is unknown.
+func MinimumReplicas(t Type) int {
+
+	backend, ok := lookup(t)
+	if !ok {
+		return 0
+	}
+
+	return backend.minReplicas
+}
+
+// New creates a replica of the consensus cluster, using the registered implementation of the algorithm.
+func New(t Type, env Environment, cluster Cluster) (Replica, error) {
+
+	backend, ok := lookup(t)
+	if !ok {
+		return nil, fmt.Errorf("unknown consensus algorithm (%d)", t)
+	}
+
+	if len(cluster.Peers) == 0 {
+		return nil, errors.New("cluster peers are required")
+	}
+
+	replica, err := backend.factory(env, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("could not create %s replica: %w", backend.name, err)
+	}
+
+	return replica, nil
+}
+
+func lookup(t Type) (backend, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	backend, ok := registry.backends[t]
+	return backend, ok
+}
@@ -0,0 +1,90 @@
+package consensus
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+type dummyReplica struct {
+	cluster Cluster
+}
+
+func (r dummyReplica) Consensus() Type { return dummyConsensus }
+func (r dummyReplica) Shutdown() error { return nil }
+func (r dummyReplica) Execute(peer.ID, string, time.Time, execute.Request) (codes.Code, execute.Result, error) {
+	return codes.OK, execute.Result{}, nil
+}
+
+const (
+	dummyConsensus   Type = 100
+	failingConsensus Type = 101
+)
+
+func TestRegistry(t *testing.T) {
+
+	Register(dummyConsensus, "Dummy", func(_ Environment, cluster Cluster) (Replica, error) {
+		return dummyReplica{cluster: cluster}, nil
+	}, WithMinimumReplicas(3))
+	Register(failingConsensus, "Failing", func(Environment, Cluster) (Replica, error) {
+		return nil, errors.New("dummy error")
+	})
+
+	t.Run("registered algorithms are known", func(t *testing.T) {
+		require.True(t, dummyConsensus.Valid())
+		require.Equal(t, "Dummy", dummyConsensus.String())
+
+		parsed, err := Parse("dUmMy")
+		require.NoError(t, err)
+		require.Equal(t, dummyConsensus, parsed)
+	})
+	t.Run("registered algorithms are listed", func(t *testing.T) {
+		require.Contains(t, Registered(), dummyConsensus)
+		require.Contains(t, Registered(), failingConsensus)
+
+		require.Equal(t, 3, MinimumReplicas(dummyConsensus))
+		require.Zero(t, MinimumReplicas(failingConsensus))
+		require.Zero(t, MinimumReplicas(Type(200)))
+	})
+	t.Run("unknown algorithms are rejected", func(t *testing.T) {
+		require.False(t, Type(200).Valid())
+
+		_, err := Parse("unknown")
+		require.Error(t, err)
+
+		_, err = New(Type(200), Environment{}, Cluster{Peers: mocks.GenericPeerIDs[:1]})
+		require.Error(t, err)
+	})
+	t.Run("no consensus", func(t *testing.T) {
+		parsed, err := Parse("")
+		require.NoError(t, err)
+		require.Zero(t, parsed)
+	})
+	t.Run("replicas are created by the factory", func(t *testing.T) {
+		cluster := Cluster{
+			ID:    mocks.GenericUUID.String(),
+			Peers: mocks.GenericPeerIDs[:4],
+		}
+
+		replica, err := New(dummyConsensus, Environment{}, cluster)
+		require.NoError(t, err)
+		require.Equal(t, cluster, replica.(dummyReplica).cluster)
+
+		_, err = New(failingConsensus, Environment{}, cluster)
+		require.Error(t, err)
+	})
+	t.Run("algorithms cannot be registered twice", func(t *testing.T) {
+		factory := func(Environment, Cluster) (Replica, error) { return dummyReplica{}, nil }
+
+		require.Panics(t, func() { Register(dummyConsensus, "Another", factory) })
+		require.Panics(t, func() { Register(Type(200), "Dummy", factory) })
+		require.Panics(t, func() { Register(Type(200), "Another", nil) })
+	})
+}
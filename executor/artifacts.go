@@ -0,0 +1,89 @@
+package executor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+// Directory in the function FS root where the function writes the files to be uploaded as artifacts.
+const artifactsDir = "artifacts"
+
+// ArtifactStore uploads output files of executions to blob storage.
+type ArtifactStore interface {
+	Upload(ctx context.Context, key string, data io.Reader, size int64) (execute.Artifact, error)
+}
+
+// uploadArtifacts uploads the files the function wrote to its artifacts directory. Files are stored under the worker ID
+// and the request ID, since request IDs are only unique per head node.
+func (e *Executor) uploadArtifacts(ctx context.Context, requestID string, dir string) ([]execute.Artifact, error) {
+
+	maxSize := e.cfg.ArtifactMaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultArtifactMaxSize
+	}
+
+	var artifacts []execute.Artifact
+	err := afero.Walk(e.cfg.FS, dir, func(name string, info fs.FileInfo, err error) error {
+		if err != nil {
+			// Function did not produce any artifacts.
+			if name == dir && errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return fmt.Errorf("could not determine artifact name: %w", err)
+		}
+		rel = filepath.ToSlash(rel)
+
+		if info.Size() > maxSize {
+			return fmt.Errorf("artifact too large (name: %s, size: %d, limit: %d)", rel, info.Size(), maxSize)
+		}
+
+		f, err := e.cfg.FS.Open(name)
+		if err != nil {
+			return fmt.Errorf("could not open artifact (name: %s): %w", rel, err)
+		}
+		defer f.Close()
+
+		// Hash the file as it is uploaded, and never read more than the size the store was told about.
+		hash := sha256.New()
+		data := io.TeeReader(io.LimitReader(f, info.Size()), hash)
+
+		artifact, err := e.cfg.Artifacts.Upload(ctx, path.Join(e.cfg.NodeID, requestID, rel), data, info.Size())
+		if err != nil {
+			return fmt.Errorf("could not upload artifact (name: %s): %w", rel, err)
+		}
+
+		artifact.Name = rel
+		artifact.Checksum = hex.EncodeToString(hash.Sum(nil))
+		artifacts = append(artifacts, artifact)
+
+		e.metrics.IncrCounter(artifactsUploadedMetric, 1)
+		e.metrics.IncrCounter(artifactBytesUploadedMetric, float32(info.Size()))
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return artifacts, nil
+}
@@ -0,0 +1,119 @@
+package executor
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/armon/go-metrics"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+type testArtifactStore struct {
+	uploaded map[string]string
+	err      error
+}
+
+func (s *testArtifactStore) Upload(_ context.Context, key string, data io.Reader, size int64) (execute.Artifact, error) {
+
+	if s.err != nil {
+		return execute.Artifact{}, s.err
+	}
+
+	payload, err := io.ReadAll(data)
+	if err != nil {
+		return execute.Artifact{}, err
+	}
+	s.uploaded[key] = string(payload)
+
+	return execute.Artifact{Size: size, URL: "https://example.com/" + key}, nil
+}
+
+func TestExecutor_UploadArtifacts(t *testing.T) {
+
+	const (
+		nodeID    = "dummy-node-id"
+		requestID = "dummy-request-id"
+		dir       = "/var/tmp/b7s/t/dummy-request-id/fs/artifacts"
+	)
+
+	newExecutor := func(fs afero.Fs, store ArtifactStore) *Executor {
+		return &Executor{
+			log:     mocks.NoopLogger,
+			metrics: metrics.Default(),
+			cfg: Config{
+				FS:        fs,
+				Artifacts: store,
+				NodeID:    nodeID,
+			},
+		}
+	}
+
+	t.Run("nominal case", func(t *testing.T) {
+
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, filepath.Join(dir, "report.csv"), []byte("a,b,c"), defaultPermissions))
+		require.NoError(t, afero.WriteFile(fs, filepath.Join(dir, "images", "chart.png"), []byte("png"), defaultPermissions))
+
+		store := &testArtifactStore{uploaded: make(map[string]string)}
+		executor := newExecutor(fs, store)
+
+		artifacts, err := executor.uploadArtifacts(context.Background(), requestID, dir)
+		require.NoError(t, err)
+
+		expected := []execute.Artifact{
+			{
+				Name:     "images/chart.png",
+				Size:     3,
+				Checksum: "8f8cbb7dcf46e0bc7d53265749a6c17d116093a6ba95e442764060c76fd4a86c",
+				URL:      "https://example.com/dummy-node-id/dummy-request-id/images/chart.png",
+			},
+			{
+				Name:     "report.csv",
+				Size:     5,
+				Checksum: "205830ca5b23bbe39ab510cfddc1dff2d9842e38b5fa7b7c48cd4ca7e44f92a1",
+				URL:      "https://example.com/dummy-node-id/dummy-request-id/report.csv",
+			},
+		}
+		require.Equal(t, expected, artifacts)
+
+		require.Equal(t, "a,b,c", store.uploaded["dummy-node-id/dummy-request-id/report.csv"])
+		require.Equal(t, "png", store.uploaded["dummy-node-id/dummy-request-id/images/chart.png"])
+	})
+	t.Run("artifact too large", func(t *testing.T) {
+
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, filepath.Join(dir, "report.csv"), []byte("a,b,c"), defaultPermissions))
+
+		store := &testArtifactStore{uploaded: make(map[string]string)}
+		executor := newExecutor(fs, store)
+		executor.cfg.ArtifactMaxSize = 4
+
+		_, err := executor.uploadArtifacts(context.Background(), requestID, dir)
+		require.Error(t, err)
+		require.Empty(t, store.uploaded)
+	})
+	t.Run("no artifacts directory", func(t *testing.T) {
+
+		executor := newExecutor(afero.NewMemMapFs(), &testArtifactStore{uploaded: make(map[string]string)})
+
+		artifacts, err := executor.uploadArtifacts(context.Background(), requestID, dir)
+		require.NoError(t, err)
+		require.Empty(t, artifacts)
+	})
+	t.Run("upload fails", func(t *testing.T) {
+
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, filepath.Join(dir, "report.csv"), []byte("a,b,c"), defaultPermissions))
+
+		executor := newExecutor(fs, &testArtifactStore{err: mocks.GenericError})
+
+		_, err := executor.uploadArtifacts(context.Background(), requestID, dir)
+		require.ErrorIs(t, err, mocks.GenericError)
+	})
+}
@@ -1,9 +1,9 @@
 package executor
 
 import (
+	"context"
 	"fmt"
 	"io"
-	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
@@ -12,7 +12,8 @@ import (
 )
 
 // createCmd will create the command to be executed, prepare working directory, environment, standard input and all else.
-func (e *Executor) createCmd(paths requestPaths, req execute.Request) *exec.Cmd {
+// The process is killed if the context is done before it exits.
+func (e *Executor) createCmd(ctx context.Context, paths requestPaths, req execute.Request) *exec.Cmd {
 
 	// Prepare command to be executed.
 	exePath := filepath.Join(e.cfg.RuntimeDir, e.cfg.ExecutableName)
@@ -21,6 +22,7 @@ func (e *Executor) createCmd(paths requestPaths, req execute.Request) *exec.Cmd
 	cfg.Input = paths.input
 	cfg.FSRoot = paths.fsRoot
 	cfg.DriversRootPath = e.cfg.DriversRootPath
+	cfg.ModuleCache = paths.moduleCache
 
 	// Prepare CLI arguments.
 	// Append the input argument first first.
@@ -41,7 +43,7 @@ func (e *Executor) createCmd(paths requestPaths, req execute.Request) *exec.Cmd
 		}
 	}
 
-	cmd := exec.Command(exePath, args...)
+	cmd := exec.CommandContext(ctx, exePath, args...)
 	cmd.Dir = paths.workdir
 
 	// Setup stdin of the command.
@@ -52,8 +54,8 @@ func (e *Executor) createCmd(paths requestPaths, req execute.Request) *exec.Cmd
 	cmd.Stdin = stdin
 
 	// Setup environment.
-	// First, pass through our environment variables.
-	cmd.Env = os.Environ()
+	// First, pass through our environment variables - only some of them for executions of tenants.
+	cmd.Env = runtimeEnvironment(req.Config.Tenant, paths.workdir)
 
 	// Second, set the variables set in the execution request.
 	names := make([]string, 0, len(req.Config.Environment))
@@ -64,11 +66,14 @@ func (e *Executor) createCmd(paths requestPaths, req execute.Request) *exec.Cmd
 		names = append(names, env.Name)
 	}
 
-	// Third and final - set the `BLS_LIST_VARS` variable with
+	// Third - set the `BLS_LIST_VARS` variable with
 	// the list of names of the variables from the execution request.
 	blsList := strings.Join(names, ";")
 	blsEnv := fmt.Sprintf("%s=%s", blsListEnvName, blsList)
 	cmd.Env = append(cmd.Env, blsEnv)
 
+	// Finally, select the GPU devices the runtime can use. These cannot be overridden by the execution request.
+	cmd.Env = append(cmd.Env, e.gpuEnv(req.Config.Attributes.RequiresGPU())...)
+
 	return cmd
 }
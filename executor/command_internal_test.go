@@ -1,6 +1,7 @@
 package executor
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -51,10 +52,10 @@ func TestExecute_CreateCMD(t *testing.T) {
 			ExecutableName: blockless.RuntimeCLI(),
 		},
 	}
-	paths := executor.generateRequestPaths(requestID, functionID, functionMethod)
+	paths := executor.generateRequestPaths(requestID, functionID, functionMethod, "")
 
 	// Create command.
-	cmd := executor.createCmd(paths, request)
+	cmd := executor.createCmd(context.Background(), paths, request)
 	require.NotNil(t, cmd)
 
 	// Verify command to be executed is correct.
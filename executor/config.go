@@ -1,6 +1,8 @@
 package executor
 
 import (
+	"time"
+
 	"github.com/armon/go-metrics"
 	"github.com/spf13/afero"
 
@@ -15,8 +17,23 @@ var defaultConfig = Config{
 	FS:              afero.NewOsFs(),
 	Limiter:         &noopLimiter{},
 	DriversRootPath: "",
+	IPFSGateway:     DefaultIPFSGateway,
 }
 
+const (
+	// DefaultIPFSGateway is the gateway IPFS inputs of executions are fetched through.
+	DefaultIPFSGateway = "https://w3s.link"
+
+	// DefaultInputCacheTTL is how long cached inputs of executions are used before they are fetched again.
+	DefaultInputCacheTTL = time.Hour
+
+	// DefaultInputMaxSize is the maximum size of a single input of an execution, if no other limit is set.
+	DefaultInputMaxSize = 1 << 30
+
+	// DefaultArtifactMaxSize is the maximum size of a single artifact of an execution, if no other limit is set.
+	DefaultArtifactMaxSize = 1 << 30
+)
+
 // Config represents the Executor configuration.
 type Config struct {
 	WorkDir         string           // directory where files needed for the execution are stored
@@ -26,6 +43,19 @@ type Config struct {
 	FS              afero.Fs         // FS accessor
 	Limiter         Limiter          // Resource limiter for executed processes
 	Metrics         *metrics.Metrics // Metrics handle
+	ModuleCacheDir  string           // directory where compiled WASM modules are cached
+	ModuleCacheSize int64            // maximum size of the compiled module cache in bytes, zero means no limit
+	Reuse           ReusePolicy      // when can runtime processes be reused for multiple executions
+	Warm            WarmPolicy       // how many runtime processes are started ahead of executions
+	Artifacts       ArtifactStore    // blob storage for output files of executions, nil means output files are not uploaded
+	ArtifactMaxSize int64            // maximum size of a single artifact in bytes, zero means the default limit
+	NodeID          string           // ID of the worker, used to keep artifacts from different workers apart
+	GPUDevices      []string         // GPU devices available to executions requiring GPUs
+	InputCacheDir   string           // directory where remote inputs of executions are cached
+	InputCacheSize  int64            // maximum size of the input cache in bytes, zero means no limit
+	InputCacheTTL   time.Duration    // how long are cached inputs used before they are fetched again
+	InputMaxSize    int64            // maximum size of a single input in bytes, zero means the default limit
+	IPFSGateway     string           // gateway IPFS inputs are fetched through
 }
 
 type Option func(*Config)
@@ -71,3 +101,77 @@ func WithMetrics(metrics *metrics.Metrics) Option {
 		cfg.Metrics = metrics
 	}
 }
+
+// WithModuleCache enables caching of compiled WASM modules in the given directory, with the given size limit (in bytes).
+func WithModuleCache(dir string, size int64) Option {
+	return func(cfg *Config) {
+		cfg.ModuleCacheDir = dir
+		cfg.ModuleCacheSize = size
+	}
+}
+
+// WithProcessReuse sets the policy for reusing runtime processes across executions.
+func WithProcessReuse(policy ReusePolicy) Option {
+	return func(cfg *Config) {
+		cfg.Reuse = policy
+	}
+}
+
+// WithArtifactStore sets the blob storage the output files of executions are uploaded to.
+func WithArtifactStore(store ArtifactStore) Option {
+	return func(cfg *Config) {
+		cfg.Artifacts = store
+	}
+}
+
+// WithArtifactMaxSize sets the maximum size (in bytes) of a single output file uploaded as an artifact.
+func WithArtifactMaxSize(size int64) Option {
+	return func(cfg *Config) {
+		cfg.ArtifactMaxSize = size
+	}
+}
+
+// WithNodeID sets the ID of the worker the executor runs on.
+func WithNodeID(id string) Option {
+	return func(cfg *Config) {
+		cfg.NodeID = id
+	}
+}
+
+// WithGPUDevices sets the GPU devices (as device indexes) the runtime can use for executions requiring GPUs.
+func WithGPUDevices(devices []string) Option {
+	return func(cfg *Config) {
+		cfg.GPUDevices = devices
+	}
+}
+
+// WithInputCache enables caching of remote inputs of executions in the given directory, with the given size limit (in bytes).
+// Cached inputs are fetched again once they are older than the TTL.
+func WithInputCache(dir string, size int64, ttl time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.InputCacheDir = dir
+		cfg.InputCacheSize = size
+		cfg.InputCacheTTL = ttl
+	}
+}
+
+// WithInputMaxSize sets the maximum size (in bytes) of a single remote input of an execution.
+func WithInputMaxSize(size int64) Option {
+	return func(cfg *Config) {
+		cfg.InputMaxSize = size
+	}
+}
+
+// WithIPFSGateway sets the gateway IPFS inputs of executions are fetched through.
+func WithIPFSGateway(gateway string) Option {
+	return func(cfg *Config) {
+		cfg.IPFSGateway = gateway
+	}
+}
+
+// WithWarmPool sets the policy for starting runtime processes ahead of executions, so executions do not wait for the runtime to start.
+func WithWarmPool(policy WarmPolicy) Option {
+	return func(cfg *Config) {
+		cfg.Warm = policy
+	}
+}
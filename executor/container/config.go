@@ -0,0 +1,94 @@
+package container
+
+import (
+	"errors"
+)
+
+const (
+	DefaultCLI       = "docker"
+	DefaultNetwork   = "none"
+	DefaultPidsLimit = 256
+)
+
+// DefaultConfig uses the Docker CLI and runs containers without network access and with a limited number of processes.
+var DefaultConfig = Config{
+	CLI:       DefaultCLI,
+	Network:   DefaultNetwork,
+	PidsLimit: DefaultPidsLimit,
+}
+
+// Config represents the container executor configuration.
+type Config struct {
+	CLI           string   // OCI container engine CLI used to run containers, e.g. `docker` or `podman`.
+	Network       string   // Network the containers are attached to.
+	MemoryLimitKB int64    // Memory limit of each container, in kB. Zero is unlimited.
+	CPULimit      float64  // Number of CPUs each container can use. Zero is unlimited.
+	PidsLimit     int64    // Number of processes each container can run. Zero is unlimited.
+	GPUDevices    []string // GPU devices (as device indexes) available to containers of executions requiring GPUs.
+}
+
+// Valid checks if the configuration is correct.
+func (c Config) Valid() error {
+
+	if c.CLI == "" {
+		return errors.New("container engine CLI is required")
+	}
+
+	if c.MemoryLimitKB < 0 {
+		return errors.New("memory limit cannot be negative")
+	}
+
+	if c.CPULimit < 0 {
+		return errors.New("CPU limit cannot be negative")
+	}
+
+	if c.PidsLimit < 0 {
+		return errors.New("process limit cannot be negative")
+	}
+
+	return nil
+}
+
+type Option func(*Config)
+
+// WithCLI sets the container engine CLI used to run containers.
+func WithCLI(cli string) Option {
+	return func(cfg *Config) {
+		cfg.CLI = cli
+	}
+}
+
+// WithNetwork sets the network containers are attached to.
+func WithNetwork(network string) Option {
+	return func(cfg *Config) {
+		cfg.Network = network
+	}
+}
+
+// WithMemoryLimit sets the memory limit of each container, in kB.
+func WithMemoryLimit(kb int64) Option {
+	return func(cfg *Config) {
+		cfg.MemoryLimitKB = kb
+	}
+}
+
+// WithCPULimit sets the number of CPUs each container can use.
+func WithCPULimit(cpus float64) Option {
+	return func(cfg *Config) {
+		cfg.CPULimit = cpus
+	}
+}
+
+// WithPidsLimit sets the number of processes each container can run.
+func WithPidsLimit(pids int64) Option {
+	return func(cfg *Config) {
+		cfg.PidsLimit = pids
+	}
+}
+
+// WithGPUDevices sets the GPU devices available to containers of executions requiring GPUs.
+func WithGPUDevices(devices []string) Option {
+	return func(cfg *Config) {
+		cfg.GPUDevices = devices
+	}
+}
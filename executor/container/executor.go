@@ -0,0 +1,262 @@
+// Package container provides an executor running Blockless functions packaged as OCI container images, instead of WASM modules.
+// Containers are run using a container engine CLI, such as Docker or Podman, so heavier workloads can run on workers that have one.
+package container
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+const (
+	blsListEnvName = "BLS_LIST_VARS"
+	requestLabel   = "b7s.request"
+	tenantLabel    = "b7s.tenant"
+
+	// How long to wait for the output of a stopped container to be flushed.
+	killWaitDelay = time.Second
+)
+
+var (
+	ErrNotContainer       = errors.New("function is not packaged as a container")
+	errImageNotPinned     = errors.New("container image is not pinned by digest")
+	errGPUNotAvailable    = errors.New("execution requires GPUs but none are available")
+	errInputsNotSupported = errors.New("remote inputs are not supported for container executions")
+)
+
+// FunctionStore provides the manifests of installed functions.
+type FunctionStore interface {
+	Get(ctx context.Context, cid string) (blockless.FunctionRecord, error)
+}
+
+// Executor runs functions as OCI containers, using the image set in the function manifest.
+type Executor struct {
+	log       zerolog.Logger
+	cfg       Config
+	functions FunctionStore
+}
+
+// New creates a new container executor.
+func New(log zerolog.Logger, functions FunctionStore, options ...Option) (*Executor, error) {
+
+	cfg := DefaultConfig
+	for _, option := range options {
+		option(&cfg)
+	}
+
+	err := cfg.Valid()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	e := Executor{
+		log:       log,
+		cfg:       cfg,
+		functions: functions,
+	}
+
+	return &e, nil
+}
+
+// ExecuteFunction runs the function container and waits for it to exit. Containers running past the execution timeout are killed.
+func (e *Executor) ExecuteFunction(ctx context.Context, requestID string, req execute.Request) (execute.Result, error) {
+
+	log := e.log.With().Str("request", requestID).Str("function", req.FunctionID).Logger()
+
+	fn, err := e.functions.Get(ctx, req.FunctionID)
+	if err != nil {
+		return execute.Result{Code: codes.Error}, fmt.Errorf("could not get function manifest: %w", err)
+	}
+
+	spec := fn.Manifest.Container
+	if spec == nil {
+		return execute.Result{Code: codes.Error}, ErrNotContainer
+	}
+
+	// Tags can be moved, so different workers could run different code for the same function.
+	if !pinned(spec.Image) {
+		return execute.Result{Code: codes.Error}, errImageNotPinned
+	}
+
+	if req.Config.Attributes.RequiresGPU() && len(e.cfg.GPUDevices) == 0 {
+		return execute.Result{Code: codes.Error}, errGPUNotAvailable
+	}
+
+	if len(req.Config.Inputs) > 0 {
+		return execute.Result{Code: codes.Error}, errInputsNotSupported
+	}
+
+	if req.Config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(req.Config.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	// The same request can be dispatched to this worker more than once (e.g. on retries), so names are unique per container.
+	name := containerName()
+
+	var (
+		stdout bytes.Buffer
+		stderr bytes.Buffer
+	)
+	cmd := exec.CommandContext(ctx, e.cfg.CLI, e.runArgs(name, requestID, *spec, req)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if req.Config.Stdin != nil {
+		cmd.Stdin = strings.NewReader(*req.Config.Stdin)
+	}
+
+	// Stopping the CLI does not stop the container, so kill the container too.
+	cmd.Cancel = func() error {
+		err := exec.Command(e.cfg.CLI, "kill", name).Run()
+		if err != nil {
+			log.Warn().Err(err).Str("container", name).Msg("could not kill container")
+		}
+		return cmd.Process.Kill()
+	}
+	cmd.WaitDelay = killWaitDelay
+
+	log.Debug().Str("image", spec.Image).Str("cmd", cmd.String()).Msg("running function container")
+
+	start := time.Now()
+	cmdErr := cmd.Run()
+
+	out := execute.RuntimeOutput{
+		Stdout: stdout.String(),
+		Stderr: stderr.String(),
+	}
+	if cmd.ProcessState != nil {
+		out.ExitCode = cmd.ProcessState.ExitCode()
+	}
+
+	res := execute.Result{
+		Code:   codes.OK,
+		Result: out,
+		Usage: execute.Usage{
+			WallClockTime: time.Since(start),
+		},
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		res.Code = codes.Timeout
+		return res, fmt.Errorf("container killed after execution timeout: %w", ctx.Err())
+	}
+
+	if cmdErr != nil {
+		res.Code = codes.Error
+		return res, fmt.Errorf("container execution failed: %w", cmdErr)
+	}
+
+	return res, nil
+}
+
+// runArgs returns the container engine CLI arguments running the function container.
+func (e *Executor) runArgs(name string, requestID string, spec blockless.Container, req execute.Request) []string {
+
+	args := []string{
+		"run", "--rm",
+		"--name", name,
+		"--label", fmt.Sprintf("%s=%s", requestLabel, requestID),
+		"--network", e.cfg.Network,
+		"--cap-drop", "ALL",
+		"--security-opt", "no-new-privileges",
+	}
+
+	if req.Config.Tenant != "" {
+		args = append(args, "--label", fmt.Sprintf("%s=%s", tenantLabel, req.Config.Tenant))
+	}
+
+	if e.cfg.PidsLimit > 0 {
+		args = append(args, "--pids-limit", strconv.FormatInt(e.cfg.PidsLimit, 10))
+	}
+
+	// Executions can lower the limits of the worker, but not raise them.
+	var limits execute.ResourceLimits
+	if req.Config.Limits != nil {
+		limits = *req.Config.Limits
+	}
+
+	memory := lowestLimit(e.cfg.MemoryLimitKB, limits.MemoryKB)
+	if memory > 0 {
+		args = append(args, "--memory", fmt.Sprintf("%dk", memory))
+	}
+
+	cpus := lowestLimit(e.cfg.CPULimit, limits.CPUPercentage)
+	if cpus > 0 {
+		args = append(args, "--cpus", strconv.FormatFloat(cpus, 'f', -1, 64))
+	}
+
+	if limits.FileDescriptors > 0 {
+		args = append(args, "--ulimit", fmt.Sprintf("nofile=%d:%d", limits.FileDescriptors, limits.FileDescriptors))
+	}
+
+	if req.Config.Stdin != nil {
+		args = append(args, "--interactive")
+	}
+
+	// The device list is quoted, since the engine treats the flag value as CSV.
+	if req.Config.Attributes.RequiresGPU() {
+		args = append(args, "--gpus", fmt.Sprintf(`"device=%s"`, strings.Join(e.cfg.GPUDevices, ",")))
+	}
+
+	// Set the variables from the execution request, along with the list of their names - same as the Blockless Runtime does.
+	names := make([]string, 0, len(req.Config.Environment))
+	for _, env := range req.Config.Environment {
+		args = append(args, "--env", fmt.Sprintf("%s=%s", env.Name, env.Value))
+		names = append(names, env.Name)
+	}
+	args = append(args, "--env", fmt.Sprintf("%s=%s", blsListEnvName, strings.Join(names, ";")))
+
+	args = append(args, spec.Image)
+	args = append(args, spec.Command...)
+
+	for _, param := range req.Parameters {
+		if param.Value != "" {
+			args = append(args, param.Value)
+		}
+	}
+
+	return args
+}
+
+func containerName() string {
+	return "b7s-" + uuid.NewString()
+}
+
+// pinned returns true if the image reference includes a SHA-256 digest.
+func pinned(image string) bool {
+
+	_, digest, found := strings.Cut(image, "@sha256:")
+	if !found || len(digest) != sha256.Size*2 {
+		return false
+	}
+
+	_, err := hex.DecodeString(digest)
+	return err == nil
+}
+
+// lowestLimit returns the lower of the two limits, with zero meaning no limit.
+func lowestLimit[T int64 | float64](a, b T) T {
+	switch {
+	case a <= 0:
+		return b
+	case b <= 0:
+		return a
+	default:
+		return min(a, b)
+	}
+}
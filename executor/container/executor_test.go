@@ -0,0 +1,229 @@
+package container_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/executor/container"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+// Fake container engine CLI - echoes its arguments and input, or fails or hangs for specific images.
+const fakeCLI = `#!/bin/sh
+[ "$1" = "kill" ] && exit 0
+echo "$@"
+case "$*" in
+  *fail-image*) echo "failed" >&2; exit 3 ;;
+  *slow-image*) exec sleep 10 ;;
+esac
+[ -p /dev/stdin ] && cat
+exit 0
+`
+
+func TestExecutor_Create(t *testing.T) {
+	t.Run("nominal case", func(t *testing.T) {
+		_, err := container.New(mocks.NoopLogger, mocks.BaselineFStore(t),
+			container.WithCLI("podman"),
+			container.WithMemoryLimit(1024),
+			container.WithCPULimit(0.5),
+		)
+		require.NoError(t, err)
+	})
+	t.Run("missing CLI", func(t *testing.T) {
+		_, err := container.New(mocks.NoopLogger, mocks.BaselineFStore(t), container.WithCLI(""))
+		require.Error(t, err)
+	})
+	t.Run("negative limits", func(t *testing.T) {
+		_, err := container.New(mocks.NoopLogger, mocks.BaselineFStore(t), container.WithMemoryLimit(-1))
+		require.Error(t, err)
+
+		_, err = container.New(mocks.NoopLogger, mocks.BaselineFStore(t), container.WithCPULimit(-1))
+		require.Error(t, err)
+	})
+}
+
+func TestExecutor_ExecuteFunction(t *testing.T) {
+
+	if runtime.GOOS == "windows" {
+		t.Skip("fake container CLI is a shell script")
+	}
+
+	const (
+		requestID = "dummy-request-id"
+		digest    = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+		image     = "example.com/function@" + digest
+	)
+
+	cli := filepath.Join(t.TempDir(), "docker")
+	err := os.WriteFile(cli, []byte(fakeCLI), 0o755)
+	require.NoError(t, err)
+
+	store := func(image string, command ...string) *mocks.FStore {
+		fstore := mocks.BaselineFStore(t)
+		fstore.GetFunc = func(context.Context, string) (blockless.FunctionRecord, error) {
+			fn := mocks.GenericFunctionRecord
+			fn.Manifest.Container = &blockless.Container{
+				Image:   image,
+				Command: command,
+			}
+			return fn, nil
+		}
+		return fstore
+	}
+
+	t.Run("nominal case", func(t *testing.T) {
+		executor, err := container.New(mocks.NoopLogger, store(image, "/bin/function"),
+			container.WithCLI(cli),
+			container.WithMemoryLimit(1024),
+			container.WithCPULimit(1.5),
+		)
+		require.NoError(t, err)
+
+		stdin := "input"
+		req := execute.Request{
+			FunctionID: "dummy-function-id",
+			Parameters: []execute.Parameter{{Value: "arg1"}, {Value: "arg2"}},
+			Config: execute.Config{
+				Environment: []execute.EnvVar{{Name: "KEY", Value: "value"}},
+				Stdin:       &stdin,
+			},
+		}
+
+		res, err := executor.ExecuteFunction(context.Background(), requestID, req)
+		require.NoError(t, err)
+		require.Equal(t, codes.OK, res.Code)
+		require.Equal(t, 0, res.Result.ExitCode)
+
+		lines := strings.SplitN(res.Result.Stdout, "\n", 2)
+		require.Len(t, lines, 2)
+
+		expected := `^run --rm --name b7s-[0-9a-f-]{36} --label b7s.request=dummy-request-id --network none ` +
+			`--cap-drop ALL --security-opt no-new-privileges --pids-limit 256 ` +
+			`--memory 1024k --cpus 1.5 --interactive --env KEY=value --env BLS_LIST_VARS=KEY ` +
+			`example.com/function@` + digest + ` /bin/function arg1 arg2$`
+		require.Regexp(t, expected, lines[0])
+		require.Equal(t, stdin, lines[1])
+	})
+	t.Run("container names are unique", func(t *testing.T) {
+		executor, err := container.New(mocks.NoopLogger, store(image), container.WithCLI(cli))
+		require.NoError(t, err)
+
+		name := regexp.MustCompile(`--name (\S+)`)
+
+		first, err := executor.ExecuteFunction(context.Background(), requestID, mocks.GenericExecutionRequest)
+		require.NoError(t, err)
+		second, err := executor.ExecuteFunction(context.Background(), requestID, mocks.GenericExecutionRequest)
+		require.NoError(t, err)
+
+		require.NotEqual(t, name.FindString(first.Result.Stdout), name.FindString(second.Result.Stdout))
+	})
+	t.Run("execution limits lower the container limits", func(t *testing.T) {
+		executor, err := container.New(mocks.NoopLogger, store(image),
+			container.WithCLI(cli),
+			container.WithMemoryLimit(1024),
+			container.WithCPULimit(1.5),
+			container.WithPidsLimit(16),
+		)
+		require.NoError(t, err)
+
+		req := mocks.GenericExecutionRequest
+		req.Config.Limits = &execute.ResourceLimits{MemoryKB: 512, CPUPercentage: 2, FileDescriptors: 64}
+
+		res, err := executor.ExecuteFunction(context.Background(), requestID, req)
+		require.NoError(t, err)
+		require.Contains(t, res.Result.Stdout, "--pids-limit 16 --memory 512k --cpus 1.5 --ulimit nofile=64:64")
+	})
+	t.Run("execution limits apply without container limits", func(t *testing.T) {
+		executor, err := container.New(mocks.NoopLogger, store(image), container.WithCLI(cli))
+		require.NoError(t, err)
+
+		req := mocks.GenericExecutionRequest
+		req.Config.Limits = &execute.ResourceLimits{MemoryKB: 512, CPUPercentage: 0.5}
+
+		res, err := executor.ExecuteFunction(context.Background(), requestID, req)
+		require.NoError(t, err)
+		require.Contains(t, res.Result.Stdout, "--memory 512k --cpus 0.5")
+	})
+	t.Run("image not pinned by digest", func(t *testing.T) {
+		for _, image := range []string{
+			"example.com/function:latest",
+			"example.com/function@sha256:0123",
+			"example.com/function@sha256:" + strings.Repeat("x", 64),
+		} {
+			executor, err := container.New(mocks.NoopLogger, store(image), container.WithCLI(cli))
+			require.NoError(t, err)
+
+			res, err := executor.ExecuteFunction(context.Background(), requestID, mocks.GenericExecutionRequest)
+			require.Error(t, err)
+			require.Equal(t, codes.Error, res.Code)
+		}
+	})
+	t.Run("container fails", func(t *testing.T) {
+		executor, err := container.New(mocks.NoopLogger, store("example.com/fail-image@"+digest), container.WithCLI(cli))
+		require.NoError(t, err)
+
+		res, err := executor.ExecuteFunction(context.Background(), requestID, mocks.GenericExecutionRequest)
+		require.Error(t, err)
+		require.Equal(t, codes.Error, res.Code)
+		require.Equal(t, 3, res.Result.ExitCode)
+		require.Equal(t, "failed\n", res.Result.Stderr)
+	})
+	t.Run("container killed after timeout", func(t *testing.T) {
+		executor, err := container.New(mocks.NoopLogger, store("example.com/slow-image@"+digest), container.WithCLI(cli))
+		require.NoError(t, err)
+
+		req := mocks.GenericExecutionRequest
+		req.Config.Timeout = 1
+
+		res, err := executor.ExecuteFunction(context.Background(), requestID, req)
+		require.Error(t, err)
+		require.Equal(t, codes.Timeout, res.Code)
+	})
+	t.Run("execution requiring GPUs", func(t *testing.T) {
+		executor, err := container.New(mocks.NoopLogger, store(image),
+			container.WithCLI(cli),
+			container.WithGPUDevices([]string{"0", "1"}),
+		)
+		require.NoError(t, err)
+
+		req := mocks.GenericExecutionRequest
+		req.Config.Attributes = &execute.Attributes{GPU: &execute.GPURequirements{Count: 2}}
+
+		res, err := executor.ExecuteFunction(context.Background(), requestID, req)
+		require.NoError(t, err)
+		require.Contains(t, res.Result.Stdout, `--gpus "device=0,1"`)
+
+		// Executions not requiring GPUs do not get them.
+		res, err = executor.ExecuteFunction(context.Background(), requestID, mocks.GenericExecutionRequest)
+		require.NoError(t, err)
+		require.NotContains(t, res.Result.Stdout, "--gpus")
+	})
+	t.Run("execution requiring GPUs without GPUs", func(t *testing.T) {
+		executor, err := container.New(mocks.NoopLogger, store(image), container.WithCLI(cli))
+		require.NoError(t, err)
+
+		req := mocks.GenericExecutionRequest
+		req.Config.Attributes = &execute.Attributes{GPU: &execute.GPURequirements{}}
+
+		res, err := executor.ExecuteFunction(context.Background(), requestID, req)
+		require.Error(t, err)
+		require.Equal(t, codes.Error, res.Code)
+	})
+	t.Run("function is not a container", func(t *testing.T) {
+		executor, err := container.New(mocks.NoopLogger, mocks.BaselineFStore(t), container.WithCLI(cli))
+		require.NoError(t, err)
+
+		_, err = executor.ExecuteFunction(context.Background(), requestID, mocks.GenericExecutionRequest)
+		require.ErrorIs(t, err, container.ErrNotContainer)
+	})
+}
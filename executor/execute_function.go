@@ -2,7 +2,9 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/armon/go-metrics"
@@ -14,7 +16,23 @@ import (
 )
 
 // ExecuteFunction will run the Blockless function defined by the execution request.
-func (e *Executor) ExecuteFunction(ctx context.Context, requestID string, req execute.Request) (result execute.Result, retErr error) {
+func (e *Executor) ExecuteFunction(ctx context.Context, requestID string, req execute.Request) (execute.Result, error) {
+	return e.execute(ctx, requestID, req, nil)
+}
+
+// ExecuteInteractive will run the Blockless function with its standard input read from stdin, and its standard output
+// written to stdout as the function produces it. Standard output is not included in the result.
+func (e *Executor) ExecuteInteractive(ctx context.Context, requestID string, req execute.Request, stdin io.Reader, stdout io.Writer) (execute.Result, error) {
+	return e.execute(ctx, requestID, req, &stdio{stdin: stdin, stdout: stdout})
+}
+
+// stdio holds the standard input and output of interactive executions.
+type stdio struct {
+	stdin  io.Reader
+	stdout io.Writer
+}
+
+func (e *Executor) execute(ctx context.Context, requestID string, req execute.Request, streams *stdio) (result execute.Result, retErr error) {
 
 	ml := []metrics.Label{{Name: "function", Value: req.FunctionID}}
 	e.metrics.IncrCounterWithLabels(functionExecutionsMetric, 1, ml)
@@ -39,42 +57,68 @@ func (e *Executor) ExecuteFunction(ctx context.Context, requestID string, req ex
 		trace.WithAttributes(tracing.ExecutionAttributes(requestID, req)...))
 	defer span.End()
 
+	// Let the function know which execution it is, so its logs can be correlated with b7s traces and results.
+	var traceID string
+	if sc := span.SpanContext(); sc.HasTraceID() {
+		traceID = sc.TraceID().String()
+	}
+	req = req.WithCorrelation(requestID, traceID)
+
 	// Execute the function.
-	out, usage, err := e.executeFunction(requestID, req)
+	res, err := e.executeFunction(ctx, requestID, req, streams)
 	if err != nil {
 
-		res := execute.Result{
-			Code:   codes.Error,
-			Result: out,
-			Usage:  usage,
+		res.Code = codes.Error
+		if errors.Is(err, errExecutionTimeout) {
+			res.Code = codes.Timeout
+			e.metrics.IncrCounterWithLabels(functionTimeoutMetric, 1, ml)
 		}
 
 		return res, fmt.Errorf("function execution failed: %w", err)
 	}
 
-	res := execute.Result{
-		Code:   codes.OK,
-		Result: out,
-		Usage:  usage,
-	}
+	res.Code = codes.OK
 
 	return res, nil
 }
 
 // executeFunction handles the actual execution of the Blockless function. It returns the
 // execution information like standard output, standard error, exit code and resource usage.
-func (e *Executor) executeFunction(requestID string, req execute.Request) (execute.RuntimeOutput, execute.Usage, error) {
+// If the execution was done by a pooled runtime process, information about process reuse is returned too.
+// Remote inputs of the execution are fetched, or taken from the input cache, before the execution.
+// Output files the function wrote to its artifacts directory are uploaded to the artifact store, if there is one.
+// For interactive executions, the standard input and output of the function are attached to the given streams.
+func (e *Executor) executeFunction(ctx context.Context, requestID string, req execute.Request, streams *stdio) (execute.Result, error) {
 
 	log := e.log.With().Str("request", requestID).Str("function", req.FunctionID).Logger()
 
 	log.Info().Msg("processing execution request")
 
+	// Do not run executions without the resource limits they asked for.
+	if req.Config.Limits != nil {
+		_, ok := e.cfg.Limiter.(ExecutionLimiter)
+		if !ok {
+			return execute.Result{}, errExecutionLimitsNotSupported
+		}
+	}
+
+	// Tenant ID is used as a directory name, make sure it stays within the workspace.
+	tenant := req.Config.Tenant
+	if tenant != "" && !execute.ValidTenant(tenant) {
+		return execute.Result{}, errInvalidTenant
+	}
+
+	gpuRequired := req.Config.Attributes.RequiresGPU()
+	if gpuRequired && len(e.cfg.GPUDevices) == 0 {
+		return execute.Result{}, errGPUNotAvailable
+	}
+
 	// Generate paths for execution request.
-	paths := e.generateRequestPaths(requestID, req.FunctionID, req.Method)
+	paths := e.generateRequestPaths(requestID, req.FunctionID, req.Method, tenant)
 
 	err := e.cfg.FS.MkdirAll(paths.workdir, defaultPermissions)
 	if err != nil {
-		return execute.RuntimeOutput{}, execute.Usage{}, fmt.Errorf("could not setup working directory for execution (dir: %s): %w", paths.workdir, err)
+		return execute.Result{}, fmt.Errorf("could not setup working directory for execution (dir: %s): %w", paths.workdir, err)
 	}
 	// Remove all temporary files after we're done.
 	defer func() {
@@ -86,17 +130,83 @@ func (e *Executor) executeFunction(requestID string, req execute.Request) (execu
 
 	log.Debug().Str("dir", paths.workdir).Msg("working directory for the request")
 
+	if e.cfg.Artifacts != nil {
+		err = e.cfg.FS.MkdirAll(paths.artifacts, defaultPermissions)
+		if err != nil {
+			return execute.Result{}, fmt.Errorf("could not setup artifacts directory for execution (dir: %s): %w", paths.artifacts, err)
+		}
+	}
+
+	// Place the remote inputs in the function filesystem.
+	err = e.fetchInputs(ctx, log, paths, req)
+	if err != nil {
+		return execute.Result{}, fmt.Errorf("could not prepare inputs: %w", err)
+	}
+
+	// If we have a module cache, let the runtime know where it can find (or store) the compiled module.
+	if e.modules != nil {
+		key, dir, hit, err := e.modules.entry(paths.input, tenant)
+		if err != nil {
+			log.Warn().Err(err).Msg("could not get module cache entry, executing without cache")
+		} else {
+			log.Debug().Str("key", key).Bool("hit", hit).Msg("module cache lookup")
+
+			paths.moduleCache = dir
+			defer func() {
+				err := e.modules.release(key)
+				if err != nil {
+					log.Warn().Err(err).Str("key", key).Msg("could not release module cache entry")
+				}
+			}()
+		}
+	}
+
+	// If runtime processes can be reused, hand the request over to a pooled process.
+	// Executions with their own resource limits, requiring GPUs or interactive ones always get a dedicated process.
+	if e.pool != nil && req.Config.Limits == nil && !gpuRequired && streams == nil {
+
+		out, usage, reuse, err := e.executePooled(ctx, paths, req)
+		if err != nil {
+			return execute.Result{Result: out, Reuse: reuse}, fmt.Errorf("pooled execution failed: %w", err)
+		}
+
+		log.Info().Bool("reused", reuse.Reused).Bool("recycled", reuse.Recycled).Str("reason", reuse.Reason).Msg("pooled process executed request successfully")
+
+		return e.withArtifacts(ctx, requestID, paths, execute.Result{Result: out, Usage: usage, Reuse: reuse})
+	}
+
 	// Create command that will be executed.
-	cmd := e.createCmd(paths, req)
+	cmd := e.createCmd(ctx, paths, req)
+	if streams != nil {
+		cmd.Stdin = streams.stdin
+		cmd.Stdout = streams.stdout
+	}
 
 	log.Debug().Int("env_vars_set", len(cmd.Env)).Str("cmd", cmd.String()).Msg("command ready for execution")
 
-	out, usage, err := e.executeCommand(cmd)
+	out, usage, err := e.executeCommand(ctx, requestID, cmd, req.Config.Limits, executionTimeout(req))
 	if err != nil {
-		return out, execute.Usage{}, fmt.Errorf("command execution failed: %w", err)
+		return execute.Result{Result: out}, fmt.Errorf("command execution failed: %w", err)
 	}
 
 	log.Info().Msg("command executed successfully")
 
-	return out, usage, nil
+	return e.withArtifacts(ctx, requestID, paths, execute.Result{Result: out, Usage: usage})
+}
+
+// withArtifacts uploads the output files of the execution and adds them to the result.
+func (e *Executor) withArtifacts(ctx context.Context, requestID string, paths requestPaths, res execute.Result) (execute.Result, error) {
+
+	if e.cfg.Artifacts == nil {
+		return res, nil
+	}
+
+	artifacts, err := e.uploadArtifacts(ctx, requestID, paths.artifacts)
+	if err != nil {
+		return res, fmt.Errorf("could not upload artifacts: %w", err)
+	}
+
+	res.Artifacts = artifacts
+
+	return res, nil
 }
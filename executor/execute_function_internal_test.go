@@ -0,0 +1,51 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestExecutor_ExecuteInteractive(t *testing.T) {
+
+	const (
+		runtimeName = "runtime.sh"
+		// Fake runtime echoing its standard input.
+		runtimeScript = "#!/bin/sh\ncat\necho done >&2\n"
+	)
+
+	runtimeDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(runtimeDir, runtimeName), []byte(runtimeScript), 0755)
+	require.NoError(t, err)
+
+	executor, err := New(mocks.NoopLogger,
+		WithRuntimeDir(runtimeDir),
+		WithExecutableName(runtimeName),
+		WithWorkDir(t.TempDir()),
+	)
+	require.NoError(t, err)
+
+	req := execute.Request{
+		FunctionID: "dummy-function-id",
+		Method:     "dummy-method",
+	}
+
+	var stdout bytes.Buffer
+	res, err := executor.ExecuteInteractive(context.Background(), mocks.GenericUUID.String(), req, strings.NewReader("dummy-input"), &stdout)
+	require.NoError(t, err)
+
+	require.Equal(t, codes.OK, res.Code)
+	require.Equal(t, "dummy-input", stdout.String())
+	// Standard output was streamed, so it is not repeated in the result.
+	require.Empty(t, res.Result.Stdout)
+	require.Equal(t, "done\n", res.Result.Stderr)
+}
@@ -5,6 +5,7 @@ package executor
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os/exec"
 	"time"
@@ -14,15 +15,21 @@ import (
 )
 
 // executeCommand on non-windows systems is pretty straightforward and equivalent to the ordinary `cmd.Run()` or `cmd.Output`.
-func (e *Executor) executeCommand(cmd *exec.Cmd) (execute.RuntimeOutput, execute.Usage, error) {
+func (e *Executor) executeCommand(ctx context.Context, requestID string, cmd *exec.Cmd, limits *execute.ResourceLimits, timeout time.Duration) (execute.RuntimeOutput, execute.Usage, error) {
 
 	var (
 		stdout bytes.Buffer
 		stderr bytes.Buffer
 	)
-	cmd.Stdout = &stdout
+	// Standard output of interactive executions is already sent elsewhere.
+	if cmd.Stdout == nil {
+		cmd.Stdout = &stdout
+	}
 	cmd.Stderr = &stderr
 
+	// Processes started by the runtime may outlive it and keep the output pipes open - don't wait on them once it is killed.
+	cmd.WaitDelay = killWaitDelay
+
 	// Execute the command and collect output.
 	start := time.Now()
 	err := cmd.Start()
@@ -33,14 +40,24 @@ func (e *Executor) executeCommand(cmd *exec.Cmd) (execute.RuntimeOutput, execute
 	proc := execute.ProcessID{
 		PID: cmd.Process.Pid,
 	}
-	err = e.cfg.Limiter.LimitProcess(proc)
+	err = e.limitProcess(requestID, proc, limits)
+	defer e.removeLimits(requestID, limits)
 	if err != nil {
+		// Do not leave the process running without limits.
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
 		return execute.RuntimeOutput{}, execute.Usage{}, fmt.Errorf("could not set resource limits: %w", err)
 	}
 
+	// Kill the process if it runs past the execution timeout. Output written until then is still returned.
+	expired := killAfter(timeout, func() {
+		_ = cmd.Process.Kill()
+	})
+
 	// Return execution error with as much info below.
 	cmdErr := cmd.Wait()
 	end := time.Now()
+	timedOut := expired()
 
 	out := execute.RuntimeOutput{
 		Stdout:   stdout.String(),
@@ -57,6 +74,15 @@ func (e *Executor) executeCommand(cmd *exec.Cmd) (execute.RuntimeOutput, execute
 
 	usage.WallClockTime = duration
 
+	if timedOut {
+		return out, usage, fmt.Errorf("process killed after %v: %w", timeout, errExecutionTimeout)
+	}
+
+	// Process is killed when the execution is aborted.
+	if ctx.Err() != nil {
+		return out, usage, fmt.Errorf("process killed: %w", ctx.Err())
+	}
+
 	if cmdErr != nil {
 		return out, usage, fmt.Errorf("process execution failed: %w", cmdErr)
 	}
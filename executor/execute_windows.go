@@ -5,6 +5,7 @@ package executor
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os/exec"
 	"time"
@@ -24,15 +25,21 @@ import (
 // `DuplicateHandle“ syscall. With this duplicated handle, we'll be able to access all the info we need.
 // Additionally, the `DuplicateHandle` syscall will fail if we do anything wrong, so it will also act as a
 // validation layer.
-func (e *Executor) executeCommand(cmd *exec.Cmd) (execute.RuntimeOutput, execute.Usage, error) {
+func (e *Executor) executeCommand(ctx context.Context, requestID string, cmd *exec.Cmd, limits *execute.ResourceLimits, timeout time.Duration) (execute.RuntimeOutput, execute.Usage, error) {
 
 	var (
 		stdout bytes.Buffer
 		stderr bytes.Buffer
 	)
-	cmd.Stdout = &stdout
+	// Standard output of interactive executions is already sent elsewhere.
+	if cmd.Stdout == nil {
+		cmd.Stdout = &stdout
+	}
 	cmd.Stderr = &stderr
 
+	// Processes started by the runtime may outlive it and keep the output pipes open - don't wait on them once it is killed.
+	cmd.WaitDelay = killWaitDelay
+
 	// Execute the command and collect output.
 	start := time.Now()
 	err := cmd.Start()
@@ -70,14 +77,24 @@ func (e *Executor) executeCommand(cmd *exec.Cmd) (execute.RuntimeOutput, execute
 		PID:    cmd.Process.Pid,
 		Handle: uintptr(handle),
 	}
-	err = e.cfg.Limiter.LimitProcess(proc)
+	err = e.limitProcess(requestID, proc, limits)
+	defer e.removeLimits(requestID, limits)
 	if err != nil {
+		// Do not leave the process running without limits.
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
 		return execute.RuntimeOutput{}, execute.Usage{}, fmt.Errorf("could not set resource limits: %w", err)
 	}
 
+	// Kill the process if it runs past the execution timeout. Output written until then is still returned.
+	expired := killAfter(timeout, func() {
+		_ = cmd.Process.Kill()
+	})
+
 	// Now we can safely wait for the child process to complete.
 	cmdErr := cmd.Wait()
 	end := time.Now()
+	timedOut := expired()
 
 	out := execute.RuntimeOutput{
 		Stdout:   stdout.String(),
@@ -101,6 +118,15 @@ func (e *Executor) executeCommand(cmd *exec.Cmd) (execute.RuntimeOutput, execute
 	usage.MemoryMaxKB = int64(mem) / 1000
 	usage.WallClockTime = duration
 
+	if timedOut {
+		return out, usage, fmt.Errorf("process killed after %v: %w", timeout, errExecutionTimeout)
+	}
+
+	// Process is killed when the execution is aborted.
+	if ctx.Err() != nil {
+		return out, usage, fmt.Errorf("process killed: %w", ctx.Err())
+	}
+
 	if cmdErr != nil {
 		return out, usage, fmt.Errorf("process execution failed: %w", cmdErr)
 	}
@@ -4,11 +4,15 @@ import (
 	"cmp"
 	"errors"
 	"fmt"
+	"net/http"
 	"path/filepath"
+	goruntime "runtime"
 
 	"github.com/armon/go-metrics"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 
+	"github.com/blocklessnetwork/b7s/safehttp"
 	"github.com/blocklessnetwork/b7s/telemetry/tracing"
 )
 
@@ -18,6 +22,12 @@ type Executor struct {
 	cfg     Config
 	tracer  *tracing.Tracer
 	metrics *metrics.Metrics
+
+	http    *http.Client
+	remote  *http.Client // client for input URLs supplied by clients, which refuses to connect to non-public addresses
+	modules *moduleCache
+	inputs  *inputCache
+	pool    *processPool
 }
 
 // New creates a new Executor with the specified working directory.
@@ -62,6 +72,54 @@ func New(log zerolog.Logger, options ...Option) (*Executor, error) {
 		cfg:     cfg,
 		tracer:  tracing.NewTracer(tracerName),
 		metrics: cmp.Or(cfg.Metrics, metrics.Default()),
+		http: &http.Client{
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+		},
+		remote: safehttp.NewClient(inputFetchTimeout),
+	}
+
+	// Optional runtime features are used only if the runtime supports them.
+	pooling := cfg.Reuse.enabled() || cfg.Warm.enabled()
+	if pooling && goruntime.GOOS == "windows" {
+		return nil, errors.New("runtime process reuse is not supported on windows")
+	}
+
+	var features runtimeFeatures
+	if cfg.ModuleCacheDir != "" || pooling {
+		features, err = probeRuntime(cliPath)
+		if err != nil {
+			log.Warn().Err(err).Str("path", cliPath).Msg("could not check runtime features, optional runtime features disabled")
+		}
+	}
+
+	if cfg.ModuleCacheDir != "" && !features.moduleCache {
+		log.Warn().Str("path", cliPath).Msg("runtime does not support a module cache, compiled modules will not be cached")
+	}
+
+	if cfg.ModuleCacheDir != "" && features.moduleCache {
+		modules, err := newModuleCache(log, cfg.FS, e.metrics, cfg.ModuleCacheDir, cfg.ModuleCacheSize)
+		if err != nil {
+			return nil, fmt.Errorf("could not create module cache: %w", err)
+		}
+
+		e.modules = modules
+	}
+
+	if cfg.InputCacheDir != "" {
+		inputs, err := newInputCache(log, cfg.FS, e.metrics, cfg.InputCacheDir, cfg.InputCacheSize, cfg.InputCacheTTL)
+		if err != nil {
+			return nil, fmt.Errorf("could not create input cache: %w", err)
+		}
+
+		e.inputs = inputs
+	}
+
+	if pooling && !features.serve {
+		log.Warn().Str("path", cliPath).Msg("runtime does not support serve mode, runtime processes will not be reused")
+	}
+
+	if pooling && features.serve {
+		e.pool = newProcessPool(log, cfg.Reuse, cfg.Warm, e.startPooledProcess)
 	}
 
 	return &e, nil
@@ -0,0 +1,36 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Environment variables selecting the GPU devices visible to the runtime and the drivers it uses.
+const (
+	cudaVisibleDevicesEnvName   = "CUDA_VISIBLE_DEVICES"
+	nvidiaVisibleDevicesEnvName = "NVIDIA_VISIBLE_DEVICES"
+)
+
+var errGPUNotAvailable = errors.New("execution requires GPUs but none are available")
+
+// gpuEnv returns the environment variables giving the runtime access to the GPU devices of the worker, if the execution requires them.
+// Other executions see no GPU devices. If the worker has no GPUs configured, the environment is left as it is.
+func (e *Executor) gpuEnv(required bool) []string {
+
+	if len(e.cfg.GPUDevices) == 0 {
+		return nil
+	}
+
+	var devices string
+	if required {
+		devices = strings.Join(e.cfg.GPUDevices, ",")
+	}
+
+	env := []string{
+		fmt.Sprintf("%s=%s", cudaVisibleDevicesEnvName, devices),
+		fmt.Sprintf("%s=%s", nvidiaVisibleDevicesEnvName, devices),
+	}
+
+	return env
+}
@@ -0,0 +1,92 @@
+// Package gpu detects the GPUs available to the worker, so they can be advertised as node attributes and made available
+// to executions that require them.
+package gpu
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// DefaultCLI is the tool used to detect NVIDIA GPUs.
+const DefaultCLI = "nvidia-smi"
+
+// Device describes a GPU.
+type Device struct {
+	Index string // Device index, as used in `CUDA_VISIBLE_DEVICES`.
+	Model string
+	VRAM  uint // Total memory in MiB.
+}
+
+// Detect returns the GPUs reported by the given tool. Empty name means the default tool is used.
+func Detect(ctx context.Context, cli string) ([]Device, error) {
+
+	if cli == "" {
+		cli = DefaultCLI
+	}
+
+	cmd := exec.CommandContext(ctx, cli, "--query-gpu=index,name,memory.total", "--format=csv,noheader,nounits")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not query GPUs (stderr: %s): %w", strings.TrimSpace(stderr.String()), err)
+	}
+
+	devices, err := Parse(out)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse GPU list: %w", err)
+	}
+
+	return devices, nil
+}
+
+// Parse parses the GPU list in the CSV format produced by `nvidia-smi`, with device index, name and total memory on each line.
+func Parse(data []byte) ([]Device, error) {
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.TrimLeadingSpace = true
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("could not read records: %w", err)
+	}
+
+	devices := make([]Device, 0, len(records))
+	for _, record := range records {
+
+		if len(record) != 3 {
+			return nil, fmt.Errorf("unexpected number of fields (have: %v, want: 3)", len(record))
+		}
+
+		vram, err := strconv.ParseUint(strings.TrimSpace(record[2]), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid memory size (%s): %w", record[2], err)
+		}
+
+		device := Device{
+			Index: strings.TrimSpace(record[0]),
+			Model: strings.TrimSpace(record[1]),
+			VRAM:  uint(vram),
+		}
+
+		devices = append(devices, device)
+	}
+
+	return devices, nil
+}
+
+// Indexes returns the indexes of the devices.
+func Indexes(devices []Device) []string {
+	indexes := make([]string, 0, len(devices))
+	for _, device := range devices {
+		indexes = append(indexes, device.Index)
+	}
+	return indexes
+}
@@ -0,0 +1,59 @@
+package gpu_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/executor/gpu"
+)
+
+const testOutput = `0, NVIDIA A100-SXM4-80GB, 81920
+1, NVIDIA A100-SXM4-80GB, 81920
+`
+
+func TestParse(t *testing.T) {
+
+	devices, err := gpu.Parse([]byte(testOutput))
+	require.NoError(t, err)
+
+	expected := []gpu.Device{
+		{Index: "0", Model: "NVIDIA A100-SXM4-80GB", VRAM: 81920},
+		{Index: "1", Model: "NVIDIA A100-SXM4-80GB", VRAM: 81920},
+	}
+	require.Equal(t, expected, devices)
+	require.Equal(t, []string{"0", "1"}, gpu.Indexes(devices))
+
+	devices, err = gpu.Parse(nil)
+	require.NoError(t, err)
+	require.Empty(t, devices)
+
+	_, err = gpu.Parse([]byte("0, NVIDIA A100-SXM4-80GB, [N/A]\n"))
+	require.Error(t, err)
+
+	_, err = gpu.Parse([]byte("0, NVIDIA A100-SXM4-80GB\n"))
+	require.Error(t, err)
+}
+
+func TestDetect(t *testing.T) {
+
+	if runtime.GOOS == "windows" {
+		t.Skip("fake nvidia-smi is a shell script")
+	}
+
+	cli := filepath.Join(t.TempDir(), "nvidia-smi")
+	script := "#!/bin/sh\nprintf '0, Tesla T4, 15360\\n'\n"
+	err := os.WriteFile(cli, []byte(script), 0o755)
+	require.NoError(t, err)
+
+	devices, err := gpu.Detect(context.Background(), cli)
+	require.NoError(t, err)
+	require.Equal(t, []gpu.Device{{Index: "0", Model: "Tesla T4", VRAM: 15360}}, devices)
+
+	_, err = gpu.Detect(context.Background(), filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+}
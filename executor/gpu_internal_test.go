@@ -0,0 +1,70 @@
+package executor
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestExecutor_GPUEnvironment(t *testing.T) {
+
+	gpuRequest := execute.Request{
+		FunctionID: "function-id",
+		Config: execute.Config{
+			Attributes: &execute.Attributes{GPU: &execute.GPURequirements{}},
+			// Execution request cannot override the GPU devices.
+			Environment: []execute.EnvVar{{Name: cudaVisibleDevicesEnvName, Value: "3"}},
+		},
+	}
+
+	t.Run("execution requiring GPUs sees the devices", func(t *testing.T) {
+		executor := Executor{
+			log: mocks.NoopLogger,
+			cfg: Config{
+				ExecutableName: blockless.RuntimeCLI(),
+				GPUDevices:     []string{"0", "1"},
+			},
+		}
+
+		cmd := executor.createCmd(context.Background(), executor.generateRequestPaths(mocks.GenericUUID.String(), "function-id", "", ""), gpuRequest)
+
+		// Last value of a variable is the one the process sees.
+		last := slices.Index(cmd.Env, cudaVisibleDevicesEnvName+"=0,1")
+		require.GreaterOrEqual(t, last, 0)
+		require.Less(t, slices.Index(cmd.Env, cudaVisibleDevicesEnvName+"=3"), last)
+		require.Contains(t, cmd.Env, nvidiaVisibleDevicesEnvName+"=0,1")
+	})
+	t.Run("other executions see no devices", func(t *testing.T) {
+		executor := Executor{
+			log: mocks.NoopLogger,
+			cfg: Config{
+				ExecutableName: blockless.RuntimeCLI(),
+				GPUDevices:     []string{"0", "1"},
+			},
+		}
+
+		cmd := executor.createCmd(context.Background(), executor.generateRequestPaths(mocks.GenericUUID.String(), "function-id", "", ""), mocks.GenericExecutionRequest)
+		require.Contains(t, cmd.Env, cudaVisibleDevicesEnvName+"=")
+		require.Contains(t, cmd.Env, nvidiaVisibleDevicesEnvName+"=")
+	})
+	t.Run("environment unchanged without GPUs", func(t *testing.T) {
+		executor := Executor{}
+		require.Empty(t, executor.gpuEnv(true))
+		require.Empty(t, executor.gpuEnv(false))
+	})
+	t.Run("execution requiring GPUs on worker without GPUs", func(t *testing.T) {
+		executor := Executor{
+			log: mocks.NoopLogger,
+			cfg: defaultConfig,
+		}
+
+		_, err := executor.executeFunction(context.Background(), mocks.GenericUUID.String(), gpuRequest, nil)
+		require.ErrorIs(t, err, errGPUNotAvailable)
+	})
+}
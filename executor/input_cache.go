@@ -0,0 +1,247 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/rs/zerolog"
+	"github.com/spf13/afero"
+)
+
+// Prefix of the files inputs are fetched to, before they are added to the cache.
+const inputFetchPrefix = ".fetch-"
+
+// inputCache keeps remote inputs fetched for executions on disk. Entries are keyed by the hash of their content, so identical
+// inputs are stored once. Executions of a function find the inputs fetched by its earlier executions by their URL, and inputs
+// with a checksum are found by the checksum. Inputs fetched for executions of a tenant are never shared with other tenants.
+// Entries expire after the TTL, and least recently used entries are evicted once the cache is over its size limit.
+type inputCache struct {
+	sync.Mutex
+
+	log     zerolog.Logger
+	fs      afero.Fs
+	metrics *metrics.Metrics
+
+	dir     string
+	maxSize int64
+	ttl     time.Duration
+	size    int64
+	entries map[string]*inputCacheEntry
+	sources map[inputSource]string
+}
+
+type inputCacheEntry struct {
+	size     int64
+	lastUsed time.Time
+	expires  time.Time
+}
+
+// inputSource identifies an input fetched for executions of a function.
+type inputSource struct {
+	function string
+	tenant   string
+	url      string
+}
+
+// newInputCache creates a new input cache in the given directory. Entries already present in the directory, e.g. from previous
+// node runs, are loaded, but can only be found by their checksum.
+func newInputCache(log zerolog.Logger, fs afero.Fs, metrics *metrics.Metrics, dir string, maxSize int64, ttl time.Duration) (*inputCache, error) {
+
+	err := fs.MkdirAll(dir, defaultPermissions)
+	if err != nil {
+		return nil, fmt.Errorf("could not create input cache directory (dir: %s): %w", dir, err)
+	}
+
+	cache := inputCache{
+		log:     log.With().Str("component", "input_cache").Logger(),
+		fs:      fs,
+		metrics: metrics,
+		dir:     dir,
+		maxSize: maxSize,
+		ttl:     ttl,
+		entries: make(map[string]*inputCacheEntry),
+		sources: make(map[inputSource]string),
+	}
+
+	list, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read input cache directory (dir: %s): %w", dir, err)
+	}
+
+	for _, fi := range list {
+
+		// Remove inputs whose fetch did not complete.
+		if strings.HasPrefix(fi.Name(), inputFetchPrefix) {
+			err = fs.RemoveAll(filepath.Join(dir, fi.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("could not remove incomplete input (file: %s): %w", fi.Name(), err)
+			}
+			continue
+		}
+
+		if fi.IsDir() {
+			continue
+		}
+
+		cache.entries[fi.Name()] = &inputCacheEntry{
+			size:     fi.Size(),
+			lastUsed: fi.ModTime(),
+			expires:  fi.ModTime().Add(ttl),
+		}
+		cache.size += fi.Size()
+	}
+
+	cache.evict(time.Now())
+
+	cache.log.Info().Int("entries", len(cache.entries)).Int64("size", cache.size).Int64("max_size", maxSize).Msg("input cache loaded")
+
+	return &cache, nil
+}
+
+// get opens the cached input - the one with the given checksum, or the one fetched from the source if there is no checksum.
+// It reports whether the input was found in the cache.
+func (c *inputCache) get(source inputSource, checksum string) (afero.File, bool) {
+
+	c.Lock()
+	defer c.Unlock()
+
+	key := c.sources[source]
+	if checksum != "" {
+		key = inputKey(strings.ToLower(checksum), source.tenant)
+	}
+
+	entry, ok := c.entries[key]
+	if !ok || !time.Now().Before(entry.expires) {
+		c.metrics.IncrCounter(inputCacheMissesMetric, 1)
+		return nil, false
+	}
+
+	f, err := c.fs.Open(filepath.Join(c.dir, key))
+	if err != nil {
+		c.log.Warn().Err(err).Str("key", key).Msg("could not open cached input")
+		c.metrics.IncrCounter(inputCacheMissesMetric, 1)
+		return nil, false
+	}
+
+	entry.lastUsed = time.Now()
+	c.sources[source] = key
+	c.metrics.IncrCounter(inputCacheHitsMetric, 1)
+
+	return f, true
+}
+
+// create creates the file the input should be fetched to, before it is added to the cache.
+func (c *inputCache) create() (afero.File, error) {
+	return afero.TempFile(c.fs, c.dir, inputFetchPrefix+"*")
+}
+
+// add moves the fetched input into the cache and opens it. Entries are evicted if the cache is over its size limit.
+func (c *inputCache) add(source inputSource, fetched string, checksum string, size int64) (afero.File, error) {
+
+	key := inputKey(checksum, source.tenant)
+	path := filepath.Join(c.dir, key)
+
+	c.Lock()
+	defer c.Unlock()
+
+	err := c.fs.Rename(fetched, path)
+	if err != nil {
+		return nil, fmt.Errorf("could not add input to cache (key: %s): %w", key, err)
+	}
+
+	f, err := c.fs.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open cached input (key: %s): %w", key, err)
+	}
+
+	// Same content might have been fetched in the meantime, from this or another source.
+	entry, ok := c.entries[key]
+	if ok {
+		c.size -= entry.size
+	}
+
+	now := time.Now()
+	c.entries[key] = &inputCacheEntry{
+		size:     size,
+		lastUsed: now,
+		expires:  now.Add(c.ttl),
+	}
+	c.size += size
+	c.sources[source] = key
+
+	c.evict(now)
+
+	return f, nil
+}
+
+// evict removes expired entries, and least recently used entries until the cache fits within its size limit.
+// NOTE: Caller should hold the lock.
+func (c *inputCache) evict(now time.Time) {
+
+	keys := make([]string, 0, len(c.entries))
+	for key, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			c.remove(key)
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	if c.maxSize > 0 && c.size > c.maxSize {
+
+		sort.Slice(keys, func(i, j int) bool {
+			return c.entries[keys[i]].lastUsed.Before(c.entries[keys[j]].lastUsed)
+		})
+
+		for _, key := range keys {
+			if c.size <= c.maxSize {
+				break
+			}
+
+			c.remove(key)
+		}
+	}
+
+	// Forget sources whose inputs are no longer cached.
+	for source, key := range c.sources {
+		_, ok := c.entries[key]
+		if !ok {
+			delete(c.sources, source)
+		}
+	}
+}
+
+// remove removes the entry from the cache.
+// NOTE: Caller should hold the lock.
+func (c *inputCache) remove(key string) {
+
+	// Files still in use are removed once closed - unless the OS does not allow removing them, in which case we try again later.
+	err := c.fs.Remove(filepath.Join(c.dir, key))
+	if err != nil && !os.IsNotExist(err) {
+		c.log.Error().Err(err).Str("key", key).Msg("could not remove input cache entry")
+		return
+	}
+
+	c.size -= c.entries[key].size
+	delete(c.entries, key)
+
+	c.metrics.IncrCounter(inputCacheEvictionsMetric, 1)
+
+	c.log.Debug().Str("key", key).Msg("evicted input cache entry")
+}
+
+// inputKey returns the cache key for the input with the given checksum, fetched for executions of the tenant.
+func inputKey(checksum string, tenant string) string {
+
+	if tenant == "" {
+		return checksum
+	}
+
+	return checksum + "." + tenant
+}
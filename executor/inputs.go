@@ -0,0 +1,190 @@
+package executor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+// How long can fetching a single input take.
+const inputFetchTimeout = 10 * time.Minute
+
+var (
+	errInputTooLarge    = errors.New("input exceeds the maximum size")
+	errChecksumMismatch = errors.New("input does not match the checksum")
+)
+
+// fetchInputs fetches the inputs of the execution and places them in the function filesystem.
+func (e *Executor) fetchInputs(ctx context.Context, log zerolog.Logger, paths requestPaths, req execute.Request) error {
+
+	for _, input := range req.Config.Inputs {
+
+		// Input path is relative to the function filesystem, make sure it stays within it.
+		err := input.Valid()
+		if err != nil {
+			return fmt.Errorf("invalid input (url: %s): %w", input.URL, err)
+		}
+
+		target := filepath.Join(paths.fsRoot, filepath.FromSlash(input.Path))
+		source := inputSource{
+			function: req.FunctionID,
+			tenant:   req.Config.Tenant,
+			url:      input.URL,
+		}
+
+		cached, err := e.fetchInput(ctx, source, input.Checksum, target)
+		if err != nil {
+			return fmt.Errorf("could not fetch input (url: %s): %w", input.URL, err)
+		}
+
+		log.Debug().Str("url", input.URL).Str("path", input.Path).Bool("cached", cached).Msg("input ready")
+	}
+
+	return nil
+}
+
+// fetchInput places the input in the target file, fetching it unless it is in the cache. It reports whether the input was cached.
+func (e *Executor) fetchInput(ctx context.Context, source inputSource, checksum string, target string) (bool, error) {
+
+	err := e.cfg.FS.MkdirAll(filepath.Dir(target), defaultPermissions)
+	if err != nil {
+		return false, fmt.Errorf("could not create input directory: %w", err)
+	}
+
+	out, err := e.cfg.FS.Create(target)
+	if err != nil {
+		return false, fmt.Errorf("could not create input file: %w", err)
+	}
+	defer out.Close()
+
+	// Without a cache, fetch the input straight to the function filesystem.
+	if e.inputs == nil {
+		_, _, err = e.download(ctx, source.url, checksum, out)
+		return false, err
+	}
+
+	in, cached := e.inputs.get(source, checksum)
+	if !cached {
+
+		fetched, err := e.inputs.create()
+		if err != nil {
+			return false, fmt.Errorf("could not create input cache file: %w", err)
+		}
+
+		sum, size, err := e.download(ctx, source.url, checksum, fetched)
+		fetched.Close()
+		if err != nil {
+			_ = e.cfg.FS.Remove(fetched.Name())
+			return false, err
+		}
+
+		in, err = e.inputs.add(source, fetched.Name(), sum, size)
+		if err != nil {
+			_ = e.cfg.FS.Remove(fetched.Name())
+			return false, err
+		}
+	}
+	defer in.Close()
+
+	_, err = io.Copy(out, in)
+	if err != nil {
+		return cached, fmt.Errorf("could not copy cached input: %w", err)
+	}
+
+	return cached, nil
+}
+
+// download fetches the input from the URL and writes it out, verifying it against the checksum, if there is one.
+// It returns the hex-encoded SHA-256 hash of the input and its size.
+func (e *Executor) download(ctx context.Context, address string, checksum string, w io.Writer) (string, int64, error) {
+
+	address, gateway, err := e.inputURL(address)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid input URL: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, inputFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return "", 0, fmt.Errorf("could not create request: %w", err)
+	}
+
+	// The IPFS gateway is set by the node operator and may be on a private network. Other URLs are supplied by clients,
+	// so they must resolve to public addresses.
+	cli := e.remote
+	if gateway {
+		cli = e.http
+	}
+
+	res, err := cli.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("could not fetch input: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return "", 0, fmt.Errorf("unexpected response status: %s", res.Status)
+	}
+
+	maxSize := e.cfg.InputMaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultInputMaxSize
+	}
+
+	if res.ContentLength > maxSize {
+		return "", 0, errInputTooLarge
+	}
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(w, h), io.LimitReader(res.Body, maxSize+1))
+	if err != nil {
+		return "", size, fmt.Errorf("could not read input: %w", err)
+	}
+
+	e.metrics.IncrCounter(inputBytesFetchedMetric, float32(size))
+
+	if size > maxSize {
+		return "", size, errInputTooLarge
+	}
+
+	sum := hex.EncodeToString(h.Sum(nil))
+	if checksum != "" && !strings.EqualFold(sum, checksum) {
+		return "", size, errChecksumMismatch
+	}
+
+	return sum, size, nil
+}
+
+// inputURL returns the URL the input is fetched from, and whether it is fetched through the IPFS gateway. IPFS inputs are fetched
+// through the IPFS gateway.
+func (e *Executor) inputURL(address string) (string, bool, error) {
+
+	u, err := url.Parse(address)
+	if err != nil {
+		return "", false, err
+	}
+
+	if u.Scheme != execute.InputSchemeIPFS {
+		return address, false, nil
+	}
+
+	if e.cfg.IPFSGateway == "" {
+		return "", false, errors.New("no IPFS gateway configured")
+	}
+
+	return fmt.Sprintf("%s/ipfs/%s%s", strings.TrimSuffix(e.cfg.IPFSGateway, "/"), u.Host, u.EscapedPath()), true, nil
+}
@@ -0,0 +1,241 @@
+package executor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/safehttp"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestExecutor_Inputs(t *testing.T) {
+
+	const (
+		workdir  = "/var/tmp/b7s/workspace"
+		cacheDir = "/var/tmp/b7s/inputs"
+		content  = "dummy-dataset"
+	)
+
+	sum := sha256.Sum256([]byte(content))
+	checksum := hex.EncodeToString(sum[:])
+
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+
+		switch r.URL.Path {
+		case "/dataset.csv", "/ipfs/dummy-cid/dataset.csv":
+			w.Write([]byte(content))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	newExecutor := func(t *testing.T, cache bool, options ...Option) *Executor {
+		t.Helper()
+
+		cfg := Config{
+			WorkDir:     workdir,
+			FS:          afero.NewMemMapFs(),
+			IPFSGateway: srv.URL,
+		}
+		for _, option := range options {
+			option(&cfg)
+		}
+
+		executor := Executor{
+			log:     mocks.NoopLogger,
+			cfg:     cfg,
+			metrics: metrics.Default(),
+			http:    srv.Client(),
+			remote:  srv.Client(),
+		}
+
+		if cache {
+			inputs, err := newInputCache(mocks.NoopLogger, cfg.FS, executor.metrics, cacheDir, 0, time.Minute)
+			require.NoError(t, err)
+			executor.inputs = inputs
+		}
+
+		return &executor
+	}
+
+	request := func(inputs ...execute.Input) execute.Request {
+		req := mocks.GenericExecutionRequest
+		req.Config.Inputs = inputs
+		return req
+	}
+
+	fetch := func(t *testing.T, executor *Executor, req execute.Request) requestPaths {
+		t.Helper()
+
+		paths := executor.generateRequestPaths(mocks.GenericUUID.String(), req.FunctionID, req.Method, req.Config.Tenant)
+		err := executor.fetchInputs(context.Background(), mocks.NoopLogger, paths, req)
+		require.NoError(t, err)
+
+		return paths
+	}
+
+	requireInput := func(t *testing.T, executor *Executor, path string) {
+		t.Helper()
+
+		data, err := afero.ReadFile(executor.cfg.FS, path)
+		require.NoError(t, err)
+		require.Equal(t, content, string(data))
+	}
+
+	t.Run("inputs are placed in the function filesystem", func(t *testing.T) {
+		executor := newExecutor(t, false)
+
+		req := request(
+			execute.Input{URL: srv.URL + "/dataset.csv", Path: "data/dataset.csv", Checksum: checksum},
+			execute.Input{URL: "ipfs://dummy-cid/dataset.csv", Path: "ipfs.csv"},
+		)
+
+		paths := fetch(t, executor, req)
+		requireInput(t, executor, filepath.Join(paths.fsRoot, "data", "dataset.csv"))
+		requireInput(t, executor, filepath.Join(paths.fsRoot, "ipfs.csv"))
+	})
+	t.Run("repeated executions use cached inputs", func(t *testing.T) {
+		executor := newExecutor(t, true)
+
+		req := request(execute.Input{URL: srv.URL + "/dataset.csv", Path: "dataset.csv"})
+
+		before := fetches.Load()
+		fetch(t, executor, req)
+		paths := fetch(t, executor, req)
+		require.Equal(t, before+1, fetches.Load())
+		requireInput(t, executor, filepath.Join(paths.fsRoot, "dataset.csv"))
+
+		// Other functions fetch inputs without a checksum again.
+		other := request(execute.Input{URL: srv.URL + "/dataset.csv", Path: "dataset.csv"})
+		other.FunctionID = "other-function"
+		fetch(t, executor, other)
+		require.Equal(t, before+2, fetches.Load())
+
+		// Inputs with a checksum are found by it, whatever the function fetching them.
+		other.FunctionID = "third-function"
+		other.Config.Inputs[0].Checksum = checksum
+		fetch(t, executor, other)
+		require.Equal(t, before+2, fetches.Load())
+
+		// Other tenants fetch inputs again.
+		tenant := req
+		tenant.Config.Tenant = "acme"
+		fetch(t, executor, tenant)
+		require.Equal(t, before+3, fetches.Load())
+	})
+	t.Run("expired inputs are fetched again", func(t *testing.T) {
+		executor := newExecutor(t, true)
+		executor.inputs.ttl = 0
+
+		req := request(execute.Input{URL: srv.URL + "/dataset.csv", Path: "dataset.csv"})
+
+		before := fetches.Load()
+		fetch(t, executor, req)
+		fetch(t, executor, req)
+		require.Equal(t, before+2, fetches.Load())
+		require.Empty(t, executor.inputs.entries)
+	})
+	t.Run("least recently used inputs are evicted", func(t *testing.T) {
+		executor := newExecutor(t, true)
+		executor.inputs.maxSize = int64(len(content))
+
+		fetch(t, executor, request(execute.Input{URL: srv.URL + "/dataset.csv", Path: "dataset.csv"}))
+
+		tenant := request(execute.Input{URL: srv.URL + "/dataset.csv", Path: "dataset.csv"})
+		tenant.Config.Tenant = "acme"
+		fetch(t, executor, tenant)
+
+		require.Len(t, executor.inputs.entries, 1)
+		require.Contains(t, executor.inputs.entries, inputKey(checksum, "acme"))
+		require.Equal(t, int64(len(content)), executor.inputs.size)
+	})
+	t.Run("inputs on non-public addresses are refused", func(t *testing.T) {
+		executor := newExecutor(t, false)
+		executor.remote = safehttp.NewClient(time.Second)
+
+		before := fetches.Load()
+
+		req := request(execute.Input{URL: srv.URL + "/dataset.csv", Path: "dataset.csv"})
+		paths := executor.generateRequestPaths(mocks.GenericUUID.String(), req.FunctionID, req.Method, "")
+
+		err := executor.fetchInputs(context.Background(), mocks.NoopLogger, paths, req)
+		require.ErrorIs(t, err, safehttp.ErrNonPublicAddress)
+		require.Equal(t, before, fetches.Load())
+
+		// IPFS gateway is set by the node operator, and is reached with the regular client.
+		fetch(t, executor, request(execute.Input{URL: "ipfs://dummy-cid/dataset.csv", Path: "ipfs.csv"}))
+		require.Equal(t, before+1, fetches.Load())
+	})
+	t.Run("inputs are limited in size by default", func(t *testing.T) {
+
+		large := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Length", strconv.Itoa(DefaultInputMaxSize+1))
+		}))
+		defer large.Close()
+
+		executor := newExecutor(t, false)
+
+		req := request(execute.Input{URL: large.URL + "/dataset.csv", Path: "dataset.csv"})
+		paths := executor.generateRequestPaths(mocks.GenericUUID.String(), req.FunctionID, req.Method, "")
+
+		err := executor.fetchInputs(context.Background(), mocks.NoopLogger, paths, req)
+		require.ErrorIs(t, err, errInputTooLarge)
+	})
+	t.Run("invalid inputs fail the execution", func(t *testing.T) {
+		executor := newExecutor(t, true, WithInputMaxSize(4))
+
+		tests := map[string]struct {
+			input execute.Input
+			err   error
+		}{
+			"checksum mismatch": {
+				input: execute.Input{URL: srv.URL + "/dataset.csv", Path: "dataset.csv", Checksum: hex.EncodeToString(make([]byte, 32))},
+			},
+			"input too large": {
+				input: execute.Input{URL: srv.URL + "/dataset.csv", Path: "dataset.csv"},
+				err:   errInputTooLarge,
+			},
+			"input not found": {
+				input: execute.Input{URL: srv.URL + "/missing.csv", Path: "dataset.csv"},
+			},
+			"path outside filesystem": {
+				input: execute.Input{URL: srv.URL + "/dataset.csv", Path: "../dataset.csv"},
+			},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				req := request(test.input)
+				paths := executor.generateRequestPaths(mocks.GenericUUID.String(), req.FunctionID, req.Method, "")
+
+				err := executor.fetchInputs(context.Background(), mocks.NoopLogger, paths, req)
+				require.Error(t, err)
+				if test.err != nil {
+					require.ErrorIs(t, err, test.err)
+				}
+			})
+		}
+
+		// Nothing is cached, and no fetches are left behind.
+		require.Empty(t, executor.inputs.entries)
+		list, err := afero.ReadDir(executor.cfg.FS, cacheDir)
+		require.NoError(t, err)
+		require.Empty(t, list)
+	})
+}
@@ -1,9 +1,13 @@
 package executor
 
 import (
+	"errors"
+
 	"github.com/blocklessnetwork/b7s/models/execute"
 )
 
+var errExecutionLimitsNotSupported = errors.New("resource limits for individual executions are not supported")
+
 // noopLimiter is a dummy limiter used when processes run without any resource limitations.
 type noopLimiter struct{}
 
@@ -14,3 +18,37 @@ func (n *noopLimiter) LimitProcess(proc execute.ProcessID) error {
 func (n *noopLimiter) ListProcesses() ([]int, error) {
 	return []int{}, nil
 }
+
+// limitProcess sets the resource limits for the process running the execution. Executions that requested
+// their own resource limits get them in addition to the limits set for all processes.
+func (e *Executor) limitProcess(requestID string, proc execute.ProcessID, limits *execute.ResourceLimits) error {
+
+	if limits == nil {
+		return e.cfg.Limiter.LimitProcess(proc)
+	}
+
+	limiter, ok := e.cfg.Limiter.(ExecutionLimiter)
+	if !ok {
+		return errExecutionLimitsNotSupported
+	}
+
+	return limiter.LimitExecution(requestID, proc, *limits)
+}
+
+// removeLimits removes the resource limits set for the execution, if it requested its own.
+func (e *Executor) removeLimits(requestID string, limits *execute.ResourceLimits) {
+
+	if limits == nil {
+		return
+	}
+
+	limiter, ok := e.cfg.Limiter.(ExecutionLimiter)
+	if !ok {
+		return
+	}
+
+	err := limiter.RemoveExecutionLimits(requestID)
+	if err != nil {
+		e.log.Error().Err(err).Str("request", requestID).Msg("could not remove execution resource limits")
+	}
+}
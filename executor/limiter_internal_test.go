@@ -0,0 +1,63 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+type testExecutionLimiter struct {
+	noopLimiter
+	limited map[string]execute.ResourceLimits
+}
+
+func (l *testExecutionLimiter) LimitExecution(id string, proc execute.ProcessID, limits execute.ResourceLimits) error {
+	l.limited[id] = limits
+	return nil
+}
+
+func (l *testExecutionLimiter) RemoveExecutionLimits(id string) error {
+	delete(l.limited, id)
+	return nil
+}
+
+func TestExecutor_LimitProcess(t *testing.T) {
+
+	var (
+		requestID = mocks.GenericUUID.String()
+		proc      = execute.ProcessID{PID: 1}
+		limits    = execute.ResourceLimits{CPUPercentage: 0.5, MemoryKB: 128_000, FileDescriptors: 64}
+	)
+
+	t.Run("execution limits are set and removed", func(t *testing.T) {
+
+		limiter := &testExecutionLimiter{limited: make(map[string]execute.ResourceLimits)}
+		executor := Executor{
+			log: mocks.NoopLogger,
+			cfg: Config{Limiter: limiter},
+		}
+
+		err := executor.limitProcess(requestID, proc, &limits)
+		require.NoError(t, err)
+		require.Equal(t, limits, limiter.limited[requestID])
+
+		executor.removeLimits(requestID, &limits)
+		require.Empty(t, limiter.limited)
+	})
+	t.Run("limiter does not support execution limits", func(t *testing.T) {
+
+		executor := Executor{
+			log: mocks.NoopLogger,
+			cfg: Config{Limiter: &noopLimiter{}},
+		}
+
+		err := executor.limitProcess(requestID, proc, nil)
+		require.NoError(t, err)
+
+		err = executor.limitProcess(requestID, proc, &limits)
+		require.ErrorIs(t, err, errExecutionLimitsNotSupported)
+	})
+}
@@ -8,3 +8,10 @@ type Limiter interface {
 	LimitProcess(proc execute.ProcessID) error
 	ListProcesses() ([]int, error)
 }
+
+// ExecutionLimiter is a limiter that can also enforce resource limits requested for individual executions.
+type ExecutionLimiter interface {
+	Limiter
+	LimitExecution(id string, proc execute.ProcessID, limits execute.ResourceLimits) error
+	RemoveExecutionLimits(id string) error
+}
@@ -7,10 +7,13 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/containerd/cgroups/v3"
 	"github.com/containerd/cgroups/v3/cgroup2"
+	"golang.org/x/sys/unix"
 
 	"github.com/blocklessnetwork/b7s/models/execute"
 )
@@ -21,6 +24,10 @@ type Limits struct {
 	cfg Config
 
 	cgroup *cgroup2.Manager
+	shared *cgroup2.Manager
+
+	lock       sync.Mutex
+	executions map[string]*cgroup2.Manager
 }
 
 // New creates a new process resource limit with the given configuration.
@@ -49,9 +56,18 @@ func New(opts ...Option) (*Limits, error) {
 		return nil, fmt.Errorf("could not create cgroup: %w", err)
 	}
 
+	// Processes are not added to the limiter cgroup directly, but to a nested one, as cgroups v2 does not allow
+	// processes in cgroups whose children have resource controllers enabled - such as the ones for individual executions.
+	shared, err := cg.NewChild(SharedCgroup, &cgroup2.Resources{})
+	if err != nil {
+		return nil, fmt.Errorf("could not create shared cgroup: %w", err)
+	}
+
 	l := Limits{
-		cfg:    cfg,
-		cgroup: cg,
+		cfg:        cfg,
+		cgroup:     cg,
+		shared:     shared,
+		executions: make(map[string]*cgroup2.Manager),
 	}
 
 	return &l, nil
@@ -61,7 +77,7 @@ func New(opts ...Option) (*Limits, error) {
 func (l *Limits) LimitProcess(proc execute.ProcessID) error {
 
 	pid := proc.PID
-	err := l.cgroup.AddProc(uint64(pid))
+	err := l.shared.AddProc(uint64(pid))
 	if err != nil {
 		return fmt.Errorf("could not set resouce limit for process (pid: %v): %w", pid, err)
 	}
@@ -69,11 +85,77 @@ func (l *Limits) LimitProcess(proc execute.ProcessID) error {
 	return nil
 }
 
+// LimitExecution will set the resource limits for the process running the execution with the given ID.
+// Limits are set on top of the limits for all processes, so executions cannot use more resources than the limiter allows.
+func (l *Limits) LimitExecution(id string, proc execute.ProcessID, limits execute.ResourceLimits) error {
+
+	// The ID names the cgroup, so it must not point anywhere else in the cgroup hierarchy.
+	if id == "" || id == "." || id == ".." || strings.ContainsAny(id, "/\\") {
+		return fmt.Errorf("invalid execution ID (id: %q)", id)
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	_, ok := l.executions[id]
+	if ok {
+		return fmt.Errorf("execution already has resource limits (id: %v)", id)
+	}
+
+	cg, err := l.cgroup.NewChild(id, executionResources(limits))
+	if err != nil {
+		return fmt.Errorf("could not create cgroup for execution (id: %v): %w", id, err)
+	}
+
+	l.executions[id] = cg
+
+	pid := proc.PID
+	err = cg.AddProc(uint64(pid))
+	if err != nil {
+		return fmt.Errorf("could not set resource limit for process (pid: %v): %w", pid, err)
+	}
+
+	// There is no cgroup controller for file descriptors, so that one is a limit for the process itself.
+	if limits.FileDescriptors > 0 {
+		rlimit := unix.Rlimit{
+			Cur: limits.FileDescriptors,
+			Max: limits.FileDescriptors,
+		}
+		err = unix.Prlimit(pid, unix.RLIMIT_NOFILE, &rlimit, nil)
+		if err != nil {
+			return fmt.Errorf("could not set file descriptor limit for process (pid: %v): %w", pid, err)
+		}
+	}
+
+	return nil
+}
+
+// RemoveExecutionLimits will remove the resource limits set for the execution with the given ID, once its process is done.
+func (l *Limits) RemoveExecutionLimits(id string) error {
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	cg, ok := l.executions[id]
+	if !ok {
+		return nil
+	}
+
+	err := cg.Delete()
+	if err != nil {
+		return fmt.Errorf("could not remove cgroup for execution (id: %v): %w", id, err)
+	}
+
+	delete(l.executions, id)
+
+	return nil
+}
+
 // ListProcesses will return the pids of the processes that were added to the resource limit group.
 func (l *Limits) ListProcesses() ([]int, error) {
 
 	var list []int
-	pids, err := l.cgroup.Procs(false)
+	pids, err := l.cgroup.Procs(true)
 	if err != nil {
 		return nil, fmt.Errorf("could not get list of limited processes: %w", err)
 	}
@@ -67,7 +67,46 @@ func TestLimits(t *testing.T) {
 	require.Equal(t, pids[0], proc.PID)
 
 	// Manually verify the PID limit.
-	verifyPids(t, cgroup, []int{proc.PID})
+	verifyPids(t, filepath.Join(cgroup, limits.SharedCgroup), []int{proc.PID})
+}
+
+func TestLimits_Execution(t *testing.T) {
+
+	const (
+		cgroup      = limits.DefaultCgroup
+		executionID = "execution-limits-test"
+		cpuLimit    = 0.9
+		memLimit    = 999_424
+	)
+
+	limiter, err := limits.New(limits.WithCgroup(cgroup))
+	require.NoError(t, err)
+
+	defer func() {
+		err = limiter.Shutdown()
+		require.NoError(t, err)
+	}()
+
+	proc := execute.ProcessID{
+		PID: os.Getpid(),
+	}
+
+	// NOTE: No file descriptor limit, as it would also apply to go test after the test is done.
+	err = limiter.LimitExecution(executionID, proc, execute.ResourceLimits{CPUPercentage: cpuLimit, MemoryKB: memLimit})
+	require.NoError(t, err)
+
+	execution := filepath.Join(cgroup, executionID)
+	verifyCPULImit(t, execution, cpuLimit)
+	verifyMemLimit(t, execution, memLimit)
+	verifyPids(t, execution, []int{proc.PID})
+
+	// Move the process out of the execution cgroup so it can be removed.
+	err = limiter.LimitProcess(proc)
+	require.NoError(t, err)
+
+	err = limiter.RemoveExecutionLimits(executionID)
+	require.NoError(t, err)
+	require.NoDirExists(t, filepath.Join(limits.DefaultMountpoint, execution))
 }
 
 func verifyCPULImit(t *testing.T, cgroup string, limit float64) {
@@ -5,6 +5,9 @@ const (
 	DefaultMountpoint    = "/sys/fs/cgroup"
 	DefaultJobObjectName = "blockless"
 
+	// Name of the cgroup, nested in the limiter cgroup, for processes without their own limits.
+	SharedCgroup = "shared"
+
 	// Default percentage of the CPU allowed. By default we run unlimited.
 	DefaultCPUPercentage = 1.0
 )
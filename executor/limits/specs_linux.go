@@ -8,6 +8,8 @@ import (
 
 	"github.com/containerd/cgroups/v3/cgroup2"
 	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/blocklessnetwork/b7s/models/execute"
 )
 
 func (cfg *Config) linuxResources() *specs.LinuxResources {
@@ -45,3 +47,18 @@ func (cfg *Config) cgroupV2Resources() *cgroup2.Resources {
 	lr := cfg.linuxResources()
 	return cgroup2.ToResources(lr)
 }
+
+// executionResources returns the cgroup resources for an individual execution.
+func executionResources(limits execute.ResourceLimits) *cgroup2.Resources {
+
+	// Unset CPU limit means no limit for executions.
+	cfg := Config{
+		CPUPercentage: DefaultCPUPercentage,
+		MemoryKB:      limits.MemoryKB,
+	}
+	if limits.CPUPercentage > 0 {
+		cfg.CPUPercentage = limits.CPUPercentage
+	}
+
+	return cfg.cgroupV2Resources()
+}
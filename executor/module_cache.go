@@ -0,0 +1,239 @@
+package executor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/rs/zerolog"
+	"github.com/spf13/afero"
+)
+
+// moduleCache keeps track of compiled WASM modules on disk. Entries are keyed by the hash of the module
+// itself, so executions of different functions that use the same module will share the compiled artifact.
+// Modules compiled for executions of a tenant are never shared with other tenants.
+// Compilation is done by the runtime - the cache only provides a directory for it and enforces size limits.
+// Entries used by running executions are never evicted, so the runtime does not lose the directory it works with.
+type moduleCache struct {
+	sync.Mutex
+
+	log     zerolog.Logger
+	fs      afero.Fs
+	metrics *metrics.Metrics
+
+	dir     string
+	maxSize int64
+	size    int64
+	entries map[string]*moduleCacheEntry
+}
+
+type moduleCacheEntry struct {
+	size     int64
+	lastUsed time.Time
+	users    uint // Number of running executions using the entry.
+}
+
+// newModuleCache creates a new module cache in the given directory. Any entries already present
+// in the directory, e.g. from previous node runs, are loaded.
+func newModuleCache(log zerolog.Logger, fs afero.Fs, metrics *metrics.Metrics, dir string, maxSize int64) (*moduleCache, error) {
+
+	err := fs.MkdirAll(dir, defaultPermissions)
+	if err != nil {
+		return nil, fmt.Errorf("could not create module cache directory (dir: %s): %w", dir, err)
+	}
+
+	cache := moduleCache{
+		log:     log.With().Str("component", "module_cache").Logger(),
+		fs:      fs,
+		metrics: metrics,
+		dir:     dir,
+		maxSize: maxSize,
+		entries: make(map[string]*moduleCacheEntry),
+	}
+
+	list, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read module cache directory (dir: %s): %w", dir, err)
+	}
+
+	for _, fi := range list {
+		if !fi.IsDir() {
+			continue
+		}
+
+		size, err := dirSize(fs, filepath.Join(dir, fi.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("could not determine size of module cache entry (entry: %s): %w", fi.Name(), err)
+		}
+
+		cache.entries[fi.Name()] = &moduleCacheEntry{
+			size:     size,
+			lastUsed: fi.ModTime(),
+		}
+		cache.size += size
+	}
+
+	cache.evict()
+
+	cache.log.Info().Int("entries", len(cache.entries)).Int64("size", cache.size).Int64("max_size", maxSize).Msg("module cache loaded")
+
+	return &cache, nil
+}
+
+// entry returns the key and the cache directory for the given module, as used by the tenant. It also reports whether
+// there already is a (potentially) compiled artifact for the module. Entry is not evicted until it is released.
+func (c *moduleCache) entry(module string, tenant string) (string, string, bool, error) {
+
+	key, err := c.moduleHash(module)
+	if err != nil {
+		return "", "", false, fmt.Errorf("could not determine module hash: %w", err)
+	}
+
+	if tenant != "" {
+		key += "." + tenant
+	}
+
+	dir := filepath.Join(c.dir, key)
+
+	c.Lock()
+	defer c.Unlock()
+
+	entry, ok := c.entries[key]
+	if ok {
+		entry.lastUsed = time.Now()
+		entry.users++
+		c.metrics.IncrCounter(moduleCacheHitsMetric, 1)
+		return key, dir, true, nil
+	}
+
+	c.metrics.IncrCounter(moduleCacheMissesMetric, 1)
+
+	err = c.fs.MkdirAll(dir, defaultPermissions)
+	if err != nil {
+		return "", "", false, fmt.Errorf("could not create module cache entry (dir: %s): %w", dir, err)
+	}
+
+	c.entries[key] = &moduleCacheEntry{
+		lastUsed: time.Now(),
+		users:    1,
+	}
+
+	return key, dir, false, nil
+}
+
+// release marks the cache entry as no longer used by the execution. It recalculates the size of the entry after the runtime
+// used it and evicts entries if the cache is over its size limit.
+func (c *moduleCache) release(key string) error {
+
+	// Entry is still in use while its size is determined, so it is not evicted in the meantime.
+	size, sizeErr := dirSize(c.fs, filepath.Join(c.dir, key))
+
+	c.Lock()
+	defer c.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+
+	if entry.users > 0 {
+		entry.users--
+	}
+
+	if sizeErr != nil {
+		return fmt.Errorf("could not determine size of module cache entry: %w", sizeErr)
+	}
+
+	c.size += size - entry.size
+	entry.size = size
+
+	c.evict()
+
+	return nil
+}
+
+// evict removes least recently used entries not in use until the cache fits within its size limit.
+// NOTE: Caller should hold the lock.
+func (c *moduleCache) evict() {
+
+	if c.maxSize <= 0 || c.size <= c.maxSize {
+		return
+	}
+
+	keys := make([]string, 0, len(c.entries))
+	for key := range c.entries {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return c.entries[keys[i]].lastUsed.Before(c.entries[keys[j]].lastUsed)
+	})
+
+	for _, key := range keys {
+		if c.size <= c.maxSize {
+			break
+		}
+
+		if c.entries[key].users > 0 {
+			continue
+		}
+
+		err := c.fs.RemoveAll(filepath.Join(c.dir, key))
+		if err != nil {
+			c.log.Error().Err(err).Str("key", key).Msg("could not remove module cache entry")
+			continue
+		}
+
+		c.size -= c.entries[key].size
+		delete(c.entries, key)
+
+		c.metrics.IncrCounter(moduleCacheEvictionsMetric, 1)
+
+		c.log.Debug().Str("key", key).Msg("evicted module cache entry")
+	}
+}
+
+func (c *moduleCache) moduleHash(module string) (string, error) {
+
+	f, err := c.fs.Open(module)
+	if err != nil {
+		return "", fmt.Errorf("could not open module (path: %s): %w", module, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return "", fmt.Errorf("could not read module (path: %s): %w", module, err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func dirSize(fs afero.Fs, dir string) (int64, error) {
+
+	var size int64
+	err := afero.Walk(fs, dir, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			size += info.Size()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return size, nil
+}
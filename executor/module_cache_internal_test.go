@@ -0,0 +1,115 @@
+package executor
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/armon/go-metrics"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestModuleCache(t *testing.T) {
+
+	const (
+		cacheDir = "/var/tmp/b7s/modules"
+	)
+
+	writeFile := func(t *testing.T, fs afero.Fs, path string, size int) {
+		t.Helper()
+		err := afero.WriteFile(fs, path, make([]byte, size), defaultPermissions)
+		require.NoError(t, err)
+	}
+
+	t.Run("modules with same content share an entry", func(t *testing.T) {
+
+		fs := afero.NewMemMapFs()
+		writeFile(t, fs, "/function-a/module.wasm", 10)
+		writeFile(t, fs, "/function-b/module.wasm", 10)
+
+		cache, err := newModuleCache(mocks.NoopLogger, fs, metrics.Default(), cacheDir, 0)
+		require.NoError(t, err)
+
+		keyA, dirA, hit, err := cache.entry("/function-a/module.wasm", "")
+		require.NoError(t, err)
+		require.False(t, hit)
+
+		keyB, dirB, hit, err := cache.entry("/function-b/module.wasm", "")
+		require.NoError(t, err)
+		require.True(t, hit)
+
+		require.Equal(t, keyA, keyB)
+		require.Equal(t, dirA, dirB)
+		require.Equal(t, filepath.Join(cacheDir, keyA), dirA)
+	})
+	t.Run("least recently used entries are evicted", func(t *testing.T) {
+
+		fs := afero.NewMemMapFs()
+		writeFile(t, fs, "/function-a/module.wasm", 1)
+		writeFile(t, fs, "/function-b/module.wasm", 2)
+
+		cache, err := newModuleCache(mocks.NoopLogger, fs, metrics.Default(), cacheDir, 150)
+		require.NoError(t, err)
+
+		keyA, dirA, _, err := cache.entry("/function-a/module.wasm", "")
+		require.NoError(t, err)
+		writeFile(t, fs, filepath.Join(dirA, "compiled"), 100)
+		require.NoError(t, cache.release(keyA))
+
+		keyB, dirB, _, err := cache.entry("/function-b/module.wasm", "")
+		require.NoError(t, err)
+		writeFile(t, fs, filepath.Join(dirB, "compiled"), 100)
+		require.NoError(t, cache.release(keyB))
+
+		require.Len(t, cache.entries, 1)
+		require.Contains(t, cache.entries, keyB)
+		require.Equal(t, int64(100), cache.size)
+
+		exists, err := afero.DirExists(fs, dirA)
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+	t.Run("entries in use are not evicted", func(t *testing.T) {
+
+		fs := afero.NewMemMapFs()
+		writeFile(t, fs, "/function-a/module.wasm", 1)
+		writeFile(t, fs, "/function-b/module.wasm", 2)
+
+		cache, err := newModuleCache(mocks.NoopLogger, fs, metrics.Default(), cacheDir, 150)
+		require.NoError(t, err)
+
+		// Execution using the first module is still running.
+		keyA, dirA, _, err := cache.entry("/function-a/module.wasm", "")
+		require.NoError(t, err)
+		writeFile(t, fs, filepath.Join(dirA, "compiled"), 100)
+
+		keyB, dirB, _, err := cache.entry("/function-b/module.wasm", "")
+		require.NoError(t, err)
+		writeFile(t, fs, filepath.Join(dirB, "compiled"), 100)
+		require.NoError(t, cache.release(keyB))
+
+		exists, err := afero.DirExists(fs, dirA)
+		require.NoError(t, err)
+		require.True(t, exists)
+		require.Contains(t, cache.entries, keyA)
+
+		// Once the execution is done, the cache is brought back within its size limit.
+		require.NoError(t, cache.release(keyA))
+
+		require.Len(t, cache.entries, 1)
+		require.Equal(t, int64(100), cache.size)
+	})
+	t.Run("existing entries are loaded", func(t *testing.T) {
+
+		fs := afero.NewMemMapFs()
+		writeFile(t, fs, filepath.Join(cacheDir, "abcdef", "compiled"), 42)
+
+		cache, err := newModuleCache(mocks.NoopLogger, fs, metrics.Default(), cacheDir, 0)
+		require.NoError(t, err)
+
+		require.Len(t, cache.entries, 1)
+		require.Equal(t, int64(42), cache.size)
+	})
+}
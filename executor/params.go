@@ -2,6 +2,7 @@ package executor
 
 import (
 	"os"
+	"time"
 
 	"github.com/armon/go-metrics/prometheus"
 )
@@ -10,15 +11,30 @@ const (
 	defaultPermissions = os.ModePerm
 	blsListEnvName     = "BLS_LIST_VARS"
 	tracerName         = "b7s.Executor"
+
+	// How long the runtime has to print its help output when checking which features it supports.
+	runtimeProbeTimeout = 10 * time.Second
 )
 
 var (
-	functionExecutionsMetric  = []string{"executor", "function", "executions"}
-	functionDurationMetric    = []string{"executor", "function", "executions", "milliseconds"}
-	functionCPUUserTimeMetric = []string{"executor", "function", "executions", "cpu", "user", "time", "milliseconds"}
-	functionCPUSysTimeMetric  = []string{"executor", "function", "executions", "cpu", "sys", "time", "milliseconds"}
-	functionOkMetric          = []string{"executor", "function", "executions", "ok"}
-	functionErrMetric         = []string{"executor", "function", "executions", "err"}
+	functionExecutionsMetric    = []string{"executor", "function", "executions"}
+	functionDurationMetric      = []string{"executor", "function", "executions", "milliseconds"}
+	functionCPUUserTimeMetric   = []string{"executor", "function", "executions", "cpu", "user", "time", "milliseconds"}
+	functionCPUSysTimeMetric    = []string{"executor", "function", "executions", "cpu", "sys", "time", "milliseconds"}
+	functionOkMetric            = []string{"executor", "function", "executions", "ok"}
+	functionErrMetric           = []string{"executor", "function", "executions", "err"}
+	functionTimeoutMetric       = []string{"executor", "function", "executions", "timeout"}
+	moduleCacheHitsMetric       = []string{"executor", "module", "cache", "hits"}
+	moduleCacheMissesMetric     = []string{"executor", "module", "cache", "misses"}
+	moduleCacheEvictionsMetric  = []string{"executor", "module", "cache", "evictions"}
+	inputCacheHitsMetric        = []string{"executor", "input", "cache", "hits"}
+	inputCacheMissesMetric      = []string{"executor", "input", "cache", "misses"}
+	inputCacheEvictionsMetric   = []string{"executor", "input", "cache", "evictions"}
+	inputBytesFetchedMetric     = []string{"executor", "input", "fetched", "bytes"}
+	warmPoolHitsMetric          = []string{"executor", "warm", "pool", "hits"}
+	warmPoolMissesMetric        = []string{"executor", "warm", "pool", "misses"}
+	artifactsUploadedMetric     = []string{"executor", "function", "artifacts", "uploaded"}
+	artifactBytesUploadedMetric = []string{"executor", "function", "artifacts", "bytes"}
 )
 
 var Counters = []prometheus.CounterDefinition{
@@ -34,6 +50,10 @@ var Counters = []prometheus.CounterDefinition{
 		Name: functionErrMetric,
 		Help: "Number of functions executed by the node that resulted in an error.",
 	},
+	{
+		Name: functionTimeoutMetric,
+		Help: "Number of function executions killed for running past their timeout.",
+	},
 	{
 		Name: functionCPUUserTimeMetric,
 		Help: "Total CPU user time this node spent executing functions in milliseconds.",
@@ -42,6 +62,50 @@ var Counters = []prometheus.CounterDefinition{
 		Name: functionCPUSysTimeMetric,
 		Help: "Total CPU sys time this node spent executing functions in milliseconds.",
 	},
+	{
+		Name: moduleCacheHitsMetric,
+		Help: "Number of executions that found their compiled module in the cache.",
+	},
+	{
+		Name: moduleCacheMissesMetric,
+		Help: "Number of executions that had to compile their module.",
+	},
+	{
+		Name: moduleCacheEvictionsMetric,
+		Help: "Number of compiled modules evicted from the cache.",
+	},
+	{
+		Name: inputCacheHitsMetric,
+		Help: "Number of execution inputs found in the cache.",
+	},
+	{
+		Name: inputCacheMissesMetric,
+		Help: "Number of execution inputs that had to be fetched.",
+	},
+	{
+		Name: inputCacheEvictionsMetric,
+		Help: "Number of execution inputs evicted from the cache.",
+	},
+	{
+		Name: inputBytesFetchedMetric,
+		Help: "Total size of fetched execution inputs, in bytes.",
+	},
+	{
+		Name: warmPoolHitsMetric,
+		Help: "Number of executions handled by a runtime process started ahead of the execution.",
+	},
+	{
+		Name: warmPoolMissesMetric,
+		Help: "Number of executions that had to wait for a runtime process to start.",
+	},
+	{
+		Name: artifactsUploadedMetric,
+		Help: "Number of output files of executions uploaded to blob storage.",
+	},
+	{
+		Name: artifactBytesUploadedMetric,
+		Help: "Total size of output files of executions uploaded to blob storage, in bytes.",
+	},
 }
 
 var Summaries = []prometheus.SummaryDefinition{
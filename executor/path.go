@@ -9,16 +9,26 @@ type requestPaths struct {
 	workdir string
 	fsRoot  string
 	input   string
+	// Directory where the function writes files to be uploaded as artifacts.
+	artifacts string
+
+	// Optional - directory where the runtime should keep the compiled module.
+	moduleCache string
 }
 
-func (e *Executor) generateRequestPaths(requestID string, functionID string, method string) requestPaths {
+func (e *Executor) generateRequestPaths(requestID string, functionID string, method string, tenant string) requestPaths {
 
-	// Workdir Should be the root for all other paths.
+	// Workdir Should be the root for all other paths. Executions of tenants are kept in the directory of the tenant.
 	workdir := filepath.Join(e.cfg.WorkDir, "t", requestID)
+	if tenant != "" {
+		workdir = filepath.Join(e.tenantDir(tenant), "t", requestID)
+	}
+	fsRoot := filepath.Join(workdir, "fs")
 	paths := requestPaths{
-		workdir: workdir,
-		fsRoot:  filepath.Join(workdir, "fs"),
-		input:   filepath.Join(e.cfg.WorkDir, functionID, method),
+		workdir:   workdir,
+		fsRoot:    fsRoot,
+		input:     filepath.Join(e.cfg.WorkDir, functionID, method),
+		artifacts: filepath.Join(fsRoot, artifactsDir),
 	}
 
 	return paths
@@ -31,8 +31,14 @@ func TestExecutor_RequestPaths(t *testing.T) {
 	}
 
 	// NOTE: We use filepath.Clean to have consistent separators on platforms.
-	paths := executor.generateRequestPaths(requestID, functionID, functionMethod)
+	paths := executor.generateRequestPaths(requestID, functionID, functionMethod, "")
 	assert.Equal(t, filepath.Clean(expectedRequestWorkdir), paths.workdir)
 	assert.Equal(t, filepath.Clean(expectedEntry), paths.input)
 	assert.Equal(t, filepath.Clean(expectedFSRoot), paths.fsRoot)
+
+	// Executions of tenants get working directories of their own, but share the function files.
+	paths = executor.generateRequestPaths(requestID, functionID, functionMethod, "acme")
+	assert.Equal(t, filepath.Clean(workdir+"/tenants/acme/t/request-id"), paths.workdir)
+	assert.Equal(t, filepath.Clean(workdir+"/tenants/acme/t/request-id/fs"), paths.fsRoot)
+	assert.Equal(t, filepath.Clean(expectedEntry), paths.input)
 }
@@ -0,0 +1,436 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-multierror"
+	"github.com/rs/zerolog"
+
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+// Reasons for retiring a pooled runtime process.
+const (
+	recycleMaxInvocations = "max-invocations"
+	recycleMaxLifetime    = "max-lifetime"
+	recycleMemoryCeiling  = "memory-ceiling"
+	recycleFailure        = "failure"
+	recycleEvicted        = "evicted"
+	recycleTimeout        = "timeout"
+)
+
+// ReusePolicy describes when a runtime process can be reused for subsequent executions of the same module.
+// Reusing processes avoids the cold start cost, at the expense of weaker isolation between executions.
+type ReusePolicy struct {
+	MaxInvocations  uint          // Maximum number of executions a process will handle. Reuse is disabled if this is less than two.
+	MaxLifetime     time.Duration // Maximum amount of time a process will be kept around. Zero means no limit.
+	MemoryCeilingKB int64         // Memory usage (kB) after which the process is recycled. Zero means no limit.
+}
+
+func (p ReusePolicy) enabled() bool {
+	return p.MaxInvocations > 1
+}
+
+// invocation is the message sent to the runtime process running in serve mode.
+type invocation struct {
+	Args  []string `json:"args"`
+	Env   []string `json:"env,omitempty"`
+	Stdin string   `json:"stdin,omitempty"`
+	Dir   string   `json:"dir,omitempty"`
+}
+
+// invocationResult is the message with which the runtime process responds to an invocation.
+type invocationResult struct {
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+	ExitCode int    `json:"exit_code"`
+	MemoryKB int64  `json:"memory_kb,omitempty"`
+}
+
+// pooledProcess is a runtime process that can handle multiple executions of a single module.
+type pooledProcess struct {
+	cmd         *exec.Cmd
+	stdin       io.WriteCloser
+	dec         *json.Decoder
+	started     time.Time
+	invocations uint
+	warm        bool // process was started ahead of the execution, by the warm pool
+}
+
+func (p *pooledProcess) invoke(inv invocation) (invocationResult, error) {
+
+	payload, err := json.Marshal(inv)
+	if err != nil {
+		return invocationResult{}, fmt.Errorf("could not encode invocation: %w", err)
+	}
+
+	_, err = p.stdin.Write(append(payload, '\n'))
+	if err != nil {
+		return invocationResult{}, fmt.Errorf("could not send invocation to process: %w", err)
+	}
+
+	var res invocationResult
+	err = p.dec.Decode(&res)
+	if err != nil {
+		return invocationResult{}, fmt.Errorf("could not read invocation result: %w", err)
+	}
+
+	p.invocations++
+
+	return res, nil
+}
+
+func (p *pooledProcess) stop() error {
+
+	// Closing stdin signals the runtime to exit.
+	_ = p.stdin.Close()
+
+	err := p.cmd.Wait()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+// processPool keeps idle runtime processes, grouped by the module they run.
+type processPool struct {
+	sync.Mutex
+
+	log    zerolog.Logger
+	policy ReusePolicy
+	start  func(module string) (*pooledProcess, error)
+	idle   map[string][]*pooledProcess
+
+	// Warm pool state - when was each module last used and how many processes are being started for it.
+	warm     WarmPolicy
+	used     map[string]time.Time
+	starting map[string]uint
+	closed   bool
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+func newProcessPool(log zerolog.Logger, policy ReusePolicy, warm WarmPolicy, start func(string) (*pooledProcess, error)) *processPool {
+
+	pool := processPool{
+		log:      log.With().Str("component", "process_pool").Logger(),
+		policy:   policy,
+		start:    start,
+		idle:     make(map[string][]*pooledProcess),
+		warm:     warm,
+		used:     make(map[string]time.Time),
+		starting: make(map[string]uint),
+		done:     make(chan struct{}),
+	}
+
+	if warm.enabled() && warm.IdleTimeout > 0 {
+		pool.wg.Add(1)
+		go pool.evictIdle()
+	}
+
+	return &pool
+}
+
+// acquire returns an idle process for the module, or starts a new one. Processes that outlived their lifetime are retired.
+func (p *processPool) acquire(module string) (*pooledProcess, error) {
+
+	var (
+		proc    *pooledProcess
+		expired []*pooledProcess
+	)
+
+	p.Lock()
+	evicted := p.touch(module, time.Now())
+	for len(p.idle[module]) > 0 {
+
+		last := len(p.idle[module]) - 1
+		candidate := p.idle[module][last]
+		p.idle[module] = p.idle[module][:last]
+
+		if p.policy.MaxLifetime > 0 && time.Since(candidate.started) >= p.policy.MaxLifetime {
+			expired = append(expired, candidate)
+			continue
+		}
+
+		proc = candidate
+		break
+	}
+	p.Unlock()
+
+	for _, old := range expired {
+		p.retire(old, recycleMaxLifetime)
+	}
+	for _, old := range evicted {
+		p.retire(old, recycleEvicted)
+	}
+
+	// Replace the process we took, so the next execution finds one ready.
+	p.replenish(module)
+
+	if proc != nil {
+		return proc, nil
+	}
+
+	proc, err := p.start(module)
+	if err != nil {
+		return nil, fmt.Errorf("could not start runtime process: %w", err)
+	}
+
+	return proc, nil
+}
+
+// release returns the process to the pool, unless the reuse policy says it should be retired.
+// The returned value describes the reuse decision made for the execution.
+func (p *processPool) release(module string, proc *pooledProcess, res invocationResult) execute.ProcessReuse {
+
+	decision := execute.ProcessReuse{
+		Reused:     proc.invocations > 1,
+		Invocation: proc.invocations,
+		Warm:       proc.warm && proc.invocations == 1,
+	}
+
+	reason := ""
+	switch {
+	case proc.invocations >= p.policy.MaxInvocations:
+		reason = recycleMaxInvocations
+	case p.policy.MaxLifetime > 0 && time.Since(proc.started) >= p.policy.MaxLifetime:
+		reason = recycleMaxLifetime
+	case p.policy.MemoryCeilingKB > 0 && res.MemoryKB >= p.policy.MemoryCeilingKB:
+		reason = recycleMemoryCeiling
+	}
+
+	if reason != "" {
+		decision.Recycled = true
+		decision.Reason = reason
+		p.retire(proc, reason)
+		return decision
+	}
+
+	p.Lock()
+	// Do not keep processes for modules evicted in the meantime.
+	evicted := p.closed || (p.warm.enabled() && p.used[module].IsZero())
+	if !evicted {
+		p.idle[module] = append(p.idle[module], proc)
+	}
+	p.Unlock()
+
+	if evicted {
+		decision.Recycled = true
+		decision.Reason = recycleEvicted
+		p.retire(proc, recycleEvicted)
+	}
+
+	return decision
+}
+
+func (p *processPool) retire(proc *pooledProcess, reason string) {
+
+	p.log.Debug().Int("pid", proc.cmd.Process.Pid).Uint("invocations", proc.invocations).Str("reason", reason).Msg("retiring runtime process")
+
+	err := proc.stop()
+	if err != nil {
+		p.log.Warn().Err(err).Int("pid", proc.cmd.Process.Pid).Msg("runtime process did not exit cleanly")
+	}
+}
+
+// shutdown stops all idle processes.
+func (p *processPool) shutdown() error {
+
+	p.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.done)
+	}
+	p.Unlock()
+
+	// Wait for processes being started to be done, they will be stopped right away.
+	p.wg.Wait()
+
+	p.Lock()
+	defer p.Unlock()
+
+	var multierr *multierror.Error
+	for module, procs := range p.idle {
+		for _, proc := range procs {
+			err := proc.stop()
+			if err != nil {
+				multierr = multierror.Append(multierr, fmt.Errorf("could not stop process (pid: %v): %w", proc.cmd.Process.Pid, err))
+			}
+		}
+		delete(p.idle, module)
+	}
+
+	return multierr.ErrorOrNil()
+}
+
+// startPooledProcess starts the runtime in serve mode for the module and tenant of the pool key. Pool is created only for runtimes supporting serve mode.
+func (e *Executor) startPooledProcess(key string) (*pooledProcess, error) {
+
+	module, tenant := splitPoolKey(key)
+
+	exePath := filepath.Join(e.cfg.RuntimeDir, e.cfg.ExecutableName)
+
+	cmd := exec.Command(exePath, module, "--"+execute.BLSRuntimeFlagServe)
+	cmd.Dir = e.cfg.WorkDir
+	if tenant != "" {
+		cmd.Dir = e.tenantDir(tenant)
+		err := e.cfg.FS.MkdirAll(cmd.Dir, defaultPermissions)
+		if err != nil {
+			return nil, fmt.Errorf("could not create tenant directory (dir: %s): %w", cmd.Dir, err)
+		}
+	}
+
+	// Pooled processes serve executions that do not require GPUs.
+	gpuEnv := e.gpuEnv(false)
+	if tenant != "" || len(gpuEnv) > 0 {
+		cmd.Env = append(runtimeEnvironment(tenant, cmd.Dir), gpuEnv...)
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("could not get process stdin: %w", err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("could not get process stdout: %w", err)
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		return nil, fmt.Errorf("could not start process: %w", err)
+	}
+
+	proc := &pooledProcess{
+		cmd:     cmd,
+		stdin:   stdin,
+		dec:     json.NewDecoder(stdout),
+		started: time.Now(),
+	}
+
+	err = e.cfg.Limiter.LimitProcess(execute.ProcessID{PID: cmd.Process.Pid})
+	if err != nil {
+		_ = proc.stop()
+		return nil, fmt.Errorf("could not set resource limits: %w", err)
+	}
+
+	e.log.Debug().Int("pid", cmd.Process.Pid).Str("module", module).Msg("started pooled runtime process")
+
+	return proc, nil
+}
+
+// executePooled runs the execution request using a pooled runtime process.
+func (e *Executor) executePooled(ctx context.Context, paths requestPaths, req execute.Request) (execute.RuntimeOutput, execute.Usage, *execute.ProcessReuse, error) {
+
+	// Create the command as we would for a standalone execution, and send its arguments to the runtime process.
+	// First argument is the module path, which is fixed for the process.
+	cmd := e.createCmd(ctx, paths, req)
+
+	inv := invocation{
+		Args: cmd.Args[2:],
+		Env:  cmd.Env,
+		Dir:  cmd.Dir,
+	}
+	if req.Config.Stdin != nil {
+		inv.Stdin = *req.Config.Stdin
+	}
+
+	// Processes are pooled per tenant, so executions of a tenant never run in a process that served another tenant.
+	key := poolKey(paths.input, req.Config.Tenant)
+
+	proc, err := e.pool.acquire(key)
+	if err != nil {
+		return execute.RuntimeOutput{}, execute.Usage{}, nil, fmt.Errorf("could not get runtime process: %w", err)
+	}
+
+	// Kill the process if the execution runs past its timeout. The process responds with its output only when the
+	// execution completes, so there is no partial output to return.
+	timeout := executionTimeout(req)
+	expired := killAfter(timeout, func() {
+		_ = proc.cmd.Process.Kill()
+	})
+
+	// Kill the process if the execution is aborted too.
+	stop := context.AfterFunc(ctx, func() {
+		_ = proc.cmd.Process.Kill()
+	})
+	defer stop()
+
+	start := time.Now()
+	res, err := proc.invoke(inv)
+	if expired() {
+		e.pool.retire(proc, recycleTimeout)
+
+		out := execute.RuntimeOutput{ExitCode: -1}
+		usage := execute.Usage{WallClockTime: time.Since(start)}
+		reuse := execute.ProcessReuse{
+			Reused:     proc.invocations > 0,
+			Invocation: proc.invocations + 1,
+			Recycled:   true,
+			Reason:     recycleTimeout,
+			Warm:       proc.warm && proc.invocations == 0,
+		}
+
+		return out, usage, &reuse, fmt.Errorf("process killed after %v: %w", timeout, errExecutionTimeout)
+	}
+	if err != nil && ctx.Err() != nil {
+		e.pool.retire(proc, recycleFailure)
+		return execute.RuntimeOutput{}, execute.Usage{}, nil, fmt.Errorf("process killed: %w", ctx.Err())
+	}
+	if err != nil {
+		e.pool.retire(proc, recycleFailure)
+		return execute.RuntimeOutput{}, execute.Usage{}, nil, fmt.Errorf("pooled execution failed: %w", err)
+	}
+
+	usage := execute.Usage{
+		WallClockTime: time.Since(start),
+		MemoryMaxKB:   res.MemoryKB,
+	}
+
+	out := execute.RuntimeOutput{
+		Stdout:   res.Stdout,
+		Stderr:   res.Stderr,
+		ExitCode: res.ExitCode,
+	}
+
+	decision := e.pool.release(key, proc, res)
+
+	if e.cfg.Warm.enabled() && !decision.Reused {
+		ml := []metrics.Label{{Name: "function", Value: req.FunctionID}}
+		if decision.Warm {
+			e.metrics.IncrCounterWithLabels(warmPoolHitsMetric, 1, ml)
+		} else {
+			e.metrics.IncrCounterWithLabels(warmPoolMissesMetric, 1, ml)
+		}
+	}
+
+	if res.ExitCode != 0 {
+		return out, usage, &decision, fmt.Errorf("process execution failed (exit code: %v)", res.ExitCode)
+	}
+
+	return out, usage, &decision, nil
+}
+
+// Shutdown stops any runtime processes kept by the executor.
+func (e *Executor) Shutdown() error {
+
+	if e.pool == nil {
+		return nil
+	}
+
+	return e.pool.shutdown()
+}
@@ -0,0 +1,115 @@
+package executor
+
+import (
+	"encoding/json"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestProcessPool(t *testing.T) {
+
+	const (
+		module = "/var/tmp/b7s/module.wasm"
+	)
+
+	run := func(t *testing.T, pool *processPool) (*pooledProcess, invocationResult) {
+		t.Helper()
+
+		proc, err := pool.acquire(module)
+		require.NoError(t, err)
+
+		res, err := proc.invoke(invocation{Args: []string{"--fuel", "1"}})
+		require.NoError(t, err)
+		require.Equal(t, "hello", res.Stdout)
+
+		return proc, res
+	}
+
+	t.Run("process is reused until max invocations", func(t *testing.T) {
+
+		pool := newProcessPool(mocks.NoopLogger, ReusePolicy{MaxInvocations: 2}, WarmPolicy{}, startFakeProcess(0))
+		defer pool.shutdown()
+
+		first, res := run(t, pool)
+		decision := pool.release(module, first, res)
+		require.False(t, decision.Reused)
+		require.False(t, decision.Recycled)
+		require.Equal(t, uint(1), decision.Invocation)
+
+		second, res := run(t, pool)
+		require.Same(t, first, second)
+		decision = pool.release(module, second, res)
+		require.True(t, decision.Reused)
+		require.True(t, decision.Recycled)
+		require.Equal(t, recycleMaxInvocations, decision.Reason)
+
+		require.Empty(t, pool.idle[module])
+	})
+	t.Run("process is recycled after reaching memory ceiling", func(t *testing.T) {
+
+		pool := newProcessPool(mocks.NoopLogger, ReusePolicy{MaxInvocations: 10, MemoryCeilingKB: 1024}, WarmPolicy{}, startFakeProcess(2048))
+		defer pool.shutdown()
+
+		proc, res := run(t, pool)
+		decision := pool.release(module, proc, res)
+		require.True(t, decision.Recycled)
+		require.Equal(t, recycleMemoryCeiling, decision.Reason)
+
+		require.Empty(t, pool.idle[module])
+	})
+	t.Run("expired processes are not reused", func(t *testing.T) {
+
+		pool := newProcessPool(mocks.NoopLogger, ReusePolicy{MaxInvocations: 10, MaxLifetime: time.Hour}, WarmPolicy{}, startFakeProcess(0))
+		defer pool.shutdown()
+
+		first, res := run(t, pool)
+		pool.release(module, first, res)
+		require.Len(t, pool.idle[module], 1)
+
+		first.started = time.Now().Add(-2 * time.Hour)
+
+		second, _ := run(t, pool)
+		require.NotSame(t, first, second)
+	})
+}
+
+// startFakeProcess starts a process that responds to each invocation with a fixed result.
+func startFakeProcess(memoryKB int64) func(string) (*pooledProcess, error) {
+	return func(string) (*pooledProcess, error) {
+
+		response, err := json.Marshal(invocationResult{Stdout: "hello", MemoryKB: memoryKB})
+		if err != nil {
+			return nil, err
+		}
+
+		cmd := exec.Command("sh", "-c", "while read line; do echo '"+string(response)+"'; done")
+
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return nil, err
+		}
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, err
+		}
+
+		err = cmd.Start()
+		if err != nil {
+			return nil, err
+		}
+
+		proc := pooledProcess{
+			cmd:     cmd,
+			stdin:   stdin,
+			dec:     json.NewDecoder(stdout),
+			started: time.Now(),
+		}
+
+		return &proc, nil
+	}
+}
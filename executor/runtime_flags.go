@@ -33,6 +33,10 @@ func runtimeFlags(cfg execute.BLSRuntimeConfig, permissions []string) []string {
 		flags = append(flags, "--"+execute.BLSRuntimeFlagDrivers, cfg.DriversRootPath)
 	}
 
+	if cfg.ModuleCache != "" {
+		flags = append(flags, "--"+execute.BLSRuntimeFlagModuleCache, cfg.ModuleCache)
+	}
+
 	if cfg.Fuel > 0 {
 		flags = append(flags, "--"+execute.BLSRuntimeFlagFuel, fmt.Sprint(cfg.Fuel))
 	}
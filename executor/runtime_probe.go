@@ -0,0 +1,42 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+// runtimeFeatures describes optional features of the Blockless Runtime the executor can use.
+// Runtime versions without a feature reject its flag and fail the execution, so features are used only if the runtime advertises them.
+type runtimeFeatures struct {
+	moduleCache bool // Runtime accepts a directory for compiled modules via `--module-cache-path`.
+	serve       bool // Runtime can handle multiple executions in a single process via `--serve`.
+}
+
+// probeRuntime checks which optional features the runtime supports, by looking for their flags in the runtime help output.
+func probeRuntime(path string) (runtimeFeatures, error) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), runtimeProbeTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, path, "--help").CombinedOutput()
+	if err != nil {
+		return runtimeFeatures{}, fmt.Errorf("could not get runtime help output: %w", err)
+	}
+
+	features := runtimeFeatures{
+		moduleCache: hasFlag(out, execute.BLSRuntimeFlagModuleCache),
+		serve:       hasFlag(out, execute.BLSRuntimeFlagServe),
+	}
+
+	return features, nil
+}
+
+// hasFlag returns true if the help output lists the given flag.
+func hasFlag(help []byte, flag string) bool {
+	re := regexp.MustCompile(`--` + regexp.QuoteMeta(flag) + `([^\w-]|$)`)
+	return re.Match(help)
+}
@@ -0,0 +1,113 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestExecutor_RuntimeFeatures(t *testing.T) {
+
+	const (
+		runtimeName = "runtime.sh"
+	)
+
+	// Fake runtime printing the given help output.
+	fakeRuntime := func(t *testing.T, help string) string {
+		t.Helper()
+
+		dir := t.TempDir()
+		script := "#!/bin/sh\ncat <<'EOF'\n" + help + "\nEOF\n"
+		err := os.WriteFile(filepath.Join(dir, runtimeName), []byte(script), 0755)
+		require.NoError(t, err)
+
+		return dir
+	}
+
+	const (
+		currentHelp = "Usage: bls-runtime [OPTIONS] [INPUT]\n      --fs-root-path <FS_ROOT_PATH>\n      --module-cache-path <MODULE_CACHE_PATH>\n      --serve\n"
+		legacyHelp  = "Usage: bls-runtime [OPTIONS] [INPUT]\n      --fs-root-path <FS_ROOT_PATH>\n      --module-cache-path-v2 <PATH>\n      --server-name <NAME>\n"
+	)
+
+	t.Run("features listed in help output are detected", func(t *testing.T) {
+		t.Parallel()
+
+		dir := fakeRuntime(t, currentHelp)
+
+		features, err := probeRuntime(filepath.Join(dir, runtimeName))
+		require.NoError(t, err)
+		require.True(t, features.moduleCache)
+		require.True(t, features.serve)
+	})
+	t.Run("flags only sharing the prefix are not matched", func(t *testing.T) {
+		t.Parallel()
+
+		dir := fakeRuntime(t, legacyHelp)
+
+		features, err := probeRuntime(filepath.Join(dir, runtimeName))
+		require.NoError(t, err)
+		require.False(t, features.moduleCache)
+		require.False(t, features.serve)
+	})
+	t.Run("module cache is disabled for runtimes without support", func(t *testing.T) {
+		t.Parallel()
+
+		dir := fakeRuntime(t, legacyHelp)
+
+		executor, err := New(mocks.NoopLogger,
+			WithRuntimeDir(dir),
+			WithExecutableName(runtimeName),
+			WithWorkDir(t.TempDir()),
+			WithModuleCache(t.TempDir(), 0),
+		)
+		require.NoError(t, err)
+		require.Nil(t, executor.modules)
+	})
+	t.Run("module cache is used by runtimes with support", func(t *testing.T) {
+		t.Parallel()
+
+		dir := fakeRuntime(t, currentHelp)
+
+		executor, err := New(mocks.NoopLogger,
+			WithRuntimeDir(dir),
+			WithExecutableName(runtimeName),
+			WithWorkDir(t.TempDir()),
+			WithModuleCache(t.TempDir(), 0),
+		)
+		require.NoError(t, err)
+		require.NotNil(t, executor.modules)
+	})
+	t.Run("process reuse is disabled for runtimes without support", func(t *testing.T) {
+		t.Parallel()
+
+		dir := fakeRuntime(t, legacyHelp)
+
+		executor, err := New(mocks.NoopLogger,
+			WithRuntimeDir(dir),
+			WithExecutableName(runtimeName),
+			WithWorkDir(t.TempDir()),
+			WithProcessReuse(ReusePolicy{MaxInvocations: 10}),
+		)
+		require.NoError(t, err)
+		require.Nil(t, executor.pool)
+	})
+	t.Run("process reuse is used by runtimes with support", func(t *testing.T) {
+		t.Parallel()
+
+		dir := fakeRuntime(t, currentHelp)
+
+		executor, err := New(mocks.NoopLogger,
+			WithRuntimeDir(dir),
+			WithExecutableName(runtimeName),
+			WithWorkDir(t.TempDir()),
+			WithProcessReuse(ReusePolicy{MaxInvocations: 10}),
+		)
+		require.NoError(t, err)
+		require.NotNil(t, executor.pool)
+		require.NoError(t, executor.pool.shutdown())
+	})
+}
@@ -0,0 +1,58 @@
+package synthetic
+
+import (
+	"errors"
+	"time"
+)
+
+// Config represents the synthetic executor configuration.
+type Config struct {
+	Latency     time.Duration // how long each execution takes
+	OutputSize  uint          // size of the standard output of successful executions, in bytes
+	FailureRate float64       // share of executions that fail, in the 0-1 range
+	Seed        int64         // seed used to decide which executions fail; zero means a random seed
+}
+
+// Valid checks if the configuration is correct.
+func (c Config) Valid() error {
+
+	if c.Latency < 0 {
+		return errors.New("latency cannot be negative")
+	}
+
+	if c.FailureRate < 0 || c.FailureRate > 1 {
+		return errors.New("failure rate must be between 0 and 1")
+	}
+
+	return nil
+}
+
+type Option func(*Config)
+
+// WithLatency sets how long each execution takes.
+func WithLatency(d time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.Latency = d
+	}
+}
+
+// WithOutputSize sets the size of the output produced by successful executions.
+func WithOutputSize(n uint) Option {
+	return func(cfg *Config) {
+		cfg.OutputSize = n
+	}
+}
+
+// WithFailureRate sets the share of executions that fail.
+func WithFailureRate(rate float64) Option {
+	return func(cfg *Config) {
+		cfg.FailureRate = rate
+	}
+}
+
+// WithSeed sets the seed used to decide which executions fail, so runs can be reproduced.
+func WithSeed(seed int64) Option {
+	return func(cfg *Config) {
+		cfg.Seed = seed
+	}
+}
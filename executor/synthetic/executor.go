@@ -0,0 +1,123 @@
+// Package synthetic provides an executor that does not run Blockless functions. Executions take a configured amount of time,
+// produce output of a configured size and fail at a configured rate. It is used to soak-test the network and consensus layers
+// at scale, without real workloads.
+package synthetic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/random"
+)
+
+const (
+	// Pattern the output of successful executions is made of. Output is the same on all workers, so results can reach consensus.
+	outputPattern = "synthetic execution output\n"
+
+	failureExitCode = 1
+	failureMessage  = "synthetic execution failure"
+)
+
+// Executor simulates function executions.
+type Executor struct {
+	log    zerolog.Logger
+	cfg    Config
+	output string
+	rand   *random.Source
+}
+
+// New creates a new synthetic executor.
+func New(log zerolog.Logger, options ...Option) (*Executor, error) {
+
+	var cfg Config
+	for _, option := range options {
+		option(&cfg)
+	}
+
+	err := cfg.Valid()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	rand := random.NewUnseeded()
+	if cfg.Seed != 0 {
+		rand = random.New(cfg.Seed)
+	}
+
+	e := Executor{
+		log:    log,
+		cfg:    cfg,
+		output: output(cfg.OutputSize),
+		rand:   rand,
+	}
+
+	return &e, nil
+}
+
+// ExecuteFunction waits for the configured latency and returns a synthetic result.
+func (e *Executor) ExecuteFunction(ctx context.Context, requestID string, req execute.Request) (execute.Result, error) {
+
+	e.log.Debug().Str("request", requestID).Str("function", req.FunctionID).Msg("processing synthetic execution request")
+
+	start := time.Now()
+
+	if e.cfg.Latency > 0 {
+		timer := time.NewTimer(e.cfg.Latency)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			res := execute.Result{
+				Code:  codes.Error,
+				Usage: execute.Usage{WallClockTime: time.Since(start)},
+			}
+			return res, fmt.Errorf("execution cancelled: %w", ctx.Err())
+		}
+	}
+
+	usage := execute.Usage{
+		WallClockTime: time.Since(start),
+	}
+
+	if e.rand.Chance(e.cfg.FailureRate) {
+		res := execute.Result{
+			Code: codes.Error,
+			Result: execute.RuntimeOutput{
+				Stderr:   failureMessage,
+				ExitCode: failureExitCode,
+			},
+			Usage: usage,
+		}
+		return res, errors.New("function execution failed: " + failureMessage)
+	}
+
+	res := execute.Result{
+		Code: codes.OK,
+		Result: execute.RuntimeOutput{
+			Stdout: e.output,
+		},
+		Usage: usage,
+	}
+
+	return res, nil
+}
+
+func output(size uint) string {
+
+	if size == 0 {
+		return ""
+	}
+
+	n := int(size)
+	repeat := n/len(outputPattern) + 1
+
+	return strings.Repeat(outputPattern, repeat)[:n]
+}
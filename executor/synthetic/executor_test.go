@@ -0,0 +1,121 @@
+package synthetic_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/executor/synthetic"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestExecutor_Create(t *testing.T) {
+	t.Run("nominal case", func(t *testing.T) {
+		_, err := synthetic.New(mocks.NoopLogger,
+			synthetic.WithLatency(time.Second),
+			synthetic.WithOutputSize(1024),
+			synthetic.WithFailureRate(0.1),
+		)
+		require.NoError(t, err)
+	})
+	t.Run("negative latency", func(t *testing.T) {
+		_, err := synthetic.New(mocks.NoopLogger, synthetic.WithLatency(-time.Second))
+		require.Error(t, err)
+	})
+	t.Run("invalid failure rate", func(t *testing.T) {
+		_, err := synthetic.New(mocks.NoopLogger, synthetic.WithFailureRate(1.5))
+		require.Error(t, err)
+	})
+}
+
+func TestExecutor_ExecuteFunction(t *testing.T) {
+
+	const (
+		requestID = "dummy-request-id"
+	)
+
+	req := execute.Request{
+		FunctionID: "dummy-function-id",
+		Method:     "dummy-method",
+	}
+
+	t.Run("output has the configured size", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			latency = 50 * time.Millisecond
+			size    = 1000
+		)
+
+		executor, err := synthetic.New(mocks.NoopLogger,
+			synthetic.WithLatency(latency),
+			synthetic.WithOutputSize(size),
+		)
+		require.NoError(t, err)
+
+		res, err := executor.ExecuteFunction(context.Background(), requestID, req)
+		require.NoError(t, err)
+		require.Equal(t, codes.OK, res.Code)
+		require.Len(t, res.Result.Stdout, size)
+		require.GreaterOrEqual(t, res.Usage.WallClockTime, latency)
+
+		// Output is the same for all executions.
+		again, err := executor.ExecuteFunction(context.Background(), requestID, req)
+		require.NoError(t, err)
+		require.Equal(t, res.Result.Stdout, again.Result.Stdout)
+	})
+	t.Run("executions fail at the configured rate", func(t *testing.T) {
+		t.Parallel()
+
+		executor, err := synthetic.New(mocks.NoopLogger, synthetic.WithFailureRate(1))
+		require.NoError(t, err)
+
+		res, err := executor.ExecuteFunction(context.Background(), requestID, req)
+		require.Error(t, err)
+		require.Equal(t, codes.Error, res.Code)
+		require.NotZero(t, res.Result.ExitCode)
+	})
+	t.Run("same seed fails the same executions", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			executions = 50
+			seed       = 1234
+		)
+
+		outcomes := func() []codes.Code {
+			executor, err := synthetic.New(mocks.NoopLogger, synthetic.WithFailureRate(0.5), synthetic.WithSeed(seed))
+			require.NoError(t, err)
+
+			out := make([]codes.Code, 0, executions)
+			for i := 0; i < executions; i++ {
+				res, _ := executor.ExecuteFunction(context.Background(), requestID, req)
+				out = append(out, res.Code)
+			}
+
+			return out
+		}
+
+		first := outcomes()
+		require.Contains(t, first, codes.OK)
+		require.Contains(t, first, codes.Error)
+		require.Equal(t, first, outcomes())
+	})
+	t.Run("execution is cancelled with context", func(t *testing.T) {
+		t.Parallel()
+
+		executor, err := synthetic.New(mocks.NoopLogger, synthetic.WithLatency(time.Minute))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		res, err := executor.ExecuteFunction(ctx, requestID, req)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, codes.Error, res.Code)
+	})
+}
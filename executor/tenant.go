@@ -0,0 +1,64 @@
+package executor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// Workspace subdirectory holding working directories of tenant executions.
+	tenantsDir = "tenants"
+
+	// Separates the module from the tenant in process pool keys. Tenant IDs cannot contain it.
+	poolKeySeparator = "\x00"
+)
+
+// Worker environment variables passed through to executions of tenants. Anything else, such as credentials
+// used by the worker itself, is not visible to them.
+var tenantEnvPassthrough = []string{"PATH", "LD_LIBRARY_PATH", "LANG", "TZ", "SYSTEMROOT"}
+
+var errInvalidTenant = errors.New("invalid tenant ID")
+
+// tenantDir returns the directory holding working directories of executions of the tenant.
+func (e *Executor) tenantDir(tenant string) string {
+	return filepath.Join(e.cfg.WorkDir, tenantsDir, tenant)
+}
+
+// runtimeEnvironment returns the worker environment the runtime is started with. Executions without a tenant inherit the entire
+// worker environment. Executions of a tenant get only a minimal set of variables, and their own home and temporary directory.
+func runtimeEnvironment(tenant string, home string) []string {
+
+	if tenant == "" {
+		return os.Environ()
+	}
+
+	var env []string
+	for _, name := range tenantEnvPassthrough {
+		value, ok := os.LookupEnv(name)
+		if ok {
+			env = append(env, name+"="+value)
+		}
+	}
+
+	env = append(env, "HOME="+home, "TMPDIR="+home)
+
+	return env
+}
+
+// poolKey returns the key under which runtime processes for the module are pooled. Processes are never shared between tenants.
+func poolKey(module string, tenant string) string {
+
+	if tenant == "" {
+		return module
+	}
+
+	return module + poolKeySeparator + tenant
+}
+
+// splitPoolKey returns the module and the tenant the runtime processes pooled under the key are for.
+func splitPoolKey(key string) (string, string) {
+	module, tenant, _ := strings.Cut(key, poolKeySeparator)
+	return module, tenant
+}
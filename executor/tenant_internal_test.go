@@ -0,0 +1,108 @@
+package executor
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/armon/go-metrics"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestExecutor_Tenants(t *testing.T) {
+
+	const (
+		tenant = "acme"
+	)
+
+	t.Run("tenant executions do not see the worker environment", func(t *testing.T) {
+		t.Setenv("B7S_WORKER_SECRET", "dummy-secret")
+		t.Setenv("PATH", "/usr/bin")
+
+		executor := Executor{
+			log: mocks.NoopLogger,
+			cfg: Config{
+				WorkDir:        "/var/tmp/b7s/workspace",
+				ExecutableName: blockless.RuntimeCLI(),
+			},
+		}
+
+		req := mocks.GenericExecutionRequest
+		req.Config.Environment = []execute.EnvVar{{Name: "FOO", Value: "bar"}}
+
+		cmd := executor.createCmd(context.Background(), executor.generateRequestPaths(mocks.GenericUUID.String(), req.FunctionID, req.Method, ""), req)
+		require.Contains(t, cmd.Env, "B7S_WORKER_SECRET=dummy-secret")
+
+		req.Config.Tenant = tenant
+		paths := executor.generateRequestPaths(mocks.GenericUUID.String(), req.FunctionID, req.Method, tenant)
+		cmd = executor.createCmd(context.Background(), paths, req)
+		require.NotContains(t, cmd.Env, "B7S_WORKER_SECRET=dummy-secret")
+		require.Contains(t, cmd.Env, "PATH=/usr/bin")
+		require.Contains(t, cmd.Env, "HOME="+paths.workdir)
+		require.Contains(t, cmd.Env, "FOO=bar")
+	})
+	t.Run("runtime processes are pooled per tenant", func(t *testing.T) {
+
+		const module = "/var/tmp/b7s/workspace/function-id/module.wasm"
+
+		require.Equal(t, module, poolKey(module, ""))
+		require.NotEqual(t, poolKey(module, ""), poolKey(module, tenant))
+		require.NotEqual(t, poolKey(module, "other"), poolKey(module, tenant))
+
+		m, tn := splitPoolKey(poolKey(module, tenant))
+		require.Equal(t, module, m)
+		require.Equal(t, tenant, tn)
+
+		m, tn = splitPoolKey(poolKey(module, ""))
+		require.Equal(t, module, m)
+		require.Empty(t, tn)
+	})
+	t.Run("compiled modules are cached per tenant", func(t *testing.T) {
+
+		const (
+			cacheDir = "/var/tmp/b7s/modules"
+			module   = "/function/module.wasm"
+		)
+
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, module, make([]byte, 10), defaultPermissions))
+
+		cache, err := newModuleCache(mocks.NoopLogger, fs, metrics.Default(), cacheDir, 0)
+		require.NoError(t, err)
+
+		shared, _, _, err := cache.entry(module, "")
+		require.NoError(t, err)
+
+		key, dir, hit, err := cache.entry(module, tenant)
+		require.NoError(t, err)
+		require.False(t, hit)
+		require.NotEqual(t, shared, key)
+		require.Equal(t, filepath.Join(cacheDir, key), dir)
+
+		_, _, hit, err = cache.entry(module, tenant)
+		require.NoError(t, err)
+		require.True(t, hit)
+
+		other, _, hit, err := cache.entry(module, "other")
+		require.NoError(t, err)
+		require.False(t, hit)
+		require.NotEqual(t, key, other)
+	})
+	t.Run("invalid tenant is rejected", func(t *testing.T) {
+		executor := Executor{
+			log: mocks.NoopLogger,
+			cfg: defaultConfig,
+		}
+
+		req := mocks.GenericExecutionRequest
+		req.Config.Tenant = "../other"
+
+		_, err := executor.executeFunction(context.Background(), mocks.GenericUUID.String(), req, nil)
+		require.ErrorIs(t, err, errInvalidTenant)
+	})
+}
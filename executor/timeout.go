@@ -0,0 +1,32 @@
+package executor
+
+import (
+	"errors"
+	"time"
+
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+// How long to wait for the output of a killed process to be flushed.
+const killWaitDelay = time.Second
+
+var errExecutionTimeout = errors.New("execution timed out")
+
+// executionTimeout returns how long the execution is allowed to run. Zero means there is no limit.
+func executionTimeout(req execute.Request) time.Duration {
+	return time.Duration(req.Config.Timeout) * time.Second
+}
+
+// killAfter calls kill once the timeout expires. The returned function stops the timer and reports whether kill was called.
+// With no timeout set, kill is never called.
+func killAfter(timeout time.Duration, kill func()) func() bool {
+
+	if timeout <= 0 {
+		return func() bool { return false }
+	}
+
+	timer := time.AfterFunc(timeout, kill)
+	return func() bool {
+		return !timer.Stop()
+	}
+}
@@ -0,0 +1,87 @@
+package executor
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestExecutor_Timeout(t *testing.T) {
+
+	const (
+		timeout = 200 * time.Millisecond
+	)
+
+	executor := Executor{
+		log: mocks.NoopLogger,
+		cfg: Config{Limiter: &noopLimiter{}},
+	}
+
+	t.Run("process running past timeout is killed", func(t *testing.T) {
+
+		cmd := exec.Command("sh", "-c", "echo partial; sleep 10; echo done")
+
+		start := time.Now()
+		out, _, err := executor.executeCommand(context.Background(), mocks.GenericUUID.String(), cmd, nil, timeout)
+		require.ErrorIs(t, err, errExecutionTimeout)
+		require.Less(t, time.Since(start), 5*time.Second)
+
+		require.Equal(t, "partial\n", out.Stdout)
+		require.Equal(t, -1, out.ExitCode)
+	})
+	t.Run("process completing in time is not killed", func(t *testing.T) {
+
+		cmd := exec.Command("sh", "-c", "echo done")
+
+		out, _, err := executor.executeCommand(context.Background(), mocks.GenericUUID.String(), cmd, nil, time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, "done\n", out.Stdout)
+	})
+	t.Run("process of an aborted execution is killed", func(t *testing.T) {
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(timeout, cancel)
+
+		cmd := exec.CommandContext(ctx, "sh", "-c", "echo partial; sleep 10; echo done")
+
+		start := time.Now()
+		out, _, err := executor.executeCommand(ctx, mocks.GenericUUID.String(), cmd, nil, time.Minute)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Less(t, time.Since(start), 5*time.Second)
+
+		require.Equal(t, "partial\n", out.Stdout)
+	})
+	t.Run("killed pooled process is not reused", func(t *testing.T) {
+
+		const (
+			module = "/var/tmp/b7s/module.wasm"
+		)
+
+		pool := newProcessPool(mocks.NoopLogger, ReusePolicy{MaxInvocations: 10}, WarmPolicy{}, startFakeProcess(0))
+		defer pool.shutdown()
+
+		proc, err := pool.acquire(module)
+		require.NoError(t, err)
+
+		expired := killAfter(timeout, func() {
+			_ = proc.cmd.Process.Kill()
+		})
+
+		// Invocation without the trailing newline is never answered by the fake process.
+		_, err = proc.stdin.Write([]byte("{}"))
+		require.NoError(t, err)
+
+		var res invocationResult
+		err = proc.dec.Decode(&res)
+		require.Error(t, err)
+		require.True(t, expired())
+
+		pool.retire(proc, recycleTimeout)
+		require.Empty(t, pool.idle[module])
+	})
+}
@@ -0,0 +1,161 @@
+package executor
+
+import (
+	"time"
+)
+
+const (
+	// How often are modules checked for the idle timeout.
+	warmPoolEvictionInterval = 10 * time.Second
+)
+
+// WarmPolicy describes how many runtime processes are started ahead of executions, for frequently used modules.
+// A module gets warm processes once it is executed, and keeps them until it is evicted.
+type WarmPolicy struct {
+	Size        uint          // Number of idle processes kept ready for each module. Zero disables the warm pool.
+	Modules     uint          // Maximum number of modules with warm processes. Least recently used modules are evicted first. Zero means no limit.
+	IdleTimeout time.Duration // Modules not executed for this long are evicted. Zero means no limit.
+}
+
+func (p WarmPolicy) enabled() bool {
+	return p.Size > 0
+}
+
+// touch records that the module was used and evicts modules according to the warm policy.
+// Processes of evicted modules are returned and should be stopped by the caller. Must be called with the lock held.
+func (p *processPool) touch(module string, now time.Time) []*pooledProcess {
+
+	if !p.warm.enabled() {
+		return nil
+	}
+
+	p.used[module] = now
+
+	evicted := p.evictStale(now)
+	for p.warm.Modules > 0 && uint(len(p.used)) > p.warm.Modules {
+
+		var (
+			oldest string
+			last   time.Time
+		)
+		for m, used := range p.used {
+			if oldest == "" || used.Before(last) {
+				oldest, last = m, used
+			}
+		}
+
+		evicted = append(evicted, p.evict(oldest)...)
+	}
+
+	return evicted
+}
+
+// evictStale evicts modules not used within the idle timeout, returning their idle processes. Must be called with the lock held.
+func (p *processPool) evictStale(now time.Time) []*pooledProcess {
+
+	if p.warm.IdleTimeout <= 0 {
+		return nil
+	}
+
+	var evicted []*pooledProcess
+	for module, last := range p.used {
+		if now.Sub(last) >= p.warm.IdleTimeout {
+			evicted = append(evicted, p.evict(module)...)
+		}
+	}
+
+	return evicted
+}
+
+// evict removes the module from the warm pool, returning its idle processes. Must be called with the lock held.
+func (p *processPool) evict(module string) []*pooledProcess {
+
+	procs := p.idle[module]
+
+	delete(p.idle, module)
+	delete(p.used, module)
+
+	p.log.Debug().Str("module", module).Int("processes", len(procs)).Msg("module evicted from warm pool")
+
+	return procs
+}
+
+// replenish starts processes in the background until the module has as many idle processes as the warm policy asks for.
+func (p *processPool) replenish(module string) {
+
+	p.Lock()
+	defer p.Unlock()
+
+	if !p.warm.enabled() || p.closed || p.used[module].IsZero() {
+		return
+	}
+
+	have := uint(len(p.idle[module])) + p.starting[module]
+	if have >= p.warm.Size {
+		return
+	}
+
+	missing := p.warm.Size - have
+	p.starting[module] += missing
+
+	p.wg.Add(int(missing))
+	for i := uint(0); i < missing; i++ {
+		go p.startWarm(module)
+	}
+}
+
+// startWarm starts a process for the module and adds it to the idle processes.
+func (p *processPool) startWarm(module string) {
+	defer p.wg.Done()
+
+	proc, err := p.start(module)
+
+	p.Lock()
+	p.starting[module]--
+	if p.starting[module] == 0 {
+		delete(p.starting, module)
+	}
+
+	if err != nil {
+		p.Unlock()
+		p.log.Warn().Err(err).Str("module", module).Msg("could not start warm runtime process")
+		return
+	}
+
+	proc.warm = true
+
+	// Module might have been evicted while the process was starting.
+	keep := !p.closed && !p.used[module].IsZero()
+	if keep {
+		p.idle[module] = append(p.idle[module], proc)
+	}
+	p.Unlock()
+
+	if !keep {
+		p.retire(proc, recycleEvicted)
+	}
+}
+
+// evictIdle periodically evicts modules that were not executed within the idle timeout, until the pool is shut down.
+func (p *processPool) evictIdle() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(min(warmPoolEvictionInterval, p.warm.IdleTimeout))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+
+		case now := <-ticker.C:
+			p.Lock()
+			evicted := p.evictStale(now)
+			p.Unlock()
+
+			for _, proc := range evicted {
+				p.retire(proc, recycleEvicted)
+			}
+		}
+	}
+}
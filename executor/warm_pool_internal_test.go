@@ -0,0 +1,111 @@
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestProcessPool_Warm(t *testing.T) {
+
+	const (
+		module      = "/var/tmp/b7s/module.wasm"
+		otherModule = "/var/tmp/b7s/other-module.wasm"
+
+		waitTimeout = 5 * time.Second
+		waitTick    = 10 * time.Millisecond
+	)
+
+	idle := func(pool *processPool, module string) int {
+		pool.Lock()
+		defer pool.Unlock()
+
+		return len(pool.idle[module])
+	}
+
+	execute := func(t *testing.T, pool *processPool, module string) *pooledProcess {
+		t.Helper()
+
+		proc, err := pool.acquire(module)
+		require.NoError(t, err)
+
+		_, err = proc.invoke(invocation{Args: []string{"--fuel", "1"}})
+		require.NoError(t, err)
+
+		return proc
+	}
+
+	t.Run("processes are started ahead of executions", func(t *testing.T) {
+
+		pool := newProcessPool(mocks.NoopLogger, ReusePolicy{}, WarmPolicy{Size: 2}, startFakeProcess(0))
+		defer pool.shutdown()
+
+		// First execution has to start the process.
+		proc := execute(t, pool, module)
+		decision := pool.release(module, proc, invocationResult{})
+		require.False(t, decision.Warm)
+		require.True(t, decision.Recycled)
+
+		require.Eventually(t, func() bool { return idle(pool, module) == 2 }, waitTimeout, waitTick)
+
+		proc = execute(t, pool, module)
+		decision = pool.release(module, proc, invocationResult{})
+		require.True(t, decision.Warm)
+		require.False(t, decision.Reused)
+
+		// Process used for the execution is replaced.
+		require.Eventually(t, func() bool { return idle(pool, module) == 2 }, waitTimeout, waitTick)
+	})
+	t.Run("least recently used modules are evicted", func(t *testing.T) {
+
+		pool := newProcessPool(mocks.NoopLogger, ReusePolicy{}, WarmPolicy{Size: 1, Modules: 1}, startFakeProcess(0))
+		defer pool.shutdown()
+
+		proc := execute(t, pool, module)
+		pool.release(module, proc, invocationResult{})
+		require.Eventually(t, func() bool { return idle(pool, module) == 1 }, waitTimeout, waitTick)
+
+		proc = execute(t, pool, otherModule)
+		pool.release(otherModule, proc, invocationResult{})
+		require.Eventually(t, func() bool { return idle(pool, otherModule) == 1 }, waitTimeout, waitTick)
+
+		require.Zero(t, idle(pool, module))
+
+		pool.Lock()
+		defer pool.Unlock()
+		require.NotContains(t, pool.used, module)
+	})
+	t.Run("modules are evicted after idle timeout", func(t *testing.T) {
+
+		pool := newProcessPool(mocks.NoopLogger, ReusePolicy{}, WarmPolicy{Size: 1, IdleTimeout: time.Hour}, startFakeProcess(0))
+		defer pool.shutdown()
+
+		proc := execute(t, pool, module)
+		pool.release(module, proc, invocationResult{})
+		require.Eventually(t, func() bool { return idle(pool, module) == 1 }, waitTimeout, waitTick)
+
+		pool.Lock()
+		pool.used[module] = time.Now().Add(-2 * time.Hour)
+		pool.Unlock()
+
+		proc = execute(t, pool, otherModule)
+		pool.release(otherModule, proc, invocationResult{})
+
+		require.Zero(t, idle(pool, module))
+	})
+	t.Run("processes are kept for reuse", func(t *testing.T) {
+
+		pool := newProcessPool(mocks.NoopLogger, ReusePolicy{MaxInvocations: 10}, WarmPolicy{Size: 1}, startFakeProcess(0))
+		defer pool.shutdown()
+
+		proc := execute(t, pool, module)
+		decision := pool.release(module, proc, invocationResult{})
+		require.False(t, decision.Recycled)
+
+		// Released process and the process started for the warm pool.
+		require.Eventually(t, func() bool { return idle(pool, module) == 2 }, waitTimeout, waitTick)
+	})
+}
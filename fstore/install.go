@@ -9,6 +9,7 @@ import (
 
 	"go.opentelemetry.io/otel/trace"
 
+	"github.com/blocklessnetwork/b7s/b7serrors"
 	"github.com/blocklessnetwork/b7s/models/blockless"
 	"github.com/blocklessnetwork/b7s/telemetry/b7ssemconv"
 )
@@ -51,6 +52,23 @@ func (f *FStore) Install(ctx context.Context, address string, cid string) (retEr
 		}
 	}
 
+	// Functions packaged as container images might have no archive to download - the image is pulled when the function runs.
+	if manifest.Container != nil && manifest.Deployment.URI == "" {
+		fn := blockless.FunctionRecord{
+			CID:      cid,
+			URL:      address,
+			Manifest: manifest,
+		}
+		err = f.saveFunction(ctx, fn)
+		if err != nil {
+			return fmt.Errorf("could not save function record: %w", err)
+		}
+
+		f.log.Debug().Str("cid", cid).Str("image", manifest.Container.Image).Msg("installed container function")
+
+		return nil
+	}
+
 	// Download the function identified by the manifest.
 	functionPath, err := f.download(ctx, cid, manifest)
 	if err != nil {
@@ -97,7 +115,7 @@ func (f *FStore) Install(ctx context.Context, address string, cid string) (retEr
 func (f *FStore) IsInstalled(cid string) (bool, error) {
 
 	fn, err := f.getFunction(context.Background(), cid)
-	if err != nil && errors.Is(err, blockless.ErrNotFound) {
+	if err != nil && errors.Is(err, b7serrors.ErrNotFound) {
 		return false, nil
 	}
 	if err != nil {
@@ -15,6 +15,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/blocklessnetwork/b7s/b7serrors"
 	"github.com/blocklessnetwork/b7s/fstore"
 	"github.com/blocklessnetwork/b7s/models/blockless"
 	"github.com/blocklessnetwork/b7s/store"
@@ -82,6 +83,21 @@ func TestFunction_Install(t *testing.T) {
 
 		require.True(t, ok, "function installation info incorrect")
 	})
+	t.Run("function uninstall works", func(t *testing.T) {
+
+		function, err := fh.Get(ctx, testCID)
+		require.NoError(t, err)
+
+		err = fh.Uninstall(ctx, testCID)
+		require.NoError(t, err)
+
+		require.NoFileExists(t, filepath.Join(workdir, function.Archive))
+		require.NoDirExists(t, filepath.Join(workdir, function.Files))
+
+		installed, err := fh.IsInstalled(testCID)
+		require.NoError(t, err)
+		require.False(t, installed)
+	})
 	t.Run("function reported as not installed if files are missing", func(t *testing.T) {
 
 		err = os.RemoveAll(workdir)
@@ -94,6 +110,50 @@ func TestFunction_Install(t *testing.T) {
 	})
 }
 
+func TestFunction_InstallContainer(t *testing.T) {
+
+	const (
+		manifestURL = "manifest.json"
+		testCID     = "dummy-cid"
+	)
+	ctx := context.Background()
+
+	workdir := t.TempDir()
+
+	manifest := blockless.FunctionManifest{
+		Container: &blockless.Container{
+			Image: "registry.example.com/function@sha256:abcd",
+		},
+	}
+
+	msrv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			payload, err := json.Marshal(manifest)
+			require.NoError(t, err)
+			w.Write(payload)
+		}))
+	defer msrv.Close()
+
+	fh := fstore.New(mocks.NoopLogger, newInMemoryStore(t), workdir)
+
+	address := fmt.Sprintf("%s/%v", msrv.URL, manifestURL)
+	err := fh.Install(ctx, address, testCID)
+	require.NoError(t, err)
+
+	installed, err := fh.IsInstalled(testCID)
+	require.NoError(t, err)
+	require.True(t, installed)
+
+	function, err := fh.Get(ctx, testCID)
+	require.NoError(t, err)
+	require.Equal(t, manifest.Container, function.Manifest.Container)
+
+	// Uninstalling a function without files leaves the workdir alone.
+	err = fh.Uninstall(ctx, testCID)
+	require.NoError(t, err)
+	require.DirExists(t, workdir)
+}
+
 func TestFunction_InstallHandlesErrors(t *testing.T) {
 
 	const (
@@ -202,7 +262,7 @@ func TestFunction_InstalledHandlesError(t *testing.T) {
 
 		store := mocks.BaselineStore(t)
 		store.RetrieveFunctionFunc = func(context.Context, string) (blockless.FunctionRecord, error) {
-			return blockless.FunctionRecord{}, blockless.ErrNotFound
+			return blockless.FunctionRecord{}, b7serrors.ErrNotFound
 		}
 
 		fh := fstore.New(mocks.NoopLogger, store, workdir)
@@ -19,6 +19,22 @@ func (f *FStore) Get(ctx context.Context, cid string) (blockless.FunctionRecord,
 	return fn, nil
 }
 
+// Installed returns the CIDs of installed functions.
+func (f *FStore) Installed(ctx context.Context) ([]string, error) {
+
+	functions, err := f.store.RetrieveFunctions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve functions: %w", err)
+	}
+
+	cids := make([]string, 0, len(functions))
+	for _, fn := range functions {
+		cids = append(cids, fn.CID)
+	}
+
+	return cids, nil
+}
+
 func (f *FStore) getFunction(ctx context.Context, cid string) (blockless.FunctionRecord, error) {
 
 	function, err := f.store.RetrieveFunction(ctx, cid)
@@ -0,0 +1,38 @@
+package fstore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Uninstall removes the function files and the function record.
+func (f *FStore) Uninstall(ctx context.Context, cid string) error {
+
+	fn, err := f.store.RetrieveFunction(ctx, cid)
+	if err != nil {
+		return fmt.Errorf("could not retrieve function record: %w", err)
+	}
+
+	for _, path := range []string{fn.Archive, fn.Files} {
+		// Functions without files (e.g. container functions) have no paths set - do not remove the entire workdir.
+		if filepath.Clean(path) == "." {
+			continue
+		}
+
+		err = os.RemoveAll(filepath.Join(f.workdir, path))
+		if err != nil {
+			return fmt.Errorf("could not remove function files (path: %s): %w", path, err)
+		}
+	}
+
+	err = f.store.RemoveFunction(ctx, cid)
+	if err != nil {
+		return fmt.Errorf("could not remove function record: %w", err)
+	}
+
+	f.log.Debug().Str("cid", cid).Msg("uninstalled function")
+
+	return nil
+}
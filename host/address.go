@@ -0,0 +1,123 @@
+package host
+
+import (
+	"errors"
+	"fmt"
+
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+// listenAddresses returns the list of multiaddresses the host should listen on, based on the enabled transports.
+func listenAddresses(address string, port uint, cfg Config) ([]string, error) {
+
+	var addresses []string
+
+	if address != "" {
+
+		protocol, _, err := determineAddressProtocol(address)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse address (address: %s): %w", address, err)
+		}
+
+		if protocol != "ip4" && protocol != "ip6" {
+			return nil, fmt.Errorf("host can only listen on IPv4 or IPv6 addresses (address: %s)", address)
+		}
+
+		if cfg.TCP {
+			addresses = append(addresses, fmt.Sprintf("/%v/%v/tcp/%v", protocol, address, port))
+		}
+
+		if cfg.Websocket {
+
+			// If the TCP and websocket port are explicitly chosen and set to the same value, one of the two listens will silently fail.
+			if cfg.TCP && port == cfg.WebsocketPort && cfg.WebsocketPort != 0 {
+				return nil, fmt.Errorf("TCP and websocket ports cannot be the same (TCP: %v, Websocket: %v)", port, cfg.WebsocketPort)
+			}
+
+			addresses = append(addresses, fmt.Sprintf("/%v/%v/tcp/%v/ws", protocol, address, cfg.WebsocketPort))
+		}
+
+		// NOTE: QUIC and WebTransport can share the same UDP port.
+		if cfg.QUIC {
+			addresses = append(addresses, fmt.Sprintf("/%v/%v/udp/%v/quic-v1", protocol, address, cfg.QUICPort))
+		}
+
+		if cfg.WebTransport {
+			addresses = append(addresses, fmt.Sprintf("/%v/%v/udp/%v/quic-v1/webtransport", protocol, address, cfg.WebTransportPort))
+		}
+	}
+
+	// Add any explicitly requested listen addresses, e.g. an IPv6 address next to an IPv4 one.
+	for _, addr := range cfg.ListenAddresses {
+
+		_, err := ma.NewMultiaddr(addr)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse listen multiaddress (address: %s): %w", addr, err)
+		}
+
+		addresses = append(addresses, addr)
+	}
+
+	if len(addresses) == 0 {
+		return nil, errors.New("no listen addresses configured")
+	}
+
+	return addresses, nil
+}
+
+// externalAddresses returns the list of multiaddresses the host should advertise. Announce addresses take precedence
+// over dialback configuration. If nothing is configured, an empty list is returned and the host advertises the addresses it detects.
+func externalAddresses(cfg Config) ([]ma.Multiaddr, error) {
+
+	if len(cfg.AnnounceAddresses) > 0 {
+
+		addrs := make([]ma.Multiaddr, 0, len(cfg.AnnounceAddresses))
+		for _, addr := range cfg.AnnounceAddresses {
+			maddr, err := ma.NewMultiaddr(addr)
+			if err != nil {
+				return nil, fmt.Errorf("could not parse announce multiaddress (address: %s): %w", addr, err)
+			}
+
+			addrs = append(addrs, maddr)
+		}
+
+		return addrs, nil
+	}
+
+	if cfg.DialBackAddress == "" || cfg.DialBackPort == 0 {
+		return nil, nil
+	}
+
+	protocol, dialbackAddress, err := determineAddressProtocol(cfg.DialBackAddress)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse dialback multiaddress (address: %s): %w", cfg.DialBackAddress, err)
+	}
+
+	externalAddr := fmt.Sprintf("/%v/%v/tcp/%v", protocol, dialbackAddress, cfg.DialBackPort)
+	extAddresses := []string{
+		externalAddr,
+	}
+
+	if cfg.Websocket && cfg.DialBackWebsocketPort != 0 {
+
+		if cfg.DialBackWebsocketPort == cfg.DialBackPort {
+			return nil, fmt.Errorf("TCP and websocket dialback ports cannot be the same (TCP: %v, Websocket: %v)", cfg.DialBackPort, cfg.DialBackWebsocketPort)
+		}
+
+		externalWsAddr := fmt.Sprintf("/%v/%v/tcp/%v/ws", protocol, dialbackAddress, cfg.WebsocketPort)
+		extAddresses = append(extAddresses, externalWsAddr)
+	}
+
+	// Create list of multiaddrs with the external IP and port.
+	var externalAddrs []ma.Multiaddr
+	for _, addr := range extAddresses {
+		maddr, err := ma.NewMultiaddr(addr)
+		if err != nil {
+			return nil, fmt.Errorf("could not create external multiaddress: %w", err)
+		}
+
+		externalAddrs = append(externalAddrs, maddr)
+	}
+
+	return externalAddrs, nil
+}
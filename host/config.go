@@ -3,6 +3,8 @@ package host
 import (
 	"time"
 
+	"github.com/libp2p/go-libp2p/core/connmgr"
+	"github.com/libp2p/go-libp2p/core/crypto"
 	"github.com/multiformats/go-multiaddr"
 
 	"github.com/blocklessnetwork/b7s/models/blockless"
@@ -14,15 +16,18 @@ var defaultConfig = Config{
 	ConnectionThreshold:                20,
 	DialBackPeersLimit:                 100,
 	DiscoveryInterval:                  10 * time.Second,
+	TCP:                                true,
 	Websocket:                          false,
 	BootNodesReachabilityCheckInterval: 1 * time.Minute,
 	MustReachBootNodes:                 defaultMustReachBootNodes,
 	EnableP2PRelay:                     false,
+	KeepaliveInterval:                  DefaultKeepaliveInterval,
 }
 
 // Config represents the Host configuration.
 type Config struct {
 	PrivateKey string
+	Identity   crypto.PrivKey
 
 	ConnectionThreshold uint
 	BootNodes           []multiaddr.Multiaddr
@@ -31,8 +36,21 @@ type Config struct {
 	DiscoveryInterval   time.Duration
 	ConnectionLimit     uint
 
-	Websocket     bool
-	WebsocketPort uint
+	TCP              bool
+	Websocket        bool
+	WebsocketPort    uint
+	QUIC             bool
+	QUICPort         uint
+	WebTransport     bool
+	WebTransportPort uint
+
+	ListenAddresses   []string
+	AnnounceAddresses []string
+
+	// Browser-based peers (WebSocket, WebTransport) often have idle connections dropped by browsers and proxies.
+	KeepaliveInterval time.Duration
+	MaxMessageSize    uint
+	GossipMeshSize    uint
 
 	DialBackAddress       string
 	DialBackPort          uint
@@ -42,6 +60,8 @@ type Config struct {
 	MustReachBootNodes                 bool
 	DisableResourceLimits              bool
 	EnableP2PRelay                     bool
+
+	ConnectionGater connmgr.ConnectionGater
 }
 
 // WithPrivateKey specifies the private key for the Host.
@@ -51,6 +71,14 @@ func WithPrivateKey(filepath string) func(*Config) {
 	}
 }
 
+// WithIdentity specifies the private key for the Host. It takes precedence over the private key file.
+// It can be used to create multiple hosts with the same identity.
+func WithIdentity(key crypto.PrivKey) func(*Config) {
+	return func(cfg *Config) {
+		cfg.Identity = key
+	}
+}
+
 // WithConnectionThreshold specifies how many connections should the host wait for on peer discovery.
 func WithConnectionThreshold(n uint) func(*Config) {
 	return func(cfg *Config) {
@@ -118,6 +146,87 @@ func WithWebsocketPort(port uint) func(*Config) {
 	}
 }
 
+// WithTCP specifies whether libp2p host should listen for TCP connections.
+func WithTCP(b bool) func(*Config) {
+	return func(cfg *Config) {
+		cfg.TCP = b
+	}
+}
+
+// WithQUIC specifies whether libp2p host should use QUIC protocol.
+func WithQUIC(b bool) func(*Config) {
+	return func(cfg *Config) {
+		cfg.QUIC = b
+	}
+}
+
+// WithQUICPort specifies on which UDP port the host should listen for QUIC connections.
+func WithQUICPort(port uint) func(*Config) {
+	return func(cfg *Config) {
+		cfg.QUICPort = port
+	}
+}
+
+// WithWebTransport specifies whether libp2p host should use WebTransport protocol.
+func WithWebTransport(b bool) func(*Config) {
+	return func(cfg *Config) {
+		cfg.WebTransport = b
+	}
+}
+
+// WithWebTransportPort specifies on which UDP port the host should listen for WebTransport connections.
+func WithWebTransportPort(port uint) func(*Config) {
+	return func(cfg *Config) {
+		cfg.WebTransportPort = port
+	}
+}
+
+// WithListenAddresses specifies additional multiaddresses the host should listen on.
+func WithListenAddresses(addrs []string) func(*Config) {
+	return func(cfg *Config) {
+		cfg.ListenAddresses = addrs
+	}
+}
+
+// WithAnnounceAddresses specifies multiaddresses the host will advertise, instead of the ones it detects.
+// Announce addresses take precedence over the dialback address and ports.
+func WithAnnounceAddresses(addrs []string) func(*Config) {
+	return func(cfg *Config) {
+		cfg.AnnounceAddresses = addrs
+	}
+}
+
+// WithKeepaliveInterval specifies how often the host pings peers connected using browser transports (WebSocket, WebTransport).
+// Zero disables keepalive pings.
+func WithKeepaliveInterval(d time.Duration) func(*Config) {
+	return func(cfg *Config) {
+		cfg.KeepaliveInterval = d
+	}
+}
+
+// WithMaxMessageSize specifies the maximum size of published and direct messages, in bytes.
+// Browser-based peers typically cannot handle messages as large as native peers can.
+func WithMaxMessageSize(n uint) func(*Config) {
+	return func(cfg *Config) {
+		cfg.MaxMessageSize = n
+	}
+}
+
+// WithConnectionGater specifies the connection gater deciding which inbound and outbound connections are permitted.
+func WithConnectionGater(gater connmgr.ConnectionGater) func(*Config) {
+	return func(cfg *Config) {
+		cfg.ConnectionGater = gater
+	}
+}
+
+// WithGossipMeshSize specifies the number of peers the host exchanges full messages with on each topic.
+// Smaller mesh means less traffic for constrained devices, at the cost of slower message propagation.
+func WithGossipMeshSize(n uint) func(*Config) {
+	return func(cfg *Config) {
+		cfg.GossipMeshSize = n
+	}
+}
+
 // WithMustReachBootNodes specifies if we should treat failure to reach boot nodes as a halting error.
 func WithMustReachBootNodes(b bool) func(*Config) {
 	return func(cfg *Config) {
@@ -0,0 +1,79 @@
+package gater
+
+// Config describes the connection policy. Connections matching any of the deny rules are refused. If allow rules are set,
+// connections must also match one of them. For example, allowing a country and denying an ASN admits all peers from
+// that country, except those in the given ASN.
+type Config struct {
+	AllowedNetworks  []string // CIDR ranges, e.g. `10.0.0.0/8`.
+	DeniedNetworks   []string
+	AllowedASNs      []string // AS numbers, e.g. `AS13335` or `13335`.
+	DeniedASNs       []string
+	AllowedCountries []string // ISO 3166-1 alpha-2 country codes, e.g. `DE`.
+	DeniedCountries  []string
+
+	// AllowRelayed permits relayed connections, if the address of the relay is permitted. Since the address of the
+	// peer behind the relay is not known, relayed connections are otherwise refused.
+	AllowRelayed bool
+
+	// Database is used to determine ASN and country of the peer IP address. It is required for ASN and country rules.
+	Database *Database
+}
+
+// Option can be used to set Gater configuration options.
+type Option func(*Config)
+
+// WithAllowedNetworks sets the CIDR ranges peers are allowed to connect from.
+func WithAllowedNetworks(cidrs []string) Option {
+	return func(cfg *Config) {
+		cfg.AllowedNetworks = cidrs
+	}
+}
+
+// WithDeniedNetworks sets the CIDR ranges peers are not allowed to connect from.
+func WithDeniedNetworks(cidrs []string) Option {
+	return func(cfg *Config) {
+		cfg.DeniedNetworks = cidrs
+	}
+}
+
+// WithAllowedASNs sets the autonomous systems peers are allowed to connect from.
+func WithAllowedASNs(asns []string) Option {
+	return func(cfg *Config) {
+		cfg.AllowedASNs = asns
+	}
+}
+
+// WithDeniedASNs sets the autonomous systems peers are not allowed to connect from.
+func WithDeniedASNs(asns []string) Option {
+	return func(cfg *Config) {
+		cfg.DeniedASNs = asns
+	}
+}
+
+// WithAllowedCountries sets the countries peers are allowed to connect from.
+func WithAllowedCountries(countries []string) Option {
+	return func(cfg *Config) {
+		cfg.AllowedCountries = countries
+	}
+}
+
+// WithDeniedCountries sets the countries peers are not allowed to connect from.
+func WithDeniedCountries(countries []string) Option {
+	return func(cfg *Config) {
+		cfg.DeniedCountries = countries
+	}
+}
+
+// WithRelayedConnections sets whether relayed connections are permitted, if the address of the relay is permitted.
+func WithRelayedConnections(allow bool) Option {
+	return func(cfg *Config) {
+		cfg.AllowRelayed = allow
+	}
+}
+
+// WithDatabase sets the database used to determine the ASN and country of the peer IP address.
+func WithDatabase(db *Database) Option {
+	return func(cfg *Config) {
+		cfg.Database = db
+	}
+}
@@ -0,0 +1,158 @@
+package gater
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Record describes the IP address range an address belongs to.
+type Record struct {
+	ASN     uint32
+	Country string
+}
+
+// ipRange is a contiguous range of IP addresses, with both ends inclusive.
+type ipRange struct {
+	first netip.Addr
+	last  netip.Addr
+	Record
+}
+
+// Database maps IP addresses to their ASN and country. It is loaded from an offline database in the IP to ASN format
+// (https://iptoasn.com), e.g. `ip2asn-combined.tsv`. Each line holds the range start, range end, AS number, country code
+// and AS description, separated by tabs.
+type Database struct {
+	ranges []ipRange
+}
+
+// LoadDatabase reads the database from the given file. Files with the `.gz` extension are decompressed.
+func LoadDatabase(path string) (*Database, error) {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open database: %w", err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("could not decompress database: %w", err)
+		}
+		defer gz.Close()
+
+		r = gz
+	}
+
+	db, err := ParseDatabase(r)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse database: %w", err)
+	}
+
+	return db, nil
+}
+
+// ParseDatabase reads the database from the reader.
+func ParseDatabase(r io.Reader) (*Database, error) {
+
+	var ranges []ipRange
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Split(text, "\t")
+		if len(fields) < 4 {
+			return nil, fmt.Errorf("invalid record on line %v", line)
+		}
+
+		first, err := netip.ParseAddr(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid range start on line %v: %w", line, err)
+		}
+
+		last, err := netip.ParseAddr(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid range end on line %v: %w", line, err)
+		}
+
+		if first.BitLen() != last.BitLen() || last.Less(first) {
+			return nil, fmt.Errorf("invalid range on line %v", line)
+		}
+
+		asn, err := strconv.ParseUint(fields[2], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid AS number on line %v: %w", line, err)
+		}
+
+		// AS number zero marks ranges that are not routed.
+		if asn == 0 {
+			continue
+		}
+
+		rng := ipRange{
+			first: first.Unmap(),
+			last:  last.Unmap(),
+			Record: Record{
+				ASN:     uint32(asn),
+				Country: strings.ToUpper(fields[3]),
+			},
+		}
+
+		ranges = append(ranges, rng)
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("could not read database: %w", err)
+	}
+
+	slices.SortFunc(ranges, func(a, b ipRange) int {
+		return a.first.Compare(b.first)
+	})
+
+	db := Database{
+		ranges: ranges,
+	}
+
+	return &db, nil
+}
+
+// Lookup returns the record for the IP address range the address belongs to.
+func (d *Database) Lookup(addr netip.Addr) (Record, bool) {
+
+	addr = addr.Unmap()
+
+	// Find the last range starting at or before the address.
+	n, found := slices.BinarySearchFunc(d.ranges, addr, func(r ipRange, addr netip.Addr) int {
+		return r.first.Compare(addr)
+	})
+	if !found {
+		n--
+	}
+
+	if n < 0 {
+		return Record{}, false
+	}
+
+	rng := d.ranges[n]
+	if addr.BitLen() != rng.first.BitLen() || rng.last.Less(addr) {
+		return Record{}, false
+	}
+
+	return rng.Record, true
+}
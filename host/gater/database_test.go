@@ -0,0 +1,77 @@
+package gater_test
+
+import (
+	"compress/gzip"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host/gater"
+)
+
+const testDatabase = `1.0.0.0	1.0.0.255	13335	US	CLOUDFLARENET
+1.0.4.0	1.0.7.255	38803	AU	GTELECOM-AUSTRALIA
+2.0.0.0	2.255.255.255	3215	FR	Orange
+5.0.0.0	5.0.255.255	0	None	Not routed
+2001:db8::	2001:db8:ffff:ffff:ffff:ffff:ffff:ffff	64496	de	Documentation
+`
+
+func TestDatabase(t *testing.T) {
+
+	db, err := gater.ParseDatabase(strings.NewReader(testDatabase))
+	require.NoError(t, err)
+
+	tests := []struct {
+		addr     string
+		found    bool
+		expected gater.Record
+	}{
+		{addr: "1.0.0.1", found: true, expected: gater.Record{ASN: 13335, Country: "US"}},
+		{addr: "1.0.0.255", found: true, expected: gater.Record{ASN: 13335, Country: "US"}},
+		{addr: "1.0.5.10", found: true, expected: gater.Record{ASN: 38803, Country: "AU"}},
+		{addr: "::ffff:2.1.2.3", found: true, expected: gater.Record{ASN: 3215, Country: "FR"}},
+		{addr: "2001:db8::1", found: true, expected: gater.Record{ASN: 64496, Country: "DE"}},
+		{addr: "1.0.1.0", found: false},
+		{addr: "0.0.0.1", found: false},
+		{addr: "5.0.0.1", found: false},
+		{addr: "9.9.9.9", found: false},
+		{addr: "2001:db9::1", found: false},
+	}
+
+	for _, test := range tests {
+		record, found := db.Lookup(netip.MustParseAddr(test.addr))
+		require.Equal(t, test.found, found, test.addr)
+		require.Equal(t, test.expected, record, test.addr)
+	}
+
+	t.Run("compressed database", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ip2asn-combined.tsv.gz")
+
+		f, err := os.Create(path)
+		require.NoError(t, err)
+
+		gz := gzip.NewWriter(f)
+		_, err = gz.Write([]byte(testDatabase))
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+		require.NoError(t, f.Close())
+
+		db, err := gater.LoadDatabase(path)
+		require.NoError(t, err)
+
+		record, found := db.Lookup(netip.MustParseAddr("2.2.2.2"))
+		require.True(t, found)
+		require.Equal(t, uint32(3215), record.ASN)
+	})
+	t.Run("invalid database", func(t *testing.T) {
+		_, err := gater.ParseDatabase(strings.NewReader("1.0.0.0\t1.0.0.255\tAS13335\tUS\n"))
+		require.Error(t, err)
+
+		_, err = gater.ParseDatabase(strings.NewReader("1.0.0.255\t1.0.0.0\t13335\tUS\n"))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,232 @@
+// Package gater provides the libp2p connection gater, allowing or denying connections based on the IP address of the peer.
+// Rules can be set for CIDR ranges and, using an offline database, for autonomous systems and countries.
+package gater
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/armon/go-metrics"
+	"github.com/libp2p/go-libp2p/core/connmgr"
+	"github.com/libp2p/go-libp2p/core/control"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
+	"github.com/rs/zerolog"
+)
+
+var _ connmgr.ConnectionGater = (*Gater)(nil)
+
+// Gater refuses inbound and outbound connections that are not permitted by the policy.
+type Gater struct {
+	log     zerolog.Logger
+	metrics *metrics.Metrics
+
+	allow   rules
+	deny    rules
+	relayed bool
+
+	db *Database
+}
+
+// rules are the parsed rules of the policy.
+type rules struct {
+	networks  []netip.Prefix
+	asns      []uint32
+	countries []string
+}
+
+func (r rules) empty() bool {
+	return len(r.networks) == 0 && len(r.asns) == 0 && len(r.countries) == 0
+}
+
+// New creates a new connection gater.
+func New(log zerolog.Logger, options ...Option) (*Gater, error) {
+
+	var cfg Config
+	for _, option := range options {
+		option(&cfg)
+	}
+
+	allow, err := parseRules(cfg.AllowedNetworks, cfg.AllowedASNs, cfg.AllowedCountries)
+	if err != nil {
+		return nil, fmt.Errorf("invalid allow rules: %w", err)
+	}
+
+	deny, err := parseRules(cfg.DeniedNetworks, cfg.DeniedASNs, cfg.DeniedCountries)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deny rules: %w", err)
+	}
+
+	needDB := len(allow.asns) > 0 || len(allow.countries) > 0 || len(deny.asns) > 0 || len(deny.countries) > 0
+	if needDB && cfg.Database == nil {
+		return nil, errors.New("ASN and country rules require a database")
+	}
+
+	g := Gater{
+		log:     log.With().Str("component", "gater").Logger(),
+		metrics: metrics.Default(),
+		allow:   allow,
+		deny:    deny,
+		relayed: cfg.AllowRelayed,
+		db:      cfg.Database,
+	}
+
+	return &g, nil
+}
+
+func parseRules(networks []string, asns []string, countries []string) (rules, error) {
+
+	var out rules
+	for _, network := range networks {
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(network))
+		if err != nil {
+			return rules{}, fmt.Errorf("invalid CIDR range (%s): %w", network, err)
+		}
+
+		out.networks = append(out.networks, prefix.Masked())
+	}
+
+	for _, asn := range asns {
+		value := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(asn)), "AS")
+		n, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return rules{}, fmt.Errorf("invalid AS number (%s): %w", asn, err)
+		}
+
+		out.asns = append(out.asns, uint32(n))
+	}
+
+	for _, country := range countries {
+		code := strings.ToUpper(strings.TrimSpace(country))
+		if len(code) != 2 {
+			return rules{}, fmt.Errorf("invalid country code (%s)", country)
+		}
+
+		out.countries = append(out.countries, code)
+	}
+
+	return out, nil
+}
+
+// Permitted returns true if the connections with the given IP address are permitted by the policy.
+func (g *Gater) Permitted(addr netip.Addr) bool {
+
+	addr = addr.Unmap()
+
+	var (
+		record Record
+		found  bool
+	)
+	if g.db != nil {
+		record, found = g.db.Lookup(addr)
+	}
+
+	if g.deny.match(addr, record, found) {
+		return false
+	}
+
+	if g.allow.empty() {
+		return true
+	}
+
+	return g.allow.match(addr, record, found)
+}
+
+func (r rules) match(addr netip.Addr, record Record, found bool) bool {
+
+	for _, network := range r.networks {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+
+	if !found {
+		return false
+	}
+
+	return slices.Contains(r.asns, record.ASN) || slices.Contains(r.countries, record.Country)
+}
+
+// permittedAddress checks if the connection with the given multiaddress is permitted. If the policy has any rules,
+// connections the policy cannot be checked for are refused - addresses without an IP address component, and relayed
+// connections, for which only the address of the relay is known. Relayed connections can be permitted explicitly,
+// in which case the address of the relay must be permitted by the policy.
+func (g *Gater) permittedAddress(addr ma.Multiaddr, direction string) bool {
+
+	if g.allow.empty() && g.deny.empty() {
+		return true
+	}
+
+	check := addr
+	if relayed(addr) {
+		if !g.relayed {
+			return g.refuse(addr, direction, "relayed connection refused by policy")
+		}
+
+		check, _ = ma.SplitFunc(addr, func(c ma.Component) bool {
+			return c.Protocol().Code == ma.P_CIRCUIT
+		})
+	}
+
+	ip, err := manet.ToIP(check)
+	if err != nil {
+		return g.refuse(addr, direction, "connection without IP address refused by policy")
+	}
+
+	ipaddr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return g.refuse(addr, direction, "connection without IP address refused by policy")
+	}
+
+	if !g.Permitted(ipaddr) {
+		return g.refuse(addr, direction, "connection refused by policy")
+	}
+
+	return true
+}
+
+// refuse records the refused connection. It always returns false.
+func (g *Gater) refuse(addr ma.Multiaddr, direction string, msg string) bool {
+
+	g.log.Debug().Str("address", addr.String()).Str("direction", direction).Msg(msg)
+	g.metrics.IncrCounterWithLabels(connectionsGatedMetric, 1, []metrics.Label{{Name: "direction", Value: direction}})
+
+	return false
+}
+
+// relayed returns true if the address is a circuit relay address.
+func relayed(addr ma.Multiaddr) bool {
+	_, err := addr.ValueForProtocol(ma.P_CIRCUIT)
+	return err == nil
+}
+
+// InterceptPeerDial permits dialing all peers, since the addresses are checked before they are dialed.
+func (g *Gater) InterceptPeerDial(peer.ID) bool {
+	return true
+}
+
+// InterceptAddrDial checks if the address of the peer can be dialed.
+func (g *Gater) InterceptAddrDial(_ peer.ID, addr ma.Multiaddr) bool {
+	return g.permittedAddress(addr, directionOutbound)
+}
+
+// InterceptAccept checks if the inbound connection can be accepted.
+func (g *Gater) InterceptAccept(conn network.ConnMultiaddrs) bool {
+	return g.permittedAddress(conn.RemoteMultiaddr(), directionInbound)
+}
+
+// InterceptSecured permits all connections, since the address was already checked when the connection was accepted or dialed.
+func (g *Gater) InterceptSecured(network.Direction, peer.ID, network.ConnMultiaddrs) bool {
+	return true
+}
+
+// InterceptUpgraded permits all upgraded connections.
+func (g *Gater) InterceptUpgraded(network.Conn) (bool, control.DisconnectReason) {
+	return true, 0
+}
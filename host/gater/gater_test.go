@@ -0,0 +1,155 @@
+package gater_test
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/host/gater"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestGater_Permitted(t *testing.T) {
+
+	db, err := gater.ParseDatabase(strings.NewReader(testDatabase))
+	require.NoError(t, err)
+
+	t.Run("no rules", func(t *testing.T) {
+		g, err := gater.New(mocks.NoopLogger)
+		require.NoError(t, err)
+
+		require.True(t, g.Permitted(netip.MustParseAddr("1.2.3.4")))
+	})
+	t.Run("denied networks", func(t *testing.T) {
+		g, err := gater.New(mocks.NoopLogger, gater.WithDeniedNetworks([]string{"10.0.0.0/8", "2001:db8::/32"}))
+		require.NoError(t, err)
+
+		require.False(t, g.Permitted(netip.MustParseAddr("10.1.2.3")))
+		require.False(t, g.Permitted(netip.MustParseAddr("::ffff:10.1.2.3")))
+		require.False(t, g.Permitted(netip.MustParseAddr("2001:db8::1")))
+		require.True(t, g.Permitted(netip.MustParseAddr("11.1.2.3")))
+	})
+	t.Run("allowed networks", func(t *testing.T) {
+		g, err := gater.New(mocks.NoopLogger, gater.WithAllowedNetworks([]string{"10.0.0.0/8"}))
+		require.NoError(t, err)
+
+		require.True(t, g.Permitted(netip.MustParseAddr("10.1.2.3")))
+		require.False(t, g.Permitted(netip.MustParseAddr("11.1.2.3")))
+	})
+	t.Run("allowed countries with denied ASN", func(t *testing.T) {
+		g, err := gater.New(mocks.NoopLogger,
+			gater.WithDatabase(db),
+			gater.WithAllowedCountries([]string{"us", "FR"}),
+			gater.WithDeniedASNs([]string{"AS3215"}),
+		)
+		require.NoError(t, err)
+
+		require.True(t, g.Permitted(netip.MustParseAddr("1.0.0.1")))
+		require.False(t, g.Permitted(netip.MustParseAddr("2.1.1.1")))
+		require.False(t, g.Permitted(netip.MustParseAddr("1.0.5.1")))
+		// Addresses not found in the database do not match ASN or country rules.
+		require.False(t, g.Permitted(netip.MustParseAddr("9.9.9.9")))
+	})
+	t.Run("denied country", func(t *testing.T) {
+		g, err := gater.New(mocks.NoopLogger, gater.WithDatabase(db), gater.WithDeniedCountries([]string{"AU"}))
+		require.NoError(t, err)
+
+		require.False(t, g.Permitted(netip.MustParseAddr("1.0.5.1")))
+		require.True(t, g.Permitted(netip.MustParseAddr("1.0.0.1")))
+		require.True(t, g.Permitted(netip.MustParseAddr("9.9.9.9")))
+	})
+	t.Run("invalid rules", func(t *testing.T) {
+		_, err := gater.New(mocks.NoopLogger, gater.WithDeniedNetworks([]string{"10.0.0.0"}))
+		require.Error(t, err)
+
+		_, err = gater.New(mocks.NoopLogger, gater.WithDatabase(db), gater.WithDeniedASNs([]string{"ASX"}))
+		require.Error(t, err)
+
+		_, err = gater.New(mocks.NoopLogger, gater.WithDatabase(db), gater.WithAllowedCountries([]string{"USA"}))
+		require.Error(t, err)
+	})
+	t.Run("ASN rules require database", func(t *testing.T) {
+		_, err := gater.New(mocks.NoopLogger, gater.WithDeniedASNs([]string{"AS3215"}))
+		require.Error(t, err)
+	})
+}
+
+func TestGater_Addresses(t *testing.T) {
+
+	const (
+		relayID = "12D3KooWRmrYxMBUH8bNrQv3sANNZgk69JfkcefsK7ihPmGMvDm1"
+	)
+
+	var (
+		permitted = ma.StringCast("/ip4/10.0.0.1/tcp/9000")
+		denied    = ma.StringCast("/ip4/192.0.2.1/tcp/9000")
+		dns       = ma.StringCast("/dns4/example.com/tcp/9000")
+		relayed   = ma.StringCast("/ip4/10.0.0.1/tcp/9000/p2p/" + relayID + "/p2p-circuit")
+		untrusted = ma.StringCast("/ip4/192.0.2.1/tcp/9000/p2p/" + relayID + "/p2p-circuit")
+	)
+
+	t.Run("no rules", func(t *testing.T) {
+		g, err := gater.New(mocks.NoopLogger)
+		require.NoError(t, err)
+
+		for _, addr := range []ma.Multiaddr{permitted, denied, dns, relayed, untrusted} {
+			require.True(t, g.InterceptAddrDial("", addr), addr.String())
+		}
+	})
+	t.Run("addresses without IP address are refused", func(t *testing.T) {
+		g, err := gater.New(mocks.NoopLogger, gater.WithAllowedNetworks([]string{"10.0.0.0/8"}))
+		require.NoError(t, err)
+
+		require.True(t, g.InterceptAddrDial("", permitted))
+		require.False(t, g.InterceptAddrDial("", denied))
+		require.False(t, g.InterceptAddrDial("", dns))
+	})
+	t.Run("relayed connections are refused", func(t *testing.T) {
+		g, err := gater.New(mocks.NoopLogger, gater.WithAllowedNetworks([]string{"10.0.0.0/8"}))
+		require.NoError(t, err)
+
+		require.False(t, g.InterceptAddrDial("", relayed))
+		require.False(t, g.InterceptAddrDial("", untrusted))
+	})
+	t.Run("relayed connections through permitted relays", func(t *testing.T) {
+		g, err := gater.New(mocks.NoopLogger,
+			gater.WithAllowedNetworks([]string{"10.0.0.0/8"}),
+			gater.WithRelayedConnections(true),
+		)
+		require.NoError(t, err)
+
+		require.True(t, g.InterceptAddrDial("", relayed))
+		require.False(t, g.InterceptAddrDial("", untrusted))
+	})
+}
+
+func TestGater_Connections(t *testing.T) {
+
+	const loopback = "127.0.0.1"
+
+	deny, err := gater.New(mocks.NoopLogger, gater.WithDeniedNetworks([]string{"127.0.0.0/8"}))
+	require.NoError(t, err)
+
+	gated, err := host.New(mocks.NoopLogger, loopback, 0, host.WithConnectionGater(deny))
+	require.NoError(t, err)
+	defer gated.Close()
+
+	other, err := host.New(mocks.NoopLogger, loopback, 0)
+	require.NoError(t, err)
+	defer other.Close()
+
+	t.Run("outbound connection is refused", func(t *testing.T) {
+		err := gated.Connect(context.Background(), peer.AddrInfo{ID: other.ID(), Addrs: other.Addrs()})
+		require.Error(t, err)
+	})
+	t.Run("inbound connection is refused", func(t *testing.T) {
+		err := other.Connect(context.Background(), peer.AddrInfo{ID: gated.ID(), Addrs: gated.Addrs()})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,22 @@
+package gater
+
+import (
+	"github.com/armon/go-metrics/prometheus"
+)
+
+// Connection directions, used as metric labels.
+const (
+	directionInbound  = "inbound"
+	directionOutbound = "outbound"
+)
+
+var (
+	connectionsGatedMetric = []string{"host", "connections", "gated"}
+)
+
+var Counters = []prometheus.CounterDefinition{
+	{
+		Name: connectionsGatedMetric,
+		Help: "Number of connections refused by the connection gater.",
+	},
+}
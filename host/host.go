@@ -9,6 +9,7 @@ import (
 	"github.com/asaskevich/govalidator"
 	"github.com/libp2p/go-libp2p"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	coreconnmgr "github.com/libp2p/go-libp2p/core/connmgr"
 	"github.com/libp2p/go-libp2p/core/crypto"
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/peer"
@@ -38,20 +39,9 @@ func New(log zerolog.Logger, address string, port uint, options ...func(*Config)
 		option(&cfg)
 	}
 
-	hostAddress := fmt.Sprintf("/ip4/%v/tcp/%v", address, port)
-	addresses := []string{
-		hostAddress,
-	}
-
-	if cfg.Websocket {
-
-		// If the TCP and websocket port are explicitly chosen and set to the same value, one of the two listens will silently fail.
-		if port == cfg.WebsocketPort && cfg.WebsocketPort != 0 {
-			return nil, fmt.Errorf("TCP and websocket ports cannot be the same (TCP: %v, Websocket: %v)", port, cfg.WebsocketPort)
-		}
-
-		wsAddr := fmt.Sprintf("/ip4/%v/tcp/%v/ws", address, cfg.WebsocketPort)
-		addresses = append(addresses, wsAddr)
+	addresses, err := listenAddresses(address, port, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not determine listen addresses: %w", err)
 	}
 
 	opts := []libp2p.Option{
@@ -71,8 +61,14 @@ func New(log zerolog.Logger, address string, port uint, options ...func(*Config)
 		opts = append(opts, libp2p.ResourceManager(rcmgr))
 	}
 
-	// Read private key, if provided.
-	if cfg.PrivateKey != "" {
+	// Use the specified identity, or read private key, if provided.
+	if cfg.Identity != nil {
+		opts = append(opts, libp2p.Identity(cfg.Identity))
+
+		if !exportable(cfg.Identity) {
+			opts = append(opts, libp2p.QUICReuse(newEphemeralQUICConnManager))
+		}
+	} else if cfg.PrivateKey != "" {
 		key, err := readPrivateKey(cfg.PrivateKey)
 		if err != nil {
 			return nil, fmt.Errorf("could not read private key: %w", err)
@@ -81,38 +77,16 @@ func New(log zerolog.Logger, address string, port uint, options ...func(*Config)
 		opts = append(opts, libp2p.Identity(key))
 	}
 
-	if cfg.DialBackAddress != "" && cfg.DialBackPort != 0 {
-
-		protocol, dialbackAddress, err := determineAddressProtocol(cfg.DialBackAddress)
-		if err != nil {
-			return nil, fmt.Errorf("could not parse dialback multiaddress (address: %s): %w", cfg.DialBackAddress, err)
-		}
-
-		externalAddr := fmt.Sprintf("/%v/%v/tcp/%v", protocol, dialbackAddress, cfg.DialBackPort)
-		extAddresses := []string{
-			externalAddr,
-		}
-
-		if cfg.Websocket && cfg.DialBackWebsocketPort != 0 {
-
-			if cfg.DialBackWebsocketPort == cfg.DialBackPort {
-				return nil, fmt.Errorf("TCP and websocket dialback ports cannot be the same (TCP: %v, Websocket: %v)", cfg.DialBackPort, cfg.DialBackWebsocketPort)
-			}
-
-			externalWsAddr := fmt.Sprintf("/%v/%v/tcp/%v/ws", protocol, dialbackAddress, cfg.WebsocketPort)
-			extAddresses = append(extAddresses, externalWsAddr)
-		}
+	if cfg.ConnectionGater != nil {
+		opts = append(opts, libp2p.ConnectionGater(cfg.ConnectionGater))
+	}
 
-		// Create list of multiaddrs with the external IP and port.
-		var externalAddrs []ma.Multiaddr
-		for _, addr := range extAddresses {
-			maddr, err := ma.NewMultiaddr(addr)
-			if err != nil {
-				return nil, fmt.Errorf("could not create external multiaddress: %w", err)
-			}
+	externalAddrs, err := externalAddresses(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not determine external addresses: %w", err)
+	}
 
-			externalAddrs = append(externalAddrs, maddr)
-		}
+	if len(externalAddrs) > 0 {
 
 		addrFactory := libp2p.AddrsFactory(func(addrs []ma.Multiaddr) []ma.Multiaddr {
 			// Return only the external multiaddrs.
@@ -169,6 +143,11 @@ func New(log zerolog.Logger, address string, port uint, options ...func(*Config)
 	return &host, nil
 }
 
+// ConnectionGater returns the connection gater of the host, if any.
+func (h *Host) ConnectionGater() coreconnmgr.ConnectionGater {
+	return h.cfg.ConnectionGater
+}
+
 // PrivateKey returns the private key of the libp2p host.
 func (h *Host) PrivateKey() crypto.PrivKey {
 	return h.Peerstore().PrivKey(h.ID())
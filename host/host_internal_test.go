@@ -1,9 +1,19 @@
 package host
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
 )
 
 func TestDetermineAddressProtocol(t *testing.T) {
@@ -86,3 +96,232 @@ func TestDetermineAddressProtocol(t *testing.T) {
 		require.Equalf(t, test.protocol, protocol, "unexpected protocol for address: %s", test.address)
 	}
 }
+
+func TestListenAddresses(t *testing.T) {
+
+	const (
+		port = 9000
+	)
+
+	t.Run("default is TCP only", func(t *testing.T) {
+		t.Parallel()
+
+		addrs, err := listenAddresses("127.0.0.1", port, defaultConfig)
+		require.NoError(t, err)
+		require.Equal(t, []string{"/ip4/127.0.0.1/tcp/9000"}, addrs)
+	})
+	t.Run("IPv6 address with all transports", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := defaultConfig
+		cfg.Websocket = true
+		cfg.WebsocketPort = 9010
+		cfg.QUIC = true
+		cfg.QUICPort = 9020
+		cfg.WebTransport = true
+		cfg.WebTransportPort = 9020
+
+		addrs, err := listenAddresses("::1", port, cfg)
+		require.NoError(t, err)
+
+		expected := []string{
+			"/ip6/::1/tcp/9000",
+			"/ip6/::1/tcp/9010/ws",
+			"/ip6/::1/udp/9020/quic-v1",
+			"/ip6/::1/udp/9020/quic-v1/webtransport",
+		}
+		require.Equal(t, expected, addrs)
+	})
+	t.Run("additional listen addresses", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := defaultConfig
+		cfg.TCP = false
+		cfg.QUIC = true
+		cfg.ListenAddresses = []string{"/ip6/::/tcp/9000"}
+
+		addrs, err := listenAddresses("0.0.0.0", port, cfg)
+		require.NoError(t, err)
+		require.Equal(t, []string{"/ip4/0.0.0.0/udp/0/quic-v1", "/ip6/::/tcp/9000"}, addrs)
+	})
+	t.Run("handles invalid configuration", func(t *testing.T) {
+		t.Parallel()
+
+		// Listening on a DNS name is not supported.
+		_, err := listenAddresses("example.com", port, defaultConfig)
+		require.Error(t, err)
+
+		// Unparseable address is reported in the error.
+		_, err = listenAddresses("698.168.0.1", port, defaultConfig)
+		require.ErrorContains(t, err, "698.168.0.1")
+
+		// Same port for TCP and websocket.
+		cfg := defaultConfig
+		cfg.Websocket = true
+		cfg.WebsocketPort = port
+		_, err = listenAddresses("127.0.0.1", port, cfg)
+		require.Error(t, err)
+
+		// Invalid multiaddress.
+		cfg = defaultConfig
+		cfg.ListenAddresses = []string{"not-a-multiaddress"}
+		_, err = listenAddresses("127.0.0.1", port, cfg)
+		require.Error(t, err)
+
+		// No transports enabled.
+		cfg = defaultConfig
+		cfg.TCP = false
+		_, err = listenAddresses("127.0.0.1", port, cfg)
+		require.Error(t, err)
+	})
+}
+
+func TestExternalAddresses(t *testing.T) {
+
+	t.Run("no external addresses by default", func(t *testing.T) {
+		t.Parallel()
+
+		addrs, err := externalAddresses(defaultConfig)
+		require.NoError(t, err)
+		require.Empty(t, addrs)
+	})
+	t.Run("dialback address", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := defaultConfig
+		cfg.DialBackAddress = "10.10.10.10"
+		cfg.DialBackPort = 9000
+
+		addrs, err := externalAddresses(cfg)
+		require.NoError(t, err)
+		require.Len(t, addrs, 1)
+		require.Equal(t, "/ip4/10.10.10.10/tcp/9000", addrs[0].String())
+	})
+	t.Run("announce addresses take precedence", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := defaultConfig
+		cfg.DialBackAddress = "10.10.10.10"
+		cfg.DialBackPort = 9000
+		cfg.AnnounceAddresses = []string{"/ip6/2001:db8::1/tcp/9000", "/dns/example.com/udp/9020/quic-v1"}
+
+		addrs, err := externalAddresses(cfg)
+		require.NoError(t, err)
+		require.Len(t, addrs, 2)
+		require.Equal(t, "/ip6/2001:db8::1/tcp/9000", addrs[0].String())
+		require.Equal(t, "/dns/example.com/udp/9020/quic-v1", addrs[1].String())
+	})
+	t.Run("invalid announce address", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := defaultConfig
+		cfg.AnnounceAddresses = []string{"not-a-multiaddress"}
+
+		_, err := externalAddresses(cfg)
+		require.Error(t, err)
+	})
+}
+
+func TestIsBrowserAddress(t *testing.T) {
+
+	tests := []struct {
+		address string
+		browser bool
+	}{
+		{address: "/ip4/127.0.0.1/tcp/9000", browser: false},
+		{address: "/ip4/127.0.0.1/udp/9000/quic-v1", browser: false},
+		{address: "/ip4/127.0.0.1/tcp/9010/ws", browser: true},
+		{address: "/dns/example.com/tcp/443/wss", browser: true},
+		{address: "/ip6/::1/udp/9020/quic-v1/webtransport", browser: true},
+	}
+
+	for _, test := range tests {
+		addr, err := ma.NewMultiaddr(test.address)
+		require.NoError(t, err)
+		require.Equalf(t, test.browser, isBrowserAddress(addr), "unexpected result for address: %s", test.address)
+	}
+}
+
+func TestHost_MaxMessageSize(t *testing.T) {
+
+	const (
+		limit = 16
+	)
+
+	h, err := New(zerolog.Nop(), "127.0.0.1", 0, WithMaxMessageSize(limit))
+	require.NoError(t, err)
+	defer h.Close()
+
+	receiver, err := New(zerolog.Nop(), "127.0.0.1", 0)
+	require.NoError(t, err)
+	defer receiver.Close()
+
+	receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+		stream.Close()
+	})
+
+	h.Peerstore().AddAddrs(receiver.ID(), receiver.Addrs(), time.Minute)
+
+	err = h.SendMessage(context.Background(), receiver.ID(), make([]byte, limit+1))
+	require.Error(t, err)
+
+	err = h.SendMessage(context.Background(), receiver.ID(), make([]byte, limit))
+	require.NoError(t, err)
+}
+
+func TestHost_GossipSubParams(t *testing.T) {
+
+	for d := 1; d <= 12; d++ {
+		params := gossipSubParams(d)
+
+		require.Equal(t, d, params.D)
+		require.LessOrEqual(t, params.Dlo, params.D)
+		require.GreaterOrEqual(t, params.Dhi, params.D)
+		require.LessOrEqual(t, params.Dscore, params.Dhi)
+		require.Less(t, params.Dout, params.Dlo)
+		require.LessOrEqual(t, params.Dout, params.D/2)
+	}
+
+	h, err := New(zerolog.Nop(), "127.0.0.1", 0, WithGossipMeshSize(3))
+	require.NoError(t, err)
+	defer h.Close()
+
+	err = h.InitPubSub(context.Background())
+	require.NoError(t, err)
+}
+
+// unexportableKey is a private key that cannot be exported, like the keys kept in an HSM.
+type unexportableKey struct {
+	crypto.PrivKey
+}
+
+func (k unexportableKey) Raw() ([]byte, error) {
+	return nil, errors.New("key cannot be exported")
+}
+
+func TestHost_UnexportableIdentity(t *testing.T) {
+
+	key, _, err := crypto.GenerateKeyPair(crypto.ECDSA, 0)
+	require.NoError(t, err)
+
+	h, err := New(zerolog.Nop(), "127.0.0.1", 0, WithIdentity(unexportableKey{key}), WithQUIC(true))
+	require.NoError(t, err)
+	defer h.Close()
+
+	expected, err := peer.IDFromPrivateKey(key)
+	require.NoError(t, err)
+	require.Equal(t, expected, h.ID())
+
+	receiver, err := New(zerolog.Nop(), "127.0.0.1", 0)
+	require.NoError(t, err)
+	defer receiver.Close()
+
+	receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+		stream.Close()
+	})
+
+	h.Peerstore().AddAddrs(receiver.ID(), receiver.Addrs(), time.Minute)
+
+	err = h.SendMessage(context.Background(), receiver.ID(), []byte("hello"))
+	require.NoError(t, err)
+}
@@ -0,0 +1,84 @@
+package host
+
+import (
+	"context"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/p2p/protocol/ping"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+// KeepAlive will run a long running loop, pinging peers connected using browser transports until cancelled.
+// Browsers and proxies tend to drop idle WebSocket and WebTransport connections.
+func (h *Host) KeepAlive(ctx context.Context) {
+
+	if h.cfg.KeepaliveInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(h.cfg.KeepaliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+
+			for _, conn := range h.Network().Conns() {
+				if !isBrowserConnection(conn) {
+					continue
+				}
+
+				go h.ping(ctx, conn)
+			}
+
+		case <-ctx.Done():
+			h.log.Info().Msg("stopping keepalive pings")
+			return
+		}
+	}
+}
+
+func (h *Host) ping(ctx context.Context, conn network.Conn) {
+
+	ctx, cancel := context.WithTimeout(ctx, keepaliveTimeout)
+	defer cancel()
+
+	peer := conn.RemotePeer()
+
+	select {
+	case res := <-ping.Ping(ctx, h, peer):
+		if res.Error != nil {
+			h.metrics.IncrCounter(keepaliveFailuresMetric, 1)
+			h.log.Debug().Err(res.Error).Str("peer", peer.String()).Msg("keepalive ping failed")
+			return
+		}
+
+		h.log.Trace().Str("peer", peer.String()).Dur("rtt", res.RTT).Msg("keepalive ping")
+
+	case <-ctx.Done():
+		h.metrics.IncrCounter(keepaliveFailuresMetric, 1)
+		h.log.Debug().Str("peer", peer.String()).Msg("keepalive ping timed out")
+	}
+}
+
+// isBrowserConnection returns true if the connection uses a transport typically used by browser-based peers.
+func isBrowserConnection(conn network.Conn) bool {
+	return isBrowserAddress(conn.RemoteMultiaddr()) || isBrowserAddress(conn.LocalMultiaddr())
+}
+
+func isBrowserAddress(addr ma.Multiaddr) bool {
+
+	if addr == nil {
+		return false
+	}
+
+	for _, protocol := range addr.Protocols() {
+		switch protocol.Code {
+		case ma.P_WS, ma.P_WSS, ma.P_WEBTRANSPORT, ma.P_WEBRTC_DIRECT:
+			return true
+		}
+	}
+
+	return false
+}
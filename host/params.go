@@ -1,6 +1,8 @@
 package host
 
 import (
+	"time"
+
 	"github.com/armon/go-metrics/prometheus"
 )
 
@@ -9,6 +11,9 @@ const (
 	errNoGoodAddresses = "no good addresses"
 
 	defaultMustReachBootNodes = false
+
+	DefaultKeepaliveInterval = 15 * time.Second
+	keepaliveTimeout         = 10 * time.Second
 )
 
 var (
@@ -16,6 +21,7 @@ var (
 	messagesSentSizeMetric      = []string{"host", "messages", "sent", "bytes"}
 	messagesPublishedMetric     = []string{"host", "messages", "published"}
 	messagesPublishedSizeMetric = []string{"host", "messages", "published", "bytes"}
+	keepaliveFailuresMetric     = []string{"host", "keepalive", "failures"}
 )
 
 var Counters = []prometheus.CounterDefinition{
@@ -35,4 +41,8 @@ var Counters = []prometheus.CounterDefinition{
 		Name: messagesPublishedSizeMetric,
 		Help: "Total size of messages published, in bytes",
 	},
+	{
+		Name: keepaliveFailuresMetric,
+		Help: "Number of failed keepalive pings to peers connected using browser transports.",
+	},
 }
@@ -0,0 +1,40 @@
+package host
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/p2p/protocol/ping"
+)
+
+// Ping checks if the peer is reachable, and returns the round-trip time.
+func (h *Host) Ping(ctx context.Context, id peer.ID) (time.Duration, error) {
+
+	select {
+	case res := <-ping.Ping(ctx, h, id):
+		if res.Error != nil {
+			return 0, fmt.Errorf("ping failed: %w", res.Error)
+		}
+		return res.RTT, nil
+
+	case <-ctx.Done():
+		return 0, fmt.Errorf("ping timed out: %w", ctx.Err())
+	}
+}
+
+// BootNodes returns the IDs of the boot nodes the host was configured with.
+func (h *Host) BootNodes() []peer.ID {
+
+	ids := make([]peer.ID, 0, len(h.cfg.BootNodes))
+	for _, addr := range h.cfg.BootNodes {
+		info, err := peer.AddrInfoFromP2pAddr(addr)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, info.ID)
+	}
+
+	return ids
+}
@@ -0,0 +1,46 @@
+package host
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/p2p/transport/quicreuse"
+	"github.com/quic-go/quic-go"
+	"go.uber.org/fx"
+)
+
+// exportable returns true if the raw private key can be read. Keys kept in an HSM cannot be exported.
+func exportable(key crypto.PrivKey) bool {
+	_, err := key.Raw()
+	return err == nil
+}
+
+// newEphemeralQUICConnManager creates the QUIC connection manager with random stateless reset and token keys.
+// By default libp2p derives these keys from the raw private key, which is not possible for keys that cannot be exported.
+// Random keys only mean that stateless resets and address validation tokens do not carry over node restarts.
+func newEphemeralQUICConnManager(lifecycle fx.Lifecycle) (*quicreuse.ConnManager, error) {
+
+	var (
+		resetKey quic.StatelessResetKey
+		tokenKey quic.TokenGeneratorKey
+	)
+
+	_, err := rand.Read(resetKey[:])
+	if err != nil {
+		return nil, fmt.Errorf("could not generate stateless reset key: %w", err)
+	}
+
+	_, err = rand.Read(tokenKey[:])
+	if err != nil {
+		return nil, fmt.Errorf("could not generate token key: %w", err)
+	}
+
+	cm, err := quicreuse.NewConnManager(resetKey, tokenKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not create QUIC connection manager: %w", err)
+	}
+	lifecycle.Append(fx.StopHook(cm.Close))
+
+	return cm, nil
+}
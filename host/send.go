@@ -19,6 +19,10 @@ func (h *Host) SendMessage(ctx context.Context, to peer.ID, payload []byte) erro
 // SendMessageOnProtocol sends a message directly to the specified peer, using the specified protocol.
 func (h *Host) SendMessageOnProtocol(ctx context.Context, to peer.ID, payload []byte, protocol protocol.ID) error {
 
+	if h.cfg.MaxMessageSize > 0 && uint(len(payload)) > h.cfg.MaxMessageSize {
+		return fmt.Errorf("message too large (size: %v, limit: %v)", len(payload), h.cfg.MaxMessageSize)
+	}
+
 	h.metrics.IncrCounterWithLabels(messagesSentMetric, 1, []metrics.Label{{Name: "protocol", Value: string(protocol)}})
 	h.metrics.IncrCounterWithLabels(messagesSentSizeMetric, float32(len(payload)), []metrics.Label{{Name: "protocol", Value: string(protocol)}})
 
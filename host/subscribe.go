@@ -10,8 +10,16 @@ import (
 
 func (h *Host) InitPubSub(ctx context.Context) error {
 
+	var opts []pubsub.Option
+	if h.cfg.MaxMessageSize > 0 {
+		opts = append(opts, pubsub.WithMaxMessageSize(int(h.cfg.MaxMessageSize)))
+	}
+	if h.cfg.GossipMeshSize > 0 {
+		opts = append(opts, pubsub.WithGossipSubParams(gossipSubParams(int(h.cfg.GossipMeshSize))))
+	}
+
 	// Get a new PubSub object with the default router.
-	pubsub, err := pubsub.NewGossipSub(ctx, h)
+	pubsub, err := pubsub.NewGossipSub(ctx, h, opts...)
 	if err != nil {
 		return fmt.Errorf("could not create new gossipsub: %w", err)
 	}
@@ -52,3 +60,18 @@ func (h *Host) Subscribe(topic string) (*pubsub.Topic, *pubsub.Subscription, err
 
 	return th, subscription, nil
 }
+
+// gossipSubParams returns the gossipsub parameters for a mesh of the given size. Mesh bounds are scaled
+// so they keep the relations gossipsub requires - outbound peers below the lower bound and at most half the mesh size.
+func gossipSubParams(d int) pubsub.GossipSubParams {
+
+	params := pubsub.DefaultGossipSubParams()
+	params.D = d
+	params.Dlo = max(1, d*2/3)
+	params.Dhi = 2 * d
+	params.Dscore = min(params.Dscore, d*2/3)
+	params.Dout = min(params.Dout, d/2, params.Dlo-1)
+	params.Dlazy = min(params.Dlazy, d)
+
+	return params
+}
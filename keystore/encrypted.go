@@ -0,0 +1,189 @@
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	encryptedKeyVersion = 1
+	encryptedKeyKDF     = "scrypt"
+
+	// Recommended scrypt parameters for interactive logins - key derivation takes a fraction of a second.
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+	saltLength   = 32
+
+	// Limits for scrypt parameters read from the key file, so a crafted file cannot make key derivation take up
+	// unbounded memory or time. Scrypt uses 128 * N * R bytes of memory.
+	scryptMaxN      = 1 << 20
+	scryptMaxR      = 32
+	scryptMaxP      = 16
+	scryptMaxMemory = 1 << 30
+)
+
+var ErrInvalidPassphrase = errors.New("invalid passphrase")
+
+// encryptedKey is the format of the encrypted key file. The private key is encrypted using AES-256-GCM, with the key
+// derived from the passphrase using scrypt.
+type encryptedKey struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// EncryptedFile reads the private key from a file encrypted with a passphrase.
+type EncryptedFile struct {
+	path       string
+	passphrase string
+}
+
+// NewEncryptedFile creates a keystore reading the private key from the given file, encrypted with the passphrase.
+func NewEncryptedFile(path string, passphrase string) (*EncryptedFile, error) {
+
+	if passphrase == "" {
+		return nil, errors.New("passphrase is required")
+	}
+
+	f := EncryptedFile{
+		path:       path,
+		passphrase: passphrase,
+	}
+
+	return &f, nil
+}
+
+// PrivateKey reads and decrypts the private key.
+func (f *EncryptedFile) PrivateKey() (crypto.PrivKey, error) {
+
+	payload, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read file: %w", err)
+	}
+
+	key, err := Decrypt(payload, f.passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt private key: %w", err)
+	}
+
+	return key, nil
+}
+
+// Encrypt encrypts the private key with the passphrase, producing the contents of the encrypted key file.
+func Encrypt(key crypto.PrivKey, passphrase string) ([]byte, error) {
+
+	raw, err := crypto.MarshalPrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal private key: %w", err)
+	}
+
+	salt := make([]byte, saltLength)
+	_, err = rand.Read(salt)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate salt: %w", err)
+	}
+
+	out := encryptedKey{
+		Version: encryptedKeyVersion,
+		KDF:     encryptedKeyKDF,
+		N:       scryptN,
+		R:       scryptR,
+		P:       scryptP,
+		Salt:    salt,
+	}
+
+	aead, err := out.cipher(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	out.Nonce = make([]byte, aead.NonceSize())
+	_, err = rand.Read(out.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate nonce: %w", err)
+	}
+
+	out.Ciphertext = aead.Seal(nil, out.Nonce, raw, nil)
+
+	payload, err := json.Marshal(out)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode encrypted key: %w", err)
+	}
+
+	return payload, nil
+}
+
+// Decrypt decrypts the contents of the encrypted key file using the passphrase.
+func Decrypt(payload []byte, passphrase string) (crypto.PrivKey, error) {
+
+	var in encryptedKey
+	err := json.Unmarshal(payload, &in)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode encrypted key: %w", err)
+	}
+
+	if in.Version != encryptedKeyVersion || in.KDF != encryptedKeyKDF {
+		return nil, fmt.Errorf("unsupported encrypted key format (version: %v, kdf: %v)", in.Version, in.KDF)
+	}
+
+	if in.N < 2 || in.N > scryptMaxN || in.R < 1 || in.R > scryptMaxR || in.P < 1 || in.P > scryptMaxP || 128*in.N*in.R > scryptMaxMemory {
+		return nil, fmt.Errorf("unsupported scrypt parameters (n: %v, r: %v, p: %v)", in.N, in.R, in.P)
+	}
+
+	aead, err := in.cipher(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(in.Nonce) != aead.NonceSize() {
+		return nil, errors.New("invalid nonce")
+	}
+
+	raw, err := aead.Open(nil, in.Nonce, in.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrInvalidPassphrase
+	}
+
+	key, err := crypto.UnmarshalPrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal private key: %w", err)
+	}
+
+	return key, nil
+}
+
+// cipher returns the AES-GCM cipher using the key derived from the passphrase.
+func (k encryptedKey) cipher(passphrase string) (cipher.AEAD, error) {
+
+	derived, err := scrypt.Key([]byte(passphrase), k.Salt, k.N, k.R, k.P, scryptKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("could not derive encryption key: %w", err)
+	}
+
+	block, err := aes.NewCipher(derived)
+	if err != nil {
+		return nil, fmt.Errorf("could not create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("could not create cipher: %w", err)
+	}
+
+	return aead, nil
+}
@@ -0,0 +1,75 @@
+package keystore_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/keystore"
+)
+
+func TestEncryptedFile(t *testing.T) {
+
+	const passphrase = "correct horse battery staple"
+
+	key, _, err := crypto.GenerateKeyPair(crypto.Ed25519, 0)
+	require.NoError(t, err)
+
+	payload, err := keystore.Encrypt(key, passphrase)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "priv.enc")
+	err = os.WriteFile(path, payload, 0o600)
+	require.NoError(t, err)
+
+	t.Run("nominal case", func(t *testing.T) {
+		store, err := keystore.NewEncryptedFile(path, passphrase)
+		require.NoError(t, err)
+
+		loaded, err := store.PrivateKey()
+		require.NoError(t, err)
+		require.True(t, key.Equals(loaded))
+	})
+	t.Run("wrong passphrase", func(t *testing.T) {
+		store, err := keystore.NewEncryptedFile(path, "wrong passphrase")
+		require.NoError(t, err)
+
+		_, err = store.PrivateKey()
+		require.ErrorIs(t, err, keystore.ErrInvalidPassphrase)
+	})
+	t.Run("passphrase is required", func(t *testing.T) {
+		_, err := keystore.NewEncryptedFile(path, "")
+		require.Error(t, err)
+	})
+	t.Run("excessive scrypt parameters are rejected", func(t *testing.T) {
+		var encrypted map[string]any
+		err := json.Unmarshal(payload, &encrypted)
+		require.NoError(t, err)
+
+		encrypted["n"] = 1 << 30
+
+		crafted, err := json.Marshal(encrypted)
+		require.NoError(t, err)
+
+		_, err = keystore.Decrypt(crafted, passphrase)
+		require.ErrorContains(t, err, "unsupported scrypt parameters")
+	})
+	t.Run("plain key file is rejected", func(t *testing.T) {
+		raw, err := crypto.MarshalPrivateKey(key)
+		require.NoError(t, err)
+
+		plain := filepath.Join(t.TempDir(), "priv.bin")
+		err = os.WriteFile(plain, raw, 0o600)
+		require.NoError(t, err)
+
+		store, err := keystore.NewEncryptedFile(plain, passphrase)
+		require.NoError(t, err)
+
+		_, err = store.PrivateKey()
+		require.Error(t, err)
+	})
+}
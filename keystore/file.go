@@ -0,0 +1,36 @@
+package keystore
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+)
+
+// File reads the private key from an unencrypted file.
+type File struct {
+	path string
+}
+
+// NewFile creates a keystore reading the private key from the given file.
+func NewFile(path string) *File {
+	return &File{
+		path: path,
+	}
+}
+
+// PrivateKey reads the private key from the file.
+func (f *File) PrivateKey() (crypto.PrivKey, error) {
+
+	payload, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read file: %w", err)
+	}
+
+	key, err := crypto.UnmarshalPrivateKey(payload)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal private key: %w", err)
+	}
+
+	return key, nil
+}
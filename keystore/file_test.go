@@ -0,0 +1,35 @@
+package keystore_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/keystore"
+)
+
+func TestFile(t *testing.T) {
+
+	key, _, err := crypto.GenerateKeyPair(crypto.Ed25519, 0)
+	require.NoError(t, err)
+
+	raw, err := crypto.MarshalPrivateKey(key)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "priv.bin")
+	err = os.WriteFile(path, raw, 0o600)
+	require.NoError(t, err)
+
+	t.Run("nominal case", func(t *testing.T) {
+		loaded, err := keystore.NewFile(path).PrivateKey()
+		require.NoError(t, err)
+		require.True(t, key.Equals(loaded))
+	})
+	t.Run("missing file", func(t *testing.T) {
+		_, err := keystore.NewFile(filepath.Join(t.TempDir(), "missing")).PrivateKey()
+		require.Error(t, err)
+	})
+}
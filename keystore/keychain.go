@@ -0,0 +1,67 @@
+package keystore
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+)
+
+var ErrKeychainNotSupported = errors.New("OS keychain not supported on this platform")
+
+// Keychain reads the private key from the OS keychain - the macOS Keychain or the Secret Service (e.g. GNOME Keyring) on Linux.
+// The key is stored as a generic secret, identified by the service and account names, holding the base64 encoded private key.
+type Keychain struct {
+	service string
+	account string
+}
+
+// NewKeychain creates a keystore reading the private key from the OS keychain.
+func NewKeychain(service string, account string) (*Keychain, error) {
+
+	if service == "" || account == "" {
+		return nil, errors.New("keychain service and account are required")
+	}
+
+	k := Keychain{
+		service: service,
+		account: account,
+	}
+
+	return &k, nil
+}
+
+// PrivateKey reads the private key from the OS keychain.
+func (k *Keychain) PrivateKey() (crypto.PrivKey, error) {
+
+	name, args, err := keychainLookup(k.service, k.account)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(name, args...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not read secret from keychain (stderr: %s): %w", strings.TrimSpace(stderr.String()), err)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(out)))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode secret: %w", err)
+	}
+
+	key, err := crypto.UnmarshalPrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal private key: %w", err)
+	}
+
+	return key, nil
+}
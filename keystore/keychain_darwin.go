@@ -0,0 +1,9 @@
+//go:build darwin
+// +build darwin
+
+package keystore
+
+// keychainLookup returns the command printing the secret from the OS keychain.
+func keychainLookup(service string, account string) (string, []string, error) {
+	return "security", []string{"find-generic-password", "-s", service, "-a", account, "-w"}, nil
+}
@@ -0,0 +1,9 @@
+//go:build linux
+// +build linux
+
+package keystore
+
+// keychainLookup returns the command printing the secret from the OS keychain.
+func keychainLookup(service string, account string) (string, []string, error) {
+	return "secret-tool", []string{"lookup", "service", service, "account", account}, nil
+}
@@ -0,0 +1,67 @@
+//go:build linux
+// +build linux
+
+package keystore_test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/keystore"
+)
+
+func TestKeychain(t *testing.T) {
+
+	const (
+		service = "b7s"
+		account = "worker-1"
+	)
+
+	key, _, err := crypto.GenerateKeyPair(crypto.Ed25519, 0)
+	require.NoError(t, err)
+
+	raw, err := crypto.MarshalPrivateKey(key)
+	require.NoError(t, err)
+
+	// Fake secret-tool, returning the secret only for the expected service and account.
+	dir := t.TempDir()
+	script := fmt.Sprintf(`#!/bin/sh
+if [ "$*" = "lookup service %s account %s" ]; then
+	echo "%s"
+	exit 0
+fi
+echo "no such secret" >&2
+exit 1
+`, service, account, base64.StdEncoding.EncodeToString(raw))
+
+	err = os.WriteFile(filepath.Join(dir, "secret-tool"), []byte(script), 0o755)
+	require.NoError(t, err)
+
+	t.Setenv("PATH", dir)
+
+	t.Run("nominal case", func(t *testing.T) {
+		store, err := keystore.NewKeychain(service, account)
+		require.NoError(t, err)
+
+		loaded, err := store.PrivateKey()
+		require.NoError(t, err)
+		require.True(t, key.Equals(loaded))
+	})
+	t.Run("missing secret", func(t *testing.T) {
+		store, err := keystore.NewKeychain(service, "worker-2")
+		require.NoError(t, err)
+
+		_, err = store.PrivateKey()
+		require.Error(t, err)
+	})
+	t.Run("service and account are required", func(t *testing.T) {
+		_, err := keystore.NewKeychain(service, "")
+		require.Error(t, err)
+	})
+}
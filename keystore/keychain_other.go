@@ -0,0 +1,8 @@
+//go:build !darwin && !linux
+// +build !darwin,!linux
+
+package keystore
+
+func keychainLookup(string, string) (string, []string, error) {
+	return "", nil, ErrKeychainNotSupported
+}
@@ -0,0 +1,21 @@
+// Package keystore provides backends for the private key that forms the libp2p identity of the node. Keys can be kept
+// in a plain file, in a file encrypted with a passphrase, in the OS keychain, or in an HSM, so production nodes do not
+// have to keep raw keys on disk.
+package keystore
+
+import (
+	"github.com/libp2p/go-libp2p/core/crypto"
+)
+
+// Supported keystore types.
+const (
+	TypeFile          = "file"
+	TypeEncryptedFile = "encrypted-file"
+	TypeKeychain      = "keychain"
+	TypePKCS11        = "pkcs11"
+)
+
+// Keystore provides the private key of the node.
+type Keystore interface {
+	PrivateKey() (crypto.PrivKey, error)
+}
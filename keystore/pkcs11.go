@@ -0,0 +1,166 @@
+package keystore
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/crypto/pb"
+)
+
+// DefaultPKCS11Tool is the OpenSC tool used to access the HSM.
+const DefaultPKCS11Tool = "pkcs11-tool"
+
+// pinEnvName is the environment variable the PIN is passed to the tool in, so that it does not show up in the process list.
+const pinEnvName = "B7S_PKCS11_PIN"
+
+// PKCS11 uses an ECDSA key kept in an HSM, accessed via the PKCS#11 module of the device. The private key never leaves the
+// HSM - signing is done by the device, using the OpenSC `pkcs11-tool`. Since each signature spawns a process, message
+// signing is slower than with keys kept in memory.
+type PKCS11 struct {
+	tool   string
+	module string
+	keyID  string
+	pin    string
+}
+
+// NewPKCS11 creates a keystore for the key with the given ID (hex encoded), kept in the HSM accessed via the PKCS#11 module.
+func NewPKCS11(tool string, module string, keyID string, pin string) (*PKCS11, error) {
+
+	if module == "" || keyID == "" {
+		return nil, errors.New("PKCS#11 module and key ID are required")
+	}
+
+	if tool == "" {
+		tool = DefaultPKCS11Tool
+	}
+
+	p := PKCS11{
+		tool:   tool,
+		module: module,
+		keyID:  keyID,
+		pin:    pin,
+	}
+
+	return &p, nil
+}
+
+// PrivateKey returns the handle to the private key kept in the HSM.
+func (p *PKCS11) PrivateKey() (crypto.PrivKey, error) {
+
+	der, err := p.run(nil, "--read-object", "--type", "pubkey", "--id", p.keyID)
+	if err != nil {
+		return nil, fmt.Errorf("could not read public key: %w", err)
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse public key: %w", err)
+	}
+
+	ecpub, ok := parsed.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type (%T), only ECDSA keys are supported", parsed)
+	}
+
+	pub, err := crypto.ECDSAPublicKeyFromPubKey(*ecpub)
+	if err != nil {
+		return nil, fmt.Errorf("could not convert public key: %w", err)
+	}
+
+	key := &hsmKey{
+		store: p,
+		pub:   pub,
+	}
+
+	// Verify that the HSM can sign using the key before it is used for the node identity.
+	probe := []byte("b7s keystore probe")
+	sig, err := key.Sign(probe)
+	if err != nil {
+		return nil, fmt.Errorf("could not sign using the key: %w", err)
+	}
+
+	ok, err = pub.Verify(probe, sig)
+	if err != nil || !ok {
+		return nil, errors.New("signature made by the HSM does not match the public key")
+	}
+
+	return key, nil
+}
+
+func (p *PKCS11) sign(data []byte) ([]byte, error) {
+
+	args := []string{"--sign", "--mechanism", "ECDSA", "--signature-format", "openssl", "--id", p.keyID}
+
+	// libp2p ECDSA signatures are made over the SHA-256 hash of the data.
+	digest := sha256.Sum256(data)
+
+	sig, err := p.run(digest[:], args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not sign data: %w", err)
+	}
+
+	return sig, nil
+}
+
+func (p *PKCS11) run(stdin []byte, args ...string) ([]byte, error) {
+
+	args = append([]string{"--module", p.module}, args...)
+	if p.pin != "" {
+		args = append(args, "--login", "--pin", "env:"+pinEnvName)
+	}
+
+	cmd := exec.Command(p.tool, args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	if p.pin != "" {
+		cmd.Env = append(os.Environ(), pinEnvName+"="+p.pin)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s failed (stderr: %s): %w", p.tool, strings.TrimSpace(stderr.String()), err)
+	}
+
+	return out, nil
+}
+
+// hsmKey is the private key kept in the HSM.
+type hsmKey struct {
+	store *PKCS11
+	pub   crypto.PubKey
+}
+
+func (k *hsmKey) Type() pb.KeyType {
+	return pb.KeyType_ECDSA
+}
+
+// Raw returns an error since the key cannot be exported from the HSM.
+func (k *hsmKey) Raw() ([]byte, error) {
+	return nil, errors.New("private key kept in HSM cannot be exported")
+}
+
+func (k *hsmKey) Equals(other crypto.Key) bool {
+	o, ok := other.(*hsmKey)
+	if !ok {
+		return false
+	}
+	return k.pub.Equals(o.pub)
+}
+
+func (k *hsmKey) Sign(data []byte) ([]byte, error) {
+	return k.store.sign(data)
+}
+
+func (k *hsmKey) GetPublic() crypto.PubKey {
+	return k.pub
+}
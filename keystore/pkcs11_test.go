@@ -0,0 +1,100 @@
+package keystore_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/keystore"
+)
+
+func TestPKCS11(t *testing.T) {
+
+	if runtime.GOOS == "windows" {
+		t.Skip("fake pkcs11-tool is a shell script")
+	}
+
+	openssl, err := exec.LookPath("openssl")
+	if err != nil {
+		t.Skip("openssl not found")
+	}
+
+	const keyID = "01"
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+
+	der, err := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	require.NoError(t, err)
+	pubPath := filepath.Join(dir, "pub.der")
+	err = os.WriteFile(pubPath, der, 0o600)
+	require.NoError(t, err)
+
+	privDER, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+	privPath := filepath.Join(dir, "priv.pem")
+	err = os.WriteFile(privPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: privDER}), 0o600)
+	require.NoError(t, err)
+
+	// Fake pkcs11-tool, emulating the HSM with openssl. Raw ECDSA signing of the digest read from stdin.
+	// PIN must not be passed as an argument.
+	script := fmt.Sprintf(`#!/bin/sh
+case "$*" in
+*1234*)
+	exit 2
+	;;
+esac
+[ "$B7S_PKCS11_PIN" = "1234" ] || exit 3
+case "$*" in
+*--read-object*)
+	cat %s
+	;;
+*--sign*)
+	%s pkeyutl -sign -inkey %s
+	;;
+*)
+	exit 1
+	;;
+esac
+`, pubPath, openssl, privPath)
+
+	tool := filepath.Join(dir, "pkcs11-tool")
+	err = os.WriteFile(tool, []byte(script), 0o755)
+	require.NoError(t, err)
+
+	store, err := keystore.NewPKCS11(tool, "/usr/lib/softhsm/libsofthsm2.so", keyID, "1234")
+	require.NoError(t, err)
+
+	key, err := store.PrivateKey()
+	require.NoError(t, err)
+
+	require.Equal(t, crypto.ECDSA, int(key.Type()))
+
+	expected, err := crypto.ECDSAPublicKeyFromPubKey(ecKey.PublicKey)
+	require.NoError(t, err)
+	require.True(t, expected.Equals(key.GetPublic()))
+
+	data := []byte("message to sign")
+	sig, err := key.Sign(data)
+	require.NoError(t, err)
+
+	ok, err := key.GetPublic().Verify(data, sig)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, err = key.Raw()
+	require.Error(t, err)
+}
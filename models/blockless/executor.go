@@ -2,6 +2,7 @@ package blockless
 
 import (
 	"context"
+	"io"
 
 	"github.com/blocklessnetwork/b7s/models/execute"
 )
@@ -9,3 +10,8 @@ import (
 type Executor interface {
 	ExecuteFunction(ctx context.Context, requestID string, request execute.Request) (execute.Result, error)
 }
+
+// InteractiveExecutor can run functions with their standard input and output attached to a live stream.
+type InteractiveExecutor interface {
+	ExecuteInteractive(ctx context.Context, requestID string, request execute.Request, stdin io.Reader, stdout io.Writer) (execute.Result, error)
+}
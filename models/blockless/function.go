@@ -1,5 +1,9 @@
 package blockless
 
+import (
+	"encoding/json"
+)
+
 // FunctionManifest describes some important configuration options for a Blockless function.
 type FunctionManifest struct {
 	ID          string        `json:"id,omitempty"`
@@ -18,6 +22,32 @@ type FunctionManifest struct {
 	DriversRootPath string `json:"drivers_root_path,omitempty"`
 	LimitedFuel     uint   `json:"limited_fuel,omitempty"`
 	LimitedMemory   uint   `json:"limited_memory,omitempty"`
+
+	// OutputSchema is an optional JSON Schema that the function output (stdout) should conform to. Schemas that do not declare
+	// their dialect using `$schema` are treated as draft 2020-12. Only references within the schema are resolved.
+	OutputSchema json.RawMessage `json:"output_schema,omitempty"`
+	// Normalization describes how the function output is normalized before results from different nodes are compared.
+	Normalization *Normalization `json:"normalization,omitempty"`
+	// Container is set for functions packaged as OCI container images, run by workers with a container executor.
+	Container *Container `json:"container,omitempty"`
+}
+
+// Container describes the OCI container image a function runs as, instead of a WASM module.
+type Container struct {
+	Image   string   `json:"image"`             // Image reference, pinned by digest (`image@sha256:...`) so all workers run the same code.
+	Command []string `json:"command,omitempty"` // Command run in the container. If empty, the image default is used.
+}
+
+// Normalization describes rules applied to the function output so that semantically identical outputs are equal.
+type Normalization struct {
+	// TrimWhitespace removes leading and trailing whitespace from each line of the output, as well as empty trailing lines.
+	TrimWhitespace bool `json:"trim_whitespace,omitempty"`
+	// StripPatterns is a list of regular expressions whose matches are removed from the output (e.g. timestamps).
+	StripPatterns []string `json:"strip_patterns,omitempty"`
+	// IgnoreFields is a list of fields removed from JSON output, with nested fields separated by dots.
+	IgnoreFields []string `json:"ignore_fields,omitempty"`
+	// IgnoreStderr discards the standard error output.
+	IgnoreStderr bool `json:"ignore_stderr,omitempty"`
 }
 
 // Runtime is here to support legacy manifests.
@@ -0,0 +1,107 @@
+package blockless
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// MembershipCredential attests that a worker is a member of a restricted subgroup.
+// Credentials are issued by the subgroup owner and signed with the owner's key.
+type MembershipCredential struct {
+	Subgroup  string    `json:"subgroup"`
+	Member    peer.ID   `json:"member"`
+	Owner     peer.ID   `json:"owner"`
+	Expires   time.Time `json:"expires,omitempty"` // Zero value means the credential does not expire.
+	Signature string    `json:"signature,omitempty"`
+}
+
+// Sign signs the credential with the key of the subgroup owner.
+func (c *MembershipCredential) Sign(key crypto.PrivKey) error {
+
+	owner, err := peer.IDFromPrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("could not determine peer ID: %w", err)
+	}
+
+	c.Owner = owner
+
+	payload, err := c.payload()
+	if err != nil {
+		return err
+	}
+
+	sig, err := key.Sign(payload)
+	if err != nil {
+		return fmt.Errorf("could not sign credential: %w", err)
+	}
+
+	c.Signature = hex.EncodeToString(sig)
+	return nil
+}
+
+// Verify checks that the credential admits the member to the subgroup, at the given point in time,
+// and that it was issued by the given subgroup owner.
+func (c MembershipCredential) Verify(owner peer.ID, subgroup string, member peer.ID, now time.Time) error {
+
+	if c.Subgroup != subgroup {
+		return fmt.Errorf("credential issued for a different subgroup (subgroup: %s)", c.Subgroup)
+	}
+
+	if c.Member != member {
+		return fmt.Errorf("credential issued for a different peer (member: %s)", c.Member)
+	}
+
+	if c.Owner != owner {
+		return fmt.Errorf("credential issued by a different owner (owner: %s)", c.Owner)
+	}
+
+	if !c.Expires.IsZero() && now.After(c.Expires) {
+		return fmt.Errorf("credential expired (expires: %s)", c.Expires)
+	}
+
+	key, err := owner.ExtractPublicKey()
+	if err != nil {
+		return fmt.Errorf("could not extract public key of the owner: %w", err)
+	}
+
+	payload, err := c.payload()
+	if err != nil {
+		return err
+	}
+
+	sig, err := hex.DecodeString(c.Signature)
+	if err != nil {
+		return fmt.Errorf("could not decode signature from hex: %w", err)
+	}
+
+	ok, err := key.Verify(payload, sig)
+	if err != nil {
+		return fmt.Errorf("could not verify signature: %w", err)
+	}
+
+	if !ok {
+		return errors.New("invalid signature")
+	}
+
+	return nil
+}
+
+// payload returns the byte representation of the credential, excluding the signature.
+func (c MembershipCredential) payload() ([]byte, error) {
+
+	cp := c
+	cp.Signature = ""
+
+	payload, err := json.Marshal(cp)
+	if err != nil {
+		return nil, fmt.Errorf("could not get byte representation of the credential: %w", err)
+	}
+
+	return payload, nil
+}
@@ -10,16 +10,61 @@ type Message interface {
 
 // Message types in the Blockless protocol.
 const (
-	MessageHealthCheck             = "MsgHealthCheck"
-	MessageInstallFunction         = "MsgInstallFunction"
-	MessageInstallFunctionResponse = "MsgInstallFunctionResponse"
-	MessageRollCall                = "MsgRollCall"
-	MessageRollCallResponse        = "MsgRollCallResponse"
-	MessageExecute                 = "MsgExecute"
-	MessageExecuteResponse         = "MsgExecuteResponse"
-	MessageFormCluster             = "MsgFormCluster"
-	MessageFormClusterResponse     = "MsgFormClusterResponse"
-	MessageDisbandCluster          = "MsgDisbandCluster"
+	MessageHealthCheck                = "MsgHealthCheck"
+	MessageInstallFunction            = "MsgInstallFunction"
+	MessageInstallFunctionResponse    = "MsgInstallFunctionResponse"
+	MessageRollCall                   = "MsgRollCall"
+	MessageRollCallResponse           = "MsgRollCallResponse"
+	MessageExecute                    = "MsgExecute"
+	MessageExecuteResponse            = "MsgExecuteResponse"
+	MessageExecutePartialResponse     = "MsgExecutePartialResponse"
+	MessageFormCluster                = "MsgFormCluster"
+	MessageFormClusterResponse        = "MsgFormClusterResponse"
+	MessageDisbandCluster             = "MsgDisbandCluster"
+	MessageDisbandClusterResponse     = "MsgDisbandClusterResponse"
+	MessageUpdateCluster              = "MsgUpdateCluster"
+	MessageUpdateClusterResponse      = "MsgUpdateClusterResponse"
+	MessagePeerExchange               = "MsgPeerExchange"
+	MessagePeerExchangeResponse       = "MsgPeerExchangeResponse"
+	MessageScheduleExecution          = "MsgScheduleExecution"
+	MessageScheduleExecutionResponse  = "MsgScheduleExecutionResponse"
+	MessageScheduledExecution         = "MsgScheduledExecution"
+	MessageUsageQuery                 = "MsgUsageQuery"
+	MessageUsageQueryResponse         = "MsgUsageQueryResponse"
+	MessageQuotaQuery                 = "MsgQuotaQuery"
+	MessageQuotaQueryResponse         = "MsgQuotaQueryResponse"
+	MessageRecoveryReport             = "MsgRecoveryReport"
+	MessageRecoveryReportResponse     = "MsgRecoveryReportResponse"
+	MessageSwapExecutor               = "MsgSwapExecutor"
+	MessageSwapExecutorResponse       = "MsgSwapExecutorResponse"
+	MessageTopology                   = "MsgTopology"
+	MessageTopologyResponse           = "MsgTopologyResponse"
+	MessageCancelExecution            = "MsgCancelExecution"
+	MessageCancelExecutionResponse    = "MsgCancelExecutionResponse"
+	MessageStoreMaintenance           = "MsgStoreMaintenance"
+	MessageStoreMaintenanceResponse   = "MsgStoreMaintenanceResponse"
+	MessageHealthQuery                = "MsgHealthQuery"
+	MessageHealthQueryResponse        = "MsgHealthQueryResponse"
+	MessageFleetReport                = "MsgFleetReport"
+	MessageFleetPlan                  = "MsgFleetPlan"
+	MessageFleetPlanResponse          = "MsgFleetPlanResponse"
+	MessageTransferLeadership         = "MsgTransferLeadership"
+	MessageTransferLeadershipResponse = "MsgTransferLeadershipResponse"
+	MessageMisbehavior                = "MsgMisbehavior"
+	MessagePeerStates                 = "MsgPeerStates"
+	MessagePeerStatesResponse         = "MsgPeerStatesResponse"
+	MessageDelegateExecution          = "MsgDelegateExecution"
+	MessageDelegateExecutionResponse  = "MsgDelegateExecutionResponse"
+	MessageDrain                      = "MsgDrain"
+	MessageDrainResponse              = "MsgDrainResponse"
+	MessageSelfTest                   = "MsgSelfTest"
+	MessageSelfTestResponse           = "MsgSelfTestResponse"
+	MessageSubgroups                  = "MsgSubgroups"
+	MessageSubgroupsResponse          = "MsgSubgroupsResponse"
+	MessageAbortExecution             = "MsgAbortExecution"
+	MessageExecutionResult            = "MsgExecutionResult"
+	MessageExecutionResultResponse    = "MsgExecutionResultResponse"
+	MessageInteractiveExecution       = "MsgInteractiveExecution"
 )
 
 type TraceableMessage interface {
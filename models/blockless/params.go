@@ -1,19 +1,24 @@
 package blockless
 
 import (
-	"errors"
-
 	"github.com/libp2p/go-libp2p/core/protocol"
+
+	"github.com/blocklessnetwork/b7s/b7serrors"
 )
 
 // Sentinel errors.
+//
+// Deprecated: Use the errors from the `b7serrors` package.
 var (
-	ErrNotFound                = errors.New("not found")
-	ErrRollCallTimeout         = errors.New("roll call timed out - not enough nodes responded")
-	ErrExecutionNotEnoughNodes = errors.New("not enough execution results received")
+	ErrNotFound                = b7serrors.ErrNotFound
+	ErrRollCallTimeout         = b7serrors.ErrRollCallTimeout
+	ErrExecutionNotEnoughNodes = b7serrors.ErrExecutionNotEnoughNodes
+	ErrQuotaExceeded           = b7serrors.ErrQuotaExceeded
+	ErrRateLimited             = b7serrors.ErrRateLimited
 )
 
 const (
-	ProtocolID protocol.ID = "/b7s/work/1.0.0"
-	EnvPrefix  string      = "B7S_"
+	ProtocolID            protocol.ID = "/b7s/work/1.0.0"
+	InteractiveProtocolID protocol.ID = "/b7s/interactive/1.0.0" // Protocol for streaming standard input and output of interactive executions.
+	EnvPrefix             string      = "B7S_"
 )
@@ -1,5 +1,11 @@
 package codes
 
+import (
+	"net/http"
+
+	grpccodes "google.golang.org/grpc/codes"
+)
+
 // Code represents the status of an action. It is a rough equivalent of the HTTP status code.
 type Code string
 
@@ -10,19 +16,109 @@ const (
 	NoContent      Code = "204"
 	PartialContent Code = "206"
 
-	Invalid       Code = "400"
-	NotAuthorized Code = "401"
-	NotPermitted  Code = "403"
-	NotFound      Code = "404"
-	Timeout       Code = "408"
-
-	Error          Code = "500"
-	NotImplemented Code = "501"
-	NotAvailable   Code = "503"
-	NotSupported   Code = "505"
-	Unknown        Code = "520"
+	Invalid         Code = "400"
+	NotAuthorized   Code = "401"
+	PaymentRequired Code = "402"
+	NotPermitted    Code = "403"
+	NotFound        Code = "404"
+	Timeout         Code = "408"
+	Aborted         Code = "409"
+	InvalidOutput   Code = "422"
+	TooManyRequests Code = "429"
+	TooManyInFlight Code = "430" // Client has too many executions in flight at the same time.
+	QuotaExceeded   Code = "431" // Client used up its execution quota for the current period.
+
+	Error             Code = "500"
+	NotImplemented    Code = "501"
+	NotAvailable      Code = "503"
+	NotSupported      Code = "505"
+	ResourceExhausted Code = "507"
+	Unknown           Code = "520"
+	Overloaded        Code = "529"
 )
 
 func (c Code) String() string {
 	return string(c)
 }
+
+// Retryable returns true if the same request may succeed if retried later.
+func (c Code) Retryable() bool {
+	switch c {
+	case Timeout, Aborted, TooManyRequests, TooManyInFlight, QuotaExceeded, NotAvailable, ResourceExhausted, Overloaded:
+		return true
+	default:
+		return false
+	}
+}
+
+// HTTPStatus returns the HTTP status code corresponding to the code.
+func (c Code) HTTPStatus() int {
+	switch c {
+	case OK:
+		return http.StatusOK
+	case Accepted:
+		return http.StatusAccepted
+	case NoContent:
+		return http.StatusNoContent
+	case PartialContent:
+		return http.StatusPartialContent
+	case Invalid:
+		return http.StatusBadRequest
+	case NotAuthorized:
+		return http.StatusUnauthorized
+	case PaymentRequired:
+		return http.StatusPaymentRequired
+	case NotPermitted:
+		return http.StatusForbidden
+	case NotFound:
+		return http.StatusNotFound
+	case Timeout:
+		return http.StatusRequestTimeout
+	case Aborted:
+		return http.StatusConflict
+	case InvalidOutput:
+		return http.StatusUnprocessableEntity
+	case TooManyRequests, TooManyInFlight, QuotaExceeded:
+		return http.StatusTooManyRequests
+	case NotImplemented, NotSupported:
+		return http.StatusNotImplemented
+	case NotAvailable, Overloaded:
+		return http.StatusServiceUnavailable
+	case ResourceExhausted:
+		return http.StatusInsufficientStorage
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GRPCCode returns the gRPC status code corresponding to the code.
+func (c Code) GRPCCode() grpccodes.Code {
+	switch c {
+	case OK, Accepted, NoContent, PartialContent:
+		return grpccodes.OK
+	case Invalid:
+		return grpccodes.InvalidArgument
+	case NotAuthorized:
+		return grpccodes.Unauthenticated
+	case PaymentRequired, NotPermitted:
+		return grpccodes.PermissionDenied
+	case NotFound:
+		return grpccodes.NotFound
+	case Timeout:
+		return grpccodes.DeadlineExceeded
+	case Aborted:
+		return grpccodes.Aborted
+	case InvalidOutput:
+		return grpccodes.FailedPrecondition
+	case TooManyRequests, TooManyInFlight, QuotaExceeded, ResourceExhausted, Overloaded:
+		return grpccodes.ResourceExhausted
+	case NotImplemented, NotSupported:
+		return grpccodes.Unimplemented
+	case NotAvailable:
+		return grpccodes.Unavailable
+	case Unknown:
+		return grpccodes.Unknown
+	default:
+		return grpccodes.Internal
+	}
+}
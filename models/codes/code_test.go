@@ -0,0 +1,53 @@
+package codes_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	grpccodes "google.golang.org/grpc/codes"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+)
+
+func TestCode_Retryable(t *testing.T) {
+
+	retryable := []codes.Code{codes.Timeout, codes.Aborted, codes.TooManyRequests, codes.QuotaExceeded, codes.TooManyInFlight, codes.NotAvailable, codes.ResourceExhausted, codes.Overloaded}
+	for _, code := range retryable {
+		require.True(t, code.Retryable(), code.String())
+	}
+
+	final := []codes.Code{codes.OK, codes.Invalid, codes.PaymentRequired, codes.NotFound, codes.Error, codes.NotSupported}
+	for _, code := range final {
+		require.False(t, code.Retryable(), code.String())
+	}
+}
+
+func TestCode_Mapping(t *testing.T) {
+
+	tests := []struct {
+		code   codes.Code
+		status int
+		grpc   grpccodes.Code
+	}{
+		{codes.OK, http.StatusOK, grpccodes.OK},
+		{codes.NoContent, http.StatusNoContent, grpccodes.OK},
+		{codes.Invalid, http.StatusBadRequest, grpccodes.InvalidArgument},
+		{codes.PaymentRequired, http.StatusPaymentRequired, grpccodes.PermissionDenied},
+		{codes.Timeout, http.StatusRequestTimeout, grpccodes.DeadlineExceeded},
+		{codes.Aborted, http.StatusConflict, grpccodes.Aborted},
+		{codes.TooManyRequests, http.StatusTooManyRequests, grpccodes.ResourceExhausted},
+		{codes.TooManyInFlight, http.StatusTooManyRequests, grpccodes.ResourceExhausted},
+		{codes.QuotaExceeded, http.StatusTooManyRequests, grpccodes.ResourceExhausted},
+		{codes.NotSupported, http.StatusNotImplemented, grpccodes.Unimplemented},
+		{codes.ResourceExhausted, http.StatusInsufficientStorage, grpccodes.ResourceExhausted},
+		{codes.Unknown, http.StatusInternalServerError, grpccodes.Unknown},
+		{codes.Overloaded, http.StatusServiceUnavailable, grpccodes.ResourceExhausted},
+		{codes.Code("999"), http.StatusInternalServerError, grpccodes.Internal},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.status, test.code.HTTPStatus(), test.code.String())
+		require.Equal(t, test.grpc, test.code.GRPCCode(), test.code.String())
+	}
+}
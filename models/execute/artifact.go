@@ -0,0 +1,14 @@
+package execute
+
+// Artifact describes an output file produced by the function and uploaded to blob storage, instead of being sent
+// along with the execution result.
+type Artifact struct {
+	// Path of the file, relative to the artifacts directory of the function.
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	// Hex encoded SHA-256 digest of the file contents.
+	Checksum string `json:"checksum,omitempty"`
+	// Location of the uploaded file. Depending on the storage, artifacts have a URL, a CID or both.
+	URL string `json:"url,omitempty"`
+	CID string `json:"cid,omitempty"`
+}
@@ -0,0 +1,24 @@
+package execute
+
+import (
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+)
+
+// Execution stages that can be attempted more than once.
+const (
+	AttemptRollCall   = "roll-call"
+	AttemptExecution  = "execution"
+	AttemptDelegation = "delegation"
+)
+
+// Attempt describes a single try at a stage of the execution. Attempts are reported when the head node
+// had to retry or re-dispatch the request, with the last attempt being the one that produced the response.
+type Attempt struct {
+	Stage    string     `json:"stage"`
+	Peers    []peer.ID  `json:"peers,omitempty"`
+	Code     codes.Code `json:"code"`
+	Duration int64      `json:"duration_ms"`
+	Reason   string     `json:"reason,omitempty"`
+}
@@ -0,0 +1,449 @@
+package execute
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// AttributeExpression is a condition on node attributes, e.g. `cpu.cores >= 8 && (region == "eu" || region == "us")`.
+//
+// Supported are comparisons of an attribute with a value (`==`, `!=`, `<`, `<=`, `>`, `>=`), inclusive numeric ranges
+// (`ram in 16..64`), conjunction (`&&` or `and`), disjunction (`||` or `or`), negation (`!` or `not`) and parentheses.
+// An attribute on its own is true if the node has it. Values are compared as numbers if both sides are numbers,
+// and as strings otherwise. Comparisons with attributes the node does not have are false.
+type AttributeExpression struct {
+	root expressionNode
+}
+
+// ParseAttributeExpression parses the attribute expression.
+func ParseAttributeExpression(input string) (*AttributeExpression, error) {
+
+	tokens, err := tokenizeExpression(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, errors.New("expression is empty")
+	}
+
+	p := expressionParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %q at position %v", p.peek().text, p.peek().pos)
+	}
+
+	return &AttributeExpression{root: root}, nil
+}
+
+// Match evaluates the expression against the given attributes.
+func (e *AttributeExpression) Match(attributes map[string]string) bool {
+	return e.root.eval(attributes)
+}
+
+type expressionNode interface {
+	eval(attributes map[string]string) bool
+}
+
+type andNode struct{ left, right expressionNode }
+
+func (n andNode) eval(attributes map[string]string) bool {
+	return n.left.eval(attributes) && n.right.eval(attributes)
+}
+
+type orNode struct{ left, right expressionNode }
+
+func (n orNode) eval(attributes map[string]string) bool {
+	return n.left.eval(attributes) || n.right.eval(attributes)
+}
+
+type notNode struct{ operand expressionNode }
+
+func (n notNode) eval(attributes map[string]string) bool {
+	return !n.operand.eval(attributes)
+}
+
+type existsNode struct{ name string }
+
+func (n existsNode) eval(attributes map[string]string) bool {
+	_, ok := attributes[n.name]
+	return ok
+}
+
+type comparisonNode struct {
+	name  string
+	op    string
+	value string
+}
+
+func (n comparisonNode) eval(attributes map[string]string) bool {
+
+	have, ok := attributes[n.name]
+	if !ok {
+		return false
+	}
+
+	hv, herr := strconv.ParseFloat(have, 64)
+	wv, werr := strconv.ParseFloat(n.value, 64)
+	numeric := herr == nil && werr == nil
+
+	switch n.op {
+	case "==":
+		if numeric {
+			return hv == wv
+		}
+		return have == n.value
+	case "!=":
+		if numeric {
+			return hv != wv
+		}
+		return have != n.value
+	}
+
+	// Ordering is only defined for numbers.
+	if !numeric {
+		return false
+	}
+
+	switch n.op {
+	case "<":
+		return hv < wv
+	case "<=":
+		return hv <= wv
+	case ">":
+		return hv > wv
+	case ">=":
+		return hv >= wv
+	default:
+		return false
+	}
+}
+
+type rangeNode struct {
+	name     string
+	min, max float64
+}
+
+func (n rangeNode) eval(attributes map[string]string) bool {
+
+	have, ok := attributes[n.name]
+	if !ok {
+		return false
+	}
+
+	value, err := strconv.ParseFloat(have, 64)
+	if err != nil {
+		return false
+	}
+
+	return value >= n.min && value <= n.max
+}
+
+type tokenKind uint8
+
+const (
+	tokenIdent tokenKind = iota + 1
+	tokenString
+	tokenNumber
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// keyword returns the operator the identifier stands for, if it is a keyword.
+func (t token) keyword() string {
+	if t.kind != tokenIdent {
+		return ""
+	}
+
+	switch strings.ToLower(t.text) {
+	case "and":
+		return "&&"
+	case "or":
+		return "||"
+	case "not":
+		return "!"
+	case "in":
+		return "in"
+	default:
+		return ""
+	}
+}
+
+// is checks if the token is the given operator, or a keyword standing for it.
+func (t token) is(op string) bool {
+	return (t.kind == tokenOperator && t.text == op) || t.keyword() == op
+}
+
+var expressionOperators = []string{"==", "!=", "<=", ">=", "&&", "||", "..", "<", ">", "!", "(", ")"}
+
+func tokenizeExpression(input string) ([]token, error) {
+
+	var tokens []token
+	for pos := 0; pos < len(input); {
+
+		c := rune(input[pos])
+		switch {
+		case unicode.IsSpace(c):
+			pos++
+
+		case c == '"' || c == '\'':
+			end := strings.IndexRune(input[pos+1:], c)
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated string at position %v", pos)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: input[pos+1 : pos+1+end], pos: pos})
+			pos += end + 2
+
+		case unicode.IsDigit(c) || (c == '-' && pos+1 < len(input) && unicode.IsDigit(rune(input[pos+1]))):
+			start := pos
+			pos++
+			for pos < len(input) {
+				d := rune(input[pos])
+				// Stop at the range operator.
+				if d == '.' && pos+1 < len(input) && input[pos+1] == '.' {
+					break
+				}
+				if !unicode.IsDigit(d) && d != '.' {
+					break
+				}
+				pos++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: input[start:pos], pos: start})
+
+		case unicode.IsLetter(c) || c == '_':
+			start := pos
+			for pos < len(input) && isIdentRune(rune(input[pos])) {
+				// Stop at the range operator.
+				if input[pos] == '.' && pos+1 < len(input) && input[pos+1] == '.' {
+					break
+				}
+				pos++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: input[start:pos], pos: start})
+
+		default:
+			var found bool
+			for _, op := range expressionOperators {
+				if strings.HasPrefix(input[pos:], op) {
+					tokens = append(tokens, token{kind: tokenOperator, text: op, pos: pos})
+					pos += len(op)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unexpected character %q at position %v", c, pos)
+			}
+		}
+	}
+
+	return tokens, nil
+}
+
+func isIdentRune(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '.' || c == '-'
+}
+
+// expressionParser is a recursive descent parser for attribute expressions.
+// Operator precedence, from lowest: or, and, not.
+type expressionParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *expressionParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *expressionParser) peek() token {
+	if p.done() {
+		return token{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *expressionParser) next() (token, error) {
+	if p.done() {
+		return token{}, errors.New("unexpected end of expression")
+	}
+
+	t := p.tokens[p.pos]
+	p.pos++
+	return t, nil
+}
+
+func (p *expressionParser) parseOr() (expressionNode, error) {
+
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for !p.done() && p.peek().is("||") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *expressionParser) parseAnd() (expressionNode, error) {
+
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for !p.done() && p.peek().is("&&") {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *expressionParser) parseUnary() (expressionNode, error) {
+
+	if !p.done() && p.peek().is("!") {
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *expressionParser) parsePrimary() (expressionNode, error) {
+
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	if t.is("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		closing, err := p.next()
+		if err != nil || !closing.is(")") {
+			return nil, fmt.Errorf("missing closing parenthesis for parenthesis at position %v", t.pos)
+		}
+
+		return inner, nil
+	}
+
+	if t.kind != tokenIdent || t.keyword() != "" {
+		return nil, fmt.Errorf("expected attribute name at position %v, got %q", t.pos, t.text)
+	}
+
+	name := t.text
+
+	// Attribute on its own checks for its presence.
+	if p.done() {
+		return existsNode{name: name}, nil
+	}
+
+	op := p.peek()
+	switch {
+	case op.is("in"):
+		p.pos++
+		return p.parseRange(name)
+
+	case op.kind == tokenOperator && isComparison(op.text):
+		p.pos++
+
+		value, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+
+		if value.kind == tokenOperator || value.keyword() != "" {
+			return nil, fmt.Errorf("expected value at position %v, got %q", value.pos, value.text)
+		}
+
+		return comparisonNode{name: name, op: op.text, value: value.text}, nil
+
+	default:
+		return existsNode{name: name}, nil
+	}
+}
+
+func (p *expressionParser) parseRange(name string) (expressionNode, error) {
+
+	low, err := p.parseNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	sep, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if !sep.is("..") {
+		return nil, fmt.Errorf("expected range operator '..' at position %v, got %q", sep.pos, sep.text)
+	}
+
+	high, err := p.parseNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	if low > high {
+		return nil, fmt.Errorf("invalid range for attribute %v (min: %v, max: %v)", name, low, high)
+	}
+
+	return rangeNode{name: name, min: low, max: high}, nil
+}
+
+func (p *expressionParser) parseNumber() (float64, error) {
+
+	t, err := p.next()
+	if err != nil {
+		return 0, err
+	}
+
+	if t.kind != tokenNumber {
+		return 0, fmt.Errorf("expected number at position %v, got %q", t.pos, t.text)
+	}
+
+	value, err := strconv.ParseFloat(t.text, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number at position %v: %w", t.pos, err)
+	}
+
+	return value, nil
+}
+
+func isComparison(op string) bool {
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,85 @@
+package execute
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttributeExpression(t *testing.T) {
+
+	attributes := map[string]string{
+		"ram":       "32",
+		"cpu.cores": "8",
+		"region":    "eu-west",
+		"gpu":       "none",
+		"version":   "1.2.3",
+	}
+
+	t.Run("expressions are evaluated", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			expression string
+			match      bool
+		}{
+			{`ram >= 16`, true},
+			{`ram > 32`, false},
+			{`ram == 32.0`, true},
+			{`cpu.cores < 16 && cpu.cores >= 8`, true},
+			{`region == "eu-west"`, true},
+			{`region == 'us-east'`, false},
+			{`region == eu-west`, true},
+			{`region != "eu-west"`, false},
+			{`ram in 16..64`, true},
+			{`ram in 1..31.5`, false},
+			{`cpu.cores in 8..8`, true},
+			{`region == "us-east" || region == "eu-west"`, true},
+			{`region == "us-east" OR ram < 8`, false},
+			{`!(gpu == "none")`, false},
+			{`not gpu == "none" or ram >= 32`, true},
+			{`(region == "us-east" || region == "eu-west") && ram in 16..64 && !gpu == "nvidia"`, true},
+			{`ram >= 16 && region == "us-east" || cpu.cores == 8`, true},
+			{`ram >= 16 && (region == "us-east" || cpu.cores == 4)`, false},
+			{`region`, true},
+			{`tpu`, false},
+			{`!tpu && region`, true},
+			{`tpu == "v4"`, false},
+			{`!tpu == "v4"`, true},
+			// Ordering of non-numeric values is not defined.
+			{`region > "a"`, false},
+			{`version == 1.2.3`, true},
+		}
+
+		for _, test := range tests {
+			expr, err := ParseAttributeExpression(test.expression)
+			require.NoError(t, err, test.expression)
+			require.Equal(t, test.match, expr.Match(attributes), test.expression)
+		}
+	})
+	t.Run("invalid expressions are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		invalid := []string{
+			``,
+			`   `,
+			`ram >=`,
+			`>= 16`,
+			`ram >= 16 &&`,
+			`(ram >= 16`,
+			`ram >= 16)`,
+			`ram in 16`,
+			`ram in 64..16`,
+			`ram in a..b`,
+			`region == "eu`,
+			`ram >= 16 # comment`,
+			`ram 16`,
+			`and`,
+		}
+
+		for _, expression := range invalid {
+			_, err := ParseAttributeExpression(expression)
+			require.Error(t, err, expression)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package execute
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+)
+
+// Checksum contains content hashes of the execution output. Hashes are hex encoded SHA-256 digests.
+type Checksum struct {
+	Stdout string `json:"stdout"`
+	Stderr string `json:"stderr"`
+	// Output is the hash of the complete runtime output - stdout, stderr and exit code.
+	// Results with the same output hash are identical.
+	Output string `json:"output"`
+}
+
+// Checksum calculates the content hashes of the runtime output.
+func (o RuntimeOutput) Checksum() *Checksum {
+
+	stdout := sha256.Sum256([]byte(o.Stdout))
+	stderr := sha256.Sum256([]byte(o.Stderr))
+
+	var exitCode [8]byte
+	binary.BigEndian.PutUint64(exitCode[:], uint64(o.ExitCode))
+
+	h := sha256.New()
+	h.Write(stdout[:])
+	h.Write(stderr[:])
+	h.Write(exitCode[:])
+
+	checksum := Checksum{
+		Stdout: hex.EncodeToString(stdout[:]),
+		Stderr: hex.EncodeToString(stderr[:]),
+		Output: hex.EncodeToString(h.Sum(nil)),
+	}
+
+	return &checksum
+}
+
+// SetChecksum sets the checksum of the execution result.
+func (r *NodeResult) SetChecksum() {
+	r.Checksum = r.Result.Result.Checksum()
+}
+
+// VerifyChecksum checks that the execution result matches its checksum. Results without a checksum are considered valid.
+func (r NodeResult) VerifyChecksum() error {
+
+	if r.Checksum == nil {
+		return nil
+	}
+
+	expected := r.Result.Result.Checksum()
+	if *r.Checksum != *expected {
+		return fmt.Errorf("checksum mismatch (have: %s, want: %s)", r.Checksum.Output, expected.Output)
+	}
+
+	return nil
+}
+
+// OutputChecksum returns the hash results are compared by. This is the hash of the normalized output for functions with
+// normalization rules, or the hash of the complete runtime output. The hash is always calculated from the output,
+// checksums reported by the node are never used.
+func (r NodeResult) OutputChecksum() string {
+
+	if r.Normalized != nil {
+		return r.Normalized.Checksum().Output
+	}
+
+	return r.Result.Result.Checksum().Output
+}
@@ -0,0 +1,86 @@
+package execute
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+)
+
+func TestNodeResult_Checksum(t *testing.T) {
+
+	res := NodeResult{
+		Result: Result{
+			Code: codes.OK,
+			Result: RuntimeOutput{
+				Stdout:   "generic-execution-result",
+				Stderr:   "generic-execution-log",
+				ExitCode: 0,
+			},
+		},
+	}
+
+	t.Run("result without checksum is valid", func(t *testing.T) {
+		require.NoError(t, res.VerifyChecksum())
+	})
+	t.Run("result matches its checksum", func(t *testing.T) {
+
+		cp := res
+		cp.SetChecksum()
+
+		require.NotNil(t, cp.Checksum)
+		require.NoError(t, cp.VerifyChecksum())
+		require.Equal(t, res.OutputChecksum(), cp.OutputChecksum())
+	})
+	t.Run("modified result fails verification", func(t *testing.T) {
+
+		cp := res
+		cp.SetChecksum()
+		cp.Result.Result.Stdout = "modified-execution-result"
+
+		require.Error(t, cp.VerifyChecksum())
+	})
+	t.Run("output checksum differs for different outputs", func(t *testing.T) {
+
+		stdout := res
+		stdout.Result.Result.Stdout = "different-execution-result"
+		require.NotEqual(t, res.OutputChecksum(), stdout.OutputChecksum())
+
+		exitCode := res
+		exitCode.Result.Result.ExitCode = 1
+		require.NotEqual(t, res.OutputChecksum(), exitCode.OutputChecksum())
+
+		// Moving output between stdout and stderr changes the checksum.
+		a := NodeResult{Result: Result{Result: RuntimeOutput{Stdout: "ab", Stderr: "c"}}}
+		b := NodeResult{Result: Result{Result: RuntimeOutput{Stdout: "a", Stderr: "bc"}}}
+		require.NotEqual(t, a.OutputChecksum(), b.OutputChecksum())
+	})
+	t.Run("results are compared by normalized output", func(t *testing.T) {
+
+		normalized := RuntimeOutput{Stdout: "normalized-execution-result"}
+
+		a := res
+		a.Normalized = &normalized
+		a.SetChecksum()
+
+		b := res
+		b.Result.Result.Stdout = "different-execution-result"
+		b.Normalized = &normalized
+		b.SetChecksum()
+
+		require.NoError(t, a.VerifyChecksum())
+		require.NoError(t, b.VerifyChecksum())
+		require.Equal(t, a.OutputChecksum(), b.OutputChecksum())
+		require.Equal(t, normalized.Checksum().Output, a.OutputChecksum())
+	})
+	t.Run("reported checksums are not trusted", func(t *testing.T) {
+
+		forged := res
+		forged.Result.Result.Stdout = "forged-execution-result"
+		forged.Checksum = res.Result.Result.Checksum()
+
+		require.Error(t, forged.VerifyChecksum())
+		require.NotEqual(t, res.OutputChecksum(), forged.OutputChecksum())
+	})
+}
@@ -0,0 +1,30 @@
+package execute
+
+import (
+	"slices"
+)
+
+// Names of the environment variables identifying the execution to the function, so function authors can correlate
+// their own logs with b7s traces and execution results.
+const (
+	RequestIDEnvName = "B7S_REQUEST_ID"
+	TraceIDEnvName   = "B7S_TRACE_ID"
+)
+
+// WithCorrelation returns the request with the request ID and trace ID added to the execution environment.
+// Trace ID is omitted if empty. Variables with the same names set by the client are replaced. The original request is not modified.
+func (r Request) WithCorrelation(requestID string, traceID string) Request {
+
+	env := slices.DeleteFunc(slices.Clone(r.Config.Environment), func(v EnvVar) bool {
+		return v.Name == RequestIDEnvName || v.Name == TraceIDEnvName
+	})
+
+	env = append(env, EnvVar{Name: RequestIDEnvName, Value: requestID})
+	if traceID != "" {
+		env = append(env, EnvVar{Name: TraceIDEnvName, Value: traceID})
+	}
+
+	r.Config.Environment = env
+
+	return r
+}
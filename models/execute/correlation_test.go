@@ -0,0 +1,41 @@
+package execute
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequest_WithCorrelation(t *testing.T) {
+
+	const (
+		requestID = "dummy-request-id"
+		traceID   = "dummy-trace-id"
+	)
+
+	t.Run("request and trace ID are added to the environment", func(t *testing.T) {
+
+		req := Request{
+			Config: Config{
+				Environment: []EnvVar{{Name: "FOO", Value: "bar"}, {Name: RequestIDEnvName, Value: "spoofed"}},
+			},
+		}
+
+		correlated := req.WithCorrelation(requestID, traceID)
+		require.Equal(t, []EnvVar{
+			{Name: "FOO", Value: "bar"},
+			{Name: RequestIDEnvName, Value: requestID},
+			{Name: TraceIDEnvName, Value: traceID},
+		}, correlated.Config.Environment)
+
+		// Original request is not modified.
+		require.Equal(t, "spoofed", req.Config.Environment[1].Value)
+	})
+	t.Run("trace ID is omitted if not set", func(t *testing.T) {
+
+		req := Request{Config: Config{Environment: []EnvVar{{Name: "FOO", Value: "bar"}}}}
+
+		correlated := req.WithCorrelation(requestID, "")
+		require.Equal(t, []EnvVar{{Name: "FOO", Value: "bar"}, {Name: RequestIDEnvName, Value: requestID}}, correlated.Config.Environment)
+	})
+}
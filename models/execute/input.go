@@ -0,0 +1,64 @@
+package execute
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+)
+
+// Schemes of the URLs inputs can be fetched from.
+const (
+	InputSchemeHTTP  = "http"
+	InputSchemeHTTPS = "https"
+	InputSchemeIPFS  = "ipfs"
+)
+
+// MaxInputs is the maximum number of inputs an execution request can have.
+const MaxInputs = 32
+
+// Input is a remote resource, such as a dataset, the worker fetches before the execution and places in the function filesystem.
+type Input struct {
+	// URL of the resource - either an HTTP(S) URL, or an IPFS URL in the form of `ipfs://<cid>/<path>`.
+	URL string `json:"url"`
+
+	// Path of the file in the function filesystem, relative to its root.
+	Path string `json:"path"`
+
+	// Checksum is the optional hex-encoded SHA-256 hash of the resource. Executions fail if the fetched resource does not match it.
+	// Workers that have a resource with the same hash cached use it without fetching the resource again.
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// Valid checks if the input can be fetched and placed in the function filesystem.
+func (i Input) Valid() error {
+
+	u, err := url.Parse(i.URL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+
+	switch u.Scheme {
+	case InputSchemeHTTP, InputSchemeHTTPS, InputSchemeIPFS:
+	default:
+		return fmt.Errorf("unsupported URL scheme: %s", u.Scheme)
+	}
+
+	if u.Host == "" {
+		return errors.New("URL host is required")
+	}
+
+	if !filepath.IsLocal(filepath.FromSlash(i.Path)) {
+		return fmt.Errorf("path must be relative to the filesystem root: %s", i.Path)
+	}
+
+	if i.Checksum != "" {
+		sum, err := hex.DecodeString(i.Checksum)
+		if err != nil || len(sum) != 32 {
+			return errors.New("checksum must be a hex-encoded SHA-256 hash")
+		}
+	}
+
+	return nil
+}
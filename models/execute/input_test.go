@@ -0,0 +1,39 @@
+package execute
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequest_ValidInputs(t *testing.T) {
+
+	const checksum = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+	req := Request{
+		FunctionID: "dummy-function-id",
+		Method:     "dummy-method",
+		Config: Config{
+			Inputs: []Input{
+				{URL: "https://example.com/dataset.csv", Path: "data/dataset.csv"},
+				{URL: "ipfs://bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi/model.bin", Path: "model.bin", Checksum: checksum},
+			},
+		},
+	}
+	require.NoError(t, req.Valid())
+
+	invalid := []Input{
+		{URL: "ftp://example.com/dataset.csv", Path: "dataset.csv"},
+		{URL: "https:///dataset.csv", Path: "dataset.csv"},
+		{URL: "https://example.com/dataset.csv", Path: "../dataset.csv"},
+		{URL: "https://example.com/dataset.csv", Path: "/etc/dataset.csv"},
+		{URL: "https://example.com/dataset.csv", Path: ""},
+		{URL: "https://example.com/dataset.csv", Path: "dataset.csv", Checksum: "abcd"},
+		{URL: "https://example.com/dataset.csv", Path: "dataset.csv", Checksum: strings.Repeat("x", 64)},
+	}
+	for _, input := range invalid {
+		req.Config.Inputs = []Input{input}
+		require.Error(t, req.Valid(), input)
+	}
+}
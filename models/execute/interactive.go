@@ -0,0 +1,15 @@
+package execute
+
+// InteractiveFrame is a single message exchanged on the stream of an interactive execution, encoded as a line of JSON.
+// Clients send chunks of standard input, and close standard input once they are done. Workers send chunks of standard output
+// as the function writes them, and the execution result once the function exits. The result is the last frame on the stream.
+type InteractiveFrame struct {
+	Stdin      []byte `json:"stdin,omitempty"`
+	CloseStdin bool   `json:"close_stdin,omitempty"`
+
+	Stdout []byte  `json:"stdout,omitempty"`
+	Result *Result `json:"result,omitempty"`
+
+	// Used to communicate the reason for failure to the user.
+	ErrorMessage string `json:"message,omitempty"`
+}
@@ -0,0 +1,31 @@
+package execute
+
+import (
+	"errors"
+)
+
+// ResourceLimits describes the resources a single execution is allowed to use. Zero values mean no limit.
+type ResourceLimits struct {
+	// CPUPercentage is the portion of a single CPU the execution can use - e.g. 0.5 for half of a CPU.
+	CPUPercentage float64 `json:"cpu_percentage,omitempty"`
+
+	// MemoryKB is the maximum amount of memory the execution can use, in kilobytes.
+	MemoryKB int64 `json:"memory_kb,omitempty"`
+
+	// FileDescriptors is the maximum number of files the execution can have open at a time.
+	FileDescriptors uint64 `json:"file_descriptors,omitempty"`
+}
+
+// Valid checks if the resource limits are sensible.
+func (l ResourceLimits) Valid() error {
+
+	if l.CPUPercentage < 0 {
+		return errors.New("CPU percentage cannot be negative")
+	}
+
+	if l.MemoryKB < 0 {
+		return errors.New("memory limit cannot be negative")
+	}
+
+	return nil
+}
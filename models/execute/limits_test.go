@@ -0,0 +1,29 @@
+package execute
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequest_ValidLimits(t *testing.T) {
+
+	req := Request{
+		FunctionID: "dummy-function-id",
+		Method:     "dummy-method",
+		Config: Config{
+			Limits: &ResourceLimits{
+				CPUPercentage:   0.5,
+				MemoryKB:        128_000,
+				FileDescriptors: 64,
+			},
+		},
+	}
+	require.NoError(t, req.Valid())
+
+	req.Config.Limits = &ResourceLimits{CPUPercentage: -1}
+	require.Error(t, req.Valid())
+
+	req.Config.Limits = &ResourceLimits{MemoryKB: -1}
+	require.Error(t, req.Valid())
+}
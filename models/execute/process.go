@@ -5,3 +5,12 @@ type ProcessID struct {
 	PID    int     // PID can used to identify a process on all platforms.
 	Handle uintptr // windows.Handle value that can be used for Windows-specific operations.
 }
+
+// ProcessReuse describes whether the execution was done by a reused runtime process.
+type ProcessReuse struct {
+	Reused     bool   `json:"reused"`               // Reused is true if the process handled earlier executions too.
+	Invocation uint   `json:"invocation,omitempty"` // Invocation is the ordinal number of this execution for the process.
+	Recycled   bool   `json:"recycled,omitempty"`   // Recycled is true if the process was retired after this execution.
+	Reason     string `json:"reason,omitempty"`     // Reason explains why the process was retired.
+	Warm       bool   `json:"warm,omitempty"`       // Warm is true if the process was started ahead of the execution.
+}
@@ -0,0 +1,188 @@
+package execute
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// MaxProvenanceHops is the maximum number of hops a provenance chain can have.
+const MaxProvenanceHops = 16
+
+// Hop describes a node that handled the execution request on its way to the worker.
+type Hop struct {
+	Peer     peer.ID   `json:"peer"`
+	Role     string    `json:"role,omitempty"`
+	Received time.Time `json:"received"`
+	// RequestID is the ID the node passed the request on under. Head nodes assign their own request IDs, so it can change along the chain.
+	RequestID string `json:"request_id,omitempty"`
+	// RequestHash is the hash of the request the node passed on, excluding its signature.
+	RequestHash string `json:"request_hash"`
+	// Signature covers the hop and the signature of the previous hop, chaining the hops together.
+	Signature string `json:"signature"`
+}
+
+// Provenance lists the nodes that handled the execution request, in order.
+type Provenance []Hop
+
+// hopPayload is the signed representation of a hop.
+type hopPayload struct {
+	Previous    string    `json:"previous,omitempty"`
+	RequestID   string    `json:"request_id,omitempty"`
+	RequestHash string    `json:"request_hash"`
+	Peer        peer.ID   `json:"peer"`
+	Role        string    `json:"role,omitempty"`
+	Received    time.Time `json:"received"`
+}
+
+// Append returns the provenance chain extended with a hop for the node with the given key, passing on the request under the given ID.
+// The existing chain is not modified.
+func (p Provenance) Append(key crypto.PrivKey, requestID string, req Request, role string, received time.Time) (Provenance, error) {
+
+	if len(p) >= MaxProvenanceHops {
+		return nil, fmt.Errorf("provenance chain too long (max: %v)", MaxProvenanceHops)
+	}
+
+	id, err := peer.IDFromPrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("could not determine peer ID: %w", err)
+	}
+
+	hash, err := requestHash(req)
+	if err != nil {
+		return nil, err
+	}
+
+	hop := Hop{
+		Peer:        id,
+		Role:        role,
+		Received:    received.UTC(),
+		RequestID:   requestID,
+		RequestHash: hash,
+	}
+
+	payload, err := p.payload(hop)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := key.Sign(payload)
+	if err != nil {
+		return nil, fmt.Errorf("could not sign hop: %w", err)
+	}
+
+	hop.Signature = hex.EncodeToString(sig)
+
+	chain := make(Provenance, 0, len(p)+1)
+	chain = append(chain, p...)
+	chain = append(chain, hop)
+
+	return chain, nil
+}
+
+// Verify checks that the chain ends with the hop of the peer the request was received from, passing on the given request under
+// the given ID, and checks the signatures of all hops in the chain. Hops are signed by the keys of their peers.
+func (p Provenance) Verify(from peer.ID, requestID string, req Request) error {
+
+	if len(p) == 0 {
+		return errors.New("provenance chain is empty")
+	}
+
+	if len(p) > MaxProvenanceHops {
+		return fmt.Errorf("provenance chain too long (have: %v, max: %v)", len(p), MaxProvenanceHops)
+	}
+
+	last := p[len(p)-1]
+	if last.Peer != from {
+		return fmt.Errorf("provenance chain does not end with the sending peer (last: %s, from: %s)", last.Peer, from)
+	}
+
+	if last.RequestID != requestID {
+		return fmt.Errorf("provenance chain is for a different request (have: %s, want: %s)", last.RequestID, requestID)
+	}
+
+	hash, err := requestHash(req)
+	if err != nil {
+		return err
+	}
+
+	if last.RequestHash != hash {
+		return errors.New("provenance chain does not match the request")
+	}
+
+	for i, hop := range p {
+
+		key, err := hop.Peer.ExtractPublicKey()
+		if err != nil {
+			return fmt.Errorf("could not extract public key of hop %v (peer: %s): %w", i, hop.Peer, err)
+		}
+
+		payload, err := p[:i].payload(hop)
+		if err != nil {
+			return err
+		}
+
+		sig, err := hex.DecodeString(hop.Signature)
+		if err != nil {
+			return fmt.Errorf("could not decode signature of hop %v from hex: %w", i, err)
+		}
+
+		ok, err := key.Verify(payload, sig)
+		if err != nil {
+			return fmt.Errorf("could not verify signature of hop %v: %w", i, err)
+		}
+
+		if !ok {
+			return fmt.Errorf("invalid signature of hop %v (peer: %s)", i, hop.Peer)
+		}
+	}
+
+	return nil
+}
+
+// payload returns the byte representation of the hop that follows the chain.
+func (p Provenance) payload(hop Hop) ([]byte, error) {
+
+	if hop.RequestHash == "" {
+		return nil, errors.New("request hash is required")
+	}
+
+	hp := hopPayload{
+		RequestID:   hop.RequestID,
+		RequestHash: hop.RequestHash,
+		Peer:        hop.Peer,
+		Role:        hop.Role,
+		Received:    hop.Received,
+	}
+	if len(p) > 0 {
+		hp.Previous = p[len(p)-1].Signature
+	}
+
+	payload, err := json.Marshal(hp)
+	if err != nil {
+		return nil, fmt.Errorf("could not get byte representation of the hop: %w", err)
+	}
+
+	return payload, nil
+}
+
+// requestHash returns the hex-encoded SHA-256 hash of the request. The request signature is excluded, since head nodes sign the
+// request after adding their hop.
+func requestHash(req Request) (string, error) {
+
+	req.Signature = ""
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("could not get byte representation of the request: %w", err)
+	}
+
+	hash := sha256.Sum256(payload)
+	return hex.EncodeToString(hash[:]), nil
+}
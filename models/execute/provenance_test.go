@@ -0,0 +1,140 @@
+package execute
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvenance(t *testing.T) {
+
+	const (
+		requestID = "dummy-request-id"
+	)
+
+	var (
+		req = Request{
+			FunctionID: "dummy-function-id",
+			Method:     "dummy-method",
+		}
+	)
+
+	newKey := func(t *testing.T) crypto.PrivKey {
+		t.Helper()
+
+		key, _, err := crypto.GenerateKeyPair(crypto.Ed25519, 0)
+		require.NoError(t, err)
+
+		return key
+	}
+
+	// chain returns a provenance chain and the peer that added the last hop.
+	chain := func(t *testing.T) (Provenance, peer.ID) {
+		t.Helper()
+
+		var (
+			provenance Provenance
+			last       peer.ID
+			err        error
+		)
+		for _, role := range []string{"gateway", "head", "worker"} {
+			key := newKey(t)
+			provenance, err = provenance.Append(key, requestID, req, role, time.Now())
+			require.NoError(t, err)
+
+			last, err = peer.IDFromPrivateKey(key)
+			require.NoError(t, err)
+		}
+
+		return provenance, last
+	}
+
+	t.Run("signed chain is valid", func(t *testing.T) {
+		t.Parallel()
+
+		provenance, from := chain(t)
+		require.Len(t, provenance, 3)
+		require.Equal(t, "worker", provenance[2].Role)
+		require.Equal(t, requestID, provenance[2].RequestID)
+		require.NoError(t, provenance.Verify(from, requestID, req))
+	})
+	t.Run("appending does not modify the chain", func(t *testing.T) {
+		t.Parallel()
+
+		provenance, from := chain(t)
+		extended, err := provenance[:1].Append(newKey(t), requestID, req, "head", time.Now())
+		require.NoError(t, err)
+
+		require.NotEqual(t, provenance[1], extended[1])
+		require.NoError(t, provenance.Verify(from, requestID, req))
+	})
+	t.Run("request signature is not part of the chain", func(t *testing.T) {
+		t.Parallel()
+
+		provenance, from := chain(t)
+
+		signed := req
+		signed.Signature = "dummy-signature"
+		require.NoError(t, provenance.Verify(from, requestID, signed))
+	})
+	t.Run("chain must end with the sending peer", func(t *testing.T) {
+		t.Parallel()
+
+		provenance, _ := chain(t)
+		require.Error(t, provenance.Verify(provenance[1].Peer, requestID, req))
+	})
+	t.Run("chain is bound to the request ID", func(t *testing.T) {
+		t.Parallel()
+
+		provenance, from := chain(t)
+		require.Error(t, provenance.Verify(from, "other-request-id", req))
+
+		provenance[2].RequestID = "other-request-id"
+		require.Error(t, provenance.Verify(from, "other-request-id", req))
+	})
+	t.Run("chain is bound to the request", func(t *testing.T) {
+		t.Parallel()
+
+		provenance, from := chain(t)
+
+		other := req
+		other.FunctionID = "other-function-id"
+		require.Error(t, provenance.Verify(from, requestID, other))
+
+		other = req
+		other.Parameters = []Parameter{{Name: "dummy-name", Value: "dummy-value"}}
+		require.Error(t, provenance.Verify(from, requestID, other))
+	})
+	t.Run("altered hop is detected", func(t *testing.T) {
+		t.Parallel()
+
+		provenance, from := chain(t)
+		provenance[1].Received = provenance[1].Received.Add(-time.Second)
+		require.Error(t, provenance.Verify(from, requestID, req))
+	})
+	t.Run("removed hop is detected", func(t *testing.T) {
+		t.Parallel()
+
+		provenance, from := chain(t)
+		provenance = append(provenance[:1], provenance[2:]...)
+		require.Error(t, provenance.Verify(from, requestID, req))
+	})
+	t.Run("chain length is capped", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			provenance Provenance
+			err        error
+		)
+		for range MaxProvenanceHops {
+			provenance, err = provenance.Append(newKey(t), requestID, req, "head", time.Now())
+			require.NoError(t, err)
+		}
+
+		_, err = provenance.Append(newKey(t), requestID, req, "head", time.Now())
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,14 @@
+package execute
+
+import (
+	"time"
+)
+
+// QuotaRemaining describes the execution quota a client has left. Limits that are not set are omitted.
+type QuotaRemaining struct {
+	Client          string     `json:"client"`
+	Executions      *uint      `json:"executions,omitempty"`
+	ExecutionsReset *time.Time `json:"executions_reset,omitempty"`
+	CPUSeconds      *float64   `json:"cpu_seconds,omitempty"`
+	CPUReset        *time.Time `json:"cpu_reset,omitempty"`
+}
@@ -2,10 +2,15 @@ package execute
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 )
 
+// MaxTags is the maximum number of tags an execution request can have.
+const MaxTags = 16
+
 // Request describes an execution request.
 type Request struct {
 	FunctionID string      `json:"function_id"`
@@ -29,6 +34,61 @@ func (r Request) Valid() error {
 		err = multierror.Append(err, errors.New("method is required"))
 	}
 
+	if r.Config.Quorum > 1 && uint(max(r.Config.NodeCount, 1)) < r.Config.Quorum {
+		err = multierror.Append(err, errors.New("quorum cannot be larger than the number of nodes"))
+	}
+
+	if len(r.Config.Tags) > MaxTags {
+		err = multierror.Append(err, fmt.Errorf("too many tags (have: %v, max: %v)", len(r.Config.Tags), MaxTags))
+	}
+
+	if r.Config.Attributes != nil && r.Config.Attributes.Expression != "" {
+		_, perr := ParseAttributeExpression(r.Config.Attributes.Expression)
+		if perr != nil {
+			err = multierror.Append(err, fmt.Errorf("invalid attribute expression: %w", perr))
+		}
+	}
+
+	if r.Config.Tenant != "" && !ValidTenant(r.Config.Tenant) {
+		err = multierror.Append(err, fmt.Errorf("invalid tenant ID: %s", r.Config.Tenant))
+	}
+
+	if len(r.Config.Inputs) > MaxInputs {
+		err = multierror.Append(err, fmt.Errorf("too many inputs (have: %v, max: %v)", len(r.Config.Inputs), MaxInputs))
+	}
+
+	for _, input := range r.Config.Inputs {
+		ierr := input.Valid()
+		if ierr != nil {
+			err = multierror.Append(err, fmt.Errorf("invalid input (url: %s): %w", input.URL, ierr))
+		}
+	}
+
+	if r.Config.Sampling != nil {
+		serr := r.Config.Sampling.Valid()
+		if serr != nil {
+			err = multierror.Append(err, fmt.Errorf("invalid sampling: %w", serr))
+		}
+
+		if r.Config.NodeCount > 0 || r.Config.Quorum > 1 || r.Config.Verify {
+			err = multierror.Append(err, errors.New("sampling cannot be used together with node count, quorum or verification"))
+		}
+	}
+
+	if r.Config.Limits != nil {
+		lerr := r.Config.Limits.Valid()
+		if lerr != nil {
+			err = multierror.Append(err, fmt.Errorf("invalid resource limits: %w", lerr))
+		}
+	}
+
+	for name := range r.Config.Tags {
+		if name == "" {
+			err = multierror.Append(err, errors.New("tag name cannot be empty"))
+			break
+		}
+	}
+
 	return err.ErrorOrNil()
 }
 
@@ -51,14 +111,62 @@ type Config struct {
 	// NodeCount specifies how many nodes should execute this request.
 	NodeCount int `json:"number_of_nodes,omitempty"`
 
-	// When should the execution timeout
+	// When should the execution timeout (in seconds). Workers kill executions that run longer.
 	Timeout int `json:"timeout,omitempty"`
 
-	// Consensus algorithm to use. Raft and PBFT are supported at this moment.
+	// Consensus algorithm to use. Raft, PBFT and HotStuff are supported at this moment.
 	ConsensusAlgorithm string `json:"consensus_algorithm,omitempty"`
 
 	// Threshold (percentage) defines how many nodes should respond with a result to consider this execution successful.
 	Threshold float64 `json:"threshold,omitempty"`
+
+	// Quorum defines how many nodes should report identical results to consider this execution successful.
+	// It is evaluated by the head node and only used for executions without consensus.
+	Quorum uint `json:"quorum,omitempty"`
+
+	// Verify requests redundant execution verification. Head node waits for results from all nodes, and considers the execution
+	// successful only if at least `Quorum` of them are identical - or all of them, if quorum is not set. Peers with differing
+	// results are reported in the cluster information. It is only used for executions without consensus.
+	Verify bool `json:"verify,omitempty"`
+
+	// Tags are client-provided labels, such as team, project or cost center. Head node reports usage grouped by tags.
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// NotBefore is the time before which the execution should not start. Head node holds the request until then,
+	// acknowledges it right away and delivers the results once the execution is done - as with asynchronous requests.
+	NotBefore time.Time `json:"not_before,omitempty"`
+
+	// Affinity is a session token. Head node sends executions of the function with the same token to the same set of workers
+	// for as long as they remain healthy. It is only used for executions without consensus.
+	Affinity string `json:"affinity,omitempty"`
+
+	// Watermark is an optional tenant-specific secret. Workers pass it to the function in the execution environment and stamp their results
+	// with a tag derived from it, so results of other tenants' executions - mixed up or replayed - are detected and dropped by the head node.
+	Watermark string `json:"watermark,omitempty"`
+
+	// ResultTopic is an optional topic workers publish their execution results to, in addition to sending them to the head node,
+	// so third-party collectors and other head nodes can observe them.
+	ResultTopic string `json:"result_topic,omitempty"`
+
+	// Priority of the execution on the worker. Busy workers execute higher priority requests first.
+	// Workers that have no room left for waiting requests turn the request down, so the head node can try another worker.
+	Priority uint `json:"priority,omitempty"`
+
+	// Limits are the resources the execution is allowed to use on the worker, on top of any limits the worker enforces for all executions.
+	// Workers that cannot enforce them fail the execution.
+	Limits *ResourceLimits `json:"limits,omitempty"`
+
+	// Tenant identifies the customer the execution is done for. Workers serving multiple tenants keep executions of different
+	// tenants apart - they get their own working directories, environment, runtime processes and caches, and are subject
+	// to per-tenant concurrency limits. Requests without a tenant are treated as a tenant of their own.
+	Tenant string `json:"tenant,omitempty"`
+
+	// Inputs are remote resources the worker fetches and places in the function filesystem before the execution.
+	// Workers cache fetched inputs, so repeated executions against the same resources do not fetch them again.
+	Inputs []Input `json:"inputs,omitempty"`
+
+	// Sampling, if set, has the request executed on a stratified sample of workers instead of a given number of them.
+	Sampling *Sampling `json:"sampling,omitempty"`
 }
 
 // EnvVar represents the name and value of the environment variables set for the execution.
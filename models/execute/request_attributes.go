@@ -1,19 +1,30 @@
 package execute
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/libp2p/go-libp2p/core/peer"
 )
 
 type Attributes struct {
-	// Values specify which attributes the node in question should have.
-	// At the moment we support strict equality only, so no `if RAM >= 16GB` types of conditions.
+	// Values specify which attributes the node in question should have. Values are compared for strict equality.
 	Values []Parameter `json:"values,omitempty"`
 
+	// Expression is a condition the node attributes should satisfy, in addition to the values above.
+	// It allows for conditions such as `ram >= 16 && (region == "eu" || region == "us")`. See `AttributeExpression`.
+	Expression string `json:"expression,omitempty"`
+
 	// Should we accept nodes whose attributes are not attested?
 	AttestationRequired bool `json:"attestation_required,omitempty"`
 
 	// Explicitly request specific attestors.
 	Attestors AttributeAttestors `json:"attestors,omitempty"`
+
+	// GPU specifies the GPUs the node should have.
+	GPU *GPURequirements `json:"gpu,omitempty"`
 }
 
 type AttributeAttestors struct {
@@ -23,3 +34,60 @@ type AttributeAttestors struct {
 	// Any one of these attestors should be found.
 	OneOf []peer.ID `json:"one_of,omitempty"`
 }
+
+// Attributes of the node describing its GPUs.
+const (
+	AttributeGPUCount = "gpu.count" // Number of GPUs.
+	AttributeGPUModel = "gpu.model" // GPU models, comma separated if the node has GPUs of different models.
+	AttributeGPUVRAM  = "gpu.vram"  // Memory of the smallest GPU in MiB.
+)
+
+// GPURequirements describe the GPUs the node should have. Executions requiring GPUs are given access to the GPU devices of the worker.
+type GPURequirements struct {
+	// Count is the minimum number of GPUs. Zero means at least one.
+	Count uint `json:"count,omitempty"`
+
+	// Model is matched as a case-insensitive substring of the GPU model, e.g. `A100`.
+	Model string `json:"model,omitempty"`
+
+	// VRAM is the minimum memory of each GPU, in MiB.
+	VRAM uint `json:"vram,omitempty"`
+}
+
+// RequiresGPU returns true if the attributes require GPUs.
+func (a *Attributes) RequiresGPU() bool {
+	return a != nil && a.GPU != nil
+}
+
+// Match checks if the node attributes satisfy the GPU requirements.
+func (g GPURequirements) Match(attributes map[string]string) error {
+
+	count, err := strconv.ParseUint(attributes[AttributeGPUCount], 10, 32)
+	if err != nil || count == 0 {
+		return errors.New("GPU required but none found")
+	}
+
+	if uint(count) < max(g.Count, 1) {
+		return fmt.Errorf("not enough GPUs (want: %v, have: %v)", g.Count, count)
+	}
+
+	if g.Model != "" {
+		model := attributes[AttributeGPUModel]
+		if !strings.Contains(strings.ToLower(model), strings.ToLower(g.Model)) {
+			return fmt.Errorf("GPU model does not match (want: %v, have: %v)", g.Model, model)
+		}
+	}
+
+	if g.VRAM > 0 {
+		vram, err := strconv.ParseUint(attributes[AttributeGPUVRAM], 10, 32)
+		if err != nil {
+			return fmt.Errorf("GPU memory size unknown (value: %v)", attributes[AttributeGPUVRAM])
+		}
+
+		if uint(vram) < g.VRAM {
+			return fmt.Errorf("not enough GPU memory (want: %v MiB, have: %v MiB)", g.VRAM, vram)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package execute
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGPURequirements_Match(t *testing.T) {
+
+	attributes := map[string]string{
+		AttributeGPUCount: "2",
+		AttributeGPUModel: "NVIDIA A100-SXM4-80GB",
+		AttributeGPUVRAM:  "81920",
+	}
+
+	require.NoError(t, GPURequirements{}.Match(attributes))
+	require.NoError(t, GPURequirements{Count: 2, Model: "a100", VRAM: 40960}.Match(attributes))
+
+	require.Error(t, GPURequirements{Count: 4}.Match(attributes))
+	require.Error(t, GPURequirements{Model: "H100"}.Match(attributes))
+	require.Error(t, GPURequirements{VRAM: 96000}.Match(attributes))
+
+	require.Error(t, GPURequirements{}.Match(map[string]string{}))
+	require.Error(t, GPURequirements{}.Match(map[string]string{AttributeGPUCount: "0"}))
+	require.Error(t, GPURequirements{VRAM: 1}.Match(map[string]string{AttributeGPUCount: "1"}))
+
+	var none *Attributes
+	require.False(t, none.RequiresGPU())
+	require.False(t, (&Attributes{}).RequiresGPU())
+	require.True(t, (&Attributes{GPU: &GPURequirements{}}).RequiresGPU())
+}
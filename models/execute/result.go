@@ -17,9 +17,22 @@ import (
 type NodeResult struct {
 	Result
 	// Signed digest of the response.
-	Signature string         `json:"signature,omitempty"`
-	PBFT      PBFTResultInfo `json:"pbft,omitempty"`
-	Metadata  any            `json:"metadata,omitempty"`
+	Signature string             `json:"signature,omitempty"`
+	PBFT      PBFTResultInfo     `json:"pbft,omitempty"`
+	HotStuff  HotStuffResultInfo `json:"hotstuff,omitempty"`
+	Metadata  any                `json:"metadata,omitempty"`
+	// Content hashes of the execution output.
+	Checksum *Checksum `json:"checksum,omitempty"`
+	// Time the worker spent waiting for and running the execution.
+	Timing *Timing `json:"timing,omitempty"`
+	// Nodes the execution request passed through, ending with the worker that produced the result.
+	Provenance Provenance `json:"provenance,omitempty"`
+	// Set by the head node for results that deviate strongly from previous executions of the function.
+	Anomalies []string `json:"anomalies,omitempty"`
+	// Tag derived from the watermark of the request, if the request has one.
+	Watermark string `json:"watermark,omitempty"`
+	// Cached is set if the worker served the result of an earlier identical execution, without executing the function.
+	Cached bool `json:"cached,omitempty"`
 }
 
 // Result describes an execution result.
@@ -27,12 +40,29 @@ type Result struct {
 	Code   codes.Code    `json:"code"`
 	Result RuntimeOutput `json:"result"`
 	Usage  Usage         `json:"usage,omitempty"`
+	// Reuse is set if the execution was done by a pooled runtime process.
+	Reuse *ProcessReuse `json:"reuse,omitempty"`
+	// Output files uploaded to blob storage.
+	Artifacts []Artifact `json:"artifacts,omitempty"`
+	// Output normalized according to the function manifest, for functions with normalization rules.
+	// Results are compared by the hash of the normalized output, while the output itself is returned as is.
+	Normalized *RuntimeOutput `json:"normalized,omitempty"`
 }
 
 // Cluster represents the set of peers that executed the request.
 type Cluster struct {
 	Main  peer.ID   `json:"main,omitempty"`
 	Peers []peer.ID `json:"peers,omitempty"`
+	// Peers whose results did not match the verified result, for executions with verification.
+	Mismatched []peer.ID `json:"mismatched,omitempty"`
+	// Roll call round-trip time of each peer, in milliseconds.
+	RollCallLatency map[string]int64 `json:"roll_call_latency_ms,omitempty"`
+	// Head node that ran the execution, if the request was delegated to a peer head node.
+	Delegate peer.ID `json:"delegate,omitempty"`
+	// Attempts made to execute the request, set only if some of them had to be retried or re-dispatched.
+	Attempts []Attempt `json:"attempts,omitempty"`
+	// Workers sampled from each stratum, for executions on a stratified sample of workers.
+	Strata map[string][]peer.ID `json:"strata,omitempty"`
 }
 
 // RuntimeOutput describes the output produced by the Blockless Runtime during execution.
@@ -57,6 +87,12 @@ type PBFTResultInfo struct {
 	Replica          peer.ID   `json:"replica,omitempty"`
 }
 
+type HotStuffResultInfo struct {
+	View             uint      `json:"view"`
+	RequestTimestamp time.Time `json:"request_timestamp,omitempty"`
+	Replica          peer.ID   `json:"replica,omitempty"`
+}
+
 // ResultMap contains execution results from multiple peers.
 type ResultMap map[peer.ID]NodeResult
 
@@ -80,6 +116,7 @@ func (r *NodeResult) Sign(key crypto.PrivKey) error {
 	cp := *r
 	// Exclude some of the fields from the signature.
 	cp.Signature = ""
+	cp.Anomalies = nil
 
 	payload, err := json.Marshal(cp)
 	if err != nil {
@@ -100,6 +137,7 @@ func (r NodeResult) VerifySignature(key crypto.PubKey) error {
 	cp := r
 	// Exclude some of the fields from the signature.
 	cp.Signature = ""
+	cp.Anomalies = nil
 
 	payload, err := json.Marshal(cp)
 	if err != nil {
@@ -14,8 +14,9 @@ type BLSRuntimeConfig struct {
 	Logger          string `json:"runtime_logger,omitempty"`
 	DriversRootPath string `json:"drivers_root_path,omitempty"`
 	// Fields not allowed to be set in the request.
-	Input  string `json:"-"`
-	FSRoot string `json:"-"`
+	Input       string `json:"-"`
+	FSRoot      string `json:"-"`
+	ModuleCache string `json:"-"`
 }
 
 const (
@@ -30,4 +31,7 @@ const (
 	BLSRuntimeFlagPermission    = "permission"
 	BLSRuntimeFlagEnv           = "env"
 	BLSRuntimeFlagDrivers       = "drivers-root-path"
+	// Flags of optional runtime features, used only if the runtime lists them in its help output.
+	BLSRuntimeFlagModuleCache = "module-cache-path"
+	BLSRuntimeFlagServe       = "serve"
 )
@@ -0,0 +1,93 @@
+package execute
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// MaxStrata is the maximum number of strata a sampled execution can be limited to.
+const MaxStrata = 64
+
+// Sampling describes an execution on a stratified sample of workers. Workers that reported for the roll call are grouped into
+// strata by the value of one of their attributes, such as region or hardware class, and the same number of workers from each
+// stratum executes the request. Results are grouped by stratum, for benchmarking and A/B measurements across the fleet.
+type Sampling struct {
+	// Attribute is the name of the worker attribute workers are grouped by. Workers without the attribute are not sampled.
+	Attribute string `json:"attribute"`
+
+	// PerStratum is the number of workers executing the request in each stratum, one if unset.
+	PerStratum int `json:"per_stratum,omitempty"`
+
+	// Strata optionally limits the sample to workers with these attribute values.
+	Strata []string `json:"strata,omitempty"`
+}
+
+// Valid checks if a sample of workers can be chosen as described.
+func (s Sampling) Valid() error {
+
+	if s.Attribute == "" {
+		return errors.New("attribute is required")
+	}
+
+	if s.PerStratum < 0 {
+		return errors.New("number of workers per stratum cannot be negative")
+	}
+
+	if len(s.Strata) > MaxStrata {
+		return fmt.Errorf("too many strata (have: %v, max: %v)", len(s.Strata), MaxStrata)
+	}
+
+	for i, stratum := range s.Strata {
+		if slices.Contains(s.Strata[:i], stratum) {
+			return fmt.Errorf("duplicate stratum: %s", stratum)
+		}
+	}
+
+	return nil
+}
+
+// Size returns the number of workers executing the request in each stratum.
+func (s Sampling) Size() int {
+	return max(s.PerStratum, 1)
+}
+
+// StratumResult holds the results of the workers sampled from a single stratum.
+type StratumResult struct {
+	Stratum string    `json:"stratum"`
+	Peers   []peer.ID `json:"peers,omitempty"`
+	Results ResultMap `json:"results,omitempty"`
+}
+
+// GroupByStratum splits the execution results by the strata the workers were sampled from. Strata are sorted by name,
+// and include the workers that failed to respond.
+func GroupByStratum(results ResultMap, strata map[string][]peer.ID) []StratumResult {
+
+	names := make([]string, 0, len(strata))
+	for name := range strata {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	grouped := make([]StratumResult, 0, len(names))
+	for _, name := range names {
+
+		stratum := StratumResult{
+			Stratum: name,
+			Peers:   strata[name],
+			Results: make(ResultMap),
+		}
+		for _, peer := range stratum.Peers {
+			res, ok := results[peer]
+			if ok {
+				stratum.Results[peer] = res
+			}
+		}
+
+		grouped = append(grouped, stratum)
+	}
+
+	return grouped
+}
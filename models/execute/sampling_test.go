@@ -0,0 +1,57 @@
+package execute
+
+import (
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+)
+
+func TestRequest_ValidSampling(t *testing.T) {
+
+	req := Request{
+		FunctionID: "dummy-function-id",
+		Method:     "dummy-method",
+		Config: Config{
+			Sampling: &Sampling{Attribute: "region", PerStratum: 2, Strata: []string{"eu", "us"}},
+		},
+	}
+	require.NoError(t, req.Valid())
+
+	invalid := []Sampling{
+		{},
+		{Attribute: "region", PerStratum: -1},
+		{Attribute: "region", Strata: []string{"eu", "eu"}},
+	}
+	for _, sampling := range invalid {
+		req.Config.Sampling = &sampling
+		require.Error(t, req.Valid(), sampling)
+	}
+
+	req.Config.Sampling = &Sampling{Attribute: "region"}
+	req.Config.NodeCount = 3
+	require.Error(t, req.Valid())
+}
+
+func TestGroupByStratum(t *testing.T) {
+
+	var (
+		eu      = peer.ID("eu-worker")
+		us      = peer.ID("us-worker")
+		failed  = peer.ID("failed-worker")
+		results = ResultMap{
+			eu: {Result: Result{Code: codes.OK}},
+			us: {Result: Result{Code: codes.Error}},
+		}
+	)
+
+	grouped := GroupByStratum(results, map[string][]peer.ID{"us": {us, failed}, "eu": {eu}})
+	require.Equal(t, []StratumResult{
+		{Stratum: "eu", Peers: []peer.ID{eu}, Results: ResultMap{eu: results[eu]}},
+		{Stratum: "us", Peers: []peer.ID{us, failed}, Results: ResultMap{us: results[us]}},
+	}, grouped)
+
+	require.Empty(t, GroupByStratum(results, nil))
+}
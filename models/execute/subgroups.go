@@ -0,0 +1,24 @@
+package execute
+
+import (
+	"github.com/blocklessnetwork/b7s/models/codes"
+)
+
+// SubgroupTarget describes one of the subgroups an execution is dispatched to.
+type SubgroupTarget struct {
+	Topic string `json:"topic"`
+	// NodeCount specifies how many nodes in the subgroup should execute the request. If unset, the node count from the request is used.
+	NodeCount int `json:"number_of_nodes,omitempty"`
+}
+
+// SubgroupResult is the outcome of the execution in a single subgroup, when the execution is dispatched to multiple subgroups.
+type SubgroupResult struct {
+	Topic     string     `json:"topic"`
+	Code      codes.Code `json:"code"`
+	RequestID string     `json:"request_id,omitempty"`
+	Results   ResultMap  `json:"results,omitempty"`
+	Cluster   Cluster    `json:"cluster,omitempty"`
+	Timing    Timing     `json:"timing,omitempty"`
+	// Message has more info about the error, if the execution in this subgroup failed.
+	Message string `json:"message,omitempty"`
+}
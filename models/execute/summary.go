@@ -0,0 +1,49 @@
+package execute
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+)
+
+// ResultSummary describes the results of an execution without the outputs, so responses for executions on many peers stay small.
+// Full results of individual peers can be retrieved from the head node using the summary ID.
+type ResultSummary struct {
+	ID    string              `json:"id"`
+	Peers []PeerResultSummary `json:"peers,omitempty"`
+}
+
+// PeerResultSummary describes the result of a single peer.
+type PeerResultSummary struct {
+	Peer     peer.ID    `json:"peer"`
+	Code     codes.Code `json:"code"`
+	Checksum string     `json:"checksum"` // Hash of the complete runtime output. Results with the same checksum are identical.
+	Size     int        `json:"size"`     // Combined size of stdout and stderr, in bytes.
+}
+
+// Summarize creates a summary of the execution results, sorted by peer ID.
+func Summarize(id string, results ResultMap) ResultSummary {
+
+	summary := ResultSummary{
+		ID:    id,
+		Peers: make([]PeerResultSummary, 0, len(results)),
+	}
+
+	for peer, res := range results {
+		summary.Peers = append(summary.Peers, PeerResultSummary{
+			Peer:     peer,
+			Code:     res.Code,
+			Checksum: res.OutputChecksum(),
+			Size:     len(res.Result.Result.Stdout) + len(res.Result.Result.Stderr),
+		})
+	}
+
+	slices.SortFunc(summary.Peers, func(a, b PeerResultSummary) int {
+		return strings.Compare(a.Peer.String(), b.Peer.String())
+	})
+
+	return summary
+}
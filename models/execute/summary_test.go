@@ -0,0 +1,38 @@
+package execute
+
+import (
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+)
+
+func TestSummarize(t *testing.T) {
+
+	var (
+		first  = peer.ID("first-peer")
+		second = peer.ID("second-peer")
+
+		output = RuntimeOutput{Stdout: "dummy-stdout", Stderr: "err"}
+	)
+
+	results := ResultMap{
+		second: NodeResult{Result: Result{Code: codes.Error}},
+		first:  NodeResult{Result: Result{Code: codes.OK, Result: output}},
+	}
+
+	summary := Summarize("dummy-request-id", results)
+	require.Equal(t, "dummy-request-id", summary.ID)
+	require.Len(t, summary.Peers, 2)
+
+	require.Equal(t, first, summary.Peers[0].Peer)
+	require.Equal(t, codes.OK, summary.Peers[0].Code)
+	require.Equal(t, output.Checksum().Output, summary.Peers[0].Checksum)
+	require.Equal(t, len(output.Stdout)+len(output.Stderr), summary.Peers[0].Size)
+
+	require.Equal(t, second, summary.Peers[1].Peer)
+	require.Equal(t, codes.Error, summary.Peers[1].Code)
+	require.Zero(t, summary.Peers[1].Size)
+}
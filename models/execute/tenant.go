@@ -0,0 +1,16 @@
+package execute
+
+import (
+	"regexp"
+)
+
+// MaxTenantLength is the maximum length of a tenant ID.
+const MaxTenantLength = 64
+
+// Tenant IDs are used as directory names on workers, so they are limited to characters safe for that.
+var tenantPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ValidTenant reports whether the tenant ID can be used for an execution request.
+func ValidTenant(tenant string) bool {
+	return len(tenant) <= MaxTenantLength && tenantPattern.MatchString(tenant)
+}
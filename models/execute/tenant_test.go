@@ -0,0 +1,27 @@
+package execute
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequest_ValidTenant(t *testing.T) {
+
+	req := Request{
+		FunctionID: "dummy-function-id",
+		Method:     "dummy-method",
+	}
+	require.NoError(t, req.Valid())
+
+	for _, tenant := range []string{"acme", "acme-corp.eu_1", "0"} {
+		req.Config.Tenant = tenant
+		require.NoError(t, req.Valid(), tenant)
+	}
+
+	for _, tenant := range []string{".", "..", "../acme", "acme/eu", "-acme", "acme corp", strings.Repeat("a", MaxTenantLength+1)} {
+		req.Config.Tenant = tenant
+		require.Error(t, req.Valid(), tenant)
+	}
+}
@@ -0,0 +1,22 @@
+package execute
+
+import (
+	"time"
+)
+
+// Timing describes how long the phases of an execution took, in milliseconds.
+// Head node reports roll call, cluster formation and result gathering. Workers report queue wait and execution.
+type Timing struct {
+	RollCall         int64 `json:"roll_call_ms,omitempty"`
+	ClusterFormation int64 `json:"cluster_formation_ms,omitempty"`
+	QueueWait        int64 `json:"queue_wait_ms,omitempty"`
+	Execution        int64 `json:"execution_ms,omitempty"`
+	ResultGather     int64 `json:"result_gather_ms,omitempty"`
+	// For deferred executions, how late the execution was dispatched, compared to its not-before time.
+	SchedulingDrift int64 `json:"scheduling_drift_ms,omitempty"`
+}
+
+// Since returns the number of milliseconds elapsed since the given time.
+func Since(t time.Time) int64 {
+	return time.Since(t).Milliseconds()
+}
@@ -0,0 +1,68 @@
+package execute
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"slices"
+)
+
+// WatermarkEnvName is the name of the environment variable in which the execution watermark is passed to the function.
+const WatermarkEnvName = "B7S_WATERMARK"
+
+// WithWatermark returns the request with the watermark added to the execution environment, if the request has one.
+// The original request is not modified.
+func (r Request) WithWatermark() Request {
+
+	if r.Config.Watermark == "" {
+		return r
+	}
+
+	env := slices.DeleteFunc(slices.Clone(r.Config.Environment), func(v EnvVar) bool {
+		return v.Name == WatermarkEnvName
+	})
+	r.Config.Environment = append(env, EnvVar{Name: WatermarkEnvName, Value: r.Config.Watermark})
+
+	return r
+}
+
+// WatermarkTag returns the tag workers stamp results of the request with. Tags are bound to the request,
+// so results of other executions - of the same or other tenants - cannot be passed off as results of this one.
+// Tag is empty if there is no watermark.
+func WatermarkTag(watermark string, requestID string) string {
+
+	if watermark == "" {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, []byte(watermark))
+	mac.Write([]byte(requestID))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWatermark checks that the result was produced by an execution of the request with the given watermark.
+// Results of requests without a watermark are not checked.
+func (r NodeResult) VerifyWatermark(watermark string, requestID string) error {
+
+	if watermark == "" {
+		return nil
+	}
+
+	if r.Watermark == "" {
+		return errors.New("result is missing the watermark")
+	}
+
+	tag, err := hex.DecodeString(r.Watermark)
+	if err != nil {
+		return errors.New("could not decode watermark from hex")
+	}
+
+	want, _ := hex.DecodeString(WatermarkTag(watermark, requestID))
+	if !hmac.Equal(tag, want) {
+		return errors.New("watermark does not match the request")
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package execute
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequest_WithWatermark(t *testing.T) {
+
+	t.Run("watermark is added to the environment", func(t *testing.T) {
+
+		req := Request{
+			Config: Config{
+				Environment: []EnvVar{{Name: "FOO", Value: "bar"}, {Name: WatermarkEnvName, Value: "spoofed"}},
+				Watermark:   "dummy-watermark",
+			},
+		}
+
+		marked := req.WithWatermark()
+		require.Equal(t, []EnvVar{{Name: "FOO", Value: "bar"}, {Name: WatermarkEnvName, Value: "dummy-watermark"}}, marked.Config.Environment)
+
+		// Original request is not modified.
+		require.Equal(t, "spoofed", req.Config.Environment[1].Value)
+	})
+	t.Run("requests without a watermark are unchanged", func(t *testing.T) {
+
+		req := Request{Config: Config{Environment: []EnvVar{{Name: "FOO", Value: "bar"}}}}
+		require.Equal(t, req, req.WithWatermark())
+	})
+}
+
+func TestNodeResult_VerifyWatermark(t *testing.T) {
+
+	const (
+		watermark = "dummy-watermark"
+		requestID = "dummy-request-id"
+	)
+
+	res := NodeResult{Watermark: WatermarkTag(watermark, requestID)}
+	require.NoError(t, res.VerifyWatermark(watermark, requestID))
+
+	// Requests without a watermark are not checked.
+	require.Empty(t, WatermarkTag("", requestID))
+	require.NoError(t, NodeResult{}.VerifyWatermark("", requestID))
+
+	require.Error(t, NodeResult{}.VerifyWatermark(watermark, requestID))
+	require.Error(t, res.VerifyWatermark("another-watermark", requestID))
+	require.Error(t, res.VerifyWatermark(watermark, "another-request-id"))
+	require.Error(t, NodeResult{Watermark: "not-hex"}.VerifyWatermark(watermark, requestID))
+}
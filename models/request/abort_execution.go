@@ -0,0 +1,40 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+)
+
+var _ (json.Marshaler) = (*AbortExecution)(nil)
+
+// AbortExecution describes the `MessageAbortExecution` request payload.
+// It is sent by the head node to workers executing a request the client abandoned.
+type AbortExecution struct {
+	blockless.BaseMessage
+	RequestID string `json:"request_id"`
+}
+
+func (AbortExecution) Type() string { return blockless.MessageAbortExecution }
+
+func (a AbortExecution) MarshalJSON() ([]byte, error) {
+	type Alias AbortExecution
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(a),
+		Type:  a.Type(),
+	}
+	return json.Marshal(rec)
+}
+
+func (a AbortExecution) Valid() error {
+
+	if a.RequestID == "" {
+		return errors.New("request ID is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+var _ (json.Marshaler) = (*CancelExecution)(nil)
+
+// CancelExecution describes the `MessageCancelExecution` request payload.
+// It asks the head node to cancel a deferred execution that has not been dispatched yet.
+type CancelExecution struct {
+	blockless.BaseMessage
+	RequestID string `json:"request_id"`
+}
+
+func (c CancelExecution) Response(code codes.Code) *response.CancelExecution {
+	return &response.CancelExecution{
+		BaseMessage: blockless.BaseMessage{TraceInfo: c.TraceInfo},
+		RequestID:   c.RequestID,
+		Code:        code,
+	}
+}
+
+func (CancelExecution) Type() string { return blockless.MessageCancelExecution }
+
+func (c CancelExecution) MarshalJSON() ([]byte, error) {
+	type Alias CancelExecution
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(c),
+		Type:  c.Type(),
+	}
+	return json.Marshal(rec)
+}
+
+func (c CancelExecution) Valid() error {
+
+	if c.RequestID == "" {
+		return errors.New("request ID is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+var _ (json.Marshaler) = (*DelegateExecution)(nil)
+
+// DelegateExecution describes the `MessageDelegateExecution` request payload.
+// It is sent by an overloaded head node, asking a peer head node to run the whole execution on its behalf.
+type DelegateExecution struct {
+	blockless.BaseMessage
+
+	execute.Request // execute request is embedded.
+
+	RequestID string        `json:"request_id"`
+	Topic     string        `json:"topic,omitempty"`
+	Remaining time.Duration `json:"remaining,omitempty"` // Remaining is how long the delegating head node keeps waiting for the results, from the time it sent the request.
+
+	// Provenance lists the nodes the request passed through, ending with the head node that delegated it.
+	Provenance execute.Provenance `json:"provenance"`
+}
+
+func (d DelegateExecution) Response(c codes.Code) *response.DelegateExecution {
+	return &response.DelegateExecution{
+		BaseMessage: blockless.BaseMessage{TraceInfo: d.TraceInfo},
+		RequestID:   d.RequestID,
+		Code:        c,
+	}
+}
+
+func (DelegateExecution) Type() string { return blockless.MessageDelegateExecution }
+
+func (d DelegateExecution) MarshalJSON() ([]byte, error) {
+	type Alias DelegateExecution
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(d),
+		Type:  d.Type(),
+	}
+	return json.Marshal(rec)
+}
+
+func (d DelegateExecution) Valid() error {
+
+	if d.RequestID == "" {
+		return errors.New("request ID is required")
+	}
+
+	err := d.Request.Valid()
+	if err != nil {
+		return err
+	}
+
+	if len(d.Provenance) == 0 {
+		return errors.New("provenance is required")
+	}
+
+	if len(d.Provenance) > execute.MaxProvenanceHops {
+		return fmt.Errorf("provenance chain too long (have: %v, max: %v)", len(d.Provenance), execute.MaxProvenanceHops)
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package request
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+var _ (json.Marshaler) = (*Drain)(nil)
+
+// Drain describes the `MessageDrain` request payload.
+// It asks the worker node to stop answering roll calls and finish the work in progress, ahead of a shutdown.
+// The response is sent once the worker is idle and ready to be shut down.
+type Drain struct {
+	blockless.BaseMessage
+}
+
+func (d Drain) Response(c codes.Code) *response.Drain {
+	return &response.Drain{
+		BaseMessage: blockless.BaseMessage{TraceInfo: d.TraceInfo},
+		Code:        c,
+	}
+}
+
+func (Drain) Type() string { return blockless.MessageDrain }
+
+func (d Drain) MarshalJSON() ([]byte, error) {
+	type Alias Drain
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(d),
+		Type:  d.Type(),
+	}
+	return json.Marshal(rec)
+}
@@ -2,11 +2,12 @@ package request
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/blocklessnetwork/b7s/consensus"
-	"github.com/blocklessnetwork/b7s/consensus/pbft"
 	"github.com/blocklessnetwork/b7s/models/blockless"
 	"github.com/blocklessnetwork/b7s/models/codes"
 	"github.com/blocklessnetwork/b7s/models/execute"
@@ -25,6 +26,27 @@ type Execute struct {
 	Topic     string    `json:"topic,omitempty"`
 	RequestID string    `json:"request_id,omitempty"` // RequestID may be set initially, if the execution request is relayed via roll-call.
 	Timestamp time.Time `json:"timestamp,omitempty"`  // Execution request timestamp is a factor for PBFT.
+	Stream    bool      `json:"stream,omitempty"`     // Stream requests that results are forwarded to the client as they arrive.
+
+	// Remaining is how long the sender keeps waiting for the results, from the time it sent the request. It is relative,
+	// so it does not depend on the clocks of the sender and the receiver being in sync. Zero means no limit.
+	Remaining time.Duration `json:"remaining,omitempty"`
+
+	// Async requests are acknowledged right away, and the results are delivered once the execution is done.
+	Async bool `json:"async,omitempty"`
+	// Callback is the HTTP URL the results of an asynchronous execution are posted to. If not set, results are sent to the requesting peer.
+	Callback string `json:"callback,omitempty"`
+
+	// Summary requests a summary of the results instead of the full results, for executions on many peers.
+	// Full results of individual peers are retrieved with the `MessageExecutionResult` message, by the requesting peer
+	// and for a limited time.
+	Summary bool `json:"summary,omitempty"`
+
+	// DataAddresses are the addresses of the head node data host. If set, workers send execution results there.
+	DataAddresses []string `json:"data_addresses,omitempty"`
+
+	// Provenance lists the nodes the request passed through. Each node appends its own signed hop before passing the request on.
+	Provenance execute.Provenance `json:"provenance,omitempty"`
 }
 
 func (e Execute) Response(c codes.Code) *response.Execute {
@@ -35,6 +57,15 @@ func (e Execute) Response(c codes.Code) *response.Execute {
 	}
 }
 
+func (e Execute) PartialResponse(results execute.ResultMap, received uint) *response.ExecutePartial {
+	return &response.ExecutePartial{
+		BaseMessage: blockless.BaseMessage{TraceInfo: e.TraceInfo},
+		RequestID:   e.RequestID,
+		Results:     results,
+		Received:    received,
+	}
+}
+
 func (Execute) Type() string { return blockless.MessageExecute }
 
 func (e Execute) MarshalJSON() ([]byte, error) {
@@ -62,12 +93,52 @@ func (e Execute) Valid() error {
 		multierr = multierror.Append(multierr, fmt.Errorf("could not parse consensus algorithm: %w", err))
 	}
 
-	if c == consensus.PBFT &&
-		e.Config.NodeCount > 0 &&
-		e.Config.NodeCount < pbft.MinimumReplicaCount {
+	minReplicas := consensus.MinimumReplicas(c)
+	if e.Config.NodeCount > 0 && e.Config.NodeCount < minReplicas {
+		multierr = multierror.Append(multierr, fmt.Errorf("minimum %v nodes needed for %s consensus", minReplicas, c))
+	}
+
+	if e.RequestID != "" {
+		err = validRequestID(e.RequestID)
+		if err != nil {
+			multierr = multierror.Append(multierr, err)
+		}
+	}
+
+	// Signatures are verified by the receiving node, which knows who sent the request.
+	if len(e.Provenance) > execute.MaxProvenanceHops {
+		multierr = multierror.Append(multierr, fmt.Errorf("provenance chain too long (have: %v, max: %v)", len(e.Provenance), execute.MaxProvenanceHops))
+	}
+
+	if e.Callback != "" {
+		err = ValidCallback(e.Callback)
+		if err != nil {
+			multierr = multierror.Append(multierr, err)
+		}
 
-		multierr = multierror.Append(multierr, fmt.Errorf("minimum %v nodes needed for PBFT consensus", pbft.MinimumReplicaCount))
+		if !e.Async {
+			multierr = multierror.Append(multierr, errors.New("callback is only supported for asynchronous executions"))
+		}
 	}
 
 	return multierr.ErrorOrNil()
 }
+
+// ValidCallback checks that the callback is an HTTP URL.
+func ValidCallback(callback string) error {
+
+	u, err := url.Parse(callback)
+	if err != nil {
+		return fmt.Errorf("could not parse callback URL: %w", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported callback URL scheme (have: %s)", u.Scheme)
+	}
+
+	if u.Host == "" {
+		return errors.New("callback URL must have a host")
+	}
+
+	return nil
+}
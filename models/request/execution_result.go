@@ -0,0 +1,58 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+var _ (json.Marshaler) = (*ExecutionResult)(nil)
+
+// ExecutionResult describes the `MessageExecutionResult` request payload.
+// It retrieves the full result of a single peer for an execution whose response included only a result summary.
+type ExecutionResult struct {
+	blockless.BaseMessage
+	ID   string  `json:"id"` // ID of the result summary.
+	Peer peer.ID `json:"peer"`
+}
+
+func (e ExecutionResult) Response(code codes.Code) *response.ExecutionResult {
+	return &response.ExecutionResult{
+		BaseMessage: blockless.BaseMessage{TraceInfo: e.TraceInfo},
+		ID:          e.ID,
+		Peer:        e.Peer,
+		Code:        code,
+	}
+}
+
+func (ExecutionResult) Type() string { return blockless.MessageExecutionResult }
+
+func (e ExecutionResult) MarshalJSON() ([]byte, error) {
+	type Alias ExecutionResult
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(e),
+		Type:  e.Type(),
+	}
+	return json.Marshal(rec)
+}
+
+func (e ExecutionResult) Valid() error {
+
+	if e.ID == "" {
+		return errors.New("result summary ID is required")
+	}
+
+	if e.Peer == "" {
+		return errors.New("peer is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/node/head/fleet"
+)
+
+var _ (json.Marshaler) = (*FleetPlan)(nil)
+
+// FleetPlan describes the `MessageFleetPlan` request payload.
+// It asks the head node how it would execute the function, based on the fleet reports of workers, without executing it.
+type FleetPlan struct {
+	blockless.BaseMessage
+	FunctionID string `json:"function_id"`
+	NodeCount  int    `json:"node_count,omitempty"` // Zero means a single worker, -1 means any number of workers.
+	Topic      string `json:"topic,omitempty"`      // Subgroup the function would be executed in. Empty means the default topic.
+}
+
+func (f FleetPlan) Response(c codes.Code, plan fleet.Plan) *response.FleetPlan {
+	return &response.FleetPlan{
+		BaseMessage: blockless.BaseMessage{TraceInfo: f.TraceInfo},
+		Code:        c,
+		Plan:        plan,
+	}
+}
+
+func (FleetPlan) Type() string { return blockless.MessageFleetPlan }
+
+func (f FleetPlan) MarshalJSON() ([]byte, error) {
+	type Alias FleetPlan
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(f),
+		Type:  f.Type(),
+	}
+	return json.Marshal(rec)
+}
+
+func (f FleetPlan) Valid() error {
+
+	if f.FunctionID == "" {
+		return errors.New("function ID is required")
+	}
+
+	if f.NodeCount < -1 {
+		return errors.New("invalid node count")
+	}
+
+	return nil
+}
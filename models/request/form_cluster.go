@@ -2,6 +2,7 @@ package request
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/libp2p/go-libp2p/core/peer"
 
@@ -21,6 +22,9 @@ type FormCluster struct {
 	Peers          []peer.ID       `json:"peers,omitempty"`
 	Consensus      consensus.Type  `json:"consensus,omitempty"`
 	ConnectionInfo []peer.AddrInfo `json:"connection_info,omitempty"`
+
+	// ViewChangeTimeout is the inactivity period before replicas trigger a view change. Only used for PBFT clusters.
+	ViewChangeTimeout time.Duration `json:"view_change_timeout,omitempty"`
 }
 
 func (f FormCluster) Response(c codes.Code) *response.FormCluster {
@@ -0,0 +1,41 @@
+package request
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s-attributes/attributes"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+var _ (json.Marshaler) = (*HealthQuery)(nil)
+
+// HealthQuery describes the `MessageHealthQuery` request payload.
+// It is sent by the head node on startup, to check which of the workers it knows of are reachable.
+type HealthQuery struct {
+	blockless.BaseMessage
+}
+
+func (h HealthQuery) Response(c codes.Code, role blockless.NodeRole, attributes *attributes.Attestation) *response.HealthQuery {
+	return &response.HealthQuery{
+		BaseMessage: blockless.BaseMessage{TraceInfo: h.TraceInfo},
+		Code:        c,
+		Role:        role,
+		Attributes:  attributes,
+	}
+}
+
+func (HealthQuery) Type() string { return blockless.MessageHealthQuery }
+
+func (h HealthQuery) MarshalJSON() ([]byte, error) {
+	type Alias HealthQuery
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(h),
+		Type:  h.Type(),
+	}
+	return json.Marshal(rec)
+}
@@ -0,0 +1,63 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+var _ (json.Marshaler) = (*InteractiveExecution)(nil)
+
+// InteractiveExecution describes the `MessageInteractiveExecution` request payload. It is the first line sent on an
+// interactive execution stream, followed by interactive frames carrying the standard input and output of the function.
+type InteractiveExecution struct {
+	blockless.BaseMessage
+
+	execute.Request // execute request is embedded.
+
+	Topic     string `json:"topic,omitempty"`
+	RequestID string `json:"request_id,omitempty"` // RequestID is set by the head node when relaying the request to the worker.
+}
+
+func (InteractiveExecution) Type() string { return blockless.MessageInteractiveExecution }
+
+func (e InteractiveExecution) MarshalJSON() ([]byte, error) {
+	type Alias InteractiveExecution
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(e),
+		Type:  e.Type(),
+	}
+	return json.Marshal(rec)
+}
+
+func (e InteractiveExecution) Valid() error {
+
+	err := e.Request.Valid()
+	if err != nil {
+		return err
+	}
+
+	if e.RequestID != "" {
+		err = validRequestID(e.RequestID)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Standard input and output of the function are attached to the stream of a single worker.
+	cfg := e.Config
+	if cfg.ConsensusAlgorithm != "" || cfg.NodeCount > 1 || cfg.Quorum > 1 || cfg.Verify || cfg.Sampling != nil {
+		return errors.New("interactive executions run on a single worker")
+	}
+
+	if cfg.Stdin != nil {
+		return errors.New("standard input of interactive executions is sent on the stream")
+	}
+
+	return nil
+}
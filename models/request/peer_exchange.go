@@ -0,0 +1,38 @@
+package request
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+var _ (json.Marshaler) = (*PeerExchange)(nil)
+
+// PeerExchange describes the `MessagePeerExchange` request payload.
+// It is sent to connected peers to learn about healthy workers they know of.
+type PeerExchange struct {
+	blockless.BaseMessage
+	Limit uint `json:"limit,omitempty"` // Maximum number of peers to return.
+}
+
+func (p PeerExchange) Response(peers []response.ExchangedPeer) *response.PeerExchange {
+	return &response.PeerExchange{
+		BaseMessage: blockless.BaseMessage{TraceInfo: p.TraceInfo},
+		Peers:       peers,
+	}
+}
+
+func (PeerExchange) Type() string { return blockless.MessagePeerExchange }
+
+func (p PeerExchange) MarshalJSON() ([]byte, error) {
+	type Alias PeerExchange
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(p),
+		Type:  p.Type(),
+	}
+	return json.Marshal(rec)
+}
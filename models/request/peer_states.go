@@ -0,0 +1,39 @@
+package request
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+var _ (json.Marshaler) = (*PeerStates)(nil)
+
+// PeerStates describes the `MessagePeerStates` request payload.
+// It asks the head node for the state of known peers, including the load workers reported in their health pings.
+type PeerStates struct {
+	blockless.BaseMessage
+}
+
+func (p PeerStates) Response(c codes.Code, peers []response.PeerState) *response.PeerStates {
+	return &response.PeerStates{
+		BaseMessage: blockless.BaseMessage{TraceInfo: p.TraceInfo},
+		Code:        c,
+		Peers:       peers,
+	}
+}
+
+func (PeerStates) Type() string { return blockless.MessagePeerStates }
+
+func (p PeerStates) MarshalJSON() ([]byte, error) {
+	type Alias PeerStates
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(p),
+		Type:  p.Type(),
+	}
+	return json.Marshal(rec)
+}
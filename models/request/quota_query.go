@@ -0,0 +1,40 @@
+package request
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+var _ (json.Marshaler) = (*QuotaQuery)(nil)
+
+// QuotaQuery describes the `MessageQuotaQuery` request payload.
+// It asks the head node for the quota the sender has left.
+type QuotaQuery struct {
+	blockless.BaseMessage
+}
+
+func (q QuotaQuery) Response(c codes.Code, remaining execute.QuotaRemaining) *response.QuotaQuery {
+	return &response.QuotaQuery{
+		BaseMessage: blockless.BaseMessage{TraceInfo: q.TraceInfo},
+		Code:        c,
+		Remaining:   remaining,
+	}
+}
+
+func (QuotaQuery) Type() string { return blockless.MessageQuotaQuery }
+
+func (q QuotaQuery) MarshalJSON() ([]byte, error) {
+	type Alias QuotaQuery
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(q),
+		Type:  q.Type(),
+	}
+	return json.Marshal(rec)
+}
@@ -0,0 +1,40 @@
+package request
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/node/head/recovery"
+)
+
+var _ (json.Marshaler) = (*RecoveryReport)(nil)
+
+// RecoveryReport describes the `MessageRecoveryReport` request payload.
+// It asks the head node for the report on the state it found on startup.
+type RecoveryReport struct {
+	blockless.BaseMessage
+}
+
+func (r RecoveryReport) Response(c codes.Code, report recovery.Report) *response.RecoveryReport {
+	return &response.RecoveryReport{
+		BaseMessage: blockless.BaseMessage{TraceInfo: r.TraceInfo},
+		Code:        c,
+		Report:      report,
+	}
+}
+
+func (RecoveryReport) Type() string { return blockless.MessageRecoveryReport }
+
+func (r RecoveryReport) MarshalJSON() ([]byte, error) {
+	type Alias RecoveryReport
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(r),
+		Type:  r.Type(),
+	}
+	return json.Marshal(rec)
+}
@@ -0,0 +1,24 @@
+package request
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxRequestIDLength is the maximum length of a request ID.
+const maxRequestIDLength = 128
+
+// validRequestID checks if the request ID can be used as a file or cgroup name. Request IDs are assigned by head nodes,
+// but workers use them to name the working directories and cgroups of executions.
+func validRequestID(id string) error {
+
+	if len(id) > maxRequestIDLength {
+		return fmt.Errorf("request ID too long (have: %v, max: %v)", len(id), maxRequestIDLength)
+	}
+
+	if id == "." || id == ".." || strings.ContainsAny(id, "/\\\x00") {
+		return fmt.Errorf("invalid request ID (id: %q)", id)
+	}
+
+	return nil
+}
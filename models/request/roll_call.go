@@ -2,6 +2,7 @@ package request
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/libp2p/go-libp2p/core/peer"
 
@@ -22,6 +23,20 @@ type RollCall struct {
 	RequestID  string              `json:"request_id,omitempty"`
 	Consensus  consensus.Type      `json:"consensus"`
 	Attributes *execute.Attributes `json:"attributes,omitempty"`
+	Subgroup   string              `json:"subgroup,omitempty"` // Subgroup the roll call was published in.
+	// Probability, if set, is the chance with which workers answer the roll call. Workers self-select,
+	// so that head nodes are not flooded with responses on large topics. All workers answer if it is not set.
+	Probability float64 `json:"probability,omitempty"`
+}
+
+// Valid checks if the roll call is correct.
+func (r RollCall) Valid() error {
+
+	if r.Probability < 0 || r.Probability > 1 {
+		return fmt.Errorf("roll call probability must be between 0 and 1 (have: %v)", r.Probability)
+	}
+
+	return nil
 }
 
 func (r RollCall) Response(c codes.Code) *response.RollCall {
@@ -0,0 +1,66 @@
+package request
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/node/head/schedule"
+)
+
+var _ (json.Marshaler) = (*ScheduleExecution)(nil)
+
+// ScheduleExecution describes the `MessageScheduleExecution` request payload.
+// It asks the head node to execute the request periodically, according to the cron expression.
+type ScheduleExecution struct {
+	blockless.BaseMessage
+
+	execute.Request // execute request is embedded.
+
+	Cron      string `json:"cron"`
+	Topic     string `json:"topic,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+func (s ScheduleExecution) Response(c codes.Code) *response.ScheduleExecution {
+	return &response.ScheduleExecution{
+		BaseMessage: blockless.BaseMessage{TraceInfo: s.TraceInfo},
+		RequestID:   s.RequestID,
+		Code:        c,
+	}
+}
+
+func (ScheduleExecution) Type() string { return blockless.MessageScheduleExecution }
+
+func (s ScheduleExecution) MarshalJSON() ([]byte, error) {
+	type Alias ScheduleExecution
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(s),
+		Type:  s.Type(),
+	}
+	return json.Marshal(rec)
+}
+
+func (s ScheduleExecution) Valid() error {
+
+	var multierr *multierror.Error
+	err := s.Request.Valid()
+	if err != nil {
+		multierr = multierror.Append(multierr, err)
+	}
+
+	_, err = schedule.Parse(s.Cron)
+	if err != nil {
+		multierr = multierror.Append(multierr, fmt.Errorf("could not parse cron expression: %w", err))
+	}
+
+	return multierr.ErrorOrNil()
+}
@@ -0,0 +1,43 @@
+package request
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+var _ (json.Marshaler) = (*SelfTest)(nil)
+
+// SelfTest describes the `MessageSelfTest` request payload.
+// It asks the worker node to verify it is able to serve executions. If a function is set, the worker installs and executes it,
+// and removes it afterwards unless it was already installed. Without a function, runtime and function store checks are skipped.
+type SelfTest struct {
+	blockless.BaseMessage
+	FunctionID  string `json:"function_id,omitempty"`
+	Method      string `json:"method,omitempty"`
+	ManifestURL string `json:"manifest_url,omitempty"`
+}
+
+func (s SelfTest) Response(c codes.Code, report response.SelfTestReport) *response.SelfTest {
+	return &response.SelfTest{
+		BaseMessage: blockless.BaseMessage{TraceInfo: s.TraceInfo},
+		Code:        c,
+		Report:      report,
+	}
+}
+
+func (SelfTest) Type() string { return blockless.MessageSelfTest }
+
+func (s SelfTest) MarshalJSON() ([]byte, error) {
+	type Alias SelfTest
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(s),
+		Type:  s.Type(),
+	}
+	return json.Marshal(rec)
+}
@@ -0,0 +1,59 @@
+package request
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+var _ (json.Marshaler) = (*StoreMaintenance)(nil)
+
+// StoreOperation is the maintenance operation the head node runs on its database.
+type StoreOperation string
+
+const (
+	StoreCompact StoreOperation = "compact" // Compact the database.
+	StoreBackup  StoreOperation = "backup"  // Create a snapshot of the database in the backup store.
+)
+
+// StoreMaintenance describes the `MessageStoreMaintenance` request payload.
+// It asks the head node to run a maintenance operation on the database backing its stores.
+type StoreMaintenance struct {
+	blockless.BaseMessage
+	Operation StoreOperation `json:"operation"`
+}
+
+func (s StoreMaintenance) Response(c codes.Code) *response.StoreMaintenance {
+	return &response.StoreMaintenance{
+		BaseMessage: blockless.BaseMessage{TraceInfo: s.TraceInfo},
+		Code:        c,
+		Operation:   string(s.Operation),
+	}
+}
+
+func (StoreMaintenance) Type() string { return blockless.MessageStoreMaintenance }
+
+func (s StoreMaintenance) MarshalJSON() ([]byte, error) {
+	type Alias StoreMaintenance
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(s),
+		Type:  s.Type(),
+	}
+	return json.Marshal(rec)
+}
+
+func (s StoreMaintenance) Valid() error {
+
+	switch s.Operation {
+	case StoreCompact, StoreBackup:
+		return nil
+	default:
+		return fmt.Errorf("unknown store operation: %s", s.Operation)
+	}
+}
@@ -0,0 +1,64 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+var _ (json.Marshaler) = (*Subgroups)(nil)
+
+// Subgroups describes the `MessageSubgroups` request payload.
+// It asks the worker node to join or leave subgroups at runtime, without a restart. Topics are left after the ones to join are joined.
+type Subgroups struct {
+	blockless.BaseMessage
+	Join  []string `json:"join,omitempty"`
+	Leave []string `json:"leave,omitempty"`
+}
+
+func (s Subgroups) Response(c codes.Code) *response.Subgroups {
+	return &response.Subgroups{
+		BaseMessage: blockless.BaseMessage{TraceInfo: s.TraceInfo},
+		Code:        c,
+	}
+}
+
+func (Subgroups) Type() string { return blockless.MessageSubgroups }
+
+func (s Subgroups) MarshalJSON() ([]byte, error) {
+	type Alias Subgroups
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(s),
+		Type:  s.Type(),
+	}
+	return json.Marshal(rec)
+}
+
+func (s Subgroups) Valid() error {
+
+	if len(s.Join) == 0 && len(s.Leave) == 0 {
+		return errors.New("no subgroups to join or leave")
+	}
+
+	for _, topic := range slices.Concat(s.Join, s.Leave) {
+		if topic == "" {
+			return errors.New("subgroup name cannot be empty")
+		}
+	}
+
+	for _, topic := range s.Join {
+		if slices.Contains(s.Leave, topic) {
+			return fmt.Errorf("subgroup cannot be both joined and left (subgroup: %s)", topic)
+		}
+	}
+
+	return nil
+}
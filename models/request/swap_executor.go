@@ -0,0 +1,58 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+var _ (json.Marshaler) = (*SwapExecutor)(nil)
+
+// SwapExecutor describes the `MessageSwapExecutor` request payload.
+// It asks the worker node to switch to an executor using a different runtime. Canary request is executed
+// using the new executor before the switch - if it fails, the worker keeps using the current executor.
+type SwapExecutor struct {
+	blockless.BaseMessage
+	RuntimePath string          `json:"runtime_path"`
+	RuntimeCLI  string          `json:"runtime_cli,omitempty"`
+	Canary      execute.Request `json:"canary"`
+}
+
+func (s SwapExecutor) Response(c codes.Code) *response.SwapExecutor {
+	return &response.SwapExecutor{
+		BaseMessage: blockless.BaseMessage{TraceInfo: s.TraceInfo},
+		Code:        c,
+		RuntimePath: s.RuntimePath,
+	}
+}
+
+func (SwapExecutor) Type() string { return blockless.MessageSwapExecutor }
+
+func (s SwapExecutor) MarshalJSON() ([]byte, error) {
+	type Alias SwapExecutor
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(s),
+		Type:  s.Type(),
+	}
+	return json.Marshal(rec)
+}
+
+func (s SwapExecutor) Valid() error {
+
+	if s.RuntimePath == "" {
+		return errors.New("runtime path is required")
+	}
+
+	if s.Canary.FunctionID == "" {
+		return errors.New("canary function ID is required")
+	}
+
+	return nil
+}
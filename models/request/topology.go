@@ -0,0 +1,52 @@
+package request
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/node/head/topology"
+)
+
+var _ (json.Marshaler) = (*Topology)(nil)
+
+// Topology describes the `MessageTopology` request payload.
+// It asks the head node for a snapshot of the network topology. With the `dot` format, the snapshot is also rendered in the Graphviz DOT format.
+type Topology struct {
+	blockless.BaseMessage
+	Format string `json:"format,omitempty"`
+}
+
+func (t Topology) Response(c codes.Code, graph topology.Graph) *response.Topology {
+	return &response.Topology{
+		BaseMessage: blockless.BaseMessage{TraceInfo: t.TraceInfo},
+		Code:        c,
+		Topology:    graph,
+	}
+}
+
+func (Topology) Type() string { return blockless.MessageTopology }
+
+func (t Topology) MarshalJSON() ([]byte, error) {
+	type Alias Topology
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(t),
+		Type:  t.Type(),
+	}
+	return json.Marshal(rec)
+}
+
+func (t Topology) Valid() error {
+
+	switch t.Format {
+	case "", topology.FormatJSON, topology.FormatDOT:
+		return nil
+	}
+
+	return fmt.Errorf("unsupported topology format (%s)", t.Format)
+}
@@ -0,0 +1,58 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+var _ (json.Marshaler) = (*TransferLeadership)(nil)
+
+// TransferLeadership describes the `MessageTransferLeadership` request payload.
+// It asks a raft cluster to hand over leadership to the target peer, e.g. so the current leader can be drained without aborting in-flight executions.
+// Admins send it to the head node, which forwards it to the cluster members. If no members are listed, the head node uses the peers
+// executing the request. If no target is given, the most up to date member becomes the new leader.
+type TransferLeadership struct {
+	blockless.BaseMessage
+	RequestID string         `json:"request_id,omitempty"`
+	Consensus consensus.Type `json:"consensus,omitempty"`
+	Target    peer.ID        `json:"target,omitempty"`
+	Members   []peer.ID      `json:"members,omitempty"`
+}
+
+func (t TransferLeadership) Response(c codes.Code) *response.TransferLeadership {
+	return &response.TransferLeadership{
+		BaseMessage: blockless.BaseMessage{TraceInfo: t.TraceInfo},
+		RequestID:   t.RequestID,
+		Code:        c,
+	}
+}
+
+func (TransferLeadership) Type() string { return blockless.MessageTransferLeadership }
+
+func (t TransferLeadership) MarshalJSON() ([]byte, error) {
+	type Alias TransferLeadership
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(t),
+		Type:  t.Type(),
+	}
+	return json.Marshal(rec)
+}
+
+func (t TransferLeadership) Valid() error {
+
+	if t.RequestID == "" {
+		return errors.New("request ID is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+var _ (json.Marshaler) = (*UpdateCluster)(nil)
+
+// UpdateCluster describes the `MessageUpdateCluster` request payload.
+// It is sent to add or remove peers from an existing cluster, e.g. when a member permanently disappears.
+// Peers being added receive it first, so they are ready to join by the time the cluster leader adds them.
+type UpdateCluster struct {
+	blockless.BaseMessage
+	RequestID      string          `json:"request_id,omitempty"`
+	Consensus      consensus.Type  `json:"consensus,omitempty"`
+	Add            []peer.ID       `json:"add,omitempty"`
+	Remove         []peer.ID       `json:"remove,omitempty"`
+	ConnectionInfo []peer.AddrInfo `json:"connection_info,omitempty"`
+}
+
+func (u UpdateCluster) Response(c codes.Code) *response.UpdateCluster {
+	return &response.UpdateCluster{
+		BaseMessage: blockless.BaseMessage{TraceInfo: u.TraceInfo},
+		RequestID:   u.RequestID,
+		Code:        c,
+	}
+}
+
+func (UpdateCluster) Type() string { return blockless.MessageUpdateCluster }
+
+func (u UpdateCluster) MarshalJSON() ([]byte, error) {
+	type Alias UpdateCluster
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(u),
+		Type:  u.Type(),
+	}
+	return json.Marshal(rec)
+}
+
+func (u UpdateCluster) Valid() error {
+
+	if u.RequestID == "" {
+		return errors.New("request ID is required")
+	}
+
+	if len(u.Add) == 0 && len(u.Remove) == 0 {
+		return errors.New("no peers to add or remove")
+	}
+
+	for _, id := range u.Add {
+		if slices.Contains(u.Remove, id) {
+			return errors.New("peer cannot be both added and removed")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/node/head/usage"
+)
+
+var _ (json.Marshaler) = (*UsageQuery)(nil)
+
+// UsageQuery describes the `MessageUsageQuery` request payload.
+// It asks the head node for resource usage of executions, grouped by the values of the given tag.
+type UsageQuery struct {
+	blockless.BaseMessage
+	Tag string `json:"tag"`
+}
+
+func (u UsageQuery) Response(c codes.Code, summary []usage.Summary) *response.UsageQuery {
+	return &response.UsageQuery{
+		BaseMessage: blockless.BaseMessage{TraceInfo: u.TraceInfo},
+		Code:        c,
+		Tag:         u.Tag,
+		Usage:       summary,
+	}
+}
+
+func (UsageQuery) Type() string { return blockless.MessageUsageQuery }
+
+func (u UsageQuery) MarshalJSON() ([]byte, error) {
+	type Alias UsageQuery
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(u),
+		Type:  u.Type(),
+	}
+	return json.Marshal(rec)
+}
+
+func (u UsageQuery) Valid() error {
+
+	if u.Tag == "" {
+		return errors.New("tag is required")
+	}
+
+	return nil
+}
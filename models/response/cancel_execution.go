@@ -0,0 +1,39 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+)
+
+var _ (json.Marshaler) = (*CancelExecution)(nil)
+
+// CancelExecution describes the response to the `MessageCancelExecution` message.
+type CancelExecution struct {
+	blockless.BaseMessage
+	RequestID string     `json:"request_id,omitempty"`
+	Code      codes.Code `json:"code,omitempty"`
+
+	// Used to communicate the reason for failure to the user.
+	ErrorMessage string `json:"message,omitempty"`
+}
+
+func (c *CancelExecution) WithErrorMessage(err error) *CancelExecution {
+	c.ErrorMessage = err.Error()
+	return c
+}
+
+func (CancelExecution) Type() string { return blockless.MessageCancelExecutionResponse }
+
+func (c CancelExecution) MarshalJSON() ([]byte, error) {
+	type Alias CancelExecution
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(c),
+		Type:  c.Type(),
+	}
+	return json.Marshal(rec)
+}
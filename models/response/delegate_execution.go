@@ -0,0 +1,56 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+var _ (json.Marshaler) = (*DelegateExecution)(nil)
+
+// DelegateExecution describes the response to the `MessageDelegateExecution` message.
+type DelegateExecution struct {
+	blockless.BaseMessage
+	RequestID    string            `json:"request_id,omitempty"`
+	Code         codes.Code        `json:"code,omitempty"`
+	Results      execute.ResultMap `json:"results,omitempty"`
+	Cluster      execute.Cluster   `json:"cluster,omitempty"`
+	Timing       execute.Timing    `json:"timing,omitempty"`
+	ErrorMessage string            `json:"message,omitempty"`
+}
+
+func (d *DelegateExecution) WithResults(r execute.ResultMap) *DelegateExecution {
+	d.Results = r
+	return d
+}
+
+func (d *DelegateExecution) WithCluster(c execute.Cluster) *DelegateExecution {
+	d.Cluster = c
+	return d
+}
+
+func (d *DelegateExecution) WithTiming(t execute.Timing) *DelegateExecution {
+	d.Timing = t
+	return d
+}
+
+func (d *DelegateExecution) WithErrorMessage(err error) *DelegateExecution {
+	d.ErrorMessage = err.Error()
+	return d
+}
+
+func (DelegateExecution) Type() string { return blockless.MessageDelegateExecutionResponse }
+
+func (d DelegateExecution) MarshalJSON() ([]byte, error) {
+	type Alias DelegateExecution
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(d),
+		Type:  d.Type(),
+	}
+	return json.Marshal(rec)
+}
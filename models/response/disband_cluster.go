@@ -0,0 +1,38 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+)
+
+var _ (json.Marshaler) = (*DisbandCluster)(nil)
+
+// DisbandCluster describes the message workers send to the head node when they leave a cluster on their own,
+// e.g. because the cluster made no progress for too long.
+type DisbandCluster struct {
+	blockless.BaseMessage
+	RequestID    string     `json:"request_id,omitempty"`
+	Code         codes.Code `json:"code,omitempty"`
+	ErrorMessage string     `json:"message,omitempty"`
+}
+
+func (d *DisbandCluster) WithErrorMessage(err error) *DisbandCluster {
+	d.ErrorMessage = err.Error()
+	return d
+}
+
+func (DisbandCluster) Type() string { return blockless.MessageDisbandClusterResponse }
+
+func (d DisbandCluster) MarshalJSON() ([]byte, error) {
+	type Alias DisbandCluster
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(d),
+		Type:  d.Type(),
+	}
+	return json.Marshal(rec)
+}
@@ -0,0 +1,36 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+)
+
+var _ (json.Marshaler) = (*Drain)(nil)
+
+// Drain describes the response to the `MessageDrain` message. Code `OK` means the worker is ready to be shut down.
+type Drain struct {
+	blockless.BaseMessage
+	Code         codes.Code `json:"code,omitempty"`
+	ErrorMessage string     `json:"message,omitempty"`
+}
+
+func (d *Drain) WithErrorMessage(err error) *Drain {
+	d.ErrorMessage = err.Error()
+	return d
+}
+
+func (Drain) Type() string { return blockless.MessageDrainResponse }
+
+func (d Drain) MarshalJSON() ([]byte, error) {
+	type Alias Drain
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(d),
+		Type:  d.Type(),
+	}
+	return json.Marshal(rec)
+}
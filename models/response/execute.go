@@ -17,6 +17,10 @@ type Execute struct {
 	Code      codes.Code        `json:"code,omitempty"`
 	Results   execute.ResultMap `json:"results,omitempty"`
 	Cluster   execute.Cluster   `json:"cluster,omitempty"`
+	Timing    *execute.Timing   `json:"timing,omitempty"`
+
+	// Summary replaces the results for clients that asked for a summary. Full results of individual peers are retrieved separately.
+	Summary *execute.ResultSummary `json:"summary,omitempty"`
 
 	// Used to communicate the reason for failure to the user.
 	ErrorMessage string `json:"message,omitempty"`
@@ -32,6 +36,16 @@ func (e *Execute) WithCluster(c execute.Cluster) *Execute {
 	return e
 }
 
+func (e *Execute) WithTiming(t execute.Timing) *Execute {
+	e.Timing = &t
+	return e
+}
+
+func (e *Execute) WithSummary(s execute.ResultSummary) *Execute {
+	e.Summary = &s
+	return e
+}
+
 func (e *Execute) WithErrorMessage(err error) *Execute {
 	e.ErrorMessage = err.Error()
 	return e
@@ -0,0 +1,34 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+var _ (json.Marshaler) = (*ExecutePartial)(nil)
+
+// ExecutePartial describes the `MessageExecutePartialResponse` message. It carries execution results
+// forwarded to the client as they arrive, before the final `MessageExecuteResponse` is sent.
+type ExecutePartial struct {
+	blockless.BaseMessage
+	RequestID string            `json:"request_id,omitempty"`
+	Results   execute.ResultMap `json:"results,omitempty"`
+	// Number of results received so far, including the ones in this message.
+	Received uint `json:"received,omitempty"`
+}
+
+func (ExecutePartial) Type() string { return blockless.MessageExecutePartialResponse }
+
+func (e ExecutePartial) MarshalJSON() ([]byte, error) {
+	type Alias ExecutePartial
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(e),
+		Type:  e.Type(),
+	}
+	return json.Marshal(rec)
+}
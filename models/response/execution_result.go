@@ -0,0 +1,49 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+var _ (json.Marshaler) = (*ExecutionResult)(nil)
+
+// ExecutionResult describes the response to the `MessageExecutionResult` message.
+type ExecutionResult struct {
+	blockless.BaseMessage
+	ID     string              `json:"id,omitempty"`
+	Peer   peer.ID             `json:"peer,omitempty"`
+	Code   codes.Code          `json:"code,omitempty"`
+	Result *execute.NodeResult `json:"result,omitempty"`
+
+	// Used to communicate the reason for failure to the user.
+	ErrorMessage string `json:"message,omitempty"`
+}
+
+func (e *ExecutionResult) WithResult(r execute.NodeResult) *ExecutionResult {
+	e.Result = &r
+	return e
+}
+
+func (e *ExecutionResult) WithErrorMessage(err error) *ExecutionResult {
+	e.ErrorMessage = err.Error()
+	return e
+}
+
+func (ExecutionResult) Type() string { return blockless.MessageExecutionResultResponse }
+
+func (e ExecutionResult) MarshalJSON() ([]byte, error) {
+	type Alias ExecutionResult
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(e),
+		Type:  e.Type(),
+	}
+	return json.Marshal(rec)
+}
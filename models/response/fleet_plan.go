@@ -0,0 +1,32 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/node/head/fleet"
+)
+
+var _ (json.Marshaler) = (*FleetPlan)(nil)
+
+// FleetPlan describes the response to the `MessageFleetPlan` message.
+type FleetPlan struct {
+	blockless.BaseMessage
+	Code codes.Code `json:"code,omitempty"`
+	Plan fleet.Plan `json:"plan"`
+}
+
+func (FleetPlan) Type() string { return blockless.MessageFleetPlanResponse }
+
+func (f FleetPlan) MarshalJSON() ([]byte, error) {
+	type Alias FleetPlan
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(f),
+		Type:  f.Type(),
+	}
+	return json.Marshal(rec)
+}
@@ -0,0 +1,30 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/node/head/fleet"
+)
+
+var _ (json.Marshaler) = (*FleetReport)(nil)
+
+// FleetReport describes the message workers periodically publish on the fleet topic.
+type FleetReport struct {
+	blockless.BaseMessage
+	fleet.Report
+}
+
+func (FleetReport) Type() string { return blockless.MessageFleetReport }
+
+func (f FleetReport) MarshalJSON() ([]byte, error) {
+	type Alias FleetReport
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(f),
+		Type:  f.Type(),
+	}
+	return json.Marshal(rec)
+}
@@ -3,6 +3,7 @@ package response
 import (
 	"encoding/json"
 
+	"github.com/blocklessnetwork/b7s-attributes/attributes"
 	"github.com/blocklessnetwork/b7s/models/blockless"
 )
 
@@ -11,7 +12,13 @@ var _ (json.Marshaler) = (*Health)(nil)
 // Health describes the message sent as a health ping.
 type Health struct {
 	blockless.BaseMessage
-	Code int `json:"code,omitempty"`
+	Code       int                     `json:"code,omitempty"`
+	Role       blockless.NodeRole      `json:"role,omitempty"`
+	Attributes *attributes.Attestation `json:"attributes,omitempty"`
+	// NoConsensus is set by workers built without consensus algorithms.
+	NoConsensus bool `json:"no_consensus,omitempty"`
+	// Load is reported by workers, so head nodes can take it into account when choosing workers.
+	Load *Load `json:"load,omitempty"`
 }
 
 func (Health) Type() string { return blockless.MessageHealthCheck }
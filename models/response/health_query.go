@@ -0,0 +1,35 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s-attributes/attributes"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+)
+
+var _ (json.Marshaler) = (*HealthQuery)(nil)
+
+// HealthQuery describes the response to the `MessageHealthQuery` message.
+type HealthQuery struct {
+	blockless.BaseMessage
+	Code       codes.Code              `json:"code,omitempty"`
+	Role       blockless.NodeRole      `json:"role,omitempty"`
+	Attributes *attributes.Attestation `json:"attributes,omitempty"`
+	// NoConsensus is set by workers built without consensus algorithms.
+	NoConsensus bool `json:"no_consensus,omitempty"`
+}
+
+func (HealthQuery) Type() string { return blockless.MessageHealthQueryResponse }
+
+func (h HealthQuery) MarshalJSON() ([]byte, error) {
+	type Alias HealthQuery
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(h),
+		Type:  h.Type(),
+	}
+	return json.Marshal(rec)
+}
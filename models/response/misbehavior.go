@@ -0,0 +1,31 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+)
+
+var _ (json.Marshaler) = (*Misbehavior)(nil)
+
+// Misbehavior describes the message workers send to the head node when they catch a cluster member misbehaving.
+type Misbehavior struct {
+	blockless.BaseMessage
+	RequestID string `json:"request_id,omitempty"`
+	consensus.Misbehavior
+}
+
+func (Misbehavior) Type() string { return blockless.MessageMisbehavior }
+
+func (m Misbehavior) MarshalJSON() ([]byte, error) {
+	type Alias Misbehavior
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(m),
+		Type:  m.Type(),
+	}
+	return json.Marshal(rec)
+}
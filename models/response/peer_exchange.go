@@ -0,0 +1,41 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s-attributes/attributes"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+)
+
+var _ (json.Marshaler) = (*PeerExchange)(nil)
+
+// PeerExchange describes the response to the `MessagePeerExchange` message.
+type PeerExchange struct {
+	blockless.BaseMessage
+	Peers []ExchangedPeer `json:"peers,omitempty"`
+}
+
+// ExchangedPeer describes a healthy peer known to the responding node.
+type ExchangedPeer struct {
+	AddrInfo   peer.AddrInfo           `json:"addrinfo"`
+	Role       blockless.NodeRole      `json:"role,omitempty"`
+	Attributes *attributes.Attestation `json:"attributes,omitempty"`
+	LastSeen   time.Time               `json:"last_seen,omitempty"`
+}
+
+func (PeerExchange) Type() string { return blockless.MessagePeerExchangeResponse }
+
+func (p PeerExchange) MarshalJSON() ([]byte, error) {
+	type Alias PeerExchange
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(p),
+		Type:  p.Type(),
+	}
+	return json.Marshal(rec)
+}
@@ -0,0 +1,42 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+)
+
+var _ (json.Marshaler) = (*PeerStates)(nil)
+
+// PeerStates describes the response to the `MessagePeerStates` message.
+type PeerStates struct {
+	blockless.BaseMessage
+	Code  codes.Code  `json:"code,omitempty"`
+	Peers []PeerState `json:"peers,omitempty"`
+}
+
+// PeerState describes what the head node knows about a peer, from its health pings.
+type PeerState struct {
+	ID       peer.ID            `json:"id"`
+	Role     blockless.NodeRole `json:"role"`
+	LastSeen time.Time          `json:"last_seen"`
+	Load     *Load              `json:"load,omitempty"` // Latest load the worker reported.
+}
+
+func (PeerStates) Type() string { return blockless.MessagePeerStatesResponse }
+
+func (p PeerStates) MarshalJSON() ([]byte, error) {
+	type Alias PeerStates
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(p),
+		Type:  p.Type(),
+	}
+	return json.Marshal(rec)
+}
@@ -0,0 +1,38 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+var _ (json.Marshaler) = (*QuotaQuery)(nil)
+
+// QuotaQuery describes the response to the `MessageQuotaQuery` message.
+type QuotaQuery struct {
+	blockless.BaseMessage
+	Code         codes.Code             `json:"code,omitempty"`
+	Remaining    execute.QuotaRemaining `json:"remaining"`
+	ErrorMessage string                 `json:"message,omitempty"`
+}
+
+func (q *QuotaQuery) WithErrorMessage(err error) *QuotaQuery {
+	q.ErrorMessage = err.Error()
+	return q
+}
+
+func (QuotaQuery) Type() string { return blockless.MessageQuotaQueryResponse }
+
+func (q QuotaQuery) MarshalJSON() ([]byte, error) {
+	type Alias QuotaQuery
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(q),
+		Type:  q.Type(),
+	}
+	return json.Marshal(rec)
+}
@@ -0,0 +1,32 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/node/head/recovery"
+)
+
+var _ (json.Marshaler) = (*RecoveryReport)(nil)
+
+// RecoveryReport describes the response to the `MessageRecoveryReport` message.
+type RecoveryReport struct {
+	blockless.BaseMessage
+	Code   codes.Code      `json:"code,omitempty"`
+	Report recovery.Report `json:"report"`
+}
+
+func (RecoveryReport) Type() string { return blockless.MessageRecoveryReportResponse }
+
+func (r RecoveryReport) MarshalJSON() ([]byte, error) {
+	type Alias RecoveryReport
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(r),
+		Type:  r.Type(),
+	}
+	return json.Marshal(rec)
+}
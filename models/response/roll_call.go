@@ -9,12 +9,86 @@ import (
 
 var _ (json.Marshaler) = (*RollCall)(nil)
 
+// Share of memory or disk in use above which the worker is considered to be under pressure.
+const pressureThreshold = 0.9
+
 // RollCall describes the `MessageRollCall` response payload.
 type RollCall struct {
 	blockless.BaseMessage
 	Code       codes.Code `json:"code,omitempty"`
 	FunctionID string     `json:"function_id,omitempty"`
 	RequestID  string     `json:"request_id,omitempty"`
+	Reason     string     `json:"reason,omitempty"`
+	Load       *Load      `json:"load,omitempty"` // Load is how busy the worker is at the time of the roll call.
+	// Credential admits the worker to the restricted subgroup the roll call was published in.
+	Credential *blockless.MembershipCredential `json:"credential,omitempty"`
+	// Install is the progress of the function installation the worker started to answer the roll call.
+	Install *InstallProgress `json:"install,omitempty"`
+}
+
+// InstallProgress describes the installation of a function the worker did not have when it answered the roll call.
+type InstallProgress struct {
+	State   string `json:"state"`                // State of the installation.
+	Elapsed int64  `json:"elapsed_ms,omitempty"` // How long the installation has been running, in milliseconds.
+}
+
+// Installation states reported in roll call responses.
+const (
+	InstallStateInstalling = "installing"
+)
+
+// Load describes how busy the worker is.
+type Load struct {
+	Executions      uint    `json:"executions"`                 // Number of executions in progress.
+	Queued          uint    `json:"queued,omitempty"`           // Number of requests waiting for a processing slot.
+	Concurrency     uint    `json:"concurrency,omitempty"`      // Number of requests the worker processes in parallel.
+	CPUUsage        float64 `json:"cpu_usage,omitempty"`        // Share of the CPU in use, in the 0-1 range. Zero if unknown.
+	MemoryAvailable uint64  `json:"memory_available,omitempty"` // Available memory (kB). Zero if unknown.
+	MemoryPressure  float64 `json:"memory_pressure,omitempty"`  // Share of the memory in use, in the 0-1 range. Zero if unknown.
+	DiskPressure    float64 `json:"disk_pressure,omitempty"`    // Share of the workspace disk in use, in the 0-1 range. Zero if unknown.
+}
+
+// Utilization returns how close the worker is to its capacity, in the 0-1 range.
+func (l Load) Utilization() float64 {
+
+	var utilization float64
+	if l.Concurrency > 0 {
+		utilization = float64(l.Executions+l.Queued) / float64(l.Concurrency)
+	}
+
+	// Memory and disk only count once they are close to running out, when executions are likely to fail.
+	pressure := max(l.MemoryPressure, l.DiskPressure)
+	if pressure > pressureThreshold {
+		utilization = max(utilization, (pressure-pressureThreshold)/(1-pressureThreshold))
+	}
+
+	return min(max(utilization, l.CPUUsage), 1)
+}
+
+// Reasons for declining a roll call.
+const (
+	RollCallReasonMaintenance = "maintenance"
+	RollCallReasonDraining    = "draining"
+)
+
+func (r *RollCall) WithReason(reason string) *RollCall {
+	r.Reason = reason
+	return r
+}
+
+func (r *RollCall) WithLoad(load Load) *RollCall {
+	r.Load = &load
+	return r
+}
+
+func (r *RollCall) WithCredential(credential *blockless.MembershipCredential) *RollCall {
+	r.Credential = credential
+	return r
+}
+
+func (r *RollCall) WithInstall(progress InstallProgress) *RollCall {
+	r.Install = &progress
+	return r
 }
 
 func (RollCall) Type() string { return blockless.MessageRollCallResponse }
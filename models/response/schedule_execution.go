@@ -0,0 +1,74 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+var _ (json.Marshaler) = (*ScheduleExecution)(nil)
+
+// ScheduleExecution describes the response to the `MessageScheduleExecution` message.
+type ScheduleExecution struct {
+	blockless.BaseMessage
+	RequestID  string     `json:"request_id,omitempty"`
+	Code       codes.Code `json:"code,omitempty"`
+	ScheduleID string     `json:"schedule_id,omitempty"`
+	Next       time.Time  `json:"next,omitempty"` // Next is the time of the first scheduled execution.
+
+	// Used to communicate the reason for failure to the user.
+	ErrorMessage string `json:"message,omitempty"`
+}
+
+func (s *ScheduleExecution) WithErrorMessage(err error) *ScheduleExecution {
+	s.ErrorMessage = err.Error()
+	return s
+}
+
+func (ScheduleExecution) Type() string { return blockless.MessageScheduleExecutionResponse }
+
+func (s ScheduleExecution) MarshalJSON() ([]byte, error) {
+	type Alias ScheduleExecution
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(s),
+		Type:  s.Type(),
+	}
+	return json.Marshal(rec)
+}
+
+var _ (json.Marshaler) = (*ScheduledExecution)(nil)
+
+// ScheduledExecution describes the results of a scheduled execution, published by the head node.
+type ScheduledExecution struct {
+	blockless.BaseMessage
+	ScheduleID string            `json:"schedule_id,omitempty"`
+	RequestID  string            `json:"request_id,omitempty"`
+	Code       codes.Code        `json:"code,omitempty"`
+	Results    execute.ResultMap `json:"results,omitempty"`
+	Cluster    execute.Cluster   `json:"cluster,omitempty"`
+	Timing     *execute.Timing   `json:"timing,omitempty"`
+	Scheduled  time.Time         `json:"scheduled,omitempty"`
+
+	// Used to communicate the reason for failure to the user.
+	ErrorMessage string `json:"message,omitempty"`
+}
+
+func (ScheduledExecution) Type() string { return blockless.MessageScheduledExecution }
+
+func (s ScheduledExecution) MarshalJSON() ([]byte, error) {
+	type Alias ScheduledExecution
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(s),
+		Type:  s.Type(),
+	}
+	return json.Marshal(rec)
+}
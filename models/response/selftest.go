@@ -0,0 +1,58 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+)
+
+var _ (json.Marshaler) = (*SelfTest)(nil)
+
+// SelfTest describes the response to the `MessageSelfTest` message. Code `OK` means all self-test checks passed or were skipped.
+type SelfTest struct {
+	blockless.BaseMessage
+	Code         codes.Code     `json:"code,omitempty"`
+	Report       SelfTestReport `json:"report"`
+	ErrorMessage string         `json:"message,omitempty"`
+}
+
+// SelfTestReport describes the outcome of the worker self-test.
+type SelfTestReport struct {
+	Passed bool            `json:"passed"`
+	Checks []SelfTestCheck `json:"checks"`
+}
+
+// SelfTestCheck describes the outcome of a single self-test check.
+type SelfTestCheck struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Duration int64  `json:"duration_ms"`      // How long the check took, in milliseconds.
+	Detail   string `json:"detail,omitempty"` // Detail is additional information about the check, e.g. the reason it failed.
+}
+
+// Self-test check statuses.
+const (
+	SelfTestPassed  = "pass"
+	SelfTestFailed  = "fail"
+	SelfTestSkipped = "skip"
+)
+
+func (s *SelfTest) WithErrorMessage(err error) *SelfTest {
+	s.ErrorMessage = err.Error()
+	return s
+}
+
+func (SelfTest) Type() string { return blockless.MessageSelfTestResponse }
+
+func (s SelfTest) MarshalJSON() ([]byte, error) {
+	type Alias SelfTest
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(s),
+		Type:  s.Type(),
+	}
+	return json.Marshal(rec)
+}
@@ -0,0 +1,43 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+)
+
+var _ (json.Marshaler) = (*StoreMaintenance)(nil)
+
+// StoreMaintenance describes the response to the `MessageStoreMaintenance` message.
+type StoreMaintenance struct {
+	blockless.BaseMessage
+	Code         codes.Code `json:"code,omitempty"`
+	Operation    string     `json:"operation,omitempty"`
+	Snapshot     string     `json:"snapshot,omitempty"` // Snapshot is the name of the backup created by the head node.
+	ErrorMessage string     `json:"message,omitempty"`
+}
+
+func (s *StoreMaintenance) WithSnapshot(name string) *StoreMaintenance {
+	s.Snapshot = name
+	return s
+}
+
+func (s *StoreMaintenance) WithErrorMessage(err error) *StoreMaintenance {
+	s.ErrorMessage = err.Error()
+	return s
+}
+
+func (StoreMaintenance) Type() string { return blockless.MessageStoreMaintenanceResponse }
+
+func (s StoreMaintenance) MarshalJSON() ([]byte, error) {
+	type Alias StoreMaintenance
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(s),
+		Type:  s.Type(),
+	}
+	return json.Marshal(rec)
+}
@@ -0,0 +1,43 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+)
+
+var _ (json.Marshaler) = (*Subgroups)(nil)
+
+// Subgroups describes the response to the `MessageSubgroups` message.
+type Subgroups struct {
+	blockless.BaseMessage
+	Code codes.Code `json:"code,omitempty"`
+	// Topics the worker is subscribed to after the change.
+	Topics       []string `json:"topics,omitempty"`
+	ErrorMessage string   `json:"message,omitempty"`
+}
+
+func (s *Subgroups) WithTopics(topics []string) *Subgroups {
+	s.Topics = topics
+	return s
+}
+
+func (s *Subgroups) WithErrorMessage(err error) *Subgroups {
+	s.ErrorMessage = err.Error()
+	return s
+}
+
+func (Subgroups) Type() string { return blockless.MessageSubgroupsResponse }
+
+func (s Subgroups) MarshalJSON() ([]byte, error) {
+	type Alias Subgroups
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(s),
+		Type:  s.Type(),
+	}
+	return json.Marshal(rec)
+}
@@ -0,0 +1,37 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+)
+
+var _ (json.Marshaler) = (*SwapExecutor)(nil)
+
+// SwapExecutor describes the response to the `MessageSwapExecutor` message.
+type SwapExecutor struct {
+	blockless.BaseMessage
+	Code         codes.Code `json:"code,omitempty"`
+	RuntimePath  string     `json:"runtime_path,omitempty"`
+	ErrorMessage string     `json:"message,omitempty"`
+}
+
+func (s *SwapExecutor) WithErrorMessage(err error) *SwapExecutor {
+	s.ErrorMessage = err.Error()
+	return s
+}
+
+func (SwapExecutor) Type() string { return blockless.MessageSwapExecutorResponse }
+
+func (s SwapExecutor) MarshalJSON() ([]byte, error) {
+	type Alias SwapExecutor
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(s),
+		Type:  s.Type(),
+	}
+	return json.Marshal(rec)
+}
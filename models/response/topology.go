@@ -0,0 +1,38 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/node/head/topology"
+)
+
+var _ (json.Marshaler) = (*Topology)(nil)
+
+// Topology describes the response to the `MessageTopology` message.
+type Topology struct {
+	blockless.BaseMessage
+	Code     codes.Code     `json:"code,omitempty"`
+	Topology topology.Graph `json:"topology"`
+	DOT      string         `json:"dot,omitempty"`
+}
+
+func (t *Topology) WithDOT(dot string) *Topology {
+	t.DOT = dot
+	return t
+}
+
+func (Topology) Type() string { return blockless.MessageTopologyResponse }
+
+func (t Topology) MarshalJSON() ([]byte, error) {
+	type Alias Topology
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(t),
+		Type:  t.Type(),
+	}
+	return json.Marshal(rec)
+}
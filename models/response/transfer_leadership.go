@@ -0,0 +1,37 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+)
+
+var _ (json.Marshaler) = (*TransferLeadership)(nil)
+
+// TransferLeadership describes the response to the `MessageTransferLeadership` message.
+type TransferLeadership struct {
+	blockless.BaseMessage
+	RequestID    string     `json:"request_id,omitempty"`
+	Code         codes.Code `json:"code,omitempty"`
+	ErrorMessage string     `json:"message,omitempty"`
+}
+
+func (t *TransferLeadership) WithErrorMessage(err error) *TransferLeadership {
+	t.ErrorMessage = err.Error()
+	return t
+}
+
+func (TransferLeadership) Type() string { return blockless.MessageTransferLeadershipResponse }
+
+func (t TransferLeadership) MarshalJSON() ([]byte, error) {
+	type Alias TransferLeadership
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(t),
+		Type:  t.Type(),
+	}
+	return json.Marshal(rec)
+}
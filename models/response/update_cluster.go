@@ -0,0 +1,37 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+)
+
+var _ (json.Marshaler) = (*UpdateCluster)(nil)
+
+// UpdateCluster describes the response to the `MessageUpdateCluster` message.
+type UpdateCluster struct {
+	blockless.BaseMessage
+	RequestID    string     `json:"request_id,omitempty"`
+	Code         codes.Code `json:"code,omitempty"`
+	ErrorMessage string     `json:"message,omitempty"`
+}
+
+func (u *UpdateCluster) WithErrorMessage(err error) *UpdateCluster {
+	u.ErrorMessage = err.Error()
+	return u
+}
+
+func (UpdateCluster) Type() string { return blockless.MessageUpdateClusterResponse }
+
+func (u UpdateCluster) MarshalJSON() ([]byte, error) {
+	type Alias UpdateCluster
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(u),
+		Type:  u.Type(),
+	}
+	return json.Marshal(rec)
+}
@@ -0,0 +1,33 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/node/head/usage"
+)
+
+var _ (json.Marshaler) = (*UsageQuery)(nil)
+
+// UsageQuery describes the response to the `MessageUsageQuery` message.
+type UsageQuery struct {
+	blockless.BaseMessage
+	Code  codes.Code      `json:"code,omitempty"`
+	Tag   string          `json:"tag,omitempty"`
+	Usage []usage.Summary `json:"usage,omitempty"`
+}
+
+func (UsageQuery) Type() string { return blockless.MessageUsageQueryResponse }
+
+func (u UsageQuery) MarshalJSON() ([]byte, error) {
+	type Alias UsageQuery
+	rec := struct {
+		Alias
+		Type string `json:"type"`
+	}{
+		Alias: Alias(u),
+		Type:  u.Type(),
+	}
+	return json.Marshal(rec)
+}
@@ -0,0 +1,162 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/request"
+)
+
+var (
+	errExecutionAbandoned = errors.New("execution abandoned by the client")
+	errExecutionAborted   = errors.New("execution aborted by the head node")
+)
+
+// runningExecutions tracks executions the worker is working on, so the head node can abort them.
+type runningExecutions struct {
+	sync.Mutex
+	executions map[string]*runningExecution
+}
+
+type runningExecution struct {
+	origin peer.ID // Head node that requested the execution.
+	cancel context.CancelCauseFunc
+}
+
+func newRunningExecutions() *runningExecutions {
+	return &runningExecutions{
+		executions: make(map[string]*runningExecution),
+	}
+}
+
+// start records the execution requested by the given head node. The returned context is cancelled if the head node aborts the execution.
+// The returned function should be called once the execution is done.
+func (r *runningExecutions) start(ctx context.Context, requestID string, origin peer.ID) (context.Context, func()) {
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	execution := &runningExecution{
+		origin: origin,
+		cancel: cancel,
+	}
+
+	r.Lock()
+	r.executions[requestID] = execution
+	r.Unlock()
+
+	done := func() {
+		r.Lock()
+		defer r.Unlock()
+
+		// The same request might have been received again in the meantime.
+		if r.executions[requestID] == execution {
+			delete(r.executions, requestID)
+		}
+		cancel(context.Canceled)
+	}
+
+	return ctx, done
+}
+
+// abort cancels the execution, if it was requested by the given head node.
+func (r *runningExecutions) abort(requestID string, from peer.ID) bool {
+	r.Lock()
+	defer r.Unlock()
+
+	execution, ok := r.executions[requestID]
+	if !ok || execution.origin != from {
+		return false
+	}
+
+	execution.cancel(errExecutionAborted)
+	delete(r.executions, requestID)
+
+	return true
+}
+
+// executionAborted returns true if the context returned by `runningExecutions.start` was cancelled because the head node aborted the execution.
+func executionAborted(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), errExecutionAborted)
+}
+
+// abandoned returns true if the client stopped waiting for the execution before its deadline.
+func abandoned(ctx context.Context) bool {
+	return errors.Is(ctx.Err(), context.Canceled)
+}
+
+// clientContext returns a context that is cancelled once the client disconnects, as it can no longer receive the response.
+func (n *Node) clientContext(ctx context.Context, client peer.ID) (context.Context, context.CancelFunc) {
+
+	cctx, cancel := context.WithCancel(ctx)
+
+	disconnected, stop := n.disconnects.watch(client)
+
+	// Requests not received over a connection cannot be abandoned this way.
+	if !n.haveConnection(client) {
+		stop()
+		return cctx, cancel
+	}
+
+	go func() {
+		defer stop()
+
+		select {
+		case <-cctx.Done():
+		case <-disconnected:
+			n.log.Info().Str("peer", client.String()).Msg("client disconnected before receiving execution response")
+			cancel()
+		}
+	}()
+
+	return cctx, cancel
+}
+
+// abortExecution lets the workers that did not return a result know that they can stop working on the request.
+func (n *Node) abortExecution(requestID string, peers []peer.ID, results execute.ResultMap) error {
+
+	var pending []peer.ID
+	for _, peer := range peers {
+		_, ok := results[peer]
+		if !ok {
+			pending = append(pending, peer)
+		}
+	}
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	msg := request.AbortExecution{
+		RequestID: requestID,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), abortExecutionSendTimeout)
+	defer cancel()
+
+	err := n.sendToMany(ctx, pending, &msg, false)
+	if err != nil {
+		return fmt.Errorf("could not send execution abort request (request: %s): %w", requestID, err)
+	}
+
+	n.log.Info().Str("request", requestID).Strs("peers", blockless.PeerIDsToStr(pending)).Msg("sent execution abort request")
+
+	return nil
+}
+
+func (n *Node) processAbortExecution(ctx context.Context, from peer.ID, req request.AbortExecution) error {
+
+	if !n.running.abort(req.RequestID, from) {
+		n.log.Debug().Str("peer", from.String()).Str("request", req.RequestID).Msg("no execution to abort")
+		return nil
+	}
+
+	n.log.Info().Str("peer", from.String()).Str("request", req.RequestID).Msg("execution aborted by the head node")
+	n.metrics.IncrCounter(abortedExecutionsMetric, 1)
+
+	return nil
+}
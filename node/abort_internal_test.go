@@ -0,0 +1,243 @@
+package node
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/node/head/simulation"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestRunningExecutions(t *testing.T) {
+
+	const (
+		requestID = "dummy-request-id"
+	)
+
+	var (
+		origin = mocks.GenericPeerIDs[0]
+		other  = mocks.GenericPeerIDs[1]
+	)
+
+	t.Run("execution is aborted by the head node that requested it", func(t *testing.T) {
+		t.Parallel()
+
+		running := newRunningExecutions()
+
+		ctx, done := running.start(context.Background(), requestID, origin)
+		defer done()
+
+		require.False(t, running.abort(requestID, other))
+		require.NoError(t, ctx.Err())
+
+		require.True(t, running.abort(requestID, origin))
+		require.Error(t, ctx.Err())
+		require.True(t, executionAborted(ctx))
+		require.Empty(t, running.executions)
+	})
+	t.Run("finished execution cannot be aborted", func(t *testing.T) {
+		t.Parallel()
+
+		running := newRunningExecutions()
+
+		ctx, done := running.start(context.Background(), requestID, origin)
+		done()
+
+		require.False(t, running.abort(requestID, origin))
+		require.False(t, executionAborted(ctx))
+		require.Empty(t, running.executions)
+	})
+	t.Run("finished execution does not remove a newer one", func(t *testing.T) {
+		t.Parallel()
+
+		running := newRunningExecutions()
+
+		_, first := running.start(context.Background(), requestID, origin)
+		ctx, second := running.start(context.Background(), requestID, origin)
+		defer second()
+
+		first()
+
+		require.True(t, running.abort(requestID, origin))
+		require.True(t, executionAborted(ctx))
+	})
+}
+
+func TestNode_ProcessAbortExecution(t *testing.T) {
+
+	const (
+		requestID = "dummy-request-id"
+	)
+
+	var (
+		origin = mocks.GenericPeerIDs[0]
+		other  = mocks.GenericPeerIDs[1]
+	)
+
+	node := createNode(t, blockless.WorkerNode)
+
+	ctx, done := node.running.start(context.Background(), requestID, origin)
+	defer done()
+
+	err := node.processAbortExecution(context.Background(), other, request.AbortExecution{RequestID: requestID})
+	require.NoError(t, err)
+	require.NoError(t, ctx.Err())
+
+	err = node.processAbortExecution(context.Background(), origin, request.AbortExecution{RequestID: requestID})
+	require.NoError(t, err)
+	require.True(t, executionAborted(ctx))
+}
+
+func TestNode_ClientContext(t *testing.T) {
+
+	t.Run("context is not cancelled for clients without a connection", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+
+		ctx, cancel := node.clientContext(context.Background(), mocks.GenericPeerID)
+		defer cancel()
+
+		require.NoError(t, ctx.Err())
+	})
+	t.Run("context is cancelled once the client disconnects", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+
+		client, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, client)
+		err = node.host.Connect(context.Background(), *hostGetAddrInfo(t, client))
+		require.NoError(t, err)
+
+		ctx, cancel := node.clientContext(context.Background(), client.ID())
+		defer cancel()
+
+		require.NoError(t, ctx.Err())
+
+		client.Close()
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "context not cancelled after client disconnected")
+		}
+
+		require.True(t, abandoned(ctx))
+	})
+}
+
+func TestNode_AbortExecution(t *testing.T) {
+
+	const (
+		requestID = "dummy-request-id"
+	)
+
+	node := createNode(t, blockless.HeadNode)
+
+	worker, err := host.New(mocks.NoopLogger, loopback, 0)
+	require.NoError(t, err)
+
+	hostAddNewPeer(t, node.host, worker)
+
+	var (
+		wg       sync.WaitGroup
+		received request.AbortExecution
+	)
+	wg.Add(1)
+
+	worker.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+		defer wg.Done()
+		defer stream.Close()
+
+		getStreamPayload(t, stream, &received)
+	})
+
+	// Peer that already returned its result is not notified.
+	responded := mocks.GenericPeerID
+	results := execute.ResultMap{
+		responded: execute.NodeResult{Result: execute.Result{Code: codes.OK}},
+	}
+
+	err = node.abortExecution(requestID, []peer.ID{responded, worker.ID()}, results)
+	require.NoError(t, err)
+
+	wg.Wait()
+
+	require.Equal(t, blockless.MessageAbortExecution, received.Type())
+	require.Equal(t, requestID, received.RequestID)
+}
+
+func TestNode_AbandonedExecution(t *testing.T) {
+
+	const (
+		functionID = "dummy-function-id"
+	)
+
+	// Head node executing a request on a single synthetic worker, with the client abandoning the request after the given delay.
+	run := func(t *testing.T, profile simulation.Profile, after time.Duration) (codes.Code, time.Duration) {
+		t.Helper()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		node := createNode(t, blockless.HeadNode)
+
+		fleet, err := simulation.NewFleet(mocks.NoopLogger, 1, loopback, profile, []string{DefaultTopic})
+		require.NoError(t, err)
+		defer fleet.Close()
+
+		err = fleet.Connect(ctx, *hostGetAddrInfo(t, node.host))
+		require.NoError(t, err)
+
+		err = node.subscribeToTopics(ctx)
+		require.NoError(t, err)
+		node.listenDirectMessages(ctx)
+
+		go fleet.Run(ctx)
+
+		time.Sleep(subscriptionDiseminationPause)
+
+		exctx, abandon := context.WithCancel(ctx)
+		time.AfterFunc(after, abandon)
+
+		start := time.Now()
+
+		req := execute.Request{FunctionID: functionID, Method: "dummy-method", Config: execute.Config{NodeCount: 1}}
+		code, _, _, _, _ := node.headExecute(exctx, node.newRequestID(), req, DefaultTopic, nil)
+
+		return code, time.Since(start)
+	}
+
+	t.Run("execution abandoned during roll call", func(t *testing.T) {
+		t.Parallel()
+
+		profile := simulation.Profile{RollCallLatency: simulation.Latency{Min: time.Minute}}
+
+		code, took := run(t, profile, 100*time.Millisecond)
+		require.Equal(t, codes.Aborted, code)
+		require.Less(t, took, DefaultRollCallTimeout)
+	})
+	t.Run("execution abandoned while waiting for results", func(t *testing.T) {
+		t.Parallel()
+
+		profile := simulation.Profile{ExecutionLatency: simulation.Latency{Min: time.Minute}}
+
+		code, took := run(t, profile, time.Second)
+		require.Equal(t, codes.Aborted, code)
+		require.Less(t, took, DefaultExecutionTimeout)
+	})
+}
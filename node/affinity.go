@@ -0,0 +1,152 @@
+package node
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/node/internal/dispatch"
+)
+
+// affinity is the set of workers that successfully executed the last request with the affinity token.
+type affinity struct {
+	peers   []peer.ID
+	expires time.Time
+}
+
+// affinities holds the worker sets the head node bound to affinity tokens, per function.
+type affinities struct {
+	sync.Mutex
+	sets map[string]affinity
+}
+
+func newAffinities() *affinities {
+	return &affinities{
+		sets: make(map[string]affinity),
+	}
+}
+
+// set binds the workers to the affinity token. Expired bindings are removed at the same time.
+func (a *affinities) set(functionID string, token string, peers []peer.ID, expires time.Time, now time.Time) {
+	a.Lock()
+	defer a.Unlock()
+
+	for key, set := range a.sets {
+		if now.After(set.expires) {
+			delete(a.sets, key)
+		}
+	}
+
+	a.sets[affinityKey(functionID, token)] = affinity{
+		peers:   slices.Clone(peers),
+		expires: expires,
+	}
+}
+
+// get returns the workers bound to the affinity token, if the binding did not expire.
+func (a *affinities) get(functionID string, token string, now time.Time) ([]peer.ID, bool) {
+	a.Lock()
+	defer a.Unlock()
+
+	set, ok := a.sets[affinityKey(functionID, token)]
+	if !ok || now.After(set.expires) {
+		return nil, false
+	}
+
+	return slices.Clone(set.peers), true
+}
+
+func (a *affinities) remove(functionID string, token string) {
+	a.Lock()
+	defer a.Unlock()
+
+	delete(a.sets, affinityKey(functionID, token))
+}
+
+func affinityKey(functionID string, token string) string {
+	return functionID + "/" + token
+}
+
+// affinityPeers chooses the workers bound to the affinity token for the execution, skipping the roll call.
+// Bound workers are only used if all of them are still connected and can take on more work, and there are enough of them.
+// Otherwise, no workers are chosen and the caller should fall back to a roll call.
+func (n *Node) affinityPeers(
+	functionID string,
+	nodeCount int,
+	consensusAlgo consensus.Type,
+	token string,
+) ([]peer.ID, map[peer.ID]time.Duration, bool) {
+
+	if token == "" || consensusRequired(consensusAlgo) {
+		return nil, nil, false
+	}
+
+	labels := []metrics.Label{{Name: "function", Value: functionID}}
+
+	bound, ok := n.affinities.get(functionID, token, time.Now())
+	// -1 means any number of peers can execute the request.
+	if !ok || (nodeCount != -1 && len(bound) < nodeCount) {
+		n.metrics.IncrCounterWithLabels(affinityMissesMetric, 1, labels)
+		return nil, nil, false
+	}
+
+	if nodeCount != -1 {
+		bound = bound[:nodeCount]
+	}
+
+	var (
+		chosen    []peer.ID
+		latencies = make(map[peer.ID]time.Duration)
+	)
+	for _, peer := range bound {
+
+		if !n.haveConnection(peer) || !n.dispatch.Acquire(peer) {
+			n.releaseWorkers(chosen, nil, dispatch.Aborted)
+			n.metrics.IncrCounterWithLabels(affinityMissesMetric, 1, labels)
+			return nil, nil, false
+		}
+
+		latency, ok := n.latencies.get(peer)
+		if ok {
+			latencies[peer] = latency
+		}
+
+		chosen = append(chosen, peer)
+	}
+
+	n.metrics.IncrCounterWithLabels(affinityHitsMetric, 1, labels)
+
+	return chosen, latencies, true
+}
+
+// recordAffinity binds the workers that successfully executed the request to its affinity token.
+// If none of the workers succeeded, the binding is removed.
+func (n *Node) recordAffinity(req execute.Request, consensusAlgo consensus.Type, peers []peer.ID, results execute.ResultMap) {
+
+	token := req.Config.Affinity
+	if token == "" || consensusRequired(consensusAlgo) {
+		return
+	}
+
+	var healthy []peer.ID
+	for _, peer := range peers {
+		res, ok := results[peer]
+		if ok && res.Code == codes.OK {
+			healthy = append(healthy, peer)
+		}
+	}
+
+	if len(healthy) == 0 {
+		n.affinities.remove(req.FunctionID, token)
+		return
+	}
+
+	now := time.Now()
+	n.affinities.set(req.FunctionID, token, healthy, now.Add(affinityExpiry), now)
+}
@@ -0,0 +1,165 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/node/internal/dispatch"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestAffinities(t *testing.T) {
+
+	const (
+		functionID = "dummy-function-id"
+		token      = "dummy-token"
+	)
+
+	var (
+		now   = time.Now()
+		peers = mocks.GenericPeerIDs[:2]
+	)
+
+	affinities := newAffinities()
+
+	_, ok := affinities.get(functionID, token, now)
+	require.False(t, ok)
+
+	affinities.set(functionID, token, peers, now.Add(time.Minute), now)
+
+	bound, ok := affinities.get(functionID, token, now)
+	require.True(t, ok)
+	require.Equal(t, peers, bound)
+
+	_, ok = affinities.get("other-function-id", token, now)
+	require.False(t, ok)
+
+	_, ok = affinities.get(functionID, token, now.Add(2*time.Minute))
+	require.False(t, ok)
+
+	// Expired bindings are removed when new ones are set.
+	affinities.set(functionID, "other-token", peers, now.Add(3*time.Minute), now.Add(2*time.Minute))
+	require.Len(t, affinities.sets, 1)
+
+	affinities.remove(functionID, "other-token")
+	require.Empty(t, affinities.sets)
+}
+
+func TestNode_AffinityPeers(t *testing.T) {
+
+	const (
+		functionID = "dummy-function-id"
+		token      = "dummy-token"
+	)
+
+	// Create a head node with a connected worker.
+	setup := func(t *testing.T) (*Node, peer.ID) {
+		t.Helper()
+
+		node := createNode(t, blockless.HeadNode)
+		node.dispatch = dispatch.New[peer.ID](1)
+
+		worker, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, worker)
+
+		info := hostGetAddrInfo(t, worker)
+		err = node.host.Connect(context.Background(), *info)
+		require.NoError(t, err)
+
+		return node, worker.ID()
+	}
+
+	var (
+		succeeded = execute.NodeResult{Result: execute.Result{Code: codes.OK}}
+		failed    = execute.NodeResult{Result: execute.Result{Code: codes.Error}}
+	)
+
+	request := func(token string) execute.Request {
+		return execute.Request{
+			FunctionID: functionID,
+			Config: execute.Config{
+				Affinity: token,
+			},
+		}
+	}
+
+	t.Run("workers that succeeded are reused", func(t *testing.T) {
+		t.Parallel()
+
+		node, worker := setup(t)
+		node.latencies.record(worker, 10*time.Millisecond)
+
+		results := execute.ResultMap{worker: succeeded}
+		node.recordAffinity(request(token), consensus.Type(0), []peer.ID{worker}, results)
+
+		chosen, latencies, ok := node.affinityPeers(functionID, 1, consensus.Type(0), token)
+		require.True(t, ok)
+		require.Equal(t, []peer.ID{worker}, chosen)
+		require.Equal(t, 10*time.Millisecond, latencies[worker])
+
+		// Worker counts towards its dispatch limit.
+		require.False(t, node.dispatch.Acquire(worker))
+	})
+	t.Run("binding is removed if no workers succeeded", func(t *testing.T) {
+		t.Parallel()
+
+		node, worker := setup(t)
+
+		node.recordAffinity(request(token), consensus.Type(0), []peer.ID{worker}, execute.ResultMap{worker: succeeded})
+		node.recordAffinity(request(token), consensus.Type(0), []peer.ID{worker}, execute.ResultMap{worker: failed})
+
+		_, _, ok := node.affinityPeers(functionID, 1, consensus.Type(0), token)
+		require.False(t, ok)
+	})
+	t.Run("binding is not used if a worker is unavailable", func(t *testing.T) {
+		t.Parallel()
+
+		node, worker := setup(t)
+
+		results := execute.ResultMap{
+			worker:              succeeded,
+			mocks.GenericPeerID: succeeded,
+		}
+		node.recordAffinity(request(token), consensus.Type(0), []peer.ID{worker, mocks.GenericPeerID}, results)
+
+		_, _, ok := node.affinityPeers(functionID, 2, consensus.Type(0), token)
+		require.False(t, ok)
+
+		// Worker is released.
+		require.True(t, node.dispatch.Acquire(worker))
+	})
+	t.Run("binding is not used without enough workers", func(t *testing.T) {
+		t.Parallel()
+
+		node, worker := setup(t)
+
+		node.recordAffinity(request(token), consensus.Type(0), []peer.ID{worker}, execute.ResultMap{worker: succeeded})
+
+		_, _, ok := node.affinityPeers(functionID, 2, consensus.Type(0), token)
+		require.False(t, ok)
+	})
+	t.Run("binding is not used for executions with consensus or without token", func(t *testing.T) {
+		t.Parallel()
+
+		node, worker := setup(t)
+
+		node.recordAffinity(request(token), consensus.Type(0), []peer.ID{worker}, execute.ResultMap{worker: succeeded})
+
+		_, _, ok := node.affinityPeers(functionID, 1, consensus.Raft, token)
+		require.False(t, ok)
+
+		_, _, ok = node.affinityPeers(functionID, 1, consensus.Type(0), "")
+		require.False(t, ok)
+	})
+}
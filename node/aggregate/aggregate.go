@@ -5,56 +5,85 @@ import (
 
 	"github.com/libp2p/go-libp2p/core/peer"
 
+	"github.com/blocklessnetwork/b7s/models/codes"
 	"github.com/blocklessnetwork/b7s/models/execute"
 )
 
 func Aggregate(results execute.ResultMap) Results {
 
-	total := len(results)
+	// Results not conforming to the function output schema are discarded.
+	valid := make(execute.ResultMap, len(results))
+	for executingPeer, res := range results {
+		if res.Code == codes.InvalidOutput {
+			continue
+		}
+		valid[executingPeer] = res
+	}
+
+	total := len(valid)
 	if total == 0 {
 		return nil
 	}
 
 	type resultStats struct {
-		seen     uint
-		peers    []peer.ID
-		metadata map[peer.ID]any
+		output    execute.RuntimeOutput
+		lowest    peer.ID // Peer whose output is reported.
+		seen      uint
+		peers     []peer.ID
+		metadata  map[peer.ID]any
+		artifacts NodeArtifacts
 	}
 
-	stats := make(map[execute.RuntimeOutput]resultStats)
-	for executingPeer, res := range results {
+	// Results are grouped by their output checksum, so results of functions normalizing their output are grouped
+	// by the normalized output. Each group reports the output of the peer with the lowest ID.
+	stats := make(map[string]resultStats)
+	for executingPeer, res := range valid {
 
 		// NOTE: It might make sense to ignore stderr in comparison.
-		output := res.Result.Result
+		checksum := res.OutputChecksum()
 
-		stat, ok := stats[output]
+		stat, ok := stats[checksum]
 		if !ok {
 			stat = resultStats{
-				seen:     0,
-				peers:    make([]peer.ID, 0),
-				metadata: make(map[peer.ID]any),
+				output:    res.Result.Result,
+				lowest:    executingPeer,
+				seen:      0,
+				peers:     make([]peer.ID, 0),
+				metadata:  make(map[peer.ID]any),
+				artifacts: make(NodeArtifacts),
 			}
 		}
 
+		if executingPeer < stat.lowest {
+			stat.output = res.Result.Result
+			stat.lowest = executingPeer
+		}
+
 		stat.seen++
 		stat.peers = append(stat.peers, executingPeer)
 		if res.Metadata != nil {
 			stat.metadata[executingPeer] = res.Metadata
 		}
+		if len(res.Artifacts) > 0 {
+			stat.artifacts[executingPeer] = res.Artifacts
+		}
 
-		stats[output] = stat
+		stats[checksum] = stat
 	}
 
 	// Convert map of results to a slice.
 	aggregated := make([]Result, 0, len(stats))
-	for res, stat := range stats {
+	for _, stat := range stats {
 
 		aggr := Result{
-			Result:    res,
+			Result:    stat.output,
 			Peers:     stat.peers,
 			Frequency: 100 * float64(stat.seen) / float64(total),
 			Metadata:  stat.metadata,
 		}
+		if len(stat.artifacts) > 0 {
+			aggr.Artifacts = stat.artifacts
+		}
 
 		aggregated = append(aggregated, aggr)
 	}
@@ -17,12 +17,16 @@ type Result struct {
 	Peers []peer.ID `json:"peers,omitempty"`
 	// Peers metadata
 	Metadata NodeMetadata `json:"metadata,omitempty"`
+	// Output files uploaded by each of the peers.
+	Artifacts NodeArtifacts `json:"artifacts,omitempty"`
 	// How frequent was this result, in percentages.
 	Frequency float64 `json:"frequency,omitempty"`
 }
 
 type NodeMetadata map[peer.ID]any
 
+type NodeArtifacts map[peer.ID][]execute.Artifact
+
 func (m NodeMetadata) MarshalJSON() ([]byte, error) {
 
 	em := make(map[string]any, len(m))
@@ -32,3 +36,13 @@ func (m NodeMetadata) MarshalJSON() ([]byte, error) {
 
 	return json.Marshal(em)
 }
+
+func (m NodeArtifacts) MarshalJSON() ([]byte, error) {
+
+	em := make(map[string][]execute.Artifact, len(m))
+	for p, v := range m {
+		em[p.String()] = v
+	}
+
+	return json.Marshal(em)
+}
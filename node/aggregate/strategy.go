@@ -0,0 +1,144 @@
+package aggregate
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+// Aggregator collapses execution results from multiple workers into a single response.
+type Aggregator interface {
+	// Aggregate returns the code for the response, along with the results that make it up.
+	Aggregate(results execute.ResultMap) (codes.Code, execute.ResultMap)
+}
+
+// Supported aggregation strategies.
+const (
+	StrategyFirstSuccess = "first-success"
+	StrategyMajority     = "majority"
+	StrategyAllMatch     = "all-match"
+)
+
+// Parse returns the aggregator for the given strategy name.
+func Parse(strategy string) (Aggregator, error) {
+
+	switch strategy {
+	case StrategyFirstSuccess:
+		return FirstSuccess{}, nil
+	case StrategyMajority:
+		return Majority{}, nil
+	case StrategyAllMatch:
+		return AllMatch{}, nil
+	}
+
+	return nil, fmt.Errorf("unknown aggregation strategy (%s)", strategy)
+}
+
+// FirstSuccess returns a single successful result. Peers are ordered by ID so the choice is deterministic.
+type FirstSuccess struct{}
+
+func (FirstSuccess) Aggregate(results execute.ResultMap) (codes.Code, execute.ResultMap) {
+
+	if len(results) == 0 {
+		return codes.NoContent, results
+	}
+
+	for _, executingPeer := range sortedPeers(results) {
+		res := results[executingPeer]
+		if res.Code == codes.OK {
+			return codes.OK, execute.ResultMap{executingPeer: res}
+		}
+	}
+
+	return codes.Error, results
+}
+
+// Majority returns the results with identical output, if more than half of all results have it.
+type Majority struct{}
+
+func (Majority) Aggregate(results execute.ResultMap) (codes.Code, execute.ResultMap) {
+
+	if len(results) == 0 {
+		return codes.NoContent, results
+	}
+
+	groups := groupSuccessful(results)
+
+	var largest execute.ResultMap
+	for _, group := range groups {
+		if len(group) > len(largest) {
+			largest = group
+		}
+	}
+
+	if 2*len(largest) <= len(results) {
+		return codes.Error, results
+	}
+
+	return codes.OK, largest
+}
+
+// AllMatch requires all results to be successful and have identical output.
+type AllMatch struct{}
+
+func (AllMatch) Aggregate(results execute.ResultMap) (codes.Code, execute.ResultMap) {
+
+	if len(results) == 0 {
+		return codes.NoContent, results
+	}
+
+	groups := groupSuccessful(results)
+	if len(groups) != 1 {
+		return codes.Error, results
+	}
+
+	for _, group := range groups {
+		if len(group) != len(results) {
+			return codes.Error, results
+		}
+	}
+
+	return codes.OK, results
+}
+
+// groupSuccessful groups successful results by their output checksum.
+func groupSuccessful(results execute.ResultMap) map[string]execute.ResultMap {
+
+	groups := make(map[string]execute.ResultMap)
+	for executingPeer, res := range results {
+
+		if res.Code != codes.OK {
+			continue
+		}
+
+		checksum := res.OutputChecksum()
+
+		group, ok := groups[checksum]
+		if !ok {
+			group = make(execute.ResultMap)
+			groups[checksum] = group
+		}
+
+		group[executingPeer] = res
+	}
+
+	return groups
+}
+
+func sortedPeers(results execute.ResultMap) []peer.ID {
+
+	peers := make([]peer.ID, 0, len(results))
+	for executingPeer := range results {
+		peers = append(peers, executingPeer)
+	}
+
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i] < peers[j]
+	})
+
+	return peers
+}
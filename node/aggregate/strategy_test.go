@@ -0,0 +1,116 @@
+package aggregate_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/node/aggregate"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestAggregator(t *testing.T) {
+
+	var (
+		first  = execute.RuntimeOutput{Stdout: "first-result"}
+		second = execute.RuntimeOutput{Stdout: "second-result"}
+
+		peers = mocks.GenericPeerIDs
+	)
+
+	result := func(code codes.Code, output execute.RuntimeOutput) execute.NodeResult {
+		return execute.NodeResult{
+			Result: execute.Result{
+				Code:   code,
+				Result: output,
+			},
+		}
+	}
+
+	t.Run("parse strategy", func(t *testing.T) {
+		t.Parallel()
+
+		for _, strategy := range []string{aggregate.StrategyFirstSuccess, aggregate.StrategyMajority, aggregate.StrategyAllMatch} {
+			aggregator, err := aggregate.Parse(strategy)
+			require.NoError(t, err)
+			require.NotNil(t, aggregator)
+		}
+
+		_, err := aggregate.Parse("dummy-strategy")
+		require.Error(t, err)
+	})
+	t.Run("first success", func(t *testing.T) {
+		t.Parallel()
+
+		results := execute.ResultMap{
+			peers[0]: result(codes.Error, second),
+			peers[1]: result(codes.OK, first),
+			peers[2]: result(codes.OK, first),
+		}
+
+		code, aggregated := aggregate.FirstSuccess{}.Aggregate(results)
+		require.Equal(t, codes.OK, code)
+		require.Len(t, aggregated, 1)
+		for _, res := range aggregated {
+			require.Equal(t, first, res.Result.Result)
+		}
+
+		code, _ = aggregate.FirstSuccess{}.Aggregate(execute.ResultMap{peers[0]: result(codes.Error, second)})
+		require.Equal(t, codes.Error, code)
+	})
+	t.Run("majority", func(t *testing.T) {
+		t.Parallel()
+
+		results := execute.ResultMap{
+			peers[0]: result(codes.OK, first),
+			peers[1]: result(codes.OK, first),
+			peers[2]: result(codes.OK, second),
+		}
+
+		code, aggregated := aggregate.Majority{}.Aggregate(results)
+		require.Equal(t, codes.OK, code)
+		require.Len(t, aggregated, 2)
+		require.Contains(t, aggregated, peers[0])
+		require.Contains(t, aggregated, peers[1])
+
+		// Failed results count towards the total.
+		results[peers[2]] = result(codes.Error, first)
+		results[peers[3]] = result(codes.Timeout, execute.RuntimeOutput{})
+
+		code, aggregated = aggregate.Majority{}.Aggregate(results)
+		require.Equal(t, codes.Error, code)
+		require.Equal(t, results, aggregated)
+	})
+	t.Run("all match", func(t *testing.T) {
+		t.Parallel()
+
+		results := execute.ResultMap{
+			peers[0]: result(codes.OK, first),
+			peers[1]: result(codes.OK, first),
+		}
+
+		code, aggregated := aggregate.AllMatch{}.Aggregate(results)
+		require.Equal(t, codes.OK, code)
+		require.Equal(t, results, aggregated)
+
+		results[peers[2]] = result(codes.OK, second)
+
+		code, _ = aggregate.AllMatch{}.Aggregate(results)
+		require.Equal(t, codes.Error, code)
+
+		results[peers[2]] = result(codes.Error, first)
+
+		code, _ = aggregate.AllMatch{}.Aggregate(results)
+		require.Equal(t, codes.Error, code)
+	})
+	t.Run("no results", func(t *testing.T) {
+		t.Parallel()
+
+		for _, aggregator := range []aggregate.Aggregator{aggregate.FirstSuccess{}, aggregate.Majority{}, aggregate.AllMatch{}} {
+			code, _ := aggregator.Aggregate(nil)
+			require.Equal(t, codes.NoContent, code)
+		}
+	})
+}
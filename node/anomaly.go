@@ -0,0 +1,48 @@
+package node
+
+import (
+	"github.com/armon/go-metrics"
+
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+// detectAnomalies checks execution results against the history of the function, if the node has an anomaly detector.
+// Anomalous results are tagged with the kind of anomaly found.
+func (n *Node) detectAnomalies(requestID string, functionID string, results execute.ResultMap) {
+
+	if n.cfg.AnomalyDetector == nil {
+		return
+	}
+
+	for peer, res := range results {
+
+		anomalies := n.cfg.AnomalyDetector.Check(functionID, res)
+		if len(anomalies) == 0 {
+			continue
+		}
+
+		for _, a := range anomalies {
+
+			n.log.Warn().
+				Str("request", requestID).
+				Str("function", functionID).
+				Str("peer", peer.String()).
+				Str("kind", a.Kind).
+				Float64("value", a.Value).
+				Float64("mean", a.Mean).
+				Float64("stddev", a.StdDev).
+				Float64("distance", a.Distance).
+				Msg("anomalous execution result")
+
+			n.metrics.IncrCounterWithLabels(anomalousResultsMetric, 1,
+				[]metrics.Label{
+					{Name: "function", Value: functionID},
+					{Name: "kind", Value: a.Kind},
+				})
+
+			res.Anomalies = append(res.Anomalies, a.Kind)
+		}
+
+		results[peer] = res
+	}
+}
@@ -0,0 +1,53 @@
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/node/head/anomaly"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_DetectAnomalies(t *testing.T) {
+
+	const (
+		requestID  = "dummy-request-id"
+		functionID = "dummy-function-id"
+	)
+
+	var (
+		typical = mocks.GenericPeerIDs[0]
+		hung    = mocks.GenericPeerIDs[1]
+	)
+
+	result := func(duration time.Duration) execute.NodeResult {
+		return execute.NodeResult{
+			Result: execute.Result{
+				Code:  codes.OK,
+				Usage: execute.Usage{WallClockTime: duration},
+			},
+		}
+	}
+
+	node := createNode(t, blockless.HeadNode)
+	node.cfg.AnomalyDetector = anomaly.NewDetector(4)
+
+	for i := 0; i < anomaly.DefaultMinSamples; i++ {
+		node.detectAnomalies(requestID, functionID, execute.ResultMap{typical: result(time.Second)})
+	}
+
+	results := execute.ResultMap{
+		typical: result(time.Second),
+		hung:    result(time.Hour),
+	}
+
+	node.detectAnomalies(requestID, functionID, results)
+
+	require.Empty(t, results[typical].Anomalies)
+	require.Equal(t, []string{anomaly.KindDuration}, results[hung].Anomalies)
+}
@@ -0,0 +1,68 @@
+package node
+
+import (
+	"context"
+	"time"
+
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+// saveExecutionResults records the final results of an execution request, so they can be retrieved later.
+func (n *Node) saveExecutionResults(requestID string, results execute.ResultMap) {
+
+	n.executeResponses.Set(requestID, results)
+
+	if n.cfg.ResultArchive == nil {
+		return
+	}
+
+	err := n.cfg.ResultArchive.Save(requestID, results, time.Now())
+	if err != nil {
+		n.log.Error().Err(err).Str("request", requestID).Msg("could not save execution results")
+	}
+}
+
+// archivedExecutionResults looks up execution results no longer kept in the result store.
+func (n *Node) archivedExecutionResults(requestID string) (execute.ResultMap, bool) {
+
+	if n.cfg.ResultArchive == nil {
+		return nil, false
+	}
+
+	results, ok, err := n.cfg.ResultArchive.Get(requestID)
+	if err != nil {
+		n.log.Error().Err(err).Str("request", requestID).Msg("could not retrieve archived execution results")
+		return nil, false
+	}
+
+	return results, ok
+}
+
+// runArchiver periodically moves old execution results to the archive.
+func (n *Node) runArchiver(ctx context.Context) {
+
+	if n.cfg.ResultArchive == nil {
+		return
+	}
+
+	ticker := time.NewTicker(archiveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case now := <-ticker.C:
+			archived, err := n.cfg.ResultArchive.Archive(now)
+			if err != nil {
+				n.log.Error().Err(err).Int("archived", archived).Msg("could not archive execution results")
+			}
+
+			if archived > 0 {
+				n.log.Info().Int("archived", archived).Msg("archived execution results")
+				n.metrics.IncrCounter(archivedResultsMetric, float32(archived))
+			}
+		}
+	}
+}
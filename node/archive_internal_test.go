@@ -0,0 +1,42 @@
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/node/head/archive"
+	"github.com/blocklessnetwork/b7s/testing/helpers"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_ArchivedExecutionResult(t *testing.T) {
+
+	const (
+		requestID = "dummy-request-id"
+	)
+
+	db := helpers.InMemoryDB(t)
+	defer db.Close()
+
+	node := createNode(t, blockless.HeadNode)
+	node.cfg.ResultArchive = archive.New(db, archive.NewDirStore(afero.NewMemMapFs(), "/archive"), time.Hour)
+
+	// Results missing from the result store are looked up in the archive.
+	err := node.cfg.ResultArchive.Save(requestID, mocks.GenericExecutionResultMap, time.Now().Add(-2*time.Hour))
+	require.NoError(t, err)
+
+	archived, err := node.cfg.ResultArchive.Archive(time.Now())
+	require.NoError(t, err)
+	require.Equal(t, 1, archived)
+
+	results, ok := node.ExecutionResult(requestID)
+	require.True(t, ok)
+	require.Equal(t, mocks.GenericExecutionResultMap, results)
+
+	_, ok = node.ExecutionResult("dummy-request-unknown")
+	require.False(t, ok)
+}
@@ -0,0 +1,89 @@
+package node
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/b7serrors"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+// attemptLog records the failed attempts of an execution, so the response can show the retries the head node made.
+type attemptLog struct {
+	sync.Mutex
+	failed []execute.Attempt
+	// last is the time the current attempt started.
+	last time.Time
+}
+
+func newAttemptLog() *attemptLog {
+	return &attemptLog{
+		last: time.Now(),
+	}
+}
+
+// fail records an attempt that started at the given time and failed. The attempt that follows starts now.
+func (l *attemptLog) fail(stage string, peers []peer.ID, code codes.Code, started time.Time, reason string) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.failed = append(l.failed, execute.Attempt{
+		Stage:    stage,
+		Peers:    peers,
+		Code:     code,
+		Duration: execute.Since(started),
+		Reason:   reason,
+	})
+	l.last = time.Now()
+}
+
+// history returns the attempts made, ending with the given ones. If there are none, the final attempt is used instead.
+// History is empty if no attempt failed, and there are no nested attempts (e.g. reported by a peer head node).
+func (l *attemptLog) history(attempts []execute.Attempt, stage string, peers []peer.ID, code codes.Code, err error) []execute.Attempt {
+	l.Lock()
+	defer l.Unlock()
+
+	if len(l.failed) == 0 {
+		return attempts
+	}
+
+	if len(attempts) == 0 {
+		attempts = []execute.Attempt{{
+			Stage:    stage,
+			Peers:    peers,
+			Code:     code,
+			Duration: execute.Since(l.last),
+			Reason:   attemptReason(err),
+		}}
+	}
+
+	return append(slices.Clone(l.failed), attempts...)
+}
+
+// attemptReason returns the reason for the failure that can be communicated to the client.
+func attemptReason(err error) string {
+	if !b7serrors.IsPublic(err) {
+		return ""
+	}
+	return err.Error()
+}
+
+// resultPeers returns the peers that produced the results, falling back to the given peers if there are no results.
+func resultPeers(results execute.ResultMap, peers []peer.ID) []peer.ID {
+
+	if len(results) == 0 {
+		return peers
+	}
+
+	out := make([]peer.ID, 0, len(results))
+	for id := range results {
+		out = append(out, id)
+	}
+	slices.Sort(out)
+
+	return out
+}
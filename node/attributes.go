@@ -4,6 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/ipfs/boxo/ipns"
 
@@ -11,6 +14,7 @@ import (
 	"github.com/libp2p/go-libp2p/core/peer"
 
 	"github.com/blocklessnetwork/b7s-attributes/attributes"
+	"github.com/blocklessnetwork/b7s/executor/gpu"
 	"github.com/blocklessnetwork/b7s/models/blockless"
 	"github.com/blocklessnetwork/b7s/models/execute"
 )
@@ -57,6 +61,21 @@ func ipnsGatewayURL(name string) string {
 	return fmt.Sprintf("https://%s.ipns.cf-ipfs.com/%s", name, defaultAttributesFilename)
 }
 
+// verifyAttributes checks the signatures of the attestation, and that it was signed by the peer itself.
+func verifyAttributes(id peer.ID, att attributes.Attestation) error {
+
+	err := attributes.Validate(att)
+	if err != nil {
+		return fmt.Errorf("could not validate attestation: %w", err)
+	}
+
+	if att.Signature != nil && att.Signature.Signer != id {
+		return fmt.Errorf("attestation signed by a different peer (signer: %s)", att.Signature.Signer)
+	}
+
+	return nil
+}
+
 func haveAttributes(have attributes.Attestation, want execute.Attributes) error {
 
 	if want.AttestationRequired && len(have.Attestors) == 0 {
@@ -99,7 +118,7 @@ func haveAttributes(have attributes.Attestation, want execute.Attributes) error
 
 	// It doesn't make a lot of sense to require attestors without wanting specific attributes,
 	// but if that's the case, and there's no attributes wanted, we're done now.
-	if len(want.Values) == 0 {
+	if len(want.Values) == 0 && want.Expression == "" && want.GPU == nil {
 		return nil
 	}
 
@@ -120,5 +139,66 @@ func haveAttributes(have attributes.Attestation, want execute.Attributes) error
 		}
 	}
 
+	if want.GPU != nil {
+		err := want.GPU.Match(attrs)
+		if err != nil {
+			return fmt.Errorf("GPU requirements not met: %w", err)
+		}
+	}
+
+	if want.Expression != "" {
+
+		expr, err := execute.ParseAttributeExpression(want.Expression)
+		if err != nil {
+			return fmt.Errorf("could not parse attribute expression: %w", err)
+		}
+
+		if !expr.Match(attrs) {
+			return fmt.Errorf("attributes do not match expression (expression: %v)", want.Expression)
+		}
+	}
+
 	return nil
 }
+
+// matchedAttributes returns the attributes roll calls are matched against - the attributes of the detected GPUs, followed by
+// the attested attributes, which take precedence. Detected attributes are not attested. If the node has no attributes, ok is false.
+func (n *Node) matchedAttributes() (attributes.Attestation, bool) {
+
+	detected := gpuAttributes(n.cfg.GPUs)
+	if n.attributes == nil {
+		return attributes.Attestation{Attributes: detected}, len(detected) > 0
+	}
+
+	have := *n.attributes
+	have.Attributes = slices.Concat(detected, n.attributes.Attributes)
+
+	return have, true
+}
+
+// gpuAttributes returns the attributes describing the GPUs.
+func gpuAttributes(devices []gpu.Device) []attributes.Attribute {
+
+	if len(devices) == 0 {
+		return nil
+	}
+
+	var (
+		models []string
+		vram   = devices[0].VRAM
+	)
+	for _, device := range devices {
+		if !slices.Contains(models, device.Model) {
+			models = append(models, device.Model)
+		}
+		vram = min(vram, device.VRAM)
+	}
+
+	attrs := []attributes.Attribute{
+		{Name: execute.AttributeGPUCount, Value: strconv.Itoa(len(devices))},
+		{Name: execute.AttributeGPUModel, Value: strings.Join(models, ",")},
+		{Name: execute.AttributeGPUVRAM, Value: strconv.FormatUint(uint64(vram), 10)},
+	}
+
+	return attrs
+}
@@ -0,0 +1,80 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s-attributes/attributes"
+	"github.com/blocklessnetwork/b7s/executor/gpu"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+func TestNode_GPUAttributes(t *testing.T) {
+
+	devices := []gpu.Device{
+		{Index: "0", Model: "NVIDIA A100-SXM4-80GB", VRAM: 81920},
+		{Index: "1", Model: "NVIDIA A100-SXM4-40GB", VRAM: 40960},
+	}
+
+	t.Run("attributes describe the GPUs", func(t *testing.T) {
+		expected := []attributes.Attribute{
+			{Name: execute.AttributeGPUCount, Value: "2"},
+			{Name: execute.AttributeGPUModel, Value: "NVIDIA A100-SXM4-80GB,NVIDIA A100-SXM4-40GB"},
+			{Name: execute.AttributeGPUVRAM, Value: "40960"},
+		}
+		require.Equal(t, expected, gpuAttributes(devices))
+		require.Empty(t, gpuAttributes(nil))
+	})
+	t.Run("worker without attributes", func(t *testing.T) {
+		node := createNode(t, blockless.WorkerNode)
+
+		_, ok := node.matchedAttributes()
+		require.False(t, ok)
+	})
+	t.Run("worker with GPUs matches GPU requirements", func(t *testing.T) {
+		node := createNode(t, blockless.WorkerNode)
+		node.cfg.GPUs = devices
+
+		have, ok := node.matchedAttributes()
+		require.True(t, ok)
+
+		err := haveAttributes(have, execute.Attributes{GPU: &execute.GPURequirements{Count: 2, Model: "A100", VRAM: 40960}})
+		require.NoError(t, err)
+
+		err = haveAttributes(have, execute.Attributes{GPU: &execute.GPURequirements{VRAM: 81920}})
+		require.Error(t, err)
+
+		err = haveAttributes(have, execute.Attributes{Expression: `gpu.count >= 2 && gpu.vram >= 32768`})
+		require.NoError(t, err)
+	})
+	t.Run("attested attributes take precedence", func(t *testing.T) {
+		node := createNode(t, blockless.WorkerNode)
+		node.cfg.GPUs = devices
+		node.attributes = &attributes.Attestation{
+			Attributes: []attributes.Attribute{
+				{Name: execute.AttributeGPUCount, Value: "1"},
+				{Name: "region", Value: "eu"},
+			},
+		}
+
+		have, ok := node.matchedAttributes()
+		require.True(t, ok)
+
+		err := haveAttributes(have, execute.Attributes{
+			Values: []execute.Parameter{{Name: "region", Value: "eu"}},
+			GPU:    &execute.GPURequirements{Count: 2},
+		})
+		require.Error(t, err)
+
+		err = haveAttributes(have, execute.Attributes{
+			Values: []execute.Parameter{{Name: "region", Value: "eu"}},
+			GPU:    &execute.GPURequirements{Model: "A100"},
+		})
+		require.NoError(t, err)
+
+		// Attestation of the node is not modified.
+		require.Len(t, node.attributes.Attributes, 2)
+	})
+}
@@ -0,0 +1,109 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/node/head/backup"
+)
+
+var errStoreMaintenanceNotSupported = errors.New("store maintenance not configured")
+
+// CompactStore compacts the database backing the head node stores.
+func (n *Node) CompactStore() error {
+
+	if n.cfg.Backups == nil {
+		return errStoreMaintenanceNotSupported
+	}
+
+	start := time.Now()
+	err := n.cfg.Backups.Compact()
+	if err != nil {
+		return err
+	}
+
+	n.log.Info().Dur("duration", time.Since(start)).Msg("store compacted")
+
+	return nil
+}
+
+// BackupStore creates a snapshot of the database backing the head node stores in the backup store.
+func (n *Node) BackupStore() (backup.Snapshot, error) {
+
+	if n.cfg.Backups == nil {
+		return backup.Snapshot{}, errStoreMaintenanceNotSupported
+	}
+
+	snapshot, err := n.cfg.Backups.Backup(time.Now())
+	if err != nil {
+		return backup.Snapshot{}, err
+	}
+
+	n.log.Info().Str("snapshot", snapshot.Name).Int("files", len(snapshot.Files)).Uint64("size", snapshot.Size).Msg("store backup created")
+
+	return snapshot, nil
+}
+
+func (n *Node) processStoreMaintenance(ctx context.Context, from peer.ID, req request.StoreMaintenance) error {
+
+	log := n.log.With().Str("peer", from.String()).Str("operation", string(req.Operation)).Logger()
+
+	if !slices.Contains(n.cfg.AdminPeers, from) {
+		log.Warn().Msg("rejecting store maintenance from peer that is not an admin")
+
+		err := n.send(ctx, from, req.Response(codes.NotPermitted))
+		if err != nil {
+			return fmt.Errorf("could not send response: %w", err)
+		}
+
+		return nil
+	}
+
+	err := req.Valid()
+	if err != nil {
+		err = n.send(ctx, from, req.Response(codes.Invalid).WithErrorMessage(err))
+		if err != nil {
+			return fmt.Errorf("could not send response: %w", err)
+		}
+		return nil
+	}
+
+	res := req.Response(codes.OK)
+
+	switch req.Operation {
+	case request.StoreCompact:
+		err = n.CompactStore()
+
+	case request.StoreBackup:
+		var snapshot backup.Snapshot
+		snapshot, err = n.BackupStore()
+		res.WithSnapshot(snapshot.Name)
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("store maintenance failed")
+
+		code := codes.Error
+		if errors.Is(err, errStoreMaintenanceNotSupported) {
+			code = codes.NotSupported
+		}
+
+		res = req.Response(code).WithErrorMessage(err)
+	}
+
+	n.metrics.IncrCounterWithLabels(storeMaintenanceMetric, 1, []metrics.Label{{Name: "operation", Value: string(req.Operation)}})
+
+	err = n.send(ctx, from, res)
+	if err != nil {
+		return fmt.Errorf("could not send response: %w", err)
+	}
+
+	return nil
+}
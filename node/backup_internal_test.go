@@ -0,0 +1,124 @@
+package node
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/node/head/archive"
+	"github.com/blocklessnetwork/b7s/node/head/backup"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_StoreMaintenance(t *testing.T) {
+
+	const (
+		dbDir = "/db"
+	)
+
+	// Send the request from a separate host and return the response the head node sent back.
+	process := func(t *testing.T, node *Node, admin bool, req request.StoreMaintenance) response.StoreMaintenance {
+		t.Helper()
+
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, receiver)
+
+		if admin {
+			node.cfg.AdminPeers = []peer.ID{receiver.ID()}
+		}
+
+		var (
+			wg       sync.WaitGroup
+			received response.StoreMaintenance
+		)
+		wg.Add(1)
+
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer wg.Done()
+			defer stream.Close()
+
+			getStreamPayload(t, stream, &received)
+		})
+
+		err = node.processStoreMaintenance(context.Background(), receiver.ID(), req)
+		require.NoError(t, err)
+
+		wg.Wait()
+
+		return received
+	}
+
+	createBackups := func(t *testing.T) *backup.Manager {
+		t.Helper()
+
+		fs := vfs.NewMem()
+		db, err := pebble.Open(dbDir, &pebble.Options{FS: fs})
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+
+		require.NoError(t, db.Set([]byte("dummy-key"), []byte("dummy-value"), pebble.Sync))
+
+		return backup.NewManager(db, fs, dbDir, archive.NewDirStore(afero.NewMemMapFs(), "/backups"))
+	}
+
+	t.Run("admin can request backup", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+		node.cfg.Backups = createBackups(t)
+
+		res := process(t, node, true, request.StoreMaintenance{Operation: request.StoreBackup})
+		require.Equal(t, codes.OK, res.Code)
+		require.NotEmpty(t, res.Snapshot)
+	})
+	t.Run("admin can request compaction", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+		node.cfg.Backups = createBackups(t)
+
+		res := process(t, node, true, request.StoreMaintenance{Operation: request.StoreCompact})
+		require.Equal(t, codes.OK, res.Code)
+		require.Empty(t, res.Snapshot)
+	})
+	t.Run("maintenance requested by peer that is not an admin is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+		node.cfg.Backups = createBackups(t)
+
+		res := process(t, node, false, request.StoreMaintenance{Operation: request.StoreBackup})
+		require.Equal(t, codes.NotPermitted, res.Code)
+	})
+	t.Run("maintenance without backups configured is not supported", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+
+		res := process(t, node, true, request.StoreMaintenance{Operation: request.StoreCompact})
+		require.Equal(t, codes.NotSupported, res.Code)
+	})
+	t.Run("unknown operation is invalid", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+		node.cfg.Backups = createBackups(t)
+
+		res := process(t, node, true, request.StoreMaintenance{Operation: "defragment"})
+		require.Equal(t, codes.Invalid, res.Code)
+	})
+}
@@ -0,0 +1,62 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+// deliverAsyncResponse delivers the results of an asynchronous execution - either to the callback URL, or
+// to the peer that requested the execution.
+func (n *Node) deliverAsyncResponse(ctx context.Context, from peer.ID, req request.Execute, res *response.Execute) {
+
+	log := n.log.With().Str("request", req.RequestID).Str("peer", from.String()).Logger()
+
+	var err error
+	if req.Callback != "" {
+		err = n.postCallback(ctx, req.Callback, res)
+	} else {
+		err = n.send(ctx, from, res)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("callback", req.Callback).Msg("could not deliver asynchronous execution results")
+		n.metrics.IncrCounter(callbackDeliveryFailedMetric, 1)
+		return
+	}
+
+	log.Info().Str("callback", req.Callback).Msg("asynchronous execution results delivered")
+}
+
+// postCallback posts the execution response to the given URL. Callback URLs must resolve to public addresses, and redirects are not followed.
+func (n *Node) postCallback(ctx context.Context, url string, res *response.Execute) error {
+
+	payload, err := json.Marshal(res)
+	if err != nil {
+		return fmt.Errorf("could not encode execution response: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("could not create callback request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.callbacks.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not post execution response: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected callback response status: %v", resp.StatusCode)
+	}
+
+	return nil
+}
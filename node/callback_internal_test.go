@@ -0,0 +1,214 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/safehttp"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_AsyncExecution(t *testing.T) {
+
+	const (
+		rollCallTimeout = 100 * time.Millisecond
+	)
+
+	t.Run("results are sent to the requesting peer", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+		node.cfg.RollCallTimeout = rollCallTimeout
+
+		ctx := context.Background()
+		err := node.subscribeToTopics(ctx)
+		require.NoError(t, err)
+
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, receiver)
+
+		var (
+			wg       sync.WaitGroup
+			lock     sync.Mutex
+			received []response.Execute
+		)
+		wg.Add(2)
+
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer wg.Done()
+			defer stream.Close()
+
+			var res response.Execute
+			getStreamPayload(t, stream, &res)
+
+			lock.Lock()
+			defer lock.Unlock()
+			received = append(received, res)
+		})
+
+		req := request.Execute{
+			Request: mocks.GenericExecutionRequest,
+			Async:   true,
+		}
+
+		err = node.processExecute(ctx, receiver.ID(), req)
+		require.NoError(t, err)
+
+		wg.Wait()
+
+		// Execution is acknowledged first, and the results are delivered once the execution is done.
+		require.Len(t, received, 2)
+		require.Equal(t, codes.Accepted, received[0].Code)
+		require.NotEmpty(t, received[0].RequestID)
+
+		require.Equal(t, received[0].RequestID, received[1].RequestID)
+		require.Equal(t, codes.Timeout, received[1].Code)
+	})
+	t.Run("results are posted to the callback", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			clientRequestID = "dummy-client-request-id"
+		)
+
+		var (
+			wg       sync.WaitGroup
+			received response.Execute
+		)
+		wg.Add(2)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer wg.Done()
+
+			require.Equal(t, http.MethodPost, r.Method)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer srv.Close()
+
+		node := createNode(t, blockless.HeadNode)
+		node.cfg.RollCallTimeout = rollCallTimeout
+		// Test server listens on a loopback address.
+		node.callbacks = srv.Client()
+
+		ctx := context.Background()
+		err := node.subscribeToTopics(ctx)
+		require.NoError(t, err)
+
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, receiver)
+
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer wg.Done()
+			defer stream.Close()
+
+			var ack response.Execute
+			getStreamPayload(t, stream, &ack)
+
+			require.Equal(t, codes.Accepted, ack.Code)
+			require.Equal(t, clientRequestID, ack.RequestID)
+		})
+
+		req := request.Execute{
+			Request:   mocks.GenericExecutionRequest,
+			RequestID: clientRequestID,
+			Async:     true,
+			Callback:  srv.URL,
+		}
+
+		err = node.processExecute(ctx, receiver.ID(), req)
+		require.NoError(t, err)
+
+		wg.Wait()
+
+		require.Equal(t, clientRequestID, received.RequestID)
+		require.Equal(t, codes.Timeout, received.Code)
+	})
+	t.Run("results of REST API executions are posted to the callback", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			wg       sync.WaitGroup
+			received response.Execute
+		)
+		wg.Add(1)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer wg.Done()
+
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer srv.Close()
+
+		node := createNode(t, blockless.HeadNode)
+		node.cfg.RollCallTimeout = rollCallTimeout
+		node.callbacks = srv.Client()
+
+		ctx := context.Background()
+		err := node.subscribeToTopics(ctx)
+		require.NoError(t, err)
+
+		// Execution continues after the API request is done.
+		rctx, cancel := context.WithCancel(ctx)
+		code, requestID, err := node.ExecuteFunctionAsync(rctx, mocks.GenericExecutionRequest, "", srv.URL)
+		cancel()
+		require.NoError(t, err)
+		require.Equal(t, codes.Accepted, code)
+		require.NotEmpty(t, requestID)
+
+		wg.Wait()
+
+		require.Equal(t, requestID, received.RequestID)
+		require.Equal(t, codes.Timeout, received.Code)
+	})
+	t.Run("callbacks to non-public addresses are refused", func(t *testing.T) {
+		t.Parallel()
+
+		var called bool
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer srv.Close()
+
+		node := createNode(t, blockless.HeadNode)
+
+		err := node.postCallback(context.Background(), srv.URL, &response.Execute{})
+		require.ErrorIs(t, err, safehttp.ErrNonPublicAddress)
+		require.False(t, called)
+	})
+	t.Run("callback requires async execution", func(t *testing.T) {
+		t.Parallel()
+
+		req := request.Execute{
+			Request:  mocks.GenericExecutionRequest,
+			Callback: "https://example.com/results",
+		}
+		require.Error(t, req.Valid())
+
+		req.Async = true
+		require.NoError(t, req.Valid())
+
+		req.Callback = "ftp://example.com/results"
+		require.Error(t, req.Valid())
+	})
+}
@@ -0,0 +1,119 @@
+package node
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/node/head/canary"
+	"github.com/blocklessnetwork/b7s/node/internal/reputation"
+)
+
+// runCanaries periodically executes the canary probes in each subgroup the head node is part of.
+func (n *Node) runCanaries(ctx context.Context) {
+
+	var wg sync.WaitGroup
+	for _, probe := range n.cfg.Canaries {
+		wg.Add(1)
+		go func(probe Canary) {
+			defer wg.Done()
+
+			ticker := time.NewTicker(probe.Interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+
+				case <-ticker.C:
+					// Wait for probes in all subgroups to finish, so that a slow subgroup does not pile up probes.
+					var probes sync.WaitGroup
+					for _, subgroup := range n.cfg.Topics {
+						probes.Add(1)
+						go func(subgroup string) {
+							defer probes.Done()
+							n.runCanary(ctx, probe, subgroup)
+						}(subgroup)
+					}
+					probes.Wait()
+				}
+			}
+		}(probe)
+	}
+
+	wg.Wait()
+}
+
+// runCanary executes the canary probe on a sample of workers in the subgroup and compares their results.
+// Workers returning results different from the rest lose reputation. Failed executions are already accounted for
+// when workers are released after the execution.
+func (n *Node) runCanary(ctx context.Context, probe Canary, subgroup string) canary.Report {
+
+	requestID := n.newRequestID()
+
+	log := n.log.With().Str("request", requestID).Str("function", probe.FunctionID).Str("subgroup", subgroup).Logger()
+
+	n.metrics.IncrCounterWithLabels(canaryExecutionsMetric, 1,
+		[]metrics.Label{
+			{Name: "function", Value: probe.FunctionID},
+			{Name: "subgroup", Value: subgroup},
+		})
+
+	req := execute.Request{
+		FunctionID: probe.FunctionID,
+		Method:     probe.Method,
+		Config: execute.Config{
+			NodeCount: int(probe.Sample),
+		},
+	}
+
+	// Give up on the probe before the next one is due. Probes measure the subgroup, so they never fall back to the default topic.
+	pctx, cancel := context.WithTimeout(withoutRollCallFallback(ctx), probe.Interval)
+	defer cancel()
+
+	code, results, _, _, err := n.headExecute(pctx, requestID, req, subgroup, nil)
+	if err != nil {
+		log.Warn().Err(err).Msg("canary execution failed")
+	}
+
+	report := n.canaries.Evaluate(subgroup, probe.FunctionID, int(probe.Sample), results)
+	for _, peer := range report.Mismatched {
+		n.reputation.Record(peer, reputation.Mismatch)
+	}
+
+	log.Debug().
+		Str("code", code.String()).
+		Float64("healthy", report.Healthy()).
+		Dur("latency", report.Latency).
+		Dur("baseline", report.Baseline).
+		Strs("mismatched", blockless.PeerIDsToStr(report.Mismatched)).
+		Strs("failed", blockless.PeerIDsToStr(report.Failed)).
+		Msg("canary execution complete")
+
+	switch {
+	case report.Degraded && report.Changed:
+		n.metrics.IncrCounterWithLabels(canaryDegradedMetric, 1,
+			[]metrics.Label{
+				{Name: "function", Value: probe.FunctionID},
+				{Name: "subgroup", Value: subgroup},
+			})
+
+		log.Warn().
+			Float64("healthy", report.Healthy()).
+			Dur("latency", report.Latency).
+			Dur("baseline", report.Baseline).
+			Strs("mismatched", blockless.PeerIDsToStr(report.Mismatched)).
+			Strs("failed", blockless.PeerIDsToStr(report.Failed)).
+			Msg("subgroup degraded")
+
+	case report.Changed:
+		log.Info().Float64("healthy", report.Healthy()).Dur("latency", report.Latency).Msg("subgroup recovered")
+	}
+
+	return report
+}
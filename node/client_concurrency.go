@@ -0,0 +1,146 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/node/internal/workqueue"
+)
+
+// clientConcurrency limits the number of executions each client - a peer on the head node, or a tenant on the worker - can have
+// in flight at a time. Requests over the limit wait for one of the client's executions to complete, and are turned down once
+// the client has too many waiting requests.
+type clientConcurrency[K comparable] struct {
+	sync.Mutex
+
+	limit   uint
+	queue   uint
+	clients map[K]*clientQueue
+}
+
+type clientQueue struct {
+	*workqueue.Queue
+	refs uint
+}
+
+func newClientConcurrency[K comparable](limit uint, queue uint) *clientConcurrency[K] {
+
+	c := clientConcurrency[K]{
+		limit:   limit,
+		queue:   queue,
+		clients: make(map[K]*clientQueue),
+	}
+
+	return &c
+}
+
+// acquire waits until the client can have another execution in flight. If the client already has too many requests waiting,
+// `workqueue.ErrFull` is returned right away. Successful acquire must be followed by a release.
+func (c *clientConcurrency[K]) acquire(ctx context.Context, client K, priority uint) error {
+
+	c.Lock()
+	q, ok := c.clients[client]
+	if !ok {
+		q = &clientQueue{Queue: workqueue.New(c.limit, c.queue)}
+		c.clients[client] = q
+	}
+	q.refs++
+	c.Unlock()
+
+	err := q.Acquire(ctx, priority)
+	if err != nil {
+		c.done(client, q)
+		return err
+	}
+
+	return nil
+}
+
+// release frees up an execution slot of the client.
+func (c *clientConcurrency[K]) release(client K) {
+
+	c.Lock()
+	q, ok := c.clients[client]
+	c.Unlock()
+
+	if !ok {
+		return
+	}
+
+	q.Release()
+	c.done(client, q)
+}
+
+// done drops the reference to the client queue, removing the queue once no one is using it.
+func (c *clientConcurrency[K]) done(client K, q *clientQueue) {
+	c.Lock()
+	defer c.Unlock()
+
+	q.refs--
+	if q.refs == 0 {
+		delete(c.clients, client)
+	}
+}
+
+// acquireClientSlot waits until the client can have another execution in flight. It returns false if the client
+// has too many requests waiting already. Acquired slots must be released.
+func (n *Node) acquireClientSlot(ctx context.Context, client peer.ID, priority uint) (bool, error) {
+
+	if n.clientLimits == nil {
+		return true, nil
+	}
+
+	err := n.clientLimits.acquire(ctx, client, priority)
+	if errors.Is(err, workqueue.ErrFull) {
+		n.metrics.IncrCounter(clientTooManyInFlightMetric, 1)
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// releaseClientSlot frees up an execution slot of the client.
+func (n *Node) releaseClientSlot(client peer.ID) {
+
+	if n.clientLimits == nil {
+		return
+	}
+
+	n.clientLimits.release(client)
+}
+
+// acquireTenantSlot waits until the tenant can have another execution in flight on the worker. It returns false if the tenant
+// has too many requests waiting already. Acquired slots must be released.
+func (n *Node) acquireTenantSlot(ctx context.Context, tenant string, priority uint) (bool, error) {
+
+	if n.tenantLimits == nil {
+		return true, nil
+	}
+
+	err := n.tenantLimits.acquire(ctx, tenant, priority)
+	if errors.Is(err, workqueue.ErrFull) {
+		n.metrics.IncrCounter(tenantTooManyInFlightMetric, 1)
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// releaseTenantSlot frees up an execution slot of the tenant.
+func (n *Node) releaseTenantSlot(tenant string) {
+
+	if n.tenantLimits == nil {
+		return
+	}
+
+	n.tenantLimits.release(tenant)
+}
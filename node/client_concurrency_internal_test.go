@@ -0,0 +1,63 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_ClientConcurrency(t *testing.T) {
+
+	var (
+		client = mocks.GenericPeerIDs[0]
+		other  = mocks.GenericPeerIDs[1]
+	)
+
+	node := createNode(t, blockless.HeadNode)
+	node.clientLimits = newClientConcurrency[peer.ID](1, 1)
+
+	ctx := context.Background()
+
+	ok, err := node.acquireClientSlot(ctx, client, 0)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// Other clients are not affected.
+	ok, err = node.acquireClientSlot(ctx, other, 0)
+	require.NoError(t, err)
+	require.True(t, ok)
+	node.releaseClientSlot(other)
+
+	// Second request of the client waits for the first one to complete.
+	acquired := make(chan struct{})
+	go func() {
+		ok, err := node.acquireClientSlot(ctx, client, 0)
+		require.NoError(t, err)
+		require.True(t, ok)
+		close(acquired)
+	}()
+
+	require.Eventually(t, func() bool {
+		node.clientLimits.Lock()
+		defer node.clientLimits.Unlock()
+		return node.clientLimits.clients[client].Waiting() == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// With no room left in the queue, client requests are turned down.
+	ok, err = node.acquireClientSlot(ctx, client, 0)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	node.releaseClientSlot(client)
+	<-acquired
+	node.releaseClientSlot(client)
+
+	// Clients without executions in flight are not tracked.
+	require.Empty(t, node.clientLimits.clients)
+}
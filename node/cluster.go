@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
@@ -20,8 +21,26 @@ func (n *Node) processFormCluster(ctx context.Context, from peer.ID, req request
 
 	n.log.Info().Str("request", req.RequestID).Strs("peers", blockless.PeerIDsToStr(req.Peers)).Stringer("consensus", req.Consensus).Msg("received request to form consensus cluster")
 
-	// Add connection info about peers if we're not already connected to them.
-	for _, addrInfo := range req.ConnectionInfo {
+	if n.inMaintenance(time.Now()) {
+		n.log.Info().Str("request", req.RequestID).Msg("declining cluster formation during maintenance window")
+		n.metrics.IncrCounter(maintenanceDeclinedMetric, 1)
+
+		err := n.send(ctx, from, req.Response(codes.NotAvailable).WithConsensus(req.Consensus))
+		if err != nil {
+			return fmt.Errorf("could not send response: %w", err)
+		}
+
+		return nil
+	}
+
+	n.addClusterAddresses(req.ConnectionInfo)
+
+	return n.createCluster(ctx, from, req)
+}
+
+// addClusterAddresses adds connection info about fellow cluster replicas, if we're not already connected to them.
+func (n *Node) addClusterAddresses(info []peer.AddrInfo) {
+	for _, addrInfo := range info {
 
 		if n.host.Host.ID() == addrInfo.ID {
 			continue
@@ -39,16 +58,6 @@ func (n *Node) processFormCluster(ctx context.Context, from peer.ID, req request
 
 		n.host.Network().Peerstore().AddAddrs(addrInfo.ID, addrInfo.Addrs, ClusterAddressTTL)
 	}
-
-	switch req.Consensus {
-	case consensus.Raft:
-		return n.createRaftCluster(ctx, from, req)
-
-	case consensus.PBFT:
-		return n.createPBFTCluster(ctx, from, req)
-	}
-
-	return fmt.Errorf("invalid consensus specified (%v %s)", req.Consensus, req.Consensus.String())
 }
 
 // processFormClusterResponse will record the cluster formation response.
@@ -56,6 +65,8 @@ func (n *Node) processFormClusterResponse(ctx context.Context, from peer.ID, res
 
 	n.log.Debug().Str("request", res.RequestID).Stringer("from", from).Msg("received cluster formation response")
 
+	n.linkExecutionSpan(res.RequestID, res.TraceInfo)
+
 	key := consensusResponseKey(res.RequestID, from)
 	n.consensusResponses.Set(key, res)
 
@@ -77,16 +88,20 @@ func (n *Node) processDisbandCluster(ctx context.Context, from peer.ID, req requ
 	return nil
 }
 
-func (n *Node) formCluster(ctx context.Context, requestID string, replicas []peer.ID, consensus consensus.Type) error {
+func (n *Node) formCluster(ctx context.Context, requestID string, replicas []peer.ID, algo consensus.Type) error {
 
 	// Create cluster formation request.
 	reqCluster := request.FormCluster{
 		RequestID:      requestID,
 		Peers:          replicas,
-		Consensus:      consensus,
+		Consensus:      algo,
 		ConnectionInfo: make([]peer.AddrInfo, 0, len(replicas)),
 	}
 
+	if algo == consensus.PBFT {
+		reqCluster.ViewChangeTimeout = n.pbftViewChangeTimeout(replicas)
+	}
+
 	// Add connection info in case replicas don't already know of each other.
 	for _, replica := range replicas {
 		addrInfo := peer.AddrInfo{
@@ -0,0 +1,224 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+var errClusterUpdateNotSupported = errors.New("membership changes are only supported for raft clusters")
+
+// membershipUpdater is implemented by clusters that support adding and removing peers after formation.
+type membershipUpdater interface {
+	UpdateMembership(add []peer.ID, remove []peer.ID) error
+}
+
+// UpdateCluster adds and removes peers from an existing raft cluster, so a long-lived cluster can heal when one of its
+// members permanently disappears. Peers being added are asked to join first. The change is done by the cluster leader,
+// so the update succeeds once any of the current members confirms it.
+func (n *Node) UpdateCluster(ctx context.Context, requestID string, members []peer.ID, add []peer.ID, remove []peer.ID) error {
+
+	log := n.log.With().Str("request", requestID).Strs("add", blockless.PeerIDsToStr(add)).Strs("remove", blockless.PeerIDsToStr(remove)).Logger()
+
+	req := request.UpdateCluster{
+		RequestID:      requestID,
+		Consensus:      consensus.Raft,
+		Add:            add,
+		Remove:         remove,
+		ConnectionInfo: make([]peer.AddrInfo, 0, len(members)+len(add)),
+	}
+
+	// Add connection info in case replicas don't already know of each other.
+	for _, replica := range slices.Concat(members, add) {
+		addrInfo := peer.AddrInfo{
+			ID:    replica,
+			Addrs: n.host.Peerstore().Addrs(replica),
+		}
+
+		req.ConnectionInfo = append(req.ConnectionInfo, addrInfo)
+	}
+
+	err := req.Valid()
+	if err != nil {
+		return fmt.Errorf("invalid cluster update: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, n.cfg.ExecutionTimeout)
+	defer cancel()
+
+	// Joining peers must be ready before the leader adds them.
+	if len(add) > 0 {
+
+		log.Debug().Msg("asking peers to join the cluster")
+
+		err = n.sendToMany(ctx, add, &req, true)
+		if err != nil {
+			return fmt.Errorf("could not send cluster update to joining peers: %w", err)
+		}
+
+		joined := n.gatherClusterUpdateResponses(ctx, requestID, add)
+		if len(joined) != len(add) {
+			return fmt.Errorf("some peers failed to join the cluster (have: %d, want: %d)", len(joined), len(add))
+		}
+	}
+
+	// Members being removed are likely unreachable, so we don't require all sends to succeed.
+	err = n.sendToMany(ctx, members, &req, false)
+	if err != nil {
+		return fmt.Errorf("could not send cluster update to cluster members: %w", err)
+	}
+
+	confirmed := n.gatherClusterUpdateResponses(ctx, requestID, members)
+	if len(confirmed) == 0 {
+		return errors.New("cluster update was not confirmed by the cluster leader")
+	}
+
+	log.Info().Strs("confirmed", blockless.PeerIDsToStr(confirmed)).Msg("cluster membership updated")
+
+	return nil
+}
+
+// gatherClusterUpdateResponses waits for cluster update responses and returns the peers that successfully applied the update.
+func (n *Node) gatherClusterUpdateResponses(ctx context.Context, requestID string, peers []peer.ID) []peer.ID {
+
+	var (
+		lock      sync.Mutex
+		wg        sync.WaitGroup
+		confirmed []peer.ID
+	)
+
+	wg.Add(len(peers))
+	for _, rp := range peers {
+		go func() {
+			defer wg.Done()
+
+			// Do not wait for peers that disconnect.
+			pctx, cancel := n.peerContext(ctx, rp)
+			defer cancel()
+
+			res, ok := n.clusterUpdates.WaitFor(pctx, consensusResponseKey(requestID, rp))
+			if !ok {
+				return
+			}
+
+			if res.Code != codes.OK {
+				n.log.Debug().Str("request", requestID).Stringer("peer", rp).Str("code", res.Code.String()).Str("message", res.ErrorMessage).Msg("peer did not apply cluster update")
+				return
+			}
+
+			lock.Lock()
+			defer lock.Unlock()
+			confirmed = append(confirmed, rp)
+		}()
+	}
+
+	wg.Wait()
+
+	return confirmed
+}
+
+func (n *Node) processUpdateCluster(ctx context.Context, from peer.ID, req request.UpdateCluster) error {
+
+	n.log.Info().
+		Str("request", req.RequestID).
+		Strs("add", blockless.PeerIDsToStr(req.Add)).
+		Strs("remove", blockless.PeerIDsToStr(req.Remove)).
+		Msg("received request to update consensus cluster")
+
+	code, err := n.updateClusterMembership(ctx, req)
+	res := req.Response(code)
+	if err != nil {
+		n.log.Error().Err(err).Str("request", req.RequestID).Msg("could not update cluster")
+		res = res.WithErrorMessage(err)
+	}
+
+	err = n.send(ctx, from, res)
+	if err != nil {
+		return fmt.Errorf("could not send response: %w", err)
+	}
+
+	if code == codes.OK && slices.Contains(req.Add, n.host.ID()) {
+		n.clusterProgress.start(req.RequestID, from, req.Consensus, time.Now())
+	}
+
+	// A removed member leaves the cluster, even if it is not the leader. No need to wait for executions - the cluster continues without us.
+	removed := code == codes.OK || code == codes.NoContent
+	if removed && slices.Contains(req.Remove, n.host.ID()) {
+		err = n.leaveCluster(req.RequestID, 0)
+		if err != nil {
+			return fmt.Errorf("could not leave cluster: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// updateClusterMembership applies the cluster update. Peers being added join the cluster, while the cluster leader
+// changes the cluster membership. Other members report `NoContent`.
+func (n *Node) updateClusterMembership(ctx context.Context, req request.UpdateCluster) (codes.Code, error) {
+
+	if req.Consensus != consensus.Raft {
+		return codes.NotSupported, errClusterUpdateNotSupported
+	}
+
+	n.addClusterAddresses(req.ConnectionInfo)
+
+	n.clusterLock.RLock()
+	cluster, ok := n.clusters[req.RequestID]
+	n.clusterLock.RUnlock()
+
+	if slices.Contains(req.Add, n.host.ID()) {
+
+		// Already part of the cluster.
+		if ok {
+			return codes.OK, nil
+		}
+
+		err := n.joinRaftCluster(ctx, req)
+		if err != nil {
+			return codes.Error, fmt.Errorf("could not join cluster: %w", err)
+		}
+
+		return codes.OK, nil
+	}
+
+	if !ok {
+		return codes.NotFound, errors.New("no cluster with that ID")
+	}
+
+	updater, ok := cluster.(membershipUpdater)
+	if !ok {
+		return codes.NotSupported, errClusterUpdateNotSupported
+	}
+
+	err := updater.UpdateMembership(req.Add, req.Remove)
+	if errors.Is(err, consensus.ErrNotLeader) {
+		return codes.NoContent, nil
+	}
+	if err != nil {
+		return codes.Error, fmt.Errorf("could not update cluster membership: %w", err)
+	}
+
+	return codes.OK, nil
+}
+
+// processUpdateClusterResponse will record the cluster update response.
+func (n *Node) processUpdateClusterResponse(ctx context.Context, from peer.ID, res response.UpdateCluster) error {
+
+	n.log.Debug().Str("request", res.RequestID).Stringer("from", from).Msg("received cluster update response")
+
+	n.clusterUpdates.Set(consensusResponseKey(res.RequestID, from), res)
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/consensus/raft"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+type testCluster struct {
+	consensus consensus.Type
+}
+
+func (c *testCluster) Consensus() consensus.Type { return c.consensus }
+func (c *testCluster) Shutdown() error           { return nil }
+func (c *testCluster) Execute(peer.ID, string, time.Time, execute.Request) (codes.Code, execute.Result, error) {
+	return codes.NoContent, execute.Result{}, nil
+}
+
+type testUpdatableCluster struct {
+	testCluster
+	err    error
+	add    []peer.ID
+	remove []peer.ID
+}
+
+func (c *testUpdatableCluster) UpdateMembership(add []peer.ID, remove []peer.ID) error {
+	c.add = add
+	c.remove = remove
+	return c.err
+}
+
+func TestNode_UpdateClusterMembership(t *testing.T) {
+
+	var (
+		requestID = mocks.GenericUUID.String()
+		add       = mocks.GenericPeerIDs[:1]
+		remove    = mocks.GenericPeerIDs[1:2]
+	)
+
+	update := request.UpdateCluster{
+		RequestID: requestID,
+		Consensus: consensus.Raft,
+		Add:       add,
+		Remove:    remove,
+	}
+
+	t.Run("leader applies the update", func(t *testing.T) {
+		node := createNode(t, blockless.WorkerNode)
+
+		cluster := &testUpdatableCluster{testCluster: testCluster{consensus: consensus.Raft}}
+		node.clusters[requestID] = cluster
+
+		code, err := node.updateClusterMembership(context.Background(), update)
+		require.NoError(t, err)
+		require.Equal(t, codes.OK, code)
+		require.Equal(t, add, cluster.add)
+		require.Equal(t, remove, cluster.remove)
+	})
+	t.Run("followers do not apply the update", func(t *testing.T) {
+		node := createNode(t, blockless.WorkerNode)
+
+		cluster := &testUpdatableCluster{testCluster: testCluster{consensus: consensus.Raft}, err: raft.ErrNotLeader}
+		node.clusters[requestID] = cluster
+
+		code, err := node.updateClusterMembership(context.Background(), update)
+		require.NoError(t, err)
+		require.Equal(t, codes.NoContent, code)
+	})
+	t.Run("unknown cluster", func(t *testing.T) {
+		node := createNode(t, blockless.WorkerNode)
+
+		code, err := node.updateClusterMembership(context.Background(), update)
+		require.Error(t, err)
+		require.Equal(t, codes.NotFound, code)
+	})
+	t.Run("cluster does not support membership changes", func(t *testing.T) {
+		node := createNode(t, blockless.WorkerNode)
+		node.clusters[requestID] = &testCluster{consensus: consensus.Raft}
+
+		code, err := node.updateClusterMembership(context.Background(), update)
+		require.ErrorIs(t, err, errClusterUpdateNotSupported)
+		require.Equal(t, codes.NotSupported, code)
+	})
+	t.Run("only raft clusters can be updated", func(t *testing.T) {
+		node := createNode(t, blockless.WorkerNode)
+
+		pbftUpdate := update
+		pbftUpdate.Consensus = consensus.PBFT
+
+		code, err := node.updateClusterMembership(context.Background(), pbftUpdate)
+		require.ErrorIs(t, err, errClusterUpdateNotSupported)
+		require.Equal(t, codes.NotSupported, code)
+	})
+}
+
+func TestUpdateCluster_Valid(t *testing.T) {
+
+	update := request.UpdateCluster{
+		RequestID: mocks.GenericUUID.String(),
+		Add:       mocks.GenericPeerIDs[:1],
+	}
+	require.NoError(t, update.Valid())
+
+	empty := update
+	empty.Add = nil
+	require.Error(t, empty.Valid())
+
+	conflicting := update
+	conflicting.Remove = mocks.GenericPeerIDs[:1]
+	require.Error(t, conflicting.Valid())
+}
@@ -0,0 +1,155 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+var errClusterStalled = errors.New("cluster made no progress before the deadline")
+
+// clusterProgress tracks when the clusters the worker is a member of last made progress.
+type clusterProgress struct {
+	sync.Mutex
+	clusters map[string]clusterActivity
+}
+
+type clusterActivity struct {
+	head      peer.ID // Head node that formed the cluster.
+	consensus consensus.Type
+	last      time.Time
+}
+
+// stalledCluster describes a cluster that made no progress before the deadline.
+type stalledCluster struct {
+	id string
+	clusterActivity
+}
+
+func newClusterProgress() *clusterProgress {
+	return &clusterProgress{
+		clusters: make(map[string]clusterActivity),
+	}
+}
+
+func (p *clusterProgress) start(id string, head peer.ID, consensus consensus.Type, now time.Time) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.clusters[id] = clusterActivity{
+		head:      head,
+		consensus: consensus,
+		last:      now,
+	}
+}
+
+// record notes that the cluster made progress, if it is tracked.
+func (p *clusterProgress) record(id string, now time.Time) {
+	p.Lock()
+	defer p.Unlock()
+
+	activity, ok := p.clusters[id]
+	if !ok {
+		return
+	}
+
+	activity.last = now
+	p.clusters[id] = activity
+}
+
+func (p *clusterProgress) remove(id string) {
+	p.Lock()
+	defer p.Unlock()
+
+	delete(p.clusters, id)
+}
+
+// stalled returns the clusters that made no progress since the deadline.
+func (p *clusterProgress) stalled(deadline time.Time) []stalledCluster {
+	p.Lock()
+	defer p.Unlock()
+
+	var stalled []stalledCluster
+	for id, activity := range p.clusters {
+		if activity.last.Before(deadline) {
+			stalled = append(stalled, stalledCluster{id: id, clusterActivity: activity})
+		}
+	}
+
+	return stalled
+}
+
+// watchClusters will run a long running loop, shutting down clusters that make no progress, until cancelled.
+// Without it, replicas would run forever if the message disbanding the cluster was lost.
+func (n *Node) watchClusters(ctx context.Context) {
+
+	ticker := time.NewTicker(min(clusterWatchdogInterval, n.cfg.ClusterDeadline))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+
+			for _, cluster := range n.clusterProgress.stalled(time.Now().Add(-n.cfg.ClusterDeadline)) {
+				err := n.abandonCluster(ctx, cluster)
+				if err != nil {
+					n.log.Warn().Err(err).Str("request", cluster.id).Msg("could not shut down stalled cluster")
+				}
+			}
+
+		case <-ctx.Done():
+			n.log.Info().Msg("stopping cluster watchdog")
+			return
+		}
+	}
+}
+
+// abandonCluster shuts down the stalled cluster and lets the head node that formed it know.
+func (n *Node) abandonCluster(ctx context.Context, cluster stalledCluster) error {
+
+	log := n.log.With().Str("request", cluster.id).Stringer("consensus", cluster.consensus).Time("last_progress", cluster.last).Logger()
+
+	log.Warn().Msg("cluster made no progress before the deadline, shutting it down")
+
+	n.metrics.IncrCounterWithLabels(clustersAbandonedMetric, 1, []metrics.Label{{Name: "consensus", Value: cluster.consensus.String()}})
+
+	// Whatever happens next, the cluster is no longer tracked.
+	n.clusterProgress.remove(cluster.id)
+
+	err := n.leaveCluster(cluster.id, 0)
+	if err != nil {
+		return fmt.Errorf("could not leave cluster: %w", err)
+	}
+
+	sctx, cancel := context.WithTimeout(ctx, consensusClusterSendTimeout)
+	defer cancel()
+
+	res := response.DisbandCluster{
+		RequestID: cluster.id,
+		Code:      codes.Timeout,
+	}
+
+	err = n.send(sctx, cluster.head, res.WithErrorMessage(errClusterStalled))
+	if err != nil {
+		return fmt.Errorf("could not notify head node: %w", err)
+	}
+
+	return nil
+}
+
+// processDisbandClusterResponse handles the notification of a worker that left a cluster on its own.
+func (n *Node) processDisbandClusterResponse(ctx context.Context, from peer.ID, res response.DisbandCluster) error {
+
+	n.log.Warn().Str("request", res.RequestID).Stringer("peer", from).Stringer("code", res.Code).Str("message", res.ErrorMessage).Msg("worker left consensus cluster")
+
+	return nil
+}
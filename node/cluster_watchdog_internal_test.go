@@ -0,0 +1,62 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_ClusterProgress(t *testing.T) {
+
+	var (
+		head    = mocks.GenericPeerIDs[0]
+		now     = time.Now()
+		stalled = "stalled-cluster"
+		active  = "active-cluster"
+	)
+
+	progress := newClusterProgress()
+	progress.start(stalled, head, consensus.Raft, now.Add(-time.Hour))
+	progress.start(active, head, consensus.PBFT, now.Add(-time.Hour))
+	progress.record(active, now)
+
+	// Untracked clusters are ignored.
+	progress.record("unknown", now)
+
+	clusters := progress.stalled(now.Add(-30 * time.Minute))
+	require.Len(t, clusters, 1)
+	require.Equal(t, stalled, clusters[0].id)
+	require.Equal(t, head, clusters[0].head)
+	require.Equal(t, consensus.Raft, clusters[0].consensus)
+
+	progress.remove(stalled)
+	require.Empty(t, progress.stalled(now.Add(-30*time.Minute)))
+}
+
+func TestNode_AbandonCluster(t *testing.T) {
+
+	var (
+		requestID = mocks.GenericUUID.String()
+		head      = mocks.GenericPeerIDs[0]
+	)
+
+	node := createNode(t, blockless.WorkerNode)
+
+	node.clusters[requestID] = &testCluster{consensus: consensus.Raft}
+	node.clusterProgress.start(requestID, head, consensus.Raft, time.Now().Add(-time.Hour))
+
+	cluster := node.clusterProgress.stalled(time.Now())[0]
+
+	// Head node is not reachable, but the cluster is shut down regardless.
+	err := node.abandonCluster(context.Background(), cluster)
+	require.Error(t, err)
+
+	require.NotContains(t, node.clusters, requestID)
+	require.Empty(t, node.clusterProgress.stalled(time.Now()))
+}
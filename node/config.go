@@ -2,12 +2,27 @@ package node
 
 import (
 	"errors"
+	"fmt"
 	"path/filepath"
 	"time"
 
+	"github.com/libp2p/go-libp2p/core/peer"
+
 	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/executor/gpu"
+	"github.com/blocklessnetwork/b7s/host"
 	"github.com/blocklessnetwork/b7s/metadata"
 	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/node/aggregate"
+	"github.com/blocklessnetwork/b7s/node/head/anomaly"
+	"github.com/blocklessnetwork/b7s/node/head/archive"
+	"github.com/blocklessnetwork/b7s/node/head/backup"
+	"github.com/blocklessnetwork/b7s/node/head/deferred"
+	"github.com/blocklessnetwork/b7s/node/head/journal"
+	"github.com/blocklessnetwork/b7s/node/head/quota"
+	"github.com/blocklessnetwork/b7s/node/head/schedule"
+	"github.com/blocklessnetwork/b7s/node/migration"
+	"github.com/blocklessnetwork/b7s/random"
 )
 
 // Option can be used to set Node configuration options.
@@ -20,29 +35,138 @@ var DefaultConfig = Config{
 	HealthInterval:          DefaultHealthInterval,
 	RollCallTimeout:         DefaultRollCallTimeout,
 	Concurrency:             DefaultConcurrency,
+	QueueSize:               DefaultQueueSize,
 	ExecutionTimeout:        DefaultExecutionTimeout,
 	ClusterFormationTimeout: DefaultClusterFormationTimeout,
 	DefaultConsensus:        DefaultConsensusAlgorithm,
 	LoadAttributes:          DefaultAttributeLoadingSetting,
 	MetadataProvider:        metadata.NewNoopProvider(),
+	WorkerDispatchLimit:     DefaultWorkerDispatchLimit,
+	ScheduleResultTopic:     DefaultScheduleResultTopic,
+	RollCallQueueSize:       DefaultRollCallQueueSize,
+	RollCallOverflow:        DefaultRollCallOverflow,
+	RollCallBlockTimeout:    DefaultRollCallBlockTimeout,
+	SamplingFactor:          DefaultRollCallSamplingFactor,
+	SlowHandlerThreshold:    DefaultSlowHandlerThreshold,
+	FleetReportInterval:     DefaultFleetReportInterval,
+	ClusterDeadline:         DefaultClusterDeadline,
+	DelegationMaxDepth:      DefaultDelegationMaxDepth,
 }
 
 // Config represents the Node configuration.
 type Config struct {
-	Role                    blockless.NodeRole // Node role.
-	Topics                  []string           // Topics to subscribe to.
-	Execute                 blockless.Executor // Executor to use for running functions.
-	HealthInterval          time.Duration      // How often should we emit the health ping.
-	RollCallTimeout         time.Duration      // How long do we wait for roll call responses.
-	Concurrency             uint               // How many requests should the node process in parallel.
-	ExecutionTimeout        time.Duration      // How long does the head node wait for worker nodes to send their execution results.
-	ClusterFormationTimeout time.Duration      // How long do we wait for the nodes to form a cluster for an execution.
-	Workspace               string             // Directory where we can store files needed for execution.
-	DefaultConsensus        consensus.Type     // Default consensus algorithm to use.
-	LoadAttributes          bool               // Node should try to load its attributes from IPFS.
-	MetadataProvider        metadata.Provider  // Metadata provider for the node
+	Role                    blockless.NodeRole   // Node role.
+	Topics                  []string             // Topics to subscribe to.
+	Execute                 blockless.Executor   // Executor to use for running functions.
+	HealthInterval          time.Duration        // How often should we emit the health ping.
+	RollCallTimeout         time.Duration        // How long do we wait for roll call responses.
+	Concurrency             uint                 // How many requests should the node process in parallel.
+	QueueSize               uint                 // How many requests can wait for a processing slot, before new ones are turned down.
+	ExecutionTimeout        time.Duration        // How long does the head node wait for worker nodes to send their execution results.
+	WorkerResponseTimeout   time.Duration        // How long does the head node wait for a single worker before failing over to a reserve peer. Zero means deriving it from the request timeout.
+	ClusterFormationTimeout time.Duration        // How long do we wait for the nodes to form a cluster for an execution.
+	Workspace               string               // Directory where we can store files needed for execution.
+	DefaultConsensus        consensus.Type       // Default consensus algorithm to use.
+	LoadAttributes          bool                 // Node should try to load its attributes from IPFS.
+	MetadataProvider        metadata.Provider    // Metadata provider for the node
+	WorkerDispatchLimit     uint                 // Maximum number of concurrent executions the head node dispatches to a single worker.
+	ClientConcurrency       uint                 // Maximum number of executions a single client can have in flight on the head node. Zero means no limit.
+	ClientQueueSize         uint                 // Maximum number of execution requests of a single client waiting for one of its executions to complete.
+	DelegationThreshold     uint                 // Number of executions in progress above which the head node delegates new requests to peer head nodes. Zero disables delegation.
+	DelegationMaxDepth      uint                 // Maximum number of head nodes a request can be delegated through.
+	DataHost                *host.Host           // Optional host used for bulk data, such as execution results.
+	Journal                 *journal.Journal     // Journal for pending execution requests on the head node.
+	Aggregator              aggregate.Aggregator // How the head node collapses results from multiple workers into a single response.
+	Schedules               *schedule.Store      // Store for recurring executions on the head node.
+	DeferredStore           *deferred.Store      // Store for execution requests waiting for their not-before time on the head node.
+	ScheduleResultTopic     string               // Topic the head node publishes scheduled execution results to.
+	ResultStore             ResultStore          // Store for execution results, can be shared between head nodes.
+	RollCallStore           RollCallStore        // Store for roll call responses, can be shared between head nodes.
+	RollCallQueueSize       uint                 // How many roll call responses are queued per request, if the default roll call store is used.
+	RollCallOverflow        RollCallOverflow     // What happens to roll call responses arriving once the queue is full.
+	RollCallBlockTimeout    time.Duration        // How long do we wait for room in the queue, with the blocking overflow policy.
+	RollCallFallback        bool                 // Retry roll calls on the default topic if not enough workers in the subgroup responded.
+	SamplingThreshold       uint                 // Number of peers on a topic above which roll calls are sampled. Zero disables sampling.
+	SamplingFactor          float64              // How many times more workers than needed are asked to answer a sampled roll call.
+	Quotas                  *quota.Tracker       // Tracker for client quotas on the head node.
+	ResultArchive           *archive.Archive     // Archive for execution results on the head node.
+	RateLimits              []RateLimit          // Limits for the rate of execution requests per function on the head node.
+	ExecutorFactory         ExecutorFactory      // Creates executors for a different runtime, used when the worker switches executors at runtime.
+	AdminPeers              []peer.ID            // Peers allowed to switch the worker executor at runtime, drain the worker, run its self-test or change its subgroups, or run store maintenance, transfer cluster leadership, inspect peer state and schedule executions on the head node.
+	MaintenanceWindows      []MaintenanceWindow  // Periods during which the worker declines new work.
+	AnomalyDetector         *anomaly.Detector    // Detector for executions deviating from the function history on the head node.
+	PeerSelector            PeerSelector         // Chooses the workers executing the request out of the roll call responders on the head node.
+	Random                  *random.Source       // Source of random numbers, for request IDs, roll call sampling and peer exchange. Seeded sources make the node reproducible.
+	Canaries                []Canary             // Probe functions the head node periodically executes to check the health of subgroups.
+	WarmPools               []WarmPool           // Functions for which the head node keeps a pool of available workers, skipping the roll call.
+	Shadows                 []Shadow             // Shadow traffic the head node dispatches alongside client requests.
+	Backups                 *backup.Manager      // Runs compaction and backups of the database backing the head node stores.
+	SlowHandlerThreshold    time.Duration        // Message handlers running longer than this are logged with stack traces. Zero disables detection.
+	FleetReportInterval     time.Duration        // How often the worker publishes its fleet report. Zero disables fleet reports.
+	ClusterDeadline         time.Duration        // Clusters that make no progress for this long are shut down by the worker. Zero disables the watchdog.
+	ResultCacheTTL          time.Duration        // How long the worker serves results of executions for identical executions. Zero disables the result cache.
+	ImportedState           *migration.State     // State exported by the node on different hardware, whose peer reputation is carried over.
+	ContainerExecutor       blockless.Executor   // Executor to use for running functions packaged as container images. Nil means the node does not run containers.
+	SelfTest                *SelfTest            // Self-test the worker runs on startup, before it starts serving executions. Nil disables the startup self-test.
+	GPUs                    []gpu.Device         // GPUs the worker advertises as attributes and makes available to executions requiring them.
+	TenantConcurrency       uint                 // Maximum number of executions a single tenant can have in flight on the worker. Zero means no limit.
+	TenantQueueSize         uint                 // Maximum number of execution requests of a single tenant waiting for one of its executions to complete.
+	AllowedFunctions        []string             // Functions the worker executes. Empty means all functions that are not denied.
+	DeniedFunctions         []string             // Functions the worker never executes, even if they are allowed.
+
+	// Private worker pools.
+	RestrictedSubgroups   map[string]peer.ID               // Subgroups whose workers must present a membership credential issued by the subgroup owner, on the head node.
+	MembershipCredentials []blockless.MembershipCredential // Credentials admitting the worker to restricted subgroups.
+}
+
+// RateLimit describes how many execution requests the head node accepts for a function.
+// Function ID `*` sets the limit for functions without a limit of their own.
+type RateLimit struct {
+	FunctionID string  // Function the limit applies to.
+	Rate       float64 // Number of requests allowed per second.
+	Burst      uint    // Number of requests allowed at once.
+	PerPeer    bool    // Limit applies to each requesting peer separately.
+}
+
+// MaintenanceWindow describes a recurring period during which the worker declines roll calls and cluster formation,
+// e.g. so the host can be patched without surprising head nodes.
+type MaintenanceWindow struct {
+	Start    schedule.Cron // When the window starts.
+	Duration time.Duration // How long the window lasts.
+}
+
+// Canary describes a probe function the head node periodically executes on a sample of workers in each subgroup.
+// Workers returning results different from the rest lose reputation, and subgroups where the probe fails or
+// slows down are reported as degraded.
+type Canary struct {
+	FunctionID string        // Function to execute.
+	Method     string        // Method to execute.
+	Interval   time.Duration // How often the probe is executed.
+	Sample     uint          // Number of workers the probe is executed on in each subgroup.
 }
 
+// WarmPool describes a pool of available workers the head node keeps for a function, refreshed with periodic roll calls.
+// Executions of the function can use workers from the pool instead of waiting for a roll call.
+type WarmPool struct {
+	FunctionID string        // Function the pool is kept for.
+	Topic      string        // Subgroup the workers are roll called in. Default topic is used if empty.
+	Size       uint          // Maximum number of workers kept in the pool.
+	Refresh    time.Duration // How often the pool is refreshed.
+}
+
+// Shadow describes shadow traffic for a function - a share of its execution requests is also dispatched to a shadow subgroup or
+// function version. Shadow results are kept for comparison, but never returned to clients.
+// Function ID `*` sets the shadow traffic for functions without one of their own.
+type Shadow struct {
+	FunctionID       string  // Function whose requests are shadowed.
+	Rate             float64 // Share of requests that are shadowed, in the 0-1 range.
+	Subgroup         string  // Subgroup shadow requests are executed in. Subgroup of the original request is used if empty.
+	TargetFunctionID string  // Function version shadow requests execute. Function of the original request is used if empty.
+}
+
+// ExecutorFactory creates an executor using the runtime found at the given path.
+type ExecutorFactory func(runtimePath string, runtimeCLI string) (blockless.Executor, error)
+
 // Validate checks if the given configuration is correct.
 func (n *Node) ValidateConfig() error {
 
@@ -54,6 +178,15 @@ func (n *Node) ValidateConfig() error {
 		return errors.New("topics cannot be empty")
 	}
 
+	// Data host must share the identity of the main host.
+	if n.cfg.DataHost != nil && n.cfg.DataHost.ID() != n.host.ID() {
+		return errors.New("data host must have the same identity as the node host")
+	}
+
+	if n.cfg.SlowHandlerThreshold < 0 {
+		return errors.New("slow handler threshold cannot be negative")
+	}
+
 	// Worker specific validation.
 	if n.isWorker() {
 
@@ -65,15 +198,145 @@ func (n *Node) ValidateConfig() error {
 		if n.cfg.Execute == nil {
 			return errors.New("execution component is required")
 		}
+
+		if n.cfg.FleetReportInterval < 0 {
+			return errors.New("fleet report interval cannot be negative")
+		}
+
+		if n.cfg.ClusterDeadline < 0 {
+			return errors.New("cluster deadline cannot be negative")
+		}
+
+		if n.cfg.ResultCacheTTL < 0 {
+			return errors.New("result cache TTL cannot be negative")
+		}
+
+		for _, window := range n.cfg.MaintenanceWindows {
+			if window.Duration <= 0 {
+				return errors.New("maintenance window duration must be positive")
+			}
+		}
+
+		subgroups := make(map[string]struct{})
+		for _, credential := range n.cfg.MembershipCredentials {
+
+			err := credential.Verify(credential.Owner, credential.Subgroup, n.host.ID(), time.Now())
+			if err != nil {
+				return fmt.Errorf("invalid membership credential (subgroup: %s): %w", credential.Subgroup, err)
+			}
+
+			_, ok := subgroups[credential.Subgroup]
+			if ok {
+				return fmt.Errorf("duplicate membership credential (subgroup: %s)", credential.Subgroup)
+			}
+			subgroups[credential.Subgroup] = struct{}{}
+		}
 	}
 
 	// Head node specific validation.
 	if n.isHead() {
 
-		if n.cfg.Execute != nil {
+		if n.cfg.Execute != nil || n.cfg.ContainerExecutor != nil || len(n.cfg.GPUs) > 0 || n.cfg.TenantConcurrency > 0 ||
+			len(n.cfg.AllowedFunctions) > 0 || len(n.cfg.DeniedFunctions) > 0 {
 			return errors.New("execution not supported on this type of node")
 		}
 
+		if n.cfg.ScheduleResultTopic == "" {
+			return errors.New("schedule result topic cannot be empty")
+		}
+
+		if n.cfg.RollCallQueueSize == 0 {
+			return errors.New("roll call queue size must be positive")
+		}
+
+		if !n.cfg.RollCallOverflow.Valid() {
+			return fmt.Errorf("unknown roll call overflow policy: %s", n.cfg.RollCallOverflow)
+		}
+
+		if n.cfg.RollCallOverflow == RollCallBlock && n.cfg.RollCallBlockTimeout <= 0 {
+			return errors.New("roll call block timeout must be positive")
+		}
+
+		if n.cfg.SamplingThreshold > 0 && n.cfg.SamplingFactor < 1 {
+			return errors.New("roll call sampling factor must be at least 1")
+		}
+
+		if n.cfg.DelegationThreshold > 0 && n.cfg.DelegationMaxDepth == 0 {
+			return errors.New("delegation depth must be positive")
+		}
+
+		functions := make(map[string]struct{})
+		for _, limit := range n.cfg.RateLimits {
+
+			if limit.FunctionID == "" {
+				return errors.New("rate limit function ID cannot be empty")
+			}
+
+			if limit.Rate <= 0 || limit.Burst == 0 {
+				return fmt.Errorf("rate limit rate and burst must be positive (function: %s)", limit.FunctionID)
+			}
+
+			_, ok := functions[limit.FunctionID]
+			if ok {
+				return fmt.Errorf("duplicate rate limit (function: %s)", limit.FunctionID)
+			}
+			functions[limit.FunctionID] = struct{}{}
+		}
+
+		for _, canary := range n.cfg.Canaries {
+
+			if canary.FunctionID == "" || canary.Method == "" {
+				return errors.New("canary function ID and method cannot be empty")
+			}
+
+			if canary.Interval <= 0 || canary.Sample == 0 {
+				return fmt.Errorf("canary interval and sample size must be positive (function: %s)", canary.FunctionID)
+			}
+		}
+
+		for _, pool := range n.cfg.WarmPools {
+
+			if pool.FunctionID == "" {
+				return errors.New("warm pool function ID cannot be empty")
+			}
+
+			if pool.Size == 0 || pool.Refresh <= 0 {
+				return fmt.Errorf("warm pool size and refresh interval must be positive (function: %s)", pool.FunctionID)
+			}
+		}
+
+		for subgroup, owner := range n.cfg.RestrictedSubgroups {
+
+			if subgroup == "" || subgroup == DefaultTopic {
+				return fmt.Errorf("subgroup cannot be restricted (subgroup: %s)", subgroup)
+			}
+
+			if owner == "" {
+				return fmt.Errorf("restricted subgroup owner cannot be empty (subgroup: %s)", subgroup)
+			}
+		}
+
+		shadowed := make(map[string]struct{})
+		for _, shadow := range n.cfg.Shadows {
+
+			if shadow.FunctionID == "" {
+				return errors.New("shadow function ID cannot be empty")
+			}
+
+			if shadow.Rate <= 0 || shadow.Rate > 1 {
+				return fmt.Errorf("shadow rate must be in the 0-1 range (function: %s)", shadow.FunctionID)
+			}
+
+			if shadow.Subgroup == "" && shadow.TargetFunctionID == "" {
+				return fmt.Errorf("shadow subgroup or target function must be set (function: %s)", shadow.FunctionID)
+			}
+
+			_, ok := shadowed[shadow.FunctionID]
+			if ok {
+				return fmt.Errorf("duplicate shadow (function: %s)", shadow.FunctionID)
+			}
+			shadowed[shadow.FunctionID] = struct{}{}
+		}
 	}
 
 	return nil
@@ -100,6 +363,35 @@ func WithExecutor(execute blockless.Executor) Option {
 	}
 }
 
+// WithFleetReportInterval specifies how often the worker publishes its fleet report. Zero disables fleet reports.
+func WithFleetReportInterval(d time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.FleetReportInterval = d
+	}
+}
+
+// WithClusterDeadline specifies how long can a cluster go without progress before the worker shuts it down. Zero disables the watchdog.
+func WithClusterDeadline(d time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.ClusterDeadline = d
+	}
+}
+
+// WithResultCacheTTL specifies how long the worker serves results of executions for identical executions, without executing the function again.
+// Zero disables the result cache.
+func WithResultCacheTTL(ttl time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.ResultCacheTTL = ttl
+	}
+}
+
+// WithImportedState specifies the state exported by the node before it was moved to different hardware.
+func WithImportedState(state migration.State) Option {
+	return func(cfg *Config) {
+		cfg.ImportedState = &state
+	}
+}
+
 // WithHealthInterval specifies how often we should emit the health signal.
 func WithHealthInterval(d time.Duration) Option {
 	return func(cfg *Config) {
@@ -121,6 +413,14 @@ func WithExecutionTimeout(d time.Duration) Option {
 	}
 }
 
+// WithWorkerResponseTimeout specifies how long does the head node wait for a single worker to send its execution result,
+// before sending the request to a reserve peer.
+func WithWorkerResponseTimeout(d time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.WorkerResponseTimeout = d
+	}
+}
+
 // WithClusterFormationTimeout specifies how long does the head node wait for worker nodes to form a consensus cluster.
 func WithClusterFormationTimeout(d time.Duration) Option {
 	return func(cfg *Config) {
@@ -135,6 +435,40 @@ func WithConcurrency(n uint) Option {
 	}
 }
 
+// WithQueueSize specifies how many requests can wait for a processing slot. Once the queue is full, workers turn down
+// execution requests, so the head node can choose another worker, and drop other messages.
+func WithQueueSize(n uint) Option {
+	return func(cfg *Config) {
+		cfg.QueueSize = n
+	}
+}
+
+// WithWorkerDispatchLimit specifies the maximum number of concurrent executions the head node dispatches to a single worker.
+// The actual limit for a worker is lowered when the worker times out, and recovers gradually as the worker completes executions.
+func WithWorkerDispatchLimit(n uint) Option {
+	return func(cfg *Config) {
+		cfg.WorkerDispatchLimit = n
+	}
+}
+
+// WithClientConcurrency specifies the maximum number of executions a single client can have in flight on the head node,
+// and how many more of its requests can wait for one of them to complete. Requests over that are turned down.
+func WithClientConcurrency(limit uint, queue uint) Option {
+	return func(cfg *Config) {
+		cfg.ClientConcurrency = limit
+		cfg.ClientQueueSize = queue
+	}
+}
+
+// WithDelegation specifies the number of executions in progress on the head node above which new requests are delegated to
+// peer head nodes, and how many head nodes a request can be delegated through.
+func WithDelegation(threshold uint, depth uint) Option {
+	return func(cfg *Config) {
+		cfg.DelegationThreshold = threshold
+		cfg.DelegationMaxDepth = depth
+	}
+}
+
 // WithWorkspace specifies the workspace that the node can use for file storage.
 func WithWorkspace(path string) Option {
 	return func(cfg *Config) {
@@ -163,6 +497,261 @@ func WithMetadataProvider(p metadata.Provider) Option {
 	}
 }
 
+// WithDataHost specifies the host used for bulk data transfers, separate from the host used for control messages.
+func WithDataHost(h *host.Host) Option {
+	return func(cfg *Config) {
+		cfg.DataHost = h
+	}
+}
+
+// WithRequestJournal specifies the journal the head node uses to persist pending execution requests.
+func WithRequestJournal(j *journal.Journal) Option {
+	return func(cfg *Config) {
+		cfg.Journal = j
+	}
+}
+
+// WithAggregator specifies how the head node collapses results from multiple workers into a single response.
+func WithAggregator(a aggregate.Aggregator) Option {
+	return func(cfg *Config) {
+		cfg.Aggregator = a
+	}
+}
+
+// WithScheduleStore specifies the store the head node uses to persist recurring executions.
+func WithScheduleStore(s *schedule.Store) Option {
+	return func(cfg *Config) {
+		cfg.Schedules = s
+	}
+}
+
+// WithDeferredStore specifies the store the head node uses to persist execution requests waiting for their not-before time.
+func WithDeferredStore(s *deferred.Store) Option {
+	return func(cfg *Config) {
+		cfg.DeferredStore = s
+	}
+}
+
+// WithScheduleResultTopic specifies the topic the head node publishes scheduled execution results to.
+func WithScheduleResultTopic(topic string) Option {
+	return func(cfg *Config) {
+		cfg.ScheduleResultTopic = topic
+	}
+}
+
+// WithResultStore specifies the store for execution results. Head nodes sharing a result store
+// can serve results of executions started by other head nodes.
+func WithResultStore(s ResultStore) Option {
+	return func(cfg *Config) {
+		cfg.ResultStore = s
+	}
+}
+
+// WithRollCallStore specifies the store for roll call responses. Head nodes sharing a roll call store
+// can accept roll call responses for roll calls issued by other head nodes.
+func WithRollCallStore(s RollCallStore) Option {
+	return func(cfg *Config) {
+		cfg.RollCallStore = s
+	}
+}
+
+// WithQuotaTracker specifies the tracker the head node uses to enforce client quotas.
+func WithQuotaTracker(t *quota.Tracker) Option {
+	return func(cfg *Config) {
+		cfg.Quotas = t
+	}
+}
+
+// WithResultArchive specifies the archive the head node uses to keep execution results after they leave the result store.
+func WithResultArchive(a *archive.Archive) Option {
+	return func(cfg *Config) {
+		cfg.ResultArchive = a
+	}
+}
+
+// WithRateLimits specifies the limits for the rate of execution requests per function on the head node.
+func WithRateLimits(limits []RateLimit) Option {
+	return func(cfg *Config) {
+		cfg.RateLimits = limits
+	}
+}
+
+// WithExecutorFactory sets the function used to create executors when the worker switches executors at runtime.
+func WithExecutorFactory(f ExecutorFactory) Option {
+	return func(cfg *Config) {
+		cfg.ExecutorFactory = f
+	}
+}
+
+// WithAdminPeers sets the list of peers allowed to switch the worker executor at runtime, drain the worker, run its self-test or change its subgroups, or run store maintenance, transfer cluster leadership, inspect peer state and schedule executions on the head node.
+func WithAdminPeers(peers []peer.ID) Option {
+	return func(cfg *Config) {
+		cfg.AdminPeers = peers
+	}
+}
+
+// WithMaintenanceWindows sets the periods during which the worker declines new work.
+func WithMaintenanceWindows(windows []MaintenanceWindow) Option {
+	return func(cfg *Config) {
+		cfg.MaintenanceWindows = windows
+	}
+}
+
+// WithAnomalyDetector specifies the detector the head node uses to flag executions that deviate from the function history.
+func WithAnomalyDetector(d *anomaly.Detector) Option {
+	return func(cfg *Config) {
+		cfg.AnomalyDetector = d
+	}
+}
+
+// WithPeerSelector specifies how the head node chooses the workers executing the request out of the peers that reported for the roll call.
+func WithPeerSelector(s PeerSelector) Option {
+	return func(cfg *Config) {
+		cfg.PeerSelector = s
+	}
+}
+
+// WithRandomSeed specifies the seed for the random numbers the node uses, e.g. for request IDs and roll call sampling.
+// Nodes with the same seed make the same random choices, so simulations and tests can be reproduced.
+func WithRandomSeed(seed int64) Option {
+	return func(cfg *Config) {
+		cfg.Random = random.New(seed)
+	}
+}
+
+// WithCanaries specifies the probe functions the head node periodically executes to check the health of subgroups.
+func WithCanaries(canaries []Canary) Option {
+	return func(cfg *Config) {
+		cfg.Canaries = canaries
+	}
+}
+
+// WithWarmPools specifies the functions for which the head node keeps a pool of available workers.
+func WithWarmPools(pools []WarmPool) Option {
+	return func(cfg *Config) {
+		cfg.WarmPools = pools
+	}
+}
+
+// WithShadows specifies the shadow traffic the head node dispatches alongside client requests.
+func WithShadows(shadows []Shadow) Option {
+	return func(cfg *Config) {
+		cfg.Shadows = shadows
+	}
+}
+
+// WithRollCallQueueSize specifies how many roll call responses are queued per request.
+func WithRollCallQueueSize(n uint) Option {
+	return func(cfg *Config) {
+		cfg.RollCallQueueSize = n
+	}
+}
+
+// WithRollCallOverflow specifies what happens to roll call responses arriving once the queue is full.
+func WithRollCallOverflow(o RollCallOverflow) Option {
+	return func(cfg *Config) {
+		cfg.RollCallOverflow = o
+	}
+}
+
+// WithRollCallBlockTimeout specifies how long we wait for room in the roll call queue, with the blocking overflow policy.
+func WithRollCallBlockTimeout(d time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.RollCallBlockTimeout = d
+	}
+}
+
+// WithSlowHandlerThreshold specifies how long a message handler can run before it is logged as slow, along with goroutine stack traces.
+func WithSlowHandlerThreshold(d time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.SlowHandlerThreshold = d
+	}
+}
+
+// WithRestrictedSubgroups specifies the subgroups whose workers must present a membership credential, mapped to the subgroup owners issuing them.
+// Roll call responses from workers without a valid credential are not counted.
+func WithRestrictedSubgroups(subgroups map[string]peer.ID) Option {
+	return func(cfg *Config) {
+		cfg.RestrictedSubgroups = subgroups
+	}
+}
+
+// WithMembershipCredentials specifies the credentials the worker presents when reporting for roll calls in restricted subgroups.
+func WithMembershipCredentials(credentials []blockless.MembershipCredential) Option {
+	return func(cfg *Config) {
+		cfg.MembershipCredentials = credentials
+	}
+}
+
+// WithRollCallFallback specifies whether roll calls that did not get enough responses from the subgroup are retried on the default topic.
+func WithRollCallFallback(b bool) Option {
+	return func(cfg *Config) {
+		cfg.RollCallFallback = b
+	}
+}
+
+// WithRollCallSampling specifies the number of peers on a topic above which roll calls are sampled, and how many times
+// more workers than needed are asked to answer a sampled roll call.
+func WithRollCallSampling(threshold uint, factor float64) Option {
+	return func(cfg *Config) {
+		cfg.SamplingThreshold = threshold
+		cfg.SamplingFactor = factor
+	}
+}
+
+// WithBackups specifies the manager running compaction and backups of the database backing the head node stores.
+func WithBackups(m *backup.Manager) Option {
+	return func(cfg *Config) {
+		cfg.Backups = m
+	}
+}
+
+// WithSelfTest specifies the self-test the worker runs on startup. If any of the checks fail, the worker does not start.
+func WithSelfTest(test SelfTest) Option {
+	return func(cfg *Config) {
+		cfg.SelfTest = &test
+	}
+}
+
+// WithContainerExecutor specifies the executor to be used for running functions packaged as container images.
+func WithContainerExecutor(execute blockless.Executor) Option {
+	return func(cfg *Config) {
+		cfg.ContainerExecutor = execute
+	}
+}
+
+// WithGPUs specifies the GPUs of the worker.
+func WithGPUs(devices []gpu.Device) Option {
+	return func(cfg *Config) {
+		cfg.GPUs = devices
+	}
+}
+
+// WithTenantConcurrency specifies the maximum number of executions a single tenant can have in flight on the worker,
+// and how many more of its requests can wait for one of them to complete. Requests over that are turned down.
+func WithTenantConcurrency(limit uint, queue uint) Option {
+	return func(cfg *Config) {
+		cfg.TenantConcurrency = limit
+		cfg.TenantQueueSize = queue
+	}
+}
+
+// WithAllowedFunctions specifies the functions the worker executes. Roll calls for other functions are ignored,
+// and requests to execute them are turned down.
+func WithAllowedFunctions(functions []string) Option {
+	return func(cfg *Config) {
+		cfg.AllowedFunctions = functions
+	}
+}
+
+// WithDeniedFunctions specifies the functions the worker never executes. Roll calls for them are ignored,
+// and requests to execute them are turned down.
+func WithDeniedFunctions(functions []string) Option {
+	return func(cfg *Config) {
+		cfg.DeniedFunctions = functions
+	}
+}
+
 func (n *Node) isWorker() bool {
 	return n.cfg.Role == blockless.WorkerNode
 }
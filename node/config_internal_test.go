@@ -98,3 +98,16 @@ func TestConfig_Concurrency(t *testing.T) {
 
 	require.Equal(t, concurrency, cfg.Concurrency)
 }
+
+func TestConfig_WorkerDispatchLimit(t *testing.T) {
+
+	const limit = uint(3)
+
+	cfg := Config{
+		WorkerDispatchLimit: 0,
+	}
+
+	WithWorkerDispatchLimit(limit)(&cfg)
+
+	require.Equal(t, limit, cfg.WorkerDispatchLimit)
+}
@@ -9,8 +9,6 @@ import (
 	"github.com/libp2p/go-libp2p/core/peer"
 
 	"github.com/blocklessnetwork/b7s/consensus"
-	"github.com/blocklessnetwork/b7s/consensus/pbft"
-	"github.com/blocklessnetwork/b7s/consensus/raft"
 	"github.com/blocklessnetwork/b7s/models/codes"
 	"github.com/blocklessnetwork/b7s/models/execute"
 	"github.com/blocklessnetwork/b7s/models/request"
@@ -18,63 +16,36 @@ import (
 	"github.com/blocklessnetwork/b7s/telemetry/tracing"
 )
 
-// consensusExecutor defines the interface we have for managing clustered execution.
-// Execute often does not mean a direct execution but instead just pipelining the request, where execution is done asynchronously.
-type consensusExecutor interface {
-	Consensus() consensus.Type
-	Execute(from peer.ID, id string, timestamp time.Time, request execute.Request) (codes.Code, execute.Result, error)
-	Shutdown() error
-}
-
-func (n *Node) createRaftCluster(ctx context.Context, from peer.ID, fc request.FormCluster) error {
-
-	// Add a callback function to send the execution result to origin.
-	sendFn := func(req raft.FSMLogEntry, res execute.NodeResult) {
-
-		ctx, cancel := context.WithTimeout(context.Background(), consensusClusterSendTimeout)
-		defer cancel()
-
-		metadata, err := n.cfg.MetadataProvider.Metadata(req.Execute, res.Result.Result)
-		if err != nil {
-			n.log.Warn().Err(err).Msg("could not get metadata")
-		}
+// createCluster creates a replica of the consensus cluster the head node requested, using the registered implementation of the algorithm.
+func (n *Node) createCluster(ctx context.Context, from peer.ID, fc request.FormCluster) error {
 
-		res.Metadata = metadata
-
-		msg := response.Execute{
-			Code:      res.Code,
-			RequestID: req.RequestID,
-			Results:   singleNodeResultMap(n.host.ID(), res),
-		}
-
-		err = n.send(ctx, req.Origin, &msg)
-		if err != nil {
-			n.log.Error().Err(err).Str("peer", req.Origin.String()).Msg("could not send execution result to node")
-		}
+	// If we have tracing enabled we will have trace info in the context.
+	// If not, there might be trace info in the message so just use that.
+	ti := tracing.GetTraceInfo(ctx)
+	if ti.Empty() {
+		ti = fc.TraceInfo
 	}
 
-	// Add a callback function to cache the execution result
-	cacheFn := func(req raft.FSMLogEntry, res execute.NodeResult) {
-		n.executeResponses.Set(req.RequestID, singleNodeResultMap(n.host.ID(), res))
+	cluster := consensus.Cluster{
+		ID:             fc.RequestID,
+		Peers:          fc.Peers,
+		RequestTimeout: fc.ViewChangeTimeout,
 	}
 
-	rh, err := raft.New(
-		n.log,
-		n.host,
-		n.cfg.Workspace,
-		fc.RequestID,
-		n.executor,
-		fc.Peers,
-		raft.WithCallbacks(cacheFn, sendFn),
-	)
+	env := n.consensusEnvironment(ti)
+	env.Misbehaved = n.misbehaviorReporter(from, fc.RequestID)
+
+	replica, err := consensus.New(fc.Consensus, env, cluster)
 	if err != nil {
-		return fmt.Errorf("could not create raft node: %w", err)
+		return fmt.Errorf("could not create consensus replica: %w", err)
 	}
 
 	n.clusterLock.Lock()
-	n.clusters[fc.RequestID] = rh
+	n.clusters[fc.RequestID] = replica
 	n.clusterLock.Unlock()
 
+	n.clusterProgress.start(fc.RequestID, from, fc.Consensus, time.Now())
+
 	err = n.send(ctx, from, fc.Response(codes.OK).WithConsensus(fc.Consensus))
 	if err != nil {
 		return fmt.Errorf("could not send cluster confirmation message: %w", err)
@@ -83,43 +54,83 @@ func (n *Node) createRaftCluster(ctx context.Context, from peer.ID, fc request.F
 	return nil
 }
 
-func (n *Node) createPBFTCluster(ctx context.Context, from peer.ID, fc request.FormCluster) error {
+// consensusEnvironment returns the environment for the consensus replicas this node runs.
+func (n *Node) consensusEnvironment(ti tracing.TraceInfo) consensus.Environment {
 
-	cacheFn := func(requestID string, origin peer.ID, request execute.Request, result execute.NodeResult) {
+	// Cache the execution result.
+	executed := func(requestID string, origin peer.ID, request execute.Request, result execute.NodeResult) {
 		n.executeResponses.Set(requestID, singleNodeResultMap(n.host.ID(), result))
+		n.clusterProgress.record(requestID, time.Now())
+
+		if request.Config.ResultTopic != "" {
+			msg := response.Execute{
+				Code:      result.Code,
+				RequestID: requestID,
+				Results:   singleNodeResultMap(n.host.ID(), result),
+			}
+
+			ctx, cancel := context.WithTimeout(tracing.TraceContext(context.Background(), ti), consensusClusterSendTimeout)
+			defer cancel()
+
+			n.publishExecutionResult(ctx, request.Config.ResultTopic, &msg)
+		}
 	}
 
-	// If we have tracing enabled we will have trace info in the context.
-	// If not, there might be trace info in the message so just use that.
-	ti := tracing.GetTraceInfo(ctx)
-	if ti.Empty() {
-		ti = fc.TraceInfo
+	// Send the execution result to origin, for replicas that do not do it themselves.
+	// Result is sent in the context of the execution span, so the head node can link to it.
+	deliver := func(ctx context.Context, requestID string, origin peer.ID, request execute.Request, res execute.NodeResult) {
+
+		ctx, cancel := context.WithTimeout(ctx, consensusClusterSendTimeout)
+		defer cancel()
+
+		metadata, err := n.cfg.MetadataProvider.Metadata(request, res.Result.Result)
+		if err != nil {
+			n.log.Warn().Err(err).Msg("could not get metadata")
+		}
+
+		res.Metadata = metadata
+		res.Timing = &execute.Timing{Execution: res.Usage.WallClockTime.Milliseconds()}
+		res.SetChecksum()
+
+		msg := response.Execute{
+			Code:      res.Code,
+			RequestID: requestID,
+			Results:   singleNodeResultMap(n.host.ID(), res),
+		}
+
+		err = n.sendData(ctx, origin, &msg)
+		if err != nil {
+			n.log.Error().Err(err).Str("peer", origin.String()).Msg("could not send execution result to node")
+		}
 	}
 
-	ph, err := pbft.NewReplica(
-		n.log,
-		n.host,
-		n.executor,
-		fc.Peers,
-		fc.RequestID,
-		pbft.WithPostProcessors(cacheFn),
-		pbft.WithTraceInfo(ti),
-		pbft.WithMetadataProvider(n.cfg.MetadataProvider),
-	)
-	if err != nil {
-		return fmt.Errorf("could not create PBFT node: %w", err)
+	return consensus.Environment{
+		Log:              n.log,
+		Host:             n.host,
+		Executor:         n.executor,
+		Workspace:        n.cfg.Workspace,
+		MetadataProvider: n.cfg.MetadataProvider,
+		TraceInfo:        ti,
+		Executed:         executed,
+		Deliver:          deliver,
 	}
+}
 
-	n.clusterLock.Lock()
-	n.clusters[fc.RequestID] = ph
-	n.clusterLock.Unlock()
+// pbftViewChangeTimeout returns the view change timeout for a PBFT cluster of the given replicas. Geographically dispersed
+// clusters get more time, so they don't trigger view changes only because messages take longer to arrive.
+func (n *Node) pbftViewChangeTimeout(replicas []peer.ID) time.Duration {
 
-	err = n.send(ctx, from, fc.Response(codes.OK).WithConsensus(fc.Consensus))
-	if err != nil {
-		return fmt.Errorf("could not send cluster confirmation message: %w", err)
+	var slowest time.Duration
+	for _, replica := range replicas {
+		latency, ok := n.latencies.get(replica)
+		if ok {
+			slowest = max(slowest, latency)
+		}
 	}
 
-	return nil
+	timeout, maxTimeout := pbftRequestTimeouts()
+
+	return min(timeout+pbftViewChangeLatencyFactor*slowest, maxTimeout)
 }
 
 func (n *Node) leaveCluster(requestID string, timeout time.Duration) error {
@@ -154,6 +165,8 @@ func (n *Node) leaveCluster(requestID string, timeout time.Duration) error {
 	delete(n.clusters, requestID)
 	n.clusterLock.Unlock()
 
+	n.clusterProgress.remove(requestID)
+
 	return nil
 }
 
@@ -161,3 +174,14 @@ func (n *Node) leaveCluster(requestID string, timeout time.Duration) error {
 func consensusRequired(c consensus.Type) bool {
 	return c != 0
 }
+
+// noConsensus returns true if the node was built without any consensus algorithms.
+func noConsensus() bool {
+	return len(consensus.Registered()) == 0
+}
+
+// byzantineFaultTolerant returns true for consensus algorithms that tolerate byzantine replicas, and where
+// the head node collects f+1 matching results from the cluster.
+func byzantineFaultTolerant(c consensus.Type) bool {
+	return c == consensus.PBFT || c == consensus.HotStuff
+}
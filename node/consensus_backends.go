@@ -0,0 +1,69 @@
+//go:build !noconsensus
+
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	_ "github.com/blocklessnetwork/b7s/consensus/hotstuff"
+	"github.com/blocklessnetwork/b7s/consensus/pbft"
+	"github.com/blocklessnetwork/b7s/consensus/raft"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/telemetry/tracing"
+)
+
+// Consensus algorithms are built into the node by default. Building with the `noconsensus` tag leaves them out,
+// for workers on constrained devices that only run executions without consensus.
+
+// pbftRequestTimeouts returns the default and the maximum PBFT view change timeout.
+func pbftRequestTimeouts() (time.Duration, time.Duration) {
+	return pbft.RequestTimeout, pbft.MaxRequestTimeout
+}
+
+// minClusterResults returns the number of identical results needed from a byzantine fault tolerant cluster of the given size.
+func minClusterResults(n uint) uint {
+	return pbft.MinClusterResults(n)
+}
+
+// verifyMisbehavior checks the evidence of a replica misbehaving, for algorithms that detect misbehavior.
+func verifyMisbehavior(m consensus.Misbehavior) error {
+
+	switch m.Consensus {
+	case consensus.PBFT:
+		return pbft.VerifyMisbehavior(m)
+	default:
+		return errors.New("misbehavior evidence not supported for consensus")
+	}
+}
+
+func (n *Node) joinRaftCluster(ctx context.Context, req request.UpdateCluster) error {
+
+	// If we have tracing enabled we will have trace info in the context.
+	// If not, there might be trace info in the message so just use that.
+	ti := tracing.GetTraceInfo(ctx)
+	if ti.Empty() {
+		ti = req.TraceInfo
+	}
+
+	rh, err := raft.Join(
+		n.log,
+		n.host,
+		n.cfg.Workspace,
+		req.RequestID,
+		n.executor,
+		raft.EnvironmentOptions(n.consensusEnvironment(ti))...,
+	)
+	if err != nil {
+		return fmt.Errorf("could not create raft node: %w", err)
+	}
+
+	n.clusterLock.Lock()
+	n.clusters[req.RequestID] = rh
+	n.clusterLock.Unlock()
+
+	return nil
+}
@@ -0,0 +1,33 @@
+//go:build noconsensus
+
+package node
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/models/request"
+)
+
+// Node built without consensus algorithms only runs executions without consensus. Head nodes built this way reject
+// requests for consensus, and workers decline roll calls for it.
+
+var errConsensusNotBuilt = errors.New("node built without consensus support")
+
+func pbftRequestTimeouts() (time.Duration, time.Duration) {
+	return 0, 0
+}
+
+func minClusterResults(n uint) uint {
+	return n
+}
+
+func verifyMisbehavior(consensus.Misbehavior) error {
+	return errConsensusNotBuilt
+}
+
+func (n *Node) joinRaftCluster(context.Context, request.UpdateCluster) error {
+	return errConsensusNotBuilt
+}
@@ -0,0 +1,72 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+var errContainersNotSupported = errors.New("container execution not supported by this node")
+
+// containerRouter runs functions packaged as container images using the container executor, and all other functions
+// using the regular executor.
+type containerRouter struct {
+	log       zerolog.Logger
+	executor  blockless.Executor
+	container blockless.Executor
+	fstore    FStore
+}
+
+func newContainerRouter(log zerolog.Logger, executor blockless.Executor, container blockless.Executor, fstore FStore) *containerRouter {
+
+	r := containerRouter{
+		log:       log.With().Str("component", "container_router").Logger(),
+		executor:  executor,
+		container: container,
+		fstore:    fstore,
+	}
+
+	return &r
+}
+
+// ExecuteFunction executes the function using the executor matching the function manifest.
+func (r *containerRouter) ExecuteFunction(ctx context.Context, requestID string, req execute.Request) (execute.Result, error) {
+
+	fn, err := r.fstore.Get(ctx, req.FunctionID)
+	if err != nil || fn.Manifest.Container == nil {
+		return r.executor.ExecuteFunction(ctx, requestID, req)
+	}
+
+	if r.container == nil {
+		return execute.Result{Code: codes.NotAvailable}, errContainersNotSupported
+	}
+
+	r.log.Debug().Str("request", requestID).Str("function", req.FunctionID).Str("image", fn.Manifest.Container.Image).Msg("executing function in a container")
+
+	return r.container.ExecuteFunction(ctx, requestID, req)
+}
+
+// supportsFunction returns an error if the function is packaged as a container image and the node cannot run containers.
+func (n *Node) supportsFunction(ctx context.Context, functionID string) error {
+
+	if n.cfg.ContainerExecutor != nil {
+		return nil
+	}
+
+	fn, err := n.fstore.Get(ctx, functionID)
+	if err != nil {
+		return fmt.Errorf("could not get function: %w", err)
+	}
+
+	if fn.Manifest.Container != nil {
+		return errContainersNotSupported
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package node
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_ContainerRouter(t *testing.T) {
+
+	var (
+		requestID = mocks.GenericUUID.String()
+		request   = mocks.GenericExecutionRequest
+
+		regularResult   = execute.Result{Code: codes.OK, Result: execute.RuntimeOutput{Stdout: "wasm"}}
+		containerResult = execute.Result{Code: codes.OK, Result: execute.RuntimeOutput{Stdout: "container"}}
+	)
+
+	executor := mocks.BaselineExecutor(t)
+	executor.ExecFunctionFunc = func(context.Context, string, execute.Request) (execute.Result, error) {
+		return regularResult, nil
+	}
+
+	containerExecutor := mocks.BaselineExecutor(t)
+	containerExecutor.ExecFunctionFunc = func(context.Context, string, execute.Request) (execute.Result, error) {
+		return containerResult, nil
+	}
+
+	containerStore := mocks.BaselineFStore(t)
+	containerStore.GetFunc = func(context.Context, string) (blockless.FunctionRecord, error) {
+		fn := mocks.GenericFunctionRecord
+		fn.Manifest.Container = &blockless.Container{Image: "example.com/function:latest"}
+		return fn, nil
+	}
+
+	t.Run("function without container image", func(t *testing.T) {
+		router := newContainerRouter(mocks.NoopLogger, executor, containerExecutor, mocks.BaselineFStore(t))
+
+		res, err := router.ExecuteFunction(context.Background(), requestID, request)
+		require.NoError(t, err)
+		require.Equal(t, regularResult, res)
+	})
+	t.Run("function packaged as container image", func(t *testing.T) {
+		router := newContainerRouter(mocks.NoopLogger, executor, containerExecutor, containerStore)
+
+		res, err := router.ExecuteFunction(context.Background(), requestID, request)
+		require.NoError(t, err)
+		require.Equal(t, containerResult, res)
+	})
+	t.Run("node cannot run containers", func(t *testing.T) {
+		router := newContainerRouter(mocks.NoopLogger, executor, nil, containerStore)
+
+		res, err := router.ExecuteFunction(context.Background(), requestID, request)
+		require.ErrorIs(t, err, errContainersNotSupported)
+		require.Equal(t, codes.NotAvailable, res.Code)
+	})
+	t.Run("worker without container executor does not support container functions", func(t *testing.T) {
+		node := createNode(t, blockless.WorkerNode)
+		node.fstore = containerStore
+
+		err := node.supportsFunction(context.Background(), request.FunctionID)
+		require.ErrorIs(t, err, errContainersNotSupported)
+
+		node.cfg.ContainerExecutor = containerExecutor
+
+		err = node.supportsFunction(context.Background(), request.FunctionID)
+		require.NoError(t, err)
+	})
+}
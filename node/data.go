@@ -0,0 +1,60 @@
+package node
+
+import (
+	"context"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+)
+
+// dataAddresses returns the addresses of the data host, if the node has one.
+func (n *Node) dataAddresses() []string {
+
+	if n.dataHost == nil {
+		return nil
+	}
+
+	return n.dataHost.Addresses()
+}
+
+// addDataAddresses records the data host addresses of the given peer.
+func (n *Node) addDataAddresses(from peer.ID, addresses []string) {
+
+	if n.dataHost == nil {
+		return
+	}
+
+	for _, address := range addresses {
+
+		addrInfo, err := peer.AddrInfoFromString(address)
+		if err != nil {
+			n.log.Warn().Err(err).Str("peer", from.String()).Str("address", address).Msg("could not parse data address")
+			continue
+		}
+
+		if addrInfo.ID != from {
+			n.log.Warn().Str("peer", from.String()).Str("address", address).Msg("data address belongs to a different peer, skipping")
+			continue
+		}
+
+		n.dataHost.Peerstore().AddAddrs(addrInfo.ID, addrInfo.Addrs, DataAddressTTL)
+	}
+}
+
+// sendData sends the message to the specified peer using the data host. If the data host is not available, or
+// the peer data addresses are not known, the message is sent using the main host.
+func (n *Node) sendData(ctx context.Context, to peer.ID, msg blockless.Message) error {
+
+	if n.dataHost == nil || len(n.dataHost.Peerstore().Addrs(to)) == 0 {
+		return n.send(ctx, to, msg)
+	}
+
+	err := n.sendWithHost(ctx, n.dataHost, to, msg)
+	if err != nil {
+		n.log.Warn().Err(err).Str("peer", to.String()).Msg("could not send message using data host, falling back to main host")
+		return n.send(ctx, to, msg)
+	}
+
+	return nil
+}
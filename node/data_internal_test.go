@@ -0,0 +1,126 @@
+package node
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_SendData(t *testing.T) {
+
+	const (
+		requestID = "dummy-request-id"
+	)
+
+	msg := response.Execute{
+		RequestID: requestID,
+		Code:      codes.OK,
+	}
+
+	t.Run("message is sent using the data host", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNodeWithDataHost(t)
+
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+		receiverData, err := host.New(mocks.NoopLogger, loopback, 0, host.WithIdentity(receiver.PrivateKey()))
+		require.NoError(t, err)
+
+		require.Equal(t, receiver.ID(), receiverData.ID())
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+
+		receiverData.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer wg.Done()
+			defer stream.Close()
+
+			var received response.Execute
+			getStreamPayload(t, stream, &received)
+
+			require.Equal(t, requestID, received.RequestID)
+		})
+
+		node.addDataAddresses(receiver.ID(), receiverData.Addresses())
+
+		err = node.sendData(context.Background(), receiver.ID(), &msg)
+		require.NoError(t, err)
+
+		wg.Wait()
+	})
+	t.Run("message is sent using the main host if data addresses are not known", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNodeWithDataHost(t)
+
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, receiver)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer wg.Done()
+			defer stream.Close()
+
+			var received response.Execute
+			getStreamPayload(t, stream, &received)
+
+			require.Equal(t, requestID, received.RequestID)
+		})
+
+		err = node.sendData(context.Background(), receiver.ID(), &msg)
+		require.NoError(t, err)
+
+		wg.Wait()
+	})
+	t.Run("data addresses of other peers are ignored", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNodeWithDataHost(t)
+
+		other, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		node.addDataAddresses(mocks.GenericPeerID, other.Addresses())
+		require.Empty(t, node.dataHost.Peerstore().Addrs(mocks.GenericPeerID))
+	})
+	t.Run("data host must share node identity", func(t *testing.T) {
+		t.Parallel()
+
+		h, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+		dataHost, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		_, err = New(mocks.NoopLogger, h, mocks.BaselineStore(t), mocks.BaselineFStore(t), WithRole(blockless.HeadNode), WithDataHost(dataHost))
+		require.Error(t, err)
+	})
+}
+
+func createNodeWithDataHost(t *testing.T) *Node {
+	t.Helper()
+
+	h, err := host.New(mocks.NoopLogger, loopback, 0)
+	require.NoError(t, err)
+
+	dataHost, err := host.New(mocks.NoopLogger, loopback, 0, host.WithIdentity(h.PrivateKey()))
+	require.NoError(t, err)
+
+	node, err := New(mocks.NoopLogger, h, mocks.BaselineStore(t), mocks.BaselineFStore(t), WithRole(blockless.HeadNode), WithDataHost(dataHost))
+	require.NoError(t, err)
+
+	return node
+}
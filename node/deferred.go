@@ -0,0 +1,291 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/node/head/deferred"
+	"github.com/blocklessnetwork/b7s/node/head/journal"
+	"github.com/blocklessnetwork/b7s/node/head/recovery"
+)
+
+var (
+	errUnknownDeferredExecution   = errors.New("unknown deferred execution")
+	errDuplicateDeferredExecution = errors.New("deferred execution with the same request ID already exists")
+
+	errDeferredExecutionNotSupported = errors.New("executions with a not-before time are only supported for execution request messages")
+)
+
+// deferredExecutions holds the execution requests waiting for their not-before time.
+type deferredExecutions struct {
+	sync.Mutex
+	entries map[string]deferred.Entry
+}
+
+func newDeferredExecutions() *deferredExecutions {
+	return &deferredExecutions{
+		entries: make(map[string]deferred.Entry),
+	}
+}
+
+func (d *deferredExecutions) add(entry deferred.Entry) bool {
+	d.Lock()
+	defer d.Unlock()
+
+	_, ok := d.entries[entry.ID()]
+	if ok {
+		return false
+	}
+
+	d.entries[entry.ID()] = entry
+
+	return true
+}
+
+func (d *deferredExecutions) get(id string) (deferred.Entry, bool) {
+	d.Lock()
+	defer d.Unlock()
+
+	entry, ok := d.entries[id]
+	return entry, ok
+}
+
+func (d *deferredExecutions) remove(id string) bool {
+	d.Lock()
+	defer d.Unlock()
+
+	_, ok := d.entries[id]
+	delete(d.entries, id)
+
+	return ok
+}
+
+// due returns the requests that should be dispatched at the given time, and stops tracking them.
+func (d *deferredExecutions) due(now time.Time) []deferred.Entry {
+	d.Lock()
+	defer d.Unlock()
+
+	var due []deferred.Entry
+	for id, entry := range d.entries {
+
+		if entry.NotBefore().After(now) {
+			continue
+		}
+
+		due = append(due, entry)
+		delete(d.entries, id)
+	}
+
+	return due
+}
+
+// deferExecution holds the execution request until its not-before time. The request is acknowledged right away,
+// and the results are delivered once the execution is done, as with asynchronous requests.
+func (n *Node) deferExecution(ctx context.Context, from peer.ID, req request.Execute) error {
+
+	if req.RequestID == "" {
+		req.RequestID = n.newRequestID()
+	}
+
+	entry := deferred.Entry{
+		Request: req,
+		Origin:  from,
+		Created: time.Now().UTC(),
+	}
+
+	err := n.addDeferredExecution(entry)
+	if err != nil {
+		n.log.Error().Err(err).Str("peer", from.String()).Str("request", req.RequestID).Msg("could not defer execution")
+
+		code := codes.Error
+		if errors.Is(err, errDuplicateDeferredExecution) {
+			code = codes.Invalid
+		}
+
+		err = n.send(ctx, from, req.Response(code).WithErrorMessage(err))
+		if err != nil {
+			return fmt.Errorf("could not send response: %w", err)
+		}
+		return nil
+	}
+
+	n.metrics.IncrCounter(deferredExecutionsMetric, 1)
+
+	n.log.Info().Str("peer", from.String()).Str("request", req.RequestID).Str("function", req.FunctionID).Time("not_before", req.Config.NotBefore).Msg("execution deferred")
+
+	err = n.send(ctx, from, req.Response(codes.Accepted))
+	if err != nil {
+		return fmt.Errorf("could not send acknowledgement: %w", err)
+	}
+
+	return nil
+}
+
+func (n *Node) addDeferredExecution(entry deferred.Entry) error {
+
+	if !n.deferred.add(entry) {
+		return errDuplicateDeferredExecution
+	}
+
+	if n.cfg.DeferredStore != nil {
+		err := n.cfg.DeferredStore.Save(entry)
+		if err != nil {
+			n.deferred.remove(entry.ID())
+			return fmt.Errorf("could not save deferred execution: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// CancelDeferredExecution removes the deferred execution, if it was not dispatched yet.
+func (n *Node) CancelDeferredExecution(id string) error {
+
+	if !n.deferred.remove(id) {
+		return errUnknownDeferredExecution
+	}
+
+	n.removeStoredDeferredExecution(id)
+
+	n.log.Info().Str("request", id).Msg("deferred execution cancelled")
+
+	return nil
+}
+
+func (n *Node) removeStoredDeferredExecution(id string) {
+
+	if n.cfg.DeferredStore == nil {
+		return
+	}
+
+	err := n.cfg.DeferredStore.Remove(id)
+	if err != nil {
+		n.log.Warn().Err(err).Str("request", id).Msg("could not remove deferred execution from store")
+	}
+}
+
+func (n *Node) processCancelExecution(ctx context.Context, from peer.ID, req request.CancelExecution) error {
+
+	err := req.Valid()
+	if err != nil {
+		err = n.send(ctx, from, req.Response(codes.Invalid).WithErrorMessage(err))
+		if err != nil {
+			return fmt.Errorf("could not send response: %w", err)
+		}
+		return nil
+	}
+
+	code := codes.OK
+	entry, ok := n.deferred.get(req.RequestID)
+	switch {
+	case !ok:
+		code = codes.NotFound
+	// Only the client that requested the execution can cancel it.
+	case entry.Origin != from:
+		code = codes.NotPermitted
+	default:
+		err = n.CancelDeferredExecution(req.RequestID)
+		if err != nil {
+			// Execution might have been dispatched in the meantime.
+			code = codes.NotFound
+		}
+	}
+
+	n.log.Debug().Str("peer", from.String()).Str("request", req.RequestID).Str("code", code.String()).Msg("processed deferred execution cancellation")
+
+	err = n.send(ctx, from, req.Response(code))
+	if err != nil {
+		return fmt.Errorf("could not send response: %w", err)
+	}
+
+	return nil
+}
+
+// loadDeferredExecutions restores the deferred executions saved by a previous run.
+func (n *Node) loadDeferredExecutions() error {
+
+	if n.cfg.DeferredStore == nil {
+		return nil
+	}
+
+	entries, corrupted, err := n.cfg.DeferredStore.Scan()
+	if err != nil {
+		return fmt.Errorf("could not retrieve deferred executions: %w", err)
+	}
+
+	for _, c := range corrupted {
+		n.log.Warn().Str("request", c.ID).Str("reason", c.Reason).Msg("skipping corrupted deferred execution")
+	}
+
+	var loaded uint
+	for _, entry := range entries {
+		if n.deferred.add(entry) {
+			loaded++
+		}
+	}
+
+	n.log.Info().Uint("count", loaded).Int("corrupted", len(corrupted)).Msg("loaded deferred executions")
+
+	n.recovery.update(func(r *recovery.Report) {
+		r.RecoveredDeferred = loaded
+		r.Corrupted = append(r.Corrupted, corrupted...)
+	})
+
+	return nil
+}
+
+// runDeferredExecutions dispatches deferred executions once their not-before time comes.
+// Executions that became due while the head node was down are dispatched right away.
+func (n *Node) runDeferredExecutions(ctx context.Context) {
+
+	err := n.loadDeferredExecutions()
+	if err != nil {
+		n.log.Error().Err(err).Msg("could not load deferred executions")
+	}
+
+	ticker := time.NewTicker(scheduleCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case now := <-ticker.C:
+			for _, entry := range n.deferred.due(now) {
+				// Once dispatched, the request journal takes over tracking the request.
+				n.removeStoredDeferredExecution(entry.ID())
+				go n.runDeferredExecution(ctx, entry)
+			}
+		}
+	}
+}
+
+// runDeferredExecution executes the deferred request and delivers the results to the client.
+func (n *Node) runDeferredExecution(ctx context.Context, entry deferred.Entry) {
+
+	req := entry.Request
+	requestID := n.newRequestID()
+	drift := time.Since(entry.NotBefore())
+
+	n.log.Info().Str("request", req.RequestID).Str("function", req.FunctionID).Dur("drift", drift).Msg("dispatching deferred execution")
+
+	n.journalUpdate(requestID, func(e *journal.Entry) {
+		e.Origin = entry.Origin
+		e.ClientRequestID = req.RequestID
+	})
+
+	res := n.headExecuteRequest(ctx, entry.Origin, requestID, req)
+	if res.Timing != nil {
+		res.Timing.SchedulingDrift = drift.Milliseconds()
+	}
+
+	n.deliverAsyncResponse(ctx, entry.Origin, req, res)
+}
@@ -0,0 +1,169 @@
+package node
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/node/head/deferred"
+	"github.com/blocklessnetwork/b7s/testing/helpers"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_DeferredExecution(t *testing.T) {
+
+	const (
+		requestID = "dummy-request-id"
+	)
+
+	// receive returns a function that waits for the next message sent to the host and decodes it.
+	receive := func(t *testing.T, receiver *host.Host) func(any) {
+		t.Helper()
+
+		payloads := make(chan network.Stream)
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			payloads <- stream
+		})
+
+		return func(out any) {
+			stream := <-payloads
+			defer stream.Close()
+
+			getStreamPayload(t, stream, out)
+		}
+	}
+
+	t.Run("execution is deferred, persisted and can be cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		db := helpers.InMemoryDB(t)
+		defer db.Close()
+
+		store := deferred.NewStore(db)
+
+		node := createNode(t, blockless.HeadNode)
+		node.cfg.DeferredStore = store
+
+		client, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+		hostAddNewPeer(t, node.host, client)
+
+		other, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+		hostAddNewPeer(t, node.host, other)
+
+		var (
+			clientReceive = receive(t, client)
+			otherReceive  = receive(t, other)
+		)
+
+		req := request.Execute{
+			Request:   mocks.GenericExecutionRequest,
+			RequestID: requestID,
+		}
+		req.Config.NotBefore = time.Now().Add(time.Hour)
+
+		var (
+			wg       sync.WaitGroup
+			received response.Execute
+		)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			clientReceive(&received)
+		}()
+
+		err = node.headProcessExecute(context.Background(), client.ID(), req)
+		require.NoError(t, err)
+		wg.Wait()
+
+		require.Equal(t, codes.Accepted, received.Code)
+		require.Equal(t, requestID, received.RequestID)
+
+		entries, _, err := store.Scan()
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, client.ID(), entries[0].Origin)
+
+		// Execution can only be cancelled by the client that requested it.
+		cancel := request.CancelExecution{RequestID: requestID}
+
+		var cancelled response.CancelExecution
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			otherReceive(&cancelled)
+		}()
+
+		err = node.processCancelExecution(context.Background(), other.ID(), cancel)
+		require.NoError(t, err)
+		wg.Wait()
+
+		require.Equal(t, codes.NotPermitted, cancelled.Code)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			clientReceive(&cancelled)
+		}()
+
+		err = node.processCancelExecution(context.Background(), client.ID(), cancel)
+		require.NoError(t, err)
+		wg.Wait()
+
+		require.Equal(t, codes.OK, cancelled.Code)
+		require.Equal(t, requestID, cancelled.RequestID)
+
+		entries, _, err = store.Scan()
+		require.NoError(t, err)
+		require.Empty(t, entries)
+
+		require.ErrorIs(t, node.CancelDeferredExecution(requestID), errUnknownDeferredExecution)
+	})
+	t.Run("deferred executions are due after their not-before time", func(t *testing.T) {
+		t.Parallel()
+
+		now := time.Now()
+
+		entry := func(id string, notBefore time.Time) deferred.Entry {
+			req := request.Execute{RequestID: id}
+			req.Config.NotBefore = notBefore
+			return deferred.Entry{Request: req}
+		}
+
+		executions := newDeferredExecutions()
+		require.True(t, executions.add(entry("past", now.Add(-time.Minute))))
+		require.True(t, executions.add(entry("future", now.Add(time.Minute))))
+		require.False(t, executions.add(entry("future", now.Add(time.Hour))))
+
+		due := executions.due(now)
+		require.Len(t, due, 1)
+		require.Equal(t, "past", due[0].ID())
+		require.Empty(t, executions.due(now))
+
+		due = executions.due(now.Add(2 * time.Minute))
+		require.Len(t, due, 1)
+		require.Equal(t, "future", due[0].ID())
+	})
+	t.Run("synchronous execution with not-before time is not supported", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+
+		req := mocks.GenericExecutionRequest
+		req.Config.NotBefore = time.Now().Add(time.Hour)
+
+		code, _, _, _, _, err := node.ExecuteFunction(context.Background(), req, "")
+		require.ErrorIs(t, err, errDeferredExecutionNotSupported)
+		require.Equal(t, codes.NotSupported, code)
+	})
+}
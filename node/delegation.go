@@ -0,0 +1,279 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/b7serrors"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+const (
+	// Maximum number of peer head nodes asked to take over a single request.
+	delegationMaxAttempts = 3
+)
+
+var (
+	errDelegationLoop     = errors.New("request was already delegated through this head node")
+	errDelegationTooDeep  = errors.New("request was delegated too many times")
+	errDelegationRefused  = errors.New("head node cannot take delegated executions")
+	errDelegationOverload = errors.New("head node is overloaded")
+)
+
+// executeOrDelegate runs the execution on this head node, unless it is overloaded. In that case the request is delegated
+// to a peer head node, while the results are still returned to the caller. If no peer head node takes over the request,
+// it is executed locally.
+func (n *Node) executeOrDelegate(ctx context.Context, requestID string, req execute.Request, subgroup string, provenance execute.Provenance) (codes.Code, execute.ResultMap, execute.Cluster, execute.Timing, error) {
+
+	if !n.overloaded() || delegations(provenance) >= n.cfg.DelegationMaxDepth {
+		return n.headExecute(ctx, requestID, req, subgroup, provenance)
+	}
+
+	// The request is done once we return, also when a peer head node ran it for us.
+	defer n.journalRemove(requestID)
+
+	attempts := newAttemptLog()
+
+	res, err := n.delegateExecution(ctx, attempts, requestID, req, subgroup, provenance)
+	if err == nil {
+		// Communicate the reason for failure reported by the peer head node.
+		if res.ErrorMessage != "" {
+			err = b7serrors.ParsePublic(res.ErrorMessage)
+		}
+
+		res.Cluster.Attempts = attempts.history(res.Cluster.Attempts, execute.AttemptDelegation, []peer.ID{res.Cluster.Delegate}, res.Code, err)
+		return res.Code, res.Results, res.Cluster, res.Timing, err
+	}
+
+	if ctx.Err() != nil {
+		return codes.Timeout, nil, execute.Cluster{}, execute.Timing{}, fmt.Errorf("delegated execution timed out: %w", err)
+	}
+
+	n.log.Warn().Err(err).Str("request", requestID).Msg("could not delegate execution, executing locally")
+
+	code, results, cluster, timing, err := n.headExecute(ctx, requestID, req, subgroup, provenance)
+	cluster.Attempts = attempts.history(cluster.Attempts, execute.AttemptExecution, resultPeers(results, cluster.Peers), code, err)
+
+	return code, results, cluster, timing, err
+}
+
+// overloaded returns true if the head node has more executions in progress than the delegation threshold allows.
+func (n *Node) overloaded() bool {
+	return n.cfg.DelegationThreshold > 0 && uint(n.executions.count()) >= n.cfg.DelegationThreshold
+}
+
+// delegateExecution asks connected peer head nodes, one at a time, to run the execution, and returns the response of the
+// first one that takes it over. Head nodes the request already passed through are skipped, so the request cannot go around in circles.
+// Head nodes that did not take over the request are recorded in the attempt log.
+func (n *Node) delegateExecution(ctx context.Context, attempts *attemptLog, requestID string, req execute.Request, subgroup string, provenance execute.Provenance) (response.DelegateExecution, error) {
+
+	candidates := n.delegationCandidates(provenance)
+	if len(candidates) == 0 {
+		return response.DelegateExecution{}, errors.New("no peer head nodes available")
+	}
+
+	chain, err := provenance.Append(n.host.PrivateKey(), requestID, req, n.cfg.Role.String(), time.Now())
+	if err != nil {
+		return response.DelegateExecution{}, fmt.Errorf("could not add head node to request provenance: %w", err)
+	}
+
+	// Wait for the peer head node as long as we would wait for the execution we run ourselves.
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, n.cfg.RollCallTimeout+n.cfg.ExecutionTimeout)
+		defer cancel()
+	}
+	deadline, _ := ctx.Deadline()
+
+	delegate := request.DelegateExecution{
+		Request:    req,
+		RequestID:  requestID,
+		Topic:      subgroup,
+		Provenance: chain,
+	}
+
+	for _, head := range candidates[:min(len(candidates), delegationMaxAttempts)] {
+
+		log := n.log.With().Str("request", requestID).Stringer("head", head).Logger()
+
+		started := time.Now()
+		delegate.Remaining = remainingTime(deadline)
+		res, err := n.delegateTo(ctx, head, delegate)
+		if err != nil {
+			if ctx.Err() != nil {
+				return response.DelegateExecution{}, err
+			}
+
+			log.Debug().Err(err).Msg("peer head node did not take over the execution")
+
+			if errors.Is(err, errDelegationRefused) {
+				attempts.fail(execute.AttemptDelegation, []peer.ID{head}, codes.NotAvailable, started, err.Error())
+			} else {
+				attempts.fail(execute.AttemptDelegation, []peer.ID{head}, codes.Timeout, started, "no response from peer head node")
+			}
+			continue
+		}
+
+		log.Info().Str("code", res.Code.String()).Msg("delegated execution complete")
+
+		n.metrics.IncrCounter(executionsDelegatedMetric, 1)
+
+		res.Cluster.Delegate = head
+		return res, nil
+	}
+
+	return response.DelegateExecution{}, errors.New("no peer head node took over the execution")
+}
+
+// delegateTo sends the request to the peer head node and waits for the response. A refusal is returned as an error.
+func (n *Node) delegateTo(ctx context.Context, head peer.ID, req request.DelegateExecution) (response.DelegateExecution, error) {
+
+	err := n.send(ctx, head, &req)
+	if err != nil {
+		return response.DelegateExecution{}, fmt.Errorf("could not send delegated execution: %w", err)
+	}
+
+	// Do not wait for peers that disconnect.
+	pctx, cancel := n.peerContext(ctx, head)
+	defer cancel()
+
+	res, ok := n.delegations.WaitFor(pctx, consensusResponseKey(req.RequestID, head))
+	if !ok {
+		return response.DelegateExecution{}, errors.New("no response from peer head node")
+	}
+
+	if res.Code == codes.NotAvailable {
+		return response.DelegateExecution{}, fmt.Errorf("%w: %s", errDelegationRefused, res.ErrorMessage)
+	}
+
+	return res, nil
+}
+
+// delegationCandidates returns the connected peer head nodes that have not yet handled the request, in random order.
+func (n *Node) delegationCandidates(provenance execute.Provenance) []peer.ID {
+
+	var candidates []peer.ID
+	for id, p := range n.peers.all() {
+		if p.role != blockless.HeadNode || id == n.host.ID() || !n.haveConnection(id) {
+			continue
+		}
+
+		if slices.ContainsFunc(provenance, func(hop execute.Hop) bool { return hop.Peer == id }) {
+			continue
+		}
+
+		candidates = append(candidates, id)
+	}
+
+	slices.Sort(candidates)
+	n.rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+
+	return candidates
+}
+
+func (n *Node) processDelegateExecution(ctx context.Context, from peer.ID, req request.DelegateExecution) error {
+
+	log := n.log.With().Str("request", req.RequestID).Stringer("peer", from).Str("function", req.FunctionID).Logger()
+
+	err := n.acceptDelegation(from, req)
+	if err != nil {
+		log.Info().Err(err).Msg("turning down delegated execution")
+		n.metrics.IncrCounter(delegationsRejectedMetric, 1)
+
+		err = n.send(ctx, from, req.Response(codes.NotAvailable).WithErrorMessage(err))
+		if err != nil {
+			return fmt.Errorf("could not send response: %w", err)
+		}
+		return nil
+	}
+
+	exctx := ctx
+	if req.Remaining != 0 {
+		var cancel context.CancelFunc
+		exctx, cancel = context.WithTimeout(ctx, req.Remaining)
+		defer cancel()
+	}
+
+	requestID := n.newRequestID()
+	log.Info().Str("local_request", requestID).Msg("executing delegated request")
+
+	// The request may be delegated further, within the depth limit.
+	code, results, cluster, timing, err := n.executeOrDelegate(exctx, requestID, req.Request, req.Topic, req.Provenance)
+	if err != nil {
+		log.Error().Err(err).Msg("delegated execution failed")
+	}
+
+	res := req.Response(code).WithResults(results).WithCluster(cluster).WithTiming(timing)
+	if b7serrors.IsPublic(err) {
+		res.ErrorMessage = err.Error()
+	}
+
+	err = n.send(ctx, from, res)
+	if err != nil {
+		return fmt.Errorf("could not send response: %w", err)
+	}
+
+	return nil
+}
+
+// acceptDelegation checks if the head node can take over the execution delegated by the peer.
+func (n *Node) acceptDelegation(from peer.ID, req request.DelegateExecution) error {
+
+	err := req.Provenance.Verify(from, req.RequestID, req.Request)
+	if err != nil {
+		return fmt.Errorf("invalid provenance: %w", err)
+	}
+
+	if req.Provenance[len(req.Provenance)-1].Role != blockless.HeadNode.String() {
+		return errors.New("request provenance does not end with the delegating head node")
+	}
+
+	if slices.ContainsFunc(req.Provenance, func(hop execute.Hop) bool { return hop.Peer == n.host.ID() }) {
+		return errDelegationLoop
+	}
+
+	if n.cfg.DelegationMaxDepth == 0 || delegations(req.Provenance) > n.cfg.DelegationMaxDepth {
+		return errDelegationTooDeep
+	}
+
+	// Do not take on more work than we would run ourselves, but let the request move on if it may be delegated further.
+	if n.overloaded() && delegations(req.Provenance) >= n.cfg.DelegationMaxDepth {
+		return errDelegationOverload
+	}
+
+	return nil
+}
+
+// processDelegateExecutionResponse will record the response to the execution we delegated.
+func (n *Node) processDelegateExecutionResponse(ctx context.Context, from peer.ID, res response.DelegateExecution) error {
+
+	n.log.Debug().Str("request", res.RequestID).Stringer("from", from).Str("code", res.Code.String()).Msg("received delegated execution response")
+
+	n.delegations.Set(consensusResponseKey(res.RequestID, from), res)
+
+	return nil
+}
+
+// delegations returns the number of times the request was delegated, which is the number of head nodes in its provenance.
+func delegations(provenance execute.Provenance) uint {
+
+	var count uint
+	for _, hop := range provenance {
+		if hop.Role == blockless.HeadNode.String() {
+			count++
+		}
+	}
+
+	return count
+}
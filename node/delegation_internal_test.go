@@ -0,0 +1,150 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/b7serrors"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/node/head/journal"
+	"github.com/blocklessnetwork/b7s/testing/helpers"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_AcceptDelegation(t *testing.T) {
+
+	var (
+		origin = createNode(t, blockless.HeadNode)
+		node   = createNode(t, blockless.HeadNode)
+		req    = mocks.GenericExecutionRequest
+	)
+
+	delegated := func(t *testing.T, heads ...*Node) request.DelegateExecution {
+		t.Helper()
+
+		var (
+			provenance execute.Provenance
+			err        error
+		)
+		for _, head := range heads {
+			provenance, err = provenance.Append(head.host.PrivateKey(), mocks.GenericUUID.String(), req, blockless.HeadNode.String(), time.Now())
+			require.NoError(t, err)
+		}
+
+		return request.DelegateExecution{
+			Request:    req,
+			RequestID:  mocks.GenericUUID.String(),
+			Provenance: provenance,
+		}
+	}
+
+	t.Run("delegated execution is accepted", func(t *testing.T) {
+		err := node.acceptDelegation(origin.host.ID(), delegated(t, origin))
+		require.NoError(t, err)
+	})
+	t.Run("delegating head node must be the last hop", func(t *testing.T) {
+		err := node.acceptDelegation(mocks.GenericPeerID, delegated(t, origin))
+		require.Error(t, err)
+	})
+	t.Run("provenance must be for the delegated request", func(t *testing.T) {
+		other := delegated(t, origin)
+		other.RequestID = "other-request-id"
+
+		err := node.acceptDelegation(origin.host.ID(), other)
+		require.Error(t, err)
+
+		other = delegated(t, origin)
+		other.Method = "other-method"
+
+		err = node.acceptDelegation(origin.host.ID(), other)
+		require.Error(t, err)
+	})
+	t.Run("request is not delegated back to a head node it passed through", func(t *testing.T) {
+		err := node.acceptDelegation(origin.host.ID(), delegated(t, node, origin))
+		require.ErrorIs(t, err, errDelegationLoop)
+	})
+	t.Run("request is not delegated too many times", func(t *testing.T) {
+		other := createNode(t, blockless.HeadNode)
+		another := createNode(t, blockless.HeadNode)
+
+		err := node.acceptDelegation(origin.host.ID(), delegated(t, other, another, origin))
+		require.ErrorIs(t, err, errDelegationTooDeep)
+	})
+	t.Run("overloaded head node accepts requests it can delegate further", func(t *testing.T) {
+		node := createNode(t, blockless.HeadNode)
+		node.cfg.DelegationThreshold = 1
+		node.executions.start(mocks.GenericUUID.String(), req.FunctionID, 0, nil, nil, "")
+
+		err := node.acceptDelegation(origin.host.ID(), delegated(t, origin))
+		require.NoError(t, err)
+
+		other := createNode(t, blockless.HeadNode)
+		err = node.acceptDelegation(origin.host.ID(), delegated(t, other, origin))
+		require.ErrorIs(t, err, errDelegationOverload)
+	})
+}
+
+func TestNode_DelegateExecution(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	origin := createNode(t, blockless.HeadNode)
+	origin.cfg.RollCallTimeout = time.Second
+	origin.cfg.DelegationThreshold = 1
+
+	// Peer head node has no workers, so its roll call times out.
+	delegate := createNode(t, blockless.HeadNode)
+	delegate.cfg.RollCallTimeout = time.Second
+
+	hostAddNewPeer(t, origin.host, delegate.host)
+	err := origin.host.Connect(ctx, *hostGetAddrInfo(t, delegate.host))
+	require.NoError(t, err)
+
+	for _, head := range []*Node{origin, delegate} {
+		err = head.subscribeToTopics(ctx)
+		require.NoError(t, err)
+		head.listenDirectMessages(ctx)
+	}
+
+	origin.peers.update(delegate.host.ID(), blockless.HeadNode, nil, false)
+
+	req := execute.Request{FunctionID: "dummy-function-id", Method: "dummy-method"}
+
+	t.Run("head node executes locally when not overloaded", func(t *testing.T) {
+		_, _, cluster, _, err := origin.executeOrDelegate(ctx, origin.newRequestID(), req, "", nil)
+		require.ErrorIs(t, err, b7serrors.ErrRollCallTimeout)
+		require.Empty(t, cluster.Delegate)
+	})
+	t.Run("overloaded head node delegates the execution", func(t *testing.T) {
+		origin.executions.start(mocks.GenericUUID.String(), req.FunctionID, 0, nil, nil, "")
+		defer origin.executions.done(mocks.GenericUUID.String())
+
+		db := helpers.InMemoryDB(t)
+		defer db.Close()
+
+		origin.cfg.Journal = journal.New(db)
+		defer func() { origin.cfg.Journal = nil }()
+
+		requestID := origin.newRequestID()
+		origin.journalUpdate(requestID, func(e *journal.Entry) {
+			e.Origin = mocks.GenericPeerID
+		})
+
+		code, _, cluster, _, err := origin.executeOrDelegate(ctx, requestID, req, "", nil)
+		require.ErrorIs(t, err, b7serrors.ErrRollCallTimeout)
+		require.Equal(t, codes.Timeout, code)
+		require.Equal(t, delegate.host.ID(), cluster.Delegate)
+
+		// Delegated request is no longer in the journal.
+		entries, _, err := origin.cfg.Journal.Scan()
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+}
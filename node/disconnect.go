@@ -0,0 +1,101 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+var errPeerDisconnected = errors.New("peer disconnected")
+
+// disconnectWatcher notifies interested parties when a peer disconnects. It is fed by the connection notifiee.
+type disconnectWatcher struct {
+	sync.Mutex
+
+	next    uint64
+	watches map[peer.ID]map[uint64]chan struct{}
+}
+
+func newDisconnectWatcher() *disconnectWatcher {
+	return &disconnectWatcher{
+		watches: make(map[peer.ID]map[uint64]chan struct{}),
+	}
+}
+
+// watch returns a channel that is closed when the peer disconnects, along with a function to stop watching.
+func (w *disconnectWatcher) watch(peer peer.ID) (<-chan struct{}, func()) {
+
+	w.Lock()
+	defer w.Unlock()
+
+	id := w.next
+	w.next++
+
+	ch := make(chan struct{})
+
+	watches, ok := w.watches[peer]
+	if !ok {
+		watches = make(map[uint64]chan struct{})
+		w.watches[peer] = watches
+	}
+	watches[id] = ch
+
+	stop := func() {
+		w.Lock()
+		defer w.Unlock()
+
+		delete(w.watches[peer], id)
+		if len(w.watches[peer]) == 0 {
+			delete(w.watches, peer)
+		}
+	}
+
+	return ch, stop
+}
+
+// disconnected notifies everyone watching the peer.
+func (w *disconnectWatcher) disconnected(peer peer.ID) {
+
+	w.Lock()
+	defer w.Unlock()
+
+	for _, ch := range w.watches[peer] {
+		close(ch)
+	}
+
+	delete(w.watches, peer)
+}
+
+// peerContext returns a context that is cancelled when the peer disconnects, with `errPeerDisconnected` as the cause.
+func (n *Node) peerContext(ctx context.Context, peer peer.ID) (context.Context, context.CancelFunc) {
+
+	pctx, cancel := context.WithCancelCause(ctx)
+
+	disconnected, stop := n.disconnects.watch(peer)
+
+	// Peer might have disconnected before we started watching.
+	if !n.haveConnection(peer) {
+		stop()
+		cancel(errPeerDisconnected)
+		return pctx, func() {}
+	}
+
+	go func() {
+		defer stop()
+
+		select {
+		case <-pctx.Done():
+		case <-disconnected:
+			cancel(errPeerDisconnected)
+		}
+	}()
+
+	return pctx, func() { cancel(context.Canceled) }
+}
+
+// peerDisconnected returns true if the context returned by `peerContext` was cancelled because the peer disconnected.
+func peerDisconnected(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), errPeerDisconnected)
+}
@@ -0,0 +1,112 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestDisconnectWatcher(t *testing.T) {
+
+	var (
+		watched = mocks.GenericPeerIDs[0]
+		other   = mocks.GenericPeerIDs[1]
+	)
+
+	t.Run("watchers are notified of disconnects", func(t *testing.T) {
+		t.Parallel()
+
+		w := newDisconnectWatcher()
+
+		first, stopFirst := w.watch(watched)
+		defer stopFirst()
+		second, stopSecond := w.watch(watched)
+		defer stopSecond()
+
+		w.disconnected(other)
+
+		select {
+		case <-first:
+			require.FailNow(t, "watcher notified about a different peer")
+		default:
+		}
+
+		w.disconnected(watched)
+
+		<-first
+		<-second
+		require.Empty(t, w.watches)
+	})
+	t.Run("stopped watchers are removed", func(t *testing.T) {
+		t.Parallel()
+
+		w := newDisconnectWatcher()
+
+		_, stop := w.watch(watched)
+		stop()
+
+		require.Empty(t, w.watches)
+	})
+}
+
+func TestNode_GatherExecutionResultsDisconnect(t *testing.T) {
+
+	const (
+		requestID = "dummy-request-id"
+	)
+
+	t.Run("disconnected peers are not waited for", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			responded = mocks.GenericPeerIDs[0]
+			missing   = mocks.GenericPeerIDs[1]
+		)
+
+		node := createNode(t, blockless.HeadNode)
+
+		res := execute.NodeResult{Result: execute.Result{Code: codes.OK}}
+		node.executeResponses.Set(executionResultKey(requestID, responded), singleNodeResultMap(responded, res))
+
+		start := time.Now()
+		results, dropped := node.gatherExecutionResults(context.Background(), requestID, []peer.ID{responded, missing})
+		require.Less(t, time.Since(start), node.cfg.ExecutionTimeout)
+
+		require.Len(t, results, 1)
+		require.Contains(t, results, responded)
+		require.Equal(t, []peer.ID{missing}, dropped)
+	})
+	t.Run("peer disconnecting during execution is dropped", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+
+		worker, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, worker)
+		err = node.host.Connect(context.Background(), *hostGetAddrInfo(t, worker))
+		require.NoError(t, err)
+
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			worker.Close()
+		}()
+
+		start := time.Now()
+		results, dropped := node.gatherExecutionResults(context.Background(), requestID, []peer.ID{worker.ID()})
+		require.Less(t, time.Since(start), node.cfg.ExecutionTimeout)
+
+		require.Empty(t, results)
+		require.Equal(t, []peer.ID{worker.ID()}, dropped)
+	})
+}
@@ -0,0 +1,117 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/request"
+)
+
+const (
+	// How often the draining worker checks if it has finished the work in progress.
+	drainCheckInterval = 250 * time.Millisecond
+
+	// How long after answering a roll call can the head node still send the execution request. The draining worker
+	// waits this long after the last roll call it answered, so executions it signed up for are not dropped.
+	drainRollCallGrace = 2 * DefaultRollCallTimeout
+)
+
+var errDrainNotSupported = errors.New("drain mode is only supported on worker nodes")
+
+// drainState tracks whether the worker stopped taking on new work ahead of shutdown.
+type drainState struct {
+	active atomic.Bool
+	// lastRollCall is the time (Unix nanoseconds) of the last roll call the worker answered.
+	lastRollCall atomic.Int64
+}
+
+// Drain puts the worker in drain mode - it stops answering roll calls, while executions and consensus clusters in progress
+// are allowed to finish. Drain returns once the worker is idle and can be shut down without dropping any work.
+// If the context is cancelled first, the worker stays in drain mode.
+func (n *Node) Drain(ctx context.Context) error {
+
+	if !n.isWorker() {
+		return errDrainNotSupported
+	}
+
+	if !n.drain.active.Swap(true) {
+		n.log.Info().Msg("entering drain mode, no longer answering roll calls")
+	}
+
+	ticker := time.NewTicker(drainCheckInterval)
+	defer ticker.Stop()
+
+	for !n.drained(time.Now()) {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("work still in progress: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+
+	n.log.Info().Msg("worker drained, ready for shutdown")
+
+	return nil
+}
+
+// Draining returns true if the worker is in drain mode.
+func (n *Node) Draining() bool {
+	return n.drain.active.Load()
+}
+
+// drained returns true if the worker has no executions or consensus clusters in progress, and no executions are expected
+// for the roll calls it answered.
+func (n *Node) drained(now time.Time) bool {
+
+	if n.work.Running() > 0 || n.work.Waiting() > 0 {
+		return false
+	}
+
+	n.clusterLock.RLock()
+	clusters := len(n.clusters)
+	n.clusterLock.RUnlock()
+
+	if clusters > 0 {
+		return false
+	}
+
+	return now.Sub(time.Unix(0, n.drain.lastRollCall.Load())) >= drainRollCallGrace
+}
+
+func (n *Node) processDrain(ctx context.Context, from peer.ID, req request.Drain) error {
+
+	log := n.log.With().Str("peer", from.String()).Logger()
+
+	if !slices.Contains(n.cfg.AdminPeers, from) {
+		log.Warn().Msg("rejecting drain request from peer that is not an admin")
+
+		err := n.send(ctx, from, req.Response(codes.NotPermitted))
+		if err != nil {
+			return fmt.Errorf("could not send response: %w", err)
+		}
+
+		return nil
+	}
+
+	res := req.Response(codes.OK)
+
+	err := n.Drain(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("could not drain worker")
+		res = req.Response(codes.Error).WithErrorMessage(err)
+	}
+
+	err = n.send(ctx, from, res)
+	if err != nil {
+		return fmt.Errorf("could not send response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package node
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_Drain(t *testing.T) {
+
+	const (
+		drainTimeout = 5 * time.Second
+		stuckTimeout = time.Second
+	)
+
+	t.Run("head node cannot be drained", func(t *testing.T) {
+		node := createNode(t, blockless.HeadNode)
+
+		err := node.Drain(context.Background())
+		require.ErrorIs(t, err, errDrainNotSupported)
+		require.False(t, node.Draining())
+	})
+	t.Run("idle worker is drained right away", func(t *testing.T) {
+		node := createNode(t, blockless.WorkerNode)
+
+		ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+		defer cancel()
+
+		err := node.Drain(ctx)
+		require.NoError(t, err)
+		require.True(t, node.Draining())
+	})
+	t.Run("worker waits for consensus clusters to finish", func(t *testing.T) {
+		node := createNode(t, blockless.WorkerNode)
+		node.clusters[mocks.GenericUUID.String()] = &testCluster{}
+
+		ctx, cancel := context.WithTimeout(context.Background(), stuckTimeout)
+		defer cancel()
+
+		err := node.Drain(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.True(t, node.Draining())
+
+		node.clusterLock.Lock()
+		delete(node.clusters, mocks.GenericUUID.String())
+		node.clusterLock.Unlock()
+
+		ctx, cancel = context.WithTimeout(context.Background(), drainTimeout)
+		defer cancel()
+
+		err = node.Drain(ctx)
+		require.NoError(t, err)
+	})
+	t.Run("worker waits for executions of answered roll calls", func(t *testing.T) {
+		node := createNode(t, blockless.WorkerNode)
+
+		now := time.Now()
+		node.drain.lastRollCall.Store(now.UnixNano())
+
+		require.False(t, node.drained(now))
+		require.True(t, node.drained(now.Add(drainRollCallGrace)))
+	})
+	t.Run("draining worker declines roll calls", func(t *testing.T) {
+		node := createNode(t, blockless.WorkerNode)
+		node.drain.active.Store(true)
+
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, receiver)
+
+		var (
+			wg       sync.WaitGroup
+			received response.RollCall
+		)
+		wg.Add(1)
+
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer wg.Done()
+			defer stream.Close()
+
+			getStreamPayload(t, stream, &received)
+		})
+
+		rollCall := request.RollCall{
+			FunctionID: "dummy-function-id",
+			RequestID:  mocks.GenericUUID.String(),
+			Origin:     receiver.ID(),
+		}
+
+		err = node.processRollCall(context.Background(), receiver.ID(), rollCall)
+		require.NoError(t, err)
+
+		wg.Wait()
+
+		require.Equal(t, codes.NotAvailable, received.Code)
+		require.Equal(t, response.RollCallReasonDraining, received.Reason)
+	})
+}
@@ -2,12 +2,9 @@ package node
 
 import (
 	"context"
-	"fmt"
-	"strings"
 
 	"github.com/libp2p/go-libp2p/core/peer"
 
-	"github.com/blocklessnetwork/b7s/consensus"
 	"github.com/blocklessnetwork/b7s/models/codes"
 	"github.com/blocklessnetwork/b7s/models/execute"
 	"github.com/blocklessnetwork/b7s/models/request"
@@ -26,9 +23,44 @@ func (n *Node) processExecuteResponse(ctx context.Context, from peer.ID, res res
 
 	n.log.Debug().Str("request", res.RequestID).Str("from", from.String()).Msg("received execution response")
 
+	// Drop results that do not match their checksum - they were corrupted on the way.
+	for executingPeer, result := range res.Results {
+		err := result.VerifyChecksum()
+		if err != nil {
+			n.log.Warn().Err(err).Str("request", res.RequestID).Str("peer", executingPeer.String()).Msg("dropping execution result with invalid checksum")
+			n.metrics.IncrCounter(checksumMismatchMetric, 1)
+			delete(res.Results, executingPeer)
+		}
+	}
+
+	// Drop results of other executions, e.g. results of a different tenant's request, mixed up or replayed.
+	watermark, ok := n.executions.watermark(res.RequestID)
+	if ok {
+		for executingPeer, result := range res.Results {
+			err := result.VerifyWatermark(watermark, res.RequestID)
+			if err != nil {
+				n.log.Warn().Err(err).Str("request", res.RequestID).Str("peer", executingPeer.String()).Msg("dropping execution result with invalid watermark")
+				n.metrics.IncrCounter(watermarkMismatchMetric, 1)
+				delete(res.Results, executingPeer)
+			}
+		}
+	}
+
+	// Workers with a full work queue turn down the request without results, so failover can pick another worker right away.
+	if res.Code == codes.Overloaded {
+		n.log.Info().Str("request", res.RequestID).Str("from", from.String()).Msg("worker is overloaded and turned down the execution request")
+	}
+
+	n.linkExecutionSpan(res.RequestID, res.TraceInfo)
+
 	key := executionResultKey(res.RequestID, from)
 	n.executeResponses.Set(key, res.Results)
 
+	err := n.streamResults(ctx, from, res.RequestID, res.Results)
+	if err != nil {
+		n.log.Warn().Err(err).Str("request", res.RequestID).Msg("could not stream execution results")
+	}
+
 	return nil
 }
 
@@ -62,21 +94,3 @@ func determineOverallCode(results map[string]execute.Result) codes.Code {
 
 	return codes.Error
 }
-
-func parseConsensusAlgorithm(value string) (consensus.Type, error) {
-
-	if value == "" {
-		return 0, nil
-	}
-
-	lv := strings.ToLower(value)
-	switch lv {
-	case "raft":
-		return consensus.Raft, nil
-
-	case "pbft":
-		return consensus.PBFT, nil
-	}
-
-	return 0, fmt.Errorf("unknown consensus value (%s)", value)
-}
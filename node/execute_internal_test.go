@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/stretchr/testify/require"
 
 	"github.com/blocklessnetwork/b7s/host"
@@ -16,6 +17,8 @@ import (
 	"github.com/blocklessnetwork/b7s/models/execute"
 	"github.com/blocklessnetwork/b7s/models/request"
 	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/node/internal/resultcache"
+	"github.com/blocklessnetwork/b7s/node/internal/workqueue"
 	"github.com/blocklessnetwork/b7s/testing/mocks"
 )
 
@@ -88,6 +91,17 @@ func TestNode_WorkerExecute(t *testing.T) {
 			require.Equal(t, expected.Code, received.Code)
 
 			require.Equal(t, expected.Result, received.Results[node.host.ID()].Result.Result)
+
+			// Worker reports the checksum of the output and how long the execution took.
+			require.NoError(t, received.Results[node.host.ID()].VerifyChecksum())
+			require.NotNil(t, received.Results[node.host.ID()].Checksum)
+			require.NotNil(t, received.Results[node.host.ID()].Timing)
+
+			// Worker adds itself to the request provenance.
+			provenance := received.Results[node.host.ID()].Provenance
+			require.Len(t, provenance, 1)
+			require.Equal(t, node.host.ID(), provenance[0].Peer)
+			require.NoError(t, provenance.Verify(node.host.ID(), outRequestID, executionRequest.Request))
 		})
 
 		err = node.processExecute(context.Background(), receiver.ID(), executionRequest)
@@ -218,6 +232,267 @@ func TestNode_WorkerExecute(t *testing.T) {
 
 		wg.Wait()
 	})
+	t.Run("skips execution past the deadline", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.WorkerNode)
+
+		executor := mocks.BaselineExecutor(t)
+		executor.ExecFunctionFunc = func(context.Context, string, execute.Request) (execute.Result, error) {
+			require.FailNow(t, "expired execution request should not be executed")
+			return execute.Result{}, nil
+		}
+		node.executor = executor
+
+		// Create a host that will serve as a receiver of the execution response.
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, receiver)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer wg.Done()
+			defer stream.Close()
+
+			var received response.Execute
+			getStreamPayload(t, stream, &received)
+
+			require.Equal(t, requestID, received.RequestID)
+			require.Equal(t, codes.Timeout, received.Code)
+			require.Empty(t, received.Results)
+		})
+
+		req := executionRequest
+		req.Remaining = -time.Second
+
+		err = node.processExecute(context.Background(), receiver.ID(), req)
+		require.NoError(t, err)
+
+		wg.Wait()
+	})
+	t.Run("execution context follows the deadline", func(t *testing.T) {
+		t.Parallel()
+
+		const remaining = time.Minute
+		sent := time.Now()
+
+		node := createNode(t, blockless.WorkerNode)
+
+		executor := mocks.BaselineExecutor(t)
+		executor.ExecFunctionFunc = func(ctx context.Context, _ string, _ execute.Request) (execute.Result, error) {
+			// Deadline is relative to when the worker received the request.
+			d, ok := ctx.Deadline()
+			require.True(t, ok)
+			require.WithinRange(t, d, sent.Add(remaining), time.Now().Add(remaining))
+
+			return mocks.GenericExecutionResult, nil
+		}
+		node.executor = executor
+
+		// Create a host that will serve as a receiver of the execution response.
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, receiver)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer wg.Done()
+			defer stream.Close()
+
+			var received response.Execute
+			getStreamPayload(t, stream, &received)
+
+			require.Equal(t, mocks.GenericExecutionResult.Code, received.Code)
+		})
+
+		req := executionRequest
+		req.Remaining = remaining
+
+		err = node.processExecute(context.Background(), receiver.ID(), req)
+		require.NoError(t, err)
+
+		wg.Wait()
+	})
+	t.Run("turns down execution with a full work queue", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.WorkerNode)
+
+		executor := mocks.BaselineExecutor(t)
+		executor.ExecFunctionFunc = func(context.Context, string, execute.Request) (execute.Result, error) {
+			require.FailNow(t, "execution request should not be executed with a full work queue")
+			return execute.Result{}, nil
+		}
+		node.executor = executor
+
+		// Take the only processing slot, with no room for waiting requests.
+		node.work = workqueue.New(1, 0)
+		require.NoError(t, node.work.Acquire(context.Background(), 0))
+
+		// Create a host that will serve as a receiver of the execution response.
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, receiver)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer wg.Done()
+			defer stream.Close()
+
+			var received response.Execute
+			getStreamPayload(t, stream, &received)
+
+			require.Equal(t, requestID, received.RequestID)
+			require.Equal(t, codes.Overloaded, received.Code)
+			require.Empty(t, received.Results)
+		})
+
+		err = node.processExecute(context.Background(), receiver.ID(), executionRequest)
+		require.NoError(t, err)
+
+		wg.Wait()
+	})
+	t.Run("turns down execution of a tenant with too many executions in flight", func(t *testing.T) {
+		t.Parallel()
+
+		const tenant = "dummy-tenant"
+
+		node := createNode(t, blockless.WorkerNode)
+
+		executor := mocks.BaselineExecutor(t)
+		executor.ExecFunctionFunc = func(context.Context, string, execute.Request) (execute.Result, error) {
+			require.FailNow(t, "execution request should not be executed over the tenant limit")
+			return execute.Result{}, nil
+		}
+		node.executor = executor
+
+		// Take the only slot of the tenant, with no room for waiting requests. Worker itself has room to spare.
+		node.tenantLimits = newClientConcurrency[string](1, 0)
+		ok, err := node.acquireTenantSlot(context.Background(), tenant, 0)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		// Create a host that will serve as a receiver of the execution response.
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, receiver)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer wg.Done()
+			defer stream.Close()
+
+			var received response.Execute
+			getStreamPayload(t, stream, &received)
+
+			require.Equal(t, requestID, received.RequestID)
+			require.Equal(t, codes.Overloaded, received.Code)
+			require.Empty(t, received.Results)
+		})
+
+		req := executionRequest
+		req.Config.Tenant = tenant
+
+		err = node.processExecute(context.Background(), receiver.ID(), req)
+		require.NoError(t, err)
+
+		wg.Wait()
+	})
+	t.Run("turns down execution of a function not permitted on the worker", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.WorkerNode)
+		node.cfg.DeniedFunctions = []string{functionID}
+
+		executor := mocks.BaselineExecutor(t)
+		executor.ExecFunctionFunc = func(context.Context, string, execute.Request) (execute.Result, error) {
+			require.FailNow(t, "execution request should not be executed for a denied function")
+			return execute.Result{}, nil
+		}
+		node.executor = executor
+
+		// Create a host that will serve as a receiver of the execution response.
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, receiver)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer wg.Done()
+			defer stream.Close()
+
+			var received response.Execute
+			getStreamPayload(t, stream, &received)
+
+			require.Equal(t, requestID, received.RequestID)
+			require.Equal(t, codes.NotPermitted, received.Code)
+			require.Empty(t, received.Results)
+		})
+
+		err = node.processExecute(context.Background(), receiver.ID(), executionRequest)
+		require.NoError(t, err)
+
+		wg.Wait()
+	})
+	t.Run("serves identical executions from the result cache", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.WorkerNode)
+		node.resultCache = resultcache.New(time.Minute, 0)
+
+		result := mocks.GenericExecutionResult
+		result.Code = codes.OK
+
+		executions := 0
+		executor := mocks.BaselineExecutor(t)
+		executor.ExecFunctionFunc = func(context.Context, string, execute.Request) (execute.Result, error) {
+			executions++
+			return result, nil
+		}
+		node.executor = executor
+
+		// Create a host that will serve as a receiver of the execution response.
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, receiver)
+
+		responses := make(chan response.Execute, 2)
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer stream.Close()
+
+			var received response.Execute
+			getStreamPayload(t, stream, &received)
+			responses <- received
+		})
+
+		for _, cached := range []bool{false, true} {
+			err = node.processExecute(context.Background(), receiver.ID(), executionRequest)
+			require.NoError(t, err)
+
+			received := <-responses
+			require.Equal(t, codes.OK, received.Code)
+			require.Equal(t, result.Result, received.Results[node.host.ID()].Result.Result)
+			require.Equal(t, cached, received.Results[node.host.ID()].Cached)
+		}
+
+		require.Equal(t, 1, executions)
+	})
 }
 
 func TestNode_HeadExecute(t *testing.T) {
@@ -267,6 +542,10 @@ func TestNode_HeadExecute(t *testing.T) {
 			getStreamPayload(t, stream, &received)
 
 			require.Equal(t, codes.Timeout, received.Code)
+
+			// Response shows the time was spent waiting for the roll call.
+			require.NotNil(t, received.Timing)
+			require.GreaterOrEqual(t, received.Timing.RollCall, node.cfg.RollCallTimeout.Milliseconds())
 		})
 
 		// Since no one will respond to a roll call, this is bound to time out.
@@ -483,3 +762,159 @@ func TestNode_DetermineOverallCode(t *testing.T) {
 		require.Equal(t, codes.Error, determineOverallCode(results))
 	})
 }
+
+func TestNode_ExecuteResponseChecksum(t *testing.T) {
+
+	const (
+		requestID = "dummy-request-id"
+	)
+
+	var (
+		worker = mocks.GenericPeerIDs[0]
+		result = execute.NodeResult{
+			Result: execute.Result{
+				Code: codes.OK,
+				Result: execute.RuntimeOutput{
+					Stdout: "dummy-execution-result",
+				},
+			},
+		}
+	)
+
+	t.Run("result with valid checksum is accepted", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+
+		valid := result
+		valid.SetChecksum()
+
+		res := response.Execute{
+			RequestID: requestID,
+			Code:      codes.OK,
+			Results:   execute.ResultMap{worker: valid},
+		}
+
+		err := node.processExecuteResponse(context.Background(), worker, res)
+		require.NoError(t, err)
+
+		received, ok := node.executeResponses.Get(executionResultKey(requestID, worker))
+		require.True(t, ok)
+		require.Equal(t, valid, received[worker])
+	})
+	t.Run("result with invalid checksum is dropped", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+
+		corrupted := result
+		corrupted.SetChecksum()
+		corrupted.Result.Result.Stdout = "corrupted-execution-result"
+
+		res := response.Execute{
+			RequestID: requestID,
+			Code:      codes.OK,
+			Results:   execute.ResultMap{worker: corrupted},
+		}
+
+		err := node.processExecuteResponse(context.Background(), worker, res)
+		require.NoError(t, err)
+
+		received, ok := node.executeResponses.Get(executionResultKey(requestID, worker))
+		require.True(t, ok)
+		require.NotContains(t, received, worker)
+	})
+}
+
+func TestNode_ExecuteResponseWatermark(t *testing.T) {
+
+	const (
+		requestID = "dummy-request-id"
+		watermark = "dummy-tenant-watermark"
+	)
+
+	var (
+		worker = mocks.GenericPeerIDs[0]
+		result = execute.NodeResult{
+			Result: execute.Result{
+				Code: codes.OK,
+				Result: execute.RuntimeOutput{
+					Stdout: "dummy-execution-result",
+				},
+			},
+		}
+	)
+
+	node := createNode(t, blockless.HeadNode)
+	node.executions.start(requestID, "dummy-function-id", 0, mocks.GenericPeerIDs[:3], nil, watermark)
+
+	stamped := result
+	stamped.Watermark = execute.WatermarkTag(watermark, requestID)
+	stamped.SetChecksum()
+
+	// Result of a different tenant's request.
+	foreign := result
+	foreign.Watermark = execute.WatermarkTag("another-tenant-watermark", requestID)
+	foreign.SetChecksum()
+
+	// Result of a different request of the same tenant.
+	replayed := result
+	replayed.Watermark = execute.WatermarkTag(watermark, "another-request-id")
+	replayed.SetChecksum()
+
+	for sender, res := range map[peer.ID]execute.NodeResult{
+		worker:                  stamped,
+		mocks.GenericPeerIDs[1]: foreign,
+		mocks.GenericPeerIDs[2]: replayed,
+	} {
+		msg := response.Execute{
+			RequestID: requestID,
+			Code:      codes.OK,
+			Results:   execute.ResultMap{sender: res},
+		}
+
+		err := node.processExecuteResponse(context.Background(), sender, msg)
+		require.NoError(t, err)
+	}
+
+	received, ok := node.executeResponses.Get(executionResultKey(requestID, worker))
+	require.True(t, ok)
+	require.Equal(t, stamped, received[worker])
+
+	for _, sender := range mocks.GenericPeerIDs[1:3] {
+		received, ok := node.executeResponses.Get(executionResultKey(requestID, sender))
+		require.True(t, ok)
+		require.NotContains(t, received, sender)
+	}
+}
+
+func TestNode_WorkerTiming(t *testing.T) {
+
+	t.Run("execution time is taken from resource usage", func(t *testing.T) {
+
+		received := time.Now().Add(-3 * time.Second)
+		timing := workerTiming(received, execute.Usage{WallClockTime: time.Second})
+
+		require.Equal(t, int64(1000), timing.Execution)
+		require.GreaterOrEqual(t, timing.QueueWait, int64(2000))
+	})
+	t.Run("without resource usage, all time is execution time", func(t *testing.T) {
+
+		received := time.Now().Add(-time.Second)
+		timing := workerTiming(received, execute.Usage{})
+
+		require.GreaterOrEqual(t, timing.Execution, int64(1000))
+		require.Zero(t, timing.QueueWait)
+	})
+}
+
+func TestRemainingTime(t *testing.T) {
+
+	remaining := remainingTime(time.Now().Add(time.Minute))
+	require.Greater(t, remaining, 59*time.Second)
+	require.LessOrEqual(t, remaining, time.Minute)
+
+	// Zero means no limit, so a deadline that passed must not be sent as zero.
+	require.Negative(t, remainingTime(time.Now().Add(-time.Second)))
+	require.Negative(t, remainingTime(time.Now()))
+}
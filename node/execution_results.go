@@ -3,28 +3,32 @@ package node
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sync"
 
 	"github.com/libp2p/go-libp2p/core/peer"
 
-	"github.com/blocklessnetwork/b7s/consensus/pbft"
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/models/codes"
 	"github.com/blocklessnetwork/b7s/models/execute"
 )
 
-// gatherExecutionResultsPBFT collects execution results from a PBFT cluster. This means f+1 identical results.
-func (n *Node) gatherExecutionResultsPBFT(ctx context.Context, requestID string, peers []peer.ID) execute.ResultMap {
+// gatherExecutionResultsBFT collects execution results from a PBFT or HotStuff cluster. This means f+1 identical results.
+func (n *Node) gatherExecutionResultsBFT(ctx context.Context, requestID string, peers []peer.ID, consensusAlgo consensus.Type) execute.ResultMap {
 
 	exctx, exCancel := context.WithTimeout(ctx, n.cfg.ExecutionTimeout)
 	defer exCancel()
 
 	type aggregatedResult struct {
 		result   execute.Result
+		checksum *execute.Checksum
 		peers    []peer.ID
 		metadata map[peer.ID]any
+		timing   map[peer.ID]*execute.Timing
 	}
 
 	var (
-		count = pbft.MinClusterResults(uint(len(peers)))
+		count = minClusterResults(uint(len(peers)))
 		lock  sync.Mutex
 		wg    sync.WaitGroup
 
@@ -35,7 +39,12 @@ func (n *Node) gatherExecutionResultsPBFT(ctx context.Context, requestID string,
 	// We use a map as a simple way to count identical results.
 	// Equality means same result (process outputs) and same request timestamp.
 	peerResultMapKey := func(res execute.NodeResult) string {
-		return fmt.Sprintf("%+#v-%s", res.Result.Result, res.PBFT.RequestTimestamp.String())
+		timestamp := res.PBFT.RequestTimestamp
+		if consensusAlgo == consensus.HotStuff {
+			timestamp = res.HotStuff.RequestTimestamp
+		}
+
+		return fmt.Sprintf("%s-%s", res.OutputChecksum(), timestamp.String())
 	}
 
 	wg.Add(len(peers))
@@ -44,8 +53,12 @@ func (n *Node) gatherExecutionResultsPBFT(ctx context.Context, requestID string,
 		go func(sender peer.ID) {
 			defer wg.Done()
 
+			// Do not wait for peers that disconnect.
+			pctx, cancel := n.peerContext(exctx, sender)
+			defer cancel()
+
 			key := executionResultKey(requestID, sender)
-			res, ok := n.executeResponses.WaitFor(exctx, key)
+			res, ok := n.executeResponses.WaitFor(pctx, key)
 			if !ok {
 				return
 			}
@@ -76,20 +89,25 @@ func (n *Node) gatherExecutionResultsPBFT(ctx context.Context, requestID string,
 			result, ok := results[reskey]
 			if !ok {
 				results[reskey] = aggregatedResult{
-					result: er.Result,
+					result:   er.Result,
+					checksum: er.Checksum,
 					peers: []peer.ID{
 						sender,
 					},
 					metadata: map[peer.ID]any{
 						sender: er.Metadata,
 					},
+					timing: map[peer.ID]*execute.Timing{
+						sender: er.Timing,
+					},
 				}
 				return
 			}
 
-			// Record which peers have this result, and their metadata.
+			// Record which peers have this result, their metadata and timing.
 			result.peers = append(result.peers, sender)
 			result.metadata[sender] = er.Metadata
+			result.timing[sender] = er.Timing
 
 			results[reskey] = result
 
@@ -100,7 +118,9 @@ func (n *Node) gatherExecutionResultsPBFT(ctx context.Context, requestID string,
 				for _, peer := range result.peers {
 					out[peer] = execute.NodeResult{
 						Result:   result.result,
+						Checksum: result.checksum,
 						Metadata: result.metadata[peer],
+						Timing:   result.timing[peer],
 					}
 				}
 			}
@@ -112,8 +132,9 @@ func (n *Node) gatherExecutionResultsPBFT(ctx context.Context, requestID string,
 	return out
 }
 
-// gatherExecutionResults collects execution results from direct executions or raft clusters.
-func (n *Node) gatherExecutionResults(ctx context.Context, requestID string, peers []peer.ID) execute.ResultMap {
+// gatherExecutionResults collects execution results from direct executions or raft clusters. Peers that disconnect
+// before sending their result are not waited for - they are returned along with the results.
+func (n *Node) gatherExecutionResults(ctx context.Context, requestID string, peers []peer.ID) (execute.ResultMap, []peer.ID) {
 
 	// We're willing to wait for a limited amount of time.
 	exctx, exCancel := context.WithTimeout(ctx, n.cfg.ExecutionTimeout)
@@ -121,6 +142,7 @@ func (n *Node) gatherExecutionResults(ctx context.Context, requestID string, pee
 
 	var (
 		results execute.ResultMap = make(map[peer.ID]execute.NodeResult)
+		dropped []peer.ID
 		reslock sync.Mutex
 		wg      sync.WaitGroup
 	)
@@ -133,9 +155,20 @@ func (n *Node) gatherExecutionResults(ctx context.Context, requestID string, pee
 
 		go func() {
 			defer wg.Done()
+
+			pctx, cancel := n.peerContext(exctx, rp)
+			defer cancel()
+
 			key := executionResultKey(requestID, rp)
-			res, ok := n.executeResponses.WaitFor(exctx, key)
+			res, ok := n.executeResponses.WaitFor(pctx, key)
 			if !ok {
+				if peerDisconnected(pctx) {
+					n.log.Info().Str("peer", rp.String()).Str("request", requestID).Msg("peer disconnected before sending execution response")
+
+					reslock.Lock()
+					defer reslock.Unlock()
+					dropped = append(dropped, rp)
+				}
 				return
 			}
 
@@ -154,7 +187,7 @@ func (n *Node) gatherExecutionResults(ctx context.Context, requestID string, pee
 
 	wg.Wait()
 
-	return results
+	return results, dropped
 }
 
 func singleNodeResultMap(id peer.ID, res execute.NodeResult) execute.ResultMap {
@@ -162,3 +195,121 @@ func singleNodeResultMap(id peer.ID, res execute.NodeResult) execute.ResultMap {
 		id: res,
 	}
 }
+
+// gatherExecutionResultsQuorum collects execution results from direct executions until the quorum of identical results is reached.
+// If the quorum is reached, only the matching results are returned. Otherwise, all collected results are returned.
+func (n *Node) gatherExecutionResultsQuorum(ctx context.Context, requestID string, peers []peer.ID, quorum uint) (execute.ResultMap, bool) {
+
+	exctx, exCancel := context.WithTimeout(ctx, n.cfg.ExecutionTimeout)
+	defer exCancel()
+
+	var (
+		lock sync.Mutex
+		wg   sync.WaitGroup
+
+		// Peers that reported identical results, by output checksum.
+		matching = make(map[string][]peer.ID)
+		results  = make(execute.ResultMap)
+		quorumOf string
+	)
+
+	wg.Add(len(peers))
+
+	for _, rp := range peers {
+		go func(sender peer.ID) {
+			defer wg.Done()
+
+			key := executionResultKey(requestID, sender)
+			res, ok := n.executeResponses.WaitFor(exctx, key)
+			if !ok {
+				return
+			}
+
+			n.log.Info().Str("peer", sender.String()).Str("request", requestID).Msg("accounted execution response from peer")
+
+			er, ok := res[sender]
+			if !ok {
+				return
+			}
+
+			lock.Lock()
+			defer lock.Unlock()
+
+			results[sender] = er
+
+			// Only successful executions count towards the quorum.
+			if er.Code != codes.OK || quorumOf != "" {
+				return
+			}
+
+			checksum := er.OutputChecksum()
+			matching[checksum] = append(matching[checksum], sender)
+
+			if uint(len(matching[checksum])) >= quorum {
+				n.log.Info().Str("request", requestID).Int("peers", len(peers)).Uint("quorum", quorum).Msg("quorum reached")
+				quorumOf = checksum
+				exCancel()
+			}
+		}(rp)
+	}
+
+	wg.Wait()
+
+	if quorumOf == "" {
+		return results, false
+	}
+
+	out := make(execute.ResultMap, quorum)
+	for _, peer := range matching[quorumOf] {
+		out[peer] = results[peer]
+	}
+
+	return out, true
+}
+
+// verifyExecutionResults compares output checksums of all results. If at least `quorum` successful results are identical,
+// they are returned as the verified results. Peers whose results differ from the most common successful result are
+// returned as mismatched, whether the quorum was reached or not.
+func verifyExecutionResults(results execute.ResultMap, quorum uint) (execute.ResultMap, []peer.ID) {
+
+	matching := make(map[string][]peer.ID)
+	for executingPeer, res := range results {
+		if res.Code != codes.OK {
+			continue
+		}
+
+		checksum := res.OutputChecksum()
+		matching[checksum] = append(matching[checksum], executingPeer)
+	}
+
+	// Find the most common result. Ties are broken by checksum so the choice is deterministic.
+	var (
+		best      string
+		bestPeers []peer.ID
+	)
+	for checksum, peers := range matching {
+		if len(peers) > len(bestPeers) || (len(peers) == len(bestPeers) && checksum < best) {
+			best = checksum
+			bestPeers = peers
+		}
+	}
+
+	mismatched := make([]peer.ID, 0)
+	for executingPeer := range results {
+		if !slices.Contains(bestPeers, executingPeer) {
+			mismatched = append(mismatched, executingPeer)
+		}
+	}
+	slices.Sort(mismatched)
+
+	if len(bestPeers) == 0 || uint(len(bestPeers)) < quorum {
+		return nil, mismatched
+	}
+
+	verified := make(execute.ResultMap, len(bestPeers))
+	for _, executingPeer := range bestPeers {
+		verified[executingPeer] = results[executingPeer]
+	}
+
+	return verified, mismatched
+}
@@ -0,0 +1,130 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_GatherExecutionResultsQuorum(t *testing.T) {
+
+	const (
+		requestID = "dummy-request-id"
+	)
+
+	var (
+		peers    = mocks.GenericPeerIDs[:5]
+		majority = execute.RuntimeOutput{Stdout: "majority"}
+		minority = execute.RuntimeOutput{Stdout: "minority"}
+	)
+
+	setResult := func(n *Node, peer peer.ID, code codes.Code, output execute.RuntimeOutput) {
+		res := execute.NodeResult{
+			Result: execute.Result{
+				Code:   code,
+				Result: output,
+			},
+		}
+		n.executeResponses.Set(executionResultKey(requestID, peer), singleNodeResultMap(peer, res))
+	}
+
+	t.Run("quorum reached", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+
+		setResult(node, peers[0], codes.OK, majority)
+		setResult(node, peers[1], codes.OK, minority)
+		setResult(node, peers[2], codes.OK, majority)
+		setResult(node, peers[3], codes.OK, majority)
+
+		// Last peer never responds, but we should not need to wait for it.
+		start := time.Now()
+		results, ok := node.gatherExecutionResultsQuorum(context.Background(), requestID, peers, 3)
+		require.True(t, ok)
+		require.Less(t, time.Since(start), node.cfg.ExecutionTimeout)
+
+		require.Len(t, results, 3)
+		for _, peer := range []peer.ID{peers[0], peers[2], peers[3]} {
+			require.Contains(t, results, peer)
+			require.Equal(t, majority, results[peer].Result.Result)
+		}
+	})
+	t.Run("failed executions do not count towards quorum", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+		node.cfg.ExecutionTimeout = 100 * time.Millisecond
+
+		setResult(node, peers[0], codes.OK, majority)
+		setResult(node, peers[1], codes.Error, majority)
+		setResult(node, peers[2], codes.InvalidOutput, majority)
+		setResult(node, peers[3], codes.OK, minority)
+
+		results, ok := node.gatherExecutionResultsQuorum(context.Background(), requestID, peers, 2)
+		require.False(t, ok)
+		require.Len(t, results, 4)
+	})
+}
+
+func TestNode_VerifyExecutionResults(t *testing.T) {
+
+	var (
+		peers    = mocks.GenericPeerIDs[:4]
+		majority = execute.RuntimeOutput{Stdout: "majority"}
+		minority = execute.RuntimeOutput{Stdout: "minority"}
+	)
+
+	result := func(code codes.Code, output execute.RuntimeOutput) execute.NodeResult {
+		return execute.NodeResult{
+			Result: execute.Result{
+				Code:   code,
+				Result: output,
+			},
+		}
+	}
+
+	results := execute.ResultMap{
+		peers[0]: result(codes.OK, majority),
+		peers[1]: result(codes.OK, minority),
+		peers[2]: result(codes.OK, majority),
+		peers[3]: result(codes.Error, majority),
+	}
+
+	t.Run("quorum of identical results", func(t *testing.T) {
+		t.Parallel()
+
+		verified, mismatched := verifyExecutionResults(results, 2)
+		require.Len(t, verified, 2)
+		require.Contains(t, verified, peers[0])
+		require.Contains(t, verified, peers[2])
+
+		require.ElementsMatch(t, []peer.ID{peers[1], peers[3]}, mismatched)
+	})
+	t.Run("quorum not reached", func(t *testing.T) {
+		t.Parallel()
+
+		verified, mismatched := verifyExecutionResults(results, 3)
+		require.Nil(t, verified)
+		require.ElementsMatch(t, []peer.ID{peers[1], peers[3]}, mismatched)
+	})
+	t.Run("no successful results", func(t *testing.T) {
+		t.Parallel()
+
+		failed := execute.ResultMap{
+			peers[0]: result(codes.Error, majority),
+		}
+
+		verified, mismatched := verifyExecutionResults(failed, 1)
+		require.Nil(t, verified)
+		require.Equal(t, []peer.ID{peers[0]}, mismatched)
+	})
+}
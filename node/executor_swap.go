@@ -0,0 +1,280 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/rs/zerolog"
+
+	"github.com/blocklessnetwork/b7s/b7serrors"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/request"
+)
+
+var (
+	errExecutorSwapNotSupported = errors.New("executor swap not supported")
+	errExecutorSwapInProgress   = errors.New("executor swap already in progress")
+	errExecutorDrainTimeout     = errors.New("in-flight executions did not finish in time")
+	errCanaryFailed             = errors.New("canary execution failed")
+)
+
+// swappableExecutor forwards executions to the current executor, and allows replacing it at runtime.
+type swappableExecutor struct {
+	// Lock guards the current executor and the swap state. It is never held while executions run.
+	sync.Mutex
+	current blockless.Executor
+	running uint // Executions using the current executor.
+
+	// Set while a swap is in progress. New executions wait for `swapped` to be closed,
+	// and `drained` is closed once the executions already running finish.
+	swapped chan struct{}
+	drained chan struct{}
+
+	// active counts executions in progress.
+	active atomic.Int64
+}
+
+func newSwappableExecutor(executor blockless.Executor) *swappableExecutor {
+	return &swappableExecutor{
+		current: executor,
+	}
+}
+
+// ExecuteFunction executes the function using the current executor.
+func (s *swappableExecutor) ExecuteFunction(ctx context.Context, requestID string, req execute.Request) (execute.Result, error) {
+
+	s.active.Add(1)
+	defer s.active.Add(-1)
+
+	executor, err := s.acquire(ctx)
+	if err != nil {
+		return execute.Result{Code: codes.Error}, err
+	}
+	defer s.release()
+
+	return executor.ExecuteFunction(ctx, requestID, req)
+}
+
+// ExecuteInteractive executes the function interactively using the current executor, if it supports interactive executions.
+func (s *swappableExecutor) ExecuteInteractive(ctx context.Context, requestID string, req execute.Request, stdin io.Reader, stdout io.Writer) (execute.Result, error) {
+
+	s.active.Add(1)
+	defer s.active.Add(-1)
+
+	executor, err := s.acquire(ctx)
+	if err != nil {
+		return execute.Result{Code: codes.Error}, err
+	}
+	defer s.release()
+
+	interactive, ok := executor.(blockless.InteractiveExecutor)
+	if !ok {
+		return execute.Result{Code: codes.NotSupported}, errInteractiveNotSupported
+	}
+
+	return interactive.ExecuteInteractive(ctx, requestID, req, stdin, stdout)
+}
+
+// acquire returns the current executor, waiting for an executor swap in progress to finish first.
+// Release should be called once the execution is done.
+func (s *swappableExecutor) acquire(ctx context.Context) (blockless.Executor, error) {
+
+	for {
+		s.Lock()
+		swapped := s.swapped
+		if swapped == nil {
+			s.running++
+			executor := s.current
+			s.Unlock()
+
+			return executor, nil
+		}
+		s.Unlock()
+
+		select {
+		case <-swapped:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("could not wait for executor swap: %w", ctx.Err())
+		}
+	}
+}
+
+// release records that the execution using the executor is done.
+func (s *swappableExecutor) release() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.running--
+	if s.running == 0 && s.drained != nil {
+		close(s.drained)
+		s.drained = nil
+	}
+}
+
+// inFlight returns the number of executions in progress, including the ones waiting for an executor swap to finish.
+func (s *swappableExecutor) inFlight() uint {
+	return uint(max(s.active.Load(), 0))
+}
+
+// swap stops admitting new executions, waits for in-flight executions to finish and runs the canary request using the new executor.
+// If the canary execution succeeds, the new executor becomes the current one and the previous executor is returned.
+// Otherwise, or if in-flight executions do not finish before the context is done, the current executor is kept.
+func (s *swappableExecutor) swap(ctx context.Context, next blockless.Executor, requestID string, canary execute.Request) (blockless.Executor, error) {
+
+	s.Lock()
+	if s.swapped != nil {
+		s.Unlock()
+		return nil, errExecutorSwapInProgress
+	}
+
+	swapped := make(chan struct{})
+	drained := make(chan struct{})
+	s.swapped = swapped
+	if s.running == 0 {
+		close(drained)
+	} else {
+		s.drained = drained
+	}
+	s.Unlock()
+
+	// Whatever the outcome, let the executions held back continue.
+	defer func() {
+		s.Lock()
+		s.swapped = nil
+		s.drained = nil
+		s.Unlock()
+
+		close(swapped)
+	}()
+
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%w: %w", errExecutorDrainTimeout, ctx.Err())
+	}
+
+	res, err := next.ExecuteFunction(ctx, requestID, canary)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errCanaryFailed, err)
+	}
+	if res.Code != codes.OK {
+		return nil, fmt.Errorf("%w (code: %s)", errCanaryFailed, res.Code)
+	}
+
+	s.Lock()
+	previous := s.current
+	s.current = next
+	s.Unlock()
+
+	return previous, nil
+}
+
+// SwapExecutor switches the worker to an executor using the runtime found at the given path. In-flight executions
+// are drained first, and the new executor is validated by executing the canary request. If the canary execution
+// fails, the worker keeps using the current executor.
+func (n *Node) SwapExecutor(ctx context.Context, runtimePath string, runtimeCLI string, canary execute.Request) error {
+
+	if n.executors == nil || n.cfg.ExecutorFactory == nil {
+		return errExecutorSwapNotSupported
+	}
+
+	installed, err := n.fstore.IsInstalled(canary.FunctionID)
+	if err != nil {
+		return fmt.Errorf("could not lookup canary function in store: %w", err)
+	}
+	if !installed {
+		return fmt.Errorf("canary function not installed (function: %s): %w", canary.FunctionID, b7serrors.ErrNotFound)
+	}
+
+	next, err := n.cfg.ExecutorFactory(runtimePath, runtimeCLI)
+	if err != nil {
+		return fmt.Errorf("could not create executor: %w", err)
+	}
+
+	log := n.log.With().Str("runtime_path", runtimePath).Str("runtime_cli", runtimeCLI).Logger()
+
+	log.Info().Msg("draining executions before executor swap")
+
+	ctx, cancel := context.WithTimeout(ctx, executorCanaryTimeout)
+	defer cancel()
+
+	previous, err := n.executors.swap(ctx, next, n.newRequestID(), canary)
+	if err != nil {
+		n.metrics.IncrCounter(executorSwapRollbacksMetric, 1)
+		shutdownExecutor(log, next)
+		return err
+	}
+
+	n.metrics.IncrCounter(executorSwapsMetric, 1)
+	shutdownExecutor(log, previous)
+
+	log.Info().Msg("executor swapped")
+
+	return nil
+}
+
+func (n *Node) processSwapExecutor(ctx context.Context, from peer.ID, req request.SwapExecutor) error {
+
+	log := n.log.With().Str("peer", from.String()).Str("runtime_path", req.RuntimePath).Logger()
+
+	if !slices.Contains(n.cfg.AdminPeers, from) {
+		log.Warn().Msg("rejecting executor swap from peer that is not an admin")
+
+		err := n.send(ctx, from, req.Response(codes.NotPermitted))
+		if err != nil {
+			return fmt.Errorf("could not send response: %w", err)
+		}
+
+		return nil
+	}
+
+	res := req.Response(codes.OK)
+
+	err := n.SwapExecutor(ctx, req.RuntimePath, req.RuntimeCLI, req.Canary)
+	if err != nil {
+		log.Error().Err(err).Msg("executor swap failed")
+
+		code := codes.Error
+		switch {
+		case errors.Is(err, errExecutorSwapNotSupported):
+			code = codes.NotSupported
+		case errors.Is(err, b7serrors.ErrNotFound):
+			code = codes.NotFound
+		}
+
+		res = req.Response(code).WithErrorMessage(err)
+	}
+
+	err = n.send(ctx, from, res)
+	if err != nil {
+		return fmt.Errorf("could not send response: %w", err)
+	}
+
+	return nil
+}
+
+// shutdownExecutor stops the runtime processes the executor keeps, if any.
+func shutdownExecutor(log zerolog.Logger, executor blockless.Executor) {
+
+	type shutdowner interface {
+		Shutdown() error
+	}
+
+	s, ok := executor.(shutdowner)
+	if !ok {
+		return
+	}
+
+	err := s.Shutdown()
+	if err != nil {
+		log.Warn().Err(err).Msg("could not shutdown executor")
+	}
+}
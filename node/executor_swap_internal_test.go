@@ -0,0 +1,261 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_SwapExecutor(t *testing.T) {
+
+	const (
+		runtimePath = "/tmp/runtime/v2"
+		runtimeCLI  = "bls-runtime"
+	)
+
+	executorWithOutput := func(t *testing.T, stdout string) *mocks.Executor {
+		executor := mocks.BaselineExecutor(t)
+		executor.ExecFunctionFunc = func(context.Context, string, execute.Request) (execute.Result, error) {
+			return execute.Result{Code: codes.OK, Result: execute.RuntimeOutput{Stdout: stdout}}, nil
+		}
+		return executor
+	}
+
+	t.Run("executions use the new executor after swap", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.WorkerNode)
+		node.cfg.ExecutorFactory = func(path string, cli string) (blockless.Executor, error) {
+			require.Equal(t, runtimePath, path)
+			require.Equal(t, runtimeCLI, cli)
+			return executorWithOutput(t, "new"), nil
+		}
+
+		err := node.SwapExecutor(context.Background(), runtimePath, runtimeCLI, mocks.GenericExecutionRequest)
+		require.NoError(t, err)
+
+		res, err := node.executors.ExecuteFunction(context.Background(), node.newRequestID(), mocks.GenericExecutionRequest)
+		require.NoError(t, err)
+		require.Equal(t, "new", res.Result.Stdout)
+	})
+	t.Run("failed canary keeps the current executor", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.WorkerNode)
+		node.executors.current = executorWithOutput(t, "old")
+		node.cfg.ExecutorFactory = func(string, string) (blockless.Executor, error) {
+			executor := mocks.BaselineExecutor(t)
+			executor.ExecFunctionFunc = func(context.Context, string, execute.Request) (execute.Result, error) {
+				return execute.Result{Code: codes.Error}, errors.New("runtime not working")
+			}
+			return executor, nil
+		}
+
+		err := node.SwapExecutor(context.Background(), runtimePath, runtimeCLI, mocks.GenericExecutionRequest)
+		require.ErrorIs(t, err, errCanaryFailed)
+
+		res, err := node.executors.ExecuteFunction(context.Background(), node.newRequestID(), mocks.GenericExecutionRequest)
+		require.NoError(t, err)
+		require.Equal(t, "old", res.Result.Stdout)
+	})
+	t.Run("in-flight executions are drained before swap", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			started  = make(chan struct{})
+			release  = make(chan struct{})
+			swapped  = make(chan struct{})
+			executed = make(chan struct{})
+		)
+
+		old := mocks.BaselineExecutor(t)
+		old.ExecFunctionFunc = func(context.Context, string, execute.Request) (execute.Result, error) {
+			close(started)
+			<-release
+			return execute.Result{Code: codes.OK}, nil
+		}
+
+		node := createNode(t, blockless.WorkerNode)
+		node.executors.current = old
+		node.cfg.ExecutorFactory = func(string, string) (blockless.Executor, error) {
+			return executorWithOutput(t, "new"), nil
+		}
+
+		go func() {
+			defer close(executed)
+			_, err := node.executors.ExecuteFunction(context.Background(), node.newRequestID(), mocks.GenericExecutionRequest)
+			require.NoError(t, err)
+		}()
+
+		<-started
+
+		go func() {
+			defer close(swapped)
+			err := node.SwapExecutor(context.Background(), runtimePath, runtimeCLI, mocks.GenericExecutionRequest)
+			require.NoError(t, err)
+		}()
+
+		select {
+		case <-swapped:
+			t.Fatal("executor swapped while execution was in progress")
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		close(release)
+		<-executed
+		<-swapped
+
+		res, err := node.executors.ExecuteFunction(context.Background(), node.newRequestID(), mocks.GenericExecutionRequest)
+		require.NoError(t, err)
+		require.Equal(t, "new", res.Result.Stdout)
+	})
+	t.Run("swap gives up if in-flight executions do not finish in time", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			started  = make(chan struct{})
+			release  = make(chan struct{})
+			executed = make(chan struct{})
+		)
+
+		old := mocks.BaselineExecutor(t)
+		old.ExecFunctionFunc = func(_ context.Context, _ string, req execute.Request) (execute.Result, error) {
+			if req.Method == "long-running" {
+				close(started)
+				<-release
+			}
+			return execute.Result{Code: codes.OK, Result: execute.RuntimeOutput{Stdout: "old"}}, nil
+		}
+
+		node := createNode(t, blockless.WorkerNode)
+		node.executors.current = old
+		node.cfg.ExecutorFactory = func(string, string) (blockless.Executor, error) {
+			return executorWithOutput(t, "new"), nil
+		}
+
+		go func() {
+			defer close(executed)
+
+			req := mocks.GenericExecutionRequest
+			req.Method = "long-running"
+			_, err := node.executors.ExecuteFunction(context.Background(), node.newRequestID(), req)
+			require.NoError(t, err)
+		}()
+
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		err := node.SwapExecutor(ctx, runtimePath, runtimeCLI, mocks.GenericExecutionRequest)
+		require.ErrorIs(t, err, errExecutorDrainTimeout)
+
+		// Executions are admitted again and use the current executor.
+		res, err := node.executors.ExecuteFunction(context.Background(), node.newRequestID(), mocks.GenericExecutionRequest)
+		require.NoError(t, err)
+		require.Equal(t, "old", res.Result.Stdout)
+
+		close(release)
+		<-executed
+	})
+	t.Run("executions wait for the swap only as long as their context allows", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			started = make(chan struct{})
+			release = make(chan struct{})
+		)
+
+		old := mocks.BaselineExecutor(t)
+		old.ExecFunctionFunc = func(context.Context, string, execute.Request) (execute.Result, error) {
+			close(started)
+			<-release
+			return execute.Result{Code: codes.OK}, nil
+		}
+
+		node := createNode(t, blockless.WorkerNode)
+		node.executors.current = old
+		node.cfg.ExecutorFactory = func(string, string) (blockless.Executor, error) {
+			return executorWithOutput(t, "new"), nil
+		}
+
+		go node.executors.ExecuteFunction(context.Background(), node.newRequestID(), mocks.GenericExecutionRequest)
+		<-started
+		defer close(release)
+
+		go node.SwapExecutor(context.Background(), runtimePath, runtimeCLI, mocks.GenericExecutionRequest)
+
+		require.Eventually(t, func() bool {
+			node.executors.Lock()
+			defer node.executors.Unlock()
+			return node.executors.swapped != nil
+		}, time.Second, 10*time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		res, err := node.executors.ExecuteFunction(ctx, node.newRequestID(), mocks.GenericExecutionRequest)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, codes.Error, res.Code)
+	})
+	t.Run("swap requested by peer that is not an admin is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.WorkerNode)
+		node.cfg.ExecutorFactory = func(string, string) (blockless.Executor, error) {
+			require.FailNow(t, "executor should not be created")
+			return nil, nil
+		}
+
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, receiver)
+
+		var (
+			wg       sync.WaitGroup
+			received response.SwapExecutor
+		)
+		wg.Add(1)
+
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer wg.Done()
+			defer stream.Close()
+
+			getStreamPayload(t, stream, &received)
+		})
+
+		req := request.SwapExecutor{
+			RuntimePath: runtimePath,
+			Canary:      mocks.GenericExecutionRequest,
+		}
+
+		err = node.processSwapExecutor(context.Background(), receiver.ID(), req)
+		require.NoError(t, err)
+
+		wg.Wait()
+
+		require.Equal(t, codes.NotPermitted, received.Code)
+	})
+	t.Run("swap without executor factory is not supported", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.WorkerNode)
+
+		err := node.SwapExecutor(context.Background(), runtimePath, runtimeCLI, mocks.GenericExecutionRequest)
+		require.ErrorIs(t, err, errExecutorSwapNotSupported)
+	})
+}
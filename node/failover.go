@@ -0,0 +1,201 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/node/internal/dispatch"
+)
+
+var errNoExecutionResult = errors.New("peer did not send its execution result")
+
+// peerReserve holds peers that reported for roll call but were not chosen for execution.
+type peerReserve struct {
+	sync.Mutex
+	peers []peer.ID
+}
+
+func newPeerReserve(peers []peer.ID) *peerReserve {
+	return &peerReserve{
+		peers: peers,
+	}
+}
+
+// next returns the next peer from the reserve.
+func (r *peerReserve) next() (peer.ID, bool) {
+	r.Lock()
+	defer r.Unlock()
+
+	if len(r.peers) == 0 {
+		return "", false
+	}
+
+	next := r.peers[0]
+	r.peers = r.peers[1:]
+
+	return next, true
+}
+
+// gatherExecutionResultsWithFailover collects execution results from direct executions. If a peer disconnects or does not respond
+// within the worker response timeout, the execution request is sent to a peer from the reserve instead. Reserve peers only get what is left of the execution timeout, so the client does not
+// wait longer than it would without failover. Along with the results, the list of reserve peers the request was sent to is returned.
+// Peers that failed to respond are recorded in the attempt log.
+func (n *Node) gatherExecutionResultsWithFailover(ctx context.Context, req *request.Execute, peers []peer.ID, reserve *peerReserve, attempts *attemptLog) (execute.ResultMap, []peer.ID) {
+
+	// Single deadline for the execution, including any failovers.
+	exctx, exCancel := context.WithTimeout(ctx, n.cfg.ExecutionTimeout)
+	defer exCancel()
+
+	var (
+		results   execute.ResultMap = make(map[peer.ID]execute.NodeResult)
+		failovers []peer.ID
+		lock      sync.Mutex
+		wg        sync.WaitGroup
+	)
+
+	log := n.log.With().Str("request", req.RequestID).Logger()
+
+	timeout := n.workerResponseTimeout(req.Request)
+
+	wg.Add(len(peers))
+
+	for _, rp := range peers {
+		go func(current peer.ID) {
+			defer wg.Done()
+
+			for {
+				waitStart := time.Now()
+				res, err := n.waitForExecutionResult(exctx, req.RequestID, current, timeout)
+				if err == nil {
+					log.Info().Str("peer", current.String()).Msg("accounted execution response from peer")
+
+					lock.Lock()
+					defer lock.Unlock()
+					results[current] = res
+					return
+				}
+
+				if ctx.Err() != nil {
+					return
+				}
+
+				if exctx.Err() != nil {
+					log.Warn().Str("peer", current.String()).Msg("peer did not respond before the execution timeout")
+					return
+				}
+
+				backup, ok := n.nextBackupPeer(exctx, req, reserve)
+				if !ok {
+					log.Warn().Str("peer", current.String()).Msg("peer did not respond and no reserve peers are available")
+					return
+				}
+
+				attempts.fail(execute.AttemptExecution, []peer.ID{current}, codes.NotAvailable, waitStart, err.Error())
+
+				log.Info().Err(err).Str("peer", current.String()).Str("backup", backup.String()).Msg("peer did not respond, execution request sent to reserve peer")
+
+				// Peer is still connected, but we no longer wait for it.
+				if !errors.Is(err, errPeerDisconnected) {
+					err = n.abortExecution(req.RequestID, []peer.ID{current}, nil)
+					if err != nil {
+						log.Warn().Err(err).Str("peer", current.String()).Msg("could not abort execution on unresponsive peer")
+					}
+				}
+
+				n.metrics.IncrCounter(executionFailoversMetric, 1)
+
+				lock.Lock()
+				failovers = append(failovers, backup)
+				lock.Unlock()
+
+				current = backup
+			}
+		}(rp)
+	}
+
+	wg.Wait()
+
+	return results, failovers
+}
+
+// nextBackupPeer sends the execution request to the next available peer from the reserve.
+func (n *Node) nextBackupPeer(ctx context.Context, req *request.Execute, reserve *peerReserve) (peer.ID, bool) {
+
+	for {
+		// Do not take up reserve peers for an execution no one is waiting for.
+		if ctx.Err() != nil {
+			return "", false
+		}
+
+		backup, ok := reserve.next()
+		if !ok {
+			return "", false
+		}
+
+		if !n.haveConnection(backup) || !n.dispatch.Acquire(backup) {
+			continue
+		}
+
+		// Record the peer before sending it the request, so its results are accepted as soon as they arrive.
+		n.executions.addPeer(req.RequestID, backup)
+
+		err := n.send(ctx, backup, req)
+		if err != nil {
+			n.log.Warn().Err(err).Str("request", req.RequestID).Str("peer", backup.String()).Msg("could not send execution request to reserve peer")
+			n.dispatch.Release(backup, dispatch.Overload)
+			continue
+		}
+
+		return backup, true
+	}
+}
+
+// waitForExecutionResult waits for the execution result from the given peer, until the context is done or the timeout passes,
+// if set. It stops waiting early if the peer disconnects. The returned error tells why there is no result.
+func (n *Node) waitForExecutionResult(ctx context.Context, requestID string, peer peer.ID, timeout time.Duration) (execute.NodeResult, error) {
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, timeout, fmt.Errorf("peer did not respond within %v", timeout))
+		defer cancel()
+	}
+
+	exctx, pCancel := n.peerContext(ctx, peer)
+	defer pCancel()
+
+	res, ok := n.executeResponses.WaitFor(exctx, executionResultKey(requestID, peer))
+	if !ok {
+		return execute.NodeResult{}, context.Cause(exctx)
+	}
+
+	exres, ok := res[peer]
+	if !ok {
+		return execute.NodeResult{}, errNoExecutionResult
+	}
+
+	return exres, nil
+}
+
+// workerResponseTimeout returns how long we wait for a single worker to respond before failing over. Unless configured,
+// workers are given the request timeout, after which they stop the execution, and a grace period to send the result.
+// Zero means we wait for the worker until the execution timeout.
+func (n *Node) workerResponseTimeout(req execute.Request) time.Duration {
+
+	if n.cfg.WorkerResponseTimeout > 0 {
+		return n.cfg.WorkerResponseTimeout
+	}
+
+	if req.Config.Timeout > 0 {
+		return time.Duration(req.Config.Timeout)*time.Second + workerResponseGrace
+	}
+
+	return 0
+}
@@ -0,0 +1,183 @@
+package node
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_GatherExecutionResultsWithFailover(t *testing.T) {
+
+	const (
+		requestID = "dummy-request-id"
+	)
+
+	req := request.Execute{
+		RequestID: requestID,
+		Request: execute.Request{
+			FunctionID: "dummy-function-id",
+			Method:     "dummy-function-method",
+		},
+	}
+
+	result := execute.NodeResult{
+		Result: execute.Result{
+			Code: codes.OK,
+			Result: execute.RuntimeOutput{
+				Stdout: "dummy-execution-result",
+			},
+		},
+	}
+
+	t.Run("execution is sent to reserve peer", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+		node.cfg.ExecutionTimeout = time.Second
+
+		// Create a host that will serve as a reserve peer.
+		backup, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, backup)
+
+		err = node.host.Connect(context.Background(), *hostGetAddrInfo(t, backup))
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+
+		backup.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer wg.Done()
+			defer stream.Close()
+
+			var received request.Execute
+			getStreamPayload(t, stream, &received)
+			require.Equal(t, requestID, received.RequestID)
+
+			// Feign execution.
+			node.executeResponses.Set(executionResultKey(requestID, backup.ID()), singleNodeResultMap(backup.ID(), result))
+		})
+
+		// Chosen peer is not connected, so it will never respond.
+		chosen := mocks.GenericPeerIDs[0]
+
+		attempts := newAttemptLog()
+		results, failovers := node.gatherExecutionResultsWithFailover(context.Background(), &req, []peer.ID{chosen}, newPeerReserve([]peer.ID{backup.ID()}), attempts)
+		require.Equal(t, []peer.ID{backup.ID()}, failovers)
+
+		wg.Wait()
+		require.Len(t, results, 1)
+		require.Equal(t, result, results[backup.ID()])
+
+		// Failed attempt is reported, followed by the attempt that produced the result.
+		history := attempts.history(nil, execute.AttemptExecution, resultPeers(results, nil), codes.OK, nil)
+		require.Len(t, history, 2)
+		require.Equal(t, []peer.ID{chosen}, history[0].Peers)
+		require.Equal(t, codes.NotAvailable, history[0].Code)
+		require.Equal(t, errPeerDisconnected.Error(), history[0].Reason)
+		require.Equal(t, []peer.ID{backup.ID()}, history[1].Peers)
+		require.Equal(t, codes.OK, history[1].Code)
+	})
+	t.Run("peer not responding within the worker response timeout is replaced", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+		node.cfg.ExecutionTimeout = 5 * time.Second
+		node.cfg.WorkerResponseTimeout = 200 * time.Millisecond
+
+		// Chosen peer is connected, but never responds.
+		chosen, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, chosen)
+		err = node.host.Connect(context.Background(), *hostGetAddrInfo(t, chosen))
+		require.NoError(t, err)
+
+		backup, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, backup)
+		err = node.host.Connect(context.Background(), *hostGetAddrInfo(t, backup))
+		require.NoError(t, err)
+
+		backup.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer stream.Close()
+
+			var received request.Execute
+			getStreamPayload(t, stream, &received)
+
+			node.executeResponses.Set(executionResultKey(requestID, backup.ID()), singleNodeResultMap(backup.ID(), result))
+		})
+
+		start := time.Now()
+
+		attempts := newAttemptLog()
+		results, failovers := node.gatherExecutionResultsWithFailover(context.Background(), &req, []peer.ID{chosen.ID()}, newPeerReserve([]peer.ID{backup.ID()}), attempts)
+		require.Equal(t, []peer.ID{backup.ID()}, failovers)
+		require.Equal(t, result, results[backup.ID()])
+		require.Less(t, time.Since(start), node.cfg.ExecutionTimeout)
+
+		history := attempts.history(nil, execute.AttemptExecution, resultPeers(results, nil), codes.OK, nil)
+		require.Len(t, history, 2)
+		require.Equal(t, []peer.ID{chosen.ID()}, history[0].Peers)
+		require.Contains(t, history[0].Reason, "did not respond within")
+	})
+	t.Run("reserve peers do not extend the execution timeout", func(t *testing.T) {
+		t.Parallel()
+
+		const timeout = 500 * time.Millisecond
+
+		node := createNode(t, blockless.HeadNode)
+		node.cfg.ExecutionTimeout = timeout
+
+		// Chosen peer is connected, but never responds.
+		chosen, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, chosen)
+		err = node.host.Connect(context.Background(), *hostGetAddrInfo(t, chosen))
+		require.NoError(t, err)
+
+		backup, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, backup)
+		err = node.host.Connect(context.Background(), *hostGetAddrInfo(t, backup))
+		require.NoError(t, err)
+
+		start := time.Now()
+
+		attempts := newAttemptLog()
+		results, failovers := node.gatherExecutionResultsWithFailover(context.Background(), &req, []peer.ID{chosen.ID()}, newPeerReserve([]peer.ID{backup.ID()}), attempts)
+		require.Empty(t, failovers)
+		require.Empty(t, results)
+		require.Less(t, time.Since(start), 2*timeout)
+	})
+	t.Run("no reserve peers", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+		node.cfg.ExecutionTimeout = time.Second
+
+		attempts := newAttemptLog()
+		results, failovers := node.gatherExecutionResultsWithFailover(context.Background(), &req, []peer.ID{mocks.GenericPeerIDs[0]}, newPeerReserve(nil), attempts)
+		require.Empty(t, failovers)
+		require.Empty(t, results)
+
+		// No execution was re-dispatched.
+		require.Empty(t, attempts.history(nil, execute.AttemptExecution, nil, codes.NoContent, nil))
+	})
+}
@@ -0,0 +1,129 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+
+	"github.com/blocklessnetwork/b7s/b7serrors"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+var errNoSubgroups = errors.New("no subgroups specified")
+
+// ExecuteFunctionInSubgroups dispatches the execution to multiple subgroups at the same time, and gathers the results
+// of each subgroup separately. Each subgroup execution has its own request ID. The returned code is OK if the execution
+// succeeded in all subgroups, and PartialContent if it succeeded in only some of them.
+func (n *Node) ExecuteFunctionInSubgroups(ctx context.Context, req execute.Request, targets []execute.SubgroupTarget) (codes.Code, []execute.SubgroupResult, error) {
+
+	if !n.isHead() {
+		return codes.NotAvailable, nil, fmt.Errorf("action not supported on this node type")
+	}
+
+	err := validateSubgroupTargets(targets)
+	if err != nil {
+		return codes.Invalid, nil, fmt.Errorf("invalid subgroups: %w", err)
+	}
+
+	if n.rateLimited(req.FunctionID, "") {
+		return codes.TooManyRequests, nil, b7serrors.ErrRateLimited
+	}
+
+	// Deferred executions are acknowledged before they run, which the synchronous API cannot do.
+	if req.Config.NotBefore.After(time.Now()) {
+		return codes.NotSupported, nil, errDeferredExecutionNotSupported
+	}
+
+	n.metrics.IncrCounterWithLabels(subgroupFanInsMetric, 1, []metrics.Label{{Name: "function", Value: req.FunctionID}})
+
+	results := make([]execute.SubgroupResult, len(targets))
+
+	var wg sync.WaitGroup
+	for i, target := range targets {
+
+		sreq := req
+		if target.NodeCount > 0 {
+			sreq.Config.NodeCount = target.NodeCount
+		}
+
+		wg.Add(1)
+		go func(i int, topic string, req execute.Request) {
+			defer wg.Done()
+
+			requestID := n.newRequestID()
+			code, res, cluster, timing, err := n.restExecute(ctx, requestID, req, topic)
+
+			results[i] = execute.SubgroupResult{
+				Topic:     topic,
+				Code:      code,
+				RequestID: requestID,
+				Results:   res,
+				Cluster:   cluster,
+				Timing:    timing,
+			}
+			// Communicate the reason for failure in these cases.
+			if b7serrors.IsPublic(err) {
+				results[i].Message = err.Error()
+			}
+		}(i, target.Topic, sreq)
+	}
+
+	wg.Wait()
+
+	return fanInCode(results), results, nil
+}
+
+// validateSubgroupTargets checks that the execution targets at least one subgroup, and that no subgroup is targeted twice.
+func validateSubgroupTargets(targets []execute.SubgroupTarget) error {
+
+	if len(targets) == 0 {
+		return errNoSubgroups
+	}
+
+	seen := make(map[string]struct{}, len(targets))
+	for _, target := range targets {
+
+		if target.NodeCount < 0 {
+			return fmt.Errorf("node count cannot be negative (subgroup: %s)", target.Topic)
+		}
+
+		topic := target.Topic
+		if topic == "" {
+			topic = DefaultTopic
+		}
+
+		_, ok := seen[topic]
+		if ok {
+			return fmt.Errorf("duplicate subgroup (subgroup: %s)", topic)
+		}
+		seen[topic] = struct{}{}
+	}
+
+	return nil
+}
+
+// fanInCode returns the code of the execution across all subgroups. If the execution failed in all subgroups,
+// the code from the first subgroup is returned.
+func fanInCode(results []execute.SubgroupResult) codes.Code {
+
+	succeeded := 0
+	for _, res := range results {
+		if res.Code == codes.OK {
+			succeeded++
+		}
+	}
+
+	switch {
+	case succeeded == len(results):
+		return codes.OK
+	case succeeded > 0:
+		return codes.PartialContent
+	default:
+		return results[0].Code
+	}
+}
@@ -0,0 +1,86 @@
+package node
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_ExecuteInSubgroups(t *testing.T) {
+	t.Run("not supported on worker", func(t *testing.T) {
+		node := createNode(t, blockless.WorkerNode)
+
+		code, _, err := node.ExecuteFunctionInSubgroups(context.Background(), mocks.GenericExecutionRequest, []execute.SubgroupTarget{{Topic: "eu"}})
+		require.Error(t, err)
+		require.Equal(t, codes.NotAvailable, code)
+	})
+	t.Run("invalid subgroups", func(t *testing.T) {
+		node := createNode(t, blockless.HeadNode)
+
+		code, _, err := node.ExecuteFunctionInSubgroups(context.Background(), mocks.GenericExecutionRequest, nil)
+		require.ErrorIs(t, err, errNoSubgroups)
+		require.Equal(t, codes.Invalid, code)
+	})
+}
+
+func TestNode_ValidateSubgroupTargets(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		targets []execute.SubgroupTarget
+		valid   bool
+	}{
+		{
+			name:    "multiple subgroups",
+			targets: []execute.SubgroupTarget{{Topic: "eu", NodeCount: 3}, {Topic: "us"}},
+			valid:   true,
+		},
+		{
+			name: "no subgroups",
+		},
+		{
+			name:    "duplicate subgroup",
+			targets: []execute.SubgroupTarget{{Topic: "eu"}, {Topic: "eu", NodeCount: 2}},
+		},
+		{
+			name:    "default topic listed twice",
+			targets: []execute.SubgroupTarget{{Topic: ""}, {Topic: DefaultTopic}},
+		},
+		{
+			name:    "negative node count",
+			targets: []execute.SubgroupTarget{{Topic: "eu", NodeCount: -1}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateSubgroupTargets(test.targets)
+			if test.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestNode_FanInCode(t *testing.T) {
+
+	results := func(codes ...codes.Code) []execute.SubgroupResult {
+		out := make([]execute.SubgroupResult, 0, len(codes))
+		for _, code := range codes {
+			out = append(out, execute.SubgroupResult{Code: code})
+		}
+		return out
+	}
+
+	require.Equal(t, codes.OK, fanInCode(results(codes.OK, codes.OK)))
+	require.Equal(t, codes.PartialContent, fanInCode(results(codes.Timeout, codes.OK)))
+	require.Equal(t, codes.Timeout, fanInCode(results(codes.Timeout, codes.Error)))
+}
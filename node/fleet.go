@@ -0,0 +1,145 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/node/head/fleet"
+)
+
+// publishFleetReports will run a long running loop, publishing fleet reports until cancelled.
+func (n *Node) publishFleetReports(ctx context.Context) {
+
+	ticker := time.NewTicker(n.cfg.FleetReportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+
+			report, err := n.fleetReport(ctx)
+			if err != nil {
+				n.log.Warn().Err(err).Msg("could not create fleet report")
+				continue
+			}
+
+			err = n.publishToTopic(ctx, DefaultFleetTopic, &response.FleetReport{Report: report})
+			if err != nil {
+				n.log.Warn().Err(err).Msg("could not publish fleet report")
+				continue
+			}
+
+			n.log.Trace().Uint("functions", report.FunctionCount).Msg("published fleet report")
+
+		case <-ctx.Done():
+			n.log.Info().Msg("stopping fleet reports")
+			return
+		}
+	}
+}
+
+// fleetReport summarizes the functions installed on the worker, and how much work it can take on.
+func (n *Node) fleetReport(ctx context.Context) (fleet.Report, error) {
+
+	functions, err := n.fstore.Installed(ctx)
+	if err != nil {
+		return fleet.Report{}, fmt.Errorf("could not list installed functions: %w", err)
+	}
+
+	filter := fleet.NewFilter(uint(len(functions)), fleet.FalsePositiveRate)
+	for _, cid := range functions {
+		filter.Add(cid)
+	}
+
+	load := n.currentLoad()
+
+	report := fleet.Report{
+		Functions:     filter,
+		FunctionCount: uint(len(functions)),
+		Capacity: fleet.Capacity{
+			Concurrency:     load.Concurrency,
+			Executions:      load.Executions,
+			Queued:          load.Queued,
+			MemoryAvailable: load.MemoryAvailable,
+		},
+	}
+
+	return report, nil
+}
+
+// processFleetReport records the fleet report of the worker. Only reports of connected workers are recorded.
+func (n *Node) processFleetReport(ctx context.Context, from peer.ID, report response.FleetReport) error {
+
+	n.log.Trace().Stringer("peer", from).Uint("functions", report.FunctionCount).Msg("fleet report received")
+	n.metrics.IncrCounter(fleetReportsMetric, 1)
+
+	known, ok := n.peers.get(from)
+	if !ok || known.role != blockless.WorkerNode || !n.haveConnection(from) {
+		n.log.Debug().Stringer("peer", from).Msg("dropping fleet report from a peer that is not a connected worker")
+		return nil
+	}
+
+	err := n.fleet.Record(from, report.Report)
+	if err != nil {
+		return fmt.Errorf("could not record fleet report: %w", err)
+	}
+
+	return nil
+}
+
+// FleetPlan returns how the head node would execute the function in the subgroup, based on the fleet reports of workers, without
+// executing it. Restricted subgroups only count workers known to be members.
+func (n *Node) FleetPlan(functionID string, nodeCount int, topic string) fleet.Plan {
+
+	// Same as for executions, unset node count means a single worker.
+	if nodeCount == 0 {
+		nodeCount = 1
+	}
+
+	var eligible func(peer.ID) bool
+	if n.isRestrictedSubgroup(topic) {
+		eligible = func(id peer.ID) bool {
+			return n.isSubgroupMember(topic, id)
+		}
+	}
+
+	plan := n.fleet.Plan(functionID, nodeCount, eligible)
+	if len(plan.Candidates) > fleetPlanMaxPeers {
+		plan.Candidates = plan.Candidates[:fleetPlanMaxPeers]
+	}
+
+	return plan
+}
+
+func (n *Node) processFleetPlan(ctx context.Context, from peer.ID, req request.FleetPlan) error {
+
+	n.log.Debug().Str("peer", from.String()).Str("function", req.FunctionID).Int("node_count", req.NodeCount).Str("topic", req.Topic).Msg("processing fleet plan request")
+
+	plan := n.FleetPlan(req.FunctionID, req.NodeCount, req.Topic)
+
+	err := n.send(ctx, from, req.Response(codes.OK, plan))
+	if err != nil {
+		return fmt.Errorf("could not send response: %w", err)
+	}
+
+	return nil
+}
+
+// fleetWeight returns the factor by which the score of the peer is scaled when choosing peers to execute the function.
+// Workers that had the function installed before the roll call are preferred, as they likely have it warm in their caches.
+func (n *Node) fleetWeight(peer peer.ID, functionID string) float64 {
+
+	installed, known := n.fleet.Installed(peer, functionID)
+	if known && !installed {
+		return fleetColdWeight
+	}
+
+	return 1
+}
@@ -0,0 +1,89 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_FleetReport(t *testing.T) {
+
+	const (
+		installedFunction = "installed-function"
+		missingFunction   = "missing-function"
+	)
+
+	worker := createNode(t, blockless.WorkerNode)
+
+	fstore := mocks.BaselineFStore(t)
+	fstore.InstalledFunc = func(context.Context) ([]string, error) {
+		return []string{installedFunction}, nil
+	}
+	worker.fstore = fstore
+
+	report, err := worker.fleetReport(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint(1), report.FunctionCount)
+	require.True(t, report.Functions.Contains(installedFunction))
+	require.Equal(t, worker.cfg.Concurrency, report.Capacity.Concurrency)
+
+	head := createNode(t, blockless.HeadNode)
+	require.Contains(t, head.cfg.Topics, DefaultFleetTopic)
+	require.NotContains(t, worker.cfg.Topics, DefaultFleetTopic)
+
+	workerID := worker.host.ID()
+
+	// Workers without a fleet report are not penalized.
+	require.Equal(t, 1.0, head.fleetWeight(workerID, missingFunction))
+
+	// Reports of peers that are not connected workers are dropped.
+	err = head.processFleetReport(context.Background(), workerID, response.FleetReport{Report: report})
+	require.NoError(t, err)
+	require.Equal(t, 1.0, head.fleetWeight(workerID, missingFunction))
+
+	hostAddNewPeer(t, head.host, worker.host)
+	err = head.host.Connect(context.Background(), *hostGetAddrInfo(t, worker.host))
+	require.NoError(t, err)
+
+	err = head.processFleetReport(context.Background(), workerID, response.FleetReport{Report: report})
+	require.NoError(t, err)
+	require.Equal(t, 1.0, head.fleetWeight(workerID, missingFunction))
+
+	head.peers.update(workerID, blockless.WorkerNode, nil, false)
+
+	err = head.processFleetReport(context.Background(), workerID, response.FleetReport{Report: report})
+	require.NoError(t, err)
+
+	require.Equal(t, 1.0, head.fleetWeight(workerID, installedFunction))
+	require.Equal(t, fleetColdWeight, head.fleetWeight(workerID, missingFunction))
+
+	plan := head.FleetPlan(installedFunction, 0, "")
+	require.Equal(t, 1, plan.NodeCount)
+	require.Equal(t, uint(1), plan.Installed)
+	require.Equal(t, []string{workerID.String()}, plan.Candidates)
+	require.True(t, plan.Feasible)
+
+	plan = head.FleetPlan(missingFunction, 1, "")
+	require.Zero(t, plan.Installed)
+	require.False(t, plan.Feasible)
+
+	// Restricted subgroups count only workers that presented a membership credential.
+	const subgroup = "blockless/b7s/private"
+	head.cfg.RestrictedSubgroups = map[string]peer.ID{subgroup: mocks.GenericPeerIDs[0]}
+
+	plan = head.FleetPlan(installedFunction, 0, subgroup)
+	require.Zero(t, plan.Workers)
+	require.Empty(t, plan.Candidates)
+
+	head.members.add(subgroup, workerID, time.Time{})
+
+	plan = head.FleetPlan(installedFunction, 0, subgroup)
+	require.Equal(t, []string{workerID.String()}, plan.Candidates)
+}
@@ -2,6 +2,8 @@ package node
 
 import (
 	"context"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
 )
 
 // FStore provides retrieval of function manifest.
@@ -9,9 +11,18 @@ type FStore interface {
 	// Install will install a function based on the address and CID.
 	Install(ctx context.Context, address string, cid string) error
 
+	// Uninstall removes the function from the store.
+	Uninstall(ctx context.Context, cid string) error
+
 	// IsInstalled returns info if the function is installed or not.
 	IsInstalled(cid string) (bool, error)
 
+	// Installed returns the CIDs of installed functions.
+	Installed(ctx context.Context) ([]string, error)
+
+	// Get retrieves the function record, including its manifest.
+	Get(ctx context.Context, cid string) (blockless.FunctionRecord, error)
+
 	// TODO: Refactor the sync code - move the logic outside of the package
 	// Sync will ensure function installations are correct, redownloading functions if needed.
 	Sync(ctx context.Context, haltOnError bool) error
@@ -0,0 +1,19 @@
+package node
+
+import (
+	"errors"
+	"slices"
+)
+
+var errFunctionNotPermitted = errors.New("function is not permitted on this worker")
+
+// functionPermitted returns true if the worker is allowed to execute the function. Denied functions are never executed,
+// and if the worker has an allowlist, only functions on it are executed.
+func (n *Node) functionPermitted(functionID string) bool {
+
+	if slices.Contains(n.cfg.DeniedFunctions, functionID) {
+		return false
+	}
+
+	return len(n.cfg.AllowedFunctions) == 0 || slices.Contains(n.cfg.AllowedFunctions, functionID)
+}
@@ -0,0 +1,32 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+)
+
+func TestNode_FunctionPermitted(t *testing.T) {
+
+	const (
+		allowed = "allowed-function-id"
+		denied  = "denied-function-id"
+		other   = "other-function-id"
+	)
+
+	node := createNode(t, blockless.WorkerNode)
+	require.True(t, node.functionPermitted(other))
+
+	node.cfg.DeniedFunctions = []string{denied}
+	require.False(t, node.functionPermitted(denied))
+	require.True(t, node.functionPermitted(other))
+
+	node.cfg.AllowedFunctions = []string{allowed, denied}
+	require.True(t, node.functionPermitted(allowed))
+	require.False(t, node.functionPermitted(other))
+
+	// Denylist takes precedence.
+	require.False(t, node.functionPermitted(denied))
+}
@@ -5,12 +5,23 @@ import (
 
 	"github.com/libp2p/go-libp2p/core/peer"
 
+	"github.com/blocklessnetwork/b7s/models/blockless"
 	"github.com/blocklessnetwork/b7s/models/codes"
 	"github.com/blocklessnetwork/b7s/models/response"
 )
 
-func (n *Node) processHealthCheck(ctx context.Context, from peer.ID, _ response.Health) error {
-	n.log.Trace().Stringer("peer", from).Msg("peer health check received")
+func (n *Node) processHealthCheck(ctx context.Context, from peer.ID, health response.Health) error {
+	n.log.Trace().Stringer("peer", from).Stringer("role", health.Role).Msg("peer health check received")
+
+	n.peers.update(from, health.Role, health.Attributes, health.NoConsensus)
+
+	// Workers report their load in health pings too, not only in roll call responses. Keep the latest one for scheduling.
+	// The load is attributed to the author of the health ping, never to a peer relaying it.
+	if health.Load != nil && health.Role == blockless.WorkerNode {
+		n.peers.recordLoad(from, health.Load)
+		n.loads.record(from, health.Load)
+	}
+
 	return nil
 }
 
@@ -22,12 +33,12 @@ func (n *Node) processRollCallResponse(ctx context.Context, from peer.ID, res re
 
 	// Check if the response is adequate.
 	if res.Code != codes.Accepted {
-		log.Info().Stringer("code", res.Code).Msg("skipping inadequate roll call response - unwanted code")
+		log.Info().Stringer("code", res.Code).Str("reason", res.Reason).Msg("skipping inadequate roll call response - unwanted code")
 		return nil
 	}
 
 	// Check if there's an active roll call already.
-	exists := n.rollCall.exists(res.RequestID)
+	exists := n.rollCall.Exists(res.RequestID)
 	if !exists {
 		log.Info().Msg("no pending roll call for the given request, dropping response")
 		return nil
@@ -35,13 +46,17 @@ func (n *Node) processRollCallResponse(ctx context.Context, from peer.ID, res re
 
 	log.Info().Msg("recording roll call response")
 
-	rres := rollCallResponse{
+	if res.Install != nil {
+		log.Info().Str("state", res.Install.State).Int64("elapsed_ms", res.Install.Elapsed).Msg("worker is installing the function")
+	}
+
+	rres := RollCallResponse{
 		From:     from,
 		RollCall: res,
 	}
 
 	// Record the response.
-	n.rollCall.add(res.RequestID, rres)
+	n.rollCall.Add(res.RequestID, rres)
 
 	return nil
 }
@@ -39,7 +39,7 @@ func TestNode_Handlers(t *testing.T) {
 			requestID = "dummy-request-id"
 		)
 
-		node.rollCall.create(requestID)
+		node.rollCall.Create(requestID)
 
 		res := response.RollCall{
 			Code:       codes.Accepted,
@@ -49,10 +49,10 @@ func TestNode_Handlers(t *testing.T) {
 
 		// Record response asynchronously.
 		var wg sync.WaitGroup
-		var recordedResponse rollCallResponse
+		var recordedResponse RollCallResponse
 		go func() {
 			defer wg.Done()
-			recordedResponse = <-node.rollCall.responses(requestID)
+			recordedResponse = <-node.rollCall.Responses(requestID)
 		}()
 
 		wg.Add(1)
@@ -72,7 +72,7 @@ func TestNode_Handlers(t *testing.T) {
 			requestID = "dummy-request-id-2"
 		)
 
-		node.rollCall.create(requestID)
+		node.rollCall.Create(requestID)
 
 		// We only want responses with the code `Accepted`.
 		res := response.RollCall{
@@ -89,7 +89,7 @@ func TestNode_Handlers(t *testing.T) {
 		defer cancel()
 
 		select {
-		case <-node.rollCall.responses(requestID):
+		case <-node.rollCall.Responses(requestID):
 			require.FailNow(t, "roll call response found but not expected")
 		case <-ctx.Done():
 			break
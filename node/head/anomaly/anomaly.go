@@ -0,0 +1,166 @@
+package anomaly
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+// Kinds of anomalies the detector reports.
+const (
+	KindDuration   = "duration"
+	KindOutputSize = "output-size"
+)
+
+const (
+	// DefaultMinSamples is the number of executions of a function needed before results are checked.
+	DefaultMinSamples = 20
+	// DefaultWindow is the approximate number of recent executions the distribution is based on.
+	DefaultWindow = 1000
+
+	// Deviations are measured against at least this fraction of the mean, so that functions with near constant
+	// durations or outputs do not flag every small change.
+	minRelativeDeviation = 0.25
+)
+
+// Anomaly describes a result that deviates from the history of the function.
+type Anomaly struct {
+	Kind     string  `json:"kind"`
+	Value    float64 `json:"value"`
+	Mean     float64 `json:"mean"`
+	StdDev   float64 `json:"stddev"`
+	Distance float64 `json:"distance"` // Distance from the mean, relative to the spread of previous results.
+}
+
+// Detector flags execution results whose duration or output size deviates strongly from what the function usually produces.
+type Detector struct {
+	sync.Mutex
+
+	threshold  float64
+	minSamples uint
+	window     uint
+
+	functions map[string]*history
+}
+
+type history struct {
+	duration   distribution
+	outputSize distribution
+}
+
+// NewDetector creates a new Detector. Results more than `threshold` standard deviations away from the mean are flagged.
+func NewDetector(threshold float64) *Detector {
+
+	d := Detector{
+		threshold:  threshold,
+		minSamples: DefaultMinSamples,
+		window:     DefaultWindow,
+		functions:  make(map[string]*history),
+	}
+
+	return &d
+}
+
+// Check returns the anomalies found in the execution result, and records the result in the function history.
+// Only successful executions are recorded, so that failures do not become the norm; all results are checked.
+func (d *Detector) Check(functionID string, res execute.NodeResult) []Anomaly {
+
+	d.Lock()
+	defer d.Unlock()
+
+	h, ok := d.functions[functionID]
+	if !ok {
+		h = &history{}
+		d.functions[functionID] = h
+	}
+
+	var anomalies []Anomaly
+
+	duration, haveDuration := resultDuration(res)
+	size := float64(len(res.Result.Result.Stdout) + len(res.Result.Result.Stderr))
+
+	if haveDuration {
+		a, ok := d.check(KindDuration, h.duration, duration.Seconds())
+		if ok {
+			anomalies = append(anomalies, a)
+		}
+	}
+
+	a, ok := d.check(KindOutputSize, h.outputSize, size)
+	if ok {
+		anomalies = append(anomalies, a)
+	}
+
+	if res.Code == codes.OK {
+		if haveDuration {
+			h.duration.add(duration.Seconds(), d.window)
+		}
+		h.outputSize.add(size, d.window)
+	}
+
+	return anomalies
+}
+
+func (d *Detector) check(kind string, dist distribution, value float64) (Anomaly, bool) {
+
+	if dist.count < uint64(d.minSamples) {
+		return Anomaly{}, false
+	}
+
+	stddev := math.Sqrt(dist.variance)
+	scale := max(stddev, math.Abs(dist.mean)*minRelativeDeviation)
+	if scale == 0 {
+		scale = 1
+	}
+
+	distance := math.Abs(value-dist.mean) / scale
+	if distance <= d.threshold {
+		return Anomaly{}, false
+	}
+
+	a := Anomaly{
+		Kind:     kind,
+		Value:    value,
+		Mean:     dist.mean,
+		StdDev:   stddev,
+		Distance: distance,
+	}
+
+	return a, true
+}
+
+// resultDuration returns the duration of the execution - as reported by the runtime, or as measured by the worker.
+func resultDuration(res execute.NodeResult) (time.Duration, bool) {
+
+	if res.Usage.WallClockTime > 0 {
+		return res.Usage.WallClockTime, true
+	}
+
+	if res.Timing != nil && res.Timing.Execution > 0 {
+		return time.Duration(res.Timing.Execution) * time.Millisecond, true
+	}
+
+	return 0, false
+}
+
+// distribution keeps the mean and variance of the samples. Once there are more samples than the window size,
+// older samples gradually lose weight, so the distribution follows changes in function behavior.
+type distribution struct {
+	count    uint64
+	mean     float64
+	variance float64
+}
+
+func (d *distribution) add(value float64, window uint) {
+
+	d.count++
+
+	weight := 1 / float64(min(d.count, uint64(window)))
+	delta := value - d.mean
+
+	d.mean += weight * delta
+	d.variance = (1 - weight) * (d.variance + weight*delta*delta)
+}
@@ -0,0 +1,98 @@
+package anomaly_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/node/head/anomaly"
+)
+
+func TestDetector(t *testing.T) {
+
+	const (
+		functionID = "dummy-function-id"
+		threshold  = 4
+	)
+
+	result := func(code codes.Code, duration time.Duration, output string) execute.NodeResult {
+		return execute.NodeResult{
+			Result: execute.Result{
+				Code:   code,
+				Result: execute.RuntimeOutput{Stdout: output},
+				Usage:  execute.Usage{WallClockTime: duration},
+			},
+		}
+	}
+
+	// Build up history with durations between 90ms and 110ms and a stable output.
+	trained := func(t *testing.T) *anomaly.Detector {
+		t.Helper()
+
+		detector := anomaly.NewDetector(threshold)
+		for i := 0; i < anomaly.DefaultMinSamples; i++ {
+			duration := time.Duration(90+i%21) * time.Millisecond
+			anomalies := detector.Check(functionID, result(codes.OK, duration, "hello world"))
+			require.Empty(t, anomalies)
+		}
+
+		return detector
+	}
+
+	t.Run("results are not checked without enough history", func(t *testing.T) {
+		t.Parallel()
+
+		detector := anomaly.NewDetector(threshold)
+		for i := 0; i < anomaly.DefaultMinSamples; i++ {
+			anomalies := detector.Check(functionID, result(codes.OK, time.Duration(i+1)*time.Hour, strings.Repeat("x", i*1000)))
+			require.Empty(t, anomalies)
+		}
+	})
+	t.Run("typical result is not flagged", func(t *testing.T) {
+		t.Parallel()
+
+		detector := trained(t)
+		anomalies := detector.Check(functionID, result(codes.OK, 105*time.Millisecond, "hello there"))
+		require.Empty(t, anomalies)
+	})
+	t.Run("long execution is flagged", func(t *testing.T) {
+		t.Parallel()
+
+		detector := trained(t)
+		anomalies := detector.Check(functionID, result(codes.Timeout, 30*time.Second, "hello world"))
+		require.Len(t, anomalies, 1)
+		require.Equal(t, anomaly.KindDuration, anomalies[0].Kind)
+		require.Greater(t, anomalies[0].Distance, float64(threshold))
+	})
+	t.Run("large output is flagged", func(t *testing.T) {
+		t.Parallel()
+
+		detector := trained(t)
+		anomalies := detector.Check(functionID, result(codes.OK, 100*time.Millisecond, strings.Repeat("x", 10_000)))
+		require.Len(t, anomalies, 1)
+		require.Equal(t, anomaly.KindOutputSize, anomalies[0].Kind)
+	})
+	t.Run("failed executions do not change history", func(t *testing.T) {
+		t.Parallel()
+
+		detector := trained(t)
+		for i := 0; i < 100; i++ {
+			detector.Check(functionID, result(codes.Error, 30*time.Second, ""))
+		}
+
+		anomalies := detector.Check(functionID, result(codes.Error, 30*time.Second, "hello world"))
+		require.Len(t, anomalies, 1)
+		require.Equal(t, anomaly.KindDuration, anomalies[0].Kind)
+	})
+	t.Run("functions have separate history", func(t *testing.T) {
+		t.Parallel()
+
+		detector := trained(t)
+		anomalies := detector.Check("other-function-id", result(codes.OK, 30*time.Second, strings.Repeat("x", 10_000)))
+		require.Empty(t, anomalies)
+	})
+}
@@ -0,0 +1,366 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/node/head/recovery"
+	"github.com/blocklessnetwork/b7s/store"
+)
+
+// Name of the store, used as a label for store metrics.
+const storeName = "archive"
+
+const (
+	defaultPermissions = 0o750
+
+	// maxBatchSize is the maximum number of results archived in a single batch.
+	maxBatchSize = 1000
+)
+
+// Entry describes the results of a single execution request.
+type Entry struct {
+	RequestID string            `json:"request_id"`
+	Completed time.Time         `json:"completed"`
+	Results   execute.ResultMap `json:"results"`
+}
+
+// Archive keeps execution results. Results are kept in the database first, and are moved to the object store
+// in compressed batches once they are older than the retention period. An index of batches is kept in the database,
+// so archived results can still be retrieved.
+type Archive struct {
+	sync.Mutex
+
+	db        *pebble.DB
+	objects   ObjectStore
+	retention time.Duration
+}
+
+// New creates a new Archive using the given database and object store.
+func New(db *pebble.DB, objects ObjectStore, retention time.Duration) *Archive {
+
+	a := Archive{
+		db:        db,
+		objects:   objects,
+		retention: retention,
+	}
+
+	return &a
+}
+
+// Save stores the results of the execution request.
+func (a *Archive) Save(requestID string, results execute.ResultMap, completed time.Time) error {
+
+	defer store.ObserveWrite(storeName, time.Now())
+
+	a.Lock()
+	defer a.Unlock()
+
+	entry := Entry{
+		RequestID: requestID,
+		Completed: completed.UTC(),
+		Results:   results,
+	}
+
+	encoded, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("could not encode results: %w", err)
+	}
+
+	err = a.db.Set(encodeKey(store.PrefixResult, requestID), encoded, pebble.Sync)
+	if err != nil {
+		return fmt.Errorf("could not store results: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the results of the execution request, reading them from the object store if they were archived.
+func (a *Archive) Get(requestID string) (execute.ResultMap, bool, error) {
+
+	defer store.ObserveRead(storeName, time.Now())
+
+	a.Lock()
+	defer a.Unlock()
+
+	var entry Entry
+	found, err := a.get(encodeKey(store.PrefixResult, requestID), &entry)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not retrieve results: %w", err)
+	}
+	if found {
+		return entry.Results, true, nil
+	}
+
+	var batch string
+	found, err = a.get(encodeKey(store.PrefixArchiveIndex, requestID), &batch)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not retrieve archive index: %w", err)
+	}
+	if !found {
+		return nil, false, nil
+	}
+
+	entries, err := a.readBatch(batch)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not read archived batch (batch: %s): %w", batch, err)
+	}
+
+	for _, entry := range entries {
+		if entry.RequestID == requestID {
+			return entry.Results, true, nil
+		}
+	}
+
+	return nil, false, fmt.Errorf("archived batch does not contain results (batch: %s)", batch)
+}
+
+// Archive moves results older than the retention period to the object store. It returns the number of archived results.
+func (a *Archive) Archive(now time.Time) (int, error) {
+
+	defer store.ObserveWrite(storeName, time.Now())
+
+	a.Lock()
+	defer a.Unlock()
+
+	cutoff := now.Add(-a.retention)
+
+	expired, err := a.expired(cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("could not find results to archive: %w", err)
+	}
+
+	archived := 0
+	for len(expired) > 0 {
+
+		size := min(len(expired), maxBatchSize)
+		batch := expired[:size]
+		expired = expired[size:]
+
+		err = a.writeBatch(batchKey(now, archived), batch)
+		if err != nil {
+			return archived, err
+		}
+
+		archived += len(batch)
+	}
+
+	return archived, nil
+}
+
+// Scan counts the execution results kept in the database and in the archive, and returns the list of records that could not be read.
+func (a *Archive) Scan() (uint, uint, []recovery.Corruption, error) {
+
+	defer store.ObserveRead(storeName, time.Now())
+
+	a.Lock()
+	defer a.Unlock()
+
+	var (
+		stored    uint
+		archived  uint
+		corrupted []recovery.Corruption
+	)
+
+	err := a.scan(store.PrefixResult, func(id string, value []byte) {
+		var entry Entry
+		err := json.Unmarshal(value, &entry)
+		if err != nil {
+			corrupted = append(corrupted, recovery.Corruption{Source: recovery.SourceResults, ID: id, Reason: err.Error()})
+			return
+		}
+		stored++
+	})
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("could not scan results: %w", err)
+	}
+
+	err = a.scan(store.PrefixArchiveIndex, func(id string, value []byte) {
+		var batch string
+		err := json.Unmarshal(value, &batch)
+		if err != nil {
+			corrupted = append(corrupted, recovery.Corruption{Source: recovery.SourceResults, ID: id, Reason: fmt.Sprintf("invalid archive index: %s", err)})
+			return
+		}
+		archived++
+	})
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("could not scan archive index: %w", err)
+	}
+
+	return stored, archived, corrupted, nil
+}
+
+// scan calls the given function for all records with the given prefix.
+// NOTE: Caller should hold the lock.
+func (a *Archive) scan(prefix byte, fn func(id string, value []byte)) error {
+
+	opts := pebble.IterOptions{
+		LowerBound: []byte{prefix, store.Separator},
+		UpperBound: []byte{prefix, store.Separator + 1},
+	}
+
+	it, err := a.db.NewIter(&opts)
+	if err != nil {
+		return fmt.Errorf("could not create iterator: %w", err)
+	}
+	defer it.Close()
+
+	for it.First(); it.Valid(); it.Next() {
+		fn(string(it.Key()[2:]), it.Value())
+	}
+
+	return nil
+}
+
+// expired returns results completed before the cutoff.
+// NOTE: Caller should hold the lock.
+func (a *Archive) expired(cutoff time.Time) ([]Entry, error) {
+
+	opts := pebble.IterOptions{
+		LowerBound: []byte{store.PrefixResult, store.Separator},
+		UpperBound: []byte{store.PrefixResult, store.Separator + 1},
+	}
+
+	it, err := a.db.NewIter(&opts)
+	if err != nil {
+		return nil, fmt.Errorf("could not create iterator: %w", err)
+	}
+	defer it.Close()
+
+	entries := make([]Entry, 0)
+	for it.First(); it.Valid(); it.Next() {
+
+		// Records that cannot be decoded are left in place - they are listed in the recovery report.
+		var entry Entry
+		err = json.Unmarshal(it.Value(), &entry)
+		if err != nil {
+			continue
+		}
+
+		if entry.Completed.Before(cutoff) {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// writeBatch stores the compressed batch in the object store, indexes its results and removes them from the database.
+// NOTE: Caller should hold the lock.
+func (a *Archive) writeBatch(key string, entries []Entry) error {
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+
+	err := json.NewEncoder(w).Encode(entries)
+	if err != nil {
+		return fmt.Errorf("could not encode batch: %w", err)
+	}
+
+	err = w.Close()
+	if err != nil {
+		return fmt.Errorf("could not compress batch: %w", err)
+	}
+
+	err = a.objects.Put(key, buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("could not store batch (batch: %s): %w", key, err)
+	}
+
+	index, err := json.Marshal(key)
+	if err != nil {
+		return fmt.Errorf("could not encode archive index: %w", err)
+	}
+
+	// Index and remove archived results in a single write, so results are always found either in the database or in the archive.
+	b := a.db.NewBatch()
+	defer b.Close()
+
+	for _, entry := range entries {
+
+		err = b.Set(encodeKey(store.PrefixArchiveIndex, entry.RequestID), index, nil)
+		if err != nil {
+			return fmt.Errorf("could not index archived results: %w", err)
+		}
+
+		err = b.Delete(encodeKey(store.PrefixResult, entry.RequestID), nil)
+		if err != nil {
+			return fmt.Errorf("could not remove archived results: %w", err)
+		}
+	}
+
+	err = b.Commit(pebble.Sync)
+	if err != nil {
+		return fmt.Errorf("could not commit archived batch (batch: %s): %w", key, err)
+	}
+
+	return nil
+}
+
+// readBatch reads the compressed batch from the object store.
+func (a *Archive) readBatch(key string) ([]Entry, error) {
+
+	data, err := a.objects.Get(key)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve batch: %w", err)
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not decompress batch: %w", err)
+	}
+	defer r.Close()
+
+	payload, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("could not decompress batch: %w", err)
+	}
+
+	var entries []Entry
+	err = json.Unmarshal(payload, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode batch: %w", err)
+	}
+
+	return entries, nil
+}
+
+// get reads and decodes the value stored under the given key.
+// NOTE: Caller should hold the lock.
+func (a *Archive) get(key []byte, out any) (bool, error) {
+
+	value, closer, err := a.db.Get(key)
+	if errors.Is(err, pebble.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer closer.Close()
+
+	err = json.Unmarshal(value, out)
+	if err != nil {
+		return false, fmt.Errorf("could not decode value: %w", err)
+	}
+
+	return true, nil
+}
+
+func batchKey(now time.Time, offset int) string {
+	return fmt.Sprintf("results-%d-%d.json.gz", now.UTC().UnixNano(), offset)
+}
+
+func encodeKey(prefix byte, requestID string) []byte {
+	key := []byte{prefix, store.Separator}
+	return append(key, []byte(requestID)...)
+}
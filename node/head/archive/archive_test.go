@@ -0,0 +1,69 @@
+package archive_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/node/head/archive"
+	"github.com/blocklessnetwork/b7s/testing/helpers"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestArchive(t *testing.T) {
+
+	const (
+		retention = time.Hour
+
+		oldRequest    = "dummy-request-old"
+		recentRequest = "dummy-request-recent"
+		dir           = "/archive"
+	)
+
+	now := time.Now()
+
+	db := helpers.InMemoryDB(t)
+	defer db.Close()
+
+	fs := afero.NewMemMapFs()
+	a := archive.New(db, archive.NewDirStore(fs, dir), retention)
+
+	require.NoError(t, a.Save(oldRequest, mocks.GenericExecutionResultMap, now.Add(-2*retention)))
+	require.NoError(t, a.Save(recentRequest, mocks.GenericExecutionResultMap, now))
+
+	results, ok, err := a.Get(oldRequest)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, mocks.GenericExecutionResultMap, results)
+
+	// Only the results older than the retention period are archived.
+	archived, err := a.Archive(now)
+	require.NoError(t, err)
+	require.Equal(t, 1, archived)
+
+	files, err := afero.ReadDir(fs, dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	// Archived results are still available.
+	results, ok, err = a.Get(oldRequest)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, mocks.GenericExecutionResultMap, results)
+
+	results, ok, err = a.Get(recentRequest)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, mocks.GenericExecutionResultMap, results)
+
+	// Nothing left to archive.
+	archived, err = a.Archive(now)
+	require.NoError(t, err)
+	require.Zero(t, archived)
+
+	_, ok, err = a.Get("dummy-request-unknown")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
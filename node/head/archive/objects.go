@@ -0,0 +1,60 @@
+package archive
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+// ObjectStore is where batches of archived results are kept.
+type ObjectStore interface {
+	// Put stores the object under the given key.
+	Put(key string, data []byte) error
+	// Get returns the object stored under the given key.
+	Get(key string) ([]byte, error)
+}
+
+// DirStore is an ObjectStore that keeps objects as files in a directory.
+type DirStore struct {
+	fs  afero.Fs
+	dir string
+}
+
+// NewDirStore creates a new DirStore keeping objects in the given directory.
+func NewDirStore(fs afero.Fs, dir string) *DirStore {
+
+	s := DirStore{
+		fs:  fs,
+		dir: dir,
+	}
+
+	return &s
+}
+
+// Put stores the object in a file named after the key.
+func (s *DirStore) Put(key string, data []byte) error {
+
+	err := s.fs.MkdirAll(s.dir, defaultPermissions)
+	if err != nil {
+		return fmt.Errorf("could not create archive directory: %w", err)
+	}
+
+	err = afero.WriteFile(s.fs, filepath.Join(s.dir, key), data, defaultPermissions)
+	if err != nil {
+		return fmt.Errorf("could not write object: %w", err)
+	}
+
+	return nil
+}
+
+// Get reads the object from the file named after the key.
+func (s *DirStore) Get(key string) ([]byte, error) {
+
+	data, err := afero.ReadFile(s.fs, filepath.Join(s.dir, key))
+	if err != nil {
+		return nil, fmt.Errorf("could not read object: %w", err)
+	}
+
+	return data, nil
+}
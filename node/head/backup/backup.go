@@ -0,0 +1,236 @@
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
+
+	"github.com/blocklessnetwork/b7s/node/head/archive"
+)
+
+const (
+	defaultPermissions = 0o750
+)
+
+// ErrDatabaseExists is returned when restoring a snapshot into a directory that already has a database.
+var ErrDatabaseExists = errors.New("database already exists")
+
+// Snapshot describes a consistent copy of the database, kept in an object store.
+type Snapshot struct {
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+	Files   []string  `json:"files"`
+	Size    uint64    `json:"size"`
+}
+
+// Manager runs maintenance operations on the database backing the head node stores - compaction and backups.
+type Manager struct {
+	sync.Mutex
+
+	db      *pebble.DB
+	fs      vfs.FS
+	dir     string
+	objects archive.ObjectStore
+}
+
+// NewManager creates a new Manager for the database in the given directory. Backups are written to the given object store.
+// File system should be the one the database was opened with.
+func NewManager(db *pebble.DB, fs vfs.FS, dir string, objects archive.ObjectStore) *Manager {
+
+	m := Manager{
+		db:      db,
+		fs:      fs,
+		dir:     dir,
+		objects: objects,
+	}
+
+	return &m
+}
+
+// Compact compacts the entire key range of the database.
+func (m *Manager) Compact() error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	it, err := m.db.NewIter(nil)
+	if err != nil {
+		return fmt.Errorf("could not create iterator: %w", err)
+	}
+
+	var start, end []byte
+	if it.First() {
+		start = append([]byte{}, it.Key()...)
+	}
+	if it.Last() {
+		// End of the compaction range is exclusive.
+		end = append(append([]byte{}, it.Key()...), 0)
+	}
+
+	err = it.Close()
+	if err != nil {
+		return fmt.Errorf("could not close iterator: %w", err)
+	}
+
+	// Database is empty.
+	if start == nil {
+		return nil
+	}
+
+	err = m.db.Compact(start, end, true)
+	if err != nil {
+		return fmt.Errorf("could not compact database: %w", err)
+	}
+
+	return nil
+}
+
+// Backup creates a consistent snapshot of the database and copies it to the object store.
+func (m *Manager) Backup(now time.Time) (Snapshot, error) {
+
+	m.Lock()
+	defer m.Unlock()
+
+	snapshot := Snapshot{
+		Name:    snapshotName(now),
+		Created: now.UTC(),
+	}
+
+	// Checkpoint is created next to the database, so that files can be hard-linked instead of copied.
+	checkpoint := m.dir + "-" + snapshot.Name
+	err := m.db.Checkpoint(checkpoint, pebble.WithFlushedWAL())
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("could not create checkpoint: %w", err)
+	}
+	defer m.fs.RemoveAll(checkpoint)
+
+	files, err := m.fs.List(checkpoint)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("could not list checkpoint files: %w", err)
+	}
+
+	for _, file := range files {
+
+		data, err := readFile(m.fs, m.fs.PathJoin(checkpoint, file))
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("could not read checkpoint file (file: %s): %w", file, err)
+		}
+
+		err = m.objects.Put(fileKey(snapshot.Name, file), data)
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("could not store checkpoint file (file: %s): %w", file, err)
+		}
+
+		snapshot.Files = append(snapshot.Files, file)
+		snapshot.Size += uint64(len(data))
+	}
+
+	// Manifest is written last - snapshots without one are incomplete.
+	manifest, err := json.Marshal(snapshot)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("could not encode snapshot manifest: %w", err)
+	}
+
+	err = m.objects.Put(manifestKey(snapshot.Name), manifest)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("could not store snapshot manifest: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+// Restore recreates the database in the given directory from the snapshot in the object store.
+// The directory should not exist or be empty, as existing databases are never overwritten.
+func Restore(objects archive.ObjectStore, name string, fs vfs.FS, dir string) (Snapshot, error) {
+
+	existing, err := fs.List(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return Snapshot{}, fmt.Errorf("could not list database directory: %w", err)
+	}
+	if len(existing) > 0 {
+		return Snapshot{}, fmt.Errorf("%w (dir: %s)", ErrDatabaseExists, dir)
+	}
+
+	manifest, err := objects.Get(manifestKey(name))
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("could not retrieve snapshot manifest: %w", err)
+	}
+
+	var snapshot Snapshot
+	err = json.Unmarshal(manifest, &snapshot)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("could not decode snapshot manifest: %w", err)
+	}
+
+	err = fs.MkdirAll(dir, defaultPermissions)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("could not create database directory: %w", err)
+	}
+
+	for _, file := range snapshot.Files {
+
+		data, err := objects.Get(fileKey(snapshot.Name, file))
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("could not retrieve snapshot file (file: %s): %w", file, err)
+		}
+
+		err = writeFile(fs, fs.PathJoin(dir, file), data)
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("could not write snapshot file (file: %s): %w", file, err)
+		}
+	}
+
+	return snapshot, nil
+}
+
+func snapshotName(now time.Time) string {
+	return fmt.Sprintf("snapshot-%d", now.UTC().UnixNano())
+}
+
+func manifestKey(name string) string {
+	return name + ".json"
+}
+
+func fileKey(name string, file string) string {
+	return name + "." + file
+}
+
+func readFile(fs vfs.FS, path string) ([]byte, error) {
+
+	f, err := fs.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return io.ReadAll(f)
+}
+
+func writeFile(fs vfs.FS, path string, data []byte) error {
+
+	f, err := fs.Create(path)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(data)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	err = f.Sync()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,83 @@
+package backup_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/node/head/archive"
+	"github.com/blocklessnetwork/b7s/node/head/backup"
+)
+
+func TestBackup(t *testing.T) {
+
+	const (
+		dbDir       = "/db"
+		restoredDir = "/restored"
+		backupDir   = "/backups"
+	)
+
+	var (
+		key   = []byte("dummy-key")
+		value = []byte("dummy-value")
+	)
+
+	fs := vfs.NewMem()
+	db, err := pebble.Open(dbDir, &pebble.Options{FS: fs})
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, db.Set(key, value, pebble.Sync))
+
+	objects := archive.NewDirStore(afero.NewMemMapFs(), backupDir)
+	manager := backup.NewManager(db, fs, dbDir, objects)
+
+	t.Run("compaction works", func(t *testing.T) {
+		require.NoError(t, manager.Compact())
+
+		have, closer, err := db.Get(key)
+		require.NoError(t, err)
+		require.Equal(t, value, have)
+		closer.Close()
+	})
+	t.Run("backup can be restored", func(t *testing.T) {
+
+		snapshot, err := manager.Backup(time.Now())
+		require.NoError(t, err)
+		require.NotEmpty(t, snapshot.Files)
+
+		// Checkpoint used for the backup is removed.
+		_, err = fs.Stat(dbDir + "-" + snapshot.Name)
+		require.Error(t, err)
+
+		restored, err := backup.Restore(objects, snapshot.Name, fs, restoredDir)
+		require.NoError(t, err)
+		require.Equal(t, snapshot.Name, restored.Name)
+		require.ElementsMatch(t, snapshot.Files, restored.Files)
+
+		rdb, err := pebble.Open(restoredDir, &pebble.Options{FS: fs})
+		require.NoError(t, err)
+		defer rdb.Close()
+
+		have, closer, err := rdb.Get(key)
+		require.NoError(t, err)
+		require.Equal(t, value, have)
+		closer.Close()
+	})
+	t.Run("restore does not overwrite existing database", func(t *testing.T) {
+
+		snapshot, err := manager.Backup(time.Now())
+		require.NoError(t, err)
+
+		_, err = backup.Restore(objects, snapshot.Name, fs, dbDir)
+		require.ErrorIs(t, err, backup.ErrDatabaseExists)
+	})
+	t.Run("unknown snapshot cannot be restored", func(t *testing.T) {
+		_, err := backup.Restore(objects, "snapshot-unknown", fs, "/unknown")
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,218 @@
+package canary
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+const (
+	// DefaultMinHealthy is the fraction of sampled workers that must return the expected result for the subgroup to be healthy.
+	DefaultMinHealthy = 0.5
+	// DefaultLatencyFactor is how many times slower than usual the probe can be before the subgroup is considered degraded.
+	DefaultLatencyFactor = 3.0
+
+	// Number of healthy probes needed before latency is compared against the usual latency of the subgroup.
+	minBaselineSamples = 5
+	// Weight of the latest probe in the usual latency of the subgroup.
+	baselineWeight = 0.2
+)
+
+// Report describes the outcome of a single canary probe in a subgroup.
+type Report struct {
+	Subgroup   string
+	FunctionID string
+	Sampled    int       // Number of workers the probe was sent to.
+	Agreeing   []peer.ID // Workers that returned the result most workers agreed on.
+	Mismatched []peer.ID // Workers that returned a successful result different from the one most workers agreed on.
+	Failed     []peer.ID // Workers that returned a failed result.
+	Latency    time.Duration
+	Baseline   time.Duration // Usual probe latency in the subgroup, zero if not yet known.
+	Degraded   bool
+	Changed    bool // Changed is set if the subgroup became degraded or recovered with this probe.
+}
+
+// Healthy returns the fraction of sampled workers that returned the expected result.
+func (r Report) Healthy() float64 {
+	if r.Sampled == 0 {
+		return 0
+	}
+
+	return float64(len(r.Agreeing)) / float64(r.Sampled)
+}
+
+// Monitor keeps track of canary probes in each subgroup and decides when a subgroup degrades.
+type Monitor struct {
+	sync.Mutex
+
+	minHealthy    float64
+	latencyFactor float64
+
+	subgroups map[string]*state
+}
+
+// output is the part of the execution result compared between workers. Stderr is not compared, since it often
+// contains diagnostics that differ between hosts.
+type output struct {
+	stdout   string
+	exitCode int
+}
+
+type state struct {
+	baseline time.Duration
+	samples  uint
+	degraded bool
+}
+
+// NewMonitor creates a new Monitor.
+func NewMonitor() *Monitor {
+
+	m := Monitor{
+		minHealthy:    DefaultMinHealthy,
+		latencyFactor: DefaultLatencyFactor,
+		subgroups:     make(map[string]*state),
+	}
+
+	return &m
+}
+
+// Evaluate compares the results of the probe sent to `sampled` workers in the subgroup and updates the subgroup state.
+// Workers that did not return a result count as failed, but are not listed in the report.
+func (m *Monitor) Evaluate(subgroup string, functionID string, sampled int, results execute.ResultMap) Report {
+
+	report := Report{
+		Subgroup:   subgroup,
+		FunctionID: functionID,
+		Sampled:    sampled,
+	}
+
+	// Group successful results by their output.
+	groups := make(map[output][]peer.ID)
+	var durations []time.Duration
+	for peer, res := range results {
+
+		if res.Code != codes.OK {
+			report.Failed = append(report.Failed, peer)
+			continue
+		}
+
+		out := output{
+			stdout:   res.Result.Result.Stdout,
+			exitCode: res.Result.Result.ExitCode,
+		}
+		groups[out] = append(groups[out], peer)
+
+		duration, ok := resultDuration(res)
+		if ok {
+			durations = append(durations, duration)
+		}
+	}
+
+	var (
+		majority output
+		found    bool
+	)
+	for out, peers := range groups {
+		// Prefer the group with more peers, break ties deterministically.
+		if !found || len(peers) > len(groups[majority]) || (len(peers) == len(groups[majority]) && out.stdout < majority.stdout) {
+			majority = out
+			found = true
+		}
+	}
+
+	for out, peers := range groups {
+		if out == majority {
+			report.Agreeing = append(report.Agreeing, peers...)
+			continue
+		}
+
+		report.Mismatched = append(report.Mismatched, peers...)
+	}
+
+	slices.Sort(report.Agreeing)
+	slices.Sort(report.Mismatched)
+	slices.Sort(report.Failed)
+
+	report.Latency = median(durations)
+
+	m.Lock()
+	defer m.Unlock()
+
+	s, ok := m.subgroups[subgroup]
+	if !ok {
+		s = &state{}
+		m.subgroups[subgroup] = s
+	}
+
+	if s.samples >= minBaselineSamples {
+		report.Baseline = s.baseline
+	}
+
+	slow := report.Baseline > 0 && float64(report.Latency) > m.latencyFactor*float64(report.Baseline)
+	report.Degraded = report.Healthy() < m.minHealthy || slow
+
+	report.Changed = report.Degraded != s.degraded
+	s.degraded = report.Degraded
+
+	// Only healthy probes shape the usual latency, so that a degraded subgroup does not become the norm.
+	if !report.Degraded && report.Latency > 0 {
+		s.samples++
+		if s.samples == 1 {
+			s.baseline = report.Latency
+		} else {
+			s.baseline = time.Duration(baselineWeight*float64(report.Latency) + (1-baselineWeight)*float64(s.baseline))
+		}
+	}
+
+	return report
+}
+
+// Degraded returns the subgroups currently considered degraded.
+func (m *Monitor) Degraded() []string {
+
+	m.Lock()
+	defer m.Unlock()
+
+	var degraded []string
+	for subgroup, s := range m.subgroups {
+		if s.degraded {
+			degraded = append(degraded, subgroup)
+		}
+	}
+
+	sort.Strings(degraded)
+
+	return degraded
+}
+
+// resultDuration returns the duration of the execution - as reported by the runtime, or as measured by the worker.
+func resultDuration(res execute.NodeResult) (time.Duration, bool) {
+
+	if res.Usage.WallClockTime > 0 {
+		return res.Usage.WallClockTime, true
+	}
+
+	if res.Timing != nil && res.Timing.Execution > 0 {
+		return time.Duration(res.Timing.Execution) * time.Millisecond, true
+	}
+
+	return 0, false
+}
+
+func median(durations []time.Duration) time.Duration {
+
+	if len(durations) == 0 {
+		return 0
+	}
+
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+
+	return sorted[len(sorted)/2]
+}
@@ -0,0 +1,134 @@
+package canary_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/node/head/canary"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestMonitor(t *testing.T) {
+
+	const (
+		subgroup   = "dummy-subgroup"
+		functionID = "dummy-function-id"
+	)
+
+	var (
+		first  = mocks.GenericPeerIDs[0]
+		second = mocks.GenericPeerIDs[1]
+		third  = mocks.GenericPeerIDs[2]
+	)
+
+	result := func(code codes.Code, duration time.Duration, output string) execute.NodeResult {
+		return execute.NodeResult{
+			Result: execute.Result{
+				Code:   code,
+				Result: execute.RuntimeOutput{Stdout: output},
+				Usage:  execute.Usage{WallClockTime: duration},
+			},
+		}
+	}
+
+	healthy := func(duration time.Duration) execute.ResultMap {
+		return execute.ResultMap{
+			first:  result(codes.OK, duration, "ok"),
+			second: result(codes.OK, duration, "ok"),
+			third:  result(codes.OK, duration, "ok"),
+		}
+	}
+
+	t.Run("workers agreeing on the result", func(t *testing.T) {
+		t.Parallel()
+
+		monitor := canary.NewMonitor()
+
+		report := monitor.Evaluate(subgroup, functionID, 3, healthy(100*time.Millisecond))
+		require.False(t, report.Degraded)
+		require.False(t, report.Changed)
+		require.Len(t, report.Agreeing, 3)
+		require.Empty(t, report.Mismatched)
+		require.Empty(t, report.Failed)
+		require.Equal(t, 1.0, report.Healthy())
+		require.Equal(t, 100*time.Millisecond, report.Latency)
+		require.Empty(t, monitor.Degraded())
+	})
+	t.Run("workers returning different results are mismatched", func(t *testing.T) {
+		t.Parallel()
+
+		monitor := canary.NewMonitor()
+
+		results := healthy(100 * time.Millisecond)
+		results[third] = result(codes.OK, 100*time.Millisecond, "not ok")
+
+		report := monitor.Evaluate(subgroup, functionID, 3, results)
+		require.False(t, report.Degraded)
+		require.ElementsMatch(t, []peer.ID{first, second}, report.Agreeing)
+		require.Equal(t, []peer.ID{third}, report.Mismatched)
+	})
+	t.Run("subgroup with failing workers is degraded", func(t *testing.T) {
+		t.Parallel()
+
+		monitor := canary.NewMonitor()
+
+		results := execute.ResultMap{
+			first:  result(codes.OK, 100*time.Millisecond, "ok"),
+			second: result(codes.Error, 0, ""),
+		}
+
+		// Third worker did not respond at all.
+		report := monitor.Evaluate(subgroup, functionID, 3, results)
+		require.True(t, report.Degraded)
+		require.True(t, report.Changed)
+		require.Equal(t, []peer.ID{second}, report.Failed)
+		require.Equal(t, []string{subgroup}, monitor.Degraded())
+
+		// Degradation is only reported as a change once.
+		report = monitor.Evaluate(subgroup, functionID, 3, nil)
+		require.True(t, report.Degraded)
+		require.False(t, report.Changed)
+
+		report = monitor.Evaluate(subgroup, functionID, 3, healthy(100*time.Millisecond))
+		require.False(t, report.Degraded)
+		require.True(t, report.Changed)
+		require.Empty(t, monitor.Degraded())
+	})
+	t.Run("subgroup slowing down is degraded", func(t *testing.T) {
+		t.Parallel()
+
+		monitor := canary.NewMonitor()
+
+		// Latency is only compared once the usual latency is known.
+		report := monitor.Evaluate(subgroup, functionID, 3, healthy(100*time.Millisecond))
+		require.Zero(t, report.Baseline)
+		for i := 0; i < 10; i++ {
+			report = monitor.Evaluate(subgroup, functionID, 3, healthy(100*time.Millisecond))
+			require.False(t, report.Degraded)
+		}
+		require.Equal(t, 100*time.Millisecond, report.Baseline)
+
+		report = monitor.Evaluate(subgroup, functionID, 3, healthy(200*time.Millisecond))
+		require.False(t, report.Degraded)
+
+		report = monitor.Evaluate(subgroup, functionID, 3, healthy(time.Second))
+		require.True(t, report.Degraded)
+		require.True(t, report.Changed)
+	})
+	t.Run("subgroups are tracked separately", func(t *testing.T) {
+		t.Parallel()
+
+		monitor := canary.NewMonitor()
+
+		monitor.Evaluate(subgroup, functionID, 3, nil)
+		report := monitor.Evaluate("other-subgroup", functionID, 3, healthy(100*time.Millisecond))
+		require.False(t, report.Degraded)
+		require.False(t, report.Changed)
+		require.Equal(t, []string{subgroup}, monitor.Degraded())
+	})
+}
@@ -0,0 +1,136 @@
+package deferred
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/node/head/recovery"
+	"github.com/blocklessnetwork/b7s/store"
+)
+
+// Name of the store, used as a label for store metrics.
+const storeName = "deferred"
+
+// Entry describes an execution request held until its not-before time.
+type Entry struct {
+	Request request.Execute `json:"request"`
+	Origin  peer.ID         `json:"origin,omitempty"` // Origin is the client that sent the request.
+	Created time.Time       `json:"created,omitempty"`
+}
+
+// ID returns the ID of the deferred execution - the request ID used by the client.
+func (e Entry) ID() string {
+	return e.Request.RequestID
+}
+
+// NotBefore returns the time the request should be dispatched at.
+func (e Entry) NotBefore() time.Time {
+	return e.Request.Config.NotBefore
+}
+
+// Store persists deferred execution requests, so that they survive head node restarts.
+type Store struct {
+	sync.Mutex
+	db *pebble.DB
+}
+
+// NewStore creates a new Store backed by the given database.
+func NewStore(db *pebble.DB) *Store {
+
+	s := Store{
+		db: db,
+	}
+
+	return &s
+}
+
+// Save stores the deferred execution request, replacing an existing one with the same ID.
+func (s *Store) Save(entry Entry) error {
+
+	defer store.ObserveWrite(storeName, time.Now())
+
+	s.Lock()
+	defer s.Unlock()
+
+	encoded, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("could not encode deferred execution: %w", err)
+	}
+
+	err = s.db.Set(encodeKey(entry.ID()), encoded, pebble.Sync)
+	if err != nil {
+		return fmt.Errorf("could not store deferred execution: %w", err)
+	}
+
+	return nil
+}
+
+// Remove removes the deferred execution request.
+func (s *Store) Remove(id string) error {
+
+	defer store.ObserveWrite(storeName, time.Now())
+
+	s.Lock()
+	defer s.Unlock()
+
+	err := s.db.Delete(encodeKey(id), pebble.Sync)
+	if err != nil {
+		return fmt.Errorf("could not remove deferred execution: %w", err)
+	}
+
+	return nil
+}
+
+// Scan returns the list of all deferred execution requests that can be read, along with the list of requests that could not.
+func (s *Store) Scan() ([]Entry, []recovery.Corruption, error) {
+
+	defer store.ObserveRead(storeName, time.Now())
+
+	s.Lock()
+	defer s.Unlock()
+
+	prefix := []byte{store.PrefixDeferred, store.Separator}
+	opts := pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: []byte{store.PrefixDeferred, store.Separator + 1},
+	}
+
+	it, err := s.db.NewIter(&opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create iterator: %w", err)
+	}
+	defer it.Close()
+
+	var (
+		entries   = make([]Entry, 0)
+		corrupted []recovery.Corruption
+	)
+	for it.First(); it.Valid(); it.Next() {
+
+		var entry Entry
+		err = json.Unmarshal(it.Value(), &entry)
+		if err != nil {
+			corrupted = append(corrupted, recovery.Corruption{
+				Source: recovery.SourceDeferred,
+				ID:     string(it.Key()[len(prefix):]),
+				Reason: err.Error(),
+			})
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, corrupted, nil
+}
+
+func encodeKey(id string) []byte {
+	key := []byte{store.PrefixDeferred, store.Separator}
+	return append(key, []byte(id)...)
+}
@@ -0,0 +1,58 @@
+package deferred_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/node/head/deferred"
+	"github.com/blocklessnetwork/b7s/testing/helpers"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestStore(t *testing.T) {
+
+	db := helpers.InMemoryDB(t)
+	defer db.Close()
+
+	s := deferred.NewStore(db)
+
+	entries, corrupted, err := s.Scan()
+	require.NoError(t, err)
+	require.Empty(t, entries)
+	require.Empty(t, corrupted)
+
+	newEntry := func(id string, notBefore time.Time) deferred.Entry {
+		req := request.Execute{
+			Request:   mocks.GenericExecutionRequest,
+			RequestID: id,
+		}
+		req.Config.NotBefore = notBefore
+
+		return deferred.Entry{
+			Request: req,
+			Origin:  mocks.GenericPeerID,
+			Created: time.Now().UTC().Truncate(time.Second),
+		}
+	}
+
+	first := newEntry("dummy-request-1", time.Now().Add(time.Hour).UTC().Truncate(time.Second))
+	second := newEntry("dummy-request-2", time.Now().Add(time.Minute).UTC().Truncate(time.Second))
+
+	require.NoError(t, s.Save(first))
+	require.NoError(t, s.Save(second))
+
+	entries, corrupted, err = s.Scan()
+	require.NoError(t, err)
+	require.Empty(t, corrupted)
+	require.ElementsMatch(t, []deferred.Entry{first, second}, entries)
+	require.Equal(t, first.NotBefore(), entries[0].NotBefore())
+
+	require.NoError(t, s.Remove(first.ID()))
+
+	entries, _, err = s.Scan()
+	require.NoError(t, err)
+	require.Equal(t, []deferred.Entry{second}, entries)
+}
@@ -0,0 +1,88 @@
+package fleet
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+)
+
+const (
+	minFilterBits = 64
+	maxHashes     = 16
+
+	// MaxFilterSize is the maximum size of a function filter, in bytes. It fits the filter of ~50k functions.
+	MaxFilterSize = 64 << 10
+)
+
+// Filter is a Bloom filter, used by workers to compactly advertise the functions they have installed.
+// Membership checks may return false positives, but never false negatives.
+type Filter struct {
+	Bits   []byte `json:"bits"`
+	Hashes uint   `json:"hashes"`
+}
+
+// NewFilter creates a filter sized for the given number of items and the desired false positive rate.
+func NewFilter(items uint, falsePositiveRate float64) Filter {
+
+	n := float64(max(items, 1))
+
+	bits := uint(math.Ceil(-n * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	bits = min(max(bits, minFilterBits), MaxFilterSize*8)
+
+	hashes := uint(math.Round(float64(bits) / n * math.Ln2))
+	hashes = min(max(hashes, 1), maxHashes)
+
+	filter := Filter{
+		Bits:   make([]byte, (bits+7)/8),
+		Hashes: hashes,
+	}
+
+	return filter
+}
+
+// Add adds the item to the filter.
+func (f *Filter) Add(item string) {
+
+	if len(f.Bits) == 0 {
+		return
+	}
+
+	for _, bit := range f.positions(item) {
+		f.Bits[bit/8] |= 1 << (bit % 8)
+	}
+}
+
+// Contains returns true if the item may have been added to the filter.
+func (f Filter) Contains(item string) bool {
+
+	if len(f.Bits) == 0 {
+		return false
+	}
+
+	for _, bit := range f.positions(item) {
+		if f.Bits[bit/8]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// positions returns the bits set for the item, derived from two 64-bit parts of a single hash.
+func (f Filter) positions(item string) []uint64 {
+
+	sum := sha256.Sum256([]byte(item))
+
+	h1, h2 := binary.BigEndian.Uint64(sum[:8]), binary.BigEndian.Uint64(sum[8:16])|1
+	size := uint64(len(f.Bits)) * 8
+
+	// Filters are received from other peers, so do not trust the number of hashes to be sane.
+	hashes := uint64(min(max(f.Hashes, 1), maxHashes))
+
+	positions := make([]uint64, 0, hashes)
+	for i := uint64(0); i < hashes; i++ {
+		positions = append(positions, (h1+i*h2)%size)
+	}
+
+	return positions
+}
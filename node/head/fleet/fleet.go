@@ -0,0 +1,226 @@
+package fleet
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// FalsePositiveRate is the false positive rate workers size their function filters for.
+const FalsePositiveRate = 0.01
+
+var (
+	ErrFilterTooLarge = errors.New("function filter too large")
+	ErrIndexFull      = errors.New("fleet index full")
+)
+
+// Report is the compact summary a worker periodically publishes on the fleet topic.
+type Report struct {
+	Functions     Filter   `json:"functions"`      // Functions the worker has installed.
+	FunctionCount uint     `json:"function_count"` // Number of functions added to the filter.
+	Capacity      Capacity `json:"capacity"`
+}
+
+// Capacity summarizes how much work the worker can take on.
+type Capacity struct {
+	Concurrency     uint   `json:"concurrency"`                // Number of requests the worker processes in parallel.
+	Executions      uint   `json:"executions"`                 // Number of executions in progress.
+	Queued          uint   `json:"queued,omitempty"`           // Number of requests waiting for a processing slot.
+	MemoryAvailable uint64 `json:"memory_available,omitempty"` // Available memory (kB). Zero if unknown.
+}
+
+// Free returns the number of requests the worker can start processing right away.
+func (c Capacity) Free() uint {
+
+	busy := c.Executions + c.Queued
+	if busy >= c.Concurrency {
+		return 0
+	}
+
+	return c.Concurrency - busy
+}
+
+// Plan describes how the head node would go about executing a function, without executing it.
+type Plan struct {
+	FunctionID string   `json:"function_id"`
+	NodeCount  int      `json:"node_count"`
+	Workers    uint     `json:"workers"`              // Workers with a recent fleet report.
+	Installed  uint     `json:"installed"`            // Workers that likely have the function installed.
+	FreeSlots  uint     `json:"free_slots"`           // Free processing slots on workers that likely have the function installed.
+	Candidates []string `json:"candidates,omitempty"` // Workers the head node would prefer, best first.
+	Feasible   bool     `json:"feasible"`             // Are there enough free workers with the function installed.
+	// Cost is the estimated relative cost of executing the function now, in the 0-1 range. It is the share of candidates'
+	// capacity already in use, with workers that need to install the function counting as fully used.
+	Cost float64 `json:"cost"`
+}
+
+// Index tracks the latest fleet reports of workers.
+type Index struct {
+	sync.Mutex
+
+	ttl     time.Duration
+	limit   uint
+	reports map[peer.ID]entry
+}
+
+type entry struct {
+	report   Report
+	received time.Time
+}
+
+// NewIndex creates a new fleet index. Reports older than the given TTL are ignored.
+// The index holds reports of at most `limit` workers, zero means no limit.
+func NewIndex(ttl time.Duration, limit uint) *Index {
+
+	index := Index{
+		ttl:     ttl,
+		limit:   limit,
+		reports: make(map[peer.ID]entry),
+	}
+
+	return &index
+}
+
+// Record saves the latest report of the worker. Reports with oversized filters are rejected, as are reports of new
+// workers once the index is full.
+func (i *Index) Record(id peer.ID, report Report) error {
+	i.Lock()
+	defer i.Unlock()
+
+	if len(report.Functions.Bits) > MaxFilterSize {
+		return fmt.Errorf("%w (size: %d, limit: %d)", ErrFilterTooLarge, len(report.Functions.Bits), MaxFilterSize)
+	}
+
+	_, known := i.reports[id]
+	if !known && i.limit > 0 && uint(len(i.reports)) >= i.limit {
+
+		// Make room by dropping expired reports first.
+		for id, e := range i.reports {
+			if i.expired(e) {
+				delete(i.reports, id)
+			}
+		}
+
+		if uint(len(i.reports)) >= i.limit {
+			return ErrIndexFull
+		}
+	}
+
+	i.reports[id] = entry{
+		report:   report,
+		received: time.Now(),
+	}
+
+	return nil
+}
+
+// Remove forgets the report of the worker.
+func (i *Index) Remove(id peer.ID) {
+	i.Lock()
+	defer i.Unlock()
+
+	delete(i.reports, id)
+}
+
+// Installed returns true if, according to its latest report, the worker likely has the function installed.
+// Known is false if there is no recent report for the worker.
+func (i *Index) Installed(id peer.ID, functionID string) (installed bool, known bool) {
+	i.Lock()
+	defer i.Unlock()
+
+	e, ok := i.reports[id]
+	if !ok || i.expired(e) {
+		return false, false
+	}
+
+	return e.report.Functions.Contains(functionID), true
+}
+
+// Plan returns the execution plan for the function, based on the latest worker reports.
+// Node count of -1 means any number of workers can execute the function. If set, only workers accepted by the eligible
+// function are considered.
+func (i *Index) Plan(functionID string, nodeCount int, eligible func(peer.ID) bool) Plan {
+	i.Lock()
+	defer i.Unlock()
+
+	plan := Plan{
+		FunctionID: functionID,
+		NodeCount:  nodeCount,
+	}
+
+	type candidate struct {
+		id       peer.ID
+		capacity Capacity
+	}
+
+	var candidates []candidate
+	for id, e := range i.reports {
+
+		if i.expired(e) {
+			delete(i.reports, id)
+			continue
+		}
+
+		if eligible != nil && !eligible(id) {
+			continue
+		}
+
+		plan.Workers++
+
+		if !e.report.Functions.Contains(functionID) {
+			continue
+		}
+
+		plan.Installed++
+		plan.FreeSlots += e.report.Capacity.Free()
+
+		if e.report.Capacity.Free() > 0 {
+			candidates = append(candidates, candidate{id: id, capacity: e.report.Capacity})
+		}
+	}
+
+	// Prefer workers with the most free slots.
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		if a.capacity.Free() != b.capacity.Free() {
+			return int(b.capacity.Free()) - int(a.capacity.Free())
+		}
+		return strings.Compare(a.id.String(), b.id.String())
+	})
+
+	want := len(candidates)
+	if nodeCount > 0 {
+		want = min(want, nodeCount)
+	}
+
+	var used, total uint
+	for _, c := range candidates[:want] {
+		plan.Candidates = append(plan.Candidates, c.id.String())
+		used += c.capacity.Concurrency - c.capacity.Free()
+		total += c.capacity.Concurrency
+	}
+
+	plan.Feasible = want > 0 && (nodeCount <= 0 || want >= nodeCount)
+
+	// Workers we are missing would have to install the function first.
+	if nodeCount > want {
+		used += uint(nodeCount - want)
+		total += uint(nodeCount - want)
+	}
+
+	if total > 0 {
+		plan.Cost = float64(used) / float64(total)
+	} else {
+		plan.Cost = 1
+	}
+
+	return plan
+}
+
+func (i *Index) expired(e entry) bool {
+	return i.ttl > 0 && time.Since(e.received) > i.ttl
+}
@@ -0,0 +1,155 @@
+package fleet_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/node/head/fleet"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestFilter(t *testing.T) {
+
+	const count = 100
+
+	filter := fleet.NewFilter(count, fleet.FalsePositiveRate)
+	for i := 0; i < count; i++ {
+		filter.Add(fmt.Sprintf("function-%v", i))
+	}
+
+	for i := 0; i < count; i++ {
+		require.True(t, filter.Contains(fmt.Sprintf("function-%v", i)))
+	}
+
+	var positives int
+	for i := 0; i < 1000; i++ {
+		if filter.Contains(fmt.Sprintf("other-function-%v", i)) {
+			positives++
+		}
+	}
+	require.Less(t, positives, 50)
+
+	var empty fleet.Filter
+	require.False(t, empty.Contains("function-0"))
+}
+
+func TestIndex_Plan(t *testing.T) {
+
+	const functionID = "function"
+
+	report := func(installed bool, concurrency uint, executions uint) fleet.Report {
+
+		filter := fleet.NewFilter(1, fleet.FalsePositiveRate)
+		if installed {
+			filter.Add(functionID)
+		}
+
+		return fleet.Report{
+			Functions:     filter,
+			FunctionCount: 1,
+			Capacity: fleet.Capacity{
+				Concurrency: concurrency,
+				Executions:  executions,
+			},
+		}
+	}
+
+	index := fleet.NewIndex(0, 0)
+	require.NoError(t, index.Record(mocks.GenericPeerIDs[0], report(true, 10, 8)))
+	require.NoError(t, index.Record(mocks.GenericPeerIDs[1], report(true, 10, 2)))
+	require.NoError(t, index.Record(mocks.GenericPeerIDs[2], report(false, 10, 0)))
+	require.NoError(t, index.Record(mocks.GenericPeerIDs[3], report(true, 10, 10)))
+
+	installed, known := index.Installed(mocks.GenericPeerIDs[2], functionID)
+	require.True(t, known)
+	require.False(t, installed)
+
+	_, known = index.Installed(mocks.GenericPeerIDs[4], functionID)
+	require.False(t, known)
+
+	t.Run("enough workers", func(t *testing.T) {
+		plan := index.Plan(functionID, 2, nil)
+
+		require.Equal(t, uint(4), plan.Workers)
+		require.Equal(t, uint(3), plan.Installed)
+		require.Equal(t, uint(10), plan.FreeSlots)
+		require.Equal(t, []string{mocks.GenericPeerIDs[1].String(), mocks.GenericPeerIDs[0].String()}, plan.Candidates)
+		require.True(t, plan.Feasible)
+		require.InDelta(t, 0.5, plan.Cost, 0.001)
+	})
+	t.Run("not enough workers", func(t *testing.T) {
+		plan := index.Plan(functionID, 4, nil)
+
+		require.Len(t, plan.Candidates, 2)
+		require.False(t, plan.Feasible)
+		// Two missing workers count as fully used.
+		require.InDelta(t, 12.0/22.0, plan.Cost, 0.001)
+	})
+	t.Run("eligible workers only", func(t *testing.T) {
+		plan := index.Plan(functionID, 2, func(id peer.ID) bool {
+			return id != mocks.GenericPeerIDs[1]
+		})
+
+		require.Equal(t, uint(3), plan.Workers)
+		require.Equal(t, uint(2), plan.Installed)
+		require.Equal(t, []string{mocks.GenericPeerIDs[0].String()}, plan.Candidates)
+		require.False(t, plan.Feasible)
+	})
+	t.Run("unknown function", func(t *testing.T) {
+		plan := index.Plan("other-function", 1, nil)
+
+		require.Zero(t, plan.Installed)
+		require.Empty(t, plan.Candidates)
+		require.False(t, plan.Feasible)
+		require.Equal(t, 1.0, plan.Cost)
+	})
+}
+
+func TestIndex_Record(t *testing.T) {
+
+	report := fleet.Report{
+		Functions:     fleet.NewFilter(1, fleet.FalsePositiveRate),
+		FunctionCount: 1,
+	}
+
+	t.Run("oversized filters are rejected", func(t *testing.T) {
+
+		index := fleet.NewIndex(0, 0)
+
+		oversized := report
+		oversized.Functions.Bits = make([]byte, fleet.MaxFilterSize+1)
+
+		err := index.Record(mocks.GenericPeerIDs[0], oversized)
+		require.ErrorIs(t, err, fleet.ErrFilterTooLarge)
+
+		_, known := index.Installed(mocks.GenericPeerIDs[0], "function")
+		require.False(t, known)
+	})
+	t.Run("number of indexed workers is capped", func(t *testing.T) {
+
+		index := fleet.NewIndex(0, 2)
+
+		require.NoError(t, index.Record(mocks.GenericPeerIDs[0], report))
+		require.NoError(t, index.Record(mocks.GenericPeerIDs[1], report))
+
+		err := index.Record(mocks.GenericPeerIDs[2], report)
+		require.ErrorIs(t, err, fleet.ErrIndexFull)
+
+		// Known workers can still update their reports.
+		require.NoError(t, index.Record(mocks.GenericPeerIDs[0], report))
+	})
+	t.Run("expired reports make room for new workers", func(t *testing.T) {
+
+		const ttl = 20 * time.Millisecond
+
+		index := fleet.NewIndex(ttl, 1)
+
+		require.NoError(t, index.Record(mocks.GenericPeerIDs[0], report))
+		time.Sleep(2 * ttl)
+		require.NoError(t, index.Record(mocks.GenericPeerIDs[1], report))
+	})
+}
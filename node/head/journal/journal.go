@@ -0,0 +1,164 @@
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/node/head/recovery"
+	"github.com/blocklessnetwork/b7s/store"
+)
+
+// Name of the store, used as a label for store metrics.
+const storeName = "journal"
+
+// Phase describes how far the head node got with processing an execution request.
+type Phase string
+
+const (
+	PhaseRollCall         Phase = "roll-call"
+	PhaseClusterFormation Phase = "cluster-formation"
+	PhaseExecution        Phase = "execution"
+)
+
+// Entry describes a pending execution request.
+type Entry struct {
+	RequestID       string         `json:"request_id"`
+	FunctionID      string         `json:"function_id,omitempty"`
+	Consensus       consensus.Type `json:"consensus,omitempty"`
+	Phase           Phase          `json:"phase,omitempty"`
+	Peers           []peer.ID      `json:"peers,omitempty"`
+	Origin          peer.ID        `json:"origin,omitempty"`            // Origin is the client that sent the request, if it was received as a message.
+	ClientRequestID string         `json:"client_request_id,omitempty"` // ClientRequestID is the request ID used in the response to the origin.
+	Started         time.Time      `json:"started,omitempty"`
+	Updated         time.Time      `json:"updated,omitempty"`
+}
+
+// Journal persists pending execution requests, so that they are not silently lost if the head node restarts.
+type Journal struct {
+	sync.Mutex
+	db *pebble.DB
+}
+
+// New creates a new Journal backed by the given database.
+func New(db *pebble.DB) *Journal {
+
+	j := Journal{
+		db: db,
+	}
+
+	return &j
+}
+
+// Update applies the given change to the journal entry for the request, creating the entry if needed.
+func (j *Journal) Update(requestID string, update func(*Entry)) error {
+
+	defer store.ObserveWrite(storeName, time.Now())
+
+	j.Lock()
+	defer j.Unlock()
+
+	key := encodeKey(requestID)
+
+	entry := Entry{
+		RequestID: requestID,
+		Started:   time.Now().UTC(),
+	}
+
+	value, closer, err := j.db.Get(key)
+	if err != nil && !errors.Is(err, pebble.ErrNotFound) {
+		return fmt.Errorf("could not retrieve journal entry: %w", err)
+	}
+	if err == nil {
+		err = json.Unmarshal(value, &entry)
+		closer.Close()
+		if err != nil {
+			return fmt.Errorf("could not decode journal entry: %w", err)
+		}
+	}
+
+	update(&entry)
+	entry.Updated = time.Now().UTC()
+
+	encoded, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("could not encode journal entry: %w", err)
+	}
+
+	err = j.db.Set(key, encoded, pebble.Sync)
+	if err != nil {
+		return fmt.Errorf("could not store journal entry: %w", err)
+	}
+
+	return nil
+}
+
+// Remove removes the journal entry for the request.
+func (j *Journal) Remove(requestID string) error {
+
+	defer store.ObserveWrite(storeName, time.Now())
+
+	j.Lock()
+	defer j.Unlock()
+
+	err := j.db.Delete(encodeKey(requestID), pebble.Sync)
+	if err != nil {
+		return fmt.Errorf("could not remove journal entry: %w", err)
+	}
+
+	return nil
+}
+
+// Scan returns the list of all pending requests that can be read, along with the list of journal entries that could not.
+func (j *Journal) Scan() ([]Entry, []recovery.Corruption, error) {
+
+	defer store.ObserveRead(storeName, time.Now())
+
+	j.Lock()
+	defer j.Unlock()
+
+	prefix := []byte{store.PrefixJournal, store.Separator}
+	opts := pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: []byte{store.PrefixJournal, store.Separator + 1},
+	}
+
+	it, err := j.db.NewIter(&opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create iterator: %w", err)
+	}
+	defer it.Close()
+
+	var (
+		entries   = make([]Entry, 0)
+		corrupted []recovery.Corruption
+	)
+	for it.First(); it.Valid(); it.Next() {
+
+		var entry Entry
+		err = json.Unmarshal(it.Value(), &entry)
+		if err != nil {
+			corrupted = append(corrupted, recovery.Corruption{
+				Source: recovery.SourceJournal,
+				ID:     string(it.Key()[len(prefix):]),
+				Reason: err.Error(),
+			})
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, corrupted, nil
+}
+
+func encodeKey(requestID string) []byte {
+	key := []byte{store.PrefixJournal, store.Separator}
+	return append(key, []byte(requestID)...)
+}
@@ -0,0 +1,130 @@
+package journal_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/node/head/journal"
+	"github.com/blocklessnetwork/b7s/node/head/recovery"
+	"github.com/blocklessnetwork/b7s/store"
+	"github.com/blocklessnetwork/b7s/store/codec"
+	"github.com/blocklessnetwork/b7s/testing/helpers"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestJournal(t *testing.T) {
+
+	const (
+		requestID = "dummy-request-id"
+	)
+
+	t.Run("entry is created and updated", func(t *testing.T) {
+		t.Parallel()
+
+		db := helpers.InMemoryDB(t)
+		defer db.Close()
+
+		j := journal.New(db)
+
+		err := j.Update(requestID, func(e *journal.Entry) {
+			e.FunctionID = mocks.GenericFunctionRecord.CID
+			e.Consensus = consensus.Raft
+			e.Phase = journal.PhaseRollCall
+		})
+		require.NoError(t, err)
+
+		err = j.Update(requestID, func(e *journal.Entry) {
+			e.Peers = mocks.GenericPeerIDs[:2]
+			e.Phase = journal.PhaseClusterFormation
+		})
+		require.NoError(t, err)
+
+		pending, _, err := j.Scan()
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+
+		entry := pending[0]
+		require.Equal(t, requestID, entry.RequestID)
+		require.Equal(t, mocks.GenericFunctionRecord.CID, entry.FunctionID)
+		require.Equal(t, consensus.Raft, entry.Consensus)
+		require.Equal(t, journal.PhaseClusterFormation, entry.Phase)
+		require.Equal(t, mocks.GenericPeerIDs[:2], entry.Peers)
+		require.False(t, entry.Started.IsZero())
+		require.False(t, entry.Updated.Before(entry.Started))
+	})
+	t.Run("removed entries are not pending", func(t *testing.T) {
+		t.Parallel()
+
+		db := helpers.InMemoryDB(t)
+		defer db.Close()
+
+		j := journal.New(db)
+
+		for _, id := range []string{"request-1", "request-2"} {
+			err := j.Update(id, func(e *journal.Entry) { e.Phase = journal.PhaseExecution })
+			require.NoError(t, err)
+		}
+
+		err := j.Remove("request-1")
+		require.NoError(t, err)
+
+		pending, _, err := j.Scan()
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		require.Equal(t, "request-2", pending[0].RequestID)
+	})
+	t.Run("journal coexists with the store", func(t *testing.T) {
+		t.Parallel()
+
+		db := helpers.InMemoryDB(t)
+		defer db.Close()
+
+		s := store.New(db, codec.NewJSONCodec())
+		j := journal.New(db)
+
+		for _, peer := range helpers.CreateRandomPeers(t, 3) {
+			err := s.SavePeer(context.Background(), peer)
+			require.NoError(t, err)
+		}
+
+		pending, _, err := j.Scan()
+		require.NoError(t, err)
+		require.Empty(t, pending)
+
+		err = j.Update(requestID, func(e *journal.Entry) { e.Phase = journal.PhaseExecution })
+		require.NoError(t, err)
+
+		peers, err := s.RetrievePeers(context.Background())
+		require.NoError(t, err)
+		require.Len(t, peers, 3)
+	})
+	t.Run("corrupted entries are reported", func(t *testing.T) {
+		t.Parallel()
+
+		db := helpers.InMemoryDB(t)
+		defer db.Close()
+
+		j := journal.New(db)
+
+		err := j.Update(requestID, func(e *journal.Entry) { e.Phase = journal.PhaseExecution })
+		require.NoError(t, err)
+
+		key := append([]byte{store.PrefixJournal, store.Separator}, []byte("corrupted-request")...)
+		err = db.Set(key, []byte("{not-json"), pebble.Sync)
+		require.NoError(t, err)
+
+		entries, corrupted, err := j.Scan()
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, requestID, entries[0].RequestID)
+
+		require.Len(t, corrupted, 1)
+		require.Equal(t, recovery.SourceJournal, corrupted[0].Source)
+		require.Equal(t, "corrupted-request", corrupted[0].ID)
+		require.NotEmpty(t, corrupted[0].Reason)
+	})
+}
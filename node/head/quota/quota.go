@@ -0,0 +1,215 @@
+package quota
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/blocklessnetwork/b7s/b7serrors"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/store"
+)
+
+// Name of the store, used as a label for store metrics.
+const storeName = "quota"
+
+const (
+	executionWindow = time.Hour
+	cpuWindow       = 24 * time.Hour
+)
+
+// Limits describes the quota each client has. Zero values mean no limit.
+type Limits struct {
+	ExecutionsPerHour uint          // Number of executions a client can have in an hour.
+	CPUTimePerDay     time.Duration // Total CPU time (user and system, across all nodes) a client can use in a day.
+}
+
+// counter is the persisted quota usage of a client for the current windows.
+type counter struct {
+	ExecutionWindow time.Time     `json:"execution_window"`
+	Executions      uint          `json:"executions"`
+	CPUWindow       time.Time     `json:"cpu_window"`
+	CPUTime         time.Duration `json:"cpu_time"`
+}
+
+// Tracker keeps track of client quota usage. Usage is persisted, so quotas survive head node restarts.
+type Tracker struct {
+	sync.Mutex
+
+	db     *pebble.DB
+	limits Limits
+}
+
+// NewTracker creates a new Tracker backed by the given database.
+func NewTracker(db *pebble.DB, limits Limits) *Tracker {
+
+	t := Tracker{
+		db:     db,
+		limits: limits,
+	}
+
+	return &t
+}
+
+// Admit records a new execution for the client. If the client has used up its quota, the execution is not recorded
+// and an error wrapping `b7serrors.ErrQuotaExceeded` is returned.
+func (t *Tracker) Admit(client string, now time.Time) (execute.QuotaRemaining, error) {
+
+	defer store.ObserveWrite(storeName, time.Now())
+
+	t.Lock()
+	defer t.Unlock()
+
+	c, err := t.get(client, now)
+	if err != nil {
+		return execute.QuotaRemaining{}, err
+	}
+
+	if t.limits.ExecutionsPerHour > 0 && c.Executions >= t.limits.ExecutionsPerHour {
+		return t.remaining(client, c), fmt.Errorf("%w: execution limit reached (limit: %v)", b7serrors.ErrQuotaExceeded, t.limits.ExecutionsPerHour)
+	}
+
+	if t.limits.CPUTimePerDay > 0 && c.CPUTime >= t.limits.CPUTimePerDay {
+		return t.remaining(client, c), fmt.Errorf("%w: CPU time limit reached (limit: %v)", b7serrors.ErrQuotaExceeded, t.limits.CPUTimePerDay)
+	}
+
+	c.Executions++
+
+	err = t.save(client, c)
+	if err != nil {
+		return execute.QuotaRemaining{}, err
+	}
+
+	return t.remaining(client, c), nil
+}
+
+// RecordCPU adds the CPU time used by an execution to the client usage.
+func (t *Tracker) RecordCPU(client string, now time.Time, cpu time.Duration) error {
+
+	defer store.ObserveWrite(storeName, time.Now())
+
+	t.Lock()
+	defer t.Unlock()
+
+	c, err := t.get(client, now)
+	if err != nil {
+		return err
+	}
+
+	c.CPUTime += cpu
+
+	return t.save(client, c)
+}
+
+// Remaining returns the quota the client has left.
+func (t *Tracker) Remaining(client string, now time.Time) (execute.QuotaRemaining, error) {
+
+	defer store.ObserveRead(storeName, time.Now())
+
+	t.Lock()
+	defer t.Unlock()
+
+	c, err := t.get(client, now)
+	if err != nil {
+		return execute.QuotaRemaining{}, err
+	}
+
+	return t.remaining(client, c), nil
+}
+
+// remaining computes the quota left based on the client usage.
+func (t *Tracker) remaining(client string, c counter) execute.QuotaRemaining {
+
+	r := execute.QuotaRemaining{
+		Client: client,
+	}
+
+	if t.limits.ExecutionsPerHour > 0 {
+
+		var left uint
+		if c.Executions < t.limits.ExecutionsPerHour {
+			left = t.limits.ExecutionsPerHour - c.Executions
+		}
+		reset := c.ExecutionWindow.Add(executionWindow)
+
+		r.Executions = &left
+		r.ExecutionsReset = &reset
+	}
+
+	if t.limits.CPUTimePerDay > 0 {
+
+		var left time.Duration
+		if c.CPUTime < t.limits.CPUTimePerDay {
+			left = t.limits.CPUTimePerDay - c.CPUTime
+		}
+		seconds := left.Seconds()
+		reset := c.CPUWindow.Add(cpuWindow)
+
+		r.CPUSeconds = &seconds
+		r.CPUReset = &reset
+	}
+
+	return r
+}
+
+// get returns the client usage for the windows the given time belongs to.
+// NOTE: Caller should hold the lock.
+func (t *Tracker) get(client string, now time.Time) (counter, error) {
+
+	var (
+		executionStart = now.UTC().Truncate(executionWindow)
+		cpuStart       = now.UTC().Truncate(cpuWindow)
+	)
+
+	var c counter
+	value, closer, err := t.db.Get(encodeKey(client))
+	switch {
+	case errors.Is(err, pebble.ErrNotFound):
+	case err != nil:
+		return counter{}, fmt.Errorf("could not retrieve quota usage: %w", err)
+	default:
+		err = json.Unmarshal(value, &c)
+		closer.Close()
+		if err != nil {
+			return counter{}, fmt.Errorf("could not decode quota usage: %w", err)
+		}
+	}
+
+	// Usage from previous windows does not count.
+	if !c.ExecutionWindow.Equal(executionStart) {
+		c.ExecutionWindow = executionStart
+		c.Executions = 0
+	}
+	if !c.CPUWindow.Equal(cpuStart) {
+		c.CPUWindow = cpuStart
+		c.CPUTime = 0
+	}
+
+	return c, nil
+}
+
+// save persists the client usage.
+// NOTE: Caller should hold the lock.
+func (t *Tracker) save(client string, c counter) error {
+
+	encoded, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("could not encode quota usage: %w", err)
+	}
+
+	err = t.db.Set(encodeKey(client), encoded, pebble.Sync)
+	if err != nil {
+		return fmt.Errorf("could not store quota usage: %w", err)
+	}
+
+	return nil
+}
+
+func encodeKey(client string) []byte {
+	key := []byte{store.PrefixQuota, store.Separator}
+	return append(key, []byte(client)...)
+}
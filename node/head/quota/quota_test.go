@@ -0,0 +1,92 @@
+package quota_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/b7serrors"
+	"github.com/blocklessnetwork/b7s/node/head/quota"
+	"github.com/blocklessnetwork/b7s/testing/helpers"
+)
+
+func TestTracker(t *testing.T) {
+
+	const (
+		client = "dummy-client"
+	)
+
+	now := time.Date(2024, time.March, 1, 10, 30, 0, 0, time.UTC)
+
+	t.Run("execution limit is enforced within the hour", func(t *testing.T) {
+		t.Parallel()
+
+		db := helpers.InMemoryDB(t)
+		defer db.Close()
+
+		tracker := quota.NewTracker(db, quota.Limits{ExecutionsPerHour: 2})
+
+		remaining, err := tracker.Admit(client, now)
+		require.NoError(t, err)
+		require.Equal(t, uint(1), *remaining.Executions)
+		require.Nil(t, remaining.CPUSeconds)
+
+		_, err = tracker.Admit(client, now)
+		require.NoError(t, err)
+
+		remaining, err = tracker.Admit(client, now)
+		require.ErrorIs(t, err, b7serrors.ErrQuotaExceeded)
+		require.Equal(t, uint(0), *remaining.Executions)
+		require.Equal(t, time.Date(2024, time.March, 1, 11, 0, 0, 0, time.UTC), *remaining.ExecutionsReset)
+
+		// Quota is per client.
+		_, err = tracker.Admit("other-client", now)
+		require.NoError(t, err)
+
+		// Quota resets with the next hour.
+		_, err = tracker.Admit(client, now.Add(time.Hour))
+		require.NoError(t, err)
+	})
+	t.Run("CPU time limit is enforced within the day", func(t *testing.T) {
+		t.Parallel()
+
+		db := helpers.InMemoryDB(t)
+		defer db.Close()
+
+		tracker := quota.NewTracker(db, quota.Limits{CPUTimePerDay: 10 * time.Second})
+
+		_, err := tracker.Admit(client, now)
+		require.NoError(t, err)
+
+		require.NoError(t, tracker.RecordCPU(client, now, 4*time.Second))
+
+		remaining, err := tracker.Remaining(client, now)
+		require.NoError(t, err)
+		require.Nil(t, remaining.Executions)
+		require.Equal(t, float64(6), *remaining.CPUSeconds)
+
+		require.NoError(t, tracker.RecordCPU(client, now, 6*time.Second))
+
+		_, err = tracker.Admit(client, now)
+		require.ErrorIs(t, err, b7serrors.ErrQuotaExceeded)
+
+		// Quota resets with the next day.
+		_, err = tracker.Admit(client, now.Add(24*time.Hour))
+		require.NoError(t, err)
+	})
+	t.Run("usage survives restarts", func(t *testing.T) {
+		t.Parallel()
+
+		db := helpers.InMemoryDB(t)
+		defer db.Close()
+
+		limits := quota.Limits{ExecutionsPerHour: 1}
+
+		_, err := quota.NewTracker(db, limits).Admit(client, now)
+		require.NoError(t, err)
+
+		_, err = quota.NewTracker(db, limits).Admit(client, now)
+		require.ErrorIs(t, err, b7serrors.ErrQuotaExceeded)
+	})
+}
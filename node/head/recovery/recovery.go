@@ -0,0 +1,34 @@
+package recovery
+
+import (
+	"time"
+)
+
+// Sources of persisted head node state.
+const (
+	SourceJournal   = "journal"
+	SourceSchedules = "schedules"
+	SourceResults   = "results"
+	SourceDeferred  = "deferred"
+)
+
+// Report describes the state the head node found when it started.
+type Report struct {
+	Started            time.Time    `json:"started"`
+	AbandonedRequests  uint         `json:"abandoned_requests"`  // Requests left pending by the previous run, aborted on startup.
+	NotifiedClients    uint         `json:"notified_clients"`    // Clients notified about their request being aborted.
+	RecoveredSchedules uint         `json:"recovered_schedules"` // Execution schedules restored from the previous run.
+	RecoveredDeferred  uint         `json:"recovered_deferred"`  // Deferred execution requests restored from the previous run.
+	StoredResults      uint         `json:"stored_results"`      // Execution results available from the database.
+	ArchivedResults    uint         `json:"archived_results"`    // Execution results available from the result archive.
+	KnownPeers         uint         `json:"known_peers"`         // Peers from the peer store the head node tried to reach on startup.
+	ReadyWorkers       uint         `json:"ready_workers"`       // Workers that answered the startup health query, with valid attributes.
+	Corrupted          []Corruption `json:"corrupted,omitempty"`
+}
+
+// Corruption describes a persisted record that could not be read.
+type Corruption struct {
+	Source string `json:"source"`
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+}
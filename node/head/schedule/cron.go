@@ -0,0 +1,197 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a parsed cron expression, in the standard five field format:
+// minute, hour, day of month, month and day of week.
+//
+// Each field can be a wildcard (*), a value (5), a range (1-5), a list (1,3,5) or a step (*/15, 0-30/10).
+type Cron struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+
+	// If either of the day fields is restricted, a day matches if it matches either of them, per cron convention.
+	domWildcard bool
+	dowWildcard bool
+}
+
+type field struct {
+	name string
+	min  uint
+	max  uint
+}
+
+var fields = []field{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 6},
+}
+
+// maxSearch limits how far into the future we look for the next activation.
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// Parse parses the cron expression.
+func Parse(expr string) (Cron, error) {
+
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return Cron{}, fmt.Errorf("cron expression must have %v fields, have %v", len(fields), len(parts))
+	}
+
+	values := make([]uint64, len(fields))
+	for i, part := range parts {
+		v, err := parseField(part, fields[i])
+		if err != nil {
+			return Cron{}, fmt.Errorf("invalid %s field (value: %s): %w", fields[i].name, part, err)
+		}
+		values[i] = v
+	}
+
+	cron := Cron{
+		minute:      values[0],
+		hour:        values[1],
+		dayOfMonth:  values[2],
+		month:       values[3],
+		dayOfWeek:   values[4],
+		domWildcard: parts[2] == "*",
+		dowWildcard: parts[4] == "*",
+	}
+
+	return cron, nil
+}
+
+// Next returns the first activation time after the given time. Returns zero time if there isn't one.
+func (c Cron) Next(after time.Time) time.Time {
+
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.Add(maxSearch)
+
+	for t.Before(limit) {
+
+		if !has(c.month, uint(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !c.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !has(c.hour, uint(t.Hour())) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !has(c.minute, uint(t.Minute())) {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (c Cron) matchDay(t time.Time) bool {
+
+	dom := has(c.dayOfMonth, uint(t.Day()))
+	dow := has(c.dayOfWeek, uint(t.Weekday()))
+
+	switch {
+	case c.domWildcard && c.dowWildcard:
+		return true
+	case c.domWildcard:
+		return dow
+	case c.dowWildcard:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// parseField returns a bitset of the values the field matches.
+func parseField(value string, f field) (uint64, error) {
+
+	var set uint64
+	for _, item := range strings.Split(value, ",") {
+
+		if item == "" {
+			return 0, errors.New("empty list item")
+		}
+
+		rng, stepValue, hasStep := strings.Cut(item, "/")
+
+		step := uint(1)
+		if hasStep {
+			s, err := strconv.ParseUint(stepValue, 10, 8)
+			if err != nil || s == 0 {
+				return 0, fmt.Errorf("invalid step (value: %s)", stepValue)
+			}
+			step = uint(s)
+		}
+
+		lo, hi := f.min, f.max
+		if rng != "*" {
+
+			start, end, isRange := strings.Cut(rng, "-")
+
+			var err error
+			lo, err = parseValue(start, f)
+			if err != nil {
+				return 0, err
+			}
+
+			hi = lo
+			if isRange {
+				hi, err = parseValue(end, f)
+				if err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				// A single value with a step, e.g. `5/15`, means starting from the value.
+				hi = f.max
+			}
+
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range (start: %v, end: %v)", lo, hi)
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			set |= 1 << v
+		}
+	}
+
+	return set, nil
+}
+
+func parseValue(value string, f field) (uint, error) {
+
+	v, err := strconv.ParseUint(value, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value (value: %s)", value)
+	}
+
+	if uint(v) < f.min || uint(v) > f.max {
+		return 0, fmt.Errorf("value out of range (value: %v, min: %v, max: %v)", v, f.min, f.max)
+	}
+
+	return uint(v), nil
+}
+
+func has(set uint64, v uint) bool {
+	return set&(1<<v) != 0
+}
@@ -0,0 +1,80 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/node/head/schedule"
+)
+
+func TestCron_Parse(t *testing.T) {
+
+	valid := []string{
+		"* * * * *",
+		"*/15 * * * *",
+		"0 0 1 1 *",
+		"0-30/10 8-17 * * 1-5",
+		"5,10,15 * * * 0",
+		"5/20 * * * *",
+	}
+
+	for _, expr := range valid {
+		_, err := schedule.Parse(expr)
+		require.NoError(t, err, "expression: %s", expr)
+	}
+
+	invalid := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 7",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"1,,2 * * * *",
+		"a * * * *",
+	}
+
+	for _, expr := range invalid {
+		_, err := schedule.Parse(expr)
+		require.Error(t, err, "expression: %s", expr)
+	}
+}
+
+func TestCron_Next(t *testing.T) {
+
+	// Wednesday.
+	start := time.Date(2024, time.January, 10, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.January, 10, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.January, 10, 10, 30, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2024, time.January, 10, 11, 0, 0, 0, time.UTC)},
+		{"30 9 * * *", time.Date(2024, time.January, 11, 9, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * 0", time.Date(2024, time.January, 14, 12, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// Either of the restricted day fields matches.
+		{"0 0 15 * 5", time.Date(2024, time.January, 12, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		cron, err := schedule.Parse(test.expr)
+		require.NoError(t, err)
+
+		require.Equal(t, test.expected, cron.Next(start), "expression: %s", test.expr)
+	}
+
+	// February 30th never happens.
+	cron, err := schedule.Parse("0 0 30 2 *")
+	require.NoError(t, err)
+	require.True(t, cron.Next(start).IsZero())
+}
@@ -0,0 +1,129 @@
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/node/head/recovery"
+	"github.com/blocklessnetwork/b7s/store"
+)
+
+// Name of the store, used as a label for store metrics.
+const storeName = "schedule"
+
+// Spec describes a recurring execution.
+type Spec struct {
+	ID      string          `json:"id"`
+	Cron    string          `json:"cron"`
+	Request execute.Request `json:"request"`
+	Topic   string          `json:"topic,omitempty"` // Topic is the subgroup the roll call is published to.
+	Owner   peer.ID         `json:"owner,omitempty"` // Owner is the peer that registered the schedule, if any.
+	Created time.Time       `json:"created,omitempty"`
+}
+
+// Store persists execution schedules, so that they survive head node restarts.
+type Store struct {
+	sync.Mutex
+	db *pebble.DB
+}
+
+// NewStore creates a new Store backed by the given database.
+func NewStore(db *pebble.DB) *Store {
+
+	s := Store{
+		db: db,
+	}
+
+	return &s
+}
+
+// Save stores the execution schedule, replacing an existing one with the same ID.
+func (s *Store) Save(spec Spec) error {
+
+	defer store.ObserveWrite(storeName, time.Now())
+
+	s.Lock()
+	defer s.Unlock()
+
+	encoded, err := json.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("could not encode schedule: %w", err)
+	}
+
+	err = s.db.Set(encodeKey(spec.ID), encoded, pebble.Sync)
+	if err != nil {
+		return fmt.Errorf("could not store schedule: %w", err)
+	}
+
+	return nil
+}
+
+// Remove removes the execution schedule.
+func (s *Store) Remove(id string) error {
+
+	defer store.ObserveWrite(storeName, time.Now())
+
+	s.Lock()
+	defer s.Unlock()
+
+	err := s.db.Delete(encodeKey(id), pebble.Sync)
+	if err != nil {
+		return fmt.Errorf("could not remove schedule: %w", err)
+	}
+
+	return nil
+}
+
+// Scan returns the list of all execution schedules that can be read, along with the list of schedules that could not.
+func (s *Store) Scan() ([]Spec, []recovery.Corruption, error) {
+
+	defer store.ObserveRead(storeName, time.Now())
+
+	s.Lock()
+	defer s.Unlock()
+
+	prefix := []byte{store.PrefixSchedule, store.Separator}
+	opts := pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: []byte{store.PrefixSchedule, store.Separator + 1},
+	}
+
+	it, err := s.db.NewIter(&opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create iterator: %w", err)
+	}
+	defer it.Close()
+
+	var (
+		specs     = make([]Spec, 0)
+		corrupted []recovery.Corruption
+	)
+	for it.First(); it.Valid(); it.Next() {
+
+		var spec Spec
+		err = json.Unmarshal(it.Value(), &spec)
+		if err != nil {
+			corrupted = append(corrupted, recovery.Corruption{
+				Source: recovery.SourceSchedules,
+				ID:     string(it.Key()[len(prefix):]),
+				Reason: err.Error(),
+			})
+			continue
+		}
+
+		specs = append(specs, spec)
+	}
+
+	return specs, corrupted, nil
+}
+
+func encodeKey(id string) []byte {
+	key := []byte{store.PrefixSchedule, store.Separator}
+	return append(key, []byte(id)...)
+}
@@ -0,0 +1,51 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/node/head/schedule"
+	"github.com/blocklessnetwork/b7s/testing/helpers"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestStore(t *testing.T) {
+
+	db := helpers.InMemoryDB(t)
+	defer db.Close()
+
+	s := schedule.NewStore(db)
+
+	specs, _, err := s.Scan()
+	require.NoError(t, err)
+	require.Empty(t, specs)
+
+	first := schedule.Spec{
+		ID:      "dummy-schedule-1",
+		Cron:    "*/5 * * * *",
+		Request: mocks.GenericExecutionRequest,
+		Created: time.Now().UTC().Truncate(time.Second),
+	}
+	second := schedule.Spec{
+		ID:      "dummy-schedule-2",
+		Cron:    "0 0 * * *",
+		Request: mocks.GenericExecutionRequest,
+		Topic:   "dummy-topic",
+		Created: time.Now().UTC().Truncate(time.Second),
+	}
+
+	require.NoError(t, s.Save(first))
+	require.NoError(t, s.Save(second))
+
+	specs, _, err = s.Scan()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []schedule.Spec{first, second}, specs)
+
+	require.NoError(t, s.Remove(first.ID))
+
+	specs, _, err = s.Scan()
+	require.NoError(t, err)
+	require.Equal(t, []schedule.Spec{second}, specs)
+}
@@ -0,0 +1,164 @@
+package simulation
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+// Executor executes requests - typically, this is the head node.
+type Executor interface {
+	ExecuteFunction(ctx context.Context, req execute.Request, subgroup string) (codes.Code, string, execute.ResultMap, execute.Cluster, execute.Timing, error)
+}
+
+// Benchmark describes the load put on the head node.
+type Benchmark struct {
+	Request     execute.Request // Request to execute.
+	Subgroup    string          // Subgroup to execute the request in.
+	Requests    uint            // Total number of requests to execute.
+	Concurrency uint            // Number of requests executed at the same time.
+}
+
+// Report summarizes how the head node handled the benchmark load.
+type Report struct {
+	Requests   uint                `json:"requests"`
+	Codes      map[codes.Code]uint `json:"codes"`
+	Errors     uint                `json:"errors"`
+	Duration   time.Duration       `json:"duration"`
+	Throughput float64             `json:"throughput"` // Requests per second.
+
+	Total        Durations `json:"total"`
+	RollCall     Durations `json:"roll_call"`
+	ResultGather Durations `json:"result_gather"`
+}
+
+// Durations summarizes a set of durations.
+type Durations struct {
+	Mean time.Duration `json:"mean"`
+	P50  time.Duration `json:"p50"`
+	P95  time.Duration `json:"p95"`
+	P99  time.Duration `json:"p99"`
+	Max  time.Duration `json:"max"`
+}
+
+// Run executes the benchmark requests and reports how long the phases of the executions took.
+// No new requests are started once the context is cancelled.
+func Run(ctx context.Context, executor Executor, bench Benchmark) Report {
+
+	concurrency := bench.Concurrency
+	if concurrency == 0 {
+		concurrency = 1
+	}
+
+	type sample struct {
+		code   codes.Code
+		err    error
+		total  time.Duration
+		timing execute.Timing
+	}
+
+	var (
+		lock    sync.Mutex
+		samples = make([]sample, 0, bench.Requests)
+		wg      sync.WaitGroup
+		sema    = make(chan struct{}, concurrency)
+	)
+
+	start := time.Now()
+
+launch:
+	for i := uint(0); i < bench.Requests; i++ {
+
+		select {
+		case sema <- struct{}{}:
+		case <-ctx.Done():
+			break launch
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-sema }()
+
+			requestStart := time.Now()
+			code, _, _, _, timing, err := executor.ExecuteFunction(ctx, bench.Request, bench.Subgroup)
+
+			lock.Lock()
+			defer lock.Unlock()
+
+			samples = append(samples, sample{
+				code:   code,
+				err:    err,
+				total:  time.Since(requestStart),
+				timing: timing,
+			})
+		}()
+	}
+
+	wg.Wait()
+
+	report := Report{
+		Requests: uint(len(samples)),
+		Codes:    make(map[codes.Code]uint),
+		Duration: time.Since(start),
+	}
+
+	if report.Duration > 0 {
+		report.Throughput = float64(report.Requests) / report.Duration.Seconds()
+	}
+
+	var total, rollCall, resultGather []time.Duration
+	for _, s := range samples {
+
+		if s.err != nil {
+			report.Errors++
+			continue
+		}
+
+		report.Codes[s.code]++
+
+		total = append(total, s.total)
+		rollCall = append(rollCall, time.Duration(s.timing.RollCall)*time.Millisecond)
+		resultGather = append(resultGather, time.Duration(s.timing.ResultGather)*time.Millisecond)
+	}
+
+	report.Total = summarize(total)
+	report.RollCall = summarize(rollCall)
+	report.ResultGather = summarize(resultGather)
+
+	return report
+}
+
+func summarize(durations []time.Duration) Durations {
+
+	if len(durations) == 0 {
+		return Durations{}
+	}
+
+	slices.Sort(durations)
+
+	var sum time.Duration
+	for _, d := range durations {
+		sum += d
+	}
+
+	percentile := func(p float64) time.Duration {
+		i := int(p * float64(len(durations)-1))
+		return durations[i]
+	}
+
+	out := Durations{
+		Mean: sum / time.Duration(len(durations)),
+		P50:  percentile(0.50),
+		P95:  percentile(0.95),
+		P99:  percentile(0.99),
+		Max:  durations[len(durations)-1],
+	}
+
+	return out
+}
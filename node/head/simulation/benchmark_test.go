@@ -0,0 +1,78 @@
+package simulation_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/node/head/simulation"
+)
+
+type executor struct {
+	calls atomic.Int64
+}
+
+func (e *executor) ExecuteFunction(ctx context.Context, req execute.Request, subgroup string) (codes.Code, string, execute.ResultMap, execute.Cluster, execute.Timing, error) {
+
+	n := e.calls.Add(1)
+
+	// Every fourth request fails.
+	if n%4 == 0 {
+		return codes.Error, "", nil, execute.Cluster{}, execute.Timing{}, errors.New("execution failed")
+	}
+
+	timing := execute.Timing{
+		RollCall:     n,
+		ResultGather: 2 * n,
+	}
+
+	return codes.OK, "", nil, execute.Cluster{}, timing, nil
+}
+
+func TestRun(t *testing.T) {
+
+	const requests = 100
+
+	var executor executor
+
+	bench := simulation.Benchmark{
+		Request:     execute.Request{FunctionID: "dummy-function-id"},
+		Requests:    requests,
+		Concurrency: 10,
+	}
+
+	report := simulation.Run(context.Background(), &executor, bench)
+
+	require.Equal(t, uint(requests), report.Requests)
+	require.Equal(t, uint(25), report.Errors)
+	require.Equal(t, map[codes.Code]uint{codes.OK: 75}, report.Codes)
+	require.Greater(t, report.Throughput, float64(0))
+
+	// Request N reports a roll call of N milliseconds, so the slowest successful one is the 99th.
+	require.Equal(t, 99*time.Millisecond, report.RollCall.Max)
+	require.Equal(t, 198*time.Millisecond, report.ResultGather.Max)
+	require.LessOrEqual(t, report.RollCall.P50, report.RollCall.P95)
+	require.LessOrEqual(t, report.RollCall.P95, report.RollCall.P99)
+}
+
+func TestRun_Cancelled(t *testing.T) {
+
+	var executor executor
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	bench := simulation.Benchmark{
+		Requests:    100,
+		Concurrency: 1,
+	}
+
+	report := simulation.Run(ctx, &executor, bench)
+	require.Less(t, report.Requests, uint(100))
+}
@@ -0,0 +1,93 @@
+// Package simulation provides synthetic workers that respond to head node roll calls and execution requests without
+// executing anything. It is used to benchmark roll calls, worker selection and result gathering on the head node,
+// without a real worker fleet.
+package simulation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/blocklessnetwork/b7s/random"
+)
+
+// Latency is a distribution of delays, spread uniformly between the minimum and the maximum.
+type Latency struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// Valid checks if the distribution is correct.
+func (l Latency) Valid() error {
+
+	if l.Min < 0 || l.Max < 0 {
+		return errors.New("latency cannot be negative")
+	}
+
+	if l.Max != 0 && l.Max < l.Min {
+		return errors.New("maximum latency cannot be lower than minimum latency")
+	}
+
+	return nil
+}
+
+func (l Latency) sample(rand *random.Source) time.Duration {
+
+	if l.Max <= l.Min {
+		return l.Min
+	}
+
+	return l.Min + time.Duration(rand.Int63n(int64(l.Max-l.Min)))
+}
+
+// Profile describes how synthetic workers respond to the head node.
+type Profile struct {
+	RollCallLatency  Latency // How long it takes a worker to report for a roll call.
+	ExecutionLatency Latency // How long it takes a worker to respond with an execution result.
+	DeclineRate      float64 // Share of roll calls workers do not report for.
+	FailureRate      float64 // Share of executions that fail.
+	Seed             int64   // Seed for the random choices workers make, for reproducible runs. Zero means a random seed.
+}
+
+// Valid checks if the profile is correct.
+func (p Profile) Valid() error {
+
+	err := p.RollCallLatency.Valid()
+	if err != nil {
+		return fmt.Errorf("invalid roll call latency: %w", err)
+	}
+
+	err = p.ExecutionLatency.Valid()
+	if err != nil {
+		return fmt.Errorf("invalid execution latency: %w", err)
+	}
+
+	if p.DeclineRate < 0 || p.DeclineRate > 1 {
+		return errors.New("decline rate must be between 0 and 1")
+	}
+
+	if p.FailureRate < 0 || p.FailureRate > 1 {
+		return errors.New("failure rate must be between 0 and 1")
+	}
+
+	return nil
+}
+
+// wait waits for the delay, or until the context is cancelled. It returns false if the context was cancelled.
+func wait(ctx context.Context, delay time.Duration) bool {
+
+	if delay <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,331 @@
+package simulation
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/rs/zerolog"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/random"
+)
+
+const (
+	// Output synthetic workers respond with for successful executions.
+	executionOutput = "simulated execution"
+)
+
+// Worker is a synthetic worker. It reports for roll calls and responds to execution requests according to its profile.
+type Worker struct {
+	log     zerolog.Logger
+	host    *host.Host
+	profile Profile
+	topics  []string
+	rand    *random.Source
+
+	wg sync.WaitGroup
+}
+
+// NewWorker creates a new synthetic worker, listening for roll calls on the given topics.
+func NewWorker(log zerolog.Logger, host *host.Host, profile Profile, topics []string) *Worker {
+
+	rand := random.NewUnseeded()
+	if profile.Seed != 0 {
+		rand = random.New(profile.Seed)
+	}
+
+	w := Worker{
+		log:     log.With().Str("component", "simulation").Stringer("worker", host.ID()).Logger(),
+		host:    host,
+		profile: profile,
+		topics:  topics,
+		rand:    rand,
+	}
+
+	return &w
+}
+
+// ID returns the peer ID of the worker.
+func (w *Worker) ID() peer.ID {
+	return w.host.ID()
+}
+
+// Run subscribes to the topics and processes messages from the head node, until the context is cancelled.
+func (w *Worker) Run(ctx context.Context) error {
+
+	err := w.host.InitPubSub(ctx)
+	if err != nil {
+		return fmt.Errorf("could not initialize pubsub: %w", err)
+	}
+
+	subscriptions := make([]*pubsub.Subscription, 0, len(w.topics))
+	for _, topic := range w.topics {
+		_, subscription, err := w.host.Subscribe(topic)
+		if err != nil {
+			return fmt.Errorf("could not subscribe to topic (name: %s): %w", topic, err)
+		}
+
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	w.host.SetStreamHandler(blockless.ProtocolID, w.directMessageHandler(ctx))
+
+	var topics sync.WaitGroup
+	for _, subscription := range subscriptions {
+
+		topics.Add(1)
+		go func(subscription *pubsub.Subscription) {
+			defer topics.Done()
+
+			for {
+				msg, err := subscription.Next(ctx)
+				if err != nil {
+					// NOTE: Cancelling the context will lead us here.
+					return
+				}
+
+				if msg.ReceivedFrom == w.host.ID() {
+					continue
+				}
+
+				w.wg.Add(1)
+				go func(msg *pubsub.Message) {
+					defer w.wg.Done()
+					w.processMessage(ctx, msg.GetFrom(), msg.GetData())
+				}(msg)
+			}
+		}(subscription)
+	}
+
+	topics.Wait()
+	w.wg.Wait()
+
+	return nil
+}
+
+func (w *Worker) directMessageHandler(ctx context.Context) network.StreamHandler {
+
+	return func(stream network.Stream) {
+		defer stream.Close()
+
+		from := stream.Conn().RemotePeer()
+
+		buf := bufio.NewReader(stream)
+		msg, err := buf.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			stream.Reset()
+			w.log.Error().Err(err).Msg("error receiving direct message")
+			return
+		}
+
+		w.processMessage(ctx, from, msg)
+	}
+}
+
+// processMessage handles roll calls and execution requests. Other messages are ignored.
+func (w *Worker) processMessage(ctx context.Context, from peer.ID, payload []byte) {
+
+	var base struct {
+		Type string `json:"type,omitempty"`
+	}
+	err := json.Unmarshal(payload, &base)
+	if err != nil {
+		w.log.Error().Err(err).Stringer("peer", from).Msg("could not unpack message")
+		return
+	}
+
+	switch base.Type {
+	case blockless.MessageRollCall:
+		var req request.RollCall
+		err = json.Unmarshal(payload, &req)
+		if err == nil {
+			err = w.processRollCall(ctx, req)
+		}
+
+	case blockless.MessageExecute:
+		var req request.Execute
+		err = json.Unmarshal(payload, &req)
+		if err == nil {
+			err = w.processExecute(ctx, from, req)
+		}
+
+	default:
+		return
+	}
+
+	if err != nil {
+		w.log.Error().Err(err).Stringer("peer", from).Str("type", base.Type).Msg("could not process message")
+	}
+}
+
+func (w *Worker) processRollCall(ctx context.Context, req request.RollCall) error {
+
+	// Sampled roll calls are answered only by a share of workers.
+	if req.Probability > 0 && !w.rand.Chance(req.Probability) {
+		return nil
+	}
+
+	if w.rand.Chance(w.profile.DeclineRate) {
+		return nil
+	}
+
+	if !wait(ctx, w.profile.RollCallLatency.sample(w.rand)) {
+		return nil
+	}
+
+	err := w.send(ctx, req.Origin, req.Response(codes.Accepted))
+	if err != nil {
+		return fmt.Errorf("could not send roll call response: %w", err)
+	}
+
+	return nil
+}
+
+func (w *Worker) processExecute(ctx context.Context, from peer.ID, req request.Execute) error {
+
+	received := time.Now()
+
+	if !wait(ctx, w.profile.ExecutionLatency.sample(w.rand)) {
+		return nil
+	}
+
+	result := execute.Result{
+		Code: codes.OK,
+		Result: execute.RuntimeOutput{
+			Stdout: executionOutput,
+		},
+	}
+	if w.rand.Chance(w.profile.FailureRate) {
+		result = execute.Result{
+			Code: codes.Error,
+			Result: execute.RuntimeOutput{
+				ExitCode: 1,
+			},
+		}
+	}
+	result.Usage.WallClockTime = time.Since(received)
+
+	nres := execute.NodeResult{
+		Result: result,
+		Timing: &execute.Timing{
+			Execution: execute.Since(received),
+		},
+	}
+	nres.SetChecksum()
+
+	res := req.Response(result.Code).WithResults(execute.ResultMap{w.host.ID(): nres})
+
+	err := w.send(ctx, from, res)
+	if err != nil {
+		return fmt.Errorf("could not send execution response: %w", err)
+	}
+
+	return nil
+}
+
+func (w *Worker) send(ctx context.Context, to peer.ID, msg blockless.Message) error {
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("could not encode record: %w", err)
+	}
+
+	return w.host.SendMessage(ctx, to, payload)
+}
+
+// Fleet is a set of synthetic workers, each with its own host.
+type Fleet struct {
+	workers []*Worker
+}
+
+// NewFleet creates the given number of synthetic workers, listening on the given address.
+func NewFleet(log zerolog.Logger, count uint, address string, profile Profile, topics []string) (*Fleet, error) {
+
+	err := profile.Valid()
+	if err != nil {
+		return nil, fmt.Errorf("invalid profile: %w", err)
+	}
+
+	fleet := Fleet{
+		workers: make([]*Worker, 0, count),
+	}
+
+	for i := uint(0); i < count; i++ {
+
+		h, err := host.New(log, address, 0)
+		if err != nil {
+			fleet.Close()
+			return nil, fmt.Errorf("could not create host for worker %v: %w", i, err)
+		}
+
+		// Each worker gets its own seed, so they do not all make the same choices.
+		wp := profile
+		if profile.Seed != 0 {
+			wp.Seed = profile.Seed + int64(i)
+		}
+
+		fleet.workers = append(fleet.workers, NewWorker(log, h, wp, topics))
+	}
+
+	return &fleet, nil
+}
+
+// Workers returns the workers in the fleet.
+func (f *Fleet) Workers() []*Worker {
+	return f.workers
+}
+
+// Connect connects all workers to the head node.
+func (f *Fleet) Connect(ctx context.Context, head peer.AddrInfo) error {
+
+	for _, worker := range f.workers {
+		err := worker.host.Connect(ctx, head)
+		if err != nil {
+			return fmt.Errorf("could not connect worker to head node (worker: %s): %w", worker.ID(), err)
+		}
+	}
+
+	return nil
+}
+
+// Run runs all workers, until the context is cancelled.
+func (f *Fleet) Run(ctx context.Context) error {
+
+	var group multierror.Group
+	for _, worker := range f.workers {
+		worker := worker
+		group.Go(func() error {
+			return worker.Run(ctx)
+		})
+	}
+
+	return group.Wait().ErrorOrNil()
+}
+
+// Close shuts down the hosts of all workers.
+func (f *Fleet) Close() error {
+
+	var errs *multierror.Error
+	for _, worker := range f.workers {
+		err := worker.host.Close()
+		if err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
@@ -0,0 +1,165 @@
+package simulation_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/node/head/simulation"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+const (
+	loopback = "127.0.0.1"
+)
+
+func TestProfile_Valid(t *testing.T) {
+
+	valid := simulation.Profile{
+		RollCallLatency:  simulation.Latency{Min: time.Millisecond, Max: 10 * time.Millisecond},
+		ExecutionLatency: simulation.Latency{Min: 10 * time.Millisecond},
+		DeclineRate:      0.1,
+		FailureRate:      1,
+	}
+	require.NoError(t, valid.Valid())
+
+	invalid := valid
+	invalid.RollCallLatency = simulation.Latency{Min: 10 * time.Millisecond, Max: time.Millisecond}
+	require.Error(t, invalid.Valid())
+
+	invalid = valid
+	invalid.ExecutionLatency = simulation.Latency{Min: -time.Millisecond}
+	require.Error(t, invalid.Valid())
+
+	invalid = valid
+	invalid.FailureRate = 1.5
+	require.Error(t, invalid.Valid())
+}
+
+func TestWorker(t *testing.T) {
+
+	const (
+		functionID = "dummy-function-id"
+		requestID  = "dummy-request-id"
+	)
+
+	// Start a single synthetic worker and a head node host, connected to it.
+	setup := func(t *testing.T, profile simulation.Profile) (*host.Host, *simulation.Worker) {
+		t.Helper()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+
+		fleet, err := simulation.NewFleet(mocks.NoopLogger, 1, loopback, profile, []string{"dummy-topic"})
+		require.NoError(t, err)
+		t.Cleanup(func() { fleet.Close() })
+
+		head, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+		t.Cleanup(func() { head.Close() })
+
+		err = fleet.Connect(ctx, peer.AddrInfo{ID: head.ID(), Addrs: head.Addrs()})
+		require.NoError(t, err)
+
+		go fleet.Run(ctx)
+
+		return head, fleet.Workers()[0]
+	}
+
+	// Send the message to the worker and return the response it sent back.
+	exchange := func(t *testing.T, head *host.Host, worker peer.ID, msg blockless.Message, out any) {
+		t.Helper()
+
+		received := make(chan []byte, 1)
+		head.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer stream.Close()
+
+			payload, err := io.ReadAll(stream)
+			require.NoError(t, err)
+			received <- payload
+		})
+
+		payload, err := json.Marshal(msg)
+		require.NoError(t, err)
+
+		// Worker sets its message handler when it starts.
+		require.Eventually(t, func() bool {
+			return head.SendMessage(context.Background(), worker, payload) == nil
+		}, time.Second, 10*time.Millisecond)
+
+		select {
+		case payload := <-received:
+			require.NoError(t, json.Unmarshal(payload, out))
+		case <-time.After(5 * time.Second):
+			t.Fatal("no response from worker")
+		}
+	}
+
+	t.Run("worker reports for roll call", func(t *testing.T) {
+		t.Parallel()
+
+		head, worker := setup(t, simulation.Profile{})
+
+		req := request.RollCall{
+			FunctionID: functionID,
+			RequestID:  requestID,
+			Origin:     head.ID(),
+		}
+
+		var res response.RollCall
+		exchange(t, head, worker.ID(), &req, &res)
+
+		require.Equal(t, codes.Accepted, res.Code)
+		require.Equal(t, requestID, res.RequestID)
+		require.Equal(t, functionID, res.FunctionID)
+	})
+	t.Run("worker responds with execution result", func(t *testing.T) {
+		t.Parallel()
+
+		head, worker := setup(t, simulation.Profile{})
+
+		req := request.Execute{
+			Request:   execute.Request{FunctionID: functionID},
+			RequestID: requestID,
+		}
+
+		var res response.Execute
+		exchange(t, head, worker.ID(), &req, &res)
+
+		require.Equal(t, codes.OK, res.Code)
+		require.Equal(t, requestID, res.RequestID)
+
+		result, ok := res.Results[worker.ID()]
+		require.True(t, ok)
+		require.Equal(t, codes.OK, result.Code)
+		require.NoError(t, result.VerifyChecksum())
+	})
+	t.Run("failing worker responds with error", func(t *testing.T) {
+		t.Parallel()
+
+		head, worker := setup(t, simulation.Profile{FailureRate: 1})
+
+		req := request.Execute{
+			Request:   execute.Request{FunctionID: functionID},
+			RequestID: requestID,
+		}
+
+		var res response.Execute
+		exchange(t, head, worker.ID(), &req, &res)
+
+		require.Equal(t, codes.Error, res.Code)
+		require.Equal(t, codes.Error, res.Results[worker.ID()].Code)
+	})
+}
@@ -0,0 +1,129 @@
+package topology
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+)
+
+// Supported export formats.
+const (
+	FormatJSON = "json"
+	FormatDOT  = "dot"
+)
+
+// Graph is a snapshot of the network, as seen by the head node.
+type Graph struct {
+	Generated   time.Time    `json:"generated"`
+	Nodes       []Node       `json:"nodes"`
+	Connections []Connection `json:"connections"`
+	Clusters    []Cluster    `json:"clusters,omitempty"`
+	Executions  []Execution  `json:"executions,omitempty"`
+}
+
+// Node describes a single node in the network.
+type Node struct {
+	ID        string     `json:"id"`
+	Role      string     `json:"role"`
+	Self      bool       `json:"self,omitempty"`      // Node producing the snapshot.
+	Connected bool       `json:"connected,omitempty"` // Node producing the snapshot is connected to this node.
+	LastSeen  *time.Time `json:"last_seen,omitempty"` // When we last received a health ping from the node.
+}
+
+// Connection describes a connection between two nodes.
+type Connection struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Cluster describes a consensus cluster formed for an execution.
+type Cluster struct {
+	RequestID string   `json:"request_id"`
+	Consensus string   `json:"consensus"`
+	Peers     []string `json:"peers"`
+}
+
+// Execution describes an execution in progress.
+type Execution struct {
+	RequestID  string    `json:"request_id"`
+	FunctionID string    `json:"function_id"`
+	Started    time.Time `json:"started"`
+	Peers      []string  `json:"peers,omitempty"`
+}
+
+// Sort orders the graph elements, so snapshots of the same network state are identical.
+func (g *Graph) Sort() {
+
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		return g.Nodes[i].ID < g.Nodes[j].ID
+	})
+
+	sort.Slice(g.Connections, func(i, j int) bool {
+		if g.Connections[i].From != g.Connections[j].From {
+			return g.Connections[i].From < g.Connections[j].From
+		}
+		return g.Connections[i].To < g.Connections[j].To
+	})
+
+	sort.Slice(g.Clusters, func(i, j int) bool {
+		return g.Clusters[i].RequestID < g.Clusters[j].RequestID
+	})
+
+	sort.Slice(g.Executions, func(i, j int) bool {
+		return g.Executions[i].RequestID < g.Executions[j].RequestID
+	})
+}
+
+// DOT returns the graph in the Graphviz DOT format. Clusters are drawn as subgraphs and nodes executing requests are highlighted.
+func (g Graph) DOT() string {
+
+	executing := make(map[string]int)
+	for _, execution := range g.Executions {
+		for _, peer := range execution.Peers {
+			executing[peer]++
+		}
+	}
+
+	var b strings.Builder
+
+	b.WriteString("graph b7s {\n")
+
+	for _, node := range g.Nodes {
+
+		attrs := []string{
+			fmt.Sprintf("label=%q", fmt.Sprintf("%s\n%s", node.ID, node.Role)),
+		}
+
+		if node.Self {
+			attrs = append(attrs, "shape=doublecircle")
+		} else if node.Role == blockless.HeadNodeLabel {
+			attrs = append(attrs, "shape=box")
+		}
+
+		if executing[node.ID] > 0 {
+			attrs = append(attrs, "style=filled", "fillcolor=lightblue")
+		}
+
+		fmt.Fprintf(&b, "  %q [%s];\n", node.ID, strings.Join(attrs, ", "))
+	}
+
+	for _, conn := range g.Connections {
+		fmt.Fprintf(&b, "  %q -- %q;\n", conn.From, conn.To)
+	}
+
+	for _, cluster := range g.Clusters {
+		fmt.Fprintf(&b, "  subgraph %q {\n", "cluster_"+cluster.RequestID)
+		fmt.Fprintf(&b, "    label=%q;\n", fmt.Sprintf("%s (%s)", cluster.RequestID, cluster.Consensus))
+		for _, peer := range cluster.Peers {
+			fmt.Fprintf(&b, "    %q;\n", peer)
+		}
+		b.WriteString("  }\n")
+	}
+
+	b.WriteString("}\n")
+
+	return b.String()
+}
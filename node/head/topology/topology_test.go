@@ -0,0 +1,49 @@
+package topology_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/node/head/topology"
+)
+
+func TestGraph_DOT(t *testing.T) {
+
+	graph := topology.Graph{
+		Nodes: []topology.Node{
+			{ID: "worker-b", Role: "worker", Connected: true},
+			{ID: "head", Role: "head", Self: true},
+			{ID: "worker-a", Role: "worker", Connected: true},
+		},
+		Connections: []topology.Connection{
+			{From: "head", To: "worker-b"},
+			{From: "head", To: "worker-a"},
+		},
+		Clusters: []topology.Cluster{
+			{RequestID: "request", Consensus: "Raft", Peers: []string{"worker-a", "worker-b"}},
+		},
+		Executions: []topology.Execution{
+			{RequestID: "request", FunctionID: "function", Peers: []string{"worker-a", "worker-b"}},
+		},
+	}
+	graph.Sort()
+
+	require.Equal(t, "head", graph.Nodes[0].ID)
+	require.Equal(t, "worker-a", graph.Connections[0].To)
+
+	expected := `graph b7s {
+  "head" [label="head\nhead", shape=doublecircle];
+  "worker-a" [label="worker-a\nworker", style=filled, fillcolor=lightblue];
+  "worker-b" [label="worker-b\nworker", style=filled, fillcolor=lightblue];
+  "head" -- "worker-a";
+  "head" -- "worker-b";
+  subgraph "cluster_request" {
+    label="request (Raft)";
+    "worker-a";
+    "worker-b";
+  }
+}
+`
+	require.Equal(t, expected, graph.DOT())
+}
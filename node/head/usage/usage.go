@@ -0,0 +1,99 @@
+package usage
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+// Summary describes the resources consumed by executions with a specific tag value.
+// Resource usage is the sum of the usage reported by all workers that executed the request.
+type Summary struct {
+	Value         string        `json:"value"`
+	Executions    uint64        `json:"executions"`
+	WallClockTime time.Duration `json:"wall_clock_time,omitempty"`
+	CPUUserTime   time.Duration `json:"cpu_user_time,omitempty"`
+	CPUSysTime    time.Duration `json:"cpu_sys_time,omitempty"`
+	MemoryMaxKB   int64         `json:"memory_max_kb,omitempty"` // Peak memory usage of a single execution.
+}
+
+// Accountant tracks resources consumed by executions, grouped by request tags.
+type Accountant struct {
+	sync.Mutex
+
+	// tags maps tag name to the usage for each of the tag values.
+	tags map[string]map[string]*Summary
+}
+
+// NewAccountant creates a new Accountant.
+func NewAccountant() *Accountant {
+
+	a := Accountant{
+		tags: make(map[string]map[string]*Summary),
+	}
+
+	return &a
+}
+
+// Record accounts resource usage of the execution towards each of its tags.
+func (a *Accountant) Record(tags map[string]string, results execute.ResultMap) {
+
+	if len(tags) == 0 {
+		return
+	}
+
+	var usage Summary
+	for _, res := range results {
+		usage.WallClockTime += res.Usage.WallClockTime
+		usage.CPUUserTime += res.Usage.CPUUserTime
+		usage.CPUSysTime += res.Usage.CPUSysTime
+		usage.MemoryMaxKB = max(usage.MemoryMaxKB, res.Usage.MemoryMaxKB)
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	for name, value := range tags {
+
+		values, ok := a.tags[name]
+		if !ok {
+			values = make(map[string]*Summary)
+			a.tags[name] = values
+		}
+
+		summary, ok := values[value]
+		if !ok {
+			summary = &Summary{Value: value}
+			values[value] = summary
+		}
+
+		summary.Executions++
+		summary.WallClockTime += usage.WallClockTime
+		summary.CPUUserTime += usage.CPUUserTime
+		summary.CPUSysTime += usage.CPUSysTime
+		summary.MemoryMaxKB = max(summary.MemoryMaxKB, usage.MemoryMaxKB)
+	}
+}
+
+// Summary returns the usage for each value of the given tag, ordered by value.
+// Executions that did not have the tag are not included.
+func (a *Accountant) Summary(tag string) []Summary {
+
+	a.Lock()
+	defer a.Unlock()
+
+	values := a.tags[tag]
+
+	out := make([]Summary, 0, len(values))
+	for _, summary := range values {
+		out = append(out, *summary)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Value < out[j].Value
+	})
+
+	return out
+}
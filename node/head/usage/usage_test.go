@@ -0,0 +1,81 @@
+package usage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/node/head/usage"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestAccountant(t *testing.T) {
+
+	result := func(cpu time.Duration, memory int64) execute.NodeResult {
+		return execute.NodeResult{
+			Result: execute.Result{
+				Usage: execute.Usage{
+					WallClockTime: 2 * cpu,
+					CPUUserTime:   cpu,
+					MemoryMaxKB:   memory,
+				},
+			},
+		}
+	}
+
+	accountant := usage.NewAccountant()
+
+	// Request executed on two workers.
+	accountant.Record(
+		map[string]string{"team": "research", "project": "alpha"},
+		execute.ResultMap{
+			mocks.GenericPeerIDs[0]: result(time.Second, 100),
+			mocks.GenericPeerIDs[1]: result(time.Second, 200),
+		},
+	)
+	accountant.Record(
+		map[string]string{"team": "research", "project": "beta"},
+		execute.ResultMap{
+			mocks.GenericPeerIDs[0]: result(3*time.Second, 50),
+		},
+	)
+	accountant.Record(
+		map[string]string{"team": "operations"},
+		execute.ResultMap{
+			mocks.GenericPeerIDs[0]: result(time.Second, 10),
+		},
+	)
+	// Untagged executions are not accounted.
+	accountant.Record(nil, execute.ResultMap{
+		mocks.GenericPeerIDs[0]: result(time.Hour, 10),
+	})
+
+	expected := []usage.Summary{
+		{
+			Value:         "operations",
+			Executions:    1,
+			WallClockTime: 2 * time.Second,
+			CPUUserTime:   time.Second,
+			MemoryMaxKB:   10,
+		},
+		{
+			Value:         "research",
+			Executions:    2,
+			WallClockTime: 10 * time.Second,
+			CPUUserTime:   5 * time.Second,
+			MemoryMaxKB:   200,
+		},
+	}
+	require.Equal(t, expected, accountant.Summary("team"))
+
+	projects := accountant.Summary("project")
+	require.Len(t, projects, 2)
+	require.Equal(t, "alpha", projects[0].Value)
+	require.Equal(t, 2*time.Second, projects[0].CPUUserTime)
+	require.Equal(t, "beta", projects[1].Value)
+	require.Equal(t, 3*time.Second, projects[1].CPUUserTime)
+
+	require.Empty(t, accountant.Summary("cost-center"))
+}
@@ -10,11 +10,17 @@ import (
 	"github.com/libp2p/go-libp2p/core/peer"
 	"go.opentelemetry.io/otel/trace"
 
+	"github.com/blocklessnetwork/b7s/b7serrors"
 	"github.com/blocklessnetwork/b7s/consensus"
 	"github.com/blocklessnetwork/b7s/models/blockless"
 	"github.com/blocklessnetwork/b7s/models/codes"
 	"github.com/blocklessnetwork/b7s/models/execute"
 	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/node/head/journal"
+	"github.com/blocklessnetwork/b7s/node/internal/dispatch"
+	"github.com/blocklessnetwork/b7s/node/internal/reputation"
+	"github.com/blocklessnetwork/b7s/telemetry/b7ssemconv"
 	"github.com/blocklessnetwork/b7s/telemetry/tracing"
 )
 
@@ -30,22 +36,81 @@ func (n *Node) headProcessExecute(ctx context.Context, from peer.ID, req request
 		return nil
 	}
 
-	requestID := newRequestID()
+	// Requests passed on by other nodes must come from the last node in their provenance.
+	if len(req.Provenance) > 0 {
+		err = req.Provenance.Verify(from, req.RequestID, req.Request)
+		if err != nil {
+			err = n.send(ctx, from, req.Response(codes.Invalid).WithErrorMessage(fmt.Errorf("invalid provenance: %w", err)))
+			if err != nil {
+				return fmt.Errorf("could not send response: %w", err)
+			}
+			return nil
+		}
+	}
 
-	log := n.log.With().Str("request", req.RequestID).Str("peer", from.String()).Str("function", req.FunctionID).Logger()
+	if n.rateLimited(req.FunctionID, from) {
+		err = n.send(ctx, from, req.Response(codes.TooManyRequests).WithErrorMessage(b7serrors.ErrRateLimited))
+		if err != nil {
+			return fmt.Errorf("could not send response: %w", err)
+		}
+		return nil
+	}
 
-	code, results, cluster, err := n.headExecute(ctx, requestID, req.Request, req.Topic)
+	// Hold requests that should not start yet, and dispatch them once their time comes.
+	if req.Config.NotBefore.After(time.Now()) {
+		return n.deferExecution(ctx, from, req)
+	}
+
+	// Do not let a single client take over the worker pool with many executions at once.
+	ok, err := n.acquireClientSlot(ctx, from, req.Config.Priority)
 	if err != nil {
-		log.Error().Err(err).Msg("execution failed")
+		return fmt.Errorf("could not get execution slot for the client: %w", err)
+	}
+	if !ok {
+		err = n.send(ctx, from, req.Response(codes.TooManyInFlight).WithErrorMessage(b7serrors.ErrTooManyInFlight))
+		if err != nil {
+			return fmt.Errorf("could not send response: %w", err)
+		}
+		return nil
 	}
 
-	log.Info().Str("code", code.String()).Msg("execution complete")
+	requestID := n.newRequestID()
 
-	res := req.Response(code).WithResults(results).WithCluster(cluster)
-	// Communicate the reason for failure in these cases.
-	if errors.Is(err, blockless.ErrRollCallTimeout) || errors.Is(err, blockless.ErrExecutionNotEnoughNodes) {
-		res.ErrorMessage = err.Error()
+	// Record the client, so it can be notified if the head node restarts before the execution is done.
+	n.journalUpdate(requestID, func(e *journal.Entry) {
+		e.Origin = from
+		e.ClientRequestID = req.RequestID
+	})
+
+	// Acknowledge asynchronous requests right away, and deliver the results once the execution is done.
+	if req.Async {
+
+		if req.RequestID == "" {
+			req.RequestID = requestID
+		}
+
+		err = n.send(ctx, from, req.Response(codes.Accepted))
+		if err != nil {
+			n.releaseClientSlot(from)
+			return fmt.Errorf("could not send acknowledgement: %w", err)
+		}
+
+		go func() {
+			defer n.releaseClientSlot(from)
+
+			res := n.headExecuteRequest(ctx, from, requestID, req)
+			n.deliverAsyncResponse(ctx, from, req, res)
+		}()
+
+		return nil
 	}
+	defer n.releaseClientSlot(from)
+
+	// Stop working on the request if the client disconnects before receiving the response.
+	cctx, cancel := n.clientContext(ctx, from)
+	defer cancel()
+
+	res := n.headExecuteRequest(cctx, from, requestID, req)
 
 	// Send the response, whatever it may be (success or failure).
 	err = n.send(ctx, from, res)
@@ -56,9 +121,87 @@ func (n *Node) headProcessExecute(ctx context.Context, from peer.ID, req request
 	return nil
 }
 
+// headExecuteRequest executes the client request and creates the execution response.
+func (n *Node) headExecuteRequest(ctx context.Context, from peer.ID, requestID string, req request.Execute) *response.Execute {
+
+	log := n.log.With().Str("request", req.RequestID).Str("peer", from.String()).Str("function", req.FunctionID).Logger()
+
+	// Executions count towards the quota of the peer that requested them.
+	ctx = withRequester(ctx, from)
+
+	// Stop working on the request once the client stops waiting for it.
+	exctx := ctx
+	if req.Remaining != 0 {
+		var cancel context.CancelFunc
+		exctx, cancel = context.WithTimeout(ctx, req.Remaining)
+		defer cancel()
+	}
+
+	// Forward results to the client as they arrive, if requested.
+	if req.Stream {
+		n.startResultStream(requestID, from, req)
+		defer n.stopResultStream(requestID)
+	}
+
+	code, results, cluster, timing, err := n.executeOrDelegate(exctx, requestID, req.Request, req.Topic, req.Provenance)
+	if err != nil {
+		log.Error().Err(err).Msg("execution failed")
+	}
+
+	log.Info().Str("code", code.String()).Msg("execution complete")
+
+	n.detectAnomalies(requestID, req.FunctionID, results)
+	n.shadowExecution(ctx, requestID, req.Request, req.Topic, code, results)
+
+	res := req.Response(code).WithCluster(cluster).WithTiming(timing)
+
+	// Keep the response small for executions on many peers. Full results are kept so the client can retrieve them one by one.
+	if req.Summary {
+		n.summaries.set(requestID, from, results, time.Now())
+		n.metrics.IncrCounter(resultSummariesMetric, 1)
+		res = res.WithSummary(execute.Summarize(requestID, results))
+	} else {
+		res = res.WithResults(results)
+	}
+
+	// Communicate the reason for failure in these cases.
+	if b7serrors.IsPublic(err) {
+		res.ErrorMessage = err.Error()
+	}
+
+	return res
+}
+
 // headExecute is called on the head node. The head node will publish a roll call and delegate an execution request to chosen nodes.
 // The returned map contains execution results, mapped to the peer IDs of peers who reported them.
-func (n *Node) headExecute(ctx context.Context, requestID string, req execute.Request, subgroup string) (codes.Code, execute.ResultMap, execute.Cluster, error) {
+// Provenance lists the nodes the request passed through before reaching the head node, if any.
+// If the execution had to be retried or re-dispatched, the cluster info lists the attempts made.
+func (n *Node) headExecute(ctx context.Context, requestID string, req execute.Request, subgroup string, provenance execute.Provenance) (codes.Code, execute.ResultMap, execute.Cluster, execute.Timing, error) {
+
+	attempts := newAttemptLog()
+
+	code, results, cluster, timing, err := n.runExecution(ctx, attempts, requestID, req, subgroup, provenance)
+
+	// Whatever the outcome, the execution was not completed if the client stopped waiting for it.
+	if abandoned(ctx) {
+		n.log.Info().Str("request", requestID).Msg("execution abandoned by the client")
+		n.metrics.IncrCounter(abandonedExecutionsMetric, 1)
+
+		code = codes.Aborted
+		if err == nil {
+			err = errExecutionAbandoned
+		}
+	}
+
+	cluster.Attempts = attempts.history(cluster.Attempts, execute.AttemptExecution, resultPeers(results, cluster.Peers), code, err)
+
+	return code, results, cluster, timing, err
+}
+
+// runExecution executes the request for headExecute, recording failed attempts in the attempt log.
+func (n *Node) runExecution(ctx context.Context, attempts *attemptLog, requestID string, req execute.Request, subgroup string, provenance execute.Provenance) (codes.Code, execute.ResultMap, execute.Cluster, execute.Timing, error) {
+
+	received := time.Now()
 
 	n.metrics.IncrCounterWithLabels(functionExecutionsMetric, 1,
 		[]metrics.Label{
@@ -89,33 +232,132 @@ func (n *Node) headExecute(ctx context.Context, requestID string, req execute.Re
 		log = log.With().Str("consensus", consensusAlgo.String()).Logger()
 	}
 
+	// The request is done once this function returns, whatever the outcome.
+	defer n.journalRemove(requestID)
+
+	// Sampled executions choose workers out of all peers that report for the roll call, rather than the workers bound to the affinity token.
+	affinity := req.Config.Affinity
+	if req.Config.Sampling != nil {
+		if consensusRequired(consensusAlgo) {
+			return codes.Invalid, nil, execute.Cluster{}, execute.Timing{}, errSamplingWithConsensus
+		}
+
+		nodeCount = -1
+		affinity = ""
+	}
+
 	log.Info().Msg("processing execution request")
 
+	// Check if the client still has quota left. Shadow executions do not count towards the quota.
+	shadow := isShadowExecution(ctx)
+	if !shadow {
+		err = n.admitExecution(ctx)
+		if err != nil {
+			if errors.Is(err, b7serrors.ErrQuotaExceeded) {
+				n.metrics.IncrCounter(quotaExceededMetric, 1)
+				return codes.QuotaExceeded, nil, execute.Cluster{}, execute.Timing{}, err
+			}
+
+			log.Warn().Err(err).Msg("could not check client quota")
+		}
+	}
+
+	n.journalUpdate(requestID, func(e *journal.Entry) {
+		e.FunctionID = req.FunctionID
+		e.Consensus = consensusAlgo
+		e.Phase = journal.PhaseRollCall
+	})
+
+	var timing execute.Timing
+
 	// Phase 1. - Issue roll call to nodes.
-	reportingPeers, err := n.executeRollCall(ctx, requestID, req.FunctionID, nodeCount, consensusAlgo, subgroup, req.Config.Attributes, req.Config.Timeout)
+	rollCallStart := time.Now()
+	reportingPeers, reserve, latencies, err := n.executeRollCall(ctx, requestID, req.FunctionID, nodeCount, consensusAlgo, subgroup, req.Config.Attributes, affinity)
+	// Not enough workers in the subgroup responded - try the rest of the network.
+	if errors.Is(err, b7serrors.ErrRollCallTimeout) && n.rollCallFallback(ctx, subgroup) {
+		log.Warn().Str("subgroup", subgroup).Msg("not enough workers in subgroup responded to roll call, retrying on the default topic")
+		n.metrics.IncrCounterWithLabels(rollCallFallbacksMetric, 1, []metrics.Label{{Name: "subgroup", Value: subgroup}})
+		attempts.fail(execute.AttemptRollCall, nil, b7serrors.Code(err), rollCallStart, attemptReason(err))
+
+		reportingPeers, reserve, latencies, err = n.executeRollCall(ctx, requestID, req.FunctionID, nodeCount, consensusAlgo, DefaultTopic, req.Config.Attributes, "")
+	}
+	timing.RollCall = execute.Since(rollCallStart)
 	if err != nil {
-		code := codes.Error
-		if errors.Is(err, blockless.ErrRollCallTimeout) {
-			code = codes.Timeout
+		return b7serrors.Code(err), nil, execute.Cluster{}, timing, fmt.Errorf("could not roll call peers (request: %s): %w", requestID, err)
+	}
+
+	var strata map[string][]peer.ID
+	if req.Config.Sampling != nil {
+		reportingPeers, strata = n.stratifiedSample(*req.Config.Sampling, reportingPeers, reserve)
+		reserve = nil
+		if len(reportingPeers) == 0 {
+			return codes.NotFound, nil, execute.Cluster{}, timing, errNoPeersSampled
 		}
 
-		return code, nil, execute.Cluster{}, fmt.Errorf("could not roll call peers (request: %s): %w", requestID, err)
+		n.metrics.IncrCounterWithLabels(sampledExecutionsMetric, 1, []metrics.Label{{Name: "function", Value: req.FunctionID}})
+		log.Info().Str("attribute", req.Config.Sampling.Attribute).Int("strata", len(strata)).Strs("peers", blockless.PeerIDsToStr(reportingPeers)).Msg("stratified sample of roll called peers chosen for execution")
 	}
 
 	cluster := execute.Cluster{
-		Peers: reportingPeers,
+		Peers:           reportingPeers,
+		RollCallLatency: rollCallLatencies(reportingPeers, latencies),
+		Strata:          strata,
 	}
 
+	n.executions.start(requestID, req.FunctionID, consensusAlgo, reportingPeers, span, req.Config.Watermark)
+	defer n.executions.done(requestID)
+
+	n.journalUpdate(requestID, func(e *journal.Entry) {
+		e.Peers = reportingPeers
+		e.Phase = journal.PhaseExecution
+		if consensusRequired(consensusAlgo) {
+			e.Phase = journal.PhaseClusterFormation
+		}
+	})
+
+	// When we're done, update worker dispatch limits based on how they handled the execution.
+	// Workers that did not respond are considered overloaded, unless we did not need to wait for them.
+	var (
+		results       execute.ResultMap
+		missingResult = dispatch.Aborted
+	)
+	defer func() {
+		if abandoned(ctx) {
+			missingResult = dispatch.Aborted
+		}
+		n.releaseWorkers(reportingPeers, results, missingResult)
+	}()
+
+	// Account resources consumed by the execution towards the request tags.
+	defer func() {
+		if shadow {
+			return
+		}
+		n.usage.Record(req.Config.Tags, results)
+		n.recordQuotaUsage(ctx, results)
+	}()
+
+	// Keep sending requests with the same affinity token to the workers that handled this one well.
+	defer func() {
+		n.recordAffinity(req, consensusAlgo, reportingPeers, results)
+	}()
+
 	// Phase 2. - Request cluster formation, if we need consensus.
 	if consensusRequired(consensusAlgo) {
 
 		log.Info().Strs("peers", blockless.PeerIDsToStr(reportingPeers)).Msg("requesting cluster formation from peers who reported for roll call")
 
+		clusterStart := time.Now()
 		err := n.formCluster(ctx, requestID, reportingPeers, consensusAlgo)
+		timing.ClusterFormation = execute.Since(clusterStart)
 		if err != nil {
-			return codes.Error, nil, execute.Cluster{}, fmt.Errorf("could not form cluster (request: %s): %w", requestID, err)
+			return codes.Error, nil, execute.Cluster{}, timing, fmt.Errorf("could not form cluster (request: %s): %w", requestID, err)
 		}
 
+		n.journalUpdate(requestID, func(e *journal.Entry) {
+			e.Phase = journal.PhaseExecution
+		})
+
 		// When we're done, send a message to disband the cluster.
 		// NOTE: We could schedule this on the worker nodes when receiving the execution request.
 		// One variant I tried is waiting on the execution to be done on the leader (using a timed wait on the execution response) and starting raft shutdown after.
@@ -128,52 +370,171 @@ func (n *Node) headExecute(ctx context.Context, requestID string, req execute.Re
 
 	// Send the execution request to peers in the cluster. Non-leaders will drop the request.
 	reqExecute := request.Execute{
-		Request:   req,
-		RequestID: requestID,
-		Timestamp: time.Now().UTC(),
+		Request:       req,
+		RequestID:     requestID,
+		Timestamp:     time.Now().UTC(),
+		DataAddresses: n.dataAddresses(),
+	}
+
+	// Record that the request passed through this node.
+	reqExecute.Provenance, err = provenance.Append(n.host.PrivateKey(), requestID, req, n.cfg.Role.String(), received)
+	if err != nil {
+		return codes.Error, nil, cluster, timing, fmt.Errorf("could not add head node to request provenance (function: %s, request: %s): %w", req.FunctionID, requestID, err)
+	}
+
+	// Let workers know how long the request is relevant for. The time left is sent instead of the deadline, so it does not
+	// matter if the clocks of the head node and the workers are not in sync.
+	deadline, ok := ctx.Deadline()
+	if ok {
+		reqExecute.Remaining = remainingTime(deadline)
 	}
 
-	// If we're working with PBFT, sign the request.
-	if consensusAlgo == consensus.PBFT {
+	// If we're working with PBFT or HotStuff, sign the request.
+	if byzantineFaultTolerant(consensusAlgo) {
 		err := reqExecute.Request.Sign(n.host.PrivateKey())
 		if err != nil {
-			return codes.Error, nil, cluster, fmt.Errorf("could not sign execution request (function: %s, request: %s): %w", req.FunctionID, requestID, err)
+			return codes.Error, nil, cluster, timing, fmt.Errorf("could not sign execution request (function: %s, request: %s): %w", req.FunctionID, requestID, err)
 		}
 	}
 
+	// Let workers stop working on the request if the client abandons it.
+	defer func() {
+		if !abandoned(ctx) {
+			return
+		}
+
+		err := n.abortExecution(requestID, reportingPeers, results)
+		if err != nil {
+			log.Warn().Err(err).Msg("could not abort execution on workers")
+		}
+	}()
+
 	err = n.sendToMany(ctx,
 		reportingPeers,
 		&reqExecute,
 		consensusRequired(consensusAlgo), // If we're using consensus, try to reach all peers.
 	)
 	if err != nil {
-		return codes.Error, nil, cluster, fmt.Errorf("could not send execution request to peers (function: %s, request: %s): %w", req.FunctionID, requestID, err)
+		return codes.Error, nil, cluster, timing, fmt.Errorf("could not send execution request to peers (function: %s, request: %s): %w", req.FunctionID, requestID, err)
 	}
 
 	log.Debug().Msg("waiting for execution responses")
 
-	var results execute.ResultMap
-	if consensusAlgo == consensus.PBFT {
-		results = n.gatherExecutionResultsPBFT(ctx, requestID, reportingPeers)
+	gatherStart := time.Now()
 
-		log.Info().Msg("received PBFT execution responses")
+	if byzantineFaultTolerant(consensusAlgo) {
+		results = n.gatherExecutionResultsBFT(ctx, requestID, reportingPeers, consensusAlgo)
+		timing.ResultGather = execute.Since(gatherStart)
+
+		log.Info().Stringer("consensus", consensusAlgo).Msg("received execution responses")
 
 		retcode := codes.OK
 		// Use the return code from the execution as the return code.
 		for _, res := range results {
 			retcode = res.Code
+			span.SetAttributes(b7ssemconv.ConsensusView.Int(int(res.PBFT.View)))
 			break
 		}
 
-		return retcode, results, cluster, nil
+		return retcode, results, cluster, timing, nil
+	}
+
+	// If the client requested verification, compare results from all peers.
+	if req.Config.Verify {
+
+		if consensusRequired(consensusAlgo) {
+			log.Warn().Msg("verification is not used for executions with consensus")
+		} else {
+			var dropped []peer.ID
+			results, dropped = n.gatherExecutionResults(ctx, requestID, reportingPeers)
+			timing.ResultGather = execute.Since(gatherStart)
+			missingResult = dispatch.Overload
+
+			n.metrics.IncrCounter(droppedPeersMetric, float32(len(dropped)))
+
+			// Peers that disconnected will not respond, so they are not expected to match.
+			quorum := req.Config.Quorum
+			if quorum == 0 {
+				quorum = uint(len(reportingPeers) - len(dropped))
+			}
+
+			verified, mismatched := verifyExecutionResults(results, quorum)
+			cluster.Mismatched = mismatched
+			for _, peer := range mismatched {
+				n.reputation.Record(peer, reputation.Mismatch)
+			}
+			if verified == nil {
+				n.metrics.IncrCounter(verificationFailedMetric, 1)
+				log.Warn().Uint("quorum", quorum).Int("responded", len(results)).Strs("mismatched", blockless.PeerIDsToStr(mismatched)).Msg("execution results could not be verified")
+				return codes.PartialContent, results, cluster, timing, nil
+			}
+
+			log.Info().Uint("quorum", quorum).Strs("mismatched", blockless.PeerIDsToStr(mismatched)).Msg("execution results verified")
+
+			return codes.OK, verified, cluster, timing, nil
+		}
 	}
 
-	results = n.gatherExecutionResults(ctx, requestID, reportingPeers)
+	// If the client requested a quorum of identical results, wait until we have it.
+	if req.Config.Quorum > 1 {
 
-	log.Info().Int("cluster_size", len(reportingPeers)).Int("responded", len(results)).Msg("received execution responses")
+		if consensusRequired(consensusAlgo) {
+			log.Warn().Uint("quorum", req.Config.Quorum).Msg("quorum is not used for executions with consensus")
+		} else {
+			var ok bool
+			results, ok = n.gatherExecutionResultsQuorum(ctx, requestID, reportingPeers, req.Config.Quorum)
+			timing.ResultGather = execute.Since(gatherStart)
+			if !ok {
+				missingResult = dispatch.Overload
+				log.Warn().Uint("quorum", req.Config.Quorum).Int("responded", len(results)).Msg("quorum not reached")
+				return codes.PartialContent, results, cluster, timing, nil
+			}
+
+			log.Info().Uint("quorum", req.Config.Quorum).Msg("received quorum of identical execution responses")
+
+			return codes.OK, results, cluster, timing, nil
+		}
+	}
+
+	expected := len(reportingPeers)
+
+	if consensusRequired(consensusAlgo) {
+		var dropped []peer.ID
+		results, dropped = n.gatherExecutionResults(ctx, requestID, reportingPeers)
+
+		// With Raft, only the cluster leader responds.
+		for peer := range results {
+			span.SetAttributes(b7ssemconv.ConsensusLeader.String(peer.String()))
+		}
+
+		// Peers that disconnected before responding will not respond, so we do not expect them to.
+		if len(dropped) > 0 {
+			n.metrics.IncrCounter(droppedPeersMetric, float32(len(dropped)))
+			log.Warn().Strs("dropped", blockless.PeerIDsToStr(dropped)).Msg("peers disconnected before sending execution responses")
+			expected -= len(dropped)
+		}
+	} else {
+		// Peers that fail to respond are replaced by peers from the roll call reserve.
+		var failovers []peer.ID
+		results, failovers = n.gatherExecutionResultsWithFailover(ctx, &reqExecute, reportingPeers, newPeerReserve(reserve), attempts)
+
+		reportingPeers = append(reportingPeers, failovers...)
+		cluster.Peers = reportingPeers
+		cluster.RollCallLatency = rollCallLatencies(reportingPeers, latencies)
+
+		// Unlike with Raft, where only the leader responds, peers without a result failed to respond in time.
+		missingResult = dispatch.Overload
+	}
+
+	timing.ResultGather = execute.Since(gatherStart)
+
+	log.Info().Int("cluster_size", expected).Int("responded", len(results)).Msg("received execution responses")
 
 	// How many results do we have, and how many do we expect.
-	respondRatio := float64(len(results)) / float64(len(reportingPeers))
+	var respondRatio float64
+	if expected > 0 {
+		respondRatio = float64(len(results)) / float64(expected)
+	}
 	threshold := determineThreshold(req)
 
 	retcode := codes.OK
@@ -184,7 +545,31 @@ func (n *Node) headExecute(ctx context.Context, requestID string, req execute.Re
 		retcode = codes.PartialContent
 	}
 
-	return retcode, results, cluster, nil
+	// Collapse results into a single response, if the node is configured to do so.
+	// NOTE: Full result set is kept in `results` since it's used to update worker dispatch limits.
+	if retcode == codes.OK && n.cfg.Aggregator != nil {
+		code, aggregated := n.cfg.Aggregator.Aggregate(results)
+
+		log.Info().Str("code", code.String()).Int("results", len(aggregated)).Msg("aggregated execution results")
+
+		return code, aggregated, cluster, timing, nil
+	}
+
+	return retcode, results, cluster, timing, nil
+}
+
+// rollCallLatencies returns the roll call round-trip times of the peers, in milliseconds.
+func rollCallLatencies(peers []peer.ID, latencies map[peer.ID]time.Duration) map[string]int64 {
+
+	out := make(map[string]int64, len(peers))
+	for _, peer := range peers {
+		latency, ok := latencies[peer]
+		if ok {
+			out[peer.String()] = latency.Milliseconds()
+		}
+	}
+
+	return out
 }
 
 func determineThreshold(req execute.Request) float64 {
@@ -195,3 +580,41 @@ func determineThreshold(req execute.Request) float64 {
 
 	return defaultExecutionThreshold
 }
+
+// releaseWorkers updates the dispatch limits and reputation for workers that were chosen for an execution.
+// Workers reporting a timeout or lack of resources are considered overloaded. Workers with no result get the given outcome.
+func (n *Node) releaseWorkers(peers []peer.ID, results execute.ResultMap, missing dispatch.Outcome) {
+
+	for _, peer := range peers {
+
+		res, ok := results[peer]
+		switch {
+		case !ok:
+			n.dispatch.Release(peer, missing)
+			// Aborted executions say nothing about the worker.
+			if missing == dispatch.Overload {
+				n.reputation.Record(peer, reputation.Timeout)
+			}
+		case res.Code == codes.Timeout || res.Code == codes.NotAvailable:
+			n.dispatch.Release(peer, dispatch.Overload)
+			n.reputation.Record(peer, reputation.Timeout)
+		case res.Code == codes.OK:
+			n.dispatch.Release(peer, dispatch.Success)
+			n.reputation.Record(peer, reputation.Success)
+		default:
+			n.dispatch.Release(peer, dispatch.Success)
+			n.reputation.Record(peer, reputation.Failure)
+		}
+	}
+}
+
+// remainingTime returns the time left until the deadline, sent to peers instead of the deadline itself.
+// Since zero means there is no limit, the time left for a deadline that already passed is negative.
+func remainingTime(deadline time.Time) time.Duration {
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return -time.Nanosecond
+	}
+
+	return remaining
+}
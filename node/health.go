@@ -19,7 +19,15 @@ func (n *Node) HealthPing(ctx context.Context) {
 		case <-ticker.C:
 
 			msg := response.Health{
-				Code: http.StatusOK,
+				Code:        http.StatusOK,
+				Role:        n.cfg.Role,
+				Attributes:  n.attributes,
+				NoConsensus: noConsensus(),
+			}
+
+			if n.isWorker() {
+				load := n.currentLoad()
+				msg.Load = &load
 			}
 
 			err := n.publish(ctx, &msg)
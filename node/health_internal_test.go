@@ -90,3 +90,63 @@ func TestNode_Health(t *testing.T) {
 	// Test should complete but not because of a timeout
 	require.NotErrorIsf(t, ctx.Err(), context.DeadlineExceeded, "health test timed out")
 }
+
+func TestNode_HealthRelayed(t *testing.T) {
+
+	const (
+		testTimeLimit = 10 * time.Second
+		topic         = DefaultTopic
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeLimit)
+	defer cancel()
+
+	head := createNode(t, blockless.HeadNode)
+
+	// Worker is not connected to the head node, it reaches it only via the relay.
+	worker, err := host.New(mocks.NoopLogger, loopback, 0)
+	require.NoError(t, err)
+
+	relay, err := host.New(mocks.NoopLogger, loopback, 0)
+	require.NoError(t, err)
+
+	hostAddNewPeer(t, relay, worker)
+	hostAddNewPeer(t, relay, head.host)
+	require.NoError(t, relay.Connect(ctx, *hostGetAddrInfo(t, worker)))
+	require.NoError(t, relay.Connect(ctx, *hostGetAddrInfo(t, head.host)))
+
+	require.NoError(t, head.subscribeToTopics(ctx))
+	go head.processTopicMessages(ctx, topic, head.subgroups.topics[topic].subscription)
+
+	for _, h := range []*host.Host{worker, relay} {
+		require.NoError(t, h.InitPubSub(ctx))
+	}
+
+	wtopic, _, err := worker.Subscribe(topic)
+	require.NoError(t, err)
+	_, _, err = relay.Subscribe(topic)
+	require.NoError(t, err)
+
+	load := response.Load{Executions: 3, Concurrency: 4}
+	payload, err := json.Marshal(response.Health{Code: http.StatusOK, Role: blockless.WorkerNode, Load: &load})
+	require.NoError(t, err)
+
+	// Keep publishing until the mesh forms and the health ping reaches the head node.
+	require.Eventually(t, func() bool {
+		require.NoError(t, wtopic.Publish(ctx, payload))
+
+		_, ok := head.peers.get(worker.ID())
+		return ok
+	}, testTimeLimit, 100*time.Millisecond)
+
+	// Health ping and the reported load are attributed to its author, not the relay.
+	_, ok := head.peers.get(relay.ID())
+	require.False(t, ok)
+
+	recorded, ok := head.loads.get(worker.ID())
+	require.True(t, ok)
+	require.Equal(t, load, recorded)
+
+	_, ok = head.loads.get(relay.ID())
+	require.False(t, ok)
+}
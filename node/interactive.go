@@ -0,0 +1,374 @@
+package node
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/armon/go-metrics"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/b7serrors"
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/node/head/journal"
+	"github.com/blocklessnetwork/b7s/node/internal/dispatch"
+	"github.com/blocklessnetwork/b7s/node/internal/workqueue"
+)
+
+var (
+	errInteractiveNotSupported = errors.New("interactive execution not supported by this node")
+	errLineTooLong             = fmt.Errorf("line too long (max: %v bytes)", interactiveMaxLineSize)
+)
+
+// listenInteractiveStreams handles streams of interactive executions. Head nodes relay the stream to a worker chosen by roll call,
+// workers run the function with its standard input and output attached to the stream.
+func (n *Node) listenInteractiveStreams(ctx context.Context) {
+	n.host.SetStreamHandler(blockless.InteractiveProtocolID, n.interactiveStreamHandler(ctx))
+}
+
+func (n *Node) interactiveStreamHandler(ctx context.Context) network.StreamHandler {
+
+	return func(stream network.Stream) {
+		defer stream.Close()
+
+		from := stream.Conn().RemotePeer()
+		out := newFrameWriter(stream)
+
+		// First line on the stream is the execution request, followed by frames with the standard input.
+		in := bufio.NewReader(stream)
+		payload, err := readLine(in, interactiveMaxLineSize)
+		if err != nil {
+			stream.Reset()
+			n.log.Error().Err(err).Str("peer", from.String()).Msg("could not receive interactive execution request")
+			return
+		}
+
+		var req request.InteractiveExecution
+		err = json.Unmarshal(payload, &req)
+		if err != nil {
+			out.finish(execute.Result{Code: codes.Invalid}, fmt.Errorf("could not unmarshal request: %w", err))
+			return
+		}
+
+		err = req.Valid()
+		if err != nil {
+			out.finish(execute.Result{Code: codes.Invalid}, err)
+			return
+		}
+
+		n.metrics.IncrCounterWithLabels(interactiveExecutionsMetric, 1, []metrics.Label{{Name: "function", Value: req.FunctionID}})
+
+		if n.isHead() {
+			err = n.headInteractiveExecution(ctx, from, req, in, stream, out)
+		} else {
+			err = n.workerInteractiveExecution(ctx, from, req, in, out)
+		}
+		if err != nil {
+			n.log.Error().Err(err).Str("peer", from.String()).Str("function", req.FunctionID).Msg("interactive execution failed")
+		}
+	}
+}
+
+// headInteractiveExecution chooses a worker for the interactive execution and relays the stream between the client and the worker.
+func (n *Node) headInteractiveExecution(ctx context.Context, from peer.ID, req request.InteractiveExecution, in *bufio.Reader, stream network.Stream, out *frameWriter) error {
+
+	if n.rateLimited(req.FunctionID, from) {
+		return out.finish(execute.Result{Code: codes.TooManyRequests}, b7serrors.ErrRateLimited)
+	}
+
+	ok, err := n.acquireClientSlot(ctx, from, req.Config.Priority)
+	if err != nil {
+		return fmt.Errorf("could not get execution slot for the client: %w", err)
+	}
+	if !ok {
+		return out.finish(execute.Result{Code: codes.TooManyInFlight}, b7serrors.ErrTooManyInFlight)
+	}
+	defer n.releaseClientSlot(from)
+
+	// Stop relaying the stream if the client disconnects.
+	ctx, cancel := n.clientContext(ctx, from)
+	defer cancel()
+
+	requestID := n.newRequestID()
+	log := n.log.With().Str("request", requestID).Str("function", req.FunctionID).Str("peer", from.String()).Logger()
+
+	// Interactive executions count towards the quota of the client, like any other execution.
+	ctx = withRequester(ctx, from)
+	err = n.admitExecution(ctx)
+	if err != nil {
+		if errors.Is(err, b7serrors.ErrQuotaExceeded) {
+			n.metrics.IncrCounter(quotaExceededMetric, 1)
+			return out.finish(execute.Result{Code: codes.QuotaExceeded}, err)
+		}
+
+		log.Warn().Err(err).Msg("could not check client quota")
+	}
+
+	n.journalUpdate(requestID, func(e *journal.Entry) {
+		e.Origin = from
+		e.ClientRequestID = req.RequestID
+		e.FunctionID = req.FunctionID
+		e.Phase = journal.PhaseRollCall
+	})
+	defer n.journalRemove(requestID)
+
+	// Interactive executions do not use consensus.
+	var noConsensus consensus.Type
+	peers, _, _, err := n.executeRollCall(ctx, requestID, req.FunctionID, 1, noConsensus, req.Topic, req.Config.Attributes, "")
+	if err != nil {
+		out.finish(execute.Result{Code: b7serrors.Code(err)}, err)
+		return fmt.Errorf("could not roll call peers (request: %s): %w", requestID, err)
+	}
+	// The head node only relays the stream, so it does not know how the worker handled the execution.
+	defer n.releaseWorkers(peers, nil, dispatch.Aborted)
+
+	worker := peers[0]
+
+	n.journalUpdate(requestID, func(e *journal.Entry) {
+		e.Phase = journal.PhaseExecution
+		e.Peers = peers
+	})
+
+	wstream, err := n.host.NewStream(ctx, worker, blockless.InteractiveProtocolID)
+	if err != nil {
+		out.finish(execute.Result{Code: codes.NotAvailable}, errors.New("could not reach worker"))
+		return fmt.Errorf("could not open stream to worker (peer: %s): %w", worker, err)
+	}
+	defer wstream.Close()
+
+	// Abandoned executions are no longer relayed.
+	stop := context.AfterFunc(ctx, func() {
+		wstream.Reset()
+	})
+	defer stop()
+
+	req.RequestID = requestID
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("could not encode request: %w", err)
+	}
+
+	_, err = wstream.Write(append(payload, '\n'))
+	if err != nil {
+		out.finish(execute.Result{Code: codes.NotAvailable}, errors.New("could not reach worker"))
+		return fmt.Errorf("could not send request to worker (peer: %s): %w", worker, err)
+	}
+
+	log.Info().Str("worker", worker.String()).Msg("relaying interactive execution")
+
+	// Relay standard input to the worker, and the standard output and result back to the client, until the worker is done.
+	go func() {
+		_, err := io.Copy(wstream, in)
+		if err != nil {
+			log.Debug().Err(err).Msg("stopped relaying standard input")
+		}
+		wstream.CloseWrite()
+	}()
+
+	// Relay the frames one by one, so the CPU time reported with the execution result is recorded towards the client quota.
+	wout := bufio.NewReader(wstream)
+	for {
+		line, err := readLine(wout, interactiveMaxLineSize)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("could not receive interactive execution frame (peer: %s): %w", worker, err)
+		}
+
+		_, err = stream.Write(line)
+		if err != nil {
+			return fmt.Errorf("could not relay interactive execution (peer: %s): %w", worker, err)
+		}
+
+		var frame execute.InteractiveFrame
+		err = json.Unmarshal(line, &frame)
+		if err == nil && frame.Result != nil {
+			n.recordQuotaUsage(ctx, execute.ResultMap{worker: execute.NodeResult{Result: *frame.Result}})
+		}
+	}
+
+	log.Info().Str("worker", worker.String()).Msg("interactive execution complete")
+
+	return nil
+}
+
+// workerInteractiveExecution runs the function with its standard input read from the stream, and its standard output written to the stream.
+func (n *Node) workerInteractiveExecution(ctx context.Context, from peer.ID, req request.InteractiveExecution, in *bufio.Reader, out *frameWriter) error {
+
+	if req.RequestID == "" {
+		return out.finish(execute.Result{Code: codes.Invalid}, errors.New("request ID must be set by the head node"))
+	}
+
+	if n.executors == nil {
+		return out.finish(execute.Result{Code: codes.NotSupported}, errInteractiveNotSupported)
+	}
+
+	if !n.functionPermitted(req.FunctionID) {
+		return out.finish(execute.Result{Code: codes.NotPermitted}, errFunctionNotPermitted)
+	}
+
+	log := n.log.With().Str("request", req.RequestID).Str("function", req.FunctionID).Logger()
+
+	err := n.awaitPrefetch(ctx, req.FunctionID)
+	if err != nil {
+		out.finish(execute.Result{Code: codes.Error}, errors.New("could not install function"))
+		return fmt.Errorf("could not install function: %w", err)
+	}
+
+	installed, err := n.fstore.IsInstalled(req.FunctionID)
+	if err != nil {
+		out.finish(execute.Result{Code: codes.Error}, errors.New("could not lookup function"))
+		return fmt.Errorf("could not lookup function in store: %w", err)
+	}
+	if !installed {
+		return out.finish(execute.Result{Code: codes.NotFound}, errors.New("function not installed"))
+	}
+
+	// Interactive executions take up a processing slot like any other execution.
+	err = n.work.Acquire(ctx, req.Config.Priority)
+	if errors.Is(err, workqueue.ErrFull) {
+		n.metrics.IncrCounterWithLabels(workQueueRejectedMetric, 1, []metrics.Label{{Name: "source", Value: "interactive"}})
+		return out.finish(execute.Result{Code: codes.Overloaded}, err)
+	}
+	if err != nil {
+		return fmt.Errorf("could not get a processing slot: %w", err)
+	}
+	defer n.work.Release()
+
+	// Use a pipe for the standard input, so the function sees it as a file and is not waited on once it exits.
+	stdin, stdinWriter, err := os.Pipe()
+	if err != nil {
+		out.finish(execute.Result{Code: codes.Error}, errors.New("could not prepare standard input"))
+		return fmt.Errorf("could not create standard input pipe: %w", err)
+	}
+	defer stdin.Close()
+	defer stdinWriter.Close()
+
+	go relayStdin(in, stdinWriter)
+
+	log.Info().Str("peer", from.String()).Msg("starting interactive execution")
+
+	// NOTE: Like with other executions, the result code tells the client how the execution went, not the error.
+	res, err := n.executors.ExecuteInteractive(ctx, req.RequestID, req.Request, stdin, out)
+	if err != nil {
+		log.Error().Err(err).Msg("interactive execution failed")
+	}
+
+	log.Info().Str("code", res.Code.String()).Msg("interactive execution complete")
+
+	return out.finish(res, nil)
+}
+
+// relayStdin writes the standard input carried by the frames the client sends to the function.
+func relayStdin(in *bufio.Reader, stdin io.WriteCloser) {
+	defer stdin.Close()
+
+	for {
+		line, err := readLine(in, interactiveMaxLineSize)
+		if err != nil {
+			return
+		}
+
+		var frame execute.InteractiveFrame
+		err = json.Unmarshal(line, &frame)
+		if err != nil {
+			return
+		}
+
+		if len(frame.Stdin) > 0 {
+			_, err = stdin.Write(frame.Stdin)
+			if err != nil {
+				return
+			}
+		}
+
+		if frame.CloseStdin {
+			return
+		}
+	}
+}
+
+// readLine reads a single line, up to the given number of bytes. Lines not terminated by a newline are returned along with the error.
+func readLine(in *bufio.Reader, limit int) ([]byte, error) {
+
+	var line []byte
+	for {
+		chunk, err := in.ReadSlice('\n')
+		if len(line)+len(chunk) > limit {
+			return nil, errLineTooLong
+		}
+
+		line = append(line, chunk...)
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+
+		return line, err
+	}
+}
+
+// frameWriter writes interactive frames to the stream. It is safe for concurrent use.
+type frameWriter struct {
+	sync.Mutex
+	w io.Writer
+}
+
+func newFrameWriter(w io.Writer) *frameWriter {
+	return &frameWriter{
+		w: w,
+	}
+}
+
+// Write sends the data as a chunk of standard output, so the writer can be used as the standard output of the function.
+func (f *frameWriter) Write(p []byte) (int, error) {
+
+	err := f.send(execute.InteractiveFrame{Stdout: p})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// finish sends the execution result as the last frame on the stream.
+func (f *frameWriter) finish(res execute.Result, reason error) error {
+
+	frame := execute.InteractiveFrame{
+		Result: &res,
+	}
+	if reason != nil {
+		frame.ErrorMessage = reason.Error()
+	}
+
+	err := f.send(frame)
+	if err != nil {
+		return fmt.Errorf("could not send execution result: %w", err)
+	}
+
+	return nil
+}
+
+func (f *frameWriter) send(frame execute.InteractiveFrame) error {
+
+	payload, err := json.Marshal(frame)
+	if err != nil {
+		return fmt.Errorf("could not encode frame: %w", err)
+	}
+
+	f.Lock()
+	defer f.Unlock()
+
+	_, err = f.w.Write(append(payload, '\n'))
+	return err
+}
@@ -0,0 +1,255 @@
+package node
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/node/head/journal"
+	"github.com/blocklessnetwork/b7s/node/head/quota"
+	"github.com/blocklessnetwork/b7s/testing/helpers"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+// echoExecutor echoes the standard input of interactive executions.
+type echoExecutor struct {
+	mocks.Executor
+}
+
+func (echoExecutor) ExecuteInteractive(_ context.Context, _ string, _ execute.Request, stdin io.Reader, stdout io.Writer) (execute.Result, error) {
+
+	_, err := io.Copy(stdout, stdin)
+	if err != nil {
+		return execute.Result{Code: codes.Error}, err
+	}
+
+	return execute.Result{Code: codes.OK}, nil
+}
+
+func TestNode_InteractiveExecution(t *testing.T) {
+
+	const (
+		functionID = "dummy-function-id"
+		input      = "dummy-input"
+	)
+
+	newWorker := func(t *testing.T, ctx context.Context, executor blockless.Executor) *Node {
+		t.Helper()
+
+		worker := createNode(t, blockless.WorkerNode)
+		worker.executors.current = executor
+		worker.listenInteractiveStreams(ctx)
+
+		return worker
+	}
+
+	newClient := func(t *testing.T) *host.Host {
+		t.Helper()
+
+		client, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+		t.Cleanup(func() { client.Close() })
+
+		return client
+	}
+
+	// Open an interactive execution stream from the client to the node, send the input and return the output along with the final frame.
+	interactAs := func(t *testing.T, client *host.Host, node *Node, req request.InteractiveExecution) (string, execute.InteractiveFrame) {
+		t.Helper()
+
+		hostAddNewPeer(t, client, node.host)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		stream, err := client.NewStream(ctx, node.host.ID(), blockless.InteractiveProtocolID)
+		require.NoError(t, err)
+		defer stream.Close()
+
+		enc := json.NewEncoder(stream)
+		require.NoError(t, enc.Encode(req))
+		require.NoError(t, enc.Encode(execute.InteractiveFrame{Stdin: []byte(input), CloseStdin: true}))
+
+		var stdout bytes.Buffer
+		dec := json.NewDecoder(stream)
+		for {
+			var frame execute.InteractiveFrame
+			require.NoError(t, dec.Decode(&frame))
+
+			if frame.Result != nil {
+				return stdout.String(), frame
+			}
+
+			stdout.Write(frame.Stdout)
+		}
+	}
+
+	interact := func(t *testing.T, node *Node, req request.InteractiveExecution) (string, execute.InteractiveFrame) {
+		t.Helper()
+		return interactAs(t, newClient(t), node, req)
+	}
+
+	req := request.InteractiveExecution{
+		Request:   execute.Request{FunctionID: functionID, Method: "dummy-method"},
+		RequestID: "dummy-request-id",
+	}
+
+	t.Run("worker streams standard input and output", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		worker := newWorker(t, ctx, &echoExecutor{Executor: *mocks.BaselineExecutor(t)})
+
+		stdout, frame := interact(t, worker, req)
+		require.Equal(t, input, stdout)
+		require.Equal(t, codes.OK, frame.Result.Code)
+		require.Empty(t, frame.ErrorMessage)
+	})
+	t.Run("worker requires request ID from the head node", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		worker := newWorker(t, ctx, &echoExecutor{Executor: *mocks.BaselineExecutor(t)})
+
+		req := req
+		req.RequestID = ""
+
+		stdout, frame := interact(t, worker, req)
+		require.Empty(t, stdout)
+		require.Equal(t, codes.Invalid, frame.Result.Code)
+	})
+	t.Run("executor without interactive support is reported", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		worker := newWorker(t, ctx, mocks.BaselineExecutor(t))
+
+		stdout, frame := interact(t, worker, req)
+		require.Empty(t, stdout)
+		require.Equal(t, codes.NotSupported, frame.Result.Code)
+	})
+	t.Run("interactive executions run on a single worker", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		worker := newWorker(t, ctx, &echoExecutor{Executor: *mocks.BaselineExecutor(t)})
+
+		req := req
+		req.Config.NodeCount = 3
+
+		_, frame := interact(t, worker, req)
+		require.Equal(t, codes.Invalid, frame.Result.Code)
+		require.NotEmpty(t, frame.ErrorMessage)
+	})
+	t.Run("head node relays the stream to a worker", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		worker := newWorker(t, ctx, &echoExecutor{Executor: *mocks.BaselineExecutor(t)})
+
+		head := createNode(t, blockless.HeadNode)
+		head.listenInteractiveStreams(ctx)
+
+		hostAddNewPeer(t, head.host, worker.host)
+		err := head.host.Connect(ctx, *hostGetAddrInfo(t, worker.host))
+		require.NoError(t, err)
+
+		// Skip the roll call by having the worker in the warm pool.
+		head.pools.set(functionID, "", []peer.ID{worker.host.ID()}, time.Now().Add(time.Minute))
+
+		req := req
+		req.RequestID = ""
+
+		stdout, frame := interact(t, head, req)
+		require.Equal(t, input, stdout)
+		require.Equal(t, codes.OK, frame.Result.Code)
+	})
+	t.Run("head node records interactive executions towards the client quota", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		worker := newWorker(t, ctx, &echoExecutor{Executor: *mocks.BaselineExecutor(t)})
+
+		db := helpers.InMemoryDB(t)
+		defer db.Close()
+
+		head := createNode(t, blockless.HeadNode)
+		head.cfg.Quotas = quota.NewTracker(db, quota.Limits{ExecutionsPerHour: 1})
+		head.cfg.Journal = journal.New(db)
+		head.listenInteractiveStreams(ctx)
+
+		hostAddNewPeer(t, head.host, worker.host)
+		err := head.host.Connect(ctx, *hostGetAddrInfo(t, worker.host))
+		require.NoError(t, err)
+
+		head.pools.set(functionID, "", []peer.ID{worker.host.ID()}, time.Now().Add(time.Minute))
+
+		req := req
+		req.RequestID = ""
+
+		client := newClient(t)
+
+		stdout, frame := interactAs(t, client, head, req)
+		require.Equal(t, input, stdout)
+		require.Equal(t, codes.OK, frame.Result.Code)
+
+		// Interactive execution is done, so it is no longer in the journal.
+		entries, _, err := head.cfg.Journal.Scan()
+		require.NoError(t, err)
+		require.Empty(t, entries)
+
+		_, frame = interactAs(t, client, head, req)
+		require.Equal(t, codes.QuotaExceeded, frame.Result.Code)
+	})
+}
+
+func TestReadLine(t *testing.T) {
+
+	const limit = 16
+
+	t.Run("lines are read one by one", func(t *testing.T) {
+		in := bufio.NewReader(strings.NewReader("first\nsecond\n"))
+
+		line, err := readLine(in, limit)
+		require.NoError(t, err)
+		require.Equal(t, "first\n", string(line))
+
+		line, err = readLine(in, limit)
+		require.NoError(t, err)
+		require.Equal(t, "second\n", string(line))
+
+		_, err = readLine(in, limit)
+		require.ErrorIs(t, err, io.EOF)
+	})
+	t.Run("line over the limit is rejected", func(t *testing.T) {
+		in := bufio.NewReaderSize(strings.NewReader(strings.Repeat("a", 64)+"\n"), limit)
+
+		_, err := readLine(in, limit)
+		require.ErrorIs(t, err, errLineTooLong)
+	})
+}
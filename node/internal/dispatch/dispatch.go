@@ -0,0 +1,121 @@
+package dispatch
+
+import (
+	"math"
+	"sync"
+)
+
+// Outcome describes how a dispatched execution ended, from the perspective of the executing peer load.
+type Outcome uint8
+
+const (
+	// Aborted executions say nothing about the peer load, e.g. when the execution was cancelled early.
+	Aborted Outcome = iota
+	// Success means the peer executed the request in time.
+	Success
+	// Overload means the peer timed out or reported it is out of resources.
+	Overload
+)
+
+// Limiter tracks the number of executions dispatched to each peer, and how many executions a peer can take at a time.
+// Limits are adjusted using AIMD (additive increase, multiplicative decrease) - limit of a peer is cut on overload
+// and recovers gradually as the peer successfully completes executions.
+type Limiter[K comparable] struct {
+	sync.Mutex
+
+	max   float64
+	peers map[K]*peerState
+}
+
+type peerState struct {
+	limit    float64
+	inflight uint
+}
+
+const (
+	minLimit = 1.0
+	// Limit is increased by roughly one over the course of `limit` successful executions.
+	additiveIncrease = 1.0
+	// Limit is halved on overload.
+	multiplicativeDecrease = 0.5
+)
+
+// New creates a new Limiter, with the given maximum of concurrent executions per peer.
+func New[K comparable](max uint) *Limiter[K] {
+
+	l := Limiter[K]{
+		max:   math.Max(float64(max), minLimit),
+		peers: make(map[K]*peerState),
+	}
+
+	return &l
+}
+
+// Acquire records an execution dispatched to the peer. It returns false if the peer is at its limit.
+func (l *Limiter[K]) Acquire(peer K) bool {
+
+	l.Lock()
+	defer l.Unlock()
+
+	state := l.state(peer)
+	if float64(state.inflight+1) > math.Floor(state.limit) {
+		return false
+	}
+
+	state.inflight++
+
+	return true
+}
+
+// Release records the end of an execution dispatched to the peer, and adjusts the peer limit based on the outcome.
+func (l *Limiter[K]) Release(peer K, outcome Outcome) {
+
+	l.Lock()
+	defer l.Unlock()
+
+	state := l.state(peer)
+	if state.inflight > 0 {
+		state.inflight--
+	}
+
+	switch outcome {
+	case Success:
+		state.limit = math.Min(state.limit+additiveIncrease/state.limit, l.max)
+	case Overload:
+		state.limit = math.Max(state.limit*multiplicativeDecrease, minLimit)
+	}
+
+	// No need to track peers with no executions and a full limit.
+	if state.inflight == 0 && state.limit >= l.max {
+		delete(l.peers, peer)
+	}
+}
+
+// Limit returns the current limit for the peer.
+func (l *Limiter[K]) Limit(peer K) uint {
+
+	l.Lock()
+	defer l.Unlock()
+
+	state, ok := l.peers[peer]
+	if !ok {
+		return uint(l.max)
+	}
+
+	return uint(state.limit)
+}
+
+// state returns the state for the peer, creating it if needed.
+// NOTE: Caller should hold the lock.
+func (l *Limiter[K]) state(peer K) *peerState {
+
+	state, ok := l.peers[peer]
+	if !ok {
+		state = &peerState{
+			limit: l.max,
+		}
+		l.peers[peer] = state
+	}
+
+	return state
+}
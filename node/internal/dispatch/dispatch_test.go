@@ -0,0 +1,90 @@
+package dispatch_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/node/internal/dispatch"
+)
+
+func TestLimiter(t *testing.T) {
+
+	const (
+		peer = "dummy-peer"
+		max  = 4
+	)
+
+	t.Run("peer is limited to the maximum", func(t *testing.T) {
+		t.Parallel()
+
+		limiter := dispatch.New[string](max)
+
+		for i := 0; i < max; i++ {
+			require.True(t, limiter.Acquire(peer))
+		}
+		require.False(t, limiter.Acquire(peer))
+
+		limiter.Release(peer, dispatch.Success)
+		require.True(t, limiter.Acquire(peer))
+	})
+	t.Run("overload cuts the limit", func(t *testing.T) {
+		t.Parallel()
+
+		limiter := dispatch.New[string](max)
+
+		require.True(t, limiter.Acquire(peer))
+		limiter.Release(peer, dispatch.Overload)
+		require.Equal(t, uint(2), limiter.Limit(peer))
+
+		require.True(t, limiter.Acquire(peer))
+		limiter.Release(peer, dispatch.Overload)
+		require.Equal(t, uint(1), limiter.Limit(peer))
+
+		// Limit does not go below one.
+		require.True(t, limiter.Acquire(peer))
+		limiter.Release(peer, dispatch.Overload)
+		require.Equal(t, uint(1), limiter.Limit(peer))
+
+		require.True(t, limiter.Acquire(peer))
+		require.False(t, limiter.Acquire(peer))
+	})
+	t.Run("limit recovers gradually", func(t *testing.T) {
+		t.Parallel()
+
+		limiter := dispatch.New[string](max)
+
+		require.True(t, limiter.Acquire(peer))
+		limiter.Release(peer, dispatch.Overload)
+		require.Equal(t, uint(2), limiter.Limit(peer))
+
+		// Limit grows by roughly one for every `limit` successful executions.
+		for i := 0; i < 2; i++ {
+			require.True(t, limiter.Acquire(peer))
+			limiter.Release(peer, dispatch.Success)
+			require.Equal(t, uint(2), limiter.Limit(peer))
+		}
+
+		require.True(t, limiter.Acquire(peer))
+		limiter.Release(peer, dispatch.Success)
+		require.Equal(t, uint(3), limiter.Limit(peer))
+
+		for i := 0; i < 10; i++ {
+			require.True(t, limiter.Acquire(peer))
+			limiter.Release(peer, dispatch.Success)
+		}
+		require.Equal(t, uint(max), limiter.Limit(peer))
+	})
+	t.Run("aborted executions do not change the limit", func(t *testing.T) {
+		t.Parallel()
+
+		limiter := dispatch.New[string](max)
+
+		require.True(t, limiter.Acquire(peer))
+		limiter.Release(peer, dispatch.Overload)
+
+		require.True(t, limiter.Acquire(peer))
+		limiter.Release(peer, dispatch.Aborted)
+		require.Equal(t, uint(2), limiter.Limit(peer))
+	})
+}
@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"sync"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+	"golang.org/x/time/rate"
+)
+
+// Wildcard is the function ID of the limit that applies to functions without a limit of their own.
+const Wildcard = "*"
+
+// maxTracked is the maximum number of per-peer rate limiters, and of wildcard rate limiters, kept at a time.
+// Least recently used ones are dropped first.
+const maxTracked = 10_000
+
+// Limit describes how many execution requests are allowed for a function.
+type Limit struct {
+	Rate    float64 // Number of requests allowed per second.
+	Burst   uint    // Number of requests allowed at once.
+	PerPeer bool    // Limit applies to each requesting peer separately.
+}
+
+// Limiter limits the rate of execution requests per function, and optionally per requesting peer.
+// Rate limiters of limits that apply to all peers are kept apart from the per-peer ones, so requests from many peers
+// cannot push them out and reset them. Those of functions with a limit of their own are never dropped.
+type Limiter struct {
+	sync.Mutex
+
+	limits   map[string]Limit
+	global   map[string]*rate.Limiter
+	wildcard *simplelru.LRU
+	perPeer  *simplelru.LRU
+}
+
+// New creates a new Limiter with the given limits, mapped by function ID.
+func New(limits map[string]Limit) *Limiter {
+
+	global := make(map[string]*rate.Limiter)
+	for functionID, limit := range limits {
+		if functionID != Wildcard && !limit.PerPeer {
+			global[functionID] = newLimiter(limit)
+		}
+	}
+
+	// Only possible cause of an error is providing an invalid size value
+	wildcard, _ := simplelru.NewLRU(maxTracked, nil)
+	perPeer, _ := simplelru.NewLRU(maxTracked, nil)
+
+	l := Limiter{
+		limits:   limits,
+		global:   global,
+		wildcard: wildcard,
+		perPeer:  perPeer,
+	}
+
+	return &l
+}
+
+// Allow returns true if the execution request for the function, coming from the given peer, is within limits.
+func (l *Limiter) Allow(functionID string, peer string) bool {
+
+	limit, ok := l.limits[functionID]
+	if !ok {
+		limit, ok = l.limits[Wildcard]
+		if !ok {
+			return true
+		}
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	if limit.PerPeer {
+		return l.allowFrom(l.perPeer, functionID+"/"+peer, limit)
+	}
+
+	limiter, ok := l.global[functionID]
+	if ok {
+		return limiter.Allow()
+	}
+
+	return l.allowFrom(l.wildcard, functionID, limit)
+}
+
+func (l *Limiter) allowFrom(limiters *simplelru.LRU, key string, limit Limit) bool {
+
+	limiter, ok := limiters.Get(key)
+	if !ok {
+		limiter = newLimiter(limit)
+		limiters.Add(key, limiter)
+	}
+
+	return limiter.(*rate.Limiter).Allow()
+}
+
+func newLimiter(limit Limit) *rate.Limiter {
+	return rate.NewLimiter(rate.Limit(limit.Rate), int(limit.Burst))
+}
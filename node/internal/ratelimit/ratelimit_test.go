@@ -0,0 +1,84 @@
+package ratelimit_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/node/internal/ratelimit"
+)
+
+func TestLimiter(t *testing.T) {
+
+	const (
+		function = "dummy-function"
+		other    = "other-function"
+		peer     = "dummy-peer"
+	)
+
+	t.Run("requests over the limit are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		limiter := ratelimit.New(map[string]ratelimit.Limit{
+			function: {Rate: 0.001, Burst: 2},
+		})
+
+		require.True(t, limiter.Allow(function, peer))
+		require.True(t, limiter.Allow(function, "other-peer"))
+		require.False(t, limiter.Allow(function, peer))
+
+		// Functions without limits are not limited.
+		for i := 0; i < 10; i++ {
+			require.True(t, limiter.Allow(other, peer))
+		}
+	})
+	t.Run("per peer limits are separate", func(t *testing.T) {
+		t.Parallel()
+
+		limiter := ratelimit.New(map[string]ratelimit.Limit{
+			function: {Rate: 0.001, Burst: 1, PerPeer: true},
+		})
+
+		require.True(t, limiter.Allow(function, peer))
+		require.False(t, limiter.Allow(function, peer))
+		require.True(t, limiter.Allow(function, "other-peer"))
+	})
+	t.Run("wildcard limit applies to other functions", func(t *testing.T) {
+		t.Parallel()
+
+		limiter := ratelimit.New(map[string]ratelimit.Limit{
+			ratelimit.Wildcard: {Rate: 0.001, Burst: 1},
+			function:           {Rate: 0.001, Burst: 2},
+		})
+
+		require.True(t, limiter.Allow(other, peer))
+		require.False(t, limiter.Allow(other, peer))
+
+		// Wildcard limit applies to each function separately.
+		require.True(t, limiter.Allow("third-function", peer))
+
+		require.True(t, limiter.Allow(function, peer))
+		require.True(t, limiter.Allow(function, peer))
+		require.False(t, limiter.Allow(function, peer))
+	})
+	t.Run("requests from many peers do not reset limits for all peers", func(t *testing.T) {
+		t.Parallel()
+
+		limiter := ratelimit.New(map[string]ratelimit.Limit{
+			function:           {Rate: 0.001, Burst: 1},
+			other:              {Rate: 0.001, Burst: 1, PerPeer: true},
+			ratelimit.Wildcard: {Rate: 0.001, Burst: 1},
+		})
+
+		require.True(t, limiter.Allow(function, peer))
+		require.True(t, limiter.Allow("third-function", peer))
+
+		for i := range 20_000 {
+			limiter.Allow(other, fmt.Sprintf("peer-%d", i))
+		}
+
+		require.False(t, limiter.Allow(function, peer))
+		require.False(t, limiter.Allow("third-function", peer))
+	})
+}
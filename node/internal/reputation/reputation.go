@@ -0,0 +1,165 @@
+package reputation
+
+import (
+	"slices"
+	"sync"
+)
+
+// Outcome describes how a peer handled an execution.
+type Outcome uint8
+
+const (
+	// Success means the peer returned a successful result.
+	Success Outcome = iota
+	// Failure means the peer returned a failed result.
+	Failure
+	// Timeout means the peer did not return a result in time.
+	Timeout
+	// Mismatch means the peer returned a result that did not match the verified result.
+	Mismatch
+)
+
+const (
+	// Score of peers we know nothing about.
+	neutralScore = 0.5
+	// Timeouts cost more than failures - they hold up the execution until the peer is given up on.
+	timeoutPenalty = 0.5
+	// Incorrect results are worse than failing outright.
+	mismatchPenalty = 1.5
+	// Weight of previous outcomes is reduced with each new one, so peers can recover from past problems.
+	decay = 0.98
+)
+
+// Tracker keeps track of how peers handled executions, and scores them based on their history.
+type Tracker[K comparable] struct {
+	sync.Mutex
+
+	peers map[K]*History
+}
+
+// History describes the (decayed) outcomes of executions on a peer.
+type History struct {
+	Executions float64 `json:"executions"`
+	Successes  float64 `json:"successes"`
+	Timeouts   float64 `json:"timeouts"`
+	Mismatches float64 `json:"mismatches"`
+}
+
+// New creates a new Tracker.
+func New[K comparable]() *Tracker[K] {
+
+	t := Tracker[K]{
+		peers: make(map[K]*History),
+	}
+
+	return &t
+}
+
+// Record records the outcome of an execution on the peer. Mismatch is recorded in addition to the outcome of the execution.
+func (t *Tracker[K]) Record(peer K, outcome Outcome) {
+
+	t.Lock()
+	defer t.Unlock()
+
+	h, ok := t.peers[peer]
+	if !ok {
+		h = &History{}
+		t.peers[peer] = h
+	}
+
+	if outcome == Mismatch {
+		h.Mismatches++
+		return
+	}
+
+	h.Executions = h.Executions*decay + 1
+	h.Successes *= decay
+	h.Timeouts *= decay
+	h.Mismatches *= decay
+
+	switch outcome {
+	case Success:
+		h.Successes++
+	case Timeout:
+		h.Timeouts++
+	}
+}
+
+// Export returns a copy of the history of all known peers.
+func (t *Tracker[K]) Export() map[K]History {
+
+	t.Lock()
+	defer t.Unlock()
+
+	out := make(map[K]History, len(t.peers))
+	for peer, h := range t.peers {
+		out[peer] = *h
+	}
+
+	return out
+}
+
+// Import sets the history of the given peers, e.g. one exported by a previous run. Existing history of these peers is replaced.
+func (t *Tracker[K]) Import(peers map[K]History) {
+
+	t.Lock()
+	defer t.Unlock()
+
+	for peer, h := range peers {
+		t.peers[peer] = &h
+	}
+}
+
+// Score returns the score of the peer, between 0 and 1. Higher is better.
+func (t *Tracker[K]) Score(peer K) float64 {
+
+	t.Lock()
+	defer t.Unlock()
+
+	return t.score(peer)
+}
+
+// Rank orders the peers by score, best first. Peers with the same score keep their order.
+// If set, weight scales the score of each peer, allowing other criteria to be taken into account.
+func (t *Tracker[K]) Rank(peers []K, weight func(K) float64) []K {
+
+	t.Lock()
+	defer t.Unlock()
+
+	scores := make(map[K]float64, len(peers))
+	for _, peer := range peers {
+		scores[peer] = t.score(peer)
+		if weight != nil {
+			scores[peer] *= weight(peer)
+		}
+	}
+
+	ranked := slices.Clone(peers)
+	slices.SortStableFunc(ranked, func(a, b K) int {
+		switch {
+		case scores[a] > scores[b]:
+			return -1
+		case scores[a] < scores[b]:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	return ranked
+}
+
+// score calculates the score of the peer. Counts are smoothed so that a single outcome does not decide the score.
+// NOTE: Caller should hold the lock.
+func (t *Tracker[K]) score(peer K) float64 {
+
+	h, ok := t.peers[peer]
+	if !ok {
+		return neutralScore
+	}
+
+	total := h.Executions + 2
+	score := (h.Successes + 1 - timeoutPenalty*h.Timeouts - mismatchPenalty*h.Mismatches) / total
+
+	return min(max(score, 0), 1)
+}
@@ -0,0 +1,96 @@
+package reputation_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/node/internal/reputation"
+)
+
+func TestTracker(t *testing.T) {
+
+	const (
+		reliable   = "reliable-peer"
+		unknown    = "unknown-peer"
+		failing    = "failing-peer"
+		slow       = "slow-peer"
+		incorrect  = "incorrect-peer"
+		executions = 10
+	)
+
+	tracker := reputation.New[string]()
+	for i := 0; i < executions; i++ {
+		tracker.Record(reliable, reputation.Success)
+		tracker.Record(failing, reputation.Failure)
+		tracker.Record(slow, reputation.Timeout)
+		tracker.Record(incorrect, reputation.Success)
+		tracker.Record(incorrect, reputation.Mismatch)
+	}
+
+	t.Run("peers are scored by their history", func(t *testing.T) {
+		t.Parallel()
+
+		require.Greater(t, tracker.Score(reliable), tracker.Score(unknown))
+		require.Greater(t, tracker.Score(unknown), tracker.Score(failing))
+		require.GreaterOrEqual(t, tracker.Score(failing), tracker.Score(slow))
+		require.GreaterOrEqual(t, tracker.Score(failing), tracker.Score(incorrect))
+
+		for _, peer := range []string{reliable, unknown, failing, slow, incorrect} {
+			score := tracker.Score(peer)
+			require.GreaterOrEqual(t, score, 0.0)
+			require.LessOrEqual(t, score, 1.0)
+		}
+	})
+	t.Run("peers are ranked by score", func(t *testing.T) {
+		t.Parallel()
+
+		ranked := tracker.Rank([]string{failing, unknown, reliable}, nil)
+		require.Equal(t, []string{reliable, unknown, failing}, ranked)
+	})
+	t.Run("peers with the same score keep their order", func(t *testing.T) {
+		t.Parallel()
+
+		ranked := tracker.Rank([]string{"first", "second", "third"}, nil)
+		require.Equal(t, []string{"first", "second", "third"}, ranked)
+	})
+	t.Run("score can be weighted", func(t *testing.T) {
+		t.Parallel()
+
+		weight := func(peer string) float64 {
+			if peer == reliable {
+				return 0.1
+			}
+			return 1
+		}
+
+		ranked := tracker.Rank([]string{reliable, unknown}, weight)
+		require.Equal(t, []string{unknown, reliable}, ranked)
+	})
+	t.Run("peers recover from past problems", func(t *testing.T) {
+		t.Parallel()
+
+		tracker := reputation.New[string]()
+		for i := 0; i < executions; i++ {
+			tracker.Record(failing, reputation.Failure)
+		}
+		before := tracker.Score(failing)
+
+		for i := 0; i < 5*executions; i++ {
+			tracker.Record(failing, reputation.Success)
+		}
+
+		require.Greater(t, tracker.Score(failing), before)
+		require.Greater(t, tracker.Score(failing), tracker.Score(unknown))
+	})
+	t.Run("history can be exported and imported", func(t *testing.T) {
+		t.Parallel()
+
+		imported := reputation.New[string]()
+		imported.Import(tracker.Export())
+
+		for _, peer := range []string{reliable, unknown, failing, slow, incorrect} {
+			require.Equal(t, tracker.Score(peer), imported.Score(peer))
+		}
+	})
+}
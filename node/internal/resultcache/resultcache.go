@@ -0,0 +1,132 @@
+package resultcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+// Cache keeps execution results for a limited amount of time, so identical invocations of a function can be served without executing it again.
+// Cache size is bounded - once full, the least recently used results are evicted first.
+type Cache struct {
+	sync.Mutex
+
+	ttl   time.Duration
+	cache *simplelru.LRU
+}
+
+type entry struct {
+	result  execute.Result
+	expires time.Time
+}
+
+// New creates a new Cache, keeping results for the given amount of time and holding at most the given number of results.
+func New(ttl time.Duration, size int) *Cache {
+
+	if size <= 0 {
+		size = math.MaxInt
+	}
+
+	// Only possible cause of an error is providing an invalid size value
+	cache, _ := simplelru.NewLRU(size, nil)
+
+	c := Cache{
+		ttl:   ttl,
+		cache: cache,
+	}
+
+	return &c
+}
+
+// Get returns the cached result for the key, if there is one that has not yet expired.
+func (c *Cache) Get(key string) (execute.Result, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	value, ok := c.cache.Get(key)
+	if !ok {
+		return execute.Result{}, false
+	}
+
+	e := value.(entry)
+	if !time.Now().Before(e.expires) {
+		c.cache.Remove(key)
+		return execute.Result{}, false
+	}
+
+	return e.result, true
+}
+
+// Add caches the result under the given key.
+func (c *Cache) Add(key string, result execute.Result) {
+	c.Lock()
+	defer c.Unlock()
+
+	e := entry{
+		result:  result,
+		expires: time.Now().Add(c.ttl),
+	}
+
+	c.cache.Add(key, e)
+}
+
+// Cacheable returns true if results of the execution can be cached. Remote inputs without a checksum can change
+// between executions, so results of executions using them are not cached.
+func Cacheable(req execute.Request) bool {
+
+	for _, input := range req.Config.Inputs {
+		if input.Checksum == "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Key returns the cache key for the execution request. Key is a hash of everything that determines the function output -
+// the function, its arguments, inputs and environment, as well as the resource limits and timeout, since an execution
+// running out of resources or time produces a different output. Tenant and watermark are included too, so results are
+// never shared between tenants.
+func Key(req execute.Request) string {
+
+	invocation := struct {
+		FunctionID  string                   `json:"function_id"`
+		Method      string                   `json:"method"`
+		Parameters  []execute.Parameter      `json:"parameters,omitempty"`
+		Environment []execute.EnvVar         `json:"env_vars,omitempty"`
+		Stdin       *string                  `json:"stdin,omitempty"`
+		Permissions []string                 `json:"permissions,omitempty"`
+		Runtime     execute.BLSRuntimeConfig `json:"runtime,omitempty"`
+		Inputs      []execute.Input          `json:"inputs,omitempty"`
+		Limits      *execute.ResourceLimits  `json:"limits,omitempty"`
+		Timeout     int                      `json:"timeout,omitempty"`
+		Watermark   string                   `json:"watermark,omitempty"`
+		Tenant      string                   `json:"tenant,omitempty"`
+	}{
+		FunctionID:  req.FunctionID,
+		Method:      req.Method,
+		Parameters:  req.Parameters,
+		Environment: req.Config.Environment,
+		Stdin:       req.Config.Stdin,
+		Permissions: req.Config.Permissions,
+		Runtime:     req.Config.Runtime,
+		Inputs:      req.Config.Inputs,
+		Limits:      req.Config.Limits,
+		Timeout:     req.Config.Timeout,
+		Watermark:   req.Config.Watermark,
+		Tenant:      req.Config.Tenant,
+	}
+
+	// The struct has no fields that could fail to serialize.
+	payload, _ := json.Marshal(invocation)
+	sum := sha256.Sum256(payload)
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,129 @@
+package resultcache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/node/internal/resultcache"
+)
+
+func TestCache(t *testing.T) {
+
+	result := execute.Result{
+		Code:   codes.OK,
+		Result: execute.RuntimeOutput{Stdout: "dummy-output"},
+	}
+
+	t.Run("cached results are returned until they expire", func(t *testing.T) {
+		t.Parallel()
+
+		const ttl = 100 * time.Millisecond
+
+		cache := resultcache.New(ttl, 0)
+
+		_, ok := cache.Get("key")
+		require.False(t, ok)
+
+		cache.Add("key", result)
+
+		cached, ok := cache.Get("key")
+		require.True(t, ok)
+		require.Equal(t, result, cached)
+
+		time.Sleep(ttl)
+
+		_, ok = cache.Get("key")
+		require.False(t, ok)
+	})
+	t.Run("least recently used results are evicted", func(t *testing.T) {
+		t.Parallel()
+
+		cache := resultcache.New(time.Minute, 2)
+
+		cache.Add("first", result)
+		cache.Add("second", result)
+
+		_, ok := cache.Get("first")
+		require.True(t, ok)
+
+		cache.Add("third", result)
+
+		_, ok = cache.Get("second")
+		require.False(t, ok)
+
+		_, ok = cache.Get("first")
+		require.True(t, ok)
+	})
+}
+
+func TestKey(t *testing.T) {
+
+	req := execute.Request{
+		FunctionID: "dummy-function-id",
+		Method:     "dummy-method",
+		Parameters: []execute.Parameter{{Value: "dummy-argument"}},
+		Config: execute.Config{
+			Environment: []execute.EnvVar{{Name: "FOO", Value: "bar"}},
+		},
+	}
+
+	key := resultcache.Key(req)
+	require.Equal(t, key, resultcache.Key(req))
+
+	// Options that do not affect the output of the function do not change the key.
+	other := req
+	other.Config.NodeCount = 3
+	other.Config.Priority = 1
+	require.Equal(t, key, resultcache.Key(other))
+
+	other = req
+	other.Parameters = []execute.Parameter{{Value: "other-argument"}}
+	require.NotEqual(t, key, resultcache.Key(other))
+
+	other = req
+	other.Config.Environment = []execute.EnvVar{{Name: "FOO", Value: "baz"}}
+	require.NotEqual(t, key, resultcache.Key(other))
+
+	other = req
+	other.Config.Watermark = "dummy-watermark"
+	require.NotEqual(t, key, resultcache.Key(other))
+
+	other = req
+	other.Config.Inputs = []execute.Input{{URL: "https://example.com/dataset.csv", Path: "dataset.csv"}}
+	require.NotEqual(t, key, resultcache.Key(other))
+
+	other = req
+	other.Config.Tenant = "dummy-tenant"
+	require.NotEqual(t, key, resultcache.Key(other))
+
+	other = req
+	other.Config.Limits = &execute.ResourceLimits{MemoryKB: 1024}
+	require.NotEqual(t, key, resultcache.Key(other))
+
+	other = req
+	other.Config.Timeout = 10
+	require.NotEqual(t, key, resultcache.Key(other))
+}
+
+func TestCacheable(t *testing.T) {
+
+	req := execute.Request{
+		FunctionID: "dummy-function-id",
+		Method:     "dummy-method",
+	}
+	require.True(t, resultcache.Cacheable(req))
+
+	req.Config.Inputs = []execute.Input{{
+		URL:      "https://example.com/dataset.csv",
+		Path:     "dataset.csv",
+		Checksum: "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+	}}
+	require.True(t, resultcache.Cacheable(req))
+
+	req.Config.Inputs = append(req.Config.Inputs, execute.Input{URL: "https://example.com/other.csv", Path: "other.csv"})
+	require.False(t, resultcache.Cacheable(req))
+}
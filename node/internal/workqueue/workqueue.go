@@ -0,0 +1,160 @@
+package workqueue
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrFull is returned when all processing slots are taken and there is no room left in the queue.
+var ErrFull = errors.New("work queue is full")
+
+// Queue limits the number of requests processed at a time. Requests waiting for a processing slot are queued,
+// with higher priority requests getting a slot first. Requests with the same priority get a slot in the order they arrived.
+// Queue is bounded, so callers can turn down work once it is full instead of waiting indefinitely.
+type Queue struct {
+	sync.Mutex
+
+	slots   uint
+	size    uint
+	running uint
+	seq     uint64
+	waiting waiters
+}
+
+type waiter struct {
+	priority uint
+	seq      uint64
+	index    int
+	ready    chan struct{}
+}
+
+// New creates a new Queue, with the given number of processing slots and room for the given number of waiting requests.
+func New(slots uint, size uint) *Queue {
+
+	q := Queue{
+		slots: max(slots, 1),
+		size:  size,
+	}
+
+	return &q
+}
+
+// Acquire waits for a processing slot. It returns `ErrFull` right away if the queue has no room left, and the context error
+// if the context is cancelled before a slot is available. Slot must be released once the request is processed.
+func (q *Queue) Acquire(ctx context.Context, priority uint) error {
+
+	q.Lock()
+
+	if q.running < q.slots && len(q.waiting) == 0 {
+		q.running++
+		q.Unlock()
+		return nil
+	}
+
+	if uint(len(q.waiting)) >= q.size {
+		q.Unlock()
+		return ErrFull
+	}
+
+	w := &waiter{
+		priority: priority,
+		seq:      q.seq,
+		ready:    make(chan struct{}),
+	}
+	q.seq++
+	heap.Push(&q.waiting, w)
+
+	q.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+
+	case <-ctx.Done():
+		q.Lock()
+		defer q.Unlock()
+
+		// Slot might have been handed over in the meantime.
+		select {
+		case <-w.ready:
+			q.release()
+		default:
+			heap.Remove(&q.waiting, w.index)
+		}
+
+		return ctx.Err()
+	}
+}
+
+// Release frees up the processing slot, handing it over to the highest priority request waiting.
+func (q *Queue) Release() {
+	q.Lock()
+	defer q.Unlock()
+
+	q.release()
+}
+
+// release hands over the processing slot to the next waiting request, if any.
+// NOTE: Caller should hold the lock.
+func (q *Queue) release() {
+
+	if len(q.waiting) == 0 {
+		q.running = max(q.running, 1) - 1
+		return
+	}
+
+	next := heap.Pop(&q.waiting).(*waiter)
+	close(next.ready)
+}
+
+// Running returns the number of requests holding a processing slot.
+func (q *Queue) Running() uint {
+	q.Lock()
+	defer q.Unlock()
+
+	return q.running
+}
+
+// Waiting returns the number of requests waiting for a processing slot.
+func (q *Queue) Waiting() uint {
+	q.Lock()
+	defer q.Unlock()
+
+	return uint(len(q.waiting))
+}
+
+// waiters implements heap.Interface, ordering requests by priority, then by arrival.
+type waiters []*waiter
+
+func (w waiters) Len() int { return len(w) }
+
+func (w waiters) Less(i, j int) bool {
+	if w[i].priority != w[j].priority {
+		return w[i].priority > w[j].priority
+	}
+	return w[i].seq < w[j].seq
+}
+
+func (w waiters) Swap(i, j int) {
+	w[i], w[j] = w[j], w[i]
+	w[i].index = i
+	w[j].index = j
+}
+
+func (w *waiters) Push(x any) {
+	item := x.(*waiter)
+	item.index = len(*w)
+	*w = append(*w, item)
+}
+
+func (w *waiters) Pop() any {
+	old := *w
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.index = -1
+	*w = old[:n-1]
+	return item
+}
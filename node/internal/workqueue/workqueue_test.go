@@ -0,0 +1,88 @@
+package workqueue_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/node/internal/workqueue"
+)
+
+func TestQueue(t *testing.T) {
+
+	const (
+		testTimeout = 5 * time.Second
+	)
+
+	t.Run("full queue rejects requests", func(t *testing.T) {
+		t.Parallel()
+
+		queue := workqueue.New(1, 1)
+
+		require.NoError(t, queue.Acquire(context.Background(), 0))
+
+		acquired := make(chan error)
+		go func() {
+			acquired <- queue.Acquire(context.Background(), 0)
+		}()
+
+		require.Eventually(t, func() bool { return queue.Waiting() == 1 }, testTimeout, time.Millisecond)
+
+		err := queue.Acquire(context.Background(), 0)
+		require.ErrorIs(t, err, workqueue.ErrFull)
+
+		queue.Release()
+		require.NoError(t, <-acquired)
+		require.Equal(t, uint(1), queue.Running())
+		require.Zero(t, queue.Waiting())
+
+		queue.Release()
+		require.Zero(t, queue.Running())
+	})
+	t.Run("higher priority requests get a slot first", func(t *testing.T) {
+		t.Parallel()
+
+		queue := workqueue.New(1, 3)
+
+		require.NoError(t, queue.Acquire(context.Background(), 0))
+
+		order := make(chan uint, 3)
+		for i, priority := range []uint{1, 5, 1} {
+			go func() {
+				err := queue.Acquire(context.Background(), priority)
+				require.NoError(t, err)
+				order <- priority
+			}()
+
+			require.Eventually(t, func() bool { return queue.Waiting() == uint(i+1) }, testTimeout, time.Millisecond)
+		}
+
+		queue.Release()
+		require.Equal(t, uint(5), <-order)
+
+		queue.Release()
+		require.Equal(t, uint(1), <-order)
+
+		queue.Release()
+		require.Equal(t, uint(1), <-order)
+	})
+	t.Run("cancelled requests leave the queue", func(t *testing.T) {
+		t.Parallel()
+
+		queue := workqueue.New(1, 1)
+
+		require.NoError(t, queue.Acquire(context.Background(), 0))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := queue.Acquire(ctx, 0)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Zero(t, queue.Waiting())
+
+		queue.Release()
+		require.Zero(t, queue.Running())
+	})
+}
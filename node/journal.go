@@ -0,0 +1,102 @@
+package node
+
+import (
+	"context"
+	"errors"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/node/head/journal"
+	"github.com/blocklessnetwork/b7s/node/head/recovery"
+)
+
+var errHeadNodeRestarted = errors.New("head node restarted before the execution was complete")
+
+// journalUpdate records the progress of the execution request, if the node keeps a request journal.
+// Journal errors are not fatal for the execution.
+func (n *Node) journalUpdate(requestID string, update func(*journal.Entry)) {
+
+	if n.cfg.Journal == nil {
+		return
+	}
+
+	err := n.cfg.Journal.Update(requestID, update)
+	if err != nil {
+		n.log.Warn().Err(err).Str("request", requestID).Msg("could not update request journal")
+	}
+}
+
+// journalRemove removes the execution request from the journal, once it's done.
+func (n *Node) journalRemove(requestID string) {
+
+	if n.cfg.Journal == nil {
+		return
+	}
+
+	err := n.cfg.Journal.Remove(requestID)
+	if err != nil {
+		n.log.Warn().Err(err).Str("request", requestID).Msg("could not remove request from journal")
+	}
+}
+
+// abortPendingRequests fails requests that were in progress when the head node stopped. Clusters formed for these requests
+// are disbanded and clients that sent the request are notified.
+func (n *Node) abortPendingRequests(ctx context.Context) {
+
+	if n.cfg.Journal == nil {
+		return
+	}
+
+	pending, corrupted, err := n.cfg.Journal.Scan()
+	if err != nil {
+		n.log.Error().Err(err).Msg("could not retrieve pending requests from journal")
+		return
+	}
+
+	// Entries that cannot be read cannot be aborted either - record them and clean them up.
+	for _, c := range corrupted {
+		n.log.Warn().Str("request", c.ID).Str("reason", c.Reason).Msg("removing corrupted journal entry")
+		n.journalRemove(c.ID)
+	}
+
+	var notified uint
+	defer func() {
+		n.recovery.update(func(r *recovery.Report) {
+			r.AbandonedRequests += uint(len(pending))
+			r.NotifiedClients += notified
+			r.Corrupted = append(r.Corrupted, corrupted...)
+		})
+	}()
+
+	for _, entry := range pending {
+
+		log := n.log.With().Str("request", entry.RequestID).Str("function", entry.FunctionID).Str("phase", string(entry.Phase)).Logger()
+
+		log.Warn().Time("started", entry.Started).Msg("aborting execution request left pending by previous run")
+
+		n.metrics.IncrCounter(executionsAbortedMetric, 1)
+
+		if consensusRequired(entry.Consensus) && entry.Phase != journal.PhaseRollCall && len(entry.Peers) > 0 {
+			err := n.disbandCluster(entry.RequestID, entry.Peers)
+			if err != nil {
+				log.Warn().Err(err).Msg("could not disband cluster for aborted request")
+			}
+		}
+
+		if entry.Origin != "" {
+			res := response.Execute{
+				RequestID: entry.ClientRequestID,
+				Code:      codes.Error,
+			}
+
+			err := n.send(ctx, entry.Origin, res.WithErrorMessage(errHeadNodeRestarted))
+			if err != nil {
+				log.Warn().Err(err).Str("peer", entry.Origin.String()).Msg("could not notify client about aborted request")
+			} else {
+				notified++
+			}
+		}
+
+		n.journalRemove(entry.RequestID)
+	}
+}
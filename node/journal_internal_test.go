@@ -0,0 +1,69 @@
+package node
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/node/head/journal"
+	"github.com/blocklessnetwork/b7s/testing/helpers"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_AbortPendingRequests(t *testing.T) {
+
+	const (
+		requestID       = "dummy-request-id"
+		clientRequestID = "dummy-client-request-id"
+	)
+
+	db := helpers.InMemoryDB(t)
+	defer db.Close()
+
+	node := createNode(t, blockless.HeadNode)
+	node.cfg.Journal = journal.New(db)
+
+	// Create a host that will receive the execution response.
+	receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+	require.NoError(t, err)
+
+	hostAddNewPeer(t, node.host, receiver)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+		defer wg.Done()
+		defer stream.Close()
+
+		var received response.Execute
+		getStreamPayload(t, stream, &received)
+
+		require.Equal(t, blockless.MessageExecuteResponse, received.Type())
+		require.Equal(t, clientRequestID, received.RequestID)
+		require.Equal(t, codes.Error, received.Code)
+		require.Equal(t, errHeadNodeRestarted.Error(), received.ErrorMessage)
+	})
+
+	node.journalUpdate(requestID, func(e *journal.Entry) {
+		e.Origin = receiver.ID()
+		e.ClientRequestID = clientRequestID
+		e.FunctionID = mocks.GenericFunctionRecord.CID
+		e.Phase = journal.PhaseExecution
+	})
+
+	node.abortPendingRequests(context.Background())
+
+	wg.Wait()
+
+	pending, _, err := node.cfg.Journal.Scan()
+	require.NoError(t, err)
+	require.Empty(t, pending)
+}
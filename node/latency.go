@@ -0,0 +1,52 @@
+package node
+
+import (
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// peerLatencies tracks roll call round-trip times of peers, smoothed over multiple roll calls.
+type peerLatencies struct {
+	sync.Mutex
+	latencies map[peer.ID]time.Duration
+}
+
+func newPeerLatencies() *peerLatencies {
+	return &peerLatencies{
+		latencies: make(map[peer.ID]time.Duration),
+	}
+}
+
+func (l *peerLatencies) record(peer peer.ID, rtt time.Duration) {
+	l.Lock()
+	defer l.Unlock()
+
+	current, ok := l.latencies[peer]
+	if !ok {
+		l.latencies[peer] = rtt
+		return
+	}
+
+	l.latencies[peer] = current + time.Duration(rollCallLatencyWeight*float64(rtt-current))
+}
+
+func (l *peerLatencies) get(peer peer.ID) (time.Duration, bool) {
+	l.Lock()
+	defer l.Unlock()
+
+	latency, ok := l.latencies[peer]
+	return latency, ok
+}
+
+// weight returns the factor by which the score of the peer is scaled when choosing peers for execution - lower for slower peers.
+func (l *peerLatencies) weight(peer peer.ID) float64 {
+
+	latency, ok := l.get(peer)
+	if !ok {
+		return 1
+	}
+
+	return 1 / (1 + float64(latency)/float64(rollCallLatencyScale))
+}
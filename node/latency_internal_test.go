@@ -0,0 +1,57 @@
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/consensus/pbft"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestPeerLatencies(t *testing.T) {
+
+	var (
+		fast    = mocks.GenericPeerIDs[0]
+		slow    = mocks.GenericPeerIDs[1]
+		unknown = mocks.GenericPeerIDs[2]
+	)
+
+	latencies := newPeerLatencies()
+
+	latencies.record(fast, 10*time.Millisecond)
+	latencies.record(slow, 100*time.Millisecond)
+	latencies.record(slow, 200*time.Millisecond)
+
+	latency, ok := latencies.get(slow)
+	require.True(t, ok)
+	require.Greater(t, latency, 100*time.Millisecond)
+	require.Less(t, latency, 200*time.Millisecond)
+
+	_, ok = latencies.get(unknown)
+	require.False(t, ok)
+
+	require.Equal(t, 1.0, latencies.weight(unknown))
+	require.Greater(t, latencies.weight(fast), latencies.weight(slow))
+
+	measured := map[peer.ID]time.Duration{fast: 10 * time.Millisecond}
+	require.Equal(t, map[string]int64{fast.String(): 10}, rollCallLatencies([]peer.ID{fast, slow}, measured))
+}
+
+func TestNode_PBFTViewChangeTimeout(t *testing.T) {
+
+	node := createNode(t, blockless.HeadNode)
+
+	peers := mocks.GenericPeerIDs[:4]
+	require.Equal(t, pbft.RequestTimeout, node.pbftViewChangeTimeout(peers))
+
+	node.latencies.record(peers[0], 100*time.Millisecond)
+	node.latencies.record(peers[1], 500*time.Millisecond)
+	require.Equal(t, pbft.RequestTimeout+10*time.Second, node.pbftViewChangeTimeout(peers))
+
+	node.latencies.record(peers[2], time.Hour)
+	require.Equal(t, pbft.MaxRequestTimeout, node.pbftViewChangeTimeout(peers))
+}
@@ -0,0 +1,195 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+var errLeadershipTransferNotSupported = errors.New("leadership transfer is only supported for raft clusters")
+
+// leadershipTransferer is implemented by clusters that support handing over leadership to another member.
+type leadershipTransferer interface {
+	TransferLeadership(target peer.ID) error
+}
+
+// TransferLeadership asks the raft cluster formed for the request to hand over leadership to the target peer, so the current leader
+// can be drained without aborting in-flight executions. If no target is given, the most up to date member becomes the new leader.
+// The transfer is done by the cluster leader, so it succeeds once any of the members confirms it.
+func (n *Node) TransferLeadership(ctx context.Context, requestID string, members []peer.ID, target peer.ID) error {
+
+	if target != "" && !slices.Contains(members, target) {
+		return errors.New("target peer is not a cluster member")
+	}
+
+	req := request.TransferLeadership{
+		RequestID: requestID,
+		Consensus: consensus.Raft,
+		Target:    target,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, n.cfg.ExecutionTimeout)
+	defer cancel()
+
+	err := n.sendToMany(ctx, members, &req, false)
+	if err != nil {
+		return fmt.Errorf("could not send leadership transfer to cluster members: %w", err)
+	}
+
+	confirmed := n.gatherLeadershipTransferResponses(ctx, requestID, members)
+	if len(confirmed) == 0 {
+		return errors.New("leadership transfer was not confirmed by the cluster leader")
+	}
+
+	n.log.Info().Str("request", requestID).Str("target", target.String()).Strs("confirmed", blockless.PeerIDsToStr(confirmed)).Msg("cluster leadership transferred")
+
+	return nil
+}
+
+// gatherLeadershipTransferResponses waits for leadership transfer responses and returns the peers that transferred leadership.
+func (n *Node) gatherLeadershipTransferResponses(ctx context.Context, requestID string, peers []peer.ID) []peer.ID {
+
+	var (
+		lock      sync.Mutex
+		wg        sync.WaitGroup
+		confirmed []peer.ID
+	)
+
+	wg.Add(len(peers))
+	for _, rp := range peers {
+		go func() {
+			defer wg.Done()
+
+			// Do not wait for peers that disconnect.
+			pctx, cancel := n.peerContext(ctx, rp)
+			defer cancel()
+
+			res, ok := n.leaderTransfers.WaitFor(pctx, consensusResponseKey(requestID, rp))
+			if !ok {
+				return
+			}
+
+			if res.Code != codes.OK {
+				n.log.Debug().Str("request", requestID).Stringer("peer", rp).Str("code", res.Code.String()).Str("message", res.ErrorMessage).Msg("peer did not transfer leadership")
+				return
+			}
+
+			lock.Lock()
+			defer lock.Unlock()
+			confirmed = append(confirmed, rp)
+		}()
+	}
+
+	wg.Wait()
+
+	return confirmed
+}
+
+func (n *Node) processTransferLeadership(ctx context.Context, from peer.ID, req request.TransferLeadership) error {
+
+	n.log.Info().Str("request", req.RequestID).Str("peer", from.String()).Str("target", req.Target.String()).Msg("received request to transfer cluster leadership")
+
+	var (
+		code codes.Code
+		err  error
+	)
+	if n.isHead() {
+		code, err = n.headTransferLeadership(ctx, from, req)
+	} else {
+		code, err = n.transferClusterLeadership(req)
+	}
+
+	res := req.Response(code)
+	if err != nil {
+		n.log.Error().Err(err).Str("request", req.RequestID).Msg("could not transfer cluster leadership")
+		res = res.WithErrorMessage(err)
+	}
+
+	err = n.send(ctx, from, res)
+	if err != nil {
+		return fmt.Errorf("could not send response: %w", err)
+	}
+
+	return nil
+}
+
+// headTransferLeadership forwards the leadership transfer requested by an admin to the cluster members.
+func (n *Node) headTransferLeadership(ctx context.Context, from peer.ID, req request.TransferLeadership) (codes.Code, error) {
+
+	if !slices.Contains(n.cfg.AdminPeers, from) {
+		return codes.NotPermitted, errors.New("peer is not an admin")
+	}
+
+	if req.Consensus != 0 && req.Consensus != consensus.Raft {
+		return codes.NotSupported, errLeadershipTransferNotSupported
+	}
+
+	members := req.Members
+	if len(members) == 0 {
+		members, _ = n.executions.peers(req.RequestID)
+	}
+
+	if len(members) == 0 {
+		return codes.NotFound, errors.New("cluster members unknown")
+	}
+
+	err := n.TransferLeadership(ctx, req.RequestID, members, req.Target)
+	if err != nil {
+		return codes.Error, err
+	}
+
+	return codes.OK, nil
+}
+
+// transferClusterLeadership hands over the cluster leadership, if we are the cluster leader. Other members report `NoContent`.
+func (n *Node) transferClusterLeadership(req request.TransferLeadership) (codes.Code, error) {
+
+	if req.Consensus != consensus.Raft {
+		return codes.NotSupported, errLeadershipTransferNotSupported
+	}
+
+	n.clusterLock.RLock()
+	cluster, ok := n.clusters[req.RequestID]
+	n.clusterLock.RUnlock()
+
+	if !ok {
+		return codes.NotFound, errors.New("no cluster with that ID")
+	}
+
+	transferer, ok := cluster.(leadershipTransferer)
+	if !ok {
+		return codes.NotSupported, errLeadershipTransferNotSupported
+	}
+
+	err := transferer.TransferLeadership(req.Target)
+	if errors.Is(err, consensus.ErrNotLeader) {
+		return codes.NoContent, nil
+	}
+	if err != nil {
+		return codes.Error, fmt.Errorf("could not transfer leadership: %w", err)
+	}
+
+	n.metrics.IncrCounter(leaderTransfersMetric, 1)
+
+	return codes.OK, nil
+}
+
+// processTransferLeadershipResponse will record the leadership transfer response.
+func (n *Node) processTransferLeadershipResponse(ctx context.Context, from peer.ID, res response.TransferLeadership) error {
+
+	n.log.Debug().Str("request", res.RequestID).Stringer("from", from).Msg("received leadership transfer response")
+
+	n.leaderTransfers.Set(consensusResponseKey(res.RequestID, from), res)
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package node
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/consensus/raft"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+type testTransferableCluster struct {
+	testCluster
+	err    error
+	target peer.ID
+}
+
+func (c *testTransferableCluster) TransferLeadership(target peer.ID) error {
+	c.target = target
+	return c.err
+}
+
+func TestNode_TransferClusterLeadership(t *testing.T) {
+
+	var (
+		requestID = mocks.GenericUUID.String()
+		target    = mocks.GenericPeerIDs[0]
+	)
+
+	transfer := request.TransferLeadership{
+		RequestID: requestID,
+		Consensus: consensus.Raft,
+		Target:    target,
+	}
+
+	t.Run("leader transfers leadership", func(t *testing.T) {
+		node := createNode(t, blockless.WorkerNode)
+
+		cluster := &testTransferableCluster{testCluster: testCluster{consensus: consensus.Raft}}
+		node.clusters[requestID] = cluster
+
+		code, err := node.transferClusterLeadership(transfer)
+		require.NoError(t, err)
+		require.Equal(t, codes.OK, code)
+		require.Equal(t, target, cluster.target)
+	})
+	t.Run("followers do not transfer leadership", func(t *testing.T) {
+		node := createNode(t, blockless.WorkerNode)
+
+		cluster := &testTransferableCluster{testCluster: testCluster{consensus: consensus.Raft}, err: raft.ErrNotLeader}
+		node.clusters[requestID] = cluster
+
+		code, err := node.transferClusterLeadership(transfer)
+		require.NoError(t, err)
+		require.Equal(t, codes.NoContent, code)
+	})
+	t.Run("unknown cluster", func(t *testing.T) {
+		node := createNode(t, blockless.WorkerNode)
+
+		code, err := node.transferClusterLeadership(transfer)
+		require.Error(t, err)
+		require.Equal(t, codes.NotFound, code)
+	})
+	t.Run("only raft clusters support leadership transfer", func(t *testing.T) {
+		node := createNode(t, blockless.WorkerNode)
+		node.clusters[requestID] = &testCluster{consensus: consensus.PBFT}
+
+		pbftTransfer := transfer
+		pbftTransfer.Consensus = consensus.PBFT
+
+		code, err := node.transferClusterLeadership(pbftTransfer)
+		require.ErrorIs(t, err, errLeadershipTransferNotSupported)
+		require.Equal(t, codes.NotSupported, code)
+	})
+}
+
+func TestNode_HeadTransferLeadership(t *testing.T) {
+
+	var (
+		requestID = mocks.GenericUUID.String()
+		admin     = mocks.GenericPeerIDs[0]
+	)
+
+	transfer := request.TransferLeadership{
+		RequestID: requestID,
+	}
+
+	t.Run("only admins can transfer leadership", func(t *testing.T) {
+		node := createNode(t, blockless.HeadNode)
+
+		code, err := node.headTransferLeadership(context.Background(), admin, transfer)
+		require.Error(t, err)
+		require.Equal(t, codes.NotPermitted, code)
+	})
+	t.Run("cluster members must be known", func(t *testing.T) {
+		node := createNode(t, blockless.HeadNode)
+		node.cfg.AdminPeers = []peer.ID{admin}
+
+		code, err := node.headTransferLeadership(context.Background(), admin, transfer)
+		require.Error(t, err)
+		require.Equal(t, codes.NotFound, code)
+	})
+	t.Run("target must be a cluster member", func(t *testing.T) {
+		node := createNode(t, blockless.HeadNode)
+
+		err := node.TransferLeadership(context.Background(), requestID, mocks.GenericPeerIDs[1:3], mocks.GenericPeerIDs[3])
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,71 @@
+package node
+
+import (
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+// currentLoad returns how busy the worker is, reported to the head node in roll call responses.
+func (n *Node) currentLoad() response.Load {
+
+	load := response.Load{
+		Queued:      n.work.Waiting(),
+		Concurrency: n.cfg.Concurrency,
+	}
+
+	if n.executors != nil {
+		load.Executions = n.executors.inFlight()
+	}
+
+	load.CPUUsage, load.MemoryAvailable, load.MemoryPressure = systemLoad()
+	load.DiskPressure = diskPressure(n.cfg.Workspace)
+
+	return load
+}
+
+// peerLoads tracks the load workers reported in their latest roll call responses.
+type peerLoads struct {
+	sync.Mutex
+	loads map[peer.ID]response.Load
+}
+
+func newPeerLoads() *peerLoads {
+	return &peerLoads{
+		loads: make(map[peer.ID]response.Load),
+	}
+}
+
+func (l *peerLoads) record(peer peer.ID, load *response.Load) {
+	l.Lock()
+	defer l.Unlock()
+
+	// Workers not reporting their load are treated as idle.
+	if load == nil {
+		delete(l.loads, peer)
+		return
+	}
+
+	l.loads[peer] = *load
+}
+
+func (l *peerLoads) get(peer peer.ID) (response.Load, bool) {
+	l.Lock()
+	defer l.Unlock()
+
+	load, ok := l.loads[peer]
+	return load, ok
+}
+
+// weight returns the factor by which the score of the peer is scaled when choosing peers for execution - lower for busier peers.
+func (l *peerLoads) weight(peer peer.ID) float64 {
+
+	load, ok := l.get(peer)
+	if !ok {
+		return 1
+	}
+
+	return 1 - rollCallLoadImpact*load.Utilization()
+}
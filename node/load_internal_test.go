@@ -0,0 +1,48 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestPeerLoads(t *testing.T) {
+
+	var (
+		idle    = mocks.GenericPeerIDs[0]
+		busy    = mocks.GenericPeerIDs[1]
+		unknown = mocks.GenericPeerIDs[2]
+	)
+
+	loads := newPeerLoads()
+
+	loads.record(idle, &response.Load{Executions: 0, Concurrency: 10})
+	loads.record(busy, &response.Load{Executions: 8, Queued: 4, Concurrency: 10})
+
+	load, ok := loads.get(busy)
+	require.True(t, ok)
+	require.Equal(t, 1.0, load.Utilization())
+
+	require.Equal(t, 1.0, loads.weight(unknown))
+	require.Equal(t, 1.0, loads.weight(idle))
+	require.Equal(t, 1-rollCallLoadImpact, loads.weight(busy))
+
+	// CPU usage counts towards utilization too.
+	loads.record(idle, &response.Load{Concurrency: 10, CPUUsage: 0.5})
+	require.Greater(t, loads.weight(unknown), loads.weight(idle))
+
+	// Memory and disk pressure only count when close to running out.
+	loads.record(idle, &response.Load{Concurrency: 10, MemoryPressure: 0.5})
+	require.Equal(t, 1.0, loads.weight(idle))
+	loads.record(idle, &response.Load{Concurrency: 10, DiskPressure: 1})
+	require.InDelta(t, 1-rollCallLoadImpact, loads.weight(idle), 0.001)
+
+	// Peers that stop reporting their load are treated as idle.
+	loads.record(busy, nil)
+	_, ok = loads.get(busy)
+	require.False(t, ok)
+	require.Equal(t, 1.0, loads.weight(busy))
+}
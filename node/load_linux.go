@@ -0,0 +1,71 @@
+//go:build linux
+// +build linux
+
+package node
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// systemLoad returns the share of the CPU in use, based on the one minute load average, the available memory in kB and
+// the share of the memory in use. Values that cannot be determined are zero.
+func systemLoad() (float64, uint64, float64) {
+
+	var cpu float64
+	loadavg, err := os.ReadFile("/proc/loadavg")
+	if err == nil {
+		fields := strings.Fields(string(loadavg))
+		if len(fields) > 0 {
+			avg, err := strconv.ParseFloat(fields[0], 64)
+			if err == nil {
+				cpu = min(avg/float64(runtime.NumCPU()), 1)
+			}
+		}
+	}
+
+	var memory, total uint64
+	meminfo, err := os.ReadFile("/proc/meminfo")
+	if err == nil {
+		scanner := bufio.NewScanner(bytes.NewReader(meminfo))
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) < 2 {
+				continue
+			}
+
+			switch fields[0] {
+			case "MemTotal:":
+				total, _ = strconv.ParseUint(fields[1], 10, 64)
+			case "MemAvailable:":
+				memory, _ = strconv.ParseUint(fields[1], 10, 64)
+			}
+		}
+	}
+
+	var pressure float64
+	if total > 0 && memory > 0 {
+		pressure = 1 - min(float64(memory)/float64(total), 1)
+	}
+
+	return cpu, memory, pressure
+}
+
+// diskPressure returns the share of the disk in use for the filesystem holding the given directory.
+// Zero if it cannot be determined.
+func diskPressure(dir string) float64 {
+
+	var stat unix.Statfs_t
+	err := unix.Statfs(dir, &stat)
+	if err != nil || stat.Blocks == 0 {
+		return 0
+	}
+
+	return 1 - min(float64(stat.Bavail)/float64(stat.Blocks), 1)
+}
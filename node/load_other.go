@@ -0,0 +1,16 @@
+//go:build !linux
+// +build !linux
+
+package node
+
+// systemLoad returns the share of the CPU in use, the available memory in kB and the share of the memory in use.
+// Not supported on this platform, so all are reported as unknown.
+func systemLoad() (float64, uint64, float64) {
+	return 0, 0, 0
+}
+
+// diskPressure returns the share of the disk in use for the filesystem holding the given directory.
+// Not supported on this platform, so it is reported as unknown.
+func diskPressure(string) float64 {
+	return 0
+}
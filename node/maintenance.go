@@ -0,0 +1,28 @@
+package node
+
+import (
+	"time"
+)
+
+// inMaintenance returns true if the given time falls into one of the maintenance windows.
+func (n *Node) inMaintenance(now time.Time) bool {
+
+	for _, window := range n.cfg.MaintenanceWindows {
+		if window.active(now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// active returns true if the window started less than `Duration` before the given time.
+func (w MaintenanceWindow) active(now time.Time) bool {
+
+	start := w.Start.Next(now.Add(-w.Duration))
+	if start.IsZero() {
+		return false
+	}
+
+	return !start.After(now)
+}
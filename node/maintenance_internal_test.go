@@ -0,0 +1,39 @@
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/node/head/schedule"
+)
+
+func TestMaintenanceWindow_Active(t *testing.T) {
+
+	// Sundays at 03:00, for two hours.
+	start, err := schedule.Parse("0 3 * * 0")
+	require.NoError(t, err)
+
+	window := MaintenanceWindow{
+		Start:    start,
+		Duration: 2 * time.Hour,
+	}
+
+	sunday := time.Date(2024, time.June, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		now    time.Time
+		active bool
+	}{
+		{now: sunday.Add(2*time.Hour + 59*time.Minute), active: false},
+		{now: sunday.Add(3 * time.Hour), active: true},
+		{now: sunday.Add(4*time.Hour + 30*time.Minute), active: true},
+		{now: sunday.Add(5 * time.Hour), active: false},
+		{now: sunday.Add(24*time.Hour + 3*time.Hour), active: false},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.active, window.active(test.now), "time: %s", test.now)
+	}
+}
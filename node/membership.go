@@ -0,0 +1,107 @@
+package node
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+)
+
+// subgroupMembers tracks workers that presented valid membership credentials for restricted subgroups, until the credentials expire.
+type subgroupMembers struct {
+	sync.Mutex
+	members map[string]map[peer.ID]time.Time
+}
+
+func newSubgroupMembers() *subgroupMembers {
+	return &subgroupMembers{
+		members: make(map[string]map[peer.ID]time.Time),
+	}
+}
+
+// add records the worker as a member of the subgroup. Zero expiry time means the membership does not expire.
+func (s *subgroupMembers) add(subgroup string, id peer.ID, expires time.Time) {
+	s.Lock()
+	defer s.Unlock()
+
+	members, ok := s.members[subgroup]
+	if !ok {
+		members = make(map[peer.ID]time.Time)
+		s.members[subgroup] = members
+	}
+
+	members[id] = expires
+}
+
+func (s *subgroupMembers) member(subgroup string, id peer.ID, now time.Time) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	expires, ok := s.members[subgroup][id]
+	if !ok {
+		return false
+	}
+
+	if !expires.IsZero() && now.After(expires) {
+		delete(s.members[subgroup], id)
+		return false
+	}
+
+	return true
+}
+
+// isRestrictedSubgroup returns true if only members of the subgroup are counted for its roll calls.
+func (n *Node) isRestrictedSubgroup(subgroup string) bool {
+	_, ok := n.cfg.RestrictedSubgroups[subgroup]
+	return ok
+}
+
+// verifyMembership checks that the peer responding to a roll call in the given subgroup is a member of it.
+// Anyone can respond to a roll call in subgroups that are not restricted.
+func (n *Node) verifyMembership(subgroup string, reply RollCallResponse) error {
+
+	owner, ok := n.cfg.RestrictedSubgroups[subgroup]
+	if !ok {
+		return nil
+	}
+
+	if reply.Credential == nil {
+		return errors.New("membership credential missing")
+	}
+
+	err := reply.Credential.Verify(owner, subgroup, reply.From, time.Now())
+	if err != nil {
+		return fmt.Errorf("could not verify membership credential: %w", err)
+	}
+
+	n.members.add(subgroup, reply.From, reply.Credential.Expires)
+
+	return nil
+}
+
+// isSubgroupMember returns true if the peer presented a valid membership credential for the subgroup in one of its roll call responses.
+// All peers are members of subgroups that are not restricted.
+func (n *Node) isSubgroupMember(subgroup string, id peer.ID) bool {
+
+	if !n.isRestrictedSubgroup(subgroup) {
+		return true
+	}
+
+	return n.members.member(subgroup, id, time.Now())
+}
+
+// membershipCredential returns the credential admitting the worker to the given subgroup, if the worker has one.
+func (n *Node) membershipCredential(subgroup string) *blockless.MembershipCredential {
+
+	for _, credential := range n.cfg.MembershipCredentials {
+		if credential.Subgroup == subgroup {
+			return &credential
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package node
+
+import (
+	"context"
+	"crypto/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_VerifyMembership(t *testing.T) {
+
+	const subgroup = "blockless/b7s/private"
+
+	var (
+		owner  = newMembershipOwner(t)
+		member = mocks.GenericPeerIDs[0]
+	)
+
+	node := createNode(t, blockless.HeadNode)
+	node.cfg.RestrictedSubgroups = map[string]peer.ID{subgroup: owner.id}
+
+	reply := func(credential *blockless.MembershipCredential) RollCallResponse {
+		return RollCallResponse{
+			From:     member,
+			RollCall: response.RollCall{Credential: credential},
+		}
+	}
+
+	t.Run("subgroup not restricted", func(t *testing.T) {
+		require.False(t, node.isRestrictedSubgroup(DefaultTopic))
+		require.NoError(t, node.verifyMembership(DefaultTopic, reply(nil)))
+	})
+	t.Run("valid credential", func(t *testing.T) {
+		require.True(t, node.isRestrictedSubgroup(subgroup))
+
+		require.False(t, node.isSubgroupMember(subgroup, member))
+
+		credential := owner.issue(t, subgroup, member, time.Now().Add(time.Hour))
+		require.NoError(t, node.verifyMembership(subgroup, reply(&credential)))
+
+		// Verified members are remembered.
+		require.True(t, node.isSubgroupMember(subgroup, member))
+	})
+	t.Run("credential missing", func(t *testing.T) {
+		require.Error(t, node.verifyMembership(subgroup, reply(nil)))
+	})
+	t.Run("credential for a different peer", func(t *testing.T) {
+		credential := owner.issue(t, subgroup, mocks.GenericPeerIDs[1], time.Time{})
+		require.Error(t, node.verifyMembership(subgroup, reply(&credential)))
+	})
+	t.Run("credential for a different subgroup", func(t *testing.T) {
+		credential := owner.issue(t, DefaultTopic, member, time.Time{})
+		require.Error(t, node.verifyMembership(subgroup, reply(&credential)))
+	})
+	t.Run("credential expired", func(t *testing.T) {
+		credential := owner.issue(t, subgroup, member, time.Now().Add(-time.Minute))
+		require.Error(t, node.verifyMembership(subgroup, reply(&credential)))
+	})
+	t.Run("credential issued by someone else", func(t *testing.T) {
+		credential := newMembershipOwner(t).issue(t, subgroup, member, time.Time{})
+		require.Error(t, node.verifyMembership(subgroup, reply(&credential)))
+	})
+	t.Run("credential tampered with", func(t *testing.T) {
+		credential := owner.issue(t, subgroup, member, time.Now().Add(time.Hour))
+		credential.Expires = credential.Expires.Add(time.Hour)
+		require.Error(t, node.verifyMembership(subgroup, reply(&credential)))
+	})
+}
+
+func TestNode_RollCallMembershipCredential(t *testing.T) {
+
+	const subgroup = "blockless/b7s/private"
+
+	node := createNode(t, blockless.WorkerNode)
+
+	credential := newMembershipOwner(t).issue(t, subgroup, node.host.ID(), time.Time{})
+	node.cfg.MembershipCredentials = []blockless.MembershipCredential{credential}
+
+	require.NoError(t, node.ValidateConfig())
+
+	receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+	require.NoError(t, err)
+
+	hostAddNewPeer(t, node.host, receiver)
+
+	var (
+		lock     sync.Mutex
+		received []response.RollCall
+		wg       sync.WaitGroup
+	)
+	receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+		defer wg.Done()
+		defer stream.Close()
+
+		var res response.RollCall
+		getStreamPayload(t, stream, &res)
+
+		lock.Lock()
+		defer lock.Unlock()
+		received = append(received, res)
+	})
+
+	for _, topic := range []string{subgroup, DefaultTopic} {
+
+		wg.Add(1)
+
+		rollCall := request.RollCall{
+			FunctionID: "dummy-function-id",
+			RequestID:  mocks.GenericUUID.String(),
+			Origin:     receiver.ID(),
+			Subgroup:   topic,
+		}
+
+		err = node.processRollCall(context.Background(), receiver.ID(), rollCall)
+		require.NoError(t, err)
+
+		wg.Wait()
+	}
+
+	require.Len(t, received, 2)
+
+	// Credential is presented only for the subgroup it was issued for.
+	require.Equal(t, &credential, received[0].Credential)
+	require.Nil(t, received[1].Credential)
+}
+
+type membershipOwner struct {
+	key crypto.PrivKey
+	id  peer.ID
+}
+
+func newMembershipOwner(t *testing.T) membershipOwner {
+	t.Helper()
+
+	key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+
+	id, err := peer.IDFromPrivateKey(key)
+	require.NoError(t, err)
+
+	return membershipOwner{key: key, id: id}
+}
+
+func (o membershipOwner) issue(t *testing.T, subgroup string, member peer.ID, expires time.Time) blockless.MembershipCredential {
+	t.Helper()
+
+	credential := blockless.MembershipCredential{
+		Subgroup: subgroup,
+		Member:   member,
+		Expires:  expires,
+	}
+
+	err := credential.Sign(o.key)
+	require.NoError(t, err)
+
+	return credential
+}
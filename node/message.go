@@ -10,13 +10,15 @@ import (
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/libp2p/go-libp2p/core/peer"
 
+	"github.com/blocklessnetwork/b7s/host"
 	"github.com/blocklessnetwork/b7s/models/blockless"
 	"github.com/blocklessnetwork/b7s/node/internal/pipeline"
 )
 
 type topicInfo struct {
 	handle       *pubsub.Topic
-	subscription *pubsub.Subscription
+	subscription *pubsub.Subscription // Nil if we joined the topic without subscribing to it.
+	cancel       context.CancelFunc   // Stops peer discovery for topics joined at runtime.
 }
 
 func (n *Node) subscribeToTopics(ctx context.Context) error {
@@ -52,6 +54,11 @@ func (n *Node) subscribeToTopics(ctx context.Context) error {
 
 // send serializes the message and sends it to the specified peer.
 func (n *Node) send(ctx context.Context, to peer.ID, msg blockless.Message) error {
+	return n.sendWithHost(ctx, n.host, to, msg)
+}
+
+// sendWithHost serializes the message and sends it to the specified peer, using the given host.
+func (n *Node) sendWithHost(ctx context.Context, h *host.Host, to peer.ID, msg blockless.Message) error {
 
 	opts := new(msgSpanConfig).pipeline(pipeline.DirectMessagePipeline()).peer(to).spanOpts()
 	ctx, span := n.tracer.Start(ctx, msgSendSpanName(spanMessageSend, msg.Type()), opts...)
@@ -66,7 +73,7 @@ func (n *Node) send(ctx context.Context, to peer.ID, msg blockless.Message) erro
 	}
 
 	// Send message.
-	err = n.host.SendMessage(ctx, to, payload)
+	err = h.SendMessage(ctx, to, payload)
 	if err != nil {
 		return fmt.Errorf("could not send message: %w", err)
 	}
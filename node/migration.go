@@ -0,0 +1,55 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/node/migration"
+)
+
+// ExportState returns the durable state of the node - its identity, known peers, installed functions and peer reputation.
+// Node can be moved to different hardware by starting it with the exported state.
+func (n *Node) ExportState(ctx context.Context) (migration.State, error) {
+
+	identity, err := crypto.MarshalPrivateKey(n.host.PrivateKey())
+	if err != nil {
+		return migration.State{}, fmt.Errorf("could not marshal private key - keys kept in an HSM cannot be exported: %w", err)
+	}
+
+	peers, err := n.peerStore.RetrievePeers(ctx)
+	if err != nil {
+		return migration.State{}, fmt.Errorf("could not retrieve peers: %w", err)
+	}
+
+	installed, err := n.fstore.Installed(ctx)
+	if err != nil {
+		return migration.State{}, fmt.Errorf("could not list installed functions: %w", err)
+	}
+
+	functions := make([]blockless.FunctionRecord, 0, len(installed))
+	for _, cid := range installed {
+		function, err := n.fstore.Get(ctx, cid)
+		if err != nil {
+			return migration.State{}, fmt.Errorf("could not retrieve function (cid: %s): %w", cid, err)
+		}
+
+		functions = append(functions, function)
+	}
+
+	state := migration.State{
+		Version:    migration.Version,
+		Created:    time.Now().UTC(),
+		Identity:   identity,
+		Peers:      peers,
+		Functions:  functions,
+		Reputation: n.reputation.Export(),
+	}
+
+	n.log.Info().Int("peers", len(peers)).Int("functions", len(functions)).Msg("node state exported")
+
+	return state, nil
+}
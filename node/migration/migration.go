@@ -0,0 +1,195 @@
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/spf13/afero"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/node/internal/reputation"
+)
+
+const (
+	// Version of the state format.
+	Version = 1
+
+	// State contains the private key of the node, so it should only be readable by the owner.
+	statePermissions = 0o600
+)
+
+// ErrUnsupportedVersion is returned when reading state written in an unknown format.
+var ErrUnsupportedVersion = errors.New("unsupported state version")
+
+// State is the durable state of a node, exported so that the node can be moved to different hardware
+// while keeping its identity and warm caches.
+type State struct {
+	Version uint      `json:"version"`
+	Created time.Time `json:"created"`
+
+	// Identity is the marshalled private key of the node.
+	Identity   []byte                         `json:"identity"`
+	Peers      []blockless.Peer               `json:"peers,omitempty"`
+	Functions  []blockless.FunctionRecord     `json:"functions,omitempty"`
+	Reputation map[peer.ID]reputation.History `json:"reputation,omitempty"`
+	Modules    []Module                       `json:"modules,omitempty"`
+}
+
+// Module describes an entry in the compiled module cache.
+type Module struct {
+	Key  string `json:"key"`
+	Size int64  `json:"size"`
+}
+
+// PrivateKey returns the private key of the node the state was exported from.
+func (s State) PrivateKey() (crypto.PrivKey, error) {
+
+	key, err := crypto.UnmarshalPrivateKey(s.Identity)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal private key: %w", err)
+	}
+
+	return key, nil
+}
+
+// Write writes the state to the file at the given path.
+func Write(fs afero.Fs, path string, state State) error {
+
+	payload, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("could not encode state: %w", err)
+	}
+
+	err = afero.WriteFile(fs, path, payload, statePermissions)
+	if err != nil {
+		return fmt.Errorf("could not write state file (path: %s): %w", path, err)
+	}
+
+	return nil
+}
+
+// Read reads the state from the file at the given path.
+func Read(fs afero.Fs, path string) (State, error) {
+
+	payload, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return State{}, fmt.Errorf("could not read state file (path: %s): %w", path, err)
+	}
+
+	var state State
+	err = json.Unmarshal(payload, &state)
+	if err != nil {
+		return State{}, fmt.Errorf("could not decode state: %w", err)
+	}
+
+	if state.Version != Version {
+		return State{}, fmt.Errorf("%w (version: %d)", ErrUnsupportedVersion, state.Version)
+	}
+
+	return state, nil
+}
+
+// Restore saves the peers and function records from the state to the store. Existing records are overwritten.
+// Function files are not part of the state - the function store will reinstall them on its next sync.
+func Restore(ctx context.Context, store blockless.Store, state State) error {
+
+	for _, peer := range state.Peers {
+		err := store.SavePeer(ctx, peer)
+		if err != nil {
+			return fmt.Errorf("could not save peer (id: %s): %w", peer.ID, err)
+		}
+	}
+
+	for _, function := range state.Functions {
+		err := store.SaveFunction(ctx, function)
+		if err != nil {
+			return fmt.Errorf("could not save function (cid: %s): %w", function.CID, err)
+		}
+	}
+
+	return nil
+}
+
+// ModuleManifest lists the entries of the compiled module cache in the given directory.
+func ModuleManifest(fs afero.Fs, dir string) ([]Module, error) {
+
+	list, err := afero.ReadDir(fs, dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read module cache directory (dir: %s): %w", dir, err)
+	}
+
+	var modules []Module
+	for _, fi := range list {
+		if !fi.IsDir() {
+			continue
+		}
+
+		size, err := dirSize(fs, filepath.Join(dir, fi.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("could not determine size of module cache entry (entry: %s): %w", fi.Name(), err)
+		}
+
+		modules = append(modules, Module{
+			Key:  fi.Name(),
+			Size: size,
+		})
+	}
+
+	return modules, nil
+}
+
+// MissingModules returns the modules from the manifest that are not present in the module cache in the given directory.
+// Missing modules will be compiled again on first use.
+func MissingModules(fs afero.Fs, dir string, modules []Module) ([]Module, error) {
+
+	present, err := ModuleManifest(fs, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	have := make(map[string]struct{}, len(present))
+	for _, module := range present {
+		have[module.Key] = struct{}{}
+	}
+
+	var missing []Module
+	for _, module := range modules {
+		_, ok := have[module.Key]
+		if !ok {
+			missing = append(missing, module)
+		}
+	}
+
+	return missing, nil
+}
+
+func dirSize(fs afero.Fs, dir string) (int64, error) {
+
+	var size int64
+	err := afero.Walk(fs, dir, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			size += info.Size()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return size, nil
+}
@@ -0,0 +1,117 @@
+package migration_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/node/internal/reputation"
+	"github.com/blocklessnetwork/b7s/node/migration"
+	"github.com/blocklessnetwork/b7s/store"
+	"github.com/blocklessnetwork/b7s/store/codec"
+	"github.com/blocklessnetwork/b7s/testing/helpers"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestState(t *testing.T) {
+
+	const (
+		statePath = "/state.json"
+	)
+
+	key, _, err := crypto.GenerateKeyPair(crypto.Ed25519, 0)
+	require.NoError(t, err)
+
+	identity, err := crypto.MarshalPrivateKey(key)
+	require.NoError(t, err)
+
+	state := migration.State{
+		Version:   migration.Version,
+		Created:   time.Now().UTC(),
+		Identity:  identity,
+		Peers:     []blockless.Peer{mocks.GenericPeer},
+		Functions: []blockless.FunctionRecord{mocks.GenericFunctionRecord},
+		Reputation: map[peer.ID]reputation.History{
+			mocks.GenericPeerID: {Executions: 10, Successes: 9, Timeouts: 1},
+		},
+	}
+
+	t.Run("state can be written and read", func(t *testing.T) {
+		t.Parallel()
+
+		fs := afero.NewMemMapFs()
+		require.NoError(t, migration.Write(fs, statePath, state))
+
+		read, err := migration.Read(fs, statePath)
+		require.NoError(t, err)
+		require.Equal(t, state.Peers, read.Peers)
+		require.Equal(t, state.Functions, read.Functions)
+		require.Equal(t, state.Reputation, read.Reputation)
+		require.True(t, state.Created.Equal(read.Created))
+
+		imported, err := read.PrivateKey()
+		require.NoError(t, err)
+		require.True(t, key.Equals(imported))
+	})
+	t.Run("unknown versions are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		fs := afero.NewMemMapFs()
+
+		future := state
+		future.Version = migration.Version + 1
+		require.NoError(t, migration.Write(fs, statePath, future))
+
+		_, err := migration.Read(fs, statePath)
+		require.ErrorIs(t, err, migration.ErrUnsupportedVersion)
+	})
+	t.Run("peers and functions are restored", func(t *testing.T) {
+		t.Parallel()
+
+		store := store.New(helpers.InMemoryDB(t), codec.NewJSONCodec())
+		require.NoError(t, migration.Restore(context.Background(), store, state))
+
+		peers, err := store.RetrievePeers(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, state.Peers, peers)
+
+		function, err := store.RetrieveFunction(context.Background(), mocks.GenericFunctionRecord.CID)
+		require.NoError(t, err)
+		require.Equal(t, mocks.GenericFunctionRecord, function)
+	})
+}
+
+func TestModuleManifest(t *testing.T) {
+
+	const (
+		dir     = "/modules"
+		copied  = "copied-module"
+		missing = "missing-module"
+	)
+
+	fs := afero.NewMemMapFs()
+
+	// Directory that does not exist yet has no modules.
+	modules, err := migration.ModuleManifest(fs, dir)
+	require.NoError(t, err)
+	require.Empty(t, modules)
+
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(dir, copied, "module.so"), []byte("compiled-module"), 0o644))
+
+	modules, err = migration.ModuleManifest(fs, dir)
+	require.NoError(t, err)
+	require.Equal(t, []migration.Module{{Key: copied, Size: int64(len("compiled-module"))}}, modules)
+
+	manifest := append(modules, migration.Module{Key: missing, Size: 100})
+
+	absent, err := migration.MissingModules(fs, dir, manifest)
+	require.NoError(t, err)
+	require.Equal(t, []migration.Module{{Key: missing, Size: 100}}, absent)
+}
@@ -0,0 +1,52 @@
+package node
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/node/internal/reputation"
+	"github.com/blocklessnetwork/b7s/node/migration"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_ExportState(t *testing.T) {
+
+	const (
+		installedFunction = "installed-function"
+	)
+
+	var (
+		worker  = mocks.GenericPeerIDs[0]
+		history = reputation.History{Executions: 10, Successes: 9, Timeouts: 1}
+	)
+
+	node := createNode(t, blockless.WorkerNode)
+
+	fstore := mocks.BaselineFStore(t)
+	fstore.InstalledFunc = func(context.Context) ([]string, error) {
+		return []string{installedFunction}, nil
+	}
+	node.fstore = fstore
+
+	node.reputation.Import(map[peer.ID]reputation.History{worker: history})
+
+	state, err := node.ExportState(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint(migration.Version), state.Version)
+	require.Equal(t, []blockless.Peer{mocks.GenericPeer}, state.Peers)
+	require.Equal(t, []blockless.FunctionRecord{mocks.GenericFunctionRecord}, state.Functions)
+	require.Equal(t, history, state.Reputation[worker])
+
+	key, err := state.PrivateKey()
+	require.NoError(t, err)
+	require.True(t, node.host.PrivateKey().Equals(key))
+
+	// Node started with the exported state keeps the peer reputation.
+	imported, err := New(mocks.NoopLogger, node.host, mocks.BaselineStore(t), fstore, WithRole(blockless.HeadNode), WithImportedState(state))
+	require.NoError(t, err)
+	require.Equal(t, node.reputation.Score(worker), imported.reputation.Score(worker))
+}
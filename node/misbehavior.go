@@ -0,0 +1,109 @@
+package node
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/node/internal/reputation"
+)
+
+// excludedPeers tracks workers caught misbehaving, which are not chosen for executions until their exclusion expires.
+type excludedPeers struct {
+	sync.Mutex
+	peers map[peer.ID]time.Time
+}
+
+func newExcludedPeers() *excludedPeers {
+	return &excludedPeers{
+		peers: make(map[peer.ID]time.Time),
+	}
+}
+
+func (e *excludedPeers) exclude(peer peer.ID, until time.Time) {
+	e.Lock()
+	defer e.Unlock()
+
+	if until.After(e.peers[peer]) {
+		e.peers[peer] = until
+	}
+}
+
+func (e *excludedPeers) excluded(peer peer.ID, now time.Time) bool {
+	e.Lock()
+	defer e.Unlock()
+
+	until, ok := e.peers[peer]
+	if !ok {
+		return false
+	}
+
+	if now.After(until) {
+		delete(e.peers, peer)
+		return false
+	}
+
+	return true
+}
+
+// misbehaviorReporter returns the function consensus replicas use to report misbehaving cluster members to the head node.
+func (n *Node) misbehaviorReporter(head peer.ID, requestID string) func(consensus.Misbehavior) {
+	return func(m consensus.Misbehavior) {
+
+		ctx, cancel := context.WithTimeout(context.Background(), consensusClusterSendTimeout)
+		defer cancel()
+
+		err := n.send(ctx, head, &response.Misbehavior{RequestID: requestID, Misbehavior: m})
+		if err != nil {
+			n.log.Warn().Err(err).Str("request", requestID).Stringer("replica", m.Replica).Msg("could not report replica misbehavior")
+		}
+	}
+}
+
+// processMisbehavior handles the evidence of a worker misbehaving in a consensus cluster. Only members of the cluster formed for
+// an execution in progress can report other members. Workers proven to misbehave are excluded from roll calls. Evidence that
+// cannot be verified is dropped, since any member could make it up.
+func (n *Node) processMisbehavior(ctx context.Context, from peer.ID, res response.Misbehavior) error {
+
+	log := n.log.With().
+		Str("request", res.RequestID).
+		Stringer("reporter", from).
+		Stringer("replica", res.Replica).
+		Str("kind", res.Kind).
+		Logger()
+
+	log.Info().Msg("received replica misbehavior report")
+
+	n.metrics.IncrCounterWithLabels(misbehaviorReportsMetric, 1, []metrics.Label{{Name: "kind", Value: res.Kind}})
+
+	// Workers cannot report themselves.
+	if res.Replica == from {
+		log.Warn().Msg("dropping misbehavior report of the reporter itself")
+		return nil
+	}
+
+	members, ok := n.executions.peers(res.RequestID)
+	if !ok || !slices.Contains(members, from) || !slices.Contains(members, res.Replica) {
+		log.Warn().Msg("dropping misbehavior report from outside of the execution cluster")
+		return nil
+	}
+
+	err := verifyMisbehavior(res.Misbehavior)
+	if err != nil {
+		log.Info().Err(err).Msg("could not verify misbehavior evidence, dropping report")
+		return nil
+	}
+
+	log.Warn().Dur("duration", misbehaviorExclusionDuration).Msg("replica misbehavior verified, excluding worker from roll calls")
+
+	n.reputation.Record(res.Replica, reputation.Mismatch)
+	n.excluded.exclude(res.Replica, time.Now().Add(misbehaviorExclusionDuration))
+
+	return nil
+}
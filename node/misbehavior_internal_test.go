@@ -0,0 +1,95 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_ExcludedPeers(t *testing.T) {
+
+	var (
+		peer = mocks.GenericPeerIDs[0]
+		now  = time.Now()
+	)
+
+	excluded := newExcludedPeers()
+	require.False(t, excluded.excluded(peer, now))
+
+	excluded.exclude(peer, now.Add(time.Hour))
+	// Shorter exclusion does not shorten the existing one.
+	excluded.exclude(peer, now.Add(time.Minute))
+
+	require.True(t, excluded.excluded(peer, now.Add(30*time.Minute)))
+	require.False(t, excluded.excluded(peer, now.Add(2*time.Hour)))
+	require.False(t, excluded.excluded(peer, now))
+}
+
+func TestNode_ProcessMisbehavior(t *testing.T) {
+
+	var (
+		reporter = mocks.GenericPeerIDs[0]
+		replica  = mocks.GenericPeerIDs[1]
+	)
+
+	report := response.Misbehavior{
+		RequestID: mocks.GenericUUID.String(),
+		Misbehavior: consensus.Misbehavior{
+			Consensus: consensus.PBFT,
+			Replica:   replica,
+			Kind:      consensus.MisbehaviorInvalidSignature,
+		},
+	}
+
+	t.Run("unverifiable evidence does not penalize the worker", func(t *testing.T) {
+		node := createNode(t, blockless.HeadNode)
+		node.executions.start(report.RequestID, mocks.GenericFunctionRecord.CID, consensus.PBFT, []peer.ID{reporter, replica}, nil, "")
+
+		score := node.reputation.Score(replica)
+
+		err := node.processMisbehavior(context.Background(), reporter, report)
+		require.NoError(t, err)
+		require.False(t, node.excluded.excluded(replica, time.Now()))
+		require.Equal(t, score, node.reputation.Score(replica))
+	})
+	t.Run("reports from outside of the cluster are dropped", func(t *testing.T) {
+		node := createNode(t, blockless.HeadNode)
+
+		score := node.reputation.Score(replica)
+
+		// No execution in progress.
+		err := node.processMisbehavior(context.Background(), reporter, report)
+		require.NoError(t, err)
+		require.Equal(t, score, node.reputation.Score(replica))
+
+		// Reporter is not a member of the cluster.
+		node.executions.start(report.RequestID, mocks.GenericFunctionRecord.CID, consensus.PBFT, []peer.ID{replica, mocks.GenericPeerIDs[2]}, nil, "")
+
+		err = node.processMisbehavior(context.Background(), reporter, report)
+		require.NoError(t, err)
+		require.Equal(t, score, node.reputation.Score(replica))
+	})
+	t.Run("workers cannot report themselves", func(t *testing.T) {
+		node := createNode(t, blockless.HeadNode)
+
+		report := response.Misbehavior{
+			Misbehavior: consensus.Misbehavior{
+				Consensus: consensus.PBFT,
+				Replica:   reporter,
+				Kind:      consensus.MisbehaviorConflictingVote,
+			},
+		}
+
+		err := node.processMisbehavior(context.Background(), reporter, report)
+		require.NoError(t, err)
+		require.False(t, node.excluded.excluded(reporter, time.Now()))
+	})
+}
@@ -2,20 +2,32 @@ package node
 
 import (
 	"fmt"
+	"net/http"
 	"slices"
 	"sync"
 
 	"github.com/armon/go-metrics"
-	"github.com/google/uuid"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/rs/zerolog"
 
 	"github.com/blocklessnetwork/b7s-attributes/attributes"
+	"github.com/blocklessnetwork/b7s/consensus"
 	"github.com/blocklessnetwork/b7s/host"
 	"github.com/blocklessnetwork/b7s/info"
 	"github.com/blocklessnetwork/b7s/models/blockless"
 	"github.com/blocklessnetwork/b7s/models/execute"
 	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/node/head/canary"
+	"github.com/blocklessnetwork/b7s/node/head/fleet"
+	"github.com/blocklessnetwork/b7s/node/head/usage"
+	"github.com/blocklessnetwork/b7s/node/internal/dispatch"
+	"github.com/blocklessnetwork/b7s/node/internal/ratelimit"
+	"github.com/blocklessnetwork/b7s/node/internal/reputation"
+	"github.com/blocklessnetwork/b7s/node/internal/resultcache"
 	"github.com/blocklessnetwork/b7s/node/internal/waitmap"
+	"github.com/blocklessnetwork/b7s/node/internal/workqueue"
+	"github.com/blocklessnetwork/b7s/random"
+	"github.com/blocklessnetwork/b7s/safehttp"
 	"github.com/blocklessnetwork/b7s/telemetry/tracing"
 )
 
@@ -29,26 +41,128 @@ import (
 type Node struct {
 	cfg Config
 
-	log      zerolog.Logger
-	host     *host.Host
-	executor blockless.Executor
-	fstore   FStore
+	log       zerolog.Logger
+	host      *host.Host
+	dataHost  *host.Host
+	executor  blockless.Executor
+	executors *swappableExecutor // Wrapped by the executor, allows switching executors at runtime.
+	fstore    FStore
 
-	sema       chan struct{}
+	work       *workqueue.Queue
 	wg         *sync.WaitGroup
 	subgroups  workSubgroups
 	attributes *attributes.Attestation
 
-	rollCall *rollCallQueue
+	rollCall RollCallStore
 
 	// clusters maps request ID to the cluster the node belongs to.
-	clusters map[string]consensusExecutor
+	clusters map[string]consensus.Replica
+
+	// clusterProgress tracks when the clusters last made progress, so stalled clusters can be shut down.
+	clusterProgress *clusterProgress
+
+	// drain tracks whether the worker stopped taking on new work ahead of shutdown.
+	drain drainState
+
+	// prefetches tracks function installations started when answering roll calls.
+	prefetches *functionPrefetches
 
 	// clusterLock is used to synchronize access to the `clusters` map.
 	clusterLock sync.RWMutex
 
-	executeResponses   *waitmap.WaitMap[string, execute.ResultMap]
+	executeResponses   ResultStore
 	consensusResponses *waitmap.WaitMap[string, response.FormCluster]
+	clusterUpdates     *waitmap.WaitMap[string, response.UpdateCluster]
+	leaderTransfers    *waitmap.WaitMap[string, response.TransferLeadership]
+	delegations        *waitmap.WaitMap[string, response.DelegateExecution]
+	healthQueries      *waitmap.WaitMap[peer.ID, response.HealthQuery]
+
+	// peerStore holds the peers known from previous runs.
+	peerStore blockless.PeerStore
+
+	// warm tracks whether the head node has reached the known peers on startup.
+	warm readiness
+
+	// dispatch tracks how many executions can be dispatched to a worker at a time.
+	dispatch *dispatch.Limiter[peer.ID]
+
+	// reputation tracks how workers handled previous executions, and is used to choose workers for new ones.
+	reputation *reputation.Tracker[peer.ID]
+
+	// latencies tracks roll call round-trip times of workers, and is used to choose workers for new executions.
+	latencies *peerLatencies
+
+	// excluded tracks workers caught misbehaving in consensus clusters, which are not chosen for executions.
+	excluded *excludedPeers
+
+	// loads tracks the load workers reported in roll call responses, and is used to choose workers for new executions.
+	loads *peerLoads
+
+	// pools holds the warm worker pools, used instead of roll calls for some functions.
+	pools *warmPools
+	// members tracks workers that presented valid membership credentials for restricted subgroups.
+	members *subgroupMembers
+	// affinities holds the worker sets bound to affinity tokens of client requests.
+	affinities *affinities
+
+	// summaries holds the full results of executions answered with a result summary, for the requester to retrieve.
+	summaries *summarizedExecutions
+
+	// shadows holds the results of shadow executions, mapped by the ID of the request they shadowed.
+	shadows *waitmap.WaitMap[string, ShadowResult]
+
+	// canaries tracks the health of subgroups based on canary probes.
+	canaries *canary.Monitor
+
+	// executions tracks executions the head node is working on.
+	executions *activeExecutions
+	// running tracks executions the worker is working on, so the head node can abort them.
+	running *runningExecutions
+
+	// disconnects notifies the node when peers disconnect, e.g. while executing a request.
+	disconnects *disconnectWatcher
+
+	// peers tracks healthy peers we heard from, shared with other nodes via peer exchange.
+	peers *peerDirectory
+	// exchanges tracks peer exchange requests we sent and are waiting for responses to.
+	exchanges *peerExchangeRequests
+
+	// callbacks is the HTTP client used to post asynchronous execution results to client-supplied URLs.
+	callbacks *http.Client
+
+	// schedules holds recurring executions run by the head node.
+	schedules *executionSchedules
+
+	// deferred holds execution requests waiting for their not-before time.
+	deferred *deferredExecutions
+
+	// fleet holds the latest fleet reports of workers, used to plan executions and choose workers for them.
+	fleet *fleet.Index
+
+	// usage tracks resources consumed by executions, grouped by request tags.
+	usage *usage.Accountant
+
+	// recovery describes the state the head node found on startup.
+	recovery *recoveryReport
+
+	// rateLimiter limits the rate of execution requests per function. Nil if there are no limits.
+	rateLimiter *ratelimit.Limiter
+
+	// clientLimits limits the number of executions each client can have in flight. Nil if there is no limit.
+	clientLimits *clientConcurrency[peer.ID]
+
+	// tenantLimits limits the number of executions each tenant can have in flight on the worker. Nil if there is no limit.
+	tenantLimits *clientConcurrency[string]
+
+	// rand is the source of random numbers for the node.
+	rand *random.Source
+
+	// resultCache holds results of recent executions, served for identical executions. Nil if result caching is disabled.
+	resultCache *resultcache.Cache
+
+	// streams maps request ID to the client that wants results forwarded as they arrive.
+	streams    map[string]*resultStream
+	streamLock sync.Mutex
 
 	// Telemetry
 	tracer  *tracing.Tracer
@@ -70,9 +184,23 @@ func New(log zerolog.Logger, host *host.Host, store blockless.PeerStore, fstore
 		cfg.Topics = append(cfg.Topics, DefaultTopic)
 	}
 
+	// Head nodes learn about the workers from the reports they publish on the fleet topic.
+	if cfg.Role == blockless.HeadNode && !slices.Contains(cfg.Topics, DefaultFleetTopic) {
+		cfg.Topics = append(cfg.Topics, DefaultFleetTopic)
+	}
+
 	subgroups := workSubgroups{
 		RWMutex: &sync.RWMutex{},
 		topics:  make(map[string]*topicInfo),
+		loops:   &sync.WaitGroup{},
+	}
+
+	// Unless they are shared with other head nodes, keep roll call responses and execution results in memory.
+	if cfg.RollCallStore == nil {
+		cfg.RollCallStore = newQueue(cfg.RollCallQueueSize, cfg.RollCallOverflow, cfg.RollCallBlockTimeout)
+	}
+	if cfg.ResultStore == nil {
+		cfg.ResultStore = waitmap.New[string, execute.ResultMap](executionResultCacheSize)
 	}
 
 	n := &Node{
@@ -80,22 +208,78 @@ func New(log zerolog.Logger, host *host.Host, store blockless.PeerStore, fstore
 
 		log:      log,
 		host:     host,
+		dataHost: cfg.DataHost,
 		fstore:   fstore,
 		executor: cfg.Execute,
 
 		wg:        &sync.WaitGroup{},
-		sema:      make(chan struct{}, cfg.Concurrency),
+		work:      workqueue.New(cfg.Concurrency, cfg.QueueSize),
 		subgroups: subgroups,
 
-		rollCall:           newQueue(rollCallQueueBufferSize),
-		clusters:           make(map[string]consensusExecutor),
-		executeResponses:   waitmap.New[string, execute.ResultMap](executionResultCacheSize),
+		rollCall:           cfg.RollCallStore,
+		clusters:           make(map[string]consensus.Replica),
+		clusterProgress:    newClusterProgress(),
+		executeResponses:   cfg.ResultStore,
 		consensusResponses: waitmap.New[string, response.FormCluster](0),
+		clusterUpdates:     waitmap.New[string, response.UpdateCluster](0),
+		leaderTransfers:    waitmap.New[string, response.TransferLeadership](0),
+		delegations:        waitmap.New[string, response.DelegateExecution](0),
+		healthQueries:      waitmap.New[peer.ID, response.HealthQuery](warmStartMaxPeers),
+		peerStore:          store,
+		streams:            make(map[string]*resultStream),
+		dispatch:           dispatch.New[peer.ID](cfg.WorkerDispatchLimit),
+		reputation:         reputation.New[peer.ID](),
+		latencies:          newPeerLatencies(),
+		excluded:           newExcludedPeers(),
+		loads:              newPeerLoads(),
+		canaries:           canary.NewMonitor(),
+		pools:              newWarmPools(),
+		members:            newSubgroupMembers(),
+		affinities:         newAffinities(),
+		shadows:            waitmap.New[string, ShadowResult](shadowResultCacheSize),
+		summaries:          newSummarizedExecutions(),
+		peers:              newPeerDirectory(),
+		exchanges:          newPeerExchangeRequests(),
+		callbacks:          safehttp.NewClient(callbackTimeout),
+		disconnects:        newDisconnectWatcher(),
+		executions:         newActiveExecutions(),
+		running:            newRunningExecutions(),
+		prefetches:         newFunctionPrefetches(),
+		schedules:          newExecutionSchedules(),
+		deferred:           newDeferredExecutions(),
+		usage:              usage.NewAccountant(),
+		fleet:              fleet.NewIndex(fleetReportTTL, fleetIndexMaxPeers),
+		recovery:           &recoveryReport{},
 
 		tracer:  tracing.NewTracer(tracerName),
 		metrics: metrics.Default(),
 	}
 
+	n.rand = cfg.Random
+	if n.rand == nil {
+		n.rand = random.NewUnseeded()
+	}
+
+	if cfg.ImportedState != nil {
+		n.reputation.Import(cfg.ImportedState.Reputation)
+	}
+
+	if cfg.ClientConcurrency > 0 {
+		n.clientLimits = newClientConcurrency[peer.ID](cfg.ClientConcurrency, cfg.ClientQueueSize)
+	}
+
+	if cfg.TenantConcurrency > 0 {
+		n.tenantLimits = newClientConcurrency[string](cfg.TenantConcurrency, cfg.TenantQueueSize)
+	}
+
+	if cfg.ResultCacheTTL > 0 {
+		n.resultCache = resultcache.New(cfg.ResultCacheTTL, workerResultCacheSize)
+	}
+
+	if len(cfg.RateLimits) > 0 {
+		n.rateLimiter = newRateLimiter(cfg.RateLimits)
+	}
+
 	if cfg.LoadAttributes {
 		attributes, err := loadAttributes(host.PublicKey())
 		if err != nil {
@@ -106,13 +290,20 @@ func New(log zerolog.Logger, host *host.Host, store blockless.PeerStore, fstore
 		n.log.Info().Interface("attributes", n.attributes).Msg("node loaded attributes")
 	}
 
+	// Run functions packaged as container images using the container executor, then validate and normalize function output,
+	// according to the function manifest.
+	if n.executor != nil {
+		n.executors = newSwappableExecutor(n.executor)
+		n.executor = newOutputProcessor(log, newContainerRouter(log, n.executors, cfg.ContainerExecutor, fstore), fstore, n.metrics)
+	}
+
 	err := n.ValidateConfig()
 	if err != nil {
 		return nil, fmt.Errorf("node configuration is not valid: %w", err)
 	}
 
 	// Create a notifiee with a backing store.
-	cn := newConnectionNotifee(log, store)
+	cn := newConnectionNotifee(log, store, n.disconnects)
 	host.Network().Notify(cn)
 
 	n.metrics.SetGaugeWithLabels(nodeInfoMetric, 1, []metrics.Label{
@@ -129,6 +320,7 @@ func (n *Node) ID() string {
 	return n.host.ID().String()
 }
 
-func newRequestID() string {
-	return uuid.New().String()
+// newRequestID returns a new random request ID.
+func (n *Node) newRequestID() string {
+	return n.rand.UUID().String()
 }
@@ -15,6 +15,7 @@ import (
 
 	"github.com/blocklessnetwork/b7s/host"
 	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/testing/helpers"
 	"github.com/blocklessnetwork/b7s/testing/mocks"
 )
 
@@ -67,6 +68,21 @@ func TestNode_New(t *testing.T) {
 		_, err = New(logger, host, store, functionHandler, WithRole(blockless.WorkerNode))
 		require.Error(t, err)
 	})
+	t.Run("same seed produces same request IDs", func(t *testing.T) {
+		t.Parallel()
+
+		seed := helpers.RandomSeed(t)
+
+		first, err := New(logger, host, store, functionHandler, WithRole(blockless.HeadNode), WithRandomSeed(seed))
+		require.NoError(t, err)
+
+		second, err := New(logger, host, store, functionHandler, WithRole(blockless.HeadNode), WithRandomSeed(seed))
+		require.NoError(t, err)
+
+		id := first.newRequestID()
+		require.Equal(t, id, second.newRequestID())
+		require.NotEqual(t, id, first.newRequestID())
+	})
 }
 
 func createNode(t *testing.T, role blockless.NodeRole) *Node {
@@ -83,6 +99,7 @@ func createNode(t *testing.T, role blockless.NodeRole) *Node {
 
 	opts := []Option{
 		WithRole(role),
+		WithRandomSeed(helpers.RandomSeed(t)),
 	}
 
 	if role == blockless.WorkerNode {
@@ -0,0 +1,122 @@
+package node
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+// normalizer applies the normalization rules of a function manifest to the execution output.
+type normalizer struct {
+	rules    blockless.Normalization
+	patterns []*regexp.Regexp
+}
+
+// newNormalizer prepares the normalization rules for use, compiling the strip patterns.
+func newNormalizer(rules blockless.Normalization) (*normalizer, error) {
+
+	patterns := make([]*regexp.Regexp, 0, len(rules.StripPatterns))
+	for _, pattern := range rules.StripPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid strip pattern (pattern: %s): %w", pattern, err)
+		}
+		patterns = append(patterns, re)
+	}
+
+	z := normalizer{
+		rules:    rules,
+		patterns: patterns,
+	}
+
+	return &z, nil
+}
+
+// normalize applies the normalization rules to the execution output.
+func (z *normalizer) normalize(out execute.RuntimeOutput) (execute.RuntimeOutput, error) {
+
+	stdout := out.Stdout
+
+	if len(z.rules.IgnoreFields) > 0 {
+		normalized, err := removeJSONFields(stdout, z.rules.IgnoreFields)
+		if err != nil {
+			return execute.RuntimeOutput{}, fmt.Errorf("could not remove ignored fields: %w", err)
+		}
+		stdout = normalized
+	}
+
+	for _, re := range z.patterns {
+		stdout = re.ReplaceAllString(stdout, "")
+	}
+
+	if z.rules.TrimWhitespace {
+		stdout = trimWhitespace(stdout)
+	}
+
+	out.Stdout = stdout
+
+	if z.rules.IgnoreStderr {
+		out.Stderr = ""
+	}
+
+	return out, nil
+}
+
+// removeJSONFields removes the given fields from the JSON document. Output that is not a JSON object is returned as is.
+// Since the document is re-encoded, differences in formatting and key order are removed too.
+func removeJSONFields(output string, fields []string) (string, error) {
+
+	dec := json.NewDecoder(strings.NewReader(output))
+	dec.UseNumber()
+
+	var doc map[string]any
+	err := dec.Decode(&doc)
+	if err != nil {
+		return output, nil
+	}
+
+	for _, field := range fields {
+		deleteField(doc, strings.Split(field, "."))
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	err = enc.Encode(doc)
+	if err != nil {
+		return "", fmt.Errorf("could not encode output: %w", err)
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+func deleteField(doc map[string]any, path []string) {
+
+	if len(path) == 1 {
+		delete(doc, path[0])
+		return
+	}
+
+	nested, ok := doc[path[0]].(map[string]any)
+	if !ok {
+		return
+	}
+
+	deleteField(nested, path[1:])
+}
+
+func trimWhitespace(output string) string {
+
+	lines := strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
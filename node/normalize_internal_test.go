@@ -0,0 +1,79 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+func TestNode_NormalizeOutput(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		rules    blockless.Normalization
+		outputs  []string
+		expected string
+	}{
+		{
+			name:     "trim whitespace",
+			rules:    blockless.Normalization{TrimWhitespace: true},
+			outputs:  []string{"hello\nworld", "  hello \r\n\tworld\n\n", "hello  \nworld  \n"},
+			expected: "hello\nworld",
+		},
+		{
+			name:     "strip patterns",
+			rules:    blockless.Normalization{StripPatterns: []string{`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z `}},
+			outputs:  []string{"2024-01-01T10:00:00Z price: 42", "2024-01-01T10:00:01Z price: 42"},
+			expected: "price: 42",
+		},
+		{
+			name:     "ignore JSON fields",
+			rules:    blockless.Normalization{IgnoreFields: []string{"timestamp", "meta.node"}},
+			outputs:  []string{`{"price": 42, "timestamp": 1, "meta": {"node": "a", "v": 1}}`, `{"meta":{"v":1,"node":"b"},"timestamp":2,"price":42}`},
+			expected: `{"meta":{"v":1},"price":42}`,
+		},
+		{
+			name:     "ignore fields of non JSON output",
+			rules:    blockless.Normalization{IgnoreFields: []string{"timestamp"}},
+			outputs:  []string{"not json"},
+			expected: "not json",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			normalizer, err := newNormalizer(test.rules)
+			require.NoError(t, err)
+
+			for _, output := range test.outputs {
+				out, err := normalizer.normalize(execute.RuntimeOutput{Stdout: output})
+				require.NoError(t, err)
+				require.Equal(t, test.expected, out.Stdout)
+			}
+		})
+	}
+
+	t.Run("ignore stderr", func(t *testing.T) {
+		t.Parallel()
+
+		normalizer, err := newNormalizer(blockless.Normalization{IgnoreStderr: true})
+		require.NoError(t, err)
+
+		out, err := normalizer.normalize(execute.RuntimeOutput{Stdout: "hello", Stderr: "warning", ExitCode: 0})
+		require.NoError(t, err)
+		require.Equal(t, "hello", out.Stdout)
+		require.Empty(t, out.Stderr)
+	})
+	t.Run("invalid strip pattern", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newNormalizer(blockless.Normalization{StripPatterns: []string{"("}})
+		require.Error(t, err)
+	})
+}
@@ -15,17 +15,19 @@ import (
 )
 
 type connectionNotifiee struct {
-	log    zerolog.Logger
-	store  blockless.PeerStore
-	tracer *tracing.Tracer
+	log         zerolog.Logger
+	store       blockless.PeerStore
+	disconnects *disconnectWatcher
+	tracer      *tracing.Tracer
 }
 
-func newConnectionNotifee(log zerolog.Logger, store blockless.PeerStore) *connectionNotifiee {
+func newConnectionNotifee(log zerolog.Logger, store blockless.PeerStore, disconnects *disconnectWatcher) *connectionNotifiee {
 
 	cn := connectionNotifiee{
-		log:    log.With().Str("component", "notifiee").Logger(),
-		store:  store,
-		tracer: tracing.NewTracer("b7s.Notifiee"),
+		log:         log.With().Str("component", "notifiee").Logger(),
+		store:       store,
+		disconnects: disconnects,
+		tracer:      tracing.NewTracer("b7s.Notifiee"),
 	}
 
 	return &cn
@@ -69,7 +71,7 @@ func (n *connectionNotifiee) Connected(network network.Network, conn network.Con
 	}
 }
 
-func (n *connectionNotifiee) Disconnected(_ network.Network, conn network.Conn) {
+func (n *connectionNotifiee) Disconnected(network network.Network, conn network.Conn) {
 
 	_, span := n.tracer.Start(context.Background(), spanPeerDisconnected, connectionTraceOpts(conn)...)
 	defer span.End()
@@ -83,6 +85,11 @@ func (n *connectionNotifiee) Disconnected(_ network.Network, conn network.Conn)
 		Str("remote_address", maddr.String()).
 		Str("local_address", laddr.String()).
 		Msg("peer disconnected")
+
+	// Peer might still be reachable over another connection.
+	if len(network.ConnsToPeer(peerID)) == 0 {
+		n.disconnects.disconnected(peerID)
+	}
 }
 
 func (n *connectionNotifiee) Listen(_ network.Network, _ multiaddr.Multiaddr) {
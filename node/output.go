@@ -0,0 +1,182 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/rs/zerolog"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+// outputProcessor wraps an executor and processes the function output according to the function manifest.
+// Output is validated against the output JSON Schema declared in the manifest. Results that do not conform
+// to the schema are marked as such, so they can be discarded before results from different nodes are compared.
+// Output is then normalized according to the manifest rules, so semantically identical results compare equal.
+// The normalized output is only used to compare results, the output returned to the client is not changed.
+type outputProcessor struct {
+	log      zerolog.Logger
+	executor blockless.Executor
+	fstore   FStore
+	metrics  *metrics.Metrics
+
+	// Output rules of functions, prepared once per manifest.
+	rulesLock sync.Mutex
+	rules     map[string]*outputRules
+}
+
+// outputRules are the output schema and normalization rules of a function manifest, prepared for use.
+type outputRules struct {
+	updated time.Time // Time the function record was updated, to notice a changed manifest.
+
+	schema    *jsonschema.Schema
+	schemaErr error
+
+	normalizer    *normalizer
+	normalizerErr error
+}
+
+func newOutputProcessor(log zerolog.Logger, executor blockless.Executor, fstore FStore, metrics *metrics.Metrics) *outputProcessor {
+
+	p := outputProcessor{
+		log:      log.With().Str("component", "output_processor").Logger(),
+		executor: executor,
+		fstore:   fstore,
+		metrics:  metrics,
+
+		rules: make(map[string]*outputRules),
+	}
+
+	return &p
+}
+
+// ExecuteFunction executes the function, then validates and normalizes its output.
+func (p *outputProcessor) ExecuteFunction(ctx context.Context, requestID string, req execute.Request) (execute.Result, error) {
+
+	res, err := p.executor.ExecuteFunction(ctx, requestID, req)
+	if err != nil || res.Code != codes.OK {
+		return res, err
+	}
+
+	log := p.log.With().Str("request", requestID).Str("function", req.FunctionID).Logger()
+
+	fn, err := p.fstore.Get(ctx, req.FunctionID)
+	if err != nil {
+		log.Warn().Err(err).Msg("could not retrieve function manifest, skipping output processing")
+		return res, nil
+	}
+
+	rules := p.outputRules(req.FunctionID, fn)
+
+	if len(fn.Manifest.OutputSchema) > 0 {
+		err = rules.schemaErr
+		if err == nil {
+			err = validateOutput(rules.schema, res.Result.Stdout)
+		}
+		if err != nil {
+			log.Warn().Err(err).Msg("function output does not conform to the output schema")
+
+			p.metrics.IncrCounterWithLabels(functionInvalidOutputMetric, 1, []metrics.Label{{Name: "function", Value: req.FunctionID}})
+
+			res.Code = codes.InvalidOutput
+			return res, nil
+		}
+	}
+
+	if fn.Manifest.Normalization != nil {
+		if rules.normalizerErr != nil {
+			log.Warn().Err(rules.normalizerErr).Msg("invalid output normalization rules")
+			return res, nil
+		}
+
+		out, err := rules.normalizer.normalize(res.Result)
+		if err != nil {
+			log.Warn().Err(err).Msg("could not normalize function output")
+			return res, nil
+		}
+
+		res.Normalized = &out
+	}
+
+	return res, nil
+}
+
+// outputRules returns the output rules of the function manifest, preparing them if the manifest was not seen before.
+func (p *outputProcessor) outputRules(functionID string, fn blockless.FunctionRecord) *outputRules {
+
+	p.rulesLock.Lock()
+	defer p.rulesLock.Unlock()
+
+	cached, ok := p.rules[functionID]
+	if ok && cached.updated.Equal(fn.UpdatedAt) {
+		return cached
+	}
+
+	rules := outputRules{
+		updated: fn.UpdatedAt,
+	}
+
+	if len(fn.Manifest.OutputSchema) > 0 {
+		rules.schema, rules.schemaErr = compileOutputSchema(fn.Manifest.OutputSchema)
+	}
+
+	if fn.Manifest.Normalization != nil {
+		rules.normalizer, rules.normalizerErr = newNormalizer(*fn.Manifest.Normalization)
+	}
+
+	p.rules[functionID] = &rules
+
+	return &rules
+}
+
+// compileOutputSchema compiles the JSON Schema. Schemas that do not declare their dialect are treated as draft 2020-12.
+// Only references within the schema are resolved, other documents are never loaded.
+func compileOutputSchema(schema json.RawMessage) (*jsonschema.Schema, error) {
+
+	const location = "output-schema.json"
+
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schema))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse output schema: %w", err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.UseLoader(jsonschema.SchemeURLLoader{})
+
+	err = compiler.AddResource(location, doc)
+	if err != nil {
+		return nil, fmt.Errorf("could not add output schema: %w", err)
+	}
+
+	compiled, err := compiler.Compile(location)
+	if err != nil {
+		return nil, fmt.Errorf("could not compile output schema: %w", err)
+	}
+
+	return compiled, nil
+}
+
+// validateOutput verifies that the output is a JSON document conforming to the given schema.
+func validateOutput(schema *jsonschema.Schema, output string) error {
+
+	value, err := jsonschema.UnmarshalJSON(strings.NewReader(output))
+	if err != nil {
+		return fmt.Errorf("output is not valid JSON: %w", err)
+	}
+
+	err = schema.Validate(value)
+	if err != nil {
+		return fmt.Errorf("output does not match schema: %w", err)
+	}
+
+	return nil
+}
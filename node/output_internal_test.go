@@ -0,0 +1,171 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/armon/go-metrics"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_OutputProcessor(t *testing.T) {
+
+	const (
+		requestID = "dummy-request-id"
+		schema    = `{"type": "object", "required": ["price"], "properties": {"price": {"type": "number"}}}`
+	)
+
+	req := execute.Request{
+		FunctionID: "dummy-function-id",
+		Method:     "dummy-function-method",
+	}
+
+	validate := func(t *testing.T, schema string, stdout string) execute.Result {
+		t.Helper()
+
+		executor := mocks.BaselineExecutor(t)
+		executor.ExecFunctionFunc = func(context.Context, string, execute.Request) (execute.Result, error) {
+			res := mocks.GenericExecutionResult
+			res.Code = codes.OK
+			res.Result.Stdout = stdout
+			return res, nil
+		}
+
+		fstore := mocks.BaselineFStore(t)
+		fstore.GetFunc = func(context.Context, string) (blockless.FunctionRecord, error) {
+			fn := mocks.GenericFunctionRecord
+			fn.Manifest.OutputSchema = json.RawMessage(schema)
+			return fn, nil
+		}
+
+		processor := newOutputProcessor(mocks.NoopLogger, executor, fstore, metrics.Default())
+
+		res, err := processor.ExecuteFunction(context.Background(), requestID, req)
+		require.NoError(t, err)
+
+		return res
+	}
+
+	t.Run("conforming output", func(t *testing.T) {
+		t.Parallel()
+
+		res := validate(t, schema, `{"price": 42.5}`)
+		require.Equal(t, codes.OK, res.Code)
+	})
+	t.Run("output not matching schema", func(t *testing.T) {
+		t.Parallel()
+
+		res := validate(t, schema, `{"price": "unknown"}`)
+		require.Equal(t, codes.InvalidOutput, res.Code)
+		require.Equal(t, `{"price": "unknown"}`, res.Result.Stdout)
+	})
+	t.Run("output not valid JSON", func(t *testing.T) {
+		t.Parallel()
+
+		res := validate(t, schema, "garbage")
+		require.Equal(t, codes.InvalidOutput, res.Code)
+	})
+	t.Run("schema with local references", func(t *testing.T) {
+		t.Parallel()
+
+		const schema = `{"$defs": {"price": {"type": "number", "minimum": 0}}, "type": "object", "properties": {"price": {"$ref": "#/$defs/price"}}}`
+
+		res := validate(t, schema, `{"price": 42.5}`)
+		require.Equal(t, codes.OK, res.Code)
+
+		res = validate(t, schema, `{"price": -1}`)
+		require.Equal(t, codes.InvalidOutput, res.Code)
+	})
+	t.Run("schema with draft 2020-12 keywords", func(t *testing.T) {
+		t.Parallel()
+
+		const schema = `{"type": "array", "prefixItems": [{"type": "string"}, {"type": "number"}]}`
+
+		res := validate(t, schema, `["price", 42.5]`)
+		require.Equal(t, codes.OK, res.Code)
+
+		res = validate(t, schema, `[42.5, "price"]`)
+		require.Equal(t, codes.InvalidOutput, res.Code)
+	})
+	t.Run("OpenAPI keywords are not part of JSON Schema", func(t *testing.T) {
+		t.Parallel()
+
+		res := validate(t, `{"type": "string", "nullable": true}`, "null")
+		require.Equal(t, codes.InvalidOutput, res.Code)
+	})
+	t.Run("schema referencing other documents", func(t *testing.T) {
+		t.Parallel()
+
+		res := validate(t, `{"$ref": "file:///etc/schema.json"}`, `{"price": 42.5}`)
+		require.Equal(t, codes.InvalidOutput, res.Code)
+	})
+	t.Run("no output schema", func(t *testing.T) {
+		t.Parallel()
+
+		res := validate(t, "", "garbage")
+		require.Equal(t, codes.OK, res.Code)
+	})
+}
+
+func TestNode_OutputProcessorNormalization(t *testing.T) {
+
+	const (
+		requestID = "dummy-request-id"
+	)
+
+	req := execute.Request{
+		FunctionID: "dummy-function-id",
+		Method:     "dummy-function-method",
+	}
+
+	var stdout string
+
+	executor := mocks.BaselineExecutor(t)
+	executor.ExecFunctionFunc = func(context.Context, string, execute.Request) (execute.Result, error) {
+		res := mocks.GenericExecutionResult
+		res.Code = codes.OK
+		res.Result.Stdout = stdout
+		return res, nil
+	}
+
+	fstore := mocks.BaselineFStore(t)
+	fstore.GetFunc = func(context.Context, string) (blockless.FunctionRecord, error) {
+		fn := mocks.GenericFunctionRecord
+		fn.Manifest.Normalization = &blockless.Normalization{IgnoreFields: []string{"timestamp"}}
+		return fn, nil
+	}
+
+	processor := newOutputProcessor(mocks.NoopLogger, executor, fstore, metrics.Default())
+
+	run := func(t *testing.T, output string) execute.Result {
+		t.Helper()
+
+		stdout = output
+
+		res, err := processor.ExecuteFunction(context.Background(), requestID, req)
+		require.NoError(t, err)
+		require.Equal(t, codes.OK, res.Code)
+
+		return res
+	}
+
+	first := run(t, `{"price": 42, "timestamp": 1}`)
+	second := run(t, `{"price": 42, "timestamp": 2}`)
+	different := run(t, `{"price": 43, "timestamp": 1}`)
+
+	// Output is returned as is, only the checksum results are compared by is normalized.
+	require.Equal(t, `{"price": 42, "timestamp": 1}`, first.Result.Stdout)
+	require.Equal(t, `{"price": 42, "timestamp": 2}`, second.Result.Stdout)
+	require.NotNil(t, first.Normalized)
+	require.Equal(t, first.Normalized, second.Normalized)
+	require.NotEqual(t, first.Normalized, different.Normalized)
+
+	// Normalization rules are prepared once for the manifest.
+	require.Len(t, processor.rules, 1)
+}
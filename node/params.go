@@ -14,15 +14,28 @@ const (
 	DefaultExecutionTimeout        = 20 * time.Second
 	DefaultClusterFormationTimeout = 10 * time.Second
 	DefaultConcurrency             = 10
+	DefaultQueueSize               = 10 * DefaultConcurrency
+	DefaultWorkerDispatchLimit     = DefaultConcurrency
+	DefaultScheduleResultTopic     = "blockless/b7s/schedules"
+	DefaultResultRetention         = 24 * time.Hour
+	DefaultRollCallQueueSize       = 1000
+	DefaultRollCallOverflow        = RollCallBlock
+	DefaultRollCallBlockTimeout    = time.Second
+	DefaultRollCallSamplingFactor  = 3.0
+	DefaultSlowHandlerThreshold    = 10 * time.Second
+	DefaultFleetTopic              = "blockless/b7s/fleet"
+	DefaultFleetReportInterval     = 1 * time.Minute
+	DefaultClusterDeadline         = 30 * time.Minute
+	DefaultDelegationMaxDepth      = 2
 
-	ClusterAddressTTL = 30 * time.Minute
+	ClusterAddressTTL      = 30 * time.Minute
+	DataAddressTTL         = 30 * time.Minute
+	PeerExchangeAddressTTL = 30 * time.Minute
 
 	DefaultConsensusAlgorithm = consensus.Raft
 
 	DefaultAttributeLoadingSetting = false
 
-	rollCallQueueBufferSize = 1000
-
 	defaultExecutionThreshold = 0.6
 
 	syncInterval = time.Hour // How often do we recheck function installations.
@@ -30,6 +43,64 @@ const (
 	allowErrorLeakToTelemetry = false // By default we will not send processing errors to telemetry tracers.
 
 	executionResultCacheSize = 1000
+
+	shadowResultCacheSize = 1000 // How many shadow execution results do we keep for comparison.
+
+	resultSummaryCacheSize = 100              // How many executions answered with a result summary do we keep full results for.
+	resultSummaryRetention = 10 * time.Minute // How long can the requester retrieve full results of an execution answered with a result summary.
+
+	workerResultCacheSize = 1000 // How many results of executions does the worker keep for serving identical executions.
+
+	scheduleCheckInterval = time.Second // How often do we check for due scheduled executions.
+	maxSchedulesPerPeer   = 100         // Maximum number of execution schedules a single peer can register.
+
+	interactiveMaxLineSize = 1 << 20 // Maximum size of a single line on the stream of an interactive execution - the request or a frame.
+
+	callbackTimeout = 10 * time.Second // How long do we wait for the callback URL to accept asynchronous execution results.
+
+	abortExecutionSendTimeout = 10 * time.Second // How long do we try to let workers know an abandoned execution can be aborted.
+
+	workerResponseGrace = 5 * time.Second // How long past the request timeout do we wait for a worker before failing over to a reserve peer.
+
+	archiveInterval = time.Minute // How often do we move old execution results to the archive.
+
+	executorCanaryTimeout = time.Minute // How long do we wait for in-flight executions to drain and the canary execution when switching executors.
+
+	warmStartTimeout  = 5 * time.Second // How long do we wait for known peers to answer the health query on startup.
+	warmStartMaxPeers = 500             // Maximum number of known peers the head node reaches out to on startup.
+
+	slowHandlerStackSize = 64 << 10 // Maximum size of the goroutine stack dump logged for slow message handlers.
+
+	fleetReportTTL     = 5 * time.Minute // Fleet reports older than this are ignored by the head node.
+	fleetColdWeight    = 0.9             // Weight of workers that did not have the function installed according to their fleet report.
+	fleetPlanMaxPeers  = 50              // Maximum number of candidates listed in an execution plan.
+	fleetIndexMaxPeers = 10_000          // Maximum number of workers the head node keeps fleet reports for.
+
+	misbehaviorExclusionDuration = 24 * time.Hour // How long are workers proven to misbehave in consensus excluded from roll calls.
+)
+
+// Peer exchange related parameters.
+const (
+	peerExchangeFanout         = 5                         // How many connected peers do we ask for known workers.
+	peerExchangeMaxPeers       = 20                        // Maximum number of workers shared in a single response.
+	peerExchangeMaxAge         = 3 * DefaultHealthInterval // Workers not seen for longer than this are not shared.
+	peerExchangeConnectTimeout = 10 * time.Second
+	peerExchangeMaxDials       = 5                // Maximum number of peers we connect to from a single response.
+	peerExchangeResponseWait   = 30 * time.Second // How long do we accept a response to our peer exchange request.
+)
+
+// Roll call related parameters.
+const (
+	// Weight of the latest roll call round-trip time in the measured peer latency.
+	rollCallLatencyWeight = 0.3
+	// Peers with this roll call latency have their score halved when choosing peers for execution.
+	rollCallLatencyScale = 100 * time.Millisecond
+	// Score of peers is lowered by up to this share, depending on the load they reported in the roll call response.
+	rollCallLoadImpact = 0.5
+	// Warm worker pools expire after this many refresh intervals without a successful refresh.
+	warmPoolExpiryFactor = 2
+	// Worker sets bound to an affinity token expire after this long without an execution using them.
+	affinityExpiry = 10 * time.Minute
 )
 
 // Raft and consensus related parameters.
@@ -38,6 +109,10 @@ const (
 	consensusClusterDisbandTimeout = 5 * time.Minute
 	// Timeout for the context used for sending disband request to cluster nodes.
 	consensusClusterSendTimeout = 10 * time.Second
+	// PBFT view change timeout of a cluster is extended by this many roll call round-trip times of its slowest replica.
+	pbftViewChangeLatencyFactor = 20
+	// How often does the worker check for clusters that made no progress.
+	clusterWatchdogInterval = time.Minute
 )
 
 var (
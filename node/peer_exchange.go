@@ -0,0 +1,292 @@
+package node
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s-attributes/attributes"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+// knownPeer describes a peer we received a health ping from.
+type knownPeer struct {
+	role       blockless.NodeRole
+	attributes *attributes.Attestation
+	// noConsensus is set for workers built without consensus algorithms.
+	noConsensus bool
+	lastSeen    time.Time
+	// load is the latest load the worker reported in its health ping.
+	load *response.Load
+}
+
+// peerDirectory keeps track of healthy peers, so they can be shared with other nodes.
+type peerDirectory struct {
+	sync.RWMutex
+	peers map[peer.ID]knownPeer
+}
+
+func newPeerDirectory() *peerDirectory {
+	return &peerDirectory{
+		peers: make(map[peer.ID]knownPeer),
+	}
+}
+
+func (d *peerDirectory) update(id peer.ID, role blockless.NodeRole, attributes *attributes.Attestation, noConsensus bool) {
+
+	d.Lock()
+	defer d.Unlock()
+
+	d.peers[id] = knownPeer{
+		role:        role,
+		attributes:  attributes,
+		noConsensus: noConsensus,
+		lastSeen:    time.Now().UTC(),
+		load:        d.peers[id].load,
+	}
+}
+
+// recordLoad sets the latest load reported by the peer.
+func (d *peerDirectory) recordLoad(id peer.ID, load *response.Load) {
+
+	d.Lock()
+	defer d.Unlock()
+
+	p, ok := d.peers[id]
+	if !ok {
+		return
+	}
+
+	p.load = load
+	d.peers[id] = p
+}
+
+// supportsConsensus returns false for peers that advertised they were built without consensus algorithms.
+// Peers we did not hear from are assumed to support consensus.
+func (d *peerDirectory) supportsConsensus(id peer.ID) bool {
+
+	d.RLock()
+	defer d.RUnlock()
+
+	return !d.peers[id].noConsensus
+}
+
+// get returns the peer with the given ID, if known.
+func (d *peerDirectory) get(id peer.ID) (knownPeer, bool) {
+
+	d.RLock()
+	defer d.RUnlock()
+
+	p, ok := d.peers[id]
+	return p, ok
+}
+
+// all returns all known peers.
+func (d *peerDirectory) all() map[peer.ID]knownPeer {
+
+	d.RLock()
+	defer d.RUnlock()
+
+	peers := make(map[peer.ID]knownPeer, len(d.peers))
+	for id, p := range d.peers {
+		peers[id] = p
+	}
+
+	return peers
+}
+
+// workers returns the workers seen since the given time.
+func (d *peerDirectory) workers(since time.Time) map[peer.ID]knownPeer {
+
+	d.RLock()
+	defer d.RUnlock()
+
+	workers := make(map[peer.ID]knownPeer)
+	for id, p := range d.peers {
+		if p.role != blockless.WorkerNode || p.lastSeen.Before(since) {
+			continue
+		}
+
+		workers[id] = p
+	}
+
+	return workers
+}
+
+// peerExchangeRequests keeps track of peers we asked for known workers. Only their responses are accepted, once.
+type peerExchangeRequests struct {
+	sync.Mutex
+	sent map[peer.ID]time.Time
+}
+
+func newPeerExchangeRequests() *peerExchangeRequests {
+	return &peerExchangeRequests{
+		sent: make(map[peer.ID]time.Time),
+	}
+}
+
+// add records that the peers were asked for known workers.
+func (r *peerExchangeRequests) add(ids ...peer.ID) {
+
+	r.Lock()
+	defer r.Unlock()
+
+	now := time.Now()
+	for id, sent := range r.sent {
+		if now.Sub(sent) > peerExchangeResponseWait {
+			delete(r.sent, id)
+		}
+	}
+
+	for _, id := range ids {
+		r.sent[id] = now
+	}
+}
+
+// take returns true if we are waiting for a response from the peer, and stops waiting for it.
+func (r *peerExchangeRequests) take(id peer.ID) bool {
+
+	r.Lock()
+	defer r.Unlock()
+
+	sent, ok := r.sent[id]
+	if !ok {
+		return false
+	}
+
+	delete(r.sent, id)
+
+	return time.Since(sent) <= peerExchangeResponseWait
+}
+
+// processPeerExchange responds with a random sample of healthy workers we know of.
+func (n *Node) processPeerExchange(ctx context.Context, from peer.ID, req request.PeerExchange) error {
+
+	limit := req.Limit
+	if limit == 0 || limit > peerExchangeMaxPeers {
+		limit = peerExchangeMaxPeers
+	}
+
+	workers := n.peers.workers(time.Now().Add(-peerExchangeMaxAge))
+
+	peers := make([]response.ExchangedPeer, 0, len(workers))
+	for id, worker := range workers {
+
+		if id == from {
+			continue
+		}
+
+		addrs := n.host.Peerstore().Addrs(id)
+		if len(addrs) == 0 {
+			continue
+		}
+
+		ep := response.ExchangedPeer{
+			AddrInfo: peer.AddrInfo{
+				ID:    id,
+				Addrs: addrs,
+			},
+			Role:       worker.role,
+			Attributes: worker.attributes,
+			LastSeen:   worker.lastSeen,
+		}
+
+		peers = append(peers, ep)
+	}
+
+	n.rand.Shuffle(len(peers), func(i, j int) {
+		peers[i], peers[j] = peers[j], peers[i]
+	})
+
+	if uint(len(peers)) > limit {
+		peers = peers[:limit]
+	}
+
+	n.log.Debug().Stringer("peer", from).Int("count", len(peers)).Msg("sharing known workers with peer")
+
+	return n.send(ctx, from, req.Response(peers))
+}
+
+// processPeerExchangeResponse records the received workers and connects to those we're not connected to yet.
+// Only responses to our own requests are accepted, and we connect to at most `peerExchangeMaxDials` peers from each.
+func (n *Node) processPeerExchangeResponse(ctx context.Context, from peer.ID, res response.PeerExchange) error {
+
+	if !n.exchanges.take(from) {
+		n.log.Debug().Stringer("peer", from).Msg("dropping unsolicited peer exchange response")
+		return nil
+	}
+
+	n.log.Debug().Stringer("peer", from).Int("count", len(res.Peers)).Msg("received known workers from peer")
+
+	dials := 0
+	for _, ep := range res.Peers {
+
+		if dials >= peerExchangeMaxDials {
+			break
+		}
+
+		if ep.AddrInfo.ID == n.host.ID() || len(ep.AddrInfo.Addrs) == 0 {
+			continue
+		}
+
+		if n.host.Network().Connectedness(ep.AddrInfo.ID) == network.Connected {
+			continue
+		}
+
+		n.host.Peerstore().AddAddrs(ep.AddrInfo.ID, ep.AddrInfo.Addrs, PeerExchangeAddressTTL)
+
+		dials++
+		go func(info peer.AddrInfo) {
+
+			ctx, cancel := context.WithTimeout(context.Background(), peerExchangeConnectTimeout)
+			defer cancel()
+
+			err := n.host.Connect(ctx, info)
+			if err != nil {
+				n.log.Debug().Err(err).Stringer("peer", info.ID).Msg("could not connect to exchanged peer")
+				return
+			}
+
+			n.log.Info().Stringer("peer", info.ID).Msg("connected to peer learned via peer exchange")
+		}(ep.AddrInfo)
+	}
+
+	return nil
+}
+
+// exchangePeers asks a sample of connected peers for healthy workers they know of.
+func (n *Node) exchangePeers(ctx context.Context) {
+
+	connected := n.host.Network().Peers()
+
+	n.rand.Shuffle(len(connected), func(i, j int) {
+		connected[i], connected[j] = connected[j], connected[i]
+	})
+
+	if len(connected) > peerExchangeFanout {
+		connected = connected[:peerExchangeFanout]
+	}
+
+	if len(connected) == 0 {
+		return
+	}
+
+	req := request.PeerExchange{
+		Limit: peerExchangeMaxPeers,
+	}
+
+	n.exchanges.add(connected...)
+
+	err := n.sendToMany(ctx, connected, &req, false)
+	if err != nil {
+		n.log.Warn().Err(err).Msg("could not send peer exchange request")
+		return
+	}
+
+	n.log.Info().Strs("peers", blockless.PeerIDsToStr(connected)).Msg("requested known workers from peers")
+}
@@ -0,0 +1,184 @@
+package node
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/testing/helpers"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_PeerExchange(t *testing.T) {
+
+	t.Run("healthy workers are shared", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.WorkerNode)
+
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, receiver)
+
+		var (
+			workers = mocks.GenericPeerIDs[:2]
+			head    = mocks.GenericPeerIDs[2]
+			stale   = mocks.GenericPeerIDs[3]
+		)
+
+		addr, err := multiaddr.NewMultiaddr("/ip4/127.0.0.1/tcp/9000")
+		require.NoError(t, err)
+
+		for _, id := range mocks.GenericPeerIDs[:4] {
+			node.host.Peerstore().AddAddrs(id, []multiaddr.Multiaddr{addr}, peerstore.PermanentAddrTTL)
+		}
+
+		for _, id := range workers {
+			node.peers.update(id, blockless.WorkerNode, nil, false)
+		}
+		node.peers.update(head, blockless.HeadNode, nil, false)
+		node.peers.peers[stale] = knownPeer{role: blockless.WorkerNode, lastSeen: time.Now().Add(-2 * peerExchangeMaxAge)}
+
+		// Requesting peer is not shared with itself.
+		node.peers.update(receiver.ID(), blockless.WorkerNode, nil, false)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer wg.Done()
+			defer stream.Close()
+
+			var received response.PeerExchange
+			getStreamPayload(t, stream, &received)
+
+			require.Len(t, received.Peers, len(workers))
+
+			shared := make([]peer.ID, 0, len(received.Peers))
+			for _, ep := range received.Peers {
+				require.Equal(t, blockless.WorkerNode, ep.Role)
+				require.NotEmpty(t, ep.AddrInfo.Addrs)
+				shared = append(shared, ep.AddrInfo.ID)
+			}
+
+			require.ElementsMatch(t, workers, shared)
+		})
+
+		err = node.processPeerExchange(context.Background(), receiver.ID(), request.PeerExchange{})
+		require.NoError(t, err)
+
+		wg.Wait()
+	})
+	t.Run("node connects to exchanged peers", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+
+		worker, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		res := response.PeerExchange{
+			Peers: []response.ExchangedPeer{
+				{
+					AddrInfo: *hostGetAddrInfo(t, worker),
+					Role:     blockless.WorkerNode,
+				},
+			},
+		}
+
+		node.exchanges.add(mocks.GenericPeerID)
+
+		err = node.processPeerExchangeResponse(context.Background(), mocks.GenericPeerID, res)
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			return node.haveConnection(worker.ID())
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+	t.Run("unsolicited responses are dropped", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+
+		var (
+			id  = helpers.RandPeerID(t)
+			res = response.PeerExchange{
+				Peers: []response.ExchangedPeer{
+					{
+						AddrInfo: peer.AddrInfo{ID: id, Addrs: helpers.GenerateTestAddrs(t, 1)},
+						Role:     blockless.WorkerNode,
+					},
+				},
+			}
+		)
+
+		err := node.processPeerExchangeResponse(context.Background(), mocks.GenericPeerID, res)
+		require.NoError(t, err)
+		require.Empty(t, node.host.Peerstore().Addrs(id))
+
+		// Each request is answered once.
+		node.exchanges.add(mocks.GenericPeerID)
+		require.True(t, node.exchanges.take(mocks.GenericPeerID))
+		require.False(t, node.exchanges.take(mocks.GenericPeerID))
+	})
+	t.Run("number of dialed peers is capped", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+
+		var (
+			count = 3 * peerExchangeMaxDials
+			res   response.PeerExchange
+		)
+		for i := 0; i < count; i++ {
+			ep := response.ExchangedPeer{
+				AddrInfo: peer.AddrInfo{ID: helpers.RandPeerID(t), Addrs: helpers.GenerateTestAddrs(t, 1)},
+				Role:     blockless.WorkerNode,
+			}
+			res.Peers = append(res.Peers, ep)
+		}
+
+		node.exchanges.add(mocks.GenericPeerID)
+
+		err := node.processPeerExchangeResponse(context.Background(), mocks.GenericPeerID, res)
+		require.NoError(t, err)
+
+		var learned int
+		for _, ep := range res.Peers {
+			if len(node.host.Peerstore().Addrs(ep.AddrInfo.ID)) > 0 {
+				learned++
+			}
+		}
+		require.Equal(t, peerExchangeMaxDials, learned)
+	})
+}
+
+func TestNode_PeerDirectorySupportsConsensus(t *testing.T) {
+
+	var (
+		worker   = mocks.GenericPeerIDs[0]
+		edge     = mocks.GenericPeerIDs[1]
+		unknown  = mocks.GenericPeerIDs[2]
+		registry = newPeerDirectory()
+	)
+
+	registry.update(worker, blockless.WorkerNode, nil, false)
+	registry.update(edge, blockless.WorkerNode, nil, true)
+
+	require.True(t, registry.supportsConsensus(worker))
+	require.False(t, registry.supportsConsensus(edge))
+	// Peers we did not hear from are not excluded.
+	require.True(t, registry.supportsConsensus(unknown))
+}
@@ -0,0 +1,59 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+// PeerStates returns the state of peers the node received health pings from, along with the load workers reported.
+func (n *Node) PeerStates() []response.PeerState {
+
+	known := n.peers.all()
+
+	states := make([]response.PeerState, 0, len(known))
+	for id, p := range known {
+		states = append(states, response.PeerState{
+			ID:       id,
+			Role:     p.role,
+			LastSeen: p.lastSeen,
+			Load:     p.load,
+		})
+	}
+
+	slices.SortFunc(states, func(a, b response.PeerState) int {
+		return strings.Compare(a.ID.String(), b.ID.String())
+	})
+
+	return states
+}
+
+func (n *Node) processPeerStates(ctx context.Context, from peer.ID, req request.PeerStates) error {
+
+	if !slices.Contains(n.cfg.AdminPeers, from) {
+		n.log.Warn().Stringer("peer", from).Msg("rejecting peer state request from peer that is not an admin")
+
+		err := n.send(ctx, from, req.Response(codes.NotPermitted, nil))
+		if err != nil {
+			return fmt.Errorf("could not send response: %w", err)
+		}
+
+		return nil
+	}
+
+	n.log.Debug().Stringer("peer", from).Msg("processing peer state request")
+
+	err := n.send(ctx, from, req.Response(codes.OK, n.PeerStates()))
+	if err != nil {
+		return fmt.Errorf("could not send response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package node
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_PeerStates(t *testing.T) {
+
+	var (
+		worker = mocks.GenericPeerIDs[0]
+		load   = response.Load{Executions: 4, Queued: 2, Concurrency: 8, MemoryPressure: 0.5}
+	)
+
+	// Send the request from a separate host and return the response the head node sent back.
+	process := func(t *testing.T, node *Node, admin bool) response.PeerStates {
+		t.Helper()
+
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, receiver)
+
+		if admin {
+			node.cfg.AdminPeers = []peer.ID{receiver.ID()}
+		}
+
+		var (
+			wg       sync.WaitGroup
+			received response.PeerStates
+		)
+		wg.Add(1)
+
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer wg.Done()
+			defer stream.Close()
+
+			getStreamPayload(t, stream, &received)
+		})
+
+		err = node.processPeerStates(context.Background(), receiver.ID(), request.PeerStates{})
+		require.NoError(t, err)
+
+		wg.Wait()
+
+		return received
+	}
+
+	t.Run("load from health pings is recorded", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+
+		err := node.processHealthCheck(context.Background(), worker, response.Health{Role: blockless.WorkerNode, Load: &load})
+		require.NoError(t, err)
+
+		// Load is used for choosing workers.
+		recorded, ok := node.loads.get(worker)
+		require.True(t, ok)
+		require.Equal(t, load, recorded)
+
+		// Later health pings without load do not erase it.
+		err = node.processHealthCheck(context.Background(), worker, response.Health{Role: blockless.WorkerNode})
+		require.NoError(t, err)
+
+		states := node.PeerStates()
+		require.Len(t, states, 1)
+		require.Equal(t, worker, states[0].ID)
+		require.Equal(t, blockless.WorkerNode, states[0].Role)
+		require.Equal(t, &load, states[0].Load)
+	})
+	t.Run("admin can list peer states", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+		node.peers.update(worker, blockless.WorkerNode, nil, false)
+		node.peers.recordLoad(worker, &load)
+
+		res := process(t, node, true)
+		require.Equal(t, codes.OK, res.Code)
+		require.Len(t, res.Peers, 1)
+		require.Equal(t, worker, res.Peers[0].ID)
+		require.Equal(t, &load, res.Peers[0].Load)
+	})
+	t.Run("only admins can list peer states", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+		node.peers.update(worker, blockless.WorkerNode, nil, false)
+
+		res := process(t, node, false)
+		require.Equal(t, codes.NotPermitted, res.Code)
+		require.Empty(t, res.Peers)
+	})
+}
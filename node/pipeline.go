@@ -13,7 +13,8 @@ func messageAllowedOnPipeline(msg string, pipeline pp.Pipeline) bool {
 		// Messages we don't expect as direct messages.
 		case
 			blockless.MessageHealthCheck,
-			blockless.MessageRollCall:
+			blockless.MessageRollCall,
+			blockless.MessageFleetReport:
 
 			// Technically we only publish InstallFunction. However, it's handy for tests to support
 			// direct install, and it's somewhat of a low risk.
@@ -31,10 +32,52 @@ func messageAllowedOnPipeline(msg string, pipeline pp.Pipeline) bool {
 		blockless.MessageInstallFunctionResponse,
 		blockless.MessageExecute,
 		blockless.MessageExecuteResponse,
+		blockless.MessageExecutePartialResponse,
 		blockless.MessageFormCluster,
 		blockless.MessageFormClusterResponse,
 		blockless.MessageDisbandCluster,
-		blockless.MessageRollCallResponse:
+		blockless.MessageDisbandClusterResponse,
+		blockless.MessageUpdateCluster,
+		blockless.MessageUpdateClusterResponse,
+		blockless.MessageTransferLeadership,
+		blockless.MessageTransferLeadershipResponse,
+		blockless.MessageMisbehavior,
+		blockless.MessageRollCallResponse,
+		blockless.MessagePeerExchange,
+		blockless.MessagePeerExchangeResponse,
+		blockless.MessageScheduleExecution,
+		blockless.MessageScheduleExecutionResponse,
+		blockless.MessageUsageQuery,
+		blockless.MessageUsageQueryResponse,
+		blockless.MessageQuotaQuery,
+		blockless.MessageQuotaQueryResponse,
+		blockless.MessageRecoveryReport,
+		blockless.MessageRecoveryReportResponse,
+		blockless.MessageSwapExecutor,
+		blockless.MessageSwapExecutorResponse,
+		blockless.MessageDrain,
+		blockless.MessageDrainResponse,
+		blockless.MessageSelfTest,
+		blockless.MessageSelfTestResponse,
+		blockless.MessageSubgroups,
+		blockless.MessageSubgroupsResponse,
+		blockless.MessageAbortExecution,
+		blockless.MessageExecutionResult,
+		blockless.MessageExecutionResultResponse,
+		blockless.MessageTopology,
+		blockless.MessageTopologyResponse,
+		blockless.MessageCancelExecution,
+		blockless.MessageCancelExecutionResponse,
+		blockless.MessageStoreMaintenance,
+		blockless.MessageStoreMaintenanceResponse,
+		blockless.MessageHealthQuery,
+		blockless.MessageHealthQueryResponse,
+		blockless.MessageFleetPlan,
+		blockless.MessageFleetPlanResponse,
+		blockless.MessagePeerStates,
+		blockless.MessagePeerStatesResponse,
+		blockless.MessageDelegateExecution,
+		blockless.MessageDelegateExecutionResponse:
 
 		return false
 
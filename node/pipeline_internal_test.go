@@ -27,6 +27,37 @@ func TestNode_DisallowedMessages(t *testing.T) {
 		{pubsub, blockless.MessageFormCluster},
 		{pubsub, blockless.MessageFormClusterResponse},
 		{pubsub, blockless.MessageDisbandCluster},
+		{pubsub, blockless.MessagePeerExchange},
+		{pubsub, blockless.MessagePeerExchangeResponse},
+		{pubsub, blockless.MessageScheduleExecution},
+		{pubsub, blockless.MessageScheduleExecutionResponse},
+		{pubsub, blockless.MessageUsageQuery},
+		{pubsub, blockless.MessageUsageQueryResponse},
+		{pubsub, blockless.MessageQuotaQuery},
+		{pubsub, blockless.MessageQuotaQueryResponse},
+		{pubsub, blockless.MessageRecoveryReport},
+		{pubsub, blockless.MessageRecoveryReportResponse},
+		{pubsub, blockless.MessageSwapExecutor},
+		{pubsub, blockless.MessageSwapExecutorResponse},
+		{pubsub, blockless.MessageTopology},
+		{pubsub, blockless.MessageTopologyResponse},
+		{pubsub, blockless.MessageCancelExecution},
+		{pubsub, blockless.MessageCancelExecutionResponse},
+		{pubsub, blockless.MessageStoreMaintenance},
+		{pubsub, blockless.MessageStoreMaintenanceResponse},
+		{pubsub, blockless.MessagePeerStates},
+		{pubsub, blockless.MessagePeerStatesResponse},
+		{pubsub, blockless.MessageDelegateExecution},
+		{pubsub, blockless.MessageDelegateExecutionResponse},
+		{pubsub, blockless.MessageDrain},
+		{pubsub, blockless.MessageDrainResponse},
+		{pubsub, blockless.MessageSelfTest},
+		{pubsub, blockless.MessageSelfTestResponse},
+		{pubsub, blockless.MessageSubgroups},
+		{pubsub, blockless.MessageSubgroupsResponse},
+		{pubsub, blockless.MessageAbortExecution},
+		{pubsub, blockless.MessageExecutionResult},
+		{pubsub, blockless.MessageExecutionResultResponse},
 		// Messages disallowed for direct sending.
 		{direct, blockless.MessageHealthCheck},
 		{direct, blockless.MessageRollCall},
@@ -0,0 +1,99 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+// functionInstall is an installation of a function started when the worker answered a roll call.
+type functionInstall struct {
+	started time.Time
+	done    chan struct{}
+	err     error
+}
+
+// functionPrefetches tracks function installations in progress, so the execution request can wait for them.
+type functionPrefetches struct {
+	sync.Mutex
+	installs map[string]*functionInstall
+}
+
+func newFunctionPrefetches() *functionPrefetches {
+	return &functionPrefetches{
+		installs: make(map[string]*functionInstall),
+	}
+}
+
+// prefetchFunction starts installing the function in the background, unless an installation is already in progress.
+// It returns the progress of the installation, to be reported in the roll call response.
+func (n *Node) prefetchFunction(ctx context.Context, functionID string) response.InstallProgress {
+
+	n.prefetches.Lock()
+	defer n.prefetches.Unlock()
+
+	install, ok := n.prefetches.installs[functionID]
+	if ok {
+		return response.InstallProgress{
+			State:   response.InstallStateInstalling,
+			Elapsed: execute.Since(install.started),
+		}
+	}
+
+	install = &functionInstall{
+		started: time.Now(),
+		done:    make(chan struct{}),
+	}
+	n.prefetches.installs[functionID] = install
+
+	n.metrics.IncrCounter(functionPrefetchesMetric, 1)
+
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+
+		log := n.log.With().Str("function", functionID).Logger()
+
+		err := n.installFunction(ctx, functionID, manifestURLFromCID(functionID))
+		if err != nil {
+			log.Error().Err(err).Msg("could not install function after roll call")
+			n.metrics.IncrCounter(functionPrefetchFailedMetric, 1)
+		} else {
+			log.Info().Dur("duration", time.Since(install.started)).Msg("function installed after roll call")
+		}
+
+		n.prefetches.Lock()
+		delete(n.prefetches.installs, functionID)
+		n.prefetches.Unlock()
+
+		install.err = err
+		close(install.done)
+	}()
+
+	return response.InstallProgress{
+		State: response.InstallStateInstalling,
+	}
+}
+
+// awaitPrefetch waits for the installation of the function started on roll call, if there is one in progress.
+func (n *Node) awaitPrefetch(ctx context.Context, functionID string) error {
+
+	n.prefetches.Lock()
+	install, ok := n.prefetches.installs[functionID]
+	n.prefetches.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("function installation still in progress: %w", ctx.Err())
+	case <-install.done:
+		return install.err
+	}
+}
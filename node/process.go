@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/armon/go-metrics"
 	"github.com/libp2p/go-libp2p/core/peer"
@@ -36,6 +37,11 @@ func (n *Node) processMessage(ctx context.Context, from peer.ID, payload []byte,
 	}
 
 	n.metrics.IncrCounterWithLabels(messagesProcessedMetric, 1, []metrics.Label{{Name: "type", Value: msgType}})
+	defer n.metrics.MeasureSinceWithLabels(messagesProcessingTimeMetric, time.Now(), []metrics.Label{{Name: "type", Value: msgType}})
+
+	done := n.watchSlowHandler(from, msgType)
+	defer done()
+
 	defer func() {
 		switch procError {
 		case nil:
@@ -72,6 +78,10 @@ func (n *Node) processMessage(ctx context.Context, from peer.ID, payload []byte,
 	switch msgType {
 	case blockless.MessageHealthCheck:
 		return handleMessage(ctx, from, payload, n.processHealthCheck)
+	case blockless.MessageHealthQuery:
+		return handleMessage(ctx, from, payload, n.processHealthQuery)
+	case blockless.MessageHealthQueryResponse:
+		return handleMessage(ctx, from, payload, n.processHealthQueryResponse)
 
 	case blockless.MessageInstallFunction:
 		return handleMessage(ctx, from, payload, n.processInstallFunction)
@@ -94,6 +104,69 @@ func (n *Node) processMessage(ctx context.Context, from peer.ID, payload []byte,
 		return handleMessage(ctx, from, payload, n.processFormClusterResponse)
 	case blockless.MessageDisbandCluster:
 		return handleMessage(ctx, from, payload, n.processDisbandCluster)
+	case blockless.MessageDisbandClusterResponse:
+		return handleMessage(ctx, from, payload, n.processDisbandClusterResponse)
+	case blockless.MessageUpdateCluster:
+		return handleMessage(ctx, from, payload, n.processUpdateCluster)
+	case blockless.MessageUpdateClusterResponse:
+		return handleMessage(ctx, from, payload, n.processUpdateClusterResponse)
+	case blockless.MessageTransferLeadership:
+		return handleMessage(ctx, from, payload, n.processTransferLeadership)
+	case blockless.MessageTransferLeadershipResponse:
+		return handleMessage(ctx, from, payload, n.processTransferLeadershipResponse)
+	case blockless.MessageMisbehavior:
+		return handleMessage(ctx, from, payload, n.processMisbehavior)
+
+	case blockless.MessagePeerExchange:
+		return handleMessage(ctx, from, payload, n.processPeerExchange)
+	case blockless.MessagePeerExchangeResponse:
+		return handleMessage(ctx, from, payload, n.processPeerExchangeResponse)
+
+	case blockless.MessageScheduleExecution:
+		return handleMessage(ctx, from, payload, n.processScheduleExecution)
+
+	case blockless.MessageUsageQuery:
+		return handleMessage(ctx, from, payload, n.processUsageQuery)
+
+	case blockless.MessageQuotaQuery:
+		return handleMessage(ctx, from, payload, n.processQuotaQuery)
+
+	case blockless.MessageRecoveryReport:
+		return handleMessage(ctx, from, payload, n.processRecoveryReport)
+
+	case blockless.MessageTopology:
+		return handleMessage(ctx, from, payload, n.processTopology)
+
+	case blockless.MessageSwapExecutor:
+		return handleMessage(ctx, from, payload, n.processSwapExecutor)
+	case blockless.MessageDrain:
+		return handleMessage(ctx, from, payload, n.processDrain)
+	case blockless.MessageSelfTest:
+		return handleMessage(ctx, from, payload, n.processSelfTest)
+	case blockless.MessageSubgroups:
+		return handleMessage(ctx, from, payload, n.processSubgroups)
+	case blockless.MessageAbortExecution:
+		return handleMessage(ctx, from, payload, n.processAbortExecution)
+
+	case blockless.MessageCancelExecution:
+		return handleMessage(ctx, from, payload, n.processCancelExecution)
+	case blockless.MessageExecutionResult:
+		return handleMessage(ctx, from, payload, n.processExecutionResult)
+
+	case blockless.MessageStoreMaintenance:
+		return handleMessage(ctx, from, payload, n.processStoreMaintenance)
+
+	case blockless.MessageFleetReport:
+		return handleMessage(ctx, from, payload, n.processFleetReport)
+	case blockless.MessageFleetPlan:
+		return handleMessage(ctx, from, payload, n.processFleetPlan)
+	case blockless.MessagePeerStates:
+		return handleMessage(ctx, from, payload, n.processPeerStates)
+
+	case blockless.MessageDelegateExecution:
+		return handleMessage(ctx, from, payload, n.processDelegateExecution)
+	case blockless.MessageDelegateExecutionResponse:
+		return handleMessage(ctx, from, payload, n.processDelegateExecutionResponse)
 
 	default:
 		return fmt.Errorf("unknown message type: %s", msgType)
@@ -106,11 +179,20 @@ func (n *Node) messageAllowedForRole(msgType string) bool {
 	if n.isWorker() {
 		switch msgType {
 		case blockless.MessageHealthCheck,
+			blockless.MessageHealthQuery,
 			blockless.MessageInstallFunction,
 			blockless.MessageRollCall,
 			blockless.MessageExecute,
 			blockless.MessageFormCluster,
-			blockless.MessageDisbandCluster:
+			blockless.MessageDisbandCluster,
+			blockless.MessageUpdateCluster,
+			blockless.MessageTransferLeadership,
+			blockless.MessagePeerExchange,
+			blockless.MessageSwapExecutor,
+			blockless.MessageDrain,
+			blockless.MessageSelfTest,
+			blockless.MessageSubgroups,
+			blockless.MessageAbortExecution:
 			return true
 
 		default:
@@ -122,11 +204,32 @@ func (n *Node) messageAllowedForRole(msgType string) bool {
 	switch msgType {
 
 	case blockless.MessageHealthCheck,
+		blockless.MessageHealthQueryResponse,
 		blockless.MessageInstallFunctionResponse,
 		blockless.MessageRollCallResponse,
 		blockless.MessageExecute,
 		blockless.MessageExecuteResponse,
-		blockless.MessageFormClusterResponse:
+		blockless.MessageFormClusterResponse,
+		blockless.MessageUpdateClusterResponse,
+		blockless.MessageDisbandClusterResponse,
+		blockless.MessageTransferLeadership,
+		blockless.MessageTransferLeadershipResponse,
+		blockless.MessageMisbehavior,
+		blockless.MessagePeerExchange,
+		blockless.MessagePeerExchangeResponse,
+		blockless.MessageScheduleExecution,
+		blockless.MessageUsageQuery,
+		blockless.MessageQuotaQuery,
+		blockless.MessageRecoveryReport,
+		blockless.MessageTopology,
+		blockless.MessageCancelExecution,
+		blockless.MessageExecutionResult,
+		blockless.MessageStoreMaintenance,
+		blockless.MessageFleetReport,
+		blockless.MessageFleetPlan,
+		blockless.MessagePeerStates,
+		blockless.MessageDelegateExecution,
+		blockless.MessageDelegateExecutionResponse:
 
 		// NOTE: We provide a mechanism via the REST API to broadcast function install, so there's a case for this being supported.
 		return true
@@ -1,39 +1,99 @@
 package node
 
 import (
+	"fmt"
 	"sync"
+	"time"
 
+	"github.com/armon/go-metrics"
 	"github.com/libp2p/go-libp2p/core/peer"
 
 	"github.com/blocklessnetwork/b7s/models/response"
 )
 
+var _ RollCallStore = (*rollCallQueue)(nil)
+
+// RollCallOverflow describes what happens to roll call responses arriving once the queue for the roll call is full.
+type RollCallOverflow string
+
+const (
+	// RollCallDropOldest drops the oldest queued response to make room for the new one.
+	RollCallDropOldest RollCallOverflow = "drop-oldest"
+	// RollCallRejectNew drops the new response.
+	RollCallRejectNew RollCallOverflow = "reject-new"
+	// RollCallBlock waits for room in the queue, and drops the new response if there is none before the timeout.
+	RollCallBlock RollCallOverflow = "block"
+)
+
+// Valid returns true if the overflow policy is known.
+func (o RollCallOverflow) Valid() bool {
+	switch o {
+	case RollCallDropOldest, RollCallRejectNew, RollCallBlock:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseRollCallOverflow parses the name of the roll call queue overflow policy.
+func ParseRollCallOverflow(name string) (RollCallOverflow, error) {
+
+	policy := RollCallOverflow(name)
+	if !policy.Valid() {
+		return "", fmt.Errorf("unknown roll call overflow policy: %s", name)
+	}
+
+	return policy, nil
+}
+
 type rollCallQueue struct {
 	sync.Mutex
 
-	size uint
-	m    map[string]chan rollCallResponse
+	size     uint
+	overflow RollCallOverflow
+	timeout  time.Duration
+
+	m map[string]*responseQueue
+}
+
+// responseQueue holds the responses to a single roll call.
+type responseQueue struct {
+	responses chan RollCallResponse
+	// removed is closed once the roll call is removed, releasing producers waiting for room in the queue.
+	removed chan struct{}
+}
+
+func newResponseQueue(size uint) *responseQueue {
+	return &responseQueue{
+		responses: make(chan RollCallResponse, size),
+		removed:   make(chan struct{}),
+	}
 }
 
-type rollCallResponse struct {
+// RollCallResponse is a response to a roll call, along with the peer who sent it.
+type RollCallResponse struct {
 	From peer.ID
 	response.RollCall
 }
 
-// newQueue is used to record per-request roll call responses.
-func newQueue(bufSize uint) *rollCallQueue {
+// newQueue creates the default, in-memory, store for per-request roll call responses.
+// Overflow policy determines what happens to responses once the queue for a roll call is full.
+// Timeout is only used with the blocking overflow policy.
+func newQueue(bufSize uint, overflow RollCallOverflow, timeout time.Duration) *rollCallQueue {
 
 	q := rollCallQueue{
-		size: bufSize,
-		m:    make(map[string]chan rollCallResponse),
+		size:     bufSize,
+		overflow: overflow,
+		timeout:  timeout,
+		m:        make(map[string]*responseQueue),
 	}
 
 	return &q
 }
 
-// create will create a response queue for the given requestID.
+// Create will create a response queue for the given requestID.
 // Needs to be called before receiving/reading roll call responses.
-func (q *rollCallQueue) create(reqID string) {
+func (q *rollCallQueue) Create(reqID string) {
 	q.Lock()
 	defer q.Unlock()
 
@@ -42,24 +102,89 @@ func (q *rollCallQueue) create(reqID string) {
 		return
 	}
 
-	q.m[reqID] = make(chan rollCallResponse, q.size)
+	q.m[reqID] = newResponseQueue(q.size)
 }
 
-// add records a new response to a roll call.
-func (q *rollCallQueue) add(id string, res rollCallResponse) {
+// Add records a new response to a roll call. If the queue for the roll call is full, the overflow policy decides
+// which response is dropped.
+func (q *rollCallQueue) Add(id string, res RollCallResponse) {
 	q.Lock()
-	defer q.Unlock()
 
-	_, ok := q.m[id]
+	rq, ok := q.m[id]
 	if !ok {
+		q.Unlock()
+		return
+	}
+
+	select {
+	case rq.responses <- res:
+		q.recordDepth()
+		q.Unlock()
 		return
+	default:
+	}
+
+	switch q.overflow {
+	case RollCallDropOldest:
+		defer q.Unlock()
+
+		// Consumers may be reading at the same time, so the queue might not be full anymore.
+		for {
+			select {
+			case rq.responses <- res:
+				q.recordDepth()
+				return
+			default:
+			}
+
+			select {
+			case <-rq.responses:
+				q.recordDrop()
+			default:
+			}
+		}
+
+	case RollCallBlock:
+		// Do not hold the lock while waiting, other roll calls should not be held up.
+		q.Unlock()
+
+		timer := time.NewTimer(q.timeout)
+		defer timer.Stop()
+
+		select {
+		case rq.responses <- res:
+			q.Lock()
+			q.recordDepth()
+			q.Unlock()
+		case <-rq.removed:
+		case <-timer.C:
+			q.recordDrop()
+		}
+
+	default:
+		q.Unlock()
+		q.recordDrop()
+	}
+}
+
+// recordDepth reports the number of responses queued for all roll calls.
+// NOTE: Caller should hold the lock.
+func (q *rollCallQueue) recordDepth() {
+
+	var depth int
+	for _, rq := range q.m {
+		depth += len(rq.responses)
 	}
 
-	q.m[id] <- res
+	metrics.SetGauge(rollCallQueueDepthMetric, float32(depth))
 }
 
-// exists returns true if a given request ID exists in the roll call map.
-func (q *rollCallQueue) exists(reqID string) bool {
+func (q *rollCallQueue) recordDrop() {
+	metrics.IncrCounterWithLabels(rollCallQueueDroppedMetric, 1, []metrics.Label{{Name: "policy", Value: string(q.overflow)}})
+}
+
+// Exists returns true if a given request ID exists in the roll call map.
+func (q *rollCallQueue) Exists(reqID string) bool {
 	q.Lock()
 	defer q.Unlock()
 
@@ -67,36 +192,39 @@ func (q *rollCallQueue) exists(reqID string) bool {
 	return ok
 }
 
-// responses will return a channel that can be used to iterate through all of the responses.
-func (q *rollCallQueue) responses(reqID string) <-chan rollCallResponse {
+// Responses will return a channel that can be used to iterate through all of the responses.
+func (q *rollCallQueue) Responses(reqID string) <-chan RollCallResponse {
 	q.Lock()
 	defer q.Unlock()
 
 	_, ok := q.m[reqID]
 	if !ok {
-		// Technically we shouldn't be here since we already called `create`, but there's also no harm in it.
-		q.m[reqID] = make(chan rollCallResponse, q.size)
+		// Technically we shouldn't be here since we already called `Create`, but there's also no harm in it.
+		q.m[reqID] = newResponseQueue(q.size)
 	}
 
-	return q.m[reqID]
+	return q.m[reqID].responses
 }
 
-// Remove will remove the channel with the given ID.
-func (q *rollCallQueue) remove(reqID string) {
+// Remove will remove the queue with the given ID.
+func (q *rollCallQueue) Remove(reqID string) {
 	q.Lock()
 	defer q.Unlock()
 
-	_, ok := q.m[reqID]
+	rq, ok := q.m[reqID]
 	if !ok {
 		// Should not be done but make it safe for double close.
 		return
 	}
 
-	// First drain the channel.
-	for len(q.m[reqID]) > 0 {
-		<-q.m[reqID]
+	// Release producers waiting for room in the queue first, then drain the channel.
+	// The response channel is not closed, since producers waiting for room might still be sending.
+	close(rq.removed)
+	for len(rq.responses) > 0 {
+		<-rq.responses
 	}
 
-	close(q.m[reqID])
 	delete(q.m, reqID)
+
+	q.recordDepth()
 }
@@ -3,6 +3,7 @@ package node
 import (
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -15,7 +16,7 @@ func TestRollCallQueue(t *testing.T) {
 	var (
 		requestID = "dummy-request-id"
 
-		res = rollCallResponse{
+		res = RollCallResponse{
 			From: mocks.GenericPeerID,
 			RollCall: response.RollCall{
 				RequestID:  requestID,
@@ -30,14 +31,14 @@ func TestRollCallQueue(t *testing.T) {
 			count = 20
 		)
 
-		queue := newQueue(100)
+		queue := newQueue(100, RollCallBlock, time.Second)
 
 		// Request does not exist in an empty map.
-		require.False(t, queue.exists(requestID))
+		require.False(t, queue.Exists(requestID))
 
 		// Request exists after creation.
-		queue.create(requestID)
-		require.True(t, queue.exists(requestID))
+		queue.Create(requestID)
+		require.True(t, queue.Exists(requestID))
 
 		var wg sync.WaitGroup
 		wg.Add(count)
@@ -45,13 +46,13 @@ func TestRollCallQueue(t *testing.T) {
 		for i := 0; i < count; i++ {
 			go func() {
 				defer wg.Done()
-				queue.add(requestID, res)
+				queue.Add(requestID, res)
 			}()
 		}
 		wg.Wait()
 
 		// Verify we have all responses recorded.
-		responses := queue.responses(requestID)
+		responses := queue.Responses(requestID)
 		require.Len(t, responses, count)
 
 		for i := 0; i < count; i++ {
@@ -59,8 +60,8 @@ func TestRollCallQueue(t *testing.T) {
 			require.Equal(t, res, r)
 		}
 
-		queue.remove(requestID)
-		require.False(t, queue.exists(requestID))
+		queue.Remove(requestID)
+		require.False(t, queue.Exists(requestID))
 	})
 	t.Run("roll call with pending responses removal works", func(t *testing.T) {
 
@@ -68,14 +69,139 @@ func TestRollCallQueue(t *testing.T) {
 			count = 5
 		)
 
-		queue := newQueue(100)
-		queue.create(requestID)
+		queue := newQueue(100, RollCallBlock, time.Second)
+		queue.Create(requestID)
 
 		for i := 0; i < count; i++ {
-			queue.add(requestID, res)
+			queue.Add(requestID, res)
 		}
 
-		queue.remove(requestID)
-		require.False(t, queue.exists(requestID))
+		queue.Remove(requestID)
+		require.False(t, queue.Exists(requestID))
+	})
+	t.Run("drop oldest overflow policy keeps latest responses", func(t *testing.T) {
+
+		queue := newQueue(2, RollCallDropOldest, 0)
+		queue.Create(requestID)
+
+		for _, id := range mocks.GenericPeerIDs[:3] {
+			queue.Add(requestID, RollCallResponse{From: id, RollCall: res.RollCall})
+		}
+
+		responses := queue.Responses(requestID)
+		require.Len(t, responses, 2)
+
+		require.Equal(t, mocks.GenericPeerIDs[1], (<-responses).From)
+		require.Equal(t, mocks.GenericPeerIDs[2], (<-responses).From)
+	})
+	t.Run("drop oldest overflow policy does not block on a full queue", func(t *testing.T) {
+
+		const (
+			size = 3
+		)
+
+		queue := newQueue(size, RollCallDropOldest, 0)
+		queue.Create(requestID)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+
+			// Keep adding responses well past the queue size, without anyone reading them.
+			for i := 0; i < 10*size; i++ {
+				queue.Add(requestID, res)
+			}
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("adding response to a full queue blocked")
+		}
+
+		require.Len(t, queue.Responses(requestID), size)
+	})
+	t.Run("reject new overflow policy keeps earliest responses", func(t *testing.T) {
+
+		queue := newQueue(2, RollCallRejectNew, 0)
+		queue.Create(requestID)
+
+		for _, id := range mocks.GenericPeerIDs[:3] {
+			queue.Add(requestID, RollCallResponse{From: id, RollCall: res.RollCall})
+		}
+
+		responses := queue.Responses(requestID)
+		require.Len(t, responses, 2)
+
+		require.Equal(t, mocks.GenericPeerIDs[0], (<-responses).From)
+		require.Equal(t, mocks.GenericPeerIDs[1], (<-responses).From)
+	})
+	t.Run("block overflow policy waits for room in the queue", func(t *testing.T) {
+
+		queue := newQueue(1, RollCallBlock, 5*time.Second)
+		queue.Create(requestID)
+
+		queue.Add(requestID, res)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			queue.Add(requestID, res)
+		}()
+
+		// Adding a response for a different roll call is not held up.
+		queue.Create("other-request-id")
+		queue.Add("other-request-id", res)
+		require.Len(t, queue.Responses("other-request-id"), 1)
+
+		<-queue.Responses(requestID)
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("blocked response was not added")
+		}
+
+		require.Len(t, queue.Responses(requestID), 1)
+	})
+	t.Run("block overflow policy drops response after timeout", func(t *testing.T) {
+
+		const (
+			timeout = 50 * time.Millisecond
+		)
+
+		queue := newQueue(1, RollCallBlock, timeout)
+		queue.Create(requestID)
+
+		queue.Add(requestID, res)
+
+		start := time.Now()
+		queue.Add(requestID, res)
+		require.GreaterOrEqual(t, time.Since(start), timeout)
+
+		require.Len(t, queue.Responses(requestID), 1)
+	})
+	t.Run("removal releases blocked responses", func(t *testing.T) {
+
+		queue := newQueue(1, RollCallBlock, time.Minute)
+		queue.Create(requestID)
+
+		queue.Add(requestID, res)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			queue.Add(requestID, res)
+		}()
+
+		// Give the producer a chance to start waiting.
+		time.Sleep(50 * time.Millisecond)
+		queue.Remove(requestID)
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("blocked response was not released")
+		}
 	})
 }
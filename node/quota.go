@@ -0,0 +1,101 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/request"
+)
+
+var errQuotasNotEnabled = errors.New("client quotas are not enabled")
+
+// requesterKey records the peer that sent the execution request. Quotas are kept per requesting peer.
+type requesterKey struct{}
+
+func withRequester(ctx context.Context, from peer.ID) context.Context {
+	return context.WithValue(ctx, requesterKey{}, from)
+}
+
+// requester returns the client the execution counts towards. Requests not sent by a peer, such as those
+// received via the REST API or scheduled ones, share a single quota.
+func requester(ctx context.Context) string {
+	from, ok := ctx.Value(requesterKey{}).(peer.ID)
+	if !ok {
+		return ""
+	}
+
+	return from.String()
+}
+
+// Quota returns the quota the client has left.
+func (n *Node) Quota(client string) (execute.QuotaRemaining, error) {
+
+	if n.cfg.Quotas == nil {
+		return execute.QuotaRemaining{}, errQuotasNotEnabled
+	}
+
+	return n.cfg.Quotas.Remaining(client, time.Now())
+}
+
+func (n *Node) processQuotaQuery(ctx context.Context, from peer.ID, req request.QuotaQuery) error {
+
+	n.log.Debug().Str("peer", from.String()).Msg("processing quota query")
+
+	remaining, err := n.Quota(from.String())
+	if err != nil {
+
+		code := codes.Error
+		if errors.Is(err, errQuotasNotEnabled) {
+			code = codes.NotAvailable
+		}
+
+		err = n.send(ctx, from, req.Response(code, remaining).WithErrorMessage(err))
+		if err != nil {
+			return fmt.Errorf("could not send response: %w", err)
+		}
+		return nil
+	}
+
+	err = n.send(ctx, from, req.Response(codes.OK, remaining))
+	if err != nil {
+		return fmt.Errorf("could not send response: %w", err)
+	}
+
+	return nil
+}
+
+// admitExecution records the execution towards the quota of the requesting client.
+func (n *Node) admitExecution(ctx context.Context) error {
+
+	if n.cfg.Quotas == nil {
+		return nil
+	}
+
+	_, err := n.cfg.Quotas.Admit(requester(ctx), time.Now())
+	return err
+}
+
+// recordQuotaUsage records the CPU time used by the execution towards the quota of the requesting client.
+func (n *Node) recordQuotaUsage(ctx context.Context, results execute.ResultMap) {
+
+	if n.cfg.Quotas == nil || len(results) == 0 {
+		return
+	}
+
+	var cpu time.Duration
+	for _, res := range results {
+		cpu += res.Result.Usage.CPUUserTime + res.Result.Usage.CPUSysTime
+	}
+
+	client := requester(ctx)
+	err := n.cfg.Quotas.RecordCPU(client, time.Now(), cpu)
+	if err != nil {
+		n.log.Warn().Err(err).Str("client", client).Msg("could not record client quota usage")
+	}
+}
@@ -0,0 +1,126 @@
+package node
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/b7serrors"
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/node/head/journal"
+	"github.com/blocklessnetwork/b7s/node/head/quota"
+	"github.com/blocklessnetwork/b7s/testing/helpers"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_Quota(t *testing.T) {
+
+	t.Run("execution over quota is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		db := helpers.InMemoryDB(t)
+		defer db.Close()
+
+		node := createNode(t, blockless.HeadNode)
+		node.cfg.Quotas = quota.NewTracker(db, quota.Limits{ExecutionsPerHour: 1})
+		node.cfg.Journal = journal.New(db)
+
+		client := mocks.GenericPeerIDs[0]
+		ctx := withRequester(context.Background(), client)
+
+		_, err := node.cfg.Quotas.Admit(client.String(), time.Now())
+		require.NoError(t, err)
+
+		requestID := node.newRequestID()
+		node.journalUpdate(requestID, func(e *journal.Entry) {
+			e.Origin = client
+		})
+
+		code, results, _, _, err := node.headExecute(ctx, requestID, mocks.GenericExecutionRequest, "", nil)
+		require.ErrorIs(t, err, b7serrors.ErrQuotaExceeded)
+		require.Equal(t, codes.QuotaExceeded, code)
+		require.Empty(t, results)
+
+		// Rejected request is no longer in the journal.
+		entries, _, err := node.cfg.Journal.Scan()
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+	t.Run("quotas are kept per requesting peer", func(t *testing.T) {
+		t.Parallel()
+
+		db := helpers.InMemoryDB(t)
+		defer db.Close()
+
+		node := createNode(t, blockless.HeadNode)
+		node.cfg.Quotas = quota.NewTracker(db, quota.Limits{ExecutionsPerHour: 1})
+
+		err := node.admitExecution(withRequester(context.Background(), mocks.GenericPeerIDs[0]))
+		require.NoError(t, err)
+
+		err = node.admitExecution(withRequester(context.Background(), mocks.GenericPeerIDs[0]))
+		require.ErrorIs(t, err, b7serrors.ErrQuotaExceeded)
+
+		err = node.admitExecution(withRequester(context.Background(), mocks.GenericPeerIDs[1]))
+		require.NoError(t, err)
+	})
+	t.Run("remaining quota can be queried", func(t *testing.T) {
+		t.Parallel()
+
+		db := helpers.InMemoryDB(t)
+		defer db.Close()
+
+		node := createNode(t, blockless.HeadNode)
+		node.cfg.Quotas = quota.NewTracker(db, quota.Limits{ExecutionsPerHour: 5})
+
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		_, err = node.cfg.Quotas.Admit(receiver.ID().String(), time.Now())
+		require.NoError(t, err)
+
+		// Other clients do not affect the quota of the requesting peer.
+		_, err = node.cfg.Quotas.Admit(mocks.GenericPeerIDs[0].String(), time.Now())
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, receiver)
+
+		var (
+			wg       sync.WaitGroup
+			received response.QuotaQuery
+		)
+		wg.Add(1)
+
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer wg.Done()
+			defer stream.Close()
+
+			getStreamPayload(t, stream, &received)
+		})
+
+		err = node.processQuotaQuery(context.Background(), receiver.ID(), request.QuotaQuery{})
+		require.NoError(t, err)
+
+		wg.Wait()
+
+		require.Equal(t, codes.OK, received.Code)
+		require.Equal(t, receiver.ID().String(), received.Remaining.Client)
+		require.Equal(t, uint(4), *received.Remaining.Executions)
+	})
+	t.Run("quota query without quotas enabled", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+
+		_, err := node.Quota(mocks.GenericPeerIDs[0].String())
+		require.ErrorIs(t, err, errQuotasNotEnabled)
+	})
+}
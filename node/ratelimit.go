@@ -0,0 +1,38 @@
+package node
+
+import (
+	"github.com/armon/go-metrics"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/node/internal/ratelimit"
+)
+
+func newRateLimiter(limits []RateLimit) *ratelimit.Limiter {
+
+	byFunction := make(map[string]ratelimit.Limit, len(limits))
+	for _, limit := range limits {
+		byFunction[limit.FunctionID] = ratelimit.Limit{
+			Rate:    limit.Rate,
+			Burst:   limit.Burst,
+			PerPeer: limit.PerPeer,
+		}
+	}
+
+	return ratelimit.New(byFunction)
+}
+
+// rateLimited returns true if the execution request for the function, coming from the given peer, exceeds the rate limit.
+func (n *Node) rateLimited(functionID string, from peer.ID) bool {
+
+	if n.rateLimiter == nil {
+		return false
+	}
+
+	if n.rateLimiter.Allow(functionID, from.String()) {
+		return false
+	}
+
+	n.metrics.IncrCounterWithLabels(rateLimitedMetric, 1, []metrics.Label{{Name: "function", Value: functionID}})
+
+	return true
+}
@@ -0,0 +1,73 @@
+package node
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/b7serrors"
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_RateLimit(t *testing.T) {
+
+	t.Run("execution requests over the rate limit are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		req := mocks.GenericExecutionRequest
+
+		node := createNode(t, blockless.HeadNode)
+		node.rateLimiter = newRateLimiter([]RateLimit{
+			{FunctionID: req.FunctionID, Rate: 0.001, Burst: 1},
+		})
+
+		// Use up the burst.
+		require.False(t, node.rateLimited(req.FunctionID, mocks.GenericPeerID))
+
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, receiver)
+
+		var (
+			wg       sync.WaitGroup
+			received response.Execute
+		)
+		wg.Add(1)
+
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer wg.Done()
+			defer stream.Close()
+
+			getStreamPayload(t, stream, &received)
+		})
+
+		err = node.headProcessExecute(context.Background(), receiver.ID(), request.Execute{Request: req})
+		require.NoError(t, err)
+
+		wg.Wait()
+
+		require.Equal(t, codes.TooManyRequests, received.Code)
+		require.Equal(t, b7serrors.ErrRateLimited.Error(), received.ErrorMessage)
+	})
+	t.Run("other functions are not limited", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+		node.rateLimiter = newRateLimiter([]RateLimit{
+			{FunctionID: "limited-function", Rate: 0.001, Burst: 1},
+		})
+
+		for i := 0; i < 5; i++ {
+			require.False(t, node.rateLimited("other-function", mocks.GenericPeerID))
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/node/head/recovery"
+)
+
+// recoveryReport collects information about the state the head node found on startup.
+type recoveryReport struct {
+	sync.Mutex
+	report recovery.Report
+}
+
+func (r *recoveryReport) update(fn func(*recovery.Report)) {
+	r.Lock()
+	defer r.Unlock()
+
+	fn(&r.report)
+}
+
+func (r *recoveryReport) get() recovery.Report {
+	r.Lock()
+	defer r.Unlock()
+
+	report := r.report
+	report.Corrupted = slices.Clone(r.report.Corrupted)
+
+	return report
+}
+
+// RecoveryReport returns the report on the state the head node found on startup.
+func (n *Node) RecoveryReport() recovery.Report {
+	return n.recovery.get()
+}
+
+func (n *Node) processRecoveryReport(ctx context.Context, from peer.ID, req request.RecoveryReport) error {
+
+	n.log.Debug().Str("peer", from.String()).Msg("processing recovery report request")
+
+	err := n.send(ctx, from, req.Response(codes.OK, n.RecoveryReport()))
+	if err != nil {
+		return fmt.Errorf("could not send response: %w", err)
+	}
+
+	return nil
+}
+
+// scanResults checks the execution results persisted by the previous run.
+func (n *Node) scanResults() {
+
+	if n.cfg.ResultArchive == nil {
+		return
+	}
+
+	stored, archived, corrupted, err := n.cfg.ResultArchive.Scan()
+	if err != nil {
+		n.log.Error().Err(err).Msg("could not scan execution results")
+		return
+	}
+
+	for _, c := range corrupted {
+		n.log.Warn().Str("request", c.ID).Str("reason", c.Reason).Msg("found corrupted execution results")
+	}
+
+	n.log.Info().Uint("stored", stored).Uint("archived", archived).Int("corrupted", len(corrupted)).Msg("scanned execution results")
+
+	n.recovery.update(func(r *recovery.Report) {
+		r.StoredResults = stored
+		r.ArchivedResults = archived
+		r.Corrupted = append(r.Corrupted, corrupted...)
+	})
+}
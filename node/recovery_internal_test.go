@@ -0,0 +1,77 @@
+package node
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/node/head/journal"
+	"github.com/blocklessnetwork/b7s/node/head/recovery"
+	"github.com/blocklessnetwork/b7s/node/head/schedule"
+	"github.com/blocklessnetwork/b7s/store"
+	"github.com/blocklessnetwork/b7s/testing/helpers"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_RecoveryReport(t *testing.T) {
+
+	db := helpers.InMemoryDB(t)
+	defer db.Close()
+
+	node := createNode(t, blockless.HeadNode)
+	node.cfg.Journal = journal.New(db)
+	node.cfg.Schedules = schedule.NewStore(db)
+
+	// Request left pending by the previous run.
+	node.journalUpdate("dummy-request-id", func(e *journal.Entry) {
+		e.FunctionID = mocks.GenericFunctionRecord.CID
+		e.Phase = journal.PhaseRollCall
+	})
+
+	err := node.cfg.Schedules.Save(schedule.Spec{ID: "dummy-schedule", Cron: "* * * * *", Request: mocks.GenericExecutionRequest})
+	require.NoError(t, err)
+	err = node.cfg.Schedules.Save(schedule.Spec{ID: "invalid-schedule", Cron: "invalid", Request: mocks.GenericExecutionRequest})
+	require.NoError(t, err)
+
+	// Records that cannot be read.
+	err = db.Set(append([]byte{store.PrefixJournal, store.Separator}, []byte("corrupted-request")...), []byte("{"), pebble.Sync)
+	require.NoError(t, err)
+	err = db.Set(append([]byte{store.PrefixSchedule, store.Separator}, []byte("corrupted-schedule")...), []byte("{"), pebble.Sync)
+	require.NoError(t, err)
+
+	node.abortPendingRequests(context.Background())
+	require.NoError(t, node.loadSchedules())
+
+	report := node.RecoveryReport()
+	require.Equal(t, uint(1), report.AbandonedRequests)
+	require.Zero(t, report.NotifiedClients)
+	require.Equal(t, uint(1), report.RecoveredSchedules)
+
+	require.ElementsMatch(t,
+		[]string{
+			recovery.SourceJournal + "/corrupted-request",
+			recovery.SourceSchedules + "/corrupted-schedule",
+			recovery.SourceSchedules + "/invalid-schedule",
+		},
+		corruptedIDs(report.Corrupted),
+	)
+
+	// Corrupted journal entries are cleaned up.
+	pending, corrupted, err := node.cfg.Journal.Scan()
+	require.NoError(t, err)
+	require.Empty(t, pending)
+	require.Empty(t, corrupted)
+}
+
+func corruptedIDs(corrupted []recovery.Corruption) []string {
+
+	ids := make([]string, 0, len(corrupted))
+	for _, c := range corrupted {
+		ids = append(ids, c.Source+"/"+c.ID)
+	}
+
+	return ids
+}
@@ -4,31 +4,107 @@ import (
 	"context"
 	"crypto/sha256"
 	"fmt"
+	"time"
 
+	"github.com/blocklessnetwork/b7s/b7serrors"
 	"github.com/blocklessnetwork/b7s/models/codes"
 	"github.com/blocklessnetwork/b7s/models/execute"
 	"github.com/blocklessnetwork/b7s/models/request"
 )
 
 // ExecuteFunction can be used to start function execution. At the moment this is used by the API server to start execution on the head node.
-func (n *Node) ExecuteFunction(ctx context.Context, req execute.Request, subgroup string) (codes.Code, string, execute.ResultMap, execute.Cluster, error) {
+func (n *Node) ExecuteFunction(ctx context.Context, req execute.Request, subgroup string) (codes.Code, string, execute.ResultMap, execute.Cluster, execute.Timing, error) {
 
 	if !n.isHead() {
-		return codes.NotAvailable, "", nil, execute.Cluster{}, fmt.Errorf("action not supported on this node type")
+		return codes.NotAvailable, "", nil, execute.Cluster{}, execute.Timing{}, fmt.Errorf("action not supported on this node type")
 	}
 
-	requestID := newRequestID()
-	code, results, cluster, err := n.headExecute(ctx, requestID, req, subgroup)
+	if n.rateLimited(req.FunctionID, "") {
+		return codes.TooManyRequests, "", nil, execute.Cluster{}, execute.Timing{}, b7serrors.ErrRateLimited
+	}
+
+	// Deferred executions are acknowledged before they run, which the synchronous API cannot do.
+	if req.Config.NotBefore.After(time.Now()) {
+		return codes.NotSupported, "", nil, execute.Cluster{}, execute.Timing{}, errDeferredExecutionNotSupported
+	}
+
+	requestID := n.newRequestID()
+	code, results, cluster, timing, _ := n.restExecute(ctx, requestID, req, subgroup)
+
+	return code, requestID, results, cluster, timing, nil
+}
+
+// ExecuteFunctionAsync starts the execution requested via the REST API and returns right away. Once the execution is done, results are
+// posted to the callback URL, if set, and can be retrieved using the returned request ID.
+func (n *Node) ExecuteFunctionAsync(ctx context.Context, req execute.Request, subgroup string, callback string) (codes.Code, string, error) {
+
+	if !n.isHead() {
+		return codes.NotAvailable, "", fmt.Errorf("action not supported on this node type")
+	}
+
+	if n.rateLimited(req.FunctionID, "") {
+		return codes.TooManyRequests, "", b7serrors.ErrRateLimited
+	}
+
+	if req.Config.NotBefore.After(time.Now()) {
+		return codes.NotSupported, "", errDeferredExecutionNotSupported
+	}
+
+	requestID := n.newRequestID()
+
+	// Execution outlives the API request.
+	ctx = context.WithoutCancel(ctx)
+
+	go func() {
+		code, results, cluster, timing, err := n.restExecute(ctx, requestID, req, subgroup)
+		if callback == "" {
+			return
+		}
+
+		ereq := request.Execute{
+			Request:   req,
+			RequestID: requestID,
+			Async:     true,
+			Callback:  callback,
+		}
+
+		res := ereq.Response(code).WithResults(results).WithCluster(cluster).WithTiming(timing)
+		if b7serrors.IsPublic(err) {
+			res.ErrorMessage = err.Error()
+		}
+
+		n.deliverAsyncResponse(ctx, "", ereq, res)
+	}()
+
+	return codes.Accepted, requestID, nil
+}
+
+// restExecute runs the execution requested via the REST API and records its results.
+func (n *Node) restExecute(ctx context.Context, requestID string, req execute.Request, subgroup string) (codes.Code, execute.ResultMap, execute.Cluster, execute.Timing, error) {
+
+	code, results, cluster, timing, err := n.executeOrDelegate(ctx, requestID, req, subgroup, nil)
 	if err != nil {
 		n.log.Error().Str("request", requestID).Err(err).Msg("execution failed")
 	}
 
-	return code, requestID, results, cluster, nil
+	n.detectAnomalies(requestID, req.FunctionID, results)
+	n.shadowExecution(ctx, requestID, req, subgroup, code, results)
+
+	// Record the results so they can be retrieved later, possibly from another head node sharing the result store.
+	n.saveExecutionResults(requestID, results)
+
+	return code, results, cluster, timing, err
 }
 
-// ExecutionResult fetches the execution result from the node cache.
+// ExecutionResult fetches the execution result from the node cache, falling back to the result archive.
 func (n *Node) ExecutionResult(id string) (execute.ResultMap, bool) {
-	return n.executeResponses.Get(id)
+
+	results, ok := n.executeResponses.Get(id)
+	if ok {
+		return results, true
+	}
+
+	return n.archivedExecutionResults(id)
 }
 
 // PublishFunctionInstall publishes a function install message.
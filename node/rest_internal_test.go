@@ -6,13 +6,16 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/blocklessnetwork/b7s/host"
 	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/node/internal/waitmap"
 	"github.com/blocklessnetwork/b7s/testing/mocks"
 )
 
 func TestNode_RestExecuteNotSupportedOnWorker(t *testing.T) {
 	node := createNode(t, blockless.WorkerNode)
-	_, _, _, _, err := node.ExecuteFunction(context.Background(), mocks.GenericExecutionRequest, "")
+	_, _, _, _, _, err := node.ExecuteFunction(context.Background(), mocks.GenericExecutionRequest, "")
 	require.Error(t, err)
 }
 
@@ -28,3 +31,31 @@ func TestNode_InstallMessageFromCID(t *testing.T) {
 	require.Equal(t, cid, req.CID)
 	require.Equal(t, expectedManifestURL, req.ManifestURL)
 }
+
+func TestNode_ExecutionResultFromSharedStore(t *testing.T) {
+
+	const (
+		requestID = "dummy-request-id"
+	)
+
+	// Two head nodes sharing the result store.
+	shared := waitmap.New[string, execute.ResultMap](0)
+
+	var nodes []*Node
+	for i := 0; i < 2; i++ {
+
+		host, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		node, err := New(mocks.NoopLogger, host, mocks.BaselineStore(t), mocks.BaselineFStore(t), WithRole(blockless.HeadNode), WithResultStore(shared))
+		require.NoError(t, err)
+
+		nodes = append(nodes, node)
+	}
+
+	nodes[0].executeResponses.Set(requestID, mocks.GenericExecutionResultMap)
+
+	res, ok := nodes[1].ExecutionResult(requestID)
+	require.True(t, ok)
+	require.Equal(t, mocks.GenericExecutionResultMap, res)
+}
@@ -0,0 +1,37 @@
+package node
+
+import (
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/node/internal/resultcache"
+)
+
+// cachedResult returns the result of an earlier identical execution, if the worker has one cached.
+// Executions done as part of a cluster are never served from cache, as cluster members must agree on the execution.
+// Sampled executions are not served from cache either, as they measure the workers executing them.
+func (n *Node) cachedResult(req execute.Request) (execute.Result, bool) {
+
+	if n.resultCache == nil || req.Config.ConsensusAlgorithm != "" || req.Config.Sampling != nil || !resultcache.Cacheable(req) {
+		return execute.Result{}, false
+	}
+
+	result, ok := n.resultCache.Get(resultcache.Key(req))
+	if !ok {
+		n.metrics.IncrCounter(resultCacheMissesMetric, 1)
+		return execute.Result{}, false
+	}
+
+	n.metrics.IncrCounter(resultCacheHitsMetric, 1)
+
+	return result, true
+}
+
+// cacheResult caches the result of a successful execution, so identical executions can be served without executing the function.
+func (n *Node) cacheResult(req execute.Request, result execute.Result) {
+
+	if n.resultCache == nil || req.Config.ConsensusAlgorithm != "" || result.Code != codes.OK || !resultcache.Cacheable(req) {
+		return
+	}
+
+	n.resultCache.Add(resultcache.Key(req), result)
+}
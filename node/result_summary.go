@@ -0,0 +1,118 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/request"
+)
+
+var (
+	errUnknownResultSummary = errors.New("execution results not found")
+	errUnknownSummaryPeer   = errors.New("peer has no result for this execution")
+)
+
+// summarizedExecution holds the full results of an execution answered with a result summary.
+type summarizedExecution struct {
+	requester peer.ID
+	results   execute.ResultMap
+	expires   time.Time
+}
+
+// summarizedExecutions holds the full results of executions answered with a result summary, so the requesting peer
+// can retrieve them one by one. Results are kept for a limited time, and only for a limited number of executions.
+type summarizedExecutions struct {
+	sync.Mutex
+	executions map[string]summarizedExecution
+}
+
+func newSummarizedExecutions() *summarizedExecutions {
+	return &summarizedExecutions{
+		executions: make(map[string]summarizedExecution),
+	}
+}
+
+// set records the results of the execution. Expired results are removed at the same time, and if there are still
+// too many executions kept, the one expiring first is removed.
+func (s *summarizedExecutions) set(requestID string, requester peer.ID, results execute.ResultMap, now time.Time) {
+	s.Lock()
+	defer s.Unlock()
+
+	for id, execution := range s.executions {
+		if now.After(execution.expires) {
+			delete(s.executions, id)
+		}
+	}
+
+	if len(s.executions) >= resultSummaryCacheSize {
+		var (
+			oldest  string
+			expires time.Time
+		)
+		for id, execution := range s.executions {
+			if oldest == "" || execution.expires.Before(expires) {
+				oldest, expires = id, execution.expires
+			}
+		}
+		delete(s.executions, oldest)
+	}
+
+	s.executions[requestID] = summarizedExecution{
+		requester: requester,
+		results:   results,
+		expires:   now.Add(resultSummaryRetention),
+	}
+}
+
+// get returns the results of the execution, if they did not expire and the execution was requested by the given peer.
+func (s *summarizedExecutions) get(requestID string, requester peer.ID, now time.Time) (execute.ResultMap, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	execution, ok := s.executions[requestID]
+	if !ok || now.After(execution.expires) || execution.requester != requester {
+		return nil, false
+	}
+
+	return execution.results, true
+}
+
+// processExecutionResult serves the result a single peer produced for an execution answered with a result summary.
+// Results are only served to the peer that requested the execution. For other peers the execution is reported as
+// not found, so they cannot tell which executions exist.
+func (n *Node) processExecutionResult(ctx context.Context, from peer.ID, req request.ExecutionResult) error {
+
+	results, ok := n.summaries.get(req.ID, from, time.Now())
+	if !ok {
+		err := n.send(ctx, from, req.Response(codes.NotFound).WithErrorMessage(errUnknownResultSummary))
+		if err != nil {
+			return fmt.Errorf("could not send response: %w", err)
+		}
+		return nil
+	}
+
+	res, ok := results[req.Peer]
+	if !ok {
+		err := n.send(ctx, from, req.Response(codes.NotFound).WithErrorMessage(errUnknownSummaryPeer))
+		if err != nil {
+			return fmt.Errorf("could not send response: %w", err)
+		}
+		return nil
+	}
+
+	n.log.Debug().Str("peer", from.String()).Str("id", req.ID).Str("result_peer", req.Peer.String()).Msg("serving execution result")
+
+	err := n.send(ctx, from, req.Response(codes.OK).WithResult(res))
+	if err != nil {
+		return fmt.Errorf("could not send response: %w", err)
+	}
+
+	return nil
+}
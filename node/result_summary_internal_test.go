@@ -0,0 +1,198 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/node/head/simulation"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_ProcessExecutionResult(t *testing.T) {
+
+	const (
+		requestID = "dummy-request-id"
+	)
+
+	var (
+		executingPeer = mocks.GenericPeerIDs[0]
+		result        = execute.NodeResult{Result: execute.Result{Code: codes.OK, Result: execute.RuntimeOutput{Stdout: "dummy-stdout"}}}
+	)
+
+	newReceiver := func(t *testing.T, node *Node) *host.Host {
+		t.Helper()
+
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, receiver)
+
+		return receiver
+	}
+
+	// Send the request from the receiver host and return the response the head node sent back.
+	process := func(t *testing.T, node *Node, receiver *host.Host, req request.ExecutionResult) response.ExecutionResult {
+		t.Helper()
+
+		var (
+			wg       sync.WaitGroup
+			received response.ExecutionResult
+		)
+		wg.Add(1)
+
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer wg.Done()
+			defer stream.Close()
+
+			getStreamPayload(t, stream, &received)
+		})
+
+		err := node.processExecutionResult(context.Background(), receiver.ID(), req)
+		require.NoError(t, err)
+
+		wg.Wait()
+
+		return received
+	}
+
+	t.Run("result of a peer is returned", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+		receiver := newReceiver(t, node)
+		node.summaries.set(requestID, receiver.ID(), execute.ResultMap{executingPeer: result}, time.Now())
+
+		res := process(t, node, receiver, request.ExecutionResult{ID: requestID, Peer: executingPeer})
+		require.Equal(t, codes.OK, res.Code)
+		require.Equal(t, executingPeer, res.Peer)
+		require.NotNil(t, res.Result)
+		require.Equal(t, result.Result, res.Result.Result)
+	})
+	t.Run("unknown execution is reported", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+
+		res := process(t, node, newReceiver(t, node), request.ExecutionResult{ID: requestID, Peer: executingPeer})
+		require.Equal(t, codes.NotFound, res.Code)
+		require.Nil(t, res.Result)
+	})
+	t.Run("results are only served to the requester", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+		node.summaries.set(requestID, mocks.GenericPeerIDs[2], execute.ResultMap{executingPeer: result}, time.Now())
+
+		res := process(t, node, newReceiver(t, node), request.ExecutionResult{ID: requestID, Peer: executingPeer})
+		require.Equal(t, codes.NotFound, res.Code)
+		require.Nil(t, res.Result)
+	})
+	t.Run("expired results are not served", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+		receiver := newReceiver(t, node)
+		node.summaries.set(requestID, receiver.ID(), execute.ResultMap{executingPeer: result}, time.Now().Add(-resultSummaryRetention-time.Second))
+
+		res := process(t, node, receiver, request.ExecutionResult{ID: requestID, Peer: executingPeer})
+		require.Equal(t, codes.NotFound, res.Code)
+		require.Nil(t, res.Result)
+	})
+	t.Run("unknown peer is reported", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+		receiver := newReceiver(t, node)
+		node.summaries.set(requestID, receiver.ID(), execute.ResultMap{executingPeer: result}, time.Now())
+
+		res := process(t, node, receiver, request.ExecutionResult{ID: requestID, Peer: mocks.GenericPeerIDs[1]})
+		require.Equal(t, codes.NotFound, res.Code)
+		require.Nil(t, res.Result)
+	})
+}
+
+func TestSummarizedExecutions_Bounded(t *testing.T) {
+
+	var (
+		summaries = newSummarizedExecutions()
+		requester = mocks.GenericPeerID
+		now       = time.Now()
+	)
+
+	for i := range resultSummaryCacheSize + 1 {
+		summaries.set(fmt.Sprintf("request-%d", i), requester, execute.ResultMap{}, now.Add(time.Duration(i)*time.Second))
+	}
+
+	require.Len(t, summaries.executions, resultSummaryCacheSize)
+
+	// Results expiring first are removed once there are too many.
+	_, ok := summaries.get("request-0", requester, now.Add(time.Minute))
+	require.False(t, ok)
+	_, ok = summaries.get("request-1", requester, now.Add(time.Minute))
+	require.True(t, ok)
+}
+
+func TestNode_ExecuteWithResultSummary(t *testing.T) {
+
+	const (
+		functionID = "dummy-function-id"
+		workers    = 3
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	node := createNode(t, blockless.HeadNode)
+
+	fleet, err := simulation.NewFleet(mocks.NoopLogger, workers, loopback, simulation.Profile{}, []string{DefaultTopic})
+	require.NoError(t, err)
+	defer fleet.Close()
+
+	err = fleet.Connect(ctx, *hostGetAddrInfo(t, node.host))
+	require.NoError(t, err)
+
+	err = node.subscribeToTopics(ctx)
+	require.NoError(t, err)
+	node.listenDirectMessages(ctx)
+
+	go fleet.Run(ctx)
+
+	time.Sleep(subscriptionDiseminationPause)
+
+	req := request.Execute{
+		Request: execute.Request{FunctionID: functionID, Method: "dummy-method", Config: execute.Config{NodeCount: workers}},
+		Summary: true,
+	}
+
+	requestID := node.newRequestID()
+	res := node.headExecuteRequest(ctx, mocks.GenericPeerID, requestID, req)
+	require.Equal(t, codes.OK, res.Code)
+	require.Empty(t, res.Results)
+	require.NotNil(t, res.Summary)
+	require.Equal(t, requestID, res.Summary.ID)
+	require.Len(t, res.Summary.Peers, workers)
+
+	// Full results are kept for retrieval by the requester only.
+	_, ok := node.summaries.get(requestID, mocks.GenericPeerIDs[1], time.Now())
+	require.False(t, ok)
+
+	results, ok := node.summaries.get(requestID, mocks.GenericPeerID, time.Now())
+	require.True(t, ok)
+	require.Len(t, results, workers)
+	for _, summary := range res.Summary.Peers {
+		require.Contains(t, results, summary.Peer)
+		require.Equal(t, results[summary.Peer].OutputChecksum(), summary.Checksum)
+	}
+}
@@ -0,0 +1,35 @@
+package node
+
+import (
+	"context"
+
+	"github.com/armon/go-metrics"
+
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+// publishExecutionResult publishes the execution result to the topic the client asked for, if any.
+// Topics used by the Blockless protocol itself are never used for results.
+func (n *Node) publishExecutionResult(ctx context.Context, topic string, res *response.Execute) {
+
+	if topic == "" {
+		return
+	}
+
+	log := n.log.With().Str("request", res.RequestID).Str("topic", topic).Logger()
+
+	if topic == DefaultTopic || topic == DefaultFleetTopic {
+		log.Warn().Msg("execution result topic is reserved - skipping result publishing")
+		return
+	}
+
+	err := n.publishToTopic(ctx, topic, res)
+	if err != nil {
+		log.Error().Err(err).Msg("could not publish execution result")
+		return
+	}
+
+	n.metrics.IncrCounterWithLabels(resultsPublishedMetric, 1, []metrics.Label{{Name: "topic", Value: topic}})
+
+	log.Debug().Msg("published execution result")
+}
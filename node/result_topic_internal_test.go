@@ -0,0 +1,77 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_PublishExecutionResult(t *testing.T) {
+
+	const (
+		testTimeLimit = 10 * time.Second
+		topic         = "results/collector"
+	)
+
+	node := createNode(t, blockless.WorkerNode)
+
+	receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeLimit)
+	defer cancel()
+
+	hostAddNewPeer(t, node.host, receiver)
+	info := hostGetAddrInfo(t, receiver)
+
+	err = node.host.Connect(ctx, *info)
+	require.NoError(t, err)
+
+	err = receiver.InitPubSub(ctx)
+	require.NoError(t, err)
+
+	_, subscription, err := receiver.Subscribe(topic)
+	require.NoError(t, err)
+
+	err = node.subscribeToTopics(ctx)
+	require.NoError(t, err)
+
+	_, err = node.joinTopic(topic)
+	require.NoError(t, err)
+
+	time.Sleep(subscriptionDiseminationPause)
+
+	res := response.Execute{
+		Code:      codes.OK,
+		RequestID: mocks.GenericUUID.String(),
+		Results: execute.ResultMap{
+			node.host.ID(): execute.NodeResult{Result: mocks.GenericExecutionResult},
+		},
+	}
+
+	// Reserved topics are not used for results.
+	node.publishExecutionResult(ctx, DefaultTopic, &res)
+	node.publishExecutionResult(ctx, topic, &res)
+
+	msg, err := subscription.Next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, node.host.ID(), msg.ReceivedFrom)
+
+	var received response.Execute
+	err = json.Unmarshal(msg.Data, &received)
+	require.NoError(t, err)
+
+	require.Equal(t, res.RequestID, received.RequestID)
+	require.Equal(t, codes.OK, received.Code)
+	require.Contains(t, received.Results, node.host.ID())
+}
@@ -3,17 +3,20 @@ package node
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/armon/go-metrics"
 	"github.com/libp2p/go-libp2p/core/peer"
 
+	"github.com/blocklessnetwork/b7s/b7serrors"
 	"github.com/blocklessnetwork/b7s/consensus"
-	"github.com/blocklessnetwork/b7s/consensus/pbft"
 	"github.com/blocklessnetwork/b7s/models/blockless"
 	"github.com/blocklessnetwork/b7s/models/codes"
 	"github.com/blocklessnetwork/b7s/models/execute"
 	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/node/internal/dispatch"
 )
 
 func (n *Node) processRollCall(ctx context.Context, from peer.ID, req request.RollCall) error {
@@ -23,6 +26,50 @@ func (n *Node) processRollCall(ctx context.Context, from peer.ID, req request.Ro
 	log := n.log.With().Str("request", req.RequestID).Str("origin", req.Origin.String()).Str("function", req.FunctionID).Logger()
 	log.Debug().Msg("received roll call request")
 
+	// Sampled roll calls are answered only by a share of workers, chosen by the workers themselves.
+	if !n.sampled(req.Probability) {
+		log.Debug().Float64("probability", req.Probability).Msg("not sampled for roll call")
+		n.metrics.IncrCounterWithLabels(rollCallsNotSampledMetric, 1, []metrics.Label{{Name: "function", Value: req.FunctionID}})
+		return nil
+	}
+
+	// Functions the worker is not permitted to execute are declined silently.
+	if !n.functionPermitted(req.FunctionID) {
+		log.Debug().Msg("skipping roll call for function not permitted on this worker")
+		n.metrics.IncrCounterWithLabels(functionsDeclinedMetric, 1, []metrics.Label{{Name: "source", Value: "rollcall"}})
+		return nil
+	}
+
+	if n.inMaintenance(time.Now()) {
+		log.Info().Msg("declining roll call during maintenance window")
+		n.metrics.IncrCounter(maintenanceDeclinedMetric, 1)
+
+		err := n.send(ctx, req.Origin, req.Response(codes.NotAvailable).WithReason(response.RollCallReasonMaintenance))
+		if err != nil {
+			return fmt.Errorf("could not send response: %w", err)
+		}
+
+		return nil
+	}
+
+	if n.Draining() {
+		log.Info().Msg("declining roll call in drain mode")
+		n.metrics.IncrCounter(drainDeclinedMetric, 1)
+
+		err := n.send(ctx, req.Origin, req.Response(codes.NotAvailable).WithReason(response.RollCallReasonDraining))
+		if err != nil {
+			return fmt.Errorf("could not send response: %w", err)
+		}
+
+		return nil
+	}
+
+	// Workers built without the consensus algorithm cannot take part in the cluster.
+	if consensusRequired(req.Consensus) && !req.Consensus.Valid() {
+		log.Info().Uint("consensus", uint(req.Consensus)).Msg("skipping roll call for consensus algorithm not supported by this node")
+		return nil
+	}
+
 	// TODO: (raft) temporary measure - at the moment we don't support multiple raft clusters on the same node at the same time.
 	if req.Consensus == consensus.Raft && n.haveRaftClusters() {
 		log.Warn().Msg("cannot respond to a roll call as we're already participating in one raft cluster")
@@ -31,12 +78,13 @@ func (n *Node) processRollCall(ctx context.Context, from peer.ID, req request.Ro
 
 	if req.Attributes != nil {
 
-		if n.attributes == nil {
+		have, ok := n.matchedAttributes()
+		if !ok {
 			log.Info().Msg("skipping attributed execution requested")
 			return nil
 		}
 
-		err := haveAttributes(*n.attributes, *req.Attributes)
+		err := haveAttributes(have, *req.Attributes)
 		if err != nil {
 			log.Info().Err(err).Msg("skipping attributed execution request - we do not match requested attributes")
 			return nil
@@ -55,28 +103,31 @@ func (n *Node) processRollCall(ctx context.Context, from peer.ID, req request.Ro
 		return fmt.Errorf("could not check if function is installed: %w", err)
 	}
 
-	// We don't have this function - install it now.
-	if !installed {
-
-		log.Info().Msg("roll call but function not installed, installing now")
-
-		err = n.installFunction(ctx, req.FunctionID, manifestURLFromCID(req.FunctionID))
+	// Functions packaged as container images can only be executed by workers that can run containers.
+	if installed {
+		err = n.supportsFunction(ctx, req.FunctionID)
 		if err != nil {
-			sendErr := n.send(ctx, req.Origin, req.Response(codes.Error))
-			if sendErr != nil {
-				// Log send error but choose to return the original error.
-				log.Error().Err(sendErr).Str("to", req.Origin.String()).Msg("could not send response")
-			}
-			return fmt.Errorf("could not install function: %w", err)
+			log.Info().Err(err).Msg("skipping roll call for function we cannot execute")
+			return nil
 		}
 	}
 
+	res := req.Response(codes.Accepted).WithLoad(n.currentLoad()).WithCredential(n.membershipCredential(req.Subgroup))
+
+	// We don't have this function - start installing it now, so it is ready by the time the execution request arrives.
+	if !installed {
+		log.Info().Msg("roll call but function not installed, installing now")
+		res = res.WithInstall(n.prefetchFunction(ctx, req.FunctionID))
+	}
+
 	log.Info().Str("origin", req.Origin.String()).Msg("reporting for roll call")
 
 	n.metrics.IncrCounterWithLabels(rollCallsAppliedMetric, 1, []metrics.Label{{Name: "function", Value: req.FunctionID}})
 
-	// Send positive response.
-	err = n.send(ctx, req.Origin, req.Response(codes.Accepted))
+	n.drain.lastRollCall.Store(time.Now().UnixNano())
+
+	// Send positive response, letting the head node know how busy we are.
+	err = n.send(ctx, req.Origin, res)
 	if err != nil {
 		return fmt.Errorf("could not send response: %w", err)
 	}
@@ -92,35 +143,68 @@ func (n *Node) executeRollCall(
 	consensusAlgo consensus.Type,
 	topic string,
 	attributes *execute.Attributes,
-	timeout int,
-) ([]peer.ID, error) {
+	affinity string,
+) ([]peer.ID, []peer.ID, map[peer.ID]time.Duration, error) {
 
 	// Create a logger with relevant context.
 	log := n.log.With().Str("request", requestID).Str("function", functionID).Int("node_count", nodeCount).Str("topic", topic).Logger()
 
+	// Right after startup, wait until we have reached the workers we knew of.
+	n.warm.wait(ctx)
+
+	// Workers are bound to affinity tokens regardless of the subgroup, so restricted subgroups always do a roll call.
+	if n.isRestrictedSubgroup(topic) {
+		affinity = ""
+	}
+
+	// Use the workers bound to the affinity token, if they are still available.
+	bound, boundLatencies, ok := n.affinityPeers(functionID, nodeCount, consensusAlgo, affinity)
+	if ok {
+		log.Info().Str("affinity", affinity).Strs("peers", blockless.PeerIDsToStr(bound)).Msg("using workers bound to affinity token, skipping roll call")
+		return bound, nil, boundLatencies, nil
+	}
+
+	// Use workers from the warm pool if we have enough of them.
+	pooled, pooledReserve, pooledLatencies, ok := n.warmPoolPeers(functionID, nodeCount, consensusAlgo, topic, attributes)
+	if ok {
+		log.Info().Strs("peers", blockless.PeerIDsToStr(pooled)).Msg("using workers from warm pool, skipping roll call")
+		return pooled, pooledReserve, pooledLatencies, nil
+	}
+
 	log.Info().Msg("performing roll call for request")
 
-	n.rollCall.create(requestID)
-	defer n.rollCall.remove(requestID)
+	n.rollCall.Create(requestID)
+	defer n.rollCall.Remove(requestID)
 
-	err := n.publishRollCall(ctx, requestID, functionID, consensusAlgo, topic, attributes)
+	// On large topics, ask only a share of workers to answer.
+	probability := n.rollCallProbability(topic, nodeCount)
+	if probability > 0 {
+		log.Info().Float64("probability", probability).Msg("sampling roll call")
+	}
+
+	published := time.Now()
+	err := n.publishRollCall(ctx, requestID, functionID, consensusAlgo, topic, attributes, probability)
 	if err != nil {
-		return nil, fmt.Errorf("could not publish roll call: %w", err)
+		return nil, nil, nil, fmt.Errorf("could not publish roll call: %w", err)
 	}
 
 	log.Info().Msg("roll call published")
 
 	// Limit for how long we wait for responses.
-	t := n.cfg.RollCallTimeout
-	if timeout > 0 {
-		t = time.Duration(timeout) * time.Second
-	}
-
-	tctx, exCancel := context.WithTimeout(ctx, t)
+	tctx, exCancel := context.WithTimeout(ctx, n.cfg.RollCallTimeout)
 	defer exCancel()
 
-	// Peers that have reported on roll call.
-	var reportingPeers []peer.ID
+	// Peers that have reported on roll call, and how long it took them.
+	var (
+		reportingPeers []peer.ID
+		latencies      = make(map[peer.ID]time.Duration)
+	)
+	measure := func(reply RollCallResponse) {
+		rtt := time.Since(published)
+		latencies[reply.From] = rtt
+		n.latencies.record(reply.From, rtt)
+		n.loads.record(reply.From, reply.Load)
+	}
 rollCallResponseLoop:
 	for {
 		// Wait for responses from nodes who want to work on the request.
@@ -128,6 +212,13 @@ rollCallResponseLoop:
 		// Request timed out.
 		case <-tctx.Done():
 
+			// Do not bother choosing workers if no one is waiting for the execution anymore.
+			if abandoned(ctx) {
+				log.Info().Msg("execution abandoned during roll call")
+				n.releaseWorkers(reportingPeers, nil, dispatch.Aborted)
+				return nil, nil, nil, errExecutionAbandoned
+			}
+
 			// -1 means we'll take any peers reporting
 			if len(reportingPeers) >= 1 && nodeCount == -1 {
 				log.Info().Msg("enough peers reported for roll call")
@@ -135,9 +226,10 @@ rollCallResponseLoop:
 			}
 
 			log.Warn().Msg("roll call timed out")
-			return nil, blockless.ErrRollCallTimeout
+			n.releaseWorkers(reportingPeers, nil, dispatch.Aborted)
+			return nil, nil, nil, b7serrors.ErrRollCallTimeout
 
-		case reply := <-n.rollCall.responses(requestID):
+		case reply := <-n.rollCall.Responses(requestID):
 
 			// Check if this is the reply we want - shouldn't really happen.
 			if reply.FunctionID != functionID {
@@ -145,6 +237,12 @@ rollCallResponseLoop:
 				continue
 			}
 
+			// Peers are counted once, even if they answered the roll call more than once.
+			if slices.Contains(reportingPeers, reply.From) {
+				log.Info().Str("peer", reply.From.String()).Msg("skipping duplicate roll call response")
+				continue
+			}
+
 			// Check if we are connected to this peer.
 			// Since we receive responses to roll call via direct messages - should not happen.
 			if !n.haveConnection(reply.From) {
@@ -152,6 +250,35 @@ rollCallResponseLoop:
 				continue
 			}
 
+			// Restricted subgroups only count workers that are members.
+			err = n.verifyMembership(topic, reply)
+			if err != nil {
+				log.Info().Err(err).Str("peer", reply.From.String()).Msg("skipping roll call response from peer without a valid membership credential")
+				n.metrics.IncrCounterWithLabels(membershipRejectedMetric, 1, []metrics.Label{{Name: "subgroup", Value: topic}})
+				continue
+			}
+
+			// Skip workers that cannot take part in the consensus cluster.
+			if consensusRequired(consensusAlgo) && !n.peers.supportsConsensus(reply.From) {
+				log.Info().Str("peer", reply.From.String()).Msg("skipping roll call response from peer without consensus support")
+				continue
+			}
+
+			// Skip workers caught misbehaving in consensus clusters.
+			if n.excluded.excluded(reply.From, time.Now()) {
+				log.Info().Str("peer", reply.From.String()).Msg("skipping roll call response from excluded peer")
+				n.metrics.IncrCounter(excludedPeersSkippedMetric, 1)
+				continue
+			}
+
+			measure(reply)
+
+			// Check if the peer can take on more work.
+			if !n.dispatch.Acquire(reply.From) {
+				log.Info().Str("peer", reply.From.String()).Uint("limit", n.dispatch.Limit(reply.From)).Msg("skipping roll call response from peer at its dispatch limit")
+				continue
+			}
+
 			log.Info().Str("peer", reply.From.String()).Msg("roll called peer chosen for execution")
 
 			reportingPeers = append(reportingPeers, reply.From)
@@ -164,31 +291,147 @@ rollCallResponseLoop:
 		}
 	}
 
-	if consensusAlgo == consensus.PBFT && len(reportingPeers) < pbft.MinimumReplicaCount {
-		return nil, fmt.Errorf("not enough peers reported for PBFT consensus (have: %v, need: %v)", len(reportingPeers), pbft.MinimumReplicaCount)
+	// Peers that reported after we had enough are kept as a reserve, in case some of the chosen peers fail.
+	var reserve []peer.ID
+reserveLoop:
+	for {
+		select {
+		case reply := <-n.rollCall.Responses(requestID):
+			if reply.FunctionID != functionID || !n.haveConnection(reply.From) || slices.Contains(reportingPeers, reply.From) || slices.Contains(reserve, reply.From) {
+				continue
+			}
+
+			if n.verifyMembership(topic, reply) != nil {
+				n.metrics.IncrCounterWithLabels(membershipRejectedMetric, 1, []metrics.Label{{Name: "subgroup", Value: topic}})
+				continue
+			}
+
+			if n.excluded.excluded(reply.From, time.Now()) {
+				n.metrics.IncrCounter(excludedPeersSkippedMetric, 1)
+				continue
+			}
+
+			if consensusRequired(consensusAlgo) && !n.peers.supportsConsensus(reply.From) {
+				continue
+			}
+
+			measure(reply)
+			reserve = append(reserve, reply.From)
+
+		default:
+			break reserveLoop
+		}
+	}
+
+	// Out of all peers that reported, choose the ones that will execute the request.
+	reportingPeers, reserve = n.selectRollCallPeers(functionID, nodeCount, reportingPeers, reserve, latencies)
+	if len(reportingPeers) == 0 {
+		return nil, nil, nil, errNoPeersSelected
+	}
+
+	minReplicas := consensus.MinimumReplicas(consensusAlgo)
+	if len(reportingPeers) < minReplicas {
+		n.releaseWorkers(reportingPeers, nil, dispatch.Aborted)
+		return nil, nil, nil, fmt.Errorf("not enough peers reported for %s consensus (have: %v, need: %v)", consensusAlgo, len(reportingPeers), minReplicas)
 	}
 
-	return reportingPeers, nil
+	if len(reserve) > 0 {
+		log.Info().Int("reserve", len(reserve)).Msg("roll called peers kept in reserve")
+	}
+
+	return reportingPeers, reserve, latencies, nil
+}
+
+// selectRollCallPeers chooses the peers that execute the request, out of the peers chosen so far and the reserve peers.
+// Unless the node has a custom peer selector, peers are ranked by reputation, latency, load and installed functions, and the number of peers chosen
+// for execution does not change. Remaining peers are kept in reserve, best first.
+func (n *Node) selectRollCallPeers(functionID string, nodeCount int, chosen []peer.ID, reserve []peer.ID, latencies map[peer.ID]time.Duration) ([]peer.ID, []peer.ID) {
+
+	candidates := append(slices.Clone(chosen), reserve...)
+
+	var (
+		preferred []peer.ID
+		limit     = len(chosen)
+	)
+	if n.cfg.PeerSelector != nil {
+		preferred = n.cfg.PeerSelector.Select(functionID, nodeCount, n.rollCallCandidates(candidates, latencies))
+		limit = nodeCount
+	} else {
+		if len(reserve) == 0 {
+			return chosen, reserve
+		}
+
+		preferred = n.reputation.Rank(candidates, func(peer peer.ID) float64 {
+			return n.peerWeight(peer) * n.fleetWeight(peer, functionID)
+		})
+	}
+
+	selected := make([]peer.ID, 0, len(chosen))
+	for _, peer := range preferred {
+
+		// -1 means any number of peers can execute the request.
+		if limit != -1 && len(selected) >= limit {
+			break
+		}
+
+		if !slices.Contains(candidates, peer) || slices.Contains(selected, peer) {
+			continue
+		}
+
+		// Peers chosen earlier already count towards their dispatch limit.
+		if !slices.Contains(chosen, peer) && !n.dispatch.Acquire(peer) {
+			continue
+		}
+
+		selected = append(selected, peer)
+	}
+
+	// Keep the preferred order for the reserve, followed by any peers the selector did not mention.
+	remaining := make([]peer.ID, 0, len(candidates)-len(selected))
+	for _, peer := range append(preferred, candidates...) {
+		if slices.Contains(candidates, peer) && !slices.Contains(selected, peer) && !slices.Contains(remaining, peer) {
+			remaining = append(remaining, peer)
+		}
+	}
+
+	// Peers replaced by other peers are no longer dispatched to.
+	for _, peer := range chosen {
+		if !slices.Contains(selected, peer) {
+			n.dispatch.Release(peer, dispatch.Aborted)
+			n.log.Debug().Str("peer", peer.String()).Float64("score", n.reputation.Score(peer)).Msg("roll called peer replaced by another peer")
+		}
+	}
+
+	return selected, remaining
+}
+
+// peerWeight returns the factor by which the reputation score of the peer is scaled when choosing peers for execution,
+// preferring fast and less loaded peers.
+func (n *Node) peerWeight(peer peer.ID) float64 {
+	return n.latencies.weight(peer) * n.loads.weight(peer)
 }
 
 // publishRollCall will create a roll call request for executing the given function.
 // On successful issuance of the roll call request, we return the ID of the issued request.
-func (n *Node) publishRollCall(ctx context.Context, requestID string, functionID string, consensus consensus.Type, topic string, attributes *execute.Attributes) error {
+// Probability, if set, is the chance with which workers answer the roll call.
+func (n *Node) publishRollCall(ctx context.Context, requestID string, functionID string, consensus consensus.Type, topic string, attributes *execute.Attributes, probability float64) error {
 
 	n.metrics.IncrCounterWithLabels(rollCallsPublishedMetric, 1, []metrics.Label{{Name: "function", Value: functionID}})
 
 	// Create a roll call request.
 	rollCall := request.RollCall{
-		Origin:     n.host.ID(),
-		FunctionID: functionID,
-		RequestID:  requestID,
-		Consensus:  consensus,
-		Attributes: attributes,
+		Origin:      n.host.ID(),
+		FunctionID:  functionID,
+		RequestID:   requestID,
+		Consensus:   consensus,
+		Attributes:  attributes,
+		Probability: probability,
 	}
 
 	if topic == "" {
 		topic = DefaultTopic
 	}
+	rollCall.Subgroup = topic
 
 	// Publish the mssage.
 	err := n.publishToTopic(ctx, topic, &rollCall)
@@ -199,6 +442,26 @@ func (n *Node) publishRollCall(ctx context.Context, requestID string, functionID
 	return nil
 }
 
+// noRollCallFallbackKey marks executions whose roll calls should not be retried on the default topic.
+type noRollCallFallbackKey struct{}
+
+// withoutRollCallFallback returns a context for executions that must stay within their subgroup, such as canary probes.
+func withoutRollCallFallback(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRollCallFallbackKey{}, true)
+}
+
+// rollCallFallback returns true if a roll call over the given topic that did not get enough responses should be retried on the default topic.
+func (n *Node) rollCallFallback(ctx context.Context, topic string) bool {
+
+	// Workers of restricted subgroups are never replaced by workers from the rest of the network.
+	if !n.cfg.RollCallFallback || topic == "" || topic == DefaultTopic || n.isRestrictedSubgroup(topic) {
+		return false
+	}
+
+	disabled, _ := ctx.Value(noRollCallFallbackKey{}).(bool)
+	return !disabled
+}
+
 // Temporary measure - we can't have multiple Raft clusters at this point. Remove when we remove this limitation.
 func (n *Node) haveRaftClusters() bool {
 
@@ -8,19 +8,68 @@ import (
 	"time"
 
 	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/stretchr/testify/require"
 
+	"github.com/blocklessnetwork/b7s/b7serrors"
 	"github.com/blocklessnetwork/b7s/consensus"
 	"github.com/blocklessnetwork/b7s/host"
 	"github.com/blocklessnetwork/b7s/models/blockless"
 	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
 	"github.com/blocklessnetwork/b7s/models/request"
 	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/node/head/schedule"
+	"github.com/blocklessnetwork/b7s/node/head/simulation"
+	"github.com/blocklessnetwork/b7s/node/internal/dispatch"
+	"github.com/blocklessnetwork/b7s/node/internal/reputation"
 	"github.com/blocklessnetwork/b7s/testing/mocks"
 )
 
 func TestNode_RollCall(t *testing.T) {
 
+	t.Run("worker node declines roll call during maintenance", func(t *testing.T) {
+		t.Parallel()
+
+		always, err := schedule.Parse("* * * * *")
+		require.NoError(t, err)
+
+		node := createNode(t, blockless.WorkerNode)
+		node.cfg.MaintenanceWindows = []MaintenanceWindow{{Start: always, Duration: time.Hour}}
+
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		rollCallReq := request.RollCall{
+			FunctionID: "dummy-function-id",
+			RequestID:  mocks.GenericUUID.String(),
+			Origin:     receiver.ID(),
+		}
+
+		hostAddNewPeer(t, node.host, receiver)
+
+		var (
+			wg       sync.WaitGroup
+			received response.RollCall
+		)
+		wg.Add(1)
+
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer wg.Done()
+			defer stream.Close()
+
+			getStreamPayload(t, stream, &received)
+		})
+
+		err = node.processRollCall(context.Background(), receiver.ID(), rollCallReq)
+		require.NoError(t, err)
+
+		wg.Wait()
+
+		require.Equal(t, codes.NotAvailable, received.Code)
+		require.Equal(t, response.RollCallReasonMaintenance, received.Reason)
+	})
+
 	t.Run("worker node handles roll call", func(t *testing.T) {
 		t.Parallel()
 
@@ -53,6 +102,10 @@ func TestNode_RollCall(t *testing.T) {
 			require.Equal(t, rollCallReq.FunctionID, received.FunctionID)
 			require.Equal(t, rollCallReq.RequestID, received.RequestID)
 			require.Equal(t, codes.Accepted, received.Code)
+
+			// Worker reports its load.
+			require.NotNil(t, received.Load)
+			require.Equal(t, node.cfg.Concurrency, received.Load.Concurrency)
 		})
 
 		err = node.processRollCall(context.Background(), receiver.ID(), rollCallReq)
@@ -127,7 +180,9 @@ func TestNode_RollCall(t *testing.T) {
 		fstore.IsInstalledFunc = func(string) (bool, error) {
 			return false, nil
 		}
+		installed := make(chan struct{})
 		fstore.InstallFunc = func(context.Context, string, string) error {
+			<-installed
 			return nil
 		}
 		node.fstore = fstore
@@ -148,14 +203,26 @@ func TestNode_RollCall(t *testing.T) {
 			require.Equal(t, rollCallReq.FunctionID, received.FunctionID)
 			require.Equal(t, rollCallReq.RequestID, received.RequestID)
 			require.Equal(t, codes.Accepted, received.Code)
+			require.NotNil(t, received.Install)
+			require.Equal(t, response.InstallStateInstalling, received.Install.State)
 		})
 
+		// Roll call is answered before the installation completes.
 		err = node.processRollCall(context.Background(), receiver.ID(), rollCallReq)
 		require.NoError(t, err)
 
 		wg.Wait()
+
+		// Execution waits for the installation to complete.
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		err = node.awaitPrefetch(ctx, rollCallReq.FunctionID)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		close(installed)
 	})
-	t.Run("worker node handles function failure to install function on roll call", func(t *testing.T) {
+	t.Run("worker node reports failure to install function on execution", func(t *testing.T) {
 		t.Parallel()
 
 		node := createNode(t, blockless.WorkerNode)
@@ -176,7 +243,9 @@ func TestNode_RollCall(t *testing.T) {
 		fstore.IsInstalledFunc = func(string) (bool, error) {
 			return false, nil
 		}
+		installed := make(chan struct{})
 		fstore.InstallFunc = func(context.Context, string, string) error {
+			<-installed
 			return mocks.GenericError
 		}
 		node.fstore = fstore
@@ -196,13 +265,25 @@ func TestNode_RollCall(t *testing.T) {
 
 			require.Equal(t, rollCallReq.FunctionID, received.FunctionID)
 			require.Equal(t, rollCallReq.RequestID, received.RequestID)
-			require.Equal(t, codes.Error, received.Code)
+			require.Equal(t, codes.Accepted, received.Code)
+			require.NotNil(t, received.Install)
 		})
 
 		err = node.processRollCall(context.Background(), receiver.ID(), rollCallReq)
-		require.Error(t, err)
+		require.NoError(t, err)
 
 		wg.Wait()
+
+		// Installation failure is reported once the execution request arrives.
+		node.prefetches.Lock()
+		install, ok := node.prefetches.installs[rollCallReq.FunctionID]
+		node.prefetches.Unlock()
+		require.True(t, ok)
+
+		close(installed)
+
+		<-install.done
+		require.ErrorIs(t, install.err, mocks.GenericError)
 	})
 	t.Run("node issues roll call ok", func(t *testing.T) {
 		t.Parallel()
@@ -237,8 +318,8 @@ func TestNode_RollCall(t *testing.T) {
 
 		time.Sleep(subscriptionDiseminationPause)
 
-		requestID := newRequestID()
-		err = node.publishRollCall(ctx, requestID, functionID, consensus.Type(0), "", nil)
+		requestID := node.newRequestID()
+		err = node.publishRollCall(ctx, requestID, functionID, consensus.Type(0), "", nil, 0)
 		require.NoError(t, err)
 
 		deadlineCtx, cancel := context.WithTimeout(ctx, publishTimeout)
@@ -260,3 +341,279 @@ func TestNode_RollCall(t *testing.T) {
 		require.Equal(t, requestID, received.RequestID)
 	})
 }
+
+func TestNode_RollCallDuplicateResponses(t *testing.T) {
+
+	const (
+		functionID = "dummy-function-id"
+		requestID  = "dummy-request-id"
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	node := createNode(t, blockless.HeadNode)
+	node.cfg.RollCallTimeout = time.Second
+
+	worker, err := host.New(mocks.NoopLogger, loopback, 0)
+	require.NoError(t, err)
+
+	hostAddNewPeer(t, node.host, worker)
+	err = node.host.Connect(ctx, *hostGetAddrInfo(t, worker))
+	require.NoError(t, err)
+
+	err = node.subscribeToTopics(ctx)
+	require.NoError(t, err)
+
+	type rollCallResult struct {
+		peers []peer.ID
+		err   error
+	}
+	done := make(chan rollCallResult)
+
+	go func() {
+		peers, _, _, err := node.executeRollCall(ctx, requestID, functionID, 2, consensus.Type(0), DefaultTopic, nil, "")
+		done <- rollCallResult{peers: peers, err: err}
+	}()
+
+	require.Eventually(t, func() bool {
+		return node.rollCall.Exists(requestID)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Worker answers the roll call twice.
+	reply := RollCallResponse{
+		From: worker.ID(),
+		RollCall: response.RollCall{
+			Code:       codes.Accepted,
+			FunctionID: functionID,
+			RequestID:  requestID,
+		},
+	}
+	node.rollCall.Add(requestID, reply)
+	node.rollCall.Add(requestID, reply)
+
+	res := <-done
+	require.ErrorIs(t, res.err, b7serrors.ErrRollCallTimeout)
+	require.Empty(t, res.peers)
+
+	// Worker did not keep a dispatch slot for any of the responses.
+	for range node.dispatch.Limit(worker.ID()) {
+		require.True(t, node.dispatch.Acquire(worker.ID()))
+	}
+	require.False(t, node.dispatch.Acquire(worker.ID()))
+}
+
+func TestNode_SelectRollCallPeers(t *testing.T) {
+
+	const functionID = "dummy-function-id"
+
+	var (
+		early  = mocks.GenericPeerIDs[0]
+		late   = mocks.GenericPeerIDs[1]
+		backup = mocks.GenericPeerIDs[2]
+	)
+
+	t.Run("peers with better reputation are preferred", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+		node.dispatch = dispatch.New[peer.ID](1)
+
+		for i := 0; i < 10; i++ {
+			node.reputation.Record(early, reputation.Timeout)
+			node.reputation.Record(late, reputation.Success)
+		}
+
+		require.True(t, node.dispatch.Acquire(early))
+
+		chosen, reserve := node.selectRollCallPeers(functionID, 1, []peer.ID{early}, []peer.ID{backup, late}, nil)
+		require.Equal(t, []peer.ID{late}, chosen)
+		require.Equal(t, []peer.ID{backup, early}, reserve)
+
+		// Replaced peer is released, peer chosen instead counts towards its limit.
+		require.True(t, node.dispatch.Acquire(early))
+		require.False(t, node.dispatch.Acquire(late))
+	})
+	t.Run("peers with lower latency are preferred", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+
+		node.latencies.record(early, 500*time.Millisecond)
+		node.latencies.record(late, 10*time.Millisecond)
+
+		require.True(t, node.dispatch.Acquire(early))
+
+		chosen, reserve := node.selectRollCallPeers(functionID, 1, []peer.ID{early}, []peer.ID{late}, nil)
+		require.Equal(t, []peer.ID{late}, chosen)
+		require.Equal(t, []peer.ID{early}, reserve)
+	})
+	t.Run("less loaded peers are preferred", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+
+		node.loads.record(early, &response.Load{Executions: 10, Concurrency: 10})
+		node.loads.record(late, &response.Load{Executions: 1, Concurrency: 10})
+
+		require.True(t, node.dispatch.Acquire(early))
+
+		chosen, reserve := node.selectRollCallPeers(functionID, 1, []peer.ID{early}, []peer.ID{late}, nil)
+		require.Equal(t, []peer.ID{late}, chosen)
+		require.Equal(t, []peer.ID{early}, reserve)
+	})
+	t.Run("peers at their dispatch limit are not chosen", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+		node.dispatch = dispatch.New[peer.ID](1)
+
+		for i := 0; i < 10; i++ {
+			node.reputation.Record(late, reputation.Success)
+		}
+
+		require.True(t, node.dispatch.Acquire(early))
+		require.True(t, node.dispatch.Acquire(late))
+
+		chosen, reserve := node.selectRollCallPeers(functionID, 1, []peer.ID{early}, []peer.ID{late}, nil)
+		require.Equal(t, []peer.ID{early}, chosen)
+		require.Equal(t, []peer.ID{late}, reserve)
+	})
+	t.Run("custom peer selector chooses peers", func(t *testing.T) {
+		t.Parallel()
+
+		var candidates []RollCallCandidate
+		selector := selectorFunc(func(fid string, count int, c []RollCallCandidate) []peer.ID {
+			require.Equal(t, functionID, fid)
+			require.Equal(t, 2, count)
+			candidates = c
+
+			// Unknown and duplicate peers are ignored.
+			return []peer.ID{mocks.GenericPeerIDs[3], backup, backup, late, early}
+		})
+
+		node := createNode(t, blockless.HeadNode)
+		node.cfg.PeerSelector = selector
+
+		require.True(t, node.dispatch.Acquire(early))
+		require.True(t, node.dispatch.Acquire(late))
+
+		latencies := map[peer.ID]time.Duration{
+			early:  20 * time.Millisecond,
+			late:   10 * time.Millisecond,
+			backup: 30 * time.Millisecond,
+		}
+
+		chosen, reserve := node.selectRollCallPeers(functionID, 2, []peer.ID{early, late}, []peer.ID{backup}, latencies)
+		require.Equal(t, []peer.ID{backup, late}, chosen)
+		require.Equal(t, []peer.ID{early}, reserve)
+
+		require.Len(t, candidates, 3)
+		for _, c := range candidates {
+			require.Equal(t, latencies[c.ID], c.Latency)
+			require.Equal(t, node.reputation.Score(c.ID), c.Reputation)
+		}
+	})
+	t.Run("custom peer selector can choose any number of peers", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+		node.cfg.PeerSelector = selectorFunc(func(_ string, _ int, c []RollCallCandidate) []peer.ID {
+			return []peer.ID{c[1].ID}
+		})
+
+		require.True(t, node.dispatch.Acquire(early))
+
+		chosen, reserve := node.selectRollCallPeers(functionID, -1, []peer.ID{early}, []peer.ID{late, backup}, nil)
+		require.Equal(t, []peer.ID{late}, chosen)
+		require.Equal(t, []peer.ID{early, backup}, reserve)
+	})
+	t.Run("custom peer selector choosing no peers", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+		node.cfg.PeerSelector = selectorFunc(func(string, int, []RollCallCandidate) []peer.ID {
+			return nil
+		})
+
+		require.True(t, node.dispatch.Acquire(early))
+
+		chosen, reserve := node.selectRollCallPeers(functionID, 1, []peer.ID{early}, []peer.ID{late}, nil)
+		require.Empty(t, chosen)
+		require.Equal(t, []peer.ID{early, late}, reserve)
+	})
+}
+
+type selectorFunc func(functionID string, count int, candidates []RollCallCandidate) []peer.ID
+
+func (f selectorFunc) Select(functionID string, count int, candidates []RollCallCandidate) []peer.ID {
+	return f(functionID, count, candidates)
+}
+
+func TestNode_RollCallFallback(t *testing.T) {
+
+	const (
+		functionID = "dummy-function-id"
+		subgroup   = "dummy-subgroup"
+	)
+
+	t.Run("fallback decision", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+		ctx := context.Background()
+
+		require.False(t, node.rollCallFallback(ctx, subgroup))
+
+		node.cfg.RollCallFallback = true
+		require.True(t, node.rollCallFallback(ctx, subgroup))
+		require.False(t, node.rollCallFallback(ctx, ""))
+		require.False(t, node.rollCallFallback(ctx, DefaultTopic))
+		require.False(t, node.rollCallFallback(withoutRollCallFallback(ctx), subgroup))
+	})
+
+	// Head node executing a request in a subgroup without workers. The only worker is on the default topic.
+	run := func(t *testing.T, fallback bool) codes.Code {
+		t.Helper()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		node := createNode(t, blockless.HeadNode)
+		node.cfg.RollCallTimeout = time.Second
+		node.cfg.RollCallFallback = fallback
+
+		fleet, err := simulation.NewFleet(mocks.NoopLogger, 1, loopback, simulation.Profile{}, []string{DefaultTopic})
+		require.NoError(t, err)
+		defer fleet.Close()
+
+		err = fleet.Connect(ctx, *hostGetAddrInfo(t, node.host))
+		require.NoError(t, err)
+
+		err = node.subscribeToTopics(ctx)
+		require.NoError(t, err)
+		node.listenDirectMessages(ctx)
+
+		go fleet.Run(ctx)
+
+		time.Sleep(subscriptionDiseminationPause)
+
+		req := execute.Request{FunctionID: functionID, Method: "dummy-method"}
+		code, _, _, _, _ := node.headExecute(ctx, node.newRequestID(), req, subgroup, nil)
+
+		return code
+	}
+
+	t.Run("roll call falls back to the default topic", func(t *testing.T) {
+		t.Parallel()
+
+		code := run(t, true)
+		require.Equal(t, codes.OK, code)
+	})
+	t.Run("roll call without fallback times out", func(t *testing.T) {
+		t.Parallel()
+
+		code := run(t, false)
+		require.Equal(t, codes.Timeout, code)
+	})
+}
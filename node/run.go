@@ -6,14 +6,16 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"sync"
+	"time"
 
 	"github.com/armon/go-metrics"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/libp2p/go-libp2p/core/network"
 
 	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/node/head/recovery"
 	"github.com/blocklessnetwork/b7s/node/internal/pipeline"
+	"github.com/blocklessnetwork/b7s/node/internal/workqueue"
 )
 
 // Run will start the main loop for the node.
@@ -35,8 +37,51 @@ func (n *Node) Run(ctx context.Context) error {
 		return fmt.Errorf("could not sync functions: %w", err)
 	}
 
+	// Verify the worker can serve executions before taking on any work.
+	if n.isWorker() {
+		err = n.selfTestOnStart(ctx)
+		if err != nil {
+			return fmt.Errorf("startup self-test failed: %w", err)
+		}
+	}
+
 	// Set the handler for direct messages.
 	n.listenDirectMessages(ctx)
+	n.listenInteractiveStreams(ctx)
+
+	if n.isHead() {
+		n.recovery.update(func(r *recovery.Report) {
+			r.Started = time.Now().UTC()
+		})
+
+		// Reach the workers we knew of before accepting executions.
+		n.warm.begin()
+		go n.warmStart(ctx)
+
+		// Abort requests left pending when the head node stopped.
+		go n.abortPendingRequests(ctx)
+
+		// Check execution results kept from the previous run.
+		go n.scanResults()
+
+		// Learn about workers known to our peers, without waiting for the DHT.
+		go n.exchangePeers(ctx)
+
+		// Run recurring executions.
+		go n.runScheduler(ctx)
+
+		// Dispatch deferred executions when their time comes.
+		go n.runDeferredExecutions(ctx)
+
+		// Keep pools of available workers for latency-sensitive functions.
+		go n.runWarmPools(ctx)
+
+		// Probe subgroup health with canary functions.
+		go n.runCanaries(ctx)
+
+		// Move old execution results to the archive.
+		go n.runArchiver(ctx)
+	}
 
 	// Discover peers.
 	// NOTE: Potentially signal any error here so that we abort the node
@@ -56,74 +101,125 @@ func (n *Node) Run(ctx context.Context) error {
 	// Start the health signal emitter in a separate goroutine.
 	go n.HealthPing(ctx)
 
+	// Let head nodes know which functions we have installed and how much work we can take on.
+	if n.isWorker() && n.cfg.FleetReportInterval > 0 {
+		go n.publishFleetReports(ctx)
+	}
+
+	// Shut down clusters that make no progress, e.g. because the message disbanding them was lost.
+	if n.isWorker() && n.cfg.ClusterDeadline > 0 {
+		go n.watchClusters(ctx)
+	}
+
+	// Keep connections to browser-based peers alive.
+	go n.host.KeepAlive(ctx)
+
 	// Start the function sync in the background to periodically check functions.
 	go n.runSyncLoop(ctx)
 
 	n.log.Info().Uint("concurrency", n.cfg.Concurrency).Msg("starting node main loop")
 
-	var workers sync.WaitGroup
-
 	// Process topic messages - spin up a goroutine for each topic that will feed the main processing loop below.
-	// No need for locking since we're still single threaded here and these (subscribed) topics will not be touched by other code.
+	// Workers joining subgroups at runtime start processing their messages the same way.
+	n.subgroups.Lock()
+	n.subgroups.ctx = ctx
 	for name, topic := range n.subgroups.topics {
 
-		workers.Add(1)
+		// Skip topics we only joined to publish to.
+		if topic.subscription == nil {
+			continue
+		}
 
+		n.subgroups.loops.Add(1)
 		go func(name string, subscription *pubsub.Subscription) {
-			defer workers.Done()
+			defer n.subgroups.loops.Done()
+			n.processTopicMessages(ctx, name, subscription)
+		}(name, topic.subscription)
+	}
+	n.subgroups.Unlock()
 
-			// Message processing loops.
-			for {
+	n.subgroups.loops.Wait()
 
-				// Retrieve next message.
-				msg, err := subscription.Next(ctx)
-				if err != nil {
-					// NOTE: Cancelling the context will lead us here.
-					n.log.Error().Err(err).Msg("could not receive message")
-					break
-				}
+	n.log.Debug().Msg("waiting for messages being processed")
+	n.wg.Wait()
 
-				// Skip messages we published.
-				if msg.ReceivedFrom == n.host.ID() {
-					continue
-				}
+	return nil
+}
 
-				n.log.Trace().Str("topic", name).Str("peer", msg.ReceivedFrom.String()).Hex("id", []byte(msg.ID)).Msg("received message")
+// processTopicMessages processes messages received on the topic, until the subscription is cancelled or the context is done.
+func (n *Node) processTopicMessages(ctx context.Context, name string, subscription *pubsub.Subscription) {
 
-				// Try to get a slot for processing the request.
-				n.sema <- struct{}{}
-				n.wg.Add(1)
+	// Message processing loops.
+	for {
 
-				go func(msg *pubsub.Message) {
-					// Free up slot after we're done.
-					defer n.wg.Done()
-					defer func() { <-n.sema }()
+		// Retrieve next message.
+		msg, err := subscription.Next(ctx)
+		if errors.Is(err, pubsub.ErrSubscriptionCancelled) {
+			n.log.Debug().Str("topic", name).Msg("subscription cancelled, no longer receiving messages")
+			break
+		}
+		if err != nil {
+			// NOTE: Cancelling the context will lead us here.
+			n.log.Error().Err(err).Msg("could not receive message")
+			break
+		}
 
-					n.metrics.IncrCounterWithLabels(topicMessagesMetric, 1, []metrics.Label{{Name: "topic", Value: name}})
+		// Skip messages we published.
+		if msg.ReceivedFrom == n.host.ID() {
+			continue
+		}
 
-					err = n.processMessage(ctx, msg.ReceivedFrom, msg.GetData(), pipeline.PubSubPipeline(name))
-					if err != nil {
-						n.log.Error().Err(err).Str("id", msg.ID).Str("peer", msg.ReceivedFrom.String()).Msg("could not process message")
-						return
-					}
+		// Messages are attributed to their author, not to the peer that relayed them to us.
+		// Pubsub messages are signed, so the author cannot be spoofed.
+		from := msg.GetFrom()
 
-				}(msg)
-			}
-		}(name, topic.subscription)
-	}
+		n.log.Trace().Str("topic", name).Stringer("peer", from).Stringer("relayer", msg.ReceivedFrom).Hex("id", []byte(msg.ID)).Msg("received message")
 
-	workers.Wait()
+		// Try to get a slot for processing the request. Messages are dropped once the queue is full.
+		err = n.work.Acquire(ctx, 0)
+		if errors.Is(err, workqueue.ErrFull) {
+			n.log.Warn().Str("topic", name).Stringer("peer", from).Msg("work queue full, dropping message")
+			n.metrics.IncrCounterWithLabels(workQueueRejectedMetric, 1, []metrics.Label{{Name: "source", Value: "topic"}})
+			continue
+		}
+		if err != nil {
+			break
+		}
 
-	n.log.Debug().Msg("waiting for messages being processed")
-	n.wg.Wait()
+		n.wg.Add(1)
 
-	return nil
+		go func(msg *pubsub.Message) {
+			// Free up slot after we're done.
+			defer n.wg.Done()
+			defer n.work.Release()
+
+			n.metrics.IncrCounterWithLabels(topicMessagesMetric, 1, []metrics.Label{{Name: "topic", Value: name}})
+
+			err := n.processMessage(ctx, from, msg.GetData(), pipeline.PubSubPipeline(name))
+			if err != nil {
+				n.log.Error().Err(err).Str("id", msg.ID).Stringer("peer", from).Msg("could not process message")
+				return
+			}
+
+		}(msg)
+	}
 }
 
 // listenDirectMessages will process messages sent directly to the peer (as opposed to published messages).
+// If the node has a data host, messages received on it are processed the same way.
 func (n *Node) listenDirectMessages(ctx context.Context) {
 
-	n.host.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+	handler := n.directMessageHandler(ctx)
+
+	n.host.SetStreamHandler(blockless.ProtocolID, handler)
+	if n.dataHost != nil {
+		n.dataHost.SetStreamHandler(blockless.ProtocolID, handler)
+	}
+}
+
+func (n *Node) directMessageHandler(ctx context.Context) network.StreamHandler {
+
+	return func(stream network.Stream) {
 		defer stream.Close()
 
 		from := stream.Conn().RemotePeer()
@@ -146,5 +242,5 @@ func (n *Node) listenDirectMessages(ctx context.Context) {
 			return
 		}
 
-	})
+	}
 }
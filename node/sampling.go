@@ -0,0 +1,51 @@
+package node
+
+// rollCallProbability returns the chance with which workers should answer a roll call on the topic, so that the head node
+// receives about as many responses as it needs, times the sampling factor. Zero means all workers should answer - which is
+// the case for topics below the sampling threshold, or requests that can use any number of workers.
+func (n *Node) rollCallProbability(topic string, nodeCount int) float64 {
+
+	if n.cfg.SamplingThreshold == 0 || nodeCount < 1 {
+		return 0
+	}
+
+	peers := n.topicPeers(topic)
+	if peers < n.cfg.SamplingThreshold {
+		return 0
+	}
+
+	probability := n.cfg.SamplingFactor * float64(nodeCount) / float64(peers)
+	if probability >= 1 {
+		return 0
+	}
+
+	return probability
+}
+
+// topicPeers returns the number of peers we know are subscribed to the topic.
+func (n *Node) topicPeers(topic string) uint {
+
+	if topic == "" {
+		topic = DefaultTopic
+	}
+
+	n.subgroups.RLock()
+	defer n.subgroups.RUnlock()
+
+	ti, ok := n.subgroups.topics[topic]
+	if !ok {
+		return 0
+	}
+
+	return uint(len(ti.handle.ListPeers()))
+}
+
+// sampled returns true if the worker should answer a roll call sent with the given probability.
+func (n *Node) sampled(probability float64) bool {
+
+	if probability <= 0 || probability >= 1 {
+		return true
+	}
+
+	return n.rand.Chance(probability)
+}
@@ -0,0 +1,109 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestSampled(t *testing.T) {
+
+	const (
+		rounds      = 10_000
+		probability = 0.5
+	)
+
+	node := createNode(t, blockless.WorkerNode)
+
+	require.True(t, node.sampled(0))
+	require.True(t, node.sampled(1))
+
+	var count int
+	for i := 0; i < rounds; i++ {
+		if node.sampled(probability) {
+			count++
+		}
+	}
+
+	require.InDelta(t, probability, float64(count)/rounds, 0.05)
+}
+
+func TestNode_RollCallProbability(t *testing.T) {
+
+	ctx := context.Background()
+
+	node := createNode(t, blockless.HeadNode)
+
+	// Have two peers subscribe to the default topic.
+	for i := 0; i < 2; i++ {
+
+		peer, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		err = peer.InitPubSub(ctx)
+		require.NoError(t, err)
+
+		_, _, err = peer.Subscribe(DefaultTopic)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, peer)
+		err = node.host.Connect(ctx, *hostGetAddrInfo(t, peer))
+		require.NoError(t, err)
+	}
+
+	err := node.subscribeToTopics(ctx)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return node.topicPeers(DefaultTopic) == 2
+	}, subscriptionDiseminationPause, 50*time.Millisecond)
+
+	// Sampling is disabled.
+	require.Zero(t, node.rollCallProbability(DefaultTopic, 1))
+
+	node.cfg.SamplingThreshold = 2
+	node.cfg.SamplingFactor = 1
+
+	require.Equal(t, 0.5, node.rollCallProbability(DefaultTopic, 1))
+	require.Equal(t, 0.5, node.rollCallProbability("", 1))
+
+	// All workers are needed.
+	require.Zero(t, node.rollCallProbability(DefaultTopic, 2))
+	// Any number of workers can execute the request.
+	require.Zero(t, node.rollCallProbability(DefaultTopic, -1))
+	// Unknown topic.
+	require.Zero(t, node.rollCallProbability("dummy-topic", 1))
+
+	// Topic is below the threshold.
+	node.cfg.SamplingThreshold = 3
+	require.Zero(t, node.rollCallProbability(DefaultTopic, 1))
+}
+
+func TestNode_RollCallNotSampled(t *testing.T) {
+
+	node := createNode(t, blockless.WorkerNode)
+
+	fstore := mocks.BaselineFStore(t)
+	fstore.IsInstalledFunc = func(string) (bool, error) {
+		require.FailNow(t, "worker that was not sampled should not answer the roll call")
+		return false, nil
+	}
+	node.fstore = fstore
+
+	req := request.RollCall{
+		FunctionID:  "dummy-function-id",
+		RequestID:   mocks.GenericUUID.String(),
+		Origin:      mocks.GenericPeerID,
+		Probability: 1e-12,
+	}
+
+	err := node.processRollCall(context.Background(), mocks.GenericPeerID, req)
+	require.NoError(t, err)
+}
@@ -0,0 +1,325 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/b7serrors"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/node/head/recovery"
+	"github.com/blocklessnetwork/b7s/node/head/schedule"
+)
+
+var (
+	errUnknownSchedule  = errors.New("unknown schedule")
+	errTooManySchedules = fmt.Errorf("too many execution schedules (max: %v)", maxSchedulesPerPeer)
+)
+
+// scheduledExecution is an execution schedule along with the time of its next activation.
+type scheduledExecution struct {
+	spec schedule.Spec
+	cron schedule.Cron
+	next time.Time
+}
+
+// executionSchedules holds the recurring executions the head node runs.
+type executionSchedules struct {
+	sync.Mutex
+	entries map[string]*scheduledExecution
+}
+
+func newExecutionSchedules() *executionSchedules {
+	return &executionSchedules{
+		entries: make(map[string]*scheduledExecution),
+	}
+}
+
+func (s *executionSchedules) add(spec schedule.Spec, cron schedule.Cron, now time.Time) time.Time {
+	s.Lock()
+	defer s.Unlock()
+
+	entry := scheduledExecution{
+		spec: spec,
+		cron: cron,
+		next: cron.Next(now),
+	}
+
+	s.entries[spec.ID] = &entry
+
+	return entry.next
+}
+
+// owned returns the number of schedules registered by the peer.
+func (s *executionSchedules) owned(owner peer.ID) int {
+	s.Lock()
+	defer s.Unlock()
+
+	var count int
+	for _, entry := range s.entries {
+		if entry.spec.Owner == owner {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (s *executionSchedules) remove(id string) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	_, ok := s.entries[id]
+	delete(s.entries, id)
+
+	return ok
+}
+
+// due returns the schedules that should be executed at the given time, and moves their next activation forward.
+func (s *executionSchedules) due(now time.Time) []scheduledExecution {
+	s.Lock()
+	defer s.Unlock()
+
+	var due []scheduledExecution
+	for _, entry := range s.entries {
+
+		if entry.next.IsZero() || entry.next.After(now) {
+			continue
+		}
+
+		due = append(due, *entry)
+		entry.next = entry.cron.Next(now)
+	}
+
+	return due
+}
+
+// ScheduleExecution registers a recurring execution, run according to the given cron expression.
+// It returns the ID of the schedule and the time of the first execution.
+func (n *Node) ScheduleExecution(req execute.Request, cron string, subgroup string) (string, time.Time, error) {
+	return n.scheduleExecution("", req, cron, subgroup)
+}
+
+// scheduleExecution registers a recurring execution on behalf of the given peer. Schedules not registered by a peer have no owner.
+func (n *Node) scheduleExecution(owner peer.ID, req execute.Request, cron string, subgroup string) (string, time.Time, error) {
+
+	if !n.isHead() {
+		return "", time.Time{}, fmt.Errorf("action not supported on this node type")
+	}
+
+	parsed, err := schedule.Parse(cron)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("could not parse cron expression: %w", err)
+	}
+
+	if owner != "" && n.schedules.owned(owner) >= maxSchedulesPerPeer {
+		return "", time.Time{}, errTooManySchedules
+	}
+
+	spec := schedule.Spec{
+		ID:      n.newRequestID(),
+		Cron:    cron,
+		Request: req,
+		Topic:   subgroup,
+		Owner:   owner,
+		Created: time.Now().UTC(),
+	}
+
+	if n.cfg.Schedules != nil {
+		err = n.cfg.Schedules.Save(spec)
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("could not save schedule: %w", err)
+		}
+	}
+
+	next := n.schedules.add(spec, parsed, time.Now())
+
+	n.log.Info().Str("schedule", spec.ID).Str("function", req.FunctionID).Str("cron", cron).Time("next", next).Msg("execution scheduled")
+
+	return spec.ID, next, nil
+}
+
+// CancelScheduledExecution removes the recurring execution.
+func (n *Node) CancelScheduledExecution(id string) error {
+
+	if !n.schedules.remove(id) {
+		return errUnknownSchedule
+	}
+
+	if n.cfg.Schedules != nil {
+		err := n.cfg.Schedules.Remove(id)
+		if err != nil {
+			return fmt.Errorf("could not remove schedule: %w", err)
+		}
+	}
+
+	n.log.Info().Str("schedule", id).Msg("scheduled execution cancelled")
+
+	return nil
+}
+
+func (n *Node) processScheduleExecution(ctx context.Context, from peer.ID, req request.ScheduleExecution) error {
+
+	// Schedules keep running on the head node indefinitely, so only admins can register them.
+	if !slices.Contains(n.cfg.AdminPeers, from) {
+		n.log.Warn().Str("peer", from.String()).Msg("rejecting execution schedule from peer that is not an admin")
+
+		err := n.send(ctx, from, req.Response(codes.NotPermitted).WithErrorMessage(errors.New("peer is not an admin")))
+		if err != nil {
+			return fmt.Errorf("could not send response: %w", err)
+		}
+		return nil
+	}
+
+	id, next, err := n.scheduleExecution(from, req.Request, req.Cron, req.Topic)
+	if errors.Is(err, errTooManySchedules) {
+		err = n.send(ctx, from, req.Response(codes.TooManyRequests).WithErrorMessage(err))
+		if err != nil {
+			return fmt.Errorf("could not send response: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		n.log.Error().Err(err).Str("peer", from.String()).Str("function", req.FunctionID).Msg("could not schedule execution")
+
+		err = n.send(ctx, from, req.Response(codes.Error).WithErrorMessage(err))
+		if err != nil {
+			return fmt.Errorf("could not send response: %w", err)
+		}
+		return nil
+	}
+
+	res := req.Response(codes.OK)
+	res.ScheduleID = id
+	res.Next = next
+
+	err = n.send(ctx, from, res)
+	if err != nil {
+		return fmt.Errorf("could not send response: %w", err)
+	}
+
+	return nil
+}
+
+// loadSchedules restores the execution schedules saved by a previous run.
+func (n *Node) loadSchedules() error {
+
+	if n.cfg.Schedules == nil {
+		return nil
+	}
+
+	specs, corrupted, err := n.cfg.Schedules.Scan()
+	if err != nil {
+		return fmt.Errorf("could not retrieve schedules: %w", err)
+	}
+
+	for _, c := range corrupted {
+		n.log.Warn().Str("schedule", c.ID).Str("reason", c.Reason).Msg("skipping corrupted execution schedule")
+	}
+
+	var loaded uint
+	now := time.Now()
+	for _, spec := range specs {
+
+		cron, err := schedule.Parse(spec.Cron)
+		if err != nil {
+			n.log.Warn().Err(err).Str("schedule", spec.ID).Str("cron", spec.Cron).Msg("skipping schedule with invalid cron expression")
+			corrupted = append(corrupted, recovery.Corruption{
+				Source: recovery.SourceSchedules,
+				ID:     spec.ID,
+				Reason: fmt.Sprintf("invalid cron expression: %s", err),
+			})
+			continue
+		}
+
+		n.schedules.add(spec, cron, now)
+		loaded++
+	}
+
+	n.log.Info().Uint("count", loaded).Int("corrupted", len(corrupted)).Msg("loaded execution schedules")
+
+	n.recovery.update(func(r *recovery.Report) {
+		r.RecoveredSchedules = loaded
+		r.Corrupted = append(r.Corrupted, corrupted...)
+	})
+
+	return nil
+}
+
+// runScheduler triggers scheduled executions when they are due.
+func (n *Node) runScheduler(ctx context.Context) {
+
+	err := n.loadSchedules()
+	if err != nil {
+		n.log.Error().Err(err).Msg("could not load execution schedules")
+	}
+
+	ticker := time.NewTicker(scheduleCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case now := <-ticker.C:
+			for _, entry := range n.schedules.due(now) {
+				go n.runScheduledExecution(ctx, entry.spec, entry.next)
+			}
+		}
+	}
+}
+
+// runScheduledExecution executes the scheduled request and publishes the results to the schedule result topic.
+func (n *Node) runScheduledExecution(ctx context.Context, spec schedule.Spec, scheduled time.Time) {
+
+	requestID := n.newRequestID()
+
+	log := n.log.With().Str("schedule", spec.ID).Str("request", requestID).Str("function", spec.Request.FunctionID).Logger()
+
+	n.metrics.IncrCounterWithLabels(scheduledExecutionsMetric, 1, []metrics.Label{{Name: "function", Value: spec.Request.FunctionID}})
+
+	// Executions of schedules registered by a peer count towards its quota.
+	if spec.Owner != "" {
+		ctx = withRequester(ctx, spec.Owner)
+	}
+
+	code, results, cluster, timing, err := n.headExecute(ctx, requestID, spec.Request, spec.Topic, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("scheduled execution failed")
+	}
+
+	log.Info().Str("code", code.String()).Msg("scheduled execution complete")
+
+	n.detectAnomalies(requestID, spec.Request.FunctionID, results)
+	n.saveExecutionResults(requestID, results)
+
+	msg := response.ScheduledExecution{
+		ScheduleID: spec.ID,
+		RequestID:  requestID,
+		Code:       code,
+		Results:    results,
+		Cluster:    cluster,
+		Timing:     &timing,
+		Scheduled:  scheduled,
+	}
+
+	// Communicate the reason for failure in these cases.
+	if b7serrors.IsPublic(err) {
+		msg.ErrorMessage = err.Error()
+	}
+
+	err = n.publishToTopic(ctx, n.cfg.ScheduleResultTopic, &msg)
+	if err != nil {
+		log.Error().Err(err).Str("topic", n.cfg.ScheduleResultTopic).Msg("could not publish scheduled execution results")
+	}
+}
@@ -0,0 +1,192 @@
+package node
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/node/head/schedule"
+	"github.com/blocklessnetwork/b7s/testing/helpers"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_ScheduleExecution(t *testing.T) {
+
+	const (
+		cron      = "*/5 * * * *"
+		requestID = "dummy-request-id"
+	)
+
+	t.Run("execution is scheduled and persisted", func(t *testing.T) {
+		t.Parallel()
+
+		db := helpers.InMemoryDB(t)
+		defer db.Close()
+
+		store := schedule.NewStore(db)
+
+		node := createNode(t, blockless.HeadNode)
+		node.cfg.Schedules = store
+
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, receiver)
+		node.cfg.AdminPeers = []peer.ID{receiver.ID()}
+
+		var (
+			wg       sync.WaitGroup
+			received response.ScheduleExecution
+		)
+		wg.Add(1)
+
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer wg.Done()
+			defer stream.Close()
+
+			getStreamPayload(t, stream, &received)
+		})
+
+		req := request.ScheduleExecution{
+			Request:   mocks.GenericExecutionRequest,
+			Cron:      cron,
+			RequestID: requestID,
+		}
+
+		err = node.processScheduleExecution(context.Background(), receiver.ID(), req)
+		require.NoError(t, err)
+
+		wg.Wait()
+
+		require.Equal(t, codes.OK, received.Code)
+		require.Equal(t, requestID, received.RequestID)
+		require.NotEmpty(t, received.ScheduleID)
+		require.True(t, received.Next.After(time.Now()))
+
+		specs, _, err := store.Scan()
+		require.NoError(t, err)
+		require.Len(t, specs, 1)
+		require.Equal(t, received.ScheduleID, specs[0].ID)
+		require.Equal(t, cron, specs[0].Cron)
+		require.Equal(t, receiver.ID(), specs[0].Owner)
+
+		require.NoError(t, node.CancelScheduledExecution(received.ScheduleID))
+		require.ErrorIs(t, node.CancelScheduledExecution(received.ScheduleID), errUnknownSchedule)
+
+		specs, _, err = store.Scan()
+		require.NoError(t, err)
+		require.Empty(t, specs)
+	})
+	t.Run("peers that are not admins cannot schedule executions", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, receiver)
+
+		var (
+			wg       sync.WaitGroup
+			received response.ScheduleExecution
+		)
+		wg.Add(1)
+
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer wg.Done()
+			defer stream.Close()
+
+			getStreamPayload(t, stream, &received)
+		})
+
+		req := request.ScheduleExecution{
+			Request:   mocks.GenericExecutionRequest,
+			Cron:      cron,
+			RequestID: requestID,
+		}
+
+		err = node.processScheduleExecution(context.Background(), receiver.ID(), req)
+		require.NoError(t, err)
+
+		wg.Wait()
+
+		require.Equal(t, codes.NotPermitted, received.Code)
+		require.Empty(t, node.schedules.entries)
+	})
+	t.Run("number of schedules per peer is capped", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			node  = createNode(t, blockless.HeadNode)
+			owner = mocks.GenericPeerIDs[0]
+		)
+
+		for range maxSchedulesPerPeer {
+			_, _, err := node.scheduleExecution(owner, mocks.GenericExecutionRequest, cron, "")
+			require.NoError(t, err)
+		}
+
+		_, _, err := node.scheduleExecution(owner, mocks.GenericExecutionRequest, cron, "")
+		require.ErrorIs(t, err, errTooManySchedules)
+
+		// Other peers are not affected.
+		_, _, err = node.scheduleExecution(mocks.GenericPeerIDs[1], mocks.GenericExecutionRequest, cron, "")
+		require.NoError(t, err)
+	})
+	t.Run("worker does not schedule executions", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.WorkerNode)
+
+		_, _, err := node.ScheduleExecution(mocks.GenericExecutionRequest, cron, "")
+		require.Error(t, err)
+	})
+	t.Run("invalid cron expression is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+
+		_, _, err := node.ScheduleExecution(mocks.GenericExecutionRequest, "* * *", "")
+		require.Error(t, err)
+	})
+}
+
+func TestExecutionSchedules_Due(t *testing.T) {
+
+	cron, err := schedule.Parse("0 * * * *")
+	require.NoError(t, err)
+
+	var (
+		schedules = newExecutionSchedules()
+		start     = time.Date(2024, time.January, 10, 10, 17, 0, 0, time.UTC)
+		spec      = schedule.Spec{ID: "dummy-schedule", Cron: "0 * * * *"}
+	)
+
+	next := schedules.add(spec, cron, start)
+	require.Equal(t, time.Date(2024, time.January, 10, 11, 0, 0, 0, time.UTC), next)
+
+	require.Empty(t, schedules.due(next.Add(-time.Second)))
+
+	due := schedules.due(next)
+	require.Len(t, due, 1)
+	require.Equal(t, spec.ID, due[0].spec.ID)
+	require.Equal(t, next, due[0].next)
+
+	// Schedule is not due again until the next activation.
+	require.Empty(t, schedules.due(next.Add(time.Minute)))
+	require.Len(t, schedules.due(next.Add(time.Hour)), 1)
+
+	require.True(t, schedules.remove(spec.ID))
+	require.Empty(t, schedules.due(next.Add(2*time.Hour)))
+}
@@ -0,0 +1,57 @@
+package node
+
+import (
+	"errors"
+	"time"
+
+	"github.com/blocklessnetwork/b7s-attributes/attributes"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+var errNoPeersSelected = errors.New("no roll called peers selected for execution")
+
+// PeerSelector chooses, out of the peers that reported for the roll call, the workers that execute the request.
+// It allows the head node to use a different selection policy, such as stake-weighted selection or one driven by an external oracle.
+type PeerSelector interface {
+	// Select returns the peers that should execute the request, best first. Count is the number of peers requested,
+	// -1 meaning any number of peers. Peers not returned are kept in reserve, in case some of the selected peers fail.
+	Select(functionID string, count int, candidates []RollCallCandidate) []peer.ID
+}
+
+// RollCallCandidate describes a peer that reported for the roll call.
+type RollCallCandidate struct {
+	ID         peer.ID
+	Attributes *attributes.Attestation // Attributes the peer advertised in its health pings, if any.
+	Latency    time.Duration           // Time it took the peer to respond to the roll call.
+	Reputation float64                 // Score of the peer based on its execution history, between 0 and 1.
+	Load       *response.Load          // Load the peer reported in its latest roll call response, if any.
+}
+
+func (n *Node) rollCallCandidates(peers []peer.ID, latencies map[peer.ID]time.Duration) []RollCallCandidate {
+
+	candidates := make([]RollCallCandidate, 0, len(peers))
+	for _, id := range peers {
+
+		candidate := RollCallCandidate{
+			ID:         id,
+			Latency:    latencies[id],
+			Reputation: n.reputation.Score(id),
+		}
+
+		load, ok := n.loads.get(id)
+		if ok {
+			candidate.Load = &load
+		}
+
+		known, ok := n.peers.get(id)
+		if ok {
+			candidate.Attributes = known.attributes
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates
+}
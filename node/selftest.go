@@ -0,0 +1,322 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+)
+
+const (
+	selfTestTimeout     = time.Minute
+	selfTestPingTimeout = 5 * time.Second
+	selfTestDirName     = "selftest"
+)
+
+// Self-test check names.
+const (
+	selfTestDisk      = "disk-write"
+	selfTestConsensus = "consensus"
+	selfTestInstall   = "function-install"
+	selfTestRuntime   = "runtime"
+	selfTestUninstall = "function-uninstall"
+	selfTestHeads     = "head-reachability"
+)
+
+var (
+	errSelfTestNotSupported = errors.New("self-test is only supported on worker nodes")
+	errSelfTestFailed       = errors.New("one or more self-test checks failed")
+)
+
+// SelfTest describes the function the worker uses to verify its runtime and function store in a self-test.
+type SelfTest struct {
+	FunctionID  string // Function installed and executed during the self-test. If empty, runtime and function store checks are skipped.
+	Method      string // Function method to execute.
+	ManifestURL string // Address of the function manifest. Derived from the function ID if not set.
+}
+
+// SelfTest verifies the worker is able to serve executions - it can write to its workspace, load consensus algorithms,
+// install, execute and remove functions, and reach head nodes.
+func (n *Node) SelfTest(ctx context.Context, test SelfTest) (response.SelfTestReport, error) {
+
+	if !n.isWorker() {
+		return response.SelfTestReport{}, errSelfTestNotSupported
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, selfTestTimeout)
+	defer cancel()
+
+	var checks []response.SelfTestCheck
+	run := func(name string, check func() (string, error)) bool {
+
+		start := time.Now()
+		detail, err := check()
+
+		result := response.SelfTestCheck{
+			Name:     name,
+			Status:   response.SelfTestPassed,
+			Duration: execute.Since(start),
+			Detail:   detail,
+		}
+		if err != nil {
+			result.Status = response.SelfTestFailed
+			result.Detail = err.Error()
+		}
+
+		checks = append(checks, result)
+		return err == nil
+	}
+	skip := func(name string, reason string) {
+		checks = append(checks, response.SelfTestCheck{
+			Name:   name,
+			Status: response.SelfTestSkipped,
+			Detail: reason,
+		})
+	}
+
+	run(selfTestDisk, n.selfTestDisk)
+
+	if noConsensus() {
+		skip(selfTestConsensus, "node built without consensus algorithms")
+	} else {
+		run(selfTestConsensus, n.selfTestConsensus)
+	}
+
+	switch {
+	case test.FunctionID == "":
+		skip(selfTestInstall, "no function set")
+		skip(selfTestRuntime, "no function set")
+		skip(selfTestUninstall, "no function set")
+
+	default:
+		// If we cannot tell whether the function is installed, the installation check will fail too.
+		installed, _ := n.fstore.IsInstalled(test.FunctionID)
+
+		manifestURL := test.ManifestURL
+		if manifestURL == "" {
+			manifestURL = manifestURLFromCID(test.FunctionID)
+		}
+
+		ok := run(selfTestInstall, func() (string, error) {
+			if installed {
+				return "function already installed", nil
+			}
+			return "", n.installFunction(ctx, test.FunctionID, manifestURL)
+		})
+		if !ok {
+			skip(selfTestRuntime, "function not installed")
+			skip(selfTestUninstall, "function not installed")
+			break
+		}
+
+		run(selfTestRuntime, func() (string, error) {
+			return n.selfTestRuntime(ctx, test)
+		})
+
+		if installed {
+			skip(selfTestUninstall, "function was installed before the self-test")
+			break
+		}
+
+		run(selfTestUninstall, func() (string, error) {
+			return "", n.fstore.Uninstall(ctx, test.FunctionID)
+		})
+	}
+
+	run(selfTestHeads, func() (string, error) {
+		return n.selfTestHeads(ctx)
+	})
+
+	report := response.SelfTestReport{
+		Passed: !slices.ContainsFunc(checks, func(c response.SelfTestCheck) bool { return c.Status == response.SelfTestFailed }),
+		Checks: checks,
+	}
+
+	return report, nil
+}
+
+func (n *Node) selfTestDisk() (string, error) {
+
+	dir := filepath.Join(n.cfg.Workspace, selfTestDirName)
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return "", fmt.Errorf("could not create directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	payload := []byte(n.newRequestID())
+	path := filepath.Join(dir, "probe")
+
+	err = os.WriteFile(path, payload, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("could not write file: %w", err)
+	}
+
+	read, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("could not read file: %w", err)
+	}
+
+	if !bytes.Equal(payload, read) {
+		return "", errors.New("file read back does not match the file written")
+	}
+
+	return "", nil
+}
+
+// selfTestConsensus lists the consensus algorithms loaded in this build.
+func (n *Node) selfTestConsensus() (string, error) {
+
+	algorithms := consensus.Registered()
+	if len(algorithms) == 0 {
+		return "", errors.New("no consensus algorithms loaded")
+	}
+
+	names := make([]string, 0, len(algorithms))
+	for _, algorithm := range algorithms {
+		names = append(names, algorithm.String())
+	}
+
+	return strings.Join(names, ", "), nil
+}
+
+func (n *Node) selfTestRuntime(ctx context.Context, test SelfTest) (string, error) {
+
+	req := execute.Request{
+		FunctionID: test.FunctionID,
+		Method:     test.Method,
+	}
+
+	res, err := n.executor.ExecuteFunction(ctx, n.newRequestID(), req)
+	if err != nil {
+		return "", fmt.Errorf("execution failed: %w", err)
+	}
+
+	if res.Code != codes.OK {
+		return "", fmt.Errorf("execution failed (code: %s)", res.Code)
+	}
+
+	return fmt.Sprintf("exit code %d", res.Result.ExitCode), nil
+}
+
+// selfTestHeads pings the head nodes the worker knows of - boot nodes and head nodes it heard from.
+// The check passes if at least one head node is reachable.
+func (n *Node) selfTestHeads(ctx context.Context) (string, error) {
+
+	heads := n.host.BootNodes()
+	for id, p := range n.peers.all() {
+		if p.role == blockless.HeadNode && !slices.Contains(heads, id) {
+			heads = append(heads, id)
+		}
+	}
+
+	if len(heads) == 0 {
+		return "", errors.New("no head nodes known")
+	}
+
+	var (
+		reachable []string
+		errs      []error
+	)
+	for _, id := range heads {
+
+		pctx, cancel := context.WithTimeout(ctx, selfTestPingTimeout)
+		rtt, err := n.host.Ping(pctx, id)
+		cancel()
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("peer %s: %w", id, err))
+			continue
+		}
+
+		reachable = append(reachable, fmt.Sprintf("%s (%v)", id, rtt.Round(time.Millisecond)))
+	}
+
+	if len(reachable) == 0 {
+		return "", fmt.Errorf("no head nodes reachable: %w", errors.Join(errs...))
+	}
+
+	return fmt.Sprintf("%d/%d head nodes reachable: %s", len(reachable), len(heads), strings.Join(reachable, ", ")), nil
+}
+
+// selfTestOnStart runs the self-test configured for startup, and returns an error if any of the checks failed.
+func (n *Node) selfTestOnStart(ctx context.Context) error {
+
+	if n.cfg.SelfTest == nil {
+		return nil
+	}
+
+	report, err := n.SelfTest(ctx, *n.cfg.SelfTest)
+	if err != nil {
+		return fmt.Errorf("could not run self-test: %w", err)
+	}
+
+	for _, check := range report.Checks {
+		n.log.Info().Str("check", check.Name).Str("status", check.Status).Int64("duration_ms", check.Duration).Str("detail", check.Detail).Msg("self-test check")
+	}
+
+	if !report.Passed {
+		return errSelfTestFailed
+	}
+
+	n.log.Info().Msg("self-test passed")
+
+	return nil
+}
+
+func (n *Node) processSelfTest(ctx context.Context, from peer.ID, req request.SelfTest) error {
+
+	log := n.log.With().Str("peer", from.String()).Logger()
+
+	if !slices.Contains(n.cfg.AdminPeers, from) {
+		log.Warn().Msg("rejecting self-test request from peer that is not an admin")
+
+		err := n.send(ctx, from, req.Response(codes.NotPermitted, response.SelfTestReport{}))
+		if err != nil {
+			return fmt.Errorf("could not send response: %w", err)
+		}
+
+		return nil
+	}
+
+	test := SelfTest{
+		FunctionID:  req.FunctionID,
+		Method:      req.Method,
+		ManifestURL: req.ManifestURL,
+	}
+
+	var res *response.SelfTest
+
+	report, err := n.SelfTest(ctx, test)
+	switch {
+	case err != nil:
+		log.Error().Err(err).Msg("could not run self-test")
+		res = req.Response(codes.Error, report).WithErrorMessage(err)
+	case !report.Passed:
+		log.Warn().Msg("self-test failed")
+		res = req.Response(codes.Error, report).WithErrorMessage(errSelfTestFailed)
+	default:
+		res = req.Response(codes.OK, report)
+	}
+
+	err = n.send(ctx, from, res)
+	if err != nil {
+		return fmt.Errorf("could not send response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package node
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_SelfTest(t *testing.T) {
+
+	const (
+		functionID = "dummy-function-id"
+		method     = "dummy-method"
+	)
+
+	statuses := func(report response.SelfTestReport) map[string]string {
+		out := make(map[string]string)
+		for _, check := range report.Checks {
+			out[check.Name] = check.Status
+		}
+		return out
+	}
+
+	// addHead makes the worker aware of a reachable head node.
+	addHead := func(t *testing.T, node *Node) {
+		t.Helper()
+
+		head, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, head)
+		node.peers.update(head.ID(), blockless.HeadNode, nil, false)
+	}
+
+	t.Run("head node cannot self-test", func(t *testing.T) {
+		node := createNode(t, blockless.HeadNode)
+
+		_, err := node.SelfTest(context.Background(), SelfTest{})
+		require.ErrorIs(t, err, errSelfTestNotSupported)
+	})
+	t.Run("function checks are skipped without a function", func(t *testing.T) {
+		node := createNode(t, blockless.WorkerNode)
+		addHead(t, node)
+
+		report, err := node.SelfTest(context.Background(), SelfTest{})
+		require.NoError(t, err)
+		require.True(t, report.Passed)
+
+		checks := statuses(report)
+		require.Equal(t, response.SelfTestPassed, checks[selfTestDisk])
+		require.Equal(t, response.SelfTestSkipped, checks[selfTestInstall])
+		require.Equal(t, response.SelfTestSkipped, checks[selfTestRuntime])
+		require.Equal(t, response.SelfTestSkipped, checks[selfTestUninstall])
+		require.Equal(t, response.SelfTestPassed, checks[selfTestHeads])
+	})
+	t.Run("fails without reachable head nodes", func(t *testing.T) {
+		node := createNode(t, blockless.WorkerNode)
+
+		report, err := node.SelfTest(context.Background(), SelfTest{})
+		require.NoError(t, err)
+		require.False(t, report.Passed)
+		require.Equal(t, response.SelfTestFailed, statuses(report)[selfTestHeads])
+	})
+	t.Run("function is installed, executed and removed", func(t *testing.T) {
+		node := createNode(t, blockless.WorkerNode)
+		addHead(t, node)
+
+		var installed, uninstalled bool
+
+		fstore := mocks.BaselineFStore(t)
+		fstore.IsInstalledFunc = func(string) (bool, error) {
+			return false, nil
+		}
+		fstore.InstallFunc = func(context.Context, string, string) error {
+			installed = true
+			return nil
+		}
+		fstore.UninstallFunc = func(_ context.Context, cid string) error {
+			require.Equal(t, functionID, cid)
+			uninstalled = true
+			return nil
+		}
+		node.fstore = fstore
+
+		executor := mocks.BaselineExecutor(t)
+		executor.ExecFunctionFunc = func(_ context.Context, _ string, req execute.Request) (execute.Result, error) {
+			require.Equal(t, functionID, req.FunctionID)
+			require.Equal(t, method, req.Method)
+			return execute.Result{Code: codes.OK}, nil
+		}
+		node.executor = executor
+
+		report, err := node.SelfTest(context.Background(), SelfTest{FunctionID: functionID, Method: method})
+		require.NoError(t, err)
+		require.True(t, report.Passed)
+
+		checks := statuses(report)
+		require.Equal(t, response.SelfTestPassed, checks[selfTestInstall])
+		require.Equal(t, response.SelfTestPassed, checks[selfTestRuntime])
+		require.Equal(t, response.SelfTestPassed, checks[selfTestUninstall])
+
+		require.True(t, installed)
+		require.True(t, uninstalled)
+	})
+	t.Run("function installed before is not removed", func(t *testing.T) {
+		node := createNode(t, blockless.WorkerNode)
+		addHead(t, node)
+
+		fstore := mocks.BaselineFStore(t)
+		fstore.UninstallFunc = func(context.Context, string) error {
+			require.FailNow(t, "unexpected uninstall")
+			return nil
+		}
+		node.fstore = fstore
+
+		executor := mocks.BaselineExecutor(t)
+		executor.ExecFunctionFunc = func(context.Context, string, execute.Request) (execute.Result, error) {
+			return execute.Result{Code: codes.OK}, nil
+		}
+		node.executor = executor
+
+		report, err := node.SelfTest(context.Background(), SelfTest{FunctionID: functionID, Method: method})
+		require.NoError(t, err)
+		require.True(t, report.Passed)
+		require.Equal(t, response.SelfTestSkipped, statuses(report)[selfTestUninstall])
+	})
+	t.Run("failed install skips execution", func(t *testing.T) {
+		node := createNode(t, blockless.WorkerNode)
+		addHead(t, node)
+
+		fstore := mocks.BaselineFStore(t)
+		fstore.IsInstalledFunc = func(string) (bool, error) {
+			return false, nil
+		}
+		fstore.InstallFunc = func(context.Context, string, string) error {
+			return mocks.GenericError
+		}
+		node.fstore = fstore
+
+		report, err := node.SelfTest(context.Background(), SelfTest{FunctionID: functionID, Method: method})
+		require.NoError(t, err)
+		require.False(t, report.Passed)
+
+		checks := statuses(report)
+		require.Equal(t, response.SelfTestFailed, checks[selfTestInstall])
+		require.Equal(t, response.SelfTestSkipped, checks[selfTestRuntime])
+		require.Equal(t, response.SelfTestSkipped, checks[selfTestUninstall])
+	})
+	t.Run("self-test request from non-admin is rejected", func(t *testing.T) {
+		node := createNode(t, blockless.WorkerNode)
+
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, receiver)
+
+		var (
+			wg       sync.WaitGroup
+			received response.SelfTest
+		)
+		wg.Add(1)
+
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer wg.Done()
+			defer stream.Close()
+
+			getStreamPayload(t, stream, &received)
+		})
+
+		err = node.processSelfTest(context.Background(), receiver.ID(), request.SelfTest{})
+		require.NoError(t, err)
+
+		wg.Wait()
+
+		require.Equal(t, codes.NotPermitted, received.Code)
+		require.Empty(t, received.Report.Checks)
+	})
+}
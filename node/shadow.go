@@ -0,0 +1,130 @@
+package node
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/armon/go-metrics"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+)
+
+// ShadowResult describes the outcome of a shadow execution, compared to the execution it shadowed.
+type ShadowResult struct {
+	RequestID  string            // ID of the shadow execution.
+	FunctionID string            // Function executed by the shadow execution.
+	Subgroup   string            // Subgroup the shadow execution ran in.
+	Code       codes.Code        // Code of the shadow execution.
+	Results    execute.ResultMap // Results of the shadow execution.
+	Matched    bool              // Shadow execution produced the same outputs as the original one.
+}
+
+// ShadowResult returns the outcome of the shadow execution of the given request, if the request was shadowed.
+func (n *Node) ShadowResult(requestID string) (ShadowResult, bool) {
+	return n.shadows.Get(requestID)
+}
+
+// shadowExecutionKey marks shadow executions, which do not count towards client quotas or resource usage.
+type shadowExecutionKey struct{}
+
+func withShadowExecution(ctx context.Context) context.Context {
+	return context.WithValue(ctx, shadowExecutionKey{}, true)
+}
+
+func isShadowExecution(ctx context.Context) bool {
+	shadow, _ := ctx.Value(shadowExecutionKey{}).(bool)
+	return shadow
+}
+
+// shadowFor returns the shadow traffic configured for the function. Function ID `*` applies to functions without a shadow of their own.
+func (n *Node) shadowFor(functionID string) (Shadow, bool) {
+
+	var fallback *Shadow
+	for i, shadow := range n.cfg.Shadows {
+		switch shadow.FunctionID {
+		case functionID:
+			return shadow, true
+		case "*":
+			fallback = &n.cfg.Shadows[i]
+		}
+	}
+
+	if fallback == nil {
+		return Shadow{}, false
+	}
+
+	return *fallback, true
+}
+
+// shadowExecution dispatches a share of the requests for a function to its shadow subgroup or function version. Shadow executions
+// run in the background, once the original execution is done. Their results are compared to the results of the original execution
+// and stored, but are never returned to the client.
+func (n *Node) shadowExecution(ctx context.Context, requestID string, req execute.Request, subgroup string, code codes.Code, results execute.ResultMap) {
+
+	shadow, ok := n.shadowFor(req.FunctionID)
+	if !ok || !n.sampled(shadow.Rate) {
+		return
+	}
+
+	// Shadow executions should not steer where client requests are executed.
+	sreq := req
+	sreq.FunctionID = cmp.Or(shadow.TargetFunctionID, req.FunctionID)
+	sreq.Config.Affinity = ""
+
+	subgroup = cmp.Or(shadow.Subgroup, subgroup)
+
+	// Shadow execution outlives the client request, but not for longer than an execution would take.
+	sctx, cancel := context.WithTimeout(withShadowExecution(context.WithoutCancel(ctx)), n.cfg.RollCallTimeout+n.cfg.ExecutionTimeout)
+
+	go func() {
+		defer cancel()
+
+		shadowID := n.newRequestID()
+
+		log := n.log.With().Str("request", requestID).Str("shadow_request", shadowID).Str("function", sreq.FunctionID).Str("subgroup", subgroup).Logger()
+
+		scode, sresults, _, _, err := n.headExecute(sctx, shadowID, sreq, subgroup, nil)
+		if err != nil {
+			log.Warn().Err(err).Msg("shadow execution failed")
+		}
+
+		matched := scode == code && sameOutputs(results, sresults)
+
+		labels := []metrics.Label{{Name: "function", Value: req.FunctionID}}
+		n.metrics.IncrCounterWithLabels(shadowExecutionsMetric, 1, labels)
+		if !matched {
+			n.metrics.IncrCounterWithLabels(shadowMismatchesMetric, 1, labels)
+		}
+
+		log.Debug().Str("code", scode.String()).Bool("matched", matched).Msg("shadow execution complete")
+
+		n.shadows.Set(requestID, ShadowResult{
+			RequestID:  shadowID,
+			FunctionID: sreq.FunctionID,
+			Subgroup:   subgroup,
+			Code:       scode,
+			Results:    sresults,
+			Matched:    matched,
+		})
+	}()
+}
+
+// sameOutputs returns true if both result sets have the same distinct outputs.
+func sameOutputs(a execute.ResultMap, b execute.ResultMap) bool {
+
+	outputs := func(results execute.ResultMap) []string {
+		out := make([]string, 0, len(results))
+		for _, res := range results {
+			checksum := res.OutputChecksum()
+			if !slices.Contains(out, checksum) {
+				out = append(out, checksum)
+			}
+		}
+		slices.Sort(out)
+		return out
+	}
+
+	return slices.Equal(outputs(a), outputs(b))
+}
@@ -0,0 +1,119 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/node/head/simulation"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_ShadowFor(t *testing.T) {
+
+	node := createNode(t, blockless.HeadNode)
+	node.cfg.Shadows = []Shadow{
+		{FunctionID: "*", Rate: 0.1, Subgroup: "dummy-subgroup"},
+		{FunctionID: "dummy-function-id", Rate: 0.5, TargetFunctionID: "dummy-target-function-id"},
+	}
+
+	shadow, ok := node.shadowFor("dummy-function-id")
+	require.True(t, ok)
+	require.Equal(t, node.cfg.Shadows[1], shadow)
+
+	shadow, ok = node.shadowFor("other-function-id")
+	require.True(t, ok)
+	require.Equal(t, node.cfg.Shadows[0], shadow)
+
+	node.cfg.Shadows = node.cfg.Shadows[1:]
+
+	_, ok = node.shadowFor("other-function-id")
+	require.False(t, ok)
+}
+
+func TestSameOutputs(t *testing.T) {
+
+	result := func(stdout string) execute.NodeResult {
+		return execute.NodeResult{Result: execute.Result{Code: codes.OK, Result: execute.RuntimeOutput{Stdout: stdout}}}
+	}
+
+	var (
+		peers = mocks.GenericPeerIDs
+		as    = result("a")
+		bs    = result("b")
+	)
+
+	require.True(t, sameOutputs(nil, nil))
+	require.True(t, sameOutputs(
+		execute.ResultMap{peers[0]: as},
+		execute.ResultMap{peers[1]: as, peers[2]: as},
+	))
+	require.False(t, sameOutputs(
+		execute.ResultMap{peers[0]: as},
+		execute.ResultMap{peers[0]: bs},
+	))
+	require.False(t, sameOutputs(
+		execute.ResultMap{peers[0]: as},
+		execute.ResultMap{peers[0]: as, peers[1]: bs},
+	))
+	require.False(t, sameOutputs(execute.ResultMap{peers[0]: as}, nil))
+}
+
+func TestNode_ShadowExecution(t *testing.T) {
+
+	const (
+		functionID       = "dummy-function-id"
+		targetFunctionID = "dummy-target-function-id"
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	node := createNode(t, blockless.HeadNode)
+	node.cfg.RollCallTimeout = time.Second
+	node.cfg.Shadows = []Shadow{
+		{FunctionID: functionID, Rate: 1, TargetFunctionID: targetFunctionID},
+	}
+
+	fleet, err := simulation.NewFleet(mocks.NoopLogger, 1, loopback, simulation.Profile{}, []string{DefaultTopic})
+	require.NoError(t, err)
+	defer fleet.Close()
+
+	err = fleet.Connect(ctx, *hostGetAddrInfo(t, node.host))
+	require.NoError(t, err)
+
+	err = node.subscribeToTopics(ctx)
+	require.NoError(t, err)
+	node.listenDirectMessages(ctx)
+
+	go fleet.Run(ctx)
+
+	time.Sleep(subscriptionDiseminationPause)
+
+	req := execute.Request{FunctionID: functionID, Method: "dummy-method"}
+	code, requestID, results, _, _, err := node.ExecuteFunction(ctx, req, "")
+	require.NoError(t, err)
+	require.Equal(t, codes.OK, code)
+	require.Len(t, results, 1)
+
+	wctx, wcancel := context.WithTimeout(ctx, 5*time.Second)
+	defer wcancel()
+
+	shadow, ok := node.shadows.WaitFor(wctx, requestID)
+	require.True(t, ok)
+	require.NotEqual(t, requestID, shadow.RequestID)
+	require.Equal(t, targetFunctionID, shadow.FunctionID)
+	require.Equal(t, codes.OK, shadow.Code)
+	require.Len(t, shadow.Results, 1)
+	require.True(t, shadow.Matched)
+
+	// Shadow results are not returned as the results of the request.
+	stored, ok := node.ExecutionResult(requestID)
+	require.True(t, ok)
+	require.Equal(t, results, stored)
+}
@@ -0,0 +1,50 @@
+package node
+
+import (
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// watchSlowHandler starts a watchdog for the handler of a message. If the handler is still running once the slow handler
+// threshold expires, the watchdog logs the stack traces of all goroutines, showing where the handler is stuck.
+// Returned function should be called once the handler is done.
+func (n *Node) watchSlowHandler(from peer.ID, msgType string) func() {
+
+	threshold := n.cfg.SlowHandlerThreshold
+	if threshold <= 0 {
+		return func() {}
+	}
+
+	var (
+		start = time.Now()
+		slow  atomic.Bool
+	)
+
+	timer := time.AfterFunc(threshold, func() {
+		slow.Store(true)
+
+		n.metrics.IncrCounterWithLabels(messagesSlowMetric, 1, []metrics.Label{{Name: "type", Value: msgType}})
+
+		stack := make([]byte, slowHandlerStackSize)
+		stack = stack[:runtime.Stack(stack, true)]
+
+		n.log.Warn().
+			Stringer("peer", from).
+			Str("type", msgType).
+			Dur("threshold", threshold).
+			Str("stack", string(stack)).
+			Msg("message handler exceeded time budget")
+	})
+
+	return func() {
+		timer.Stop()
+
+		if slow.Load() {
+			n.log.Warn().Stringer("peer", from).Str("type", msgType).Dur("duration", time.Since(start)).Msg("slow message handler done")
+		}
+	}
+}
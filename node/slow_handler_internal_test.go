@@ -0,0 +1,85 @@
+package node
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+// syncBuffer is a buffer safe for the concurrent writes done by the watchdog.
+type syncBuffer struct {
+	sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.Lock()
+	defer b.Unlock()
+	return b.buf.String()
+}
+
+func TestNode_WatchSlowHandler(t *testing.T) {
+
+	const threshold = 20 * time.Millisecond
+
+	peer := mocks.GenericPeerID
+
+	t.Run("slow handler is logged", func(t *testing.T) {
+		var logs syncBuffer
+
+		node := createNode(t, blockless.HeadNode)
+		node.log = zerolog.New(&logs)
+		node.cfg.SlowHandlerThreshold = threshold
+
+		done := node.watchSlowHandler(peer, blockless.MessageExecute)
+		require.Eventually(t, func() bool {
+			return strings.Contains(logs.String(), "message handler exceeded time budget")
+		}, time.Second, threshold)
+		done()
+
+		out := logs.String()
+		require.Contains(t, out, blockless.MessageExecute)
+		require.Contains(t, out, "goroutine")
+		require.Contains(t, out, "slow message handler done")
+	})
+	t.Run("fast handler is not logged", func(t *testing.T) {
+		var logs syncBuffer
+
+		node := createNode(t, blockless.HeadNode)
+		node.log = zerolog.New(&logs)
+		node.cfg.SlowHandlerThreshold = threshold
+
+		done := node.watchSlowHandler(peer, blockless.MessageExecute)
+		done()
+
+		time.Sleep(2 * threshold)
+		require.Empty(t, logs.String())
+	})
+	t.Run("detection disabled", func(t *testing.T) {
+		var logs syncBuffer
+
+		node := createNode(t, blockless.HeadNode)
+		node.log = zerolog.New(&logs)
+		node.cfg.SlowHandlerThreshold = 0
+
+		done := node.watchSlowHandler(peer, blockless.MessageExecute)
+		time.Sleep(2 * threshold)
+		done()
+
+		require.Empty(t, logs.String())
+	})
+}
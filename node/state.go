@@ -0,0 +1,44 @@
+package node
+
+import (
+	"context"
+
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/node/internal/waitmap"
+)
+
+var _ ResultStore = (*waitmap.WaitMap[string, execute.ResultMap])(nil)
+
+// ResultStore keeps execution results and allows waiting for results that did not arrive yet.
+// By default results are kept in memory. Head nodes sharing a store backed by an external service
+// can serve results of executions started by other head nodes.
+type ResultStore interface {
+	// Set records the results for the given key, notifying anyone waiting for them.
+	Set(key string, results execute.ResultMap)
+
+	// Get returns the results for the given key, if any.
+	Get(key string) (execute.ResultMap, bool)
+
+	// WaitFor waits until the results for the given key are available, or the context is cancelled.
+	WaitFor(ctx context.Context, key string) (execute.ResultMap, bool)
+}
+
+// RollCallStore records responses to roll calls in progress.
+// By default responses are kept in memory. Head nodes sharing a store backed by an external service
+// can accept roll call responses for roll calls issued by other head nodes.
+type RollCallStore interface {
+	// Create prepares the store for responses to the roll call for the given request.
+	Create(requestID string)
+
+	// Add records a response to the roll call for the given request.
+	Add(requestID string, res RollCallResponse)
+
+	// Exists returns true if there is a roll call in progress for the given request.
+	Exists(requestID string) bool
+
+	// Responses returns a channel with the responses to the roll call for the given request.
+	Responses(requestID string) <-chan RollCallResponse
+
+	// Remove removes the roll call for the given request.
+	Remove(requestID string)
+}
@@ -0,0 +1,69 @@
+package node
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/node/internal/dispatch"
+)
+
+var (
+	errNoPeersSampled        = errors.New("no roll called peers in the requested strata")
+	errSamplingWithConsensus = errors.New("sampled executions cannot use consensus")
+)
+
+// stratifiedSample chooses the workers executing a sampled request out of the peers that reported for the roll call, grouped by stratum.
+// Peers are taken in the order of preference, until each stratum has enough of them. Peers not in the sample no longer count towards
+// their dispatch limit. Nothing is kept in reserve, since a peer replacing a failed one could be from a different stratum.
+func (n *Node) stratifiedSample(sampling execute.Sampling, chosen []peer.ID, reserve []peer.ID) ([]peer.ID, map[string][]peer.ID) {
+
+	var (
+		sampled []peer.ID
+		strata  = make(map[string][]peer.ID)
+	)
+	for _, peer := range append(slices.Clone(chosen), reserve...) {
+
+		stratum, ok := n.peerAttribute(peer, sampling.Attribute)
+		take := ok &&
+			(len(sampling.Strata) == 0 || slices.Contains(sampling.Strata, stratum)) &&
+			len(strata[stratum]) < sampling.Size()
+
+		// Peers chosen earlier already count towards their dispatch limit.
+		wasChosen := slices.Contains(chosen, peer)
+		if take && !wasChosen {
+			take = n.dispatch.Acquire(peer)
+		}
+
+		if !take {
+			if wasChosen {
+				n.dispatch.Release(peer, dispatch.Aborted)
+			}
+			continue
+		}
+
+		strata[stratum] = append(strata[stratum], peer)
+		sampled = append(sampled, peer)
+	}
+
+	return sampled, strata
+}
+
+// peerAttribute returns the value of the attribute the peer advertised in its health pings.
+func (n *Node) peerAttribute(id peer.ID, name string) (string, bool) {
+
+	known, ok := n.peers.get(id)
+	if !ok || known.attributes == nil {
+		return "", false
+	}
+
+	for _, attr := range known.attributes.Attributes {
+		if attr.Name == name {
+			return attr.Value, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,125 @@
+package node
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blocklessnetwork/b7s-attributes/attributes"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/node/head/journal"
+	"github.com/blocklessnetwork/b7s/node/internal/dispatch"
+	"github.com/blocklessnetwork/b7s/testing/helpers"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_StratifiedSample(t *testing.T) {
+
+	var (
+		euFirst  = mocks.GenericPeerIDs[0]
+		euSecond = mocks.GenericPeerIDs[1]
+		us       = mocks.GenericPeerIDs[2]
+		unknown  = mocks.GenericPeerIDs[3]
+		asia     = mocks.GenericPeerIDs[4]
+	)
+
+	// Create a head node knowing the regions of the workers. All workers but the one in reserve count towards their dispatch limit.
+	setup := func(t *testing.T) *Node {
+		t.Helper()
+
+		node := createNode(t, blockless.HeadNode)
+		node.dispatch = dispatch.New[peer.ID](1)
+
+		regions := map[peer.ID]string{
+			euFirst:  "eu",
+			euSecond: "eu",
+			us:       "us",
+			asia:     "asia",
+		}
+		for id, region := range regions {
+			att := attributes.Attestation{
+				Attributes: []attributes.Attribute{{Name: "region", Value: region}},
+			}
+			node.peers.update(id, blockless.WorkerNode, &att, false)
+		}
+
+		for _, id := range []peer.ID{euFirst, euSecond, us, unknown} {
+			require.True(t, node.dispatch.Acquire(id))
+		}
+
+		return node
+	}
+
+	var (
+		chosen  = []peer.ID{euFirst, euSecond, us, unknown}
+		reserve = []peer.ID{asia}
+	)
+
+	t.Run("workers are sampled from each stratum", func(t *testing.T) {
+		t.Parallel()
+
+		node := setup(t)
+
+		sampled, strata := node.stratifiedSample(execute.Sampling{Attribute: "region"}, chosen, reserve)
+		require.Equal(t, []peer.ID{euFirst, us, asia}, sampled)
+		require.Equal(t, map[string][]peer.ID{"eu": {euFirst}, "us": {us}, "asia": {asia}}, strata)
+
+		// Workers left out of the sample are released, and sampled reserve workers count towards their dispatch limit.
+		require.True(t, node.dispatch.Acquire(euSecond))
+		require.True(t, node.dispatch.Acquire(unknown))
+		require.False(t, node.dispatch.Acquire(asia))
+	})
+	t.Run("strata have the requested number of workers", func(t *testing.T) {
+		t.Parallel()
+
+		node := setup(t)
+
+		sampled, strata := node.stratifiedSample(execute.Sampling{Attribute: "region", PerStratum: 2, Strata: []string{"eu"}}, chosen, reserve)
+		require.Equal(t, []peer.ID{euFirst, euSecond}, sampled)
+		require.Equal(t, map[string][]peer.ID{"eu": {euFirst, euSecond}}, strata)
+
+		require.True(t, node.dispatch.Acquire(us))
+		require.True(t, node.dispatch.Acquire(asia))
+	})
+	t.Run("no workers with the attribute", func(t *testing.T) {
+		t.Parallel()
+
+		node := setup(t)
+
+		sampled, strata := node.stratifiedSample(execute.Sampling{Attribute: "gpu.model"}, chosen, reserve)
+		require.Empty(t, sampled)
+		require.Empty(t, strata)
+	})
+}
+
+func TestNode_SampledExecutionWithConsensus(t *testing.T) {
+
+	db := helpers.InMemoryDB(t)
+	defer db.Close()
+
+	node := createNode(t, blockless.HeadNode)
+	node.cfg.Journal = journal.New(db)
+
+	req := mocks.GenericExecutionRequest
+	req.Config.ConsensusAlgorithm = consensus.Raft.String()
+	req.Config.Sampling = &execute.Sampling{Attribute: "region"}
+
+	requestID := node.newRequestID()
+	node.journalUpdate(requestID, func(e *journal.Entry) {
+		e.Origin = mocks.GenericPeerID
+	})
+
+	code, _, _, _, err := node.headExecute(context.Background(), requestID, req, "", nil)
+	require.ErrorIs(t, err, errSamplingWithConsensus)
+	require.Equal(t, codes.Invalid, code)
+
+	// Rejected request is no longer in the journal.
+	entries, _, err := node.cfg.Journal.Scan()
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
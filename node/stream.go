@@ -0,0 +1,87 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/request"
+)
+
+// resultStream describes a client that wants execution results forwarded as they arrive.
+type resultStream struct {
+	client   peer.ID
+	request  request.Execute
+	received uint
+}
+
+// startResultStream registers the client as the recipient of results for the given request.
+func (n *Node) startResultStream(requestID string, client peer.ID, req request.Execute) {
+
+	n.streamLock.Lock()
+	defer n.streamLock.Unlock()
+
+	n.streams[requestID] = &resultStream{
+		client:  client,
+		request: req,
+	}
+}
+
+func (n *Node) stopResultStream(requestID string) {
+
+	n.streamLock.Lock()
+	defer n.streamLock.Unlock()
+
+	delete(n.streams, requestID)
+}
+
+// streamResults forwards the execution results to the client, if the client requested results streaming.
+// Only results sent by, and produced by, peers chosen for the execution are forwarded - results from other peers
+// are not part of the final execution response either.
+// NOTE: Results are forwarded as received - the final execution response remains authoritative.
+func (n *Node) streamResults(ctx context.Context, from peer.ID, requestID string, results execute.ResultMap) error {
+
+	n.streamLock.Lock()
+	_, ok := n.streams[requestID]
+	n.streamLock.Unlock()
+	if !ok {
+		return nil
+	}
+
+	peers, ok := n.executions.peers(requestID)
+	if !ok || !slices.Contains(peers, from) {
+		n.log.Debug().Str("request", requestID).Stringer("from", from).Msg("not streaming results from peer not chosen for the execution")
+		return nil
+	}
+
+	chosen := make(execute.ResultMap, len(results))
+	for executingPeer, result := range results {
+		if slices.Contains(peers, executingPeer) {
+			chosen[executingPeer] = result
+		}
+	}
+	if len(chosen) == 0 {
+		return nil
+	}
+
+	n.streamLock.Lock()
+	stream, ok := n.streams[requestID]
+	if !ok {
+		n.streamLock.Unlock()
+		return nil
+	}
+
+	stream.received += uint(len(chosen))
+	msg := stream.request.PartialResponse(chosen, stream.received)
+	n.streamLock.Unlock()
+
+	err := n.send(ctx, stream.client, msg)
+	if err != nil {
+		return fmt.Errorf("could not send partial execution response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package node
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_StreamResults(t *testing.T) {
+
+	const (
+		requestID       = "dummy-request-id"
+		clientRequestID = "dummy-client-request-id"
+	)
+
+	var (
+		worker  = mocks.GenericPeerIDs[0]
+		results = execute.ResultMap{
+			worker: {
+				Result: execute.Result{
+					Code: codes.OK,
+					Result: execute.RuntimeOutput{
+						Stdout: "dummy-execution-result",
+					},
+				},
+			},
+		}
+	)
+
+	workerResponse := response.Execute{
+		RequestID: requestID,
+		Code:      codes.OK,
+		Results:   results,
+	}
+
+	t.Run("results are forwarded to the client", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+
+		// Create a host that will receive the partial execution response.
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, receiver)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer wg.Done()
+			defer stream.Close()
+
+			var received response.ExecutePartial
+			getStreamPayload(t, stream, &received)
+
+			require.Equal(t, clientRequestID, received.RequestID)
+			require.Equal(t, uint(1), received.Received)
+			require.Equal(t, results[worker].Result.Result, received.Results[worker].Result.Result)
+		})
+
+		node.executions.start(requestID, "dummy-function-id", 0, []peer.ID{worker}, nil, "")
+		node.startResultStream(requestID, receiver.ID(), request.Execute{RequestID: clientRequestID, Stream: true})
+		defer node.stopResultStream(requestID)
+
+		err = node.processExecuteResponse(context.Background(), worker, workerResponse)
+		require.NoError(t, err)
+
+		wg.Wait()
+	})
+	t.Run("results from peers not chosen for the execution are not forwarded", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, receiver)
+
+		var (
+			lock     sync.Mutex
+			received []response.ExecutePartial
+		)
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer stream.Close()
+
+			var msg response.ExecutePartial
+			getStreamPayload(t, stream, &msg)
+
+			lock.Lock()
+			defer lock.Unlock()
+			received = append(received, msg)
+		})
+
+		chosen := mocks.GenericPeerIDs[1]
+		node.executions.start(requestID, "dummy-function-id", 0, []peer.ID{chosen}, nil, "")
+		node.startResultStream(requestID, receiver.ID(), request.Execute{RequestID: clientRequestID, Stream: true})
+		defer node.stopResultStream(requestID)
+
+		// Peer not chosen for the execution.
+		err = node.streamResults(context.Background(), worker, requestID, results)
+		require.NoError(t, err)
+
+		// Chosen peer sending results of a peer not chosen for the execution.
+		err = node.streamResults(context.Background(), chosen, requestID, results)
+		require.NoError(t, err)
+
+		// Streaming is asynchronous, so give any unexpected message time to arrive.
+		time.Sleep(100 * time.Millisecond)
+
+		lock.Lock()
+		defer lock.Unlock()
+		require.Empty(t, received)
+	})
+	t.Run("results from reserve peers are forwarded", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, receiver)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer wg.Done()
+			defer stream.Close()
+
+			var received response.ExecutePartial
+			getStreamPayload(t, stream, &received)
+
+			require.Contains(t, received.Results, worker)
+		})
+
+		node.executions.start(requestID, "dummy-function-id", 0, []peer.ID{mocks.GenericPeerIDs[1]}, nil, "")
+		node.executions.addPeer(requestID, worker)
+		node.startResultStream(requestID, receiver.ID(), request.Execute{RequestID: clientRequestID, Stream: true})
+		defer node.stopResultStream(requestID)
+
+		err = node.streamResults(context.Background(), worker, requestID, results)
+		require.NoError(t, err)
+
+		wg.Wait()
+	})
+	t.Run("results are not forwarded without a stream", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+
+		err := node.processExecuteResponse(context.Background(), worker, workerResponse)
+		require.NoError(t, err)
+
+		res, ok := node.executeResponses.Get(executionResultKey(requestID, worker))
+		require.True(t, ok)
+		require.Equal(t, results, res)
+	})
+}
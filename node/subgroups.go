@@ -1,8 +1,23 @@
 package node
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"sync"
+
+	"github.com/armon/go-metrics"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/request"
+)
+
+var (
+	errSubgroupsNotSupported = errors.New("joining and leaving subgroups at runtime is only supported on worker nodes")
+	errNodeNotRunning        = errors.New("node is not running")
+	errLeaveDefaultTopic     = errors.New("default topic cannot be left")
 )
 
 // Subgroups are (optional) groups of nodes that can work on specific things.
@@ -12,6 +27,11 @@ import (
 type workSubgroups struct {
 	*sync.RWMutex
 	topics map[string]*topicInfo
+
+	// ctx is the context of the node main loop, set once it starts processing topic messages. Topics joined at runtime are processed until it is done.
+	ctx context.Context
+	// loops tracks the goroutines processing messages of the subscribed topics.
+	loops *sync.WaitGroup
 }
 
 // wrapper around topic joining + housekeeping.
@@ -34,3 +54,174 @@ func (n *Node) joinTopic(topic string) (*topicInfo, error) {
 
 	return ti, nil
 }
+
+// JoinSubgroup subscribes the worker to the topic at runtime, so it starts answering roll calls published there.
+// Joining a topic the worker is already subscribed to is a no-op.
+func (n *Node) JoinSubgroup(topic string) error {
+
+	if !n.isWorker() {
+		return errSubgroupsNotSupported
+	}
+
+	n.subgroups.Lock()
+	defer n.subgroups.Unlock()
+
+	if n.subgroups.ctx == nil || n.subgroups.ctx.Err() != nil {
+		return errNodeNotRunning
+	}
+
+	ti, ok := n.subgroups.topics[topic]
+	if ok && ti.subscription != nil {
+		return nil
+	}
+
+	// We may have joined the topic, without subscribing, to publish messages to it.
+	if !ok {
+		th, err := n.host.JoinTopic(topic)
+		if err != nil {
+			return fmt.Errorf("could not join topic (topic: %s): %w", topic, err)
+		}
+
+		ti = &topicInfo{
+			handle: th,
+		}
+		n.subgroups.topics[topic] = ti
+	}
+
+	subscription, err := ti.handle.Subscribe()
+	if err != nil {
+		return fmt.Errorf("could not subscribe to topic (topic: %s): %w", topic, err)
+	}
+
+	ti.subscription = subscription
+
+	// Message processing stops once the subscription is cancelled. Peer discovery has to be stopped explicitly.
+	n.subgroups.loops.Add(1)
+	go func(ctx context.Context) {
+		defer n.subgroups.loops.Done()
+		n.processTopicMessages(ctx, topic, subscription)
+	}(n.subgroups.ctx)
+
+	ctx, cancel := context.WithCancel(n.subgroups.ctx)
+	ti.cancel = cancel
+
+	go func() {
+		err := n.host.DiscoverPeers(ctx, topic)
+		if err != nil && ctx.Err() == nil {
+			n.log.Error().Err(err).Str("topic", topic).Msg("could not discover peers")
+		}
+	}()
+
+	n.metrics.IncrCounterWithLabels(subgroupChangesMetric, 1, []metrics.Label{{Name: "action", Value: "join"}})
+	n.log.Info().Str("topic", topic).Msg("joined subgroup")
+
+	return nil
+}
+
+// LeaveSubgroup unsubscribes the worker from the topic at runtime, so it no longer answers roll calls published there.
+// Work the worker signed up for is not affected. Leaving a topic the worker is not subscribed to is a no-op.
+func (n *Node) LeaveSubgroup(topic string) error {
+
+	if !n.isWorker() {
+		return errSubgroupsNotSupported
+	}
+
+	if topic == DefaultTopic {
+		return errLeaveDefaultTopic
+	}
+
+	n.subgroups.Lock()
+	defer n.subgroups.Unlock()
+
+	ti, ok := n.subgroups.topics[topic]
+	if !ok || ti.subscription == nil {
+		return nil
+	}
+
+	// Keep the topic handle, in case we publish to the topic or join it again.
+	ti.subscription.Cancel()
+	ti.subscription = nil
+
+	if ti.cancel != nil {
+		ti.cancel()
+		ti.cancel = nil
+	}
+
+	n.metrics.IncrCounterWithLabels(subgroupChangesMetric, 1, []metrics.Label{{Name: "action", Value: "leave"}})
+	n.log.Info().Str("topic", topic).Msg("left subgroup")
+
+	return nil
+}
+
+// Subgroups returns the topics the node is subscribed to, sorted by name.
+func (n *Node) Subgroups() []string {
+
+	n.subgroups.RLock()
+	defer n.subgroups.RUnlock()
+
+	topics := make([]string, 0, len(n.subgroups.topics))
+	for name, ti := range n.subgroups.topics {
+		if ti.subscription != nil {
+			topics = append(topics, name)
+		}
+	}
+	slices.Sort(topics)
+
+	return topics
+}
+
+func (n *Node) processSubgroups(ctx context.Context, from peer.ID, req request.Subgroups) error {
+
+	log := n.log.With().Str("peer", from.String()).Strs("join", req.Join).Strs("leave", req.Leave).Logger()
+
+	if !slices.Contains(n.cfg.AdminPeers, from) {
+		log.Warn().Msg("rejecting subgroup change from peer that is not an admin")
+
+		err := n.send(ctx, from, req.Response(codes.NotPermitted))
+		if err != nil {
+			return fmt.Errorf("could not send response: %w", err)
+		}
+
+		return nil
+	}
+
+	err := n.changeSubgroups(req.Join, req.Leave)
+	res := req.Response(codes.OK)
+	if err != nil {
+		log.Error().Err(err).Msg("could not change subgroups")
+
+		code := codes.Error
+		if errors.Is(err, errLeaveDefaultTopic) {
+			code = codes.Invalid
+		}
+
+		res = req.Response(code).WithErrorMessage(err)
+	}
+
+	err = n.send(ctx, from, res.WithTopics(n.Subgroups()))
+	if err != nil {
+		return fmt.Errorf("could not send response: %w", err)
+	}
+
+	return nil
+}
+
+// changeSubgroups joins and then leaves the given topics, stopping at the first failure.
+func (n *Node) changeSubgroups(join []string, leave []string) error {
+
+	for _, topic := range join {
+		err := n.JoinSubgroup(topic)
+		if err != nil {
+			return fmt.Errorf("could not join subgroup (topic: %s): %w", topic, err)
+		}
+	}
+
+	for _, topic := range leave {
+		err := n.LeaveSubgroup(topic)
+		if err != nil {
+			return fmt.Errorf("could not leave subgroup (topic: %s): %w", topic, err)
+		}
+	}
+
+	return nil
+}
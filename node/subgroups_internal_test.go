@@ -0,0 +1,142 @@
+package node
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_Subgroups(t *testing.T) {
+
+	const subgroup = "dummy-subgroup"
+
+	// Create a worker subscribed to the default topic, processing topic messages until the test is done.
+	setup := func(t *testing.T) *Node {
+		t.Helper()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+
+		node := createNode(t, blockless.WorkerNode)
+
+		err := node.subscribeToTopics(ctx)
+		require.NoError(t, err)
+
+		node.subgroups.ctx = ctx
+
+		return node
+	}
+
+	t.Run("worker joins and leaves subgroups", func(t *testing.T) {
+		t.Parallel()
+
+		node := setup(t)
+		require.Equal(t, []string{DefaultTopic}, node.Subgroups())
+
+		err := node.JoinSubgroup(subgroup)
+		require.NoError(t, err)
+		require.Equal(t, []string{DefaultTopic, subgroup}, node.Subgroups())
+
+		// Joining again is a no-op.
+		err = node.JoinSubgroup(subgroup)
+		require.NoError(t, err)
+
+		err = node.LeaveSubgroup(subgroup)
+		require.NoError(t, err)
+		require.Equal(t, []string{DefaultTopic}, node.Subgroups())
+
+		// Topic can be joined again after leaving it.
+		err = node.JoinSubgroup(subgroup)
+		require.NoError(t, err)
+		require.Equal(t, []string{DefaultTopic, subgroup}, node.Subgroups())
+	})
+	t.Run("default topic cannot be left", func(t *testing.T) {
+		t.Parallel()
+
+		node := setup(t)
+
+		err := node.LeaveSubgroup(DefaultTopic)
+		require.ErrorIs(t, err, errLeaveDefaultTopic)
+		require.Equal(t, []string{DefaultTopic}, node.Subgroups())
+	})
+	t.Run("subgroups cannot be joined before the node runs", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.WorkerNode)
+
+		err := node.JoinSubgroup(subgroup)
+		require.ErrorIs(t, err, errNodeNotRunning)
+	})
+	t.Run("head node does not change subgroups at runtime", func(t *testing.T) {
+		t.Parallel()
+
+		node := createNode(t, blockless.HeadNode)
+
+		err := node.JoinSubgroup(subgroup)
+		require.ErrorIs(t, err, errSubgroupsNotSupported)
+
+		err = node.LeaveSubgroup(subgroup)
+		require.ErrorIs(t, err, errSubgroupsNotSupported)
+	})
+	t.Run("subgroup changes are limited to admins", func(t *testing.T) {
+		t.Parallel()
+
+		node := setup(t)
+
+		admin, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, admin)
+
+		change := func(t *testing.T, from peer.ID, req request.Subgroups) response.Subgroups {
+			t.Helper()
+
+			var (
+				wg       sync.WaitGroup
+				received response.Subgroups
+			)
+			wg.Add(1)
+
+			admin.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+				defer wg.Done()
+				defer stream.Close()
+
+				getStreamPayload(t, stream, &received)
+			})
+
+			err = node.processSubgroups(context.Background(), from, req)
+			require.NoError(t, err)
+
+			wg.Wait()
+
+			return received
+		}
+
+		req := request.Subgroups{Join: []string{subgroup}}
+
+		received := change(t, admin.ID(), req)
+		require.Equal(t, codes.NotPermitted, received.Code)
+		require.Equal(t, []string{DefaultTopic}, node.Subgroups())
+
+		node.cfg.AdminPeers = []peer.ID{admin.ID()}
+
+		received = change(t, admin.ID(), req)
+		require.Equal(t, codes.OK, received.Code)
+		require.Equal(t, []string{DefaultTopic, subgroup}, received.Topics)
+
+		received = change(t, admin.ID(), request.Subgroups{Leave: []string{subgroup, DefaultTopic}})
+		require.Equal(t, codes.Invalid, received.Code)
+		require.Equal(t, []string{DefaultTopic}, received.Topics)
+	})
+}
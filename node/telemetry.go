@@ -99,3 +99,19 @@ func (c *msgSpanConfig) spanOpts() []trace.SpanStartOption {
 		trace.WithAttributes(attrs...),
 	}
 }
+
+// linkExecutionSpan links the span of the execution in progress to the span described by the trace information
+// received from a worker, so that traces of all workers taking part in the execution can be found from the head node trace.
+func (n *Node) linkExecutionSpan(requestID string, t tracing.TraceInfo) {
+
+	if t.Empty() {
+		return
+	}
+
+	link, ok := tracing.LinkFromTraceInfo(t)
+	if !ok {
+		return
+	}
+
+	n.executions.link(requestID, link)
+}
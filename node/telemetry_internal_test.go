@@ -10,9 +10,11 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel"
 
+	"github.com/blocklessnetwork/b7s/consensus"
 	"github.com/blocklessnetwork/b7s/models/blockless"
 	"github.com/blocklessnetwork/b7s/models/response"
 	"github.com/blocklessnetwork/b7s/telemetry"
+	"github.com/blocklessnetwork/b7s/telemetry/tracing"
 	"github.com/blocklessnetwork/b7s/testing/helpers"
 )
 
@@ -43,3 +45,43 @@ func TestTelemetry_SaveTraceContext(t *testing.T) {
 	require.Equal(t, sctx.SpanID().String(), fields[2])
 	require.Equal(t, sctx.TraceFlags().String(), fields[3])
 }
+
+func TestTelemetry_LinkExecutionSpan(t *testing.T) {
+
+	var (
+		ctx          = context.Background()
+		resource, _  = telemetry.CreateResource(ctx, "instance-id", blockless.HeadNode)
+		exporter, tp = helpers.CreateTracerProvider(t, resource)
+		tracer       = tp.Tracer("test-tracer")
+	)
+
+	otel.SetTextMapPropagator(telemetry.CreatePropagator())
+
+	node := createNode(t, blockless.HeadNode)
+	requestID := node.newRequestID()
+
+	_, span := tracer.Start(ctx, spanHeadExecute)
+	node.executions.start(requestID, "dummy-function-id", consensus.PBFT, nil, span, "")
+
+	replicaCtx, replicaSpan := tracer.Start(ctx, "replica-span")
+	replicaSpan.End()
+
+	node.linkExecutionSpan(requestID, tracing.GetTraceInfo(replicaCtx))
+	// Trace information for unknown executions is ignored.
+	node.linkExecutionSpan(node.newRequestID(), tracing.GetTraceInfo(replicaCtx))
+
+	span.End()
+	node.executions.done(requestID)
+
+	var found bool
+	for _, s := range exporter.GetSpans() {
+		if s.Name != spanHeadExecute {
+			continue
+		}
+
+		found = true
+		require.Len(t, s.Links, 1)
+		require.Equal(t, replicaSpan.SpanContext().SpanID(), s.Links[0].SpanContext.SpanID())
+	}
+	require.True(t, found)
+}
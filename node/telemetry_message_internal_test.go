@@ -125,7 +125,7 @@ func TestNode_TraceExecution(t *testing.T) {
 		{
 			name: "execution failed",
 			req: request.Execute{
-				RequestID: newRequestID(),
+				RequestID: node.newRequestID(),
 				Request: execute.Request{
 					FunctionID: fmt.Sprintf("test-function-cid-1-%v", rand.Int()),
 					Method:     fmt.Sprintf("test-method-%v.wasm", rand.Int()),
@@ -140,7 +140,7 @@ func TestNode_TraceExecution(t *testing.T) {
 		{
 			name: "execution ok",
 			req: request.Execute{
-				RequestID: newRequestID(),
+				RequestID: node.newRequestID(),
 				Request: execute.Request{
 					FunctionID: fmt.Sprintf("test-function-cid-2-%v", rand.Int()),
 					Method:     fmt.Sprintf("test-method-%v.wasm", rand.Int()),
@@ -285,7 +285,7 @@ func TestNode_ProcessedMessageMetric(t *testing.T) {
 		healthCheck = response.Health{}
 
 		disbandRequest = request.DisbandCluster{
-			RequestID: newRequestID(),
+			RequestID: node.newRequestID(),
 		}
 	)
 
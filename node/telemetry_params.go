@@ -35,19 +35,77 @@ func msgSendSpanName(prefix string, msgType string) string {
 }
 
 var (
-	rollCallsPublishedMetric   = []string{"node", "rollcalls", "published"}
-	rollCallsSeenMetric        = []string{"node", "rollcalls", "seen"}
-	rollCallsAppliedMetric     = []string{"node", "rollcalls", "applied"}
-	messagesProcessedMetric    = []string{"node", "messages", "processed"}
-	messagesProcessedOkMetric  = []string{"node", "messages", "processed", "ok"}
-	messagesProcessedErrMetric = []string{"node", "messages", "processed", "err"}
-	messagesSentMetric         = []string{"node", "messages", "sent"}
-	messagesPublishedMetric    = []string{"node", "messages", "published"}
-	functionExecutionsMetric   = []string{"node", "function", "executions"}
-	subscriptionsMetric        = []string{"node", "topic", "subscriptions"}
-	directMessagesMetric       = []string{"node", "direct", "messages"}
-	topicMessagesMetric        = []string{"node", "topic", "messages"}
-	nodeInfoMetric             = []string{"node", "info"}
+	rollCallsPublishedMetric     = []string{"node", "rollcalls", "published"}
+	rollCallsSeenMetric          = []string{"node", "rollcalls", "seen"}
+	rollCallsAppliedMetric       = []string{"node", "rollcalls", "applied"}
+	rollCallsNotSampledMetric    = []string{"node", "rollcalls", "not", "sampled"}
+	messagesProcessedMetric      = []string{"node", "messages", "processed"}
+	messagesProcessedOkMetric    = []string{"node", "messages", "processed", "ok"}
+	messagesProcessedErrMetric   = []string{"node", "messages", "processed", "err"}
+	messagesProcessingTimeMetric = []string{"node", "messages", "processing", "milliseconds"}
+	messagesSlowMetric           = []string{"node", "messages", "slow"}
+	messagesSentMetric           = []string{"node", "messages", "sent"}
+	messagesPublishedMetric      = []string{"node", "messages", "published"}
+	functionExecutionsMetric     = []string{"node", "function", "executions"}
+	functionInvalidOutputMetric  = []string{"node", "function", "output", "invalid"}
+	executionFailoversMetric     = []string{"node", "execution", "failovers"}
+	executionsAbortedMetric      = []string{"node", "execution", "aborted"}
+	scheduledExecutionsMetric    = []string{"node", "execution", "scheduled"}
+	deferredExecutionsMetric     = []string{"node", "execution", "deferred"}
+	checksumMismatchMetric       = []string{"node", "execution", "checksum", "mismatch"}
+	watermarkMismatchMetric      = []string{"node", "execution", "watermark", "mismatch"}
+	expiredExecutionsMetric      = []string{"node", "execution", "expired"}
+	quotaExceededMetric          = []string{"node", "execution", "quota", "exceeded"}
+	callbackDeliveryFailedMetric = []string{"node", "execution", "callback", "failed"}
+	archivedResultsMetric        = []string{"node", "execution", "results", "archived"}
+	rateLimitedMetric            = []string{"node", "execution", "rate", "limited"}
+	executorSwapsMetric          = []string{"node", "executor", "swaps"}
+	verificationFailedMetric     = []string{"node", "execution", "verification", "failed"}
+	droppedPeersMetric           = []string{"node", "execution", "peers", "dropped"}
+	maintenanceDeclinedMetric    = []string{"node", "maintenance", "declined"}
+	anomalousResultsMetric       = []string{"node", "execution", "results", "anomalous"}
+	canaryExecutionsMetric       = []string{"node", "canary", "executions"}
+	canaryDegradedMetric         = []string{"node", "canary", "degraded"}
+	shadowExecutionsMetric       = []string{"node", "shadow", "executions"}
+	shadowMismatchesMetric       = []string{"node", "shadow", "mismatches"}
+	warmPoolHitsMetric           = []string{"node", "rollcalls", "pool", "hits"}
+	warmPoolMissesMetric         = []string{"node", "rollcalls", "pool", "misses"}
+	affinityHitsMetric           = []string{"node", "rollcalls", "affinity", "hits"}
+	affinityMissesMetric         = []string{"node", "rollcalls", "affinity", "misses"}
+	rollCallFallbacksMetric      = []string{"node", "rollcalls", "fallbacks"}
+	subgroupFanInsMetric         = []string{"node", "execution", "subgroups", "fanin"}
+	sampledExecutionsMetric      = []string{"node", "execution", "sampled"}
+	membershipRejectedMetric     = []string{"node", "rollcalls", "membership", "rejected"}
+	fleetReportsMetric           = []string{"node", "fleet", "reports"}
+	leaderTransfersMetric        = []string{"node", "cluster", "leadership", "transfers"}
+	resultsPublishedMetric       = []string{"node", "execution", "results", "published"}
+	misbehaviorReportsMetric     = []string{"node", "consensus", "misbehavior", "reports"}
+	clustersAbandonedMetric      = []string{"node", "cluster", "abandoned"}
+	workQueueRejectedMetric      = []string{"node", "work", "queue", "rejected"}
+	functionsDeclinedMetric      = []string{"node", "function", "declined"}
+	resultCacheHitsMetric        = []string{"node", "result", "cache", "hits"}
+	clientTooManyInFlightMetric  = []string{"node", "client", "executions", "rejected"}
+	tenantTooManyInFlightMetric  = []string{"node", "tenant", "executions", "rejected"}
+	resultCacheMissesMetric      = []string{"node", "result", "cache", "misses"}
+	excludedPeersSkippedMetric   = []string{"node", "rollcalls", "excluded", "skipped"}
+	rollCallQueueDroppedMetric   = []string{"node", "rollcalls", "queue", "dropped"}
+	rollCallQueueDepthMetric     = []string{"node", "rollcalls", "queue", "depth"}
+	storeMaintenanceMetric       = []string{"node", "store", "maintenance"}
+	executorSwapRollbacksMetric  = []string{"node", "executor", "swap", "rollbacks"}
+	subscriptionsMetric          = []string{"node", "topic", "subscriptions"}
+	subgroupChangesMetric        = []string{"node", "topic", "subscriptions", "changes"}
+	abandonedExecutionsMetric    = []string{"node", "execution", "abandoned"}
+	abortedExecutionsMetric      = []string{"node", "execution", "aborted"}
+	resultSummariesMetric        = []string{"node", "execution", "results", "summarized"}
+	interactiveExecutionsMetric  = []string{"node", "execution", "interactive"}
+	directMessagesMetric         = []string{"node", "direct", "messages"}
+	topicMessagesMetric          = []string{"node", "topic", "messages"}
+	nodeInfoMetric               = []string{"node", "info"}
+	executionsDelegatedMetric    = []string{"node", "execution", "delegated"}
+	delegationsRejectedMetric    = []string{"node", "execution", "delegation", "rejected"}
+	drainDeclinedMetric          = []string{"node", "drain", "declined"}
+	functionPrefetchesMetric     = []string{"node", "function", "prefetches"}
+	functionPrefetchFailedMetric = []string{"node", "function", "prefetches", "failed"}
 )
 
 var Counters = []prometheus.CounterDefinition{
@@ -63,6 +121,10 @@ var Counters = []prometheus.CounterDefinition{
 		Name: rollCallsAppliedMetric,
 		Help: "Number of roll calls this node applied to.",
 	},
+	{
+		Name: rollCallsNotSampledMetric,
+		Help: "Number of sampled roll calls this node was not chosen to answer.",
+	},
 	{
 		Name: messagesProcessedMetric,
 		Help: "Number of messages this node processed.",
@@ -79,10 +141,226 @@ var Counters = []prometheus.CounterDefinition{
 		Name: functionExecutionsMetric,
 		Help: "Number of function executions.",
 	},
+	{
+		Name: functionInvalidOutputMetric,
+		Help: "Number of function executions with output not conforming to the output schema.",
+	},
+	{
+		Name: executionFailoversMetric,
+		Help: "Number of execution requests sent to reserve peers after the chosen peer failed to respond.",
+	},
+	{
+		Name: executionsAbortedMetric,
+		Help: "Number of pending execution requests aborted after the head node restarted.",
+	},
+	{
+		Name: scheduledExecutionsMetric,
+		Help: "Number of scheduled executions the head node started.",
+	},
+	{
+		Name: deferredExecutionsMetric,
+		Help: "Number of execution requests the head node held until their not-before time.",
+	},
+	{
+		Name: checksumMismatchMetric,
+		Help: "Number of execution results dropped because they did not match their checksum.",
+	},
+	{
+		Name: watermarkMismatchMetric,
+		Help: "Number of execution results dropped because they did not carry the watermark of the request.",
+	},
+	{
+		Name: expiredExecutionsMetric,
+		Help: "Number of execution requests not executed because their deadline had passed.",
+	},
+	{
+		Name: quotaExceededMetric,
+		Help: "Number of execution requests rejected because the client exceeded its quota.",
+	},
+	{
+		Name: callbackDeliveryFailedMetric,
+		Help: "Number of asynchronous execution results that could not be delivered.",
+	},
+	{
+		Name: archivedResultsMetric,
+		Help: "Number of execution results moved to the result archive.",
+	},
+	{
+		Name: rateLimitedMetric,
+		Help: "Number of execution requests rejected because they exceeded the rate limit for the function.",
+	},
+	{
+		Name: verificationFailedMetric,
+		Help: "Number of executions whose results could not be verified by a quorum of identical results.",
+	},
+	{
+		Name: droppedPeersMetric,
+		Help: "Number of peers that disconnected before sending their execution result.",
+	},
+	{
+		Name: maintenanceDeclinedMetric,
+		Help: "Number of roll calls and cluster formation requests declined during maintenance windows.",
+	},
+	{
+		Name: anomalousResultsMetric,
+		Help: "Number of execution results deviating strongly from previous executions of the function.",
+	},
+	{
+		Name: canaryExecutionsMetric,
+		Help: "Number of canary probes the head node executed.",
+	},
+	{
+		Name: canaryDegradedMetric,
+		Help: "Number of times a subgroup was found degraded by canary probes.",
+	},
+	{
+		Name: warmPoolHitsMetric,
+		Help: "Number of executions that used workers from a warm worker pool instead of a roll call.",
+	},
+	{
+		Name: warmPoolMissesMetric,
+		Help: "Number of executions that fell back to a roll call because the warm worker pool did not have enough available workers.",
+	},
+	{
+		Name: affinityHitsMetric,
+		Help: "Number of executions that used the workers bound to their affinity token instead of a roll call.",
+	},
+	{
+		Name: affinityMissesMetric,
+		Help: "Number of executions with an affinity token that fell back to a roll call because the bound workers were not available.",
+	},
+	{
+		Name: shadowExecutionsMetric,
+		Help: "Number of shadow executions dispatched alongside client requests.",
+	},
+	{
+		Name: shadowMismatchesMetric,
+		Help: "Number of shadow executions whose results differ from the results returned to the client.",
+	},
+	{
+		Name: rollCallFallbacksMetric,
+		Help: "Number of roll calls retried on the default topic because not enough workers in the subgroup responded.",
+	},
+	{
+		Name: subgroupFanInsMetric,
+		Help: "Number of executions dispatched to multiple subgroups at the same time.",
+	},
+	{
+		Name: sampledExecutionsMetric,
+		Help: "Number of executions on a stratified sample of workers.",
+	},
+	{
+		Name: membershipRejectedMetric,
+		Help: "Number of roll call responses in restricted subgroups skipped because the worker did not present a valid membership credential.",
+	},
+	{
+		Name: resultsPublishedMetric,
+		Help: "Number of execution results this node published to topics requested by clients.",
+	},
+	{
+		Name: clustersAbandonedMetric,
+		Help: "Number of consensus clusters the worker shut down because they made no progress.",
+	},
+	{
+		Name: workQueueRejectedMetric,
+		Help: "Number of messages and execution requests turned down because the work queue was full.",
+	},
+	{
+		Name: functionsDeclinedMetric,
+		Help: "Number of roll calls and execution requests declined because the function is not permitted on the worker.",
+	},
+	{
+		Name: clientTooManyInFlightMetric,
+		Help: "Number of execution requests turned down because the client had too many executions in flight.",
+	},
+	{
+		Name: tenantTooManyInFlightMetric,
+		Help: "Number of execution requests turned down because the tenant had too many executions in flight on the worker.",
+	},
+	{
+		Name: executionsDelegatedMetric,
+		Help: "Number of executions delegated to peer head nodes.",
+	},
+	{
+		Name: delegationsRejectedMetric,
+		Help: "Number of delegated executions this node turned down.",
+	},
+	{
+		Name: drainDeclinedMetric,
+		Help: "Number of roll calls declined in drain mode.",
+	},
+	{
+		Name: functionPrefetchesMetric,
+		Help: "Number of function installations started when answering roll calls.",
+	},
+	{
+		Name: functionPrefetchFailedMetric,
+		Help: "Number of function installations started when answering roll calls that failed.",
+	},
+	{
+		Name: resultCacheHitsMetric,
+		Help: "Number of executions served from the result cache.",
+	},
+	{
+		Name: resultCacheMissesMetric,
+		Help: "Number of executions not found in the result cache.",
+	},
+	{
+		Name: misbehaviorReportsMetric,
+		Help: "Number of reports of workers misbehaving in consensus clusters.",
+	},
+	{
+		Name: excludedPeersSkippedMetric,
+		Help: "Number of roll call responses skipped because the worker was excluded for misbehaving.",
+	},
+	{
+		Name: leaderTransfersMetric,
+		Help: "Number of times this node handed over the leadership of a raft cluster.",
+	},
+	{
+		Name: fleetReportsMetric,
+		Help: "Number of fleet reports the head node received from workers.",
+	},
+	{
+		Name: rollCallQueueDroppedMetric,
+		Help: "Number of roll call responses dropped because the roll call queue was full.",
+	},
+	{
+		Name: storeMaintenanceMetric,
+		Help: "Number of store compactions and backups requested from the head node.",
+	},
+	{
+		Name: executorSwapsMetric,
+		Help: "Number of times the worker switched to a different executor.",
+	},
+	{
+		Name: executorSwapRollbacksMetric,
+		Help: "Number of executor switches abandoned because the canary execution failed.",
+	},
 	{
 		Name: subscriptionsMetric,
 		Help: "Number of topics this node subscribes to.",
 	},
+	{
+		Name: subgroupChangesMetric,
+		Help: "Number of subgroups the worker joined or left at runtime.",
+	},
+	{
+		Name: abandonedExecutionsMetric,
+		Help: "Number of executions the head node stopped working on because the client abandoned the request.",
+	},
+	{
+		Name: abortedExecutionsMetric,
+		Help: "Number of executions the worker aborted on request of the head node.",
+	},
+	{
+		Name: resultSummariesMetric,
+		Help: "Number of execution responses with a result summary instead of full results.",
+	},
+	{
+		Name: interactiveExecutionsMetric,
+		Help: "Number of interactive executions the node relayed or ran.",
+	},
 	{
 		Name: directMessagesMetric,
 		Help: "Number of direct messages this node received.",
@@ -91,6 +369,10 @@ var Counters = []prometheus.CounterDefinition{
 		Name: topicMessagesMetric,
 		Help: "Number of topic messages this node received.",
 	},
+	{
+		Name: messagesSlowMetric,
+		Help: "Number of messages whose handlers ran longer than the slow handler threshold.",
+	},
 	{
 		Name: messagesSentMetric,
 		Help: "Number of messages sent.",
@@ -106,4 +388,15 @@ var Gauges = []prometheus.GaugeDefinition{
 		Name: nodeInfoMetric,
 		Help: "Information about the b7s node.",
 	},
+	{
+		Name: rollCallQueueDepthMetric,
+		Help: "Number of roll call responses waiting to be processed.",
+	},
+}
+
+var Summaries = []prometheus.SummaryDefinition{
+	{
+		Name: messagesProcessingTimeMetric,
+		Help: "Time needed to process a message, by message type.",
+	},
 }
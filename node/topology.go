@@ -0,0 +1,218 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/node/head/topology"
+)
+
+const unknownRole = "unknown"
+
+// activeExecutions tracks executions the head node is working on.
+type activeExecutions struct {
+	sync.Mutex
+	executions map[string]activeExecution
+}
+
+type activeExecution struct {
+	functionID string
+	consensus  consensus.Type
+	peers      []peer.ID
+	started    time.Time
+	span       trace.Span // Span of the execution, linked to the spans of the workers executing the request.
+	watermark  string     // Watermark of the request, results not carrying it are dropped.
+}
+
+func newActiveExecutions() *activeExecutions {
+	return &activeExecutions{
+		executions: make(map[string]activeExecution),
+	}
+}
+
+func (a *activeExecutions) start(requestID string, functionID string, consensus consensus.Type, peers []peer.ID, span trace.Span, watermark string) {
+	a.Lock()
+	defer a.Unlock()
+
+	a.executions[requestID] = activeExecution{
+		functionID: functionID,
+		consensus:  consensus,
+		peers:      peers,
+		started:    time.Now().UTC(),
+		span:       span,
+		watermark:  watermark,
+	}
+}
+
+// watermark returns the watermark of the execution, if the execution is in progress.
+func (a *activeExecutions) watermark(requestID string) (string, bool) {
+	a.Lock()
+	defer a.Unlock()
+
+	execution, ok := a.executions[requestID]
+	return execution.watermark, ok
+}
+
+// peers returns the peers executing the request, if the execution is in progress.
+func (a *activeExecutions) peers(requestID string) ([]peer.ID, bool) {
+	a.Lock()
+	defer a.Unlock()
+
+	execution, ok := a.executions[requestID]
+	return execution.peers, ok
+}
+
+// addPeer records that the request was also sent to the given peer, e.g. a reserve peer, if the execution is in progress.
+func (a *activeExecutions) addPeer(requestID string, peer peer.ID) {
+	a.Lock()
+	defer a.Unlock()
+
+	execution, ok := a.executions[requestID]
+	if !ok {
+		return
+	}
+
+	// Peer lists are handed out to callers, so do not modify them in place.
+	execution.peers = append(slices.Clone(execution.peers), peer)
+	a.executions[requestID] = execution
+}
+
+// link adds a link to the given span to the span of the execution, if the execution is in progress.
+func (a *activeExecutions) link(requestID string, link trace.Link) bool {
+	a.Lock()
+	defer a.Unlock()
+
+	execution, ok := a.executions[requestID]
+	if !ok || execution.span == nil {
+		return false
+	}
+
+	execution.span.AddLink(link)
+	return true
+}
+
+func (a *activeExecutions) done(requestID string) {
+	a.Lock()
+	defer a.Unlock()
+
+	delete(a.executions, requestID)
+}
+
+func (a *activeExecutions) count() int {
+	a.Lock()
+	defer a.Unlock()
+
+	return len(a.executions)
+}
+
+func (a *activeExecutions) list() map[string]activeExecution {
+	a.Lock()
+	defer a.Unlock()
+
+	executions := make(map[string]activeExecution, len(a.executions))
+	for id, execution := range a.executions {
+		executions[id] = execution
+	}
+
+	return executions
+}
+
+// Topology returns a snapshot of the network as seen by this node - known peers and the ones we are connected to,
+// along with executions in progress and clusters formed for them.
+func (n *Node) Topology() topology.Graph {
+
+	self := n.host.ID()
+
+	graph := topology.Graph{
+		Generated:   time.Now().UTC(),
+		Nodes:       []topology.Node{{ID: self.String(), Role: n.cfg.Role.String(), Self: true}},
+		Connections: make([]topology.Connection, 0),
+	}
+
+	nodes := make(map[peer.ID]*topology.Node)
+
+	for id, known := range n.peers.all() {
+		if id == self {
+			continue
+		}
+
+		lastSeen := known.lastSeen
+		nodes[id] = &topology.Node{
+			ID:       id.String(),
+			Role:     known.role.String(),
+			LastSeen: &lastSeen,
+		}
+	}
+
+	for _, id := range n.host.Network().Peers() {
+
+		node, ok := nodes[id]
+		if !ok {
+			node = &topology.Node{
+				ID:   id.String(),
+				Role: unknownRole,
+			}
+			nodes[id] = node
+		}
+		node.Connected = true
+
+		graph.Connections = append(graph.Connections, topology.Connection{From: self.String(), To: id.String()})
+	}
+
+	for _, node := range nodes {
+		graph.Nodes = append(graph.Nodes, *node)
+	}
+
+	for requestID, execution := range n.executions.list() {
+
+		peers := blockless.PeerIDsToStr(execution.peers)
+
+		graph.Executions = append(graph.Executions, topology.Execution{
+			RequestID:  requestID,
+			FunctionID: execution.functionID,
+			Started:    execution.started,
+			Peers:      peers,
+		})
+
+		if consensusRequired(execution.consensus) {
+			graph.Clusters = append(graph.Clusters, topology.Cluster{
+				RequestID: requestID,
+				Consensus: execution.consensus.String(),
+				Peers:     peers,
+			})
+		}
+	}
+
+	graph.Sort()
+
+	return graph
+}
+
+func (n *Node) processTopology(ctx context.Context, from peer.ID, req request.Topology) error {
+
+	n.log.Debug().Str("peer", from.String()).Str("format", req.Format).Msg("processing topology request")
+
+	graph := n.Topology()
+
+	res := req.Response(codes.OK, graph)
+	if req.Format == topology.FormatDOT {
+		res = res.WithDOT(graph.DOT())
+	}
+
+	err := n.send(ctx, from, res)
+	if err != nil {
+		return fmt.Errorf("could not send response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package node
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/node/head/topology"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_Topology(t *testing.T) {
+
+	const (
+		requestID = "dummy-request-id"
+	)
+
+	node := createNode(t, blockless.HeadNode)
+
+	worker, err := host.New(mocks.NoopLogger, loopback, 0)
+	require.NoError(t, err)
+
+	hostAddNewPeer(t, node.host, worker)
+	err = node.host.Connect(context.Background(), *hostGetAddrInfo(t, worker))
+	require.NoError(t, err)
+
+	node.peers.update(worker.ID(), blockless.WorkerNode, nil, false)
+	node.executions.start(requestID, mocks.GenericExecutionRequest.FunctionID, consensus.Raft, []peer.ID{worker.ID()}, nil, "")
+
+	t.Run("snapshot describes the network", func(t *testing.T) {
+
+		graph := node.Topology()
+
+		require.Len(t, graph.Nodes, 2)
+
+		var found bool
+		for _, n := range graph.Nodes {
+			if n.ID != worker.ID().String() {
+				require.True(t, n.Self)
+				continue
+			}
+
+			found = true
+			require.Equal(t, blockless.WorkerNodeLabel, n.Role)
+			require.True(t, n.Connected)
+			require.NotNil(t, n.LastSeen)
+		}
+		require.True(t, found)
+
+		require.Equal(t, []topology.Connection{{From: node.host.ID().String(), To: worker.ID().String()}}, graph.Connections)
+
+		require.Len(t, graph.Executions, 1)
+		require.Equal(t, requestID, graph.Executions[0].RequestID)
+		require.Equal(t, []string{worker.ID().String()}, graph.Executions[0].Peers)
+
+		require.Len(t, graph.Clusters, 1)
+		require.Equal(t, consensus.Raft.String(), graph.Clusters[0].Consensus)
+	})
+	t.Run("snapshot in DOT format", func(t *testing.T) {
+
+		receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, receiver)
+
+		var (
+			wg       sync.WaitGroup
+			received response.Topology
+		)
+		wg.Add(1)
+
+		receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+			defer wg.Done()
+			defer stream.Close()
+
+			getStreamPayload(t, stream, &received)
+		})
+
+		err = node.processTopology(context.Background(), receiver.ID(), request.Topology{Format: topology.FormatDOT})
+		require.NoError(t, err)
+
+		wg.Wait()
+
+		require.Equal(t, codes.OK, received.Code)
+		require.NotEmpty(t, received.Topology.Nodes)
+		require.Contains(t, received.DOT, worker.ID().String())
+	})
+}
@@ -0,0 +1,31 @@
+package node
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/node/head/usage"
+)
+
+// Usage returns the resource usage of executions, grouped by the values of the given tag.
+func (n *Node) Usage(tag string) []usage.Summary {
+	return n.usage.Summary(tag)
+}
+
+func (n *Node) processUsageQuery(ctx context.Context, from peer.ID, req request.UsageQuery) error {
+
+	summary := n.Usage(req.Tag)
+
+	n.log.Debug().Str("peer", from.String()).Str("tag", req.Tag).Int("values", len(summary)).Msg("processing usage query")
+
+	err := n.send(ctx, from, req.Response(codes.OK, summary))
+	if err != nil {
+		return fmt.Errorf("could not send response: %w", err)
+	}
+
+	return nil
+}
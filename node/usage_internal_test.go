@@ -0,0 +1,74 @@
+package node
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_UsageQuery(t *testing.T) {
+
+	const (
+		tag = "cost-center"
+	)
+
+	node := createNode(t, blockless.HeadNode)
+
+	results := execute.ResultMap{
+		mocks.GenericPeerIDs[0]: {
+			Result: execute.Result{
+				Code:  codes.OK,
+				Usage: execute.Usage{CPUUserTime: time.Second},
+			},
+		},
+	}
+	node.usage.Record(map[string]string{tag: "cc-1"}, results)
+	node.usage.Record(map[string]string{tag: "cc-1"}, results)
+	node.usage.Record(map[string]string{tag: "cc-2"}, results)
+
+	receiver, err := host.New(mocks.NoopLogger, loopback, 0)
+	require.NoError(t, err)
+
+	hostAddNewPeer(t, node.host, receiver)
+
+	var (
+		wg       sync.WaitGroup
+		received response.UsageQuery
+	)
+	wg.Add(1)
+
+	receiver.SetStreamHandler(blockless.ProtocolID, func(stream network.Stream) {
+		defer wg.Done()
+		defer stream.Close()
+
+		getStreamPayload(t, stream, &received)
+	})
+
+	err = node.processUsageQuery(context.Background(), receiver.ID(), request.UsageQuery{Tag: tag})
+	require.NoError(t, err)
+
+	wg.Wait()
+
+	require.Equal(t, codes.OK, received.Code)
+	require.Equal(t, tag, received.Tag)
+	require.Len(t, received.Usage, 2)
+
+	require.Equal(t, "cc-1", received.Usage[0].Value)
+	require.Equal(t, uint64(2), received.Usage[0].Executions)
+	require.Equal(t, 2*time.Second, received.Usage[0].CPUUserTime)
+
+	require.Equal(t, "cc-2", received.Usage[1].Value)
+	require.Equal(t, uint64(1), received.Usage[1].Executions)
+}
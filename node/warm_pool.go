@@ -0,0 +1,207 @@
+package node
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/node/internal/dispatch"
+)
+
+// warmPool is a set of workers that recently reported for a roll call for the function.
+type warmPool struct {
+	peers   []peer.ID
+	expires time.Time
+}
+
+// warmPools holds the warm worker pools the head node maintains, per function and subgroup.
+type warmPools struct {
+	sync.Mutex
+	pools map[string]warmPool
+}
+
+func newWarmPools() *warmPools {
+	return &warmPools{
+		pools: make(map[string]warmPool),
+	}
+}
+
+func (p *warmPools) set(functionID string, topic string, peers []peer.ID, expires time.Time) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.pools[warmPoolKey(functionID, topic)] = warmPool{
+		peers:   peers,
+		expires: expires,
+	}
+}
+
+// get returns the workers in the pool, if there is a pool for the function that did not expire.
+func (p *warmPools) get(functionID string, topic string, now time.Time) ([]peer.ID, bool) {
+	p.Lock()
+	defer p.Unlock()
+
+	pool, ok := p.pools[warmPoolKey(functionID, topic)]
+	if !ok || now.After(pool.expires) {
+		return nil, false
+	}
+
+	return slices.Clone(pool.peers), true
+}
+
+func warmPoolKey(functionID string, topic string) string {
+	if topic == "" {
+		topic = DefaultTopic
+	}
+
+	return functionID + "/" + topic
+}
+
+// runWarmPools periodically refreshes the warm worker pools.
+func (n *Node) runWarmPools(ctx context.Context) {
+
+	var wg sync.WaitGroup
+	for _, pool := range n.cfg.WarmPools {
+		wg.Add(1)
+		go func(pool WarmPool) {
+			defer wg.Done()
+
+			ticker := time.NewTicker(pool.Refresh)
+			defer ticker.Stop()
+
+			for {
+				n.refreshWarmPool(ctx, pool)
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}(pool)
+	}
+
+	wg.Wait()
+}
+
+// refreshWarmPool issues a roll call for the function and replaces the pool with the workers that reported.
+// Workers are not reserved for any execution - dispatch limits are checked once the pool is used.
+func (n *Node) refreshWarmPool(ctx context.Context, pool WarmPool) {
+
+	requestID := n.newRequestID()
+
+	log := n.log.With().Str("request", requestID).Str("function", pool.FunctionID).Str("topic", pool.Topic).Logger()
+
+	n.rollCall.Create(requestID)
+	defer n.rollCall.Remove(requestID)
+
+	published := time.Now()
+	err := n.publishRollCall(ctx, requestID, pool.FunctionID, consensus.Type(0), pool.Topic, nil, 0)
+	if err != nil {
+		log.Warn().Err(err).Msg("could not publish roll call for warm worker pool")
+		return
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, n.cfg.RollCallTimeout)
+	defer cancel()
+
+	var peers []peer.ID
+responseLoop:
+	for uint(len(peers)) < pool.Size {
+		select {
+		case <-tctx.Done():
+			break responseLoop
+
+		case reply := <-n.rollCall.Responses(requestID):
+			if reply.FunctionID != pool.FunctionID || !n.haveConnection(reply.From) || slices.Contains(peers, reply.From) {
+				continue
+			}
+
+			// Restricted subgroups only pool workers that are members.
+			if n.verifyMembership(pool.Topic, reply) != nil {
+				n.metrics.IncrCounterWithLabels(membershipRejectedMetric, 1, []metrics.Label{{Name: "subgroup", Value: pool.Topic}})
+				continue
+			}
+
+			n.latencies.record(reply.From, time.Since(published))
+			n.loads.record(reply.From, reply.Load)
+			peers = append(peers, reply.From)
+		}
+	}
+
+	// Keep the pool around for a while even if the next refresh fails, but not indefinitely.
+	n.pools.set(pool.FunctionID, pool.Topic, peers, time.Now().Add(warmPoolExpiryFactor*pool.Refresh))
+
+	log.Debug().Int("size", len(peers)).Msg("warm worker pool refreshed")
+}
+
+// warmPoolPeers chooses workers for the execution out of the warm worker pool for the function, skipping the roll call.
+// Pools are not used for executions requiring consensus or specific worker attributes. If the pool does not have enough
+// available workers, no workers are chosen and the caller should fall back to a roll call.
+func (n *Node) warmPoolPeers(
+	functionID string,
+	nodeCount int,
+	consensusAlgo consensus.Type,
+	topic string,
+	attributes *execute.Attributes,
+) ([]peer.ID, []peer.ID, map[peer.ID]time.Duration, bool) {
+
+	if consensusRequired(consensusAlgo) || attributes != nil {
+		return nil, nil, nil, false
+	}
+
+	pooled, ok := n.pools.get(functionID, topic, time.Now())
+	if !ok {
+		return nil, nil, nil, false
+	}
+
+	labels := []metrics.Label{{Name: "function", Value: functionID}}
+
+	var (
+		chosen    []peer.ID
+		reserve   []peer.ID
+		latencies = make(map[peer.ID]time.Duration)
+	)
+	for _, peer := range n.reputation.Rank(pooled, n.latencies.weight) {
+
+		// Membership credentials of pooled workers may have expired since the pool was refreshed.
+		if !n.haveConnection(peer) || n.excluded.excluded(peer, time.Now()) || !n.isSubgroupMember(topic, peer) {
+			continue
+		}
+
+		latency, ok := n.latencies.get(peer)
+		if ok {
+			latencies[peer] = latency
+		}
+
+		// -1 means any number of peers can execute the request.
+		if (nodeCount != -1 && len(chosen) >= nodeCount) || !n.dispatch.Acquire(peer) {
+			reserve = append(reserve, peer)
+			continue
+		}
+
+		chosen = append(chosen, peer)
+	}
+
+	if len(chosen) == 0 || (nodeCount != -1 && len(chosen) < nodeCount) {
+		n.releaseWorkers(chosen, nil, dispatch.Aborted)
+		n.metrics.IncrCounterWithLabels(warmPoolMissesMetric, 1, labels)
+		return nil, nil, nil, false
+	}
+
+	chosen, reserve = n.selectRollCallPeers(functionID, nodeCount, chosen, reserve, latencies)
+	if len(chosen) == 0 {
+		n.metrics.IncrCounterWithLabels(warmPoolMissesMetric, 1, labels)
+		return nil, nil, nil, false
+	}
+
+	n.metrics.IncrCounterWithLabels(warmPoolHitsMetric, 1, labels)
+
+	return chosen, reserve, latencies, true
+}
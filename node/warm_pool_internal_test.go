@@ -0,0 +1,141 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/consensus"
+	"github.com/blocklessnetwork/b7s/host"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/execute"
+	"github.com/blocklessnetwork/b7s/node/internal/dispatch"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestWarmPools(t *testing.T) {
+
+	const functionID = "dummy-function-id"
+
+	var (
+		now   = time.Now()
+		peers = mocks.GenericPeerIDs[:2]
+	)
+
+	pools := newWarmPools()
+
+	_, ok := pools.get(functionID, "", now)
+	require.False(t, ok)
+
+	pools.set(functionID, "", peers, now.Add(time.Minute))
+
+	// Empty topic is the default topic.
+	pooled, ok := pools.get(functionID, DefaultTopic, now)
+	require.True(t, ok)
+	require.Equal(t, peers, pooled)
+
+	_, ok = pools.get(functionID, "other-topic", now)
+	require.False(t, ok)
+
+	_, ok = pools.get(functionID, "", now.Add(2*time.Minute))
+	require.False(t, ok)
+}
+
+func TestNode_WarmPoolPeers(t *testing.T) {
+
+	const functionID = "dummy-function-id"
+
+	// Create a head node with a connected worker in its warm pool, along with a worker it is not connected to.
+	setup := func(t *testing.T) (*Node, peer.ID) {
+		t.Helper()
+
+		node := createNode(t, blockless.HeadNode)
+
+		worker, err := host.New(mocks.NoopLogger, loopback, 0)
+		require.NoError(t, err)
+
+		hostAddNewPeer(t, node.host, worker)
+
+		info := hostGetAddrInfo(t, worker)
+		err = node.host.Connect(context.Background(), *info)
+		require.NoError(t, err)
+
+		node.pools.set(functionID, "", []peer.ID{mocks.GenericPeerID, worker.ID()}, time.Now().Add(time.Minute))
+
+		return node, worker.ID()
+	}
+
+	t.Run("connected workers from the pool are used", func(t *testing.T) {
+		t.Parallel()
+
+		node, worker := setup(t)
+		node.dispatch = dispatch.New[peer.ID](1)
+
+		node.latencies.record(worker, 10*time.Millisecond)
+
+		chosen, reserve, latencies, ok := node.warmPoolPeers(functionID, 1, consensus.Type(0), "", nil)
+		require.True(t, ok)
+		require.Equal(t, []peer.ID{worker}, chosen)
+		require.Empty(t, reserve)
+		require.Equal(t, 10*time.Millisecond, latencies[worker])
+
+		// Worker counts towards its dispatch limit.
+		require.False(t, node.dispatch.Acquire(worker))
+	})
+	t.Run("pool without enough workers is not used", func(t *testing.T) {
+		t.Parallel()
+
+		node, worker := setup(t)
+		node.dispatch = dispatch.New[peer.ID](1)
+
+		_, _, _, ok := node.warmPoolPeers(functionID, 2, consensus.Type(0), "", nil)
+		require.False(t, ok)
+
+		// Worker is released.
+		require.True(t, node.dispatch.Acquire(worker))
+	})
+	t.Run("pool is not used for executions with consensus or attributes", func(t *testing.T) {
+		t.Parallel()
+
+		node, _ := setup(t)
+
+		_, _, _, ok := node.warmPoolPeers(functionID, 1, consensus.Raft, "", nil)
+		require.False(t, ok)
+
+		_, _, _, ok = node.warmPoolPeers(functionID, 1, consensus.Type(0), "", &execute.Attributes{})
+		require.False(t, ok)
+	})
+	t.Run("restricted subgroups use only member workers", func(t *testing.T) {
+		t.Parallel()
+
+		const subgroup = "blockless/b7s/private"
+
+		node, worker := setup(t)
+		node.cfg.RestrictedSubgroups = map[string]peer.ID{subgroup: mocks.GenericPeerIDs[1]}
+		node.pools.set(functionID, subgroup, []peer.ID{worker}, time.Now().Add(time.Minute))
+
+		_, _, _, ok := node.warmPoolPeers(functionID, 1, consensus.Type(0), subgroup, nil)
+		require.False(t, ok)
+
+		// Expired memberships do not count.
+		node.members.add(subgroup, worker, time.Now().Add(-time.Minute))
+		_, _, _, ok = node.warmPoolPeers(functionID, 1, consensus.Type(0), subgroup, nil)
+		require.False(t, ok)
+
+		node.members.add(subgroup, worker, time.Now().Add(time.Hour))
+		chosen, _, _, ok := node.warmPoolPeers(functionID, 1, consensus.Type(0), subgroup, nil)
+		require.True(t, ok)
+		require.Equal(t, []peer.ID{worker}, chosen)
+	})
+	t.Run("no pool for the function", func(t *testing.T) {
+		t.Parallel()
+
+		node, _ := setup(t)
+
+		_, _, _, ok := node.warmPoolPeers("other-function-id", 1, consensus.Type(0), "", nil)
+		require.False(t, ok)
+	})
+}
@@ -0,0 +1,185 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/node/head/recovery"
+)
+
+// readiness tracks whether the head node warm start is done. Executions wait for it only once the warm start has begun.
+type readiness struct {
+	sync.Mutex
+	done chan struct{}
+}
+
+func (r *readiness) begin() {
+	r.Lock()
+	defer r.Unlock()
+
+	r.done = make(chan struct{})
+}
+
+func (r *readiness) end() {
+	r.Lock()
+	defer r.Unlock()
+
+	close(r.done)
+}
+
+func (r *readiness) wait(ctx context.Context) {
+
+	r.Lock()
+	done := r.done
+	r.Unlock()
+
+	if done == nil {
+		return
+	}
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+// warmStart reaches out to the peers from the peer store, known from the previous run. Workers that are reachable and
+// have valid attributes are ready for executions before the first roll call, instead of waiting for their health pings.
+func (n *Node) warmStart(ctx context.Context) {
+	defer n.warm.end()
+
+	peers, err := n.peerStore.RetrievePeers(ctx)
+	if err != nil {
+		n.log.Warn().Err(err).Msg("could not retrieve known peers for warm start")
+		return
+	}
+
+	if len(peers) > warmStartMaxPeers {
+		peers = peers[:warmStartMaxPeers]
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, warmStartTimeout)
+	defer cancel()
+
+	var (
+		wg    sync.WaitGroup
+		known uint
+		ready atomic.Uint32
+	)
+
+	for _, p := range peers {
+		if p.ID == n.host.ID() {
+			continue
+		}
+
+		known++
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			err := n.warmUpPeer(ctx, p)
+			if err != nil {
+				n.log.Debug().Err(err).Stringer("peer", p.ID).Msg("known peer not ready")
+				return
+			}
+
+			ready.Add(1)
+		}()
+	}
+
+	wg.Wait()
+
+	n.recovery.update(func(r *recovery.Report) {
+		r.KnownPeers = known
+		r.ReadyWorkers = uint(ready.Load())
+	})
+
+	n.log.Info().Uint("known", known).Uint32("ready", ready.Load()).Msg("warm start done")
+}
+
+// warmUpPeer connects to a known peer and asks for its health status.
+func (n *Node) warmUpPeer(ctx context.Context, p blockless.Peer) error {
+
+	if n.host.Network().Connectedness(p.ID) != network.Connected {
+
+		info := peer.AddrInfo{
+			ID:    p.ID,
+			Addrs: p.AddrInfo.Addrs,
+		}
+
+		// Use the last known multiaddress if we don't have any other.
+		if len(info.Addrs) == 0 {
+			ma, err := multiaddr.NewMultiaddr(p.MultiAddr)
+			if err != nil {
+				return fmt.Errorf("no valid address for peer: %w", err)
+			}
+
+			info.Addrs = []multiaddr.Multiaddr{ma}
+		}
+
+		err := n.host.Connect(ctx, info)
+		if err != nil {
+			return fmt.Errorf("could not connect to peer: %w", err)
+		}
+	}
+
+	err := n.send(ctx, p.ID, &request.HealthQuery{})
+	if err != nil {
+		return fmt.Errorf("could not send health query: %w", err)
+	}
+
+	_, ok := n.healthQueries.WaitFor(ctx, p.ID)
+	if !ok {
+		return fmt.Errorf("no health status received from peer")
+	}
+
+	return nil
+}
+
+func (n *Node) processHealthQuery(ctx context.Context, from peer.ID, req request.HealthQuery) error {
+
+	n.log.Debug().Stringer("peer", from).Msg("processing health query")
+
+	res := req.Response(codes.OK, n.cfg.Role, n.attributes)
+	res.NoConsensus = noConsensus()
+
+	err := n.send(ctx, from, res)
+	if err != nil {
+		return fmt.Errorf("could not send response: %w", err)
+	}
+
+	return nil
+}
+
+// processHealthQueryResponse records the health status of a worker. Workers with invalid attributes are not considered ready.
+func (n *Node) processHealthQueryResponse(ctx context.Context, from peer.ID, res response.HealthQuery) error {
+
+	n.log.Debug().Stringer("peer", from).Stringer("code", res.Code).Msg("received health query response")
+
+	if res.Code != codes.OK || res.Role != blockless.WorkerNode {
+		return nil
+	}
+
+	if res.Attributes != nil {
+		err := verifyAttributes(from, *res.Attributes)
+		if err != nil {
+			return fmt.Errorf("invalid attributes for peer (peer: %s): %w", from, err)
+		}
+	}
+
+	n.peers.update(from, res.Role, res.Attributes, res.NoConsensus)
+	n.healthQueries.Set(from, res)
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s-attributes/attributes"
+	"github.com/blocklessnetwork/b7s/models/blockless"
+	"github.com/blocklessnetwork/b7s/models/codes"
+	"github.com/blocklessnetwork/b7s/models/response"
+	"github.com/blocklessnetwork/b7s/testing/mocks"
+)
+
+func TestNode_WarmStart(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	worker := createNode(t, blockless.WorkerNode)
+	worker.listenDirectMessages(ctx)
+
+	head := createNode(t, blockless.HeadNode)
+	head.listenDirectMessages(ctx)
+
+	store := mocks.BaselineStore(t)
+	store.RetrievePeersFunc = func(context.Context) ([]blockless.Peer, error) {
+		peers := []blockless.Peer{
+			{ID: worker.host.ID(), AddrInfo: *hostGetAddrInfo(t, worker.host)},
+			// Peer from the previous run that is gone.
+			mocks.GenericPeer,
+			// Head node itself.
+			{ID: head.host.ID()},
+		}
+		return peers, nil
+	}
+	head.peerStore = store
+
+	head.warm.begin()
+	go head.warmStart(ctx)
+
+	// Executions wait for the warm start.
+	waitCtx, waitCancel := context.WithTimeout(ctx, 2*warmStartTimeout)
+	defer waitCancel()
+	head.warm.wait(waitCtx)
+	require.NoError(t, waitCtx.Err())
+
+	report := head.RecoveryReport()
+	require.Equal(t, uint(2), report.KnownPeers)
+	require.Equal(t, uint(1), report.ReadyWorkers)
+
+	known, ok := head.peers.get(worker.host.ID())
+	require.True(t, ok)
+	require.Equal(t, blockless.WorkerNode, known.role)
+	require.WithinDuration(t, time.Now(), known.lastSeen, 2*warmStartTimeout)
+}
+
+func TestNode_ProcessHealthQueryResponse(t *testing.T) {
+
+	t.Run("worker without attributes is ready", func(t *testing.T) {
+		node := createNode(t, blockless.HeadNode)
+
+		res := response.HealthQuery{Code: codes.OK, Role: blockless.WorkerNode}
+		err := node.processHealthQueryResponse(context.Background(), mocks.GenericPeerID, res)
+		require.NoError(t, err)
+
+		_, ok := node.healthQueries.Get(mocks.GenericPeerID)
+		require.True(t, ok)
+	})
+	t.Run("worker with invalid attributes is not ready", func(t *testing.T) {
+		node := createNode(t, blockless.HeadNode)
+
+		res := response.HealthQuery{
+			Code: codes.OK,
+			Role: blockless.WorkerNode,
+			Attributes: &attributes.Attestation{
+				Attributes: []attributes.Attribute{{Name: "region", Value: "eu"}},
+				Signature:  &attributes.Signature{Signer: mocks.GenericPeerIDs[0], Signature: "invalid"},
+			},
+		}
+		err := node.processHealthQueryResponse(context.Background(), mocks.GenericPeerID, res)
+		require.Error(t, err)
+
+		_, ok := node.healthQueries.Get(mocks.GenericPeerID)
+		require.False(t, ok)
+
+		_, ok = node.peers.get(mocks.GenericPeerID)
+		require.False(t, ok)
+	})
+	t.Run("head nodes are not workers", func(t *testing.T) {
+		node := createNode(t, blockless.HeadNode)
+
+		res := response.HealthQuery{Code: codes.OK, Role: blockless.HeadNode}
+		err := node.processHealthQueryResponse(context.Background(), mocks.GenericPeerID, res)
+		require.NoError(t, err)
+
+		_, ok := node.healthQueries.Get(mocks.GenericPeerID)
+		require.False(t, ok)
+	})
+}
+
+func TestReadiness(t *testing.T) {
+
+	var ready readiness
+
+	// Nothing to wait for before the warm start begins.
+	ready.wait(context.Background())
+
+	ready.begin()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	ready.wait(ctx)
+	require.Error(t, ctx.Err())
+
+	ready.end()
+	ready.wait(context.Background())
+}
@@ -2,6 +2,7 @@ package node
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,9 +10,11 @@ import (
 	"github.com/libp2p/go-libp2p/core/peer"
 	"go.opentelemetry.io/otel/trace"
 
+	"github.com/blocklessnetwork/b7s/consensus"
 	"github.com/blocklessnetwork/b7s/models/codes"
 	"github.com/blocklessnetwork/b7s/models/execute"
 	"github.com/blocklessnetwork/b7s/models/request"
+	"github.com/blocklessnetwork/b7s/node/internal/workqueue"
 	"github.com/blocklessnetwork/b7s/telemetry/tracing"
 )
 
@@ -19,6 +22,8 @@ func (n *Node) workerProcessExecute(ctx context.Context, from peer.ID, req reque
 
 	n.metrics.IncrCounterWithLabels(functionExecutionsMetric, 1, []metrics.Label{{Name: "function", Value: req.FunctionID}})
 
+	received := time.Now()
+
 	requestID := req.RequestID
 	if requestID == "" {
 		return fmt.Errorf("request ID must be set by the head node")
@@ -29,11 +34,123 @@ func (n *Node) workerProcessExecute(ctx context.Context, from peer.ID, req reque
 
 	log := n.log.With().Str("request", req.RequestID).Str("function", req.FunctionID).Logger()
 
-	// NOTE: In case of an error, we do not return early from this function.
-	// Instead, we send the response back to the caller, whatever it may be.
-	code, result, err := n.workerExecute(ctx, requestID, req.Timestamp, req.Request, from)
-	if err != nil {
-		log.Error().Err(err).Str("peer", from.String()).Msg("execution failed")
+	// The provenance must end with the head node that sent us the request.
+	if len(req.Provenance) > 0 {
+		err := req.Provenance.Verify(from, requestID, req.Request)
+		if err != nil {
+			log.Warn().Err(err).Stringer("from", from).Msg("execution request has invalid provenance - dropping")
+
+			err = n.sendData(ctx, from, req.Response(codes.Invalid).WithErrorMessage(fmt.Errorf("invalid provenance: %w", err)))
+			if err != nil {
+				return fmt.Errorf("could not send response: %w", err)
+			}
+
+			return nil
+		}
+	}
+
+	// Remember where the head node wants to receive execution results.
+	n.addDataAddresses(from, req.DataAddresses)
+
+	// Do not bother executing requests no one is waiting for anymore.
+	if req.Remaining != 0 {
+		deadline := received.Add(req.Remaining)
+		if !time.Now().Before(deadline) {
+			log.Info().Dur("remaining", req.Remaining).Msg("execution request deadline exceeded - skipping execution")
+			n.metrics.IncrCounter(expiredExecutionsMetric, 1)
+
+			err := n.sendData(ctx, from, req.Response(codes.Timeout))
+			if err != nil {
+				return fmt.Errorf("could not send response: %w", err)
+			}
+
+			return nil
+		}
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
+	if !n.functionPermitted(req.FunctionID) {
+		log.Info().Msg("function not permitted on this worker - turning down execution request")
+		n.metrics.IncrCounterWithLabels(functionsDeclinedMetric, 1, []metrics.Label{{Name: "source", Value: "execute"}})
+
+		err := n.sendData(ctx, from, req.Response(codes.NotPermitted).WithErrorMessage(errFunctionNotPermitted))
+		if err != nil {
+			return fmt.Errorf("could not send response: %w", err)
+		}
+
+		return nil
+	}
+
+	// Stop working on the request if the head node lets us know the client abandoned it.
+	ctx, done := n.running.start(ctx, requestID, from)
+	defer done()
+
+	// Identical executions are served from the result cache, without waiting for a processing slot.
+	result, cached := n.cachedResult(req.Request)
+	code := result.Code
+	if cached {
+		log.Info().Msg("serving execution result from cache")
+	} else {
+
+		// Tenants wait for their own executions first, so a single tenant cannot take up all processing slots of the worker.
+		ok, err := n.acquireTenantSlot(ctx, req.Config.Tenant, req.Config.Priority)
+		if err != nil && executionAborted(ctx) {
+			log.Info().Msg("execution aborted while waiting for a tenant execution slot")
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not get a tenant execution slot: %w", err)
+		}
+		if !ok {
+			log.Info().Str("tenant", req.Config.Tenant).Msg("tenant has too many executions in flight - turning down execution request")
+
+			err = n.sendData(ctx, from, req.Response(codes.Overloaded).WithErrorMessage(errors.New("tenant has too many executions in flight")))
+			if err != nil {
+				return fmt.Errorf("could not send response: %w", err)
+			}
+
+			return nil
+		}
+		defer n.releaseTenantSlot(req.Config.Tenant)
+
+		// Wait for a processing slot. If there is no room left in the queue, let the head node know so it can try another worker.
+		err = n.work.Acquire(ctx, req.Config.Priority)
+		if errors.Is(err, workqueue.ErrFull) {
+			log.Info().Uint("priority", req.Config.Priority).Msg("work queue full - turning down execution request")
+			n.metrics.IncrCounterWithLabels(workQueueRejectedMetric, 1, []metrics.Label{{Name: "source", Value: "execute"}})
+
+			err = n.sendData(ctx, from, req.Response(codes.Overloaded).WithErrorMessage(err))
+			if err != nil {
+				return fmt.Errorf("could not send response: %w", err)
+			}
+
+			return nil
+		}
+		if err != nil && executionAborted(ctx) {
+			log.Info().Msg("execution aborted while waiting for a processing slot")
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not get a processing slot: %w", err)
+		}
+		defer n.work.Release()
+
+		// NOTE: In case of an error, we do not return early from this function.
+		// Instead, we send the response back to the caller, whatever it may be.
+		code, result, err = n.workerExecute(ctx, requestID, req.Timestamp, req.Request, from)
+		// No one is waiting for the result of an aborted execution, and it might be incomplete.
+		if executionAborted(ctx) {
+			log.Info().Msg("execution aborted - dropping result")
+			return nil
+		}
+		if err != nil {
+			log.Error().Err(err).Str("peer", from.String()).Msg("execution failed")
+		}
+
+		n.cacheResult(req.Request, result)
 	}
 
 	// There's little benefit to sending a response just to say we didn't execute anything.
@@ -49,25 +166,53 @@ func (n *Node) workerProcessExecute(ctx context.Context, from peer.ID, req reque
 
 	log.Info().Str("code", code.String()).Msg("execution complete")
 
+	// Complete the provenance chain, so the client can see every node the request passed through.
+	provenance, err := req.Provenance.Append(n.host.PrivateKey(), requestID, req.Request, n.cfg.Role.String(), received)
+	if err != nil {
+		log.Error().Err(err).Msg("could not add worker node to request provenance")
+		provenance = req.Provenance
+	}
+
 	// Create the execution response from the execution result.
-	rm := execute.ResultMap{n.host.ID(): {Result: result, Metadata: metadata}}
+	nres := execute.NodeResult{
+		Result:     result,
+		Metadata:   metadata,
+		Timing:     workerTiming(received, result.Usage),
+		Provenance: provenance,
+		Watermark:  execute.WatermarkTag(req.Config.Watermark, requestID),
+		Cached:     cached,
+	}
+	nres.SetChecksum()
+
+	rm := execute.ResultMap{n.host.ID(): nres}
 
 	n.executeResponses.Set(requestID, rm)
 
 	res := req.Response(code).WithResults(rm)
 
 	// Send the response, whatever it may be (success or failure).
-	err = n.send(ctx, from, res)
+	err = n.sendData(ctx, from, res)
 	if err != nil {
 		return fmt.Errorf("could not send response: %w", err)
 	}
 
+	// Cluster members publish results as they execute them.
+	if req.Config.ConsensusAlgorithm == "" {
+		n.publishExecutionResult(ctx, req.Config.ResultTopic, res)
+	}
+
 	return nil
 }
 
 // workerExecute is called on the worker node to use its executor component to invoke the function.
 func (n *Node) workerExecute(ctx context.Context, requestID string, timestamp time.Time, req execute.Request, from peer.ID) (codes.Code, execute.Result, error) {
 
+	// If the function is still being installed after the roll call, wait for it.
+	err := n.awaitPrefetch(ctx, req.FunctionID)
+	if err != nil {
+		return codes.Error, execute.Result{}, fmt.Errorf("could not install function: %w", err)
+	}
+
 	// Check if we have function in store.
 	functionInstalled, err := n.fstore.IsInstalled(req.FunctionID)
 	if err != nil {
@@ -83,15 +228,15 @@ func (n *Node) workerExecute(ctx context.Context, requestID string, timestamp ti
 	// Here we actually have a bit of a conceptual problem with having the same models for head and worker node.
 	// Head node receives client requests so it can expect _some_ type of inaccuracy there. Worker node receives
 	// execution requests from the head node, so it shouldn't really tolerate errors/ambiguities.
-	consensus, err := parseConsensusAlgorithm(req.Config.ConsensusAlgorithm)
+	consensusAlgo, err := consensus.Parse(req.Config.ConsensusAlgorithm)
 	if err != nil {
 		return codes.Error, execute.Result{}, fmt.Errorf("could not parse consensus algorithm from the head node request, aborting (value: %s): %w", req.Config.ConsensusAlgorithm, err)
 	}
 
 	// We are not part of a cluster - just execute the request.
-	if !consensusRequired(consensus) {
+	if !consensusRequired(consensusAlgo) {
 
-		res, err := n.executor.ExecuteFunction(ctx, requestID, req)
+		res, err := n.executor.ExecuteFunction(ctx, requestID, req.WithWatermark())
 		if err != nil {
 			return res.Code, res, fmt.Errorf("execution failed: %w", err)
 		}
@@ -109,10 +254,12 @@ func (n *Node) workerExecute(ctx context.Context, requestID string, timestamp ti
 		return codes.Error, execute.Result{}, fmt.Errorf("consensus required but no cluster found; omitted cluster formation message or error forming cluster (request: %s)", requestID)
 	}
 
-	log := n.log.With().Str("request", requestID).Str("function", req.FunctionID).Str("consensus", consensus.String()).Logger()
+	log := n.log.With().Str("request", requestID).Str("function", req.FunctionID).Str("consensus", consensusAlgo.String()).Logger()
 
 	log.Info().Msg("execution request to be executed as part of a cluster")
 
+	n.clusterProgress.record(requestID, time.Now())
+
 	code, value, err := cluster.Execute(from, requestID, timestamp, req)
 	if err != nil {
 		return codes.Error, execute.Result{}, fmt.Errorf("execution failed: %w", err)
@@ -122,3 +269,22 @@ func (n *Node) workerExecute(ctx context.Context, requestID string, timestamp ti
 
 	return code, value, nil
 }
+
+// workerTiming splits the time since the execution request was received into time spent waiting for the execution,
+// e.g. for function lookup or cluster replication, and time spent executing the function.
+func workerTiming(received time.Time, usage execute.Usage) *execute.Timing {
+
+	total := time.Since(received)
+
+	execution := usage.WallClockTime
+	if execution == 0 || execution > total {
+		execution = total
+	}
+
+	timing := execute.Timing{
+		QueueWait: (total - execution).Milliseconds(),
+		Execution: execution.Milliseconds(),
+	}
+
+	return &timing
+}
@@ -0,0 +1,78 @@
+// Package random provides a source of random numbers that can be seeded, so components making random choices -
+// such as peer selection, roll call sampling or request IDs - behave the same way on every run with the same seed.
+// This makes simulations and tests reproducible.
+package random
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Source is a source of random numbers, safe for concurrent use.
+type Source struct {
+	sync.Mutex
+	rand *rand.Rand
+}
+
+// New creates a new Source with the given seed. Sources with the same seed produce the same sequence of values.
+func New(seed int64) *Source {
+
+	s := Source{
+		rand: rand.New(rand.NewSource(seed)),
+	}
+
+	return &s
+}
+
+// NewUnseeded creates a new Source with a seed based on the current time, for when reproducibility is not needed.
+func NewUnseeded() *Source {
+	return New(time.Now().UnixNano())
+}
+
+// Float64 returns a random number in the [0.0, 1.0) range.
+func (s *Source) Float64() float64 {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.rand.Float64()
+}
+
+// Int63n returns a random number in the [0, n) range. It panics if n is not positive.
+func (s *Source) Int63n(n int64) int64 {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.rand.Int63n(n)
+}
+
+// Shuffle randomizes the order of n elements, using the swap function to swap them.
+func (s *Source) Shuffle(n int, swap func(i, j int)) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.rand.Shuffle(n, swap)
+}
+
+// Read fills the buffer with random bytes. It always returns len(p) and a nil error.
+func (s *Source) Read(p []byte) (int, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.rand.Read(p)
+}
+
+// UUID returns a random (version 4) UUID.
+func (s *Source) UUID() uuid.UUID {
+
+	// Reading from the source does not fail.
+	id, _ := uuid.NewRandomFromReader(s)
+	return id
+}
+
+// Chance returns true with the given probability.
+func (s *Source) Chance(probability float64) bool {
+	return probability > 0 && s.Float64() < probability
+}
@@ -0,0 +1,45 @@
+package random_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/random"
+)
+
+func TestSource(t *testing.T) {
+
+	const seed = 42
+
+	t.Run("sources with the same seed produce the same values", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			first  = random.New(seed)
+			second = random.New(seed)
+		)
+
+		require.Equal(t, first.UUID(), second.UUID())
+		require.Equal(t, first.Float64(), second.Float64())
+		require.Equal(t, first.Int63n(1000), second.Int63n(1000))
+
+		a := []int{1, 2, 3, 4, 5, 6, 7, 8}
+		b := []int{1, 2, 3, 4, 5, 6, 7, 8}
+		first.Shuffle(len(a), func(i, j int) { a[i], a[j] = a[j], a[i] })
+		second.Shuffle(len(b), func(i, j int) { b[i], b[j] = b[j], b[i] })
+		require.Equal(t, a, b)
+	})
+	t.Run("sources with different seeds produce different values", func(t *testing.T) {
+		t.Parallel()
+
+		require.NotEqual(t, random.New(seed).UUID(), random.New(seed+1).UUID())
+	})
+	t.Run("chance", func(t *testing.T) {
+		t.Parallel()
+
+		source := random.New(seed)
+		require.False(t, source.Chance(0))
+		require.True(t, source.Chance(1))
+	})
+}
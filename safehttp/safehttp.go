@@ -0,0 +1,109 @@
+// Package safehttp provides an HTTP client for URLs supplied by clients, such as callback URLs and execution inputs.
+// The client refuses to connect to loopback, private, link-local and other non-public addresses, so client-supplied URLs
+// cannot be used to reach services on the node host or its internal network.
+package safehttp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+const (
+	dialTimeout         = 10 * time.Second
+	tlsHandshakeTimeout = 10 * time.Second
+	idleConnTimeout     = 90 * time.Second
+)
+
+// ErrNonPublicAddress is returned when the URL resolves to an address that is not publicly routable.
+var ErrNonPublicAddress = errors.New("connections to non-public addresses are not allowed")
+
+// Special-purpose ranges not covered by the `netip.Addr` helpers.
+var nonPublic = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "This" network.
+	netip.MustParsePrefix("100.64.0.0/10"),   // Carrier-grade NAT.
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments.
+	netip.MustParsePrefix("198.18.0.0/15"),   // Benchmarking.
+	netip.MustParsePrefix("240.0.0.0/4"),     // Reserved.
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64 - may translate to any IPv4 address.
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // Local-use NAT64.
+	netip.MustParsePrefix("2001:db8::/32"),   // Documentation.
+	netip.MustParsePrefix("2002::/16"),       // 6to4 - may embed any IPv4 address.
+	netip.MustParsePrefix("fec0::/10"),       // Deprecated site-local.
+	netip.MustParsePrefix("2001::/32"),       // Teredo.
+	netip.MustParsePrefix("100::/64"),        // Discard-only.
+	netip.MustParsePrefix("::ffff:0:0:0/96"), // IPv4-translated.
+}
+
+// NewClient creates an HTTP client with the given request timeout. The client checks the address it connects to after
+// DNS resolution, so host names resolving to non-public addresses are refused too. Redirects are not followed - the
+// redirect response is returned as is. Proxies from the environment are not used, since the client would then only
+// check the address of the proxy.
+func NewClient(timeout time.Duration) *http.Client {
+
+	dialer := &net.Dialer{
+		Timeout: dialTimeout,
+		Control: control,
+	}
+
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		ForceAttemptHTTP2:   true,
+		TLSHandshakeTimeout: tlsHandshakeTimeout,
+		IdleConnTimeout:     idleConnTimeout,
+	}
+
+	cli := &http.Client{
+		Timeout:   timeout,
+		Transport: otelhttp.NewTransport(transport),
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	return cli
+}
+
+// Public returns true if the address is publicly routable.
+func Public(addr netip.Addr) bool {
+
+	addr = addr.Unmap()
+
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+
+	for _, prefix := range nonPublic {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// control is invoked by the dialer before connecting, with the resolved address.
+func control(_ string, address string, _ syscall.RawConn) error {
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("could not parse address (address: %s): %w", address, err)
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("could not parse IP address (address: %s): %w", host, err)
+	}
+
+	if !Public(addr) {
+		return fmt.Errorf("%w (address: %s)", ErrNonPublicAddress, addr)
+	}
+
+	return nil
+}
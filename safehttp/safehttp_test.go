@@ -0,0 +1,85 @@
+package safehttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/safehttp"
+)
+
+func TestPublic(t *testing.T) {
+
+	tests := []struct {
+		addr   string
+		public bool
+	}{
+		{addr: "8.8.8.8", public: true},
+		{addr: "2606:4700:4700::1111", public: true},
+		{addr: "127.0.0.1", public: false},
+		{addr: "::1", public: false},
+		{addr: "10.1.2.3", public: false},
+		{addr: "172.16.0.1", public: false},
+		{addr: "192.168.1.1", public: false},
+		{addr: "169.254.169.254", public: false},
+		{addr: "fe80::1", public: false},
+		{addr: "fd00::1", public: false},
+		{addr: "100.64.0.1", public: false},
+		{addr: "0.0.0.0", public: false},
+		{addr: "::ffff:127.0.0.1", public: false},
+		{addr: "64:ff9b::a9fe:a9fe", public: false},
+		{addr: "224.0.0.1", public: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.addr, func(t *testing.T) {
+			require.Equal(t, test.public, safehttp.Public(netip.MustParseAddr(test.addr)))
+		})
+	}
+}
+
+func TestClient(t *testing.T) {
+
+	t.Run("non-public addresses are refused", func(t *testing.T) {
+
+		var called bool
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+
+		cli := safehttp.NewClient(time.Second)
+
+		_, err := cli.Get(srv.URL)
+		require.ErrorIs(t, err, safehttp.ErrNonPublicAddress)
+		require.False(t, called)
+	})
+	t.Run("redirects are not followed", func(t *testing.T) {
+
+		var called bool
+		target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer target.Close()
+
+		srv := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusFound))
+		defer srv.Close()
+
+		// Use the test server transport, which can reach loopback addresses.
+		cli := safehttp.NewClient(time.Second)
+		cli.Transport = srv.Client().Transport
+
+		res, err := cli.Get(srv.URL)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		require.Equal(t, http.StatusFound, res.StatusCode)
+		require.False(t, called)
+	})
+}
@@ -0,0 +1,24 @@
+package store
+
+import (
+	"time"
+)
+
+// DefaultConfig used to create Store.
+var DefaultConfig = Config{
+	Timeout: DefaultTimeout,
+}
+
+// Config represents the Store configuration.
+type Config struct {
+	Timeout time.Duration // how long can a single store operation take, zero means no limit
+}
+
+type Option func(*Config)
+
+// WithTimeout sets how long a single store operation can take.
+func WithTimeout(d time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.Timeout = d
+	}
+}
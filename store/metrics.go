@@ -0,0 +1,132 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/armon/go-metrics/prometheus"
+	"github.com/cockroachdb/pebble"
+)
+
+var (
+	readTimeMetric  = []string{"store", "read", "milliseconds"}
+	writeTimeMetric = []string{"store", "write", "milliseconds"}
+
+	diskSizeMetric           = []string{"store", "disk", "size", "bytes"}
+	memtableSizeMetric       = []string{"store", "memtable", "size", "bytes"}
+	cacheSizeMetric          = []string{"store", "cache", "size", "bytes"}
+	cacheHitRateMetric       = []string{"store", "cache", "hit", "rate"}
+	compactionsMetric        = []string{"store", "compactions"}
+	compactionsPendingMetric = []string{"store", "compactions", "in", "progress"}
+	compactionDebtMetric     = []string{"store", "compaction", "debt", "bytes"}
+	compactionTimeMetric     = []string{"store", "compaction", "milliseconds"}
+	flushesMetric            = []string{"store", "flushes"}
+	readAmplificationMetric  = []string{"store", "read", "amplification"}
+)
+
+var Summaries = []prometheus.SummaryDefinition{
+	{
+		Name: readTimeMetric,
+		Help: "Time needed to read from the store.",
+	},
+	{
+		Name: writeTimeMetric,
+		Help: "Time needed to write to the store.",
+	},
+}
+
+var Gauges = []prometheus.GaugeDefinition{
+	{
+		Name: diskSizeMetric,
+		Help: "Disk space used by the database.",
+	},
+	{
+		Name: memtableSizeMetric,
+		Help: "Size of the database memtables.",
+	},
+	{
+		Name: cacheSizeMetric,
+		Help: "Size of the database block cache.",
+	},
+	{
+		Name: cacheHitRateMetric,
+		Help: "Share of database block cache lookups that were hits, in the 0-1 range.",
+	},
+	{
+		Name: compactionsMetric,
+		Help: "Number of compactions since the database was opened.",
+	},
+	{
+		Name: compactionsPendingMetric,
+		Help: "Number of compactions in progress.",
+	},
+	{
+		Name: compactionDebtMetric,
+		Help: "Estimated number of bytes that need to be compacted for the database to reach a stable state.",
+	},
+	{
+		Name: compactionTimeMetric,
+		Help: "Total time spent compacting since the database was opened.",
+	},
+	{
+		Name: flushesMetric,
+		Help: "Number of memtable flushes since the database was opened.",
+	},
+	{
+		Name: readAmplificationMetric,
+		Help: "Read amplification of the database.",
+	},
+}
+
+// ObserveRead records the time needed to read from the named store, e.g. `defer store.ObserveRead("journal", time.Now())`.
+func ObserveRead(name string, start time.Time) {
+	metrics.MeasureSinceWithLabels(readTimeMetric, start, storeLabels(name))
+}
+
+// ObserveWrite records the time needed to write to the named store, e.g. `defer store.ObserveWrite("journal", time.Now())`.
+func ObserveWrite(name string, start time.Time) {
+	metrics.MeasureSinceWithLabels(writeTimeMetric, start, storeLabels(name))
+}
+
+func storeLabels(name string) []metrics.Label {
+	return []metrics.Label{{Name: "store", Value: name}}
+}
+
+// RecordMetrics reports the current state of the database - disk size, cache usage and compaction stats.
+func RecordMetrics(db *pebble.DB) {
+
+	m := db.Metrics()
+
+	metrics.SetGauge(diskSizeMetric, float32(m.DiskSpaceUsage()))
+	metrics.SetGauge(memtableSizeMetric, float32(m.MemTable.Size))
+	metrics.SetGauge(cacheSizeMetric, float32(m.BlockCache.Size))
+	metrics.SetGauge(compactionsMetric, float32(m.Compact.Count))
+	metrics.SetGauge(compactionsPendingMetric, float32(m.Compact.NumInProgress))
+	metrics.SetGauge(compactionDebtMetric, float32(m.Compact.EstimatedDebt))
+	metrics.SetGauge(compactionTimeMetric, float32(m.Compact.Duration.Milliseconds()))
+	metrics.SetGauge(flushesMetric, float32(m.Flush.Count))
+	metrics.SetGauge(readAmplificationMetric, float32(m.ReadAmp()))
+
+	lookups := m.BlockCache.Hits + m.BlockCache.Misses
+	if lookups > 0 {
+		metrics.SetGauge(cacheHitRateMetric, float32(m.BlockCache.Hits)/float32(lookups))
+	}
+}
+
+// MonitorMetrics periodically reports the state of the database, until the context is cancelled.
+func MonitorMetrics(ctx context.Context, db *pebble.DB, interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		RecordMetrics(db)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blocklessnetwork/b7s/store"
+	"github.com/blocklessnetwork/b7s/store/codec"
+	"github.com/blocklessnetwork/b7s/testing/helpers"
+)
+
+func TestStore_Metrics(t *testing.T) {
+
+	sink := metrics.NewInmemSink(time.Minute, time.Minute)
+
+	cfg := metrics.DefaultConfig("b7s")
+	cfg.EnableHostname = false
+	cfg.EnableRuntimeMetrics = false
+	_, err := metrics.NewGlobal(cfg, sink)
+	require.NoError(t, err)
+
+	db := helpers.InMemoryDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	peer := helpers.CreateRandomPeers(t, 1)[0]
+	store.RecordMetrics(db)
+
+	s := store.New(db, codec.NewJSONCodec())
+
+	err = s.SavePeer(ctx, peer)
+	require.NoError(t, err)
+
+	_, err = s.RetrievePeer(ctx, peer.ID)
+	require.NoError(t, err)
+
+	store.RecordMetrics(db)
+
+	data := sink.Data()
+	require.NotEmpty(t, data)
+
+	interval := data[len(data)-1]
+
+	_, ok := interval.Gauges["b7s.store.disk.size.bytes"]
+	require.True(t, ok)
+	_, ok = interval.Gauges["b7s.store.compactions"]
+	require.True(t, ok)
+
+	writes, ok := interval.Samples["b7s.store.write.milliseconds;store=node"]
+	require.True(t, ok)
+	require.Equal(t, 1, writes.Count)
+
+	reads, ok := interval.Samples["b7s.store.read.milliseconds;store=node"]
+	require.True(t, ok)
+	require.Equal(t, 1, reads.Count)
+}
@@ -0,0 +1,26 @@
+package store
+
+import (
+	"context"
+	"fmt"
+)
+
+// run executes the database operation, bounded by the store timeout. Operations check the context between steps and do not
+// start new ones once it is done. A single pebble read or write cannot be interrupted, so once a write is issued it runs
+// to completion and is reported as successful, even if the context expires in the meantime.
+func (s *Store) run(ctx context.Context, op func(ctx context.Context) error) error {
+
+	if s.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.Timeout)
+		defer cancel()
+	}
+
+	// Do not start operations for contexts that are already done.
+	err := ctx.Err()
+	if err != nil {
+		return fmt.Errorf("could not start store operation: %w", err)
+	}
+
+	return op(ctx)
+}
@@ -1,10 +1,28 @@
 package store
 
+import (
+	"time"
+)
+
 const (
 	PrefixPeer     = 1
 	PrefixFunction = 2
+	PrefixJournal  = 3 // Used by the head node request journal.
+	PrefixSchedule = 4 // Used by the head node execution scheduler.
+	PrefixQuota    = 5 // Used by the head node client quotas.
+
+	PrefixResult       = 6 // Used by the head node for execution results not yet archived.
+	PrefixArchiveIndex = 7 // Used by the head node to locate archived execution results.
+	PrefixDeferred     = 8 // Used by the head node for execution requests waiting for their not-before time.
 )
 
 const (
 	Separator = ':'
 )
+
+const (
+	DefaultTimeout = 10 * time.Second // How long can a single store operation take.
+)
+
+// Name of the store holding peers and functions, used as a label for store metrics.
+const storeName = "node"
@@ -3,12 +3,15 @@ package store
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/cockroachdb/pebble"
 	"github.com/libp2p/go-libp2p/core/peer"
 )
 
-func (s *Store) RemovePeer(_ context.Context, id peer.ID) error {
+func (s *Store) RemovePeer(ctx context.Context, id peer.ID) error {
+
+	defer ObserveWrite(storeName, time.Now())
 
 	idBytes, err := id.MarshalBinary()
 	if err != nil {
@@ -16,7 +19,7 @@ func (s *Store) RemovePeer(_ context.Context, id peer.ID) error {
 	}
 
 	key := encodeKey(PrefixPeer, idBytes)
-	err = s.remove(key)
+	err = s.remove(ctx, key)
 	if err != nil {
 		return fmt.Errorf("could not remove peer: %w", err)
 	}
@@ -24,10 +27,12 @@ func (s *Store) RemovePeer(_ context.Context, id peer.ID) error {
 	return nil
 }
 
-func (s *Store) RemoveFunction(_ context.Context, cid string) error {
+func (s *Store) RemoveFunction(ctx context.Context, cid string) error {
+
+	defer ObserveWrite(storeName, time.Now())
 
 	key := encodeKey(PrefixFunction, cid)
-	err := s.remove(key)
+	err := s.remove(ctx, key)
 	if err != nil {
 		return fmt.Errorf("could not remove function: %w", err)
 	}
@@ -35,6 +40,8 @@ func (s *Store) RemoveFunction(_ context.Context, cid string) error {
 	return nil
 }
 
-func (s *Store) remove(key []byte) error {
-	return s.db.Delete(key, pebble.Sync)
+func (s *Store) remove(ctx context.Context, key []byte) error {
+	return s.run(ctx, func(context.Context) error {
+		return s.db.Delete(key, pebble.Sync)
+	})
 }
@@ -4,14 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/cockroachdb/pebble"
 	"github.com/libp2p/go-libp2p/core/peer"
 
+	"github.com/blocklessnetwork/b7s/b7serrors"
 	"github.com/blocklessnetwork/b7s/models/blockless"
 )
 
-func (s *Store) RetrievePeer(_ context.Context, id peer.ID) (blockless.Peer, error) {
+func (s *Store) RetrievePeer(ctx context.Context, id peer.ID) (blockless.Peer, error) {
+
+	defer ObserveRead(storeName, time.Now())
 
 	idBytes, err := id.MarshalBinary()
 	if err != nil {
@@ -20,7 +24,7 @@ func (s *Store) RetrievePeer(_ context.Context, id peer.ID) (blockless.Peer, err
 
 	key := encodeKey(PrefixPeer, idBytes)
 	var peer blockless.Peer
-	err = s.retrieve(key, &peer)
+	err = s.retrieve(ctx, key, &peer)
 	if err != nil {
 		return blockless.Peer{}, fmt.Errorf("could not retrieve value: %w", err)
 	}
@@ -28,34 +32,36 @@ func (s *Store) RetrievePeer(_ context.Context, id peer.ID) (blockless.Peer, err
 	return peer, nil
 }
 
-func (s *Store) RetrievePeers(_ context.Context) ([]blockless.Peer, error) {
+func (s *Store) RetrievePeers(ctx context.Context) ([]blockless.Peer, error) {
 
-	peers := make([]blockless.Peer, 0)
+	defer ObserveRead(storeName, time.Now())
 
-	opts := prefixIterOptions([]byte{PrefixPeer})
-	it, err := s.db.NewIter(opts)
-	if err != nil {
-		return nil, fmt.Errorf("could not create iterator: %w", err)
-	}
-	for it.First(); it.Valid(); it.Next() {
+	peers := make([]blockless.Peer, 0)
+	err := s.iterate(ctx, []byte{PrefixPeer}, func(key []byte, value []byte) error {
 
 		var peer blockless.Peer
-		err := s.retrieve(it.Key(), &peer)
+		err := s.codec.Unmarshal(value, &peer)
 		if err != nil {
-			return nil, fmt.Errorf("could not retrieve peer (key: %x): %w", it.Key(), err)
+			return fmt.Errorf("could not decode peer (key: %x): %w", key, err)
 		}
 
 		peers = append(peers, peer)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve peers: %w", err)
 	}
 
 	return peers, nil
 }
 
-func (s *Store) RetrieveFunction(_ context.Context, cid string) (blockless.FunctionRecord, error) {
+func (s *Store) RetrieveFunction(ctx context.Context, cid string) (blockless.FunctionRecord, error) {
+
+	defer ObserveRead(storeName, time.Now())
 
 	key := encodeKey(PrefixFunction, cid)
 	var function blockless.FunctionRecord
-	err := s.retrieve(key, &function)
+	err := s.retrieve(ctx, key, &function)
 	if err != nil {
 		return blockless.FunctionRecord{}, fmt.Errorf("could not retrieve function record: %w", err)
 	}
@@ -63,47 +69,78 @@ func (s *Store) RetrieveFunction(_ context.Context, cid string) (blockless.Funct
 	return function, nil
 }
 
-func (s *Store) RetrieveFunctions(_ context.Context) ([]blockless.FunctionRecord, error) {
+func (s *Store) RetrieveFunctions(ctx context.Context) ([]blockless.FunctionRecord, error) {
 
-	functions := make([]blockless.FunctionRecord, 0)
+	defer ObserveRead(storeName, time.Now())
 
-	opts := prefixIterOptions([]byte{PrefixFunction})
-	it, err := s.db.NewIter(opts)
-	if err != nil {
-		return nil, fmt.Errorf("could not create iterator: %w", err)
-	}
-	for it.First(); it.Valid(); it.Next() {
+	functions := make([]blockless.FunctionRecord, 0)
+	err := s.iterate(ctx, []byte{PrefixFunction}, func(key []byte, value []byte) error {
 
 		var function blockless.FunctionRecord
-		err := s.retrieve(it.Key(), &function)
+		err := s.codec.Unmarshal(value, &function)
 		if err != nil {
-			return nil, fmt.Errorf("could not retrieve functioN (key: %x): %w", it.Key(), err)
+			return fmt.Errorf("could not decode function (key: %x): %w", key, err)
 		}
 
 		functions = append(functions, function)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve functions: %w", err)
 	}
 
 	return functions, nil
 }
 
-func (s *Store) retrieve(key []byte, out any) error {
+func (s *Store) retrieve(ctx context.Context, key []byte, out any) error {
 
-	value, closer, err := s.db.Get(key)
-	if err != nil {
-		if errors.Is(err, pebble.ErrNotFound) {
-			return blockless.ErrNotFound
+	return s.run(ctx, func(context.Context) error {
+
+		value, closer, err := s.db.Get(key)
+		if err != nil {
+			if errors.Is(err, pebble.ErrNotFound) {
+				return b7serrors.ErrNotFound
+			}
+			return fmt.Errorf("could not retrieve value: %w", err)
 		}
-		return fmt.Errorf("could not retrieve value: %w", err)
-	}
-	// Closer must be called else a memory leak occurs.
-	defer closer.Close()
+		// Closer must be called else a memory leak occurs.
+		defer closer.Close()
 
-	err = s.codec.Unmarshal(value, out)
-	if err != nil {
-		return fmt.Errorf("cold not decode record: %w", err)
-	}
+		err = s.codec.Unmarshal(value, out)
+		if err != nil {
+			return fmt.Errorf("cold not decode record: %w", err)
+		}
 
-	return nil
+		return nil
+	})
+}
+
+// iterate calls the function for each record with the given key prefix. Iteration stops once the context is done.
+func (s *Store) iterate(ctx context.Context, prefix []byte, fn func(key []byte, value []byte) error) error {
+
+	return s.run(ctx, func(ctx context.Context) error {
+
+		it, err := s.db.NewIter(prefixIterOptions(prefix))
+		if err != nil {
+			return fmt.Errorf("could not create iterator: %w", err)
+		}
+		defer it.Close()
+
+		for it.First(); it.Valid(); it.Next() {
+
+			err := ctx.Err()
+			if err != nil {
+				return err
+			}
+
+			err = fn(it.Key(), it.Value())
+			if err != nil {
+				return err
+			}
+		}
+
+		return it.Error()
+	})
 }
 
 func prefixIterOptions(prefix []byte) *pebble.IterOptions {
@@ -3,13 +3,16 @@ package store
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/cockroachdb/pebble"
 
 	"github.com/blocklessnetwork/b7s/models/blockless"
 )
 
-func (s *Store) SavePeer(_ context.Context, peer blockless.Peer) error {
+func (s *Store) SavePeer(ctx context.Context, peer blockless.Peer) error {
+
+	defer ObserveWrite(storeName, time.Now())
 
 	id, err := peer.ID.MarshalBinary()
 	if err != nil {
@@ -17,7 +20,7 @@ func (s *Store) SavePeer(_ context.Context, peer blockless.Peer) error {
 	}
 
 	key := encodeKey(PrefixPeer, id)
-	err = s.save(key, peer)
+	err = s.save(ctx, key, peer)
 	if err != nil {
 		return fmt.Errorf("could not save peer: %w", err)
 	}
@@ -25,10 +28,12 @@ func (s *Store) SavePeer(_ context.Context, peer blockless.Peer) error {
 	return nil
 }
 
-func (s *Store) SaveFunction(_ context.Context, function blockless.FunctionRecord) error {
+func (s *Store) SaveFunction(ctx context.Context, function blockless.FunctionRecord) error {
+
+	defer ObserveWrite(storeName, time.Now())
 
 	key := encodeKey(PrefixFunction, function.CID)
-	err := s.save(key, function)
+	err := s.save(ctx, key, function)
 	if err != nil {
 		return fmt.Errorf("could not save function: %w", err)
 	}
@@ -36,17 +41,26 @@ func (s *Store) SaveFunction(_ context.Context, function blockless.FunctionRecor
 	return nil
 }
 
-func (s *Store) save(key []byte, value any) error {
+func (s *Store) save(ctx context.Context, key []byte, value any) error {
 
-	encoded, err := s.codec.Marshal(value)
-	if err != nil {
-		return fmt.Errorf("could not encode value: %w", err)
-	}
+	return s.run(ctx, func(ctx context.Context) error {
 
-	err = s.db.Set(key, encoded, pebble.Sync)
-	if err != nil {
-		return fmt.Errorf("could not store value: %w", err)
-	}
+		encoded, err := s.codec.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("could not encode value: %w", err)
+		}
 
-	return nil
+		// Do not write the value if we ran out of time encoding it.
+		err = ctx.Err()
+		if err != nil {
+			return fmt.Errorf("could not store value: %w", err)
+		}
+
+		err = s.db.Set(key, encoded, pebble.Sync)
+		if err != nil {
+			return fmt.Errorf("could not store value: %w", err)
+		}
+
+		return nil
+	})
 }
@@ -8,14 +8,21 @@ import (
 type Store struct {
 	db    *pebble.DB
 	codec Codec
+	cfg   Config
 }
 
 // New creates a new Store backed by the database at the given path.
-func New(db *pebble.DB, codec Codec) *Store {
+func New(db *pebble.DB, codec Codec, options ...Option) *Store {
+
+	cfg := DefaultConfig
+	for _, option := range options {
+		option(&cfg)
+	}
 
 	store := Store{
 		db:    db,
 		codec: codec,
+		cfg:   cfg,
 	}
 
 	return &store
@@ -6,10 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/stretchr/testify/require"
 
+	"github.com/blocklessnetwork/b7s/b7serrors"
 	"github.com/blocklessnetwork/b7s/models/blockless"
 	"github.com/blocklessnetwork/b7s/store"
 	"github.com/blocklessnetwork/b7s/store/codec"
@@ -41,7 +43,7 @@ func TestStore_PeerOperations(t *testing.T) {
 
 		// Verify peer is gone.
 		_, err = store.RetrievePeer(ctx, peer.ID)
-		require.ErrorIs(t, err, blockless.ErrNotFound)
+		require.ErrorIs(t, err, b7serrors.ErrNotFound)
 	})
 }
 
@@ -99,7 +101,7 @@ func TestStore_FunctionOperations(t *testing.T) {
 
 		// Verify function is gone.
 		_, err = store.RetrieveFunction(ctx, function.CID)
-		require.ErrorIs(t, err, blockless.ErrNotFound)
+		require.ErrorIs(t, err, b7serrors.ErrNotFound)
 	})
 }
 
@@ -222,3 +224,83 @@ func TestStore_HandlesFailures(t *testing.T) {
 		require.ErrorIs(t, err, unmarshalErr)
 	})
 }
+
+func TestStore_Context(t *testing.T) {
+
+	db := helpers.InMemoryDB(t)
+	defer db.Close()
+
+	t.Run("operations with a cancelled context fail", func(t *testing.T) {
+
+		store := store.New(db, codec.NewJSONCodec())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		peer := helpers.CreateRandomPeers(t, 1)[0]
+		err := store.SavePeer(ctx, peer)
+		require.ErrorIs(t, err, context.Canceled)
+
+		// Peer was not saved.
+		_, err = store.RetrievePeer(context.Background(), peer.ID)
+		require.ErrorIs(t, err, b7serrors.ErrNotFound)
+
+		_, err = store.RetrievePeer(ctx, peer.ID)
+		require.ErrorIs(t, err, context.Canceled)
+
+		_, err = store.RetrievePeers(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+
+		err = store.RemoveFunction(ctx, mocks.GenericFunctionRecord.CID)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+	t.Run("operations taking longer than the store timeout fail", func(t *testing.T) {
+
+		const (
+			timeout = 20 * time.Millisecond
+		)
+
+		codec := mocks.BaselineCodec(t)
+		codec.MarshalFunc = func(obj any) ([]byte, error) {
+			return json.Marshal(obj)
+		}
+		codec.UnmarshalFunc = func(data []byte, obj any) error {
+			time.Sleep(timeout)
+			return json.Unmarshal(data, obj)
+		}
+		store := store.New(db, codec, store.WithTimeout(timeout))
+
+		ctx := context.Background()
+		for _, peer := range helpers.CreateRandomPeers(t, 3) {
+			err := store.SavePeer(ctx, peer)
+			require.NoError(t, err)
+		}
+
+		_, err := store.RetrievePeers(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+	t.Run("writes failing on timeout are not applied", func(t *testing.T) {
+
+		const (
+			timeout = 20 * time.Millisecond
+		)
+
+		codec := mocks.BaselineCodec(t)
+		codec.MarshalFunc = func(obj any) ([]byte, error) {
+			time.Sleep(2 * timeout)
+			return json.Marshal(obj)
+		}
+		codec.UnmarshalFunc = func(data []byte, obj any) error {
+			return json.Unmarshal(data, obj)
+		}
+		s := store.New(db, codec, store.WithTimeout(timeout))
+
+		peer := helpers.CreateRandomPeers(t, 1)[0]
+		err := s.SavePeer(context.Background(), peer)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		// Peer was not saved.
+		_, err = s.RetrievePeer(context.Background(), peer.ID)
+		require.ErrorIs(t, err, b7serrors.ErrNotFound)
+	})
+}
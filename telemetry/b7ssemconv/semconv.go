@@ -30,6 +30,15 @@ const (
 	ExecutionRequestID = attribute.Key("execution.request.id")
 )
 
+const (
+	ConsensusReplica  = attribute.Key("consensus.replica")
+	ConsensusLeader   = attribute.Key("consensus.leader")
+	ConsensusView     = attribute.Key("consensus.pbft.view")
+	ConsensusSequence = attribute.Key("consensus.pbft.sequence")
+	ConsensusTerm     = attribute.Key("consensus.raft.term")
+	ConsensusIndex    = attribute.Key("consensus.raft.index")
+)
+
 const (
 	PeerID         = attribute.Key("peer.id")
 	PeerMultiaddr  = attribute.Key("peer.multiaddr")
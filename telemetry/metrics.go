@@ -88,8 +88,6 @@ func formatSummaries(summaries []mp.SummaryDefinition) []mp.SummaryDefinition {
 
 func formatGauges(gauges []mp.GaugeDefinition) []mp.GaugeDefinition {
 
-	// Right now we have a single gauge - node info.
-	// gauges := node.Gauges
 	prefixed := make([]mp.GaugeDefinition, len(gauges))
 
 	for i := 0; i < len(gauges); i++ {
@@ -7,6 +7,7 @@ import (
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type TraceInfo struct {
@@ -52,3 +53,19 @@ func TraceContextWithPropagator(ctx context.Context, propagator propagation.Text
 	return propagator.Extract(ctx, t.Carrier)
 
 }
+
+// LinkFromTraceInfo creates a span link to the span described by the trace information.
+// It returns false if the trace information does not describe a valid span.
+func LinkFromTraceInfo(t TraceInfo) (trace.Link, bool) {
+	return LinkFromTraceInfoWithPropagator(otel.GetTextMapPropagator(), t)
+}
+
+func LinkFromTraceInfoWithPropagator(propagator propagation.TextMapPropagator, t TraceInfo) (trace.Link, bool) {
+
+	sc := trace.SpanContextFromContext(TraceContextWithPropagator(context.Background(), propagator, t))
+	if !sc.IsValid() {
+		return trace.Link{}, false
+	}
+
+	return trace.Link{SpanContext: sc}, true
+}
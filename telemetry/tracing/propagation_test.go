@@ -63,4 +63,23 @@ func TestTraceInfo(t *testing.T) {
 		require.Equal(t, span.SpanContext().SpanID(), newSpanCtx.SpanID())
 		require.Equal(t, span.SpanContext().TraceFlags(), newSpanCtx.TraceFlags())
 	})
+	t.Run("traceinfo produces span link", func(t *testing.T) {
+		t.Parallel()
+
+		_, span := tracer.Start(ctx, "test-span-3")
+
+		propagator := telemetry.CreatePropagator()
+		ti := tracing.GetTraceInfoWithPropagator(trace.ContextWithSpan(ctx, span), propagator)
+
+		link, ok := tracing.LinkFromTraceInfoWithPropagator(propagator, ti)
+		require.True(t, ok)
+		require.Equal(t, span.SpanContext().TraceID(), link.SpanContext.TraceID())
+		require.Equal(t, span.SpanContext().SpanID(), link.SpanContext.SpanID())
+	})
+	t.Run("empty traceinfo produces no span link", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := tracing.LinkFromTraceInfoWithPropagator(telemetry.CreatePropagator(), tracing.TraceInfo{})
+		require.False(t, ok)
+	})
 }
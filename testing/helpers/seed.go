@@ -0,0 +1,32 @@
+package helpers
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// RandomSeedEnv is the environment variable used to set the random seed for tests, so a failing test run can be reproduced.
+const RandomSeedEnv = "B7S_TEST_SEED"
+
+// RandomSeed returns the seed tests should use for random numbers. Seed is taken from the environment if set, otherwise
+// it is based on the current time. Seed is logged, so test failures can be reproduced by running the tests with it.
+func RandomSeed(t *testing.T) int64 {
+	t.Helper()
+
+	seed := time.Now().UnixNano()
+
+	env := os.Getenv(RandomSeedEnv)
+	if env != "" {
+		var err error
+		seed, err = strconv.ParseInt(env, 10, 64)
+		require.NoError(t, err)
+	}
+
+	t.Logf("random seed: %v (set %s to reproduce)", seed, RandomSeedEnv)
+
+	return seed
+}
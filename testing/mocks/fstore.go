@@ -3,12 +3,17 @@ package mocks
 import (
 	"context"
 	"testing"
+
+	"github.com/blocklessnetwork/b7s/models/blockless"
 )
 
 type FStore struct {
 	InstallFunc     func(context.Context, string, string) error
+	UninstallFunc   func(context.Context, string) error
 	IsInstalledFunc func(string) (bool, error)
 	SyncFunc        func(context.Context, bool) error
+	GetFunc         func(context.Context, string) (blockless.FunctionRecord, error)
+	InstalledFunc   func(context.Context) ([]string, error)
 }
 
 func BaselineFStore(t *testing.T) *FStore {
@@ -18,12 +23,21 @@ func BaselineFStore(t *testing.T) *FStore {
 		InstallFunc: func(context.Context, string, string) error {
 			return nil
 		},
+		UninstallFunc: func(context.Context, string) error {
+			return nil
+		},
 		IsInstalledFunc: func(string) (bool, error) {
 			return true, nil
 		},
 		SyncFunc: func(context.Context, bool) error {
 			return nil
 		},
+		GetFunc: func(context.Context, string) (blockless.FunctionRecord, error) {
+			return GenericFunctionRecord, nil
+		},
+		InstalledFunc: func(context.Context) ([]string, error) {
+			return []string{GenericFunctionRecord.CID}, nil
+		},
 	}
 
 	return &fh
@@ -33,6 +47,10 @@ func (f *FStore) Install(ctx context.Context, address string, cid string) error
 	return f.InstallFunc(ctx, address, cid)
 }
 
+func (f *FStore) Uninstall(ctx context.Context, cid string) error {
+	return f.UninstallFunc(ctx, cid)
+}
+
 func (f *FStore) IsInstalled(cid string) (bool, error) {
 	return f.IsInstalledFunc(cid)
 }
@@ -40,3 +58,11 @@ func (f *FStore) IsInstalled(cid string) (bool, error) {
 func (f *FStore) Sync(ctx context.Context, haltOnError bool) error {
 	return f.SyncFunc(ctx, haltOnError)
 }
+
+func (f *FStore) Get(ctx context.Context, cid string) (blockless.FunctionRecord, error) {
+	return f.GetFunc(ctx, cid)
+}
+
+func (f *FStore) Installed(ctx context.Context) ([]string, error) {
+	return f.InstalledFunc(ctx)
+}
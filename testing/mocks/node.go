@@ -10,7 +10,9 @@ import (
 
 // Node implements the `Node` interface expected by the API.
 type Node struct {
-	ExecuteFunctionFunc        func(context.Context, execute.Request, string) (codes.Code, string, execute.ResultMap, execute.Cluster, error)
+	ExecuteFunctionFunc        func(context.Context, execute.Request, string) (codes.Code, string, execute.ResultMap, execute.Cluster, execute.Timing, error)
+	ExecuteFunctionAsyncFunc   func(context.Context, execute.Request, string, string) (codes.Code, string, error)
+	ExecuteInSubgroupsFunc     func(context.Context, execute.Request, []execute.SubgroupTarget) (codes.Code, []execute.SubgroupResult, error)
 	ExecutionResultFunc        func(id string) (execute.ResultMap, bool)
 	PublishFunctionInstallFunc func(ctx context.Context, uri string, cid string, subgroup string) error
 }
@@ -19,10 +21,27 @@ func BaselineNode(t *testing.T) *Node {
 	t.Helper()
 
 	node := Node{
-		ExecuteFunctionFunc: func(context.Context, execute.Request, string) (codes.Code, string, execute.ResultMap, execute.Cluster, error) {
+		ExecuteFunctionFunc: func(context.Context, execute.Request, string) (codes.Code, string, execute.ResultMap, execute.Cluster, execute.Timing, error) {
 
 			// TODO: Add a generic cluster info
-			return GenericExecutionResult.Code, GenericUUID.String(), GenericExecutionResultMap, execute.Cluster{}, nil
+			return GenericExecutionResult.Code, GenericUUID.String(), GenericExecutionResultMap, execute.Cluster{}, execute.Timing{}, nil
+		},
+		ExecuteFunctionAsyncFunc: func(context.Context, execute.Request, string, string) (codes.Code, string, error) {
+			return codes.Accepted, GenericUUID.String(), nil
+		},
+		ExecuteInSubgroupsFunc: func(_ context.Context, _ execute.Request, targets []execute.SubgroupTarget) (codes.Code, []execute.SubgroupResult, error) {
+
+			results := make([]execute.SubgroupResult, 0, len(targets))
+			for _, target := range targets {
+				results = append(results, execute.SubgroupResult{
+					Topic:     target.Topic,
+					Code:      GenericExecutionResult.Code,
+					RequestID: GenericUUID.String(),
+					Results:   GenericExecutionResultMap,
+				})
+			}
+
+			return GenericExecutionResult.Code, results, nil
 		},
 		ExecutionResultFunc: func(id string) (execute.ResultMap, bool) {
 			return GenericExecutionResultMap, true
@@ -35,10 +54,18 @@ func BaselineNode(t *testing.T) *Node {
 	return &node
 }
 
-func (n *Node) ExecuteFunction(ctx context.Context, req execute.Request, subgroup string) (codes.Code, string, execute.ResultMap, execute.Cluster, error) {
+func (n *Node) ExecuteFunction(ctx context.Context, req execute.Request, subgroup string) (codes.Code, string, execute.ResultMap, execute.Cluster, execute.Timing, error) {
 	return n.ExecuteFunctionFunc(ctx, req, subgroup)
 }
 
+func (n *Node) ExecuteFunctionAsync(ctx context.Context, req execute.Request, subgroup string, callback string) (codes.Code, string, error) {
+	return n.ExecuteFunctionAsyncFunc(ctx, req, subgroup, callback)
+}
+
+func (n *Node) ExecuteFunctionInSubgroups(ctx context.Context, req execute.Request, targets []execute.SubgroupTarget) (codes.Code, []execute.SubgroupResult, error) {
+	return n.ExecuteInSubgroupsFunc(ctx, req, targets)
+}
+
 func (n *Node) ExecutionResult(id string) (execute.ResultMap, bool) {
 	return n.ExecutionResultFunc(id)
 }